@@ -331,3 +331,40 @@ var Password action.GTSAction = func(ctx context.Context) error {
 
 	return stopState(ctx, state)
 }
+
+// Disable2FA turns off two-factor authentication for target account.
+var Disable2FA action.GTSAction = func(ctx context.Context) error {
+	state, err := initState(ctx)
+	if err != nil {
+		return err
+	}
+
+	username := config.GetAdminAccountUsername()
+	if err := validate.Username(username); err != nil {
+		return err
+	}
+
+	account, err := state.DB.GetAccountByUsernameDomain(ctx, username, "")
+	if err != nil {
+		return err
+	}
+
+	user, err := state.DB.GetUserByAccountID(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+
+	user.TwoFactorSecret = ""
+	user.TwoFactorEnabledAt = time.Time{}
+	user.TwoFactorRecoveryCodes = nil
+	if err := state.DB.UpdateUser(
+		ctx, user,
+		"two_factor_secret",
+		"two_factor_enabled_at",
+		"two_factor_recovery_codes",
+	); err != nil {
+		return err
+	}
+
+	return stopState(ctx, state)
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/timeline"
 	"github.com/superseriousbusiness/gotosocial/internal/tracing"
 	"github.com/superseriousbusiness/gotosocial/internal/visibility"
+	"github.com/superseriousbusiness/gotosocial/internal/webpush"
 	"go.uber.org/automaxprocs/maxprocs"
 
 	"github.com/superseriousbusiness/gotosocial/internal/config"
@@ -174,12 +175,40 @@ var Start action.GTSAction = func(ctx context.Context) error {
 	}
 
 	// Create the processor using all the other services we've created so far.
-	processor := processing.NewProcessor(typeConverter, federator, oauthServer, mediaManager, &state, emailSender)
+	processor := processing.NewProcessor(typeConverter, federator, oauthServer, mediaManager, &state, emailSender, webpush.NewSender(&state, client))
 
 	// Set state client / federator worker enqueue functions
 	state.Workers.EnqueueClientAPI = processor.EnqueueClientAPI
 	state.Workers.EnqueueFederator = processor.EnqueueFederator
 
+	// Replay any worker messages that weren't
+	// processed before the server last stopped.
+	processor.ReplayWorkerMessages(ctx)
+
+	// Add a task to the scheduler to close expired polls.
+	// Frequency = 1 * minute
+	closePolls := func(time.Time) { processor.Polls().CloseExpired(ctx) }
+	job = sched.NewJob(closePolls).Every(time.Minute)
+	_ = state.Workers.Scheduler.Schedule(job)
+
+	// Add a task to the scheduler to publish due scheduled statuses.
+	// Frequency = 1 * minute
+	publishScheduled := func(time.Time) { processor.Status().ScheduledStatusesPublish(ctx) }
+	job = sched.NewJob(publishScheduled).Every(time.Minute)
+	_ = state.Workers.Scheduler.Schedule(job)
+
+	// Add a task to the scheduler to retry queued federation deliveries.
+	// Frequency = 1 * minute
+	retryDeliveries := func(time.Time) { transportController.RetryDeliveries(ctx) }
+	job = sched.NewJob(retryDeliveries).Every(time.Minute)
+	_ = state.Workers.Scheduler.Schedule(job)
+
+	// Add a task to the scheduler to sync domain permission subscriptions.
+	// Frequency = 24 * hour
+	syncSubs := func(time.Time) { processor.Admin().DomainPermissionSubscriptionsSync(ctx) }
+	job = sched.NewJob(syncSubs).Every(24 * time.Hour)
+	_ = state.Workers.Scheduler.Schedule(job)
+
 	/*
 		HTTP router initialization
 	*/
@@ -262,6 +291,7 @@ var Start action.GTSAction = func(ctx context.Context) error {
 	// apply throttling *after* rate limiting
 	authModule.Route(router, clLimit, clThrottle, gzip)
 	clientModule.Route(router, clLimit, clThrottle, gzip)
+	clientModule.RouteStreaming(router, clLimit) // long-lived, so no throttling or gzip
 	fileserverModule.Route(router, fsLimit, fsThrottle)
 	wellKnownModule.Route(router, gzip, s2sLimit, s2sThrottle)
 	nodeInfoModule.Route(router, s2sLimit, s2sThrottle, gzip)
@@ -284,6 +314,12 @@ var Start action.GTSAction = func(ctx context.Context) error {
 	sig := <-sigs // block until signal received
 	log.Infof(ctx, "received signal %s, shutting down", sig)
 
+	// Give queued worker messages a chance
+	// to be processed before closing down.
+	if !state.Workers.Drain(30 * time.Second) {
+		log.Warn(ctx, "timed out waiting for worker queues to drain")
+	}
+
 	// close down all running services in order
 	if err := gts.Stop(ctx); err != nil {
 		return fmt.Errorf("error closing gotosocial service: %s", err)
@@ -177,6 +177,7 @@ var Start action.GTSAction = func(ctx context.Context) error {
 	// these should be routed in order
 	authModule.Route(router)
 	clientModule.Route(router)
+	clientModule.RouteStreaming(router)
 	fileserverModule.Route(router)
 	wellKnownModule.Route(router)
 	nodeInfoModule.Route(router)
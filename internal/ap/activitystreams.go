@@ -77,3 +77,17 @@ const (
 	// and https://www.w3.org/TR/activitystreams-vocabulary/#dfn-tag
 	TagHashtag = "Hashtag"
 )
+
+// Properties that are not (yet) part of the ActivityStreams vocabulary
+// we use, but are widely used across the fediverse. These are stored
+// in and retrieved from a type's unknown properties.
+//
+// See https://docs.joinmastodon.org/spec/activitypub/#as
+const (
+	PropertyAlsoKnownAs = "alsoKnownAs" // https://www.w3.org/TR/did-core/#dfn-alsoknownas
+	PropertyMovedTo     = "movedTo"     // https://docs.joinmastodon.org/spec/activitypub/#as
+
+	// JSONLDId is the key of the ID of a
+	// json-ld object in its raw map form.
+	JSONLDId = "id"
+)
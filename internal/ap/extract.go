@@ -22,6 +22,7 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
@@ -392,6 +393,73 @@ func ExtractURL(i WithURL) (*url.URL, error) {
 	return nil, gtserror.New("no valid URL property found")
 }
 
+// ExtractAlsoKnownAsURIs extracts the alsoKnownAs URIs (account
+// aliases) from the given WithUnknownProperties interface, if set.
+//
+// alsoKnownAs is not part of the ActivityStreams vocabulary that
+// we use, so it must be taken from the type's unknown properties.
+func ExtractAlsoKnownAsURIs(i WithUnknownProperties) []*url.URL {
+	return extractUnknownIRIs(i, PropertyAlsoKnownAs)
+}
+
+// ExtractMovedToURI extracts the movedTo URI from the
+// given WithUnknownProperties interface, or nil if not set.
+//
+// movedTo is not part of the ActivityStreams vocabulary that
+// we use, so it must be taken from the type's unknown properties.
+func ExtractMovedToURI(i WithUnknownProperties) *url.URL {
+	uris := extractUnknownIRIs(i, PropertyMovedTo)
+	if len(uris) == 0 {
+		return nil
+	}
+	return uris[0]
+}
+
+// extractUnknownIRIs extracts any valid IRIs set on the unknown
+// property with the given name. The property value may be either a
+// single IRI string, a single object with id, or an array of either.
+func extractUnknownIRIs(i WithUnknownProperties, name string) []*url.URL {
+	unknown := i.GetUnknownProperties()
+	if unknown == nil {
+		return nil
+	}
+
+	var values []interface{}
+	switch v := unknown[name].(type) {
+	case []interface{}:
+		values = v
+	case nil:
+		return nil
+	default:
+		values = []interface{}{v}
+	}
+
+	iris := make([]*url.URL, 0, len(values))
+	for _, value := range values {
+		var rawIRI string
+
+		switch v := value.(type) {
+		case string:
+			rawIRI = v
+		case map[string]interface{}:
+			rawIRI, _ = v[JSONLDId].(string)
+		}
+
+		if rawIRI == "" {
+			continue
+		}
+
+		iri, err := url.Parse(rawIRI)
+		if err != nil || !iri.IsAbs() {
+			continue
+		}
+
+		iris = append(iris, iri)
+	}
+
+	return iris
+}
+
 // ExtractPublicKey extracts the public key, public key ID, and public
 // key owner ID from an interface, or an error if something goes wrong.
 func ExtractPublicKey(i WithPublicKey) (
@@ -884,6 +952,161 @@ func ExtractSensitive(withSensitive WithSensitive) bool {
 	return false
 }
 
+// ExtractPoll extracts a placeholder Poll from Pollable interface, with
+// available options, flags and (if not hidden) vote counts populated.
+func ExtractPoll(poll Pollable) (*gtsmodel.Poll, error) {
+	var closed time.Time
+
+	// Extract the options (votes if any) and 'multiple choice' flag.
+	options, votes, multi, err := ExtractPollOptions(poll)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if counts have been hidden from us.
+	hideCounts := len(options) != len(votes)
+	if hideCounts {
+		// Simply provide zeroed slice.
+		votes = make([]int, len(options))
+	}
+
+	// Extract the poll end time.
+	endTime := ExtractEndTime(poll)
+
+	// Extract the poll closed time,
+	// falling back to end time if
+	// simply marked closed = true.
+	closedProp := poll.GetActivityStreamsClosed()
+	if closedProp != nil {
+		for iter := closedProp.Begin(); iter != closedProp.End(); iter = iter.Next() {
+			switch {
+			case iter.IsXMLSchemaDateTime():
+				closed = iter.GetXMLSchemaDateTime()
+			case iter.IsXMLSchemaBoolean() && iter.GetXMLSchemaBoolean():
+				closed = endTime
+			}
+		}
+	}
+
+	// Extract the number of voters.
+	voters := ExtractVotersCount(poll)
+
+	return &gtsmodel.Poll{
+		Options:    options,
+		Multiple:   &multi,
+		HideCounts: &hideCounts,
+		Votes:      votes,
+		Voters:     &voters,
+		ExpiresAt:  endTime,
+		ClosedAt:   closed,
+	}, nil
+}
+
+// ExtractPollOptions extracts poll option name strings, and the 'multiple choice flag' property value from Pollable.
+// Returned vote counts will be empty if vote counts for one or more options could not be found (i.e. hidden).
+func ExtractPollOptions(poll Pollable) (names []string, votes []int, multi bool, err error) {
+	var errs gtserror.MultiError
+
+	// Iterate the oneOf property and gather poll single-choice options.
+	if oneOf := poll.GetActivityStreamsOneOf(); oneOf != nil {
+		for iter := oneOf.Begin(); iter != oneOf.End(); iter = iter.Next() {
+			optionable, ok := iter.GetType().(PollOptionable)
+			if !ok {
+				errs.Appendf("invalid poll option type %T", iter.GetType())
+				continue
+			}
+			names = append(names, ExtractName(optionable))
+			if count, ok := extractRepliesCount(optionable); ok {
+				votes = append(votes, count)
+			}
+		}
+	}
+
+	if len(names) > 0 || len(errs) > 0 {
+		if len(votes) != len(names) {
+			votes = nil
+		}
+		return names, votes, false, errs.Combine()
+	}
+
+	// Iterate the anyOf property and gather poll multi-choice options.
+	if anyOf := poll.GetActivityStreamsAnyOf(); anyOf != nil {
+		for iter := anyOf.Begin(); iter != anyOf.End(); iter = iter.Next() {
+			optionable, ok := iter.GetType().(PollOptionable)
+			if !ok {
+				errs.Appendf("invalid poll option type %T", iter.GetType())
+				continue
+			}
+			names = append(names, ExtractName(optionable))
+			if count, ok := extractRepliesCount(optionable); ok {
+				votes = append(votes, count)
+			}
+		}
+	}
+
+	if len(names) == 0 && len(errs) == 0 {
+		errs.Append(errors.New("poll contained no options"))
+	}
+
+	if len(votes) != len(names) {
+		votes = nil
+	}
+
+	return names, votes, true, errs.Combine()
+}
+
+// extractRepliesCount extracts the 'totalItems' count
+// from the 'replies' collection of the given item,
+// as used by poll options to indicate vote counts.
+func extractRepliesCount(withReplies WithReplies) (int, bool) {
+	repliesProp := withReplies.GetActivityStreamsReplies()
+	if repliesProp == nil || !repliesProp.IsActivityStreamsCollection() {
+		return 0, false
+	}
+
+	collection := repliesProp.GetActivityStreamsCollection()
+	if collection == nil {
+		return 0, false
+	}
+
+	totalItemsProp := collection.GetActivityStreamsTotalItems()
+	if totalItemsProp == nil || !totalItemsProp.IsXMLSchemaNonNegativeInteger() {
+		return 0, false
+	}
+
+	return totalItemsProp.Get(), true
+}
+
+// ExtractEndTime extracts the 'endTime' property from
+// the given item. Returns zero time if not set.
+func ExtractEndTime(withEndTime WithEndTime) time.Time {
+	endTimeProp := withEndTime.GetActivityStreamsEndTime()
+	if endTimeProp == nil || !endTimeProp.IsXMLSchemaDateTime() {
+		return time.Time{}
+	}
+	return endTimeProp.Get()
+}
+
+// ExtractUpdated extracts the 'updated' property from
+// the given item. Returns zero time if not set.
+func ExtractUpdated(withUpdated WithUpdated) time.Time {
+	updatedProp := withUpdated.GetActivityStreamsUpdated()
+	if updatedProp == nil || !updatedProp.IsXMLSchemaDateTime() {
+		return time.Time{}
+	}
+	return updatedProp.Get()
+}
+
+// ExtractVotersCount extracts the 'votersCount' property
+// from the given item. Returns zero if not set.
+func ExtractVotersCount(withVotersCount WithVotersCount) int {
+	votersCountProp := withVotersCount.GetTootVotersCount()
+	if votersCountProp == nil || !votersCountProp.IsXMLSchemaNonNegativeInteger() {
+		return 0
+	}
+	return votersCountProp.Get()
+}
+
 // ExtractSharedInbox extracts the sharedInbox URI property
 // from an Actor. Returns nil if this property is not set.
 func ExtractSharedInbox(withEndpoints WithEndpoints) *url.URL {
@@ -0,0 +1,130 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package ap_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+)
+
+type ExtractPollTestSuite struct {
+	APTestSuite
+}
+
+func (suite *ExtractPollTestSuite) TestExtractPollOneOf() {
+	b := []byte(`{
+  "@context": [
+    "https://www.w3.org/ns/activitystreams",
+    {
+      "toot": "http://joinmastodon.org/ns#",
+      "votersCount": "toot:votersCount"
+    }
+  ],
+  "id": "https://example.org/users/someone/statuses/109403009346545127",
+  "type": "Question",
+  "attributedTo": "https://example.org/users/someone",
+  "content": "<p>what's your favourite colour?</p>",
+  "published": "2022-11-25T12:00:00Z",
+  "to": "https://www.w3.org/ns/activitystreams#Public",
+  "endTime": "2022-11-26T12:00:00Z",
+  "closed": "2022-11-26T12:00:00Z",
+  "votersCount": 3,
+  "oneOf": [
+    {
+      "type": "Note",
+      "name": "red",
+      "replies": {
+        "type": "Collection",
+        "totalItems": 1
+      }
+    },
+    {
+      "type": "Note",
+      "name": "blue",
+      "replies": {
+        "type": "Collection",
+        "totalItems": 2
+      }
+    }
+  ]
+}`)
+
+	statusable, err := ap.ResolveStatusable(context.Background(), b)
+	suite.NoError(err)
+
+	pollable, ok := statusable.(ap.Pollable)
+	suite.True(ok)
+
+	poll, err := ap.ExtractPoll(pollable)
+	suite.NoError(err)
+	suite.Equal([]string{"red", "blue"}, poll.Options)
+	suite.Equal([]int{1, 2}, poll.Votes)
+	suite.Equal(3, *poll.Voters)
+	suite.False(*poll.Multiple)
+	suite.False(*poll.HideCounts)
+	suite.Equal(time.Date(2022, 11, 26, 12, 0, 0, 0, time.UTC), poll.ExpiresAt.UTC())
+	suite.Equal(time.Date(2022, 11, 26, 12, 0, 0, 0, time.UTC), poll.ClosedAt.UTC())
+}
+
+func (suite *ExtractPollTestSuite) TestExtractPollAnyOfHiddenCounts() {
+	b := []byte(`{
+  "@context": "https://www.w3.org/ns/activitystreams",
+  "id": "https://example.org/users/someone/statuses/109403009346545128",
+  "type": "Question",
+  "attributedTo": "https://example.org/users/someone",
+  "content": "<p>which colours do you like?</p>",
+  "published": "2022-11-25T12:00:00Z",
+  "to": "https://www.w3.org/ns/activitystreams#Public",
+  "endTime": "2022-11-26T12:00:00Z",
+  "anyOf": [
+    {
+      "type": "Note",
+      "name": "red"
+    },
+    {
+      "type": "Note",
+      "name": "blue"
+    },
+    {
+      "type": "Note",
+      "name": "green"
+    }
+  ]
+}`)
+
+	statusable, err := ap.ResolveStatusable(context.Background(), b)
+	suite.NoError(err)
+
+	pollable, ok := statusable.(ap.Pollable)
+	suite.True(ok)
+
+	poll, err := ap.ExtractPoll(pollable)
+	suite.NoError(err)
+	suite.Equal([]string{"red", "blue", "green"}, poll.Options)
+	suite.Equal([]int{0, 0, 0}, poll.Votes)
+	suite.True(*poll.Multiple)
+	suite.True(*poll.HideCounts)
+	suite.True(poll.ClosedAt.IsZero())
+}
+
+func TestExtractPollTestSuite(t *testing.T) {
+	suite.Run(t, &ExtractPollTestSuite{})
+}
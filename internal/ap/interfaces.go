@@ -43,42 +43,84 @@ type Accountable interface {
 	WithManuallyApprovesFollowers
 	WithEndpoints
 	WithTag
+	WithUnknownProperties
 }
 
 // Statusable represents the minimum activitypub interface for representing a 'status'.
-// This interface is fulfilled by: Article, Document, Image, Video, Note, Page, Event, Place, Mention, Profile
+// This interface is fulfilled by: Article, Document, Image, Video, Note, Page, Event, Place, Mention, Profile, Question
 type Statusable interface {
-	WithJSONLDId
-	WithTypeName
+	vocab.Type
 
 	WithSummary
 	WithSetSummary
 	WithName
 	WithSetName
 	WithInReplyTo
+	WithSetInReplyTo
 	WithPublished
+	WithSetPublished
+	WithUpdated
+	WithSetUpdated
 	WithURL
+	WithSetURL
 	WithAttributedTo
+	WithSetAttributedTo
 	WithTo
+	WithSetTo
 	WithCC
+	WithSetCC
 	WithSensitive
+	WithSetSensitive
 	WithConversation
 	WithContent
 	WithSetContent
 	WithAttachment
+	WithSetAttachment
 	WithTag
+	WithSetTag
+	WithReplies
+	WithSetReplies
+}
+
+// Pollable represents the minimum activitypub interface for representing a 'poll' (it's a subset of a status).
+// This interface is fulfilled by: Question
+type Pollable interface {
+	WithOneOf
+	WithSetOneOf
+	WithAnyOf
+	WithSetAnyOf
+	WithEndTime
+	WithSetEndTime
+	WithClosed
+	WithSetClosed
+	WithVotersCount
+	WithSetVotersCount
+
+	// base-interface
+	Statusable
+}
+
+// PollOptionable represents the minimum activitypub interface for representing a poll 'option'.
+// This interface is fulfilled by: Note (mastodon's option format, i.e. with a name and replies count).
+type PollOptionable interface {
+	WithTypeName
+	WithName
 	WithReplies
 }
 
 // Attachmentable represents the minimum activitypub interface for representing a 'mediaAttachment'.
 // This interface is fulfilled by: Audio, Document, Image, Video
 type Attachmentable interface {
-	WithTypeName
+	vocab.Type
+
 	WithMediaType
+	WithSetMediaType
 	WithURL
+	WithSetURL
 	WithName
 	WithSetName
 	WithBlurhash
+	WithSetBlurhash
 }
 
 // Hashtaggable represents the minimum activitypub interface for representing a 'hashtag' tag.
@@ -228,6 +270,11 @@ type WithURL interface {
 	GetActivityStreamsUrl() vocab.ActivityStreamsUrlProperty
 }
 
+// WithSetURL represents an activity with a settable ActivityStreamsUrlProperty
+type WithSetURL interface {
+	SetActivityStreamsUrl(vocab.ActivityStreamsUrlProperty)
+}
+
 // WithPublicKey represents an activity with W3IDSecurityV1PublicKeyProperty
 type WithPublicKey interface {
 	GetW3IDSecurityV1PublicKey() vocab.W3IDSecurityV1PublicKeyProperty
@@ -263,31 +310,61 @@ type WithAttributedTo interface {
 	GetActivityStreamsAttributedTo() vocab.ActivityStreamsAttributedToProperty
 }
 
+// WithSetAttributedTo represents an activity with a settable ActivityStreamsAttributedToProperty
+type WithSetAttributedTo interface {
+	SetActivityStreamsAttributedTo(vocab.ActivityStreamsAttributedToProperty)
+}
+
 // WithAttachment represents an activity with ActivityStreamsAttachmentProperty
 type WithAttachment interface {
 	GetActivityStreamsAttachment() vocab.ActivityStreamsAttachmentProperty
 }
 
+// WithSetAttachment represents an activity with a settable ActivityStreamsAttachmentProperty
+type WithSetAttachment interface {
+	SetActivityStreamsAttachment(vocab.ActivityStreamsAttachmentProperty)
+}
+
 // WithTo represents an activity with ActivityStreamsToProperty
 type WithTo interface {
 	GetActivityStreamsTo() vocab.ActivityStreamsToProperty
 }
 
+// WithSetTo represents an activity with a settable ActivityStreamsToProperty
+type WithSetTo interface {
+	SetActivityStreamsTo(vocab.ActivityStreamsToProperty)
+}
+
 // WithInReplyTo represents an activity with ActivityStreamsInReplyToProperty
 type WithInReplyTo interface {
 	GetActivityStreamsInReplyTo() vocab.ActivityStreamsInReplyToProperty
 }
 
+// WithSetInReplyTo represents an activity with a settable ActivityStreamsInReplyToProperty
+type WithSetInReplyTo interface {
+	SetActivityStreamsInReplyTo(vocab.ActivityStreamsInReplyToProperty)
+}
+
 // WithCC represents an activity with ActivityStreamsCcProperty
 type WithCC interface {
 	GetActivityStreamsCc() vocab.ActivityStreamsCcProperty
 }
 
+// WithSetCC represents an activity with a settable ActivityStreamsCcProperty
+type WithSetCC interface {
+	SetActivityStreamsCc(vocab.ActivityStreamsCcProperty)
+}
+
 // WithSensitive represents an activity with ActivityStreamsSensitiveProperty
 type WithSensitive interface {
 	GetActivityStreamsSensitive() vocab.ActivityStreamsSensitiveProperty
 }
 
+// WithSetSensitive represents an activity with a settable ActivityStreamsSensitiveProperty
+type WithSetSensitive interface {
+	SetActivityStreamsSensitive(vocab.ActivityStreamsSensitiveProperty)
+}
+
 // WithConversation ...
 type WithConversation interface { // TODO
 }
@@ -307,26 +384,101 @@ type WithPublished interface {
 	GetActivityStreamsPublished() vocab.ActivityStreamsPublishedProperty
 }
 
+// WithSetPublished represents an activity with a settable ActivityStreamsPublishedProperty
+type WithSetPublished interface {
+	SetActivityStreamsPublished(vocab.ActivityStreamsPublishedProperty)
+}
+
 // WithTag represents an activity with ActivityStreamsTagProperty
 type WithTag interface {
 	GetActivityStreamsTag() vocab.ActivityStreamsTagProperty
 }
 
+// WithSetTag represents an activity with a settable ActivityStreamsTagProperty
+type WithSetTag interface {
+	SetActivityStreamsTag(vocab.ActivityStreamsTagProperty)
+}
+
 // WithReplies represents an activity with ActivityStreamsRepliesProperty
 type WithReplies interface {
 	GetActivityStreamsReplies() vocab.ActivityStreamsRepliesProperty
 }
 
+// WithSetReplies represents an activity with a settable ActivityStreamsRepliesProperty
+type WithSetReplies interface {
+	SetActivityStreamsReplies(vocab.ActivityStreamsRepliesProperty)
+}
+
+// WithOneOf represents an activity with ActivityStreamsOneOfProperty
+type WithOneOf interface {
+	GetActivityStreamsOneOf() vocab.ActivityStreamsOneOfProperty
+}
+
+// WithSetOneOf represents an activity with a settable ActivityStreamsOneOfProperty
+type WithSetOneOf interface {
+	SetActivityStreamsOneOf(vocab.ActivityStreamsOneOfProperty)
+}
+
+// WithAnyOf represents an activity with ActivityStreamsAnyOfProperty
+type WithAnyOf interface {
+	GetActivityStreamsAnyOf() vocab.ActivityStreamsAnyOfProperty
+}
+
+// WithSetAnyOf represents an activity with a settable ActivityStreamsAnyOfProperty
+type WithSetAnyOf interface {
+	SetActivityStreamsAnyOf(vocab.ActivityStreamsAnyOfProperty)
+}
+
+// WithEndTime represents an activity with ActivityStreamsEndTimeProperty
+type WithEndTime interface {
+	GetActivityStreamsEndTime() vocab.ActivityStreamsEndTimeProperty
+}
+
+// WithSetEndTime represents an activity with a settable ActivityStreamsEndTimeProperty
+type WithSetEndTime interface {
+	SetActivityStreamsEndTime(vocab.ActivityStreamsEndTimeProperty)
+}
+
+// WithClosed represents an activity with ActivityStreamsClosedProperty
+type WithClosed interface {
+	GetActivityStreamsClosed() vocab.ActivityStreamsClosedProperty
+}
+
+// WithSetClosed represents an activity with a settable ActivityStreamsClosedProperty
+type WithSetClosed interface {
+	SetActivityStreamsClosed(vocab.ActivityStreamsClosedProperty)
+}
+
+// WithVotersCount represents an activity with TootVotersCountProperty
+type WithVotersCount interface {
+	GetTootVotersCount() vocab.TootVotersCountProperty
+}
+
+// WithSetVotersCount represents an activity with a settable TootVotersCountProperty
+type WithSetVotersCount interface {
+	SetTootVotersCount(vocab.TootVotersCountProperty)
+}
+
 // WithMediaType represents an activity with ActivityStreamsMediaTypeProperty
 type WithMediaType interface {
 	GetActivityStreamsMediaType() vocab.ActivityStreamsMediaTypeProperty
 }
 
+// WithSetMediaType represents an activity with ActivityStreamsMediaTypeProperty
+type WithSetMediaType interface {
+	SetActivityStreamsMediaType(vocab.ActivityStreamsMediaTypeProperty)
+}
+
 // WithBlurhash represents an activity with TootBlurhashProperty
 type WithBlurhash interface {
 	GetTootBlurhash() vocab.TootBlurhashProperty
 }
 
+// WithSetBlurhash represents an activity with TootBlurhashProperty
+type WithSetBlurhash interface {
+	SetTootBlurhash(vocab.TootBlurhashProperty)
+}
+
 // type withFocalPoint interface {
 // 	// TODO
 // }
@@ -341,6 +493,11 @@ type WithUpdated interface {
 	GetActivityStreamsUpdated() vocab.ActivityStreamsUpdatedProperty
 }
 
+// WithSetUpdated represents an activity with a settable ActivityStreamsUpdatedProperty
+type WithSetUpdated interface {
+	SetActivityStreamsUpdated(vocab.ActivityStreamsUpdatedProperty)
+}
+
 // WithActor represents an activity with ActivityStreamsActorProperty
 type WithActor interface {
 	GetActivityStreamsActor() vocab.ActivityStreamsActorProperty
@@ -375,3 +532,9 @@ type WithManuallyApprovesFollowers interface {
 type WithEndpoints interface {
 	GetActivityStreamsEndpoints() vocab.ActivityStreamsEndpointsProperty
 }
+
+// WithUnknownProperties represents an activity or object with properties
+// not known to the underlying ActivityStreams vocabulary, eg., alsoKnownAs.
+type WithUnknownProperties interface {
+	GetUnknownProperties() map[string]interface{}
+}
@@ -20,6 +20,7 @@ package ap
 import (
 	"github.com/superseriousbusiness/activity/pub"
 	"github.com/superseriousbusiness/activity/streams"
+	"github.com/superseriousbusiness/activity/streams/vocab"
 )
 
 /*
@@ -75,7 +76,7 @@ func NormalizeIncomingActivityObject(activity pub.Activity, rawJSON map[string]i
 	}
 
 	switch t.GetTypeName() {
-	case ObjectArticle, ObjectDocument, ObjectImage, ObjectVideo, ObjectNote, ObjectPage, ObjectEvent, ObjectPlace, ObjectProfile:
+	case ObjectArticle, ObjectDocument, ObjectImage, ObjectVideo, ObjectNote, ObjectPage, ObjectEvent, ObjectPlace, ObjectProfile, ActivityQuestion:
 		statusable, ok := t.(Statusable)
 		if !ok {
 			// Object is not Statusable;
@@ -100,6 +101,11 @@ func NormalizeIncomingActivityObject(activity pub.Activity, rawJSON map[string]i
 		NormalizeIncomingAttachments(statusable, rawStatusableJSON)
 		NormalizeIncomingSummary(statusable, rawStatusableJSON)
 		NormalizeIncomingName(statusable, rawStatusableJSON)
+
+		if pollable, ok := statusable.(Pollable); ok {
+			// Normalize poll options if this is a poll.
+			NormalizeIncomingPollOptions(pollable, rawStatusableJSON)
+		}
 	case ActorApplication, ActorGroup, ActorOrganization, ActorPerson, ActorService:
 		accountable, ok := t.(Accountable)
 		if !ok {
@@ -260,3 +266,66 @@ func NormalizeIncomingName(item WithSetName, rawJSON map[string]interface{}) {
 	nameProp.AppendXMLSchemaString(name)
 	item.SetActivityStreamsName(nameProp)
 }
+
+// NormalizeIncomingPollOptions normalizes all poll options (if any) of
+// the given pollable, replacing the 'name' field of each option with
+// the raw 'name' value from the raw json object map.
+//
+// noop if there are no poll options; noop if options are not in a
+// format we can understand.
+func NormalizeIncomingPollOptions(item Pollable, rawJSON map[string]interface{}) {
+	var (
+		options    []vocab.Type
+		rawOptions []interface{}
+	)
+
+	if oneOf := item.GetActivityStreamsOneOf(); oneOf != nil && oneOf.Len() > 0 {
+		// Single-choice poll.
+		for iter := oneOf.Begin(); iter != oneOf.End(); iter = iter.Next() {
+			options = append(options, iter.GetType())
+		}
+		rawOptions = toSlice(rawJSON["oneOf"])
+	} else if anyOf := item.GetActivityStreamsAnyOf(); anyOf != nil && anyOf.Len() > 0 {
+		// Multiple-choice poll.
+		for iter := anyOf.Begin(); iter != anyOf.End(); iter = iter.Next() {
+			options = append(options, iter.GetType())
+		}
+		rawOptions = toSlice(rawJSON["anyOf"])
+	}
+
+	if len(options) != len(rawOptions) {
+		// Mismatch between item and
+		// JSON, can't normalize.
+		return
+	}
+
+	for i, t := range options {
+		if t == nil {
+			continue
+		}
+
+		nameable, ok := t.(WithSetName)
+		if !ok {
+			continue
+		}
+
+		rawOption, ok := rawOptions[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		NormalizeIncomingName(nameable, rawOption)
+	}
+}
+
+// toSlice returns the given interface as a slice
+// of interfaces, wrapping it if not already a slice.
+func toSlice(i interface{}) []interface{} {
+	if i == nil {
+		return nil
+	}
+	if s, ok := i.([]interface{}); ok {
+		return s
+	}
+	return []interface{}{i}
+}
@@ -29,7 +29,7 @@ import (
 // ResolveStatusable tries to resolve the given bytes into an ActivityPub Statusable representation.
 // It will then perform normalization on the Statusable.
 //
-// Works for: Article, Document, Image, Video, Note, Page, Event, Place, Profile
+// Works for: Article, Document, Image, Video, Note, Page, Event, Place, Profile, Question
 func ResolveStatusable(ctx context.Context, b []byte) (Statusable, error) {
 	rawStatusable := make(map[string]interface{})
 	if err := json.Unmarshal(b, &rawStatusable); err != nil {
@@ -65,6 +65,8 @@ func ResolveStatusable(ctx context.Context, b []byte) (Statusable, error) {
 		statusable, ok = t.(vocab.ActivityStreamsPlace)
 	case ObjectProfile:
 		statusable, ok = t.(vocab.ActivityStreamsProfile)
+	case ActivityQuestion:
+		statusable, ok = t.(vocab.ActivityStreamsQuestion)
 	}
 
 	if !ok {
@@ -77,6 +79,11 @@ func ResolveStatusable(ctx context.Context, b []byte) (Statusable, error) {
 	NormalizeIncomingSummary(statusable, rawStatusable)
 	NormalizeIncomingName(statusable, rawStatusable)
 
+	if pollable, ok := statusable.(Pollable); ok {
+		// Normalize poll options if this is a poll.
+		NormalizeIncomingPollOptions(pollable, rawStatusable)
+	}
+
 	return statusable, nil
 }
 
@@ -25,6 +25,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/oidc"
 	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	"github.com/superseriousbusiness/gotosocial/internal/uris"
 )
 
 const (
@@ -34,6 +35,10 @@ const (
 
 	// AuthSignInPath is the API path for users to sign in through
 	AuthSignInPath = "/sign_in"
+	// AuthSignUpPath is the API path for people to sign up for a new account through
+	AuthSignUpPath = "/" + uris.SignUpPath
+	// AuthTwoFactorPath users land here after signing in with a password, if they have two-factor authentication enabled
+	AuthTwoFactorPath = "/2fa"
 	// AuthCheckYourEmailPath users land here after registering a new account, instructs them to confirm their email
 	AuthCheckYourEmailPath = "/check_your_email"
 	// AuthWaitForApprovalPath users land here after confirming their email
@@ -41,6 +46,10 @@ const (
 	AuthWaitForApprovalPath = "/wait_for_approval"
 	// AuthAccountDisabledPath users land here when their account is suspended by an admin
 	AuthAccountDisabledPath = "/account_disabled"
+	// AuthForgotPasswordPath users land here to request a password reset email
+	AuthForgotPasswordPath = "/forgot_password"
+	// AuthResetPasswordPath users land here from a password reset email, to set a new password
+	AuthResetPasswordPath = "/reset_password"
 	// AuthCallbackPath is the API path for receiving callback tokens from external OIDC providers
 	AuthCallbackPath = "/callback"
 
@@ -61,18 +70,20 @@ const (
 		params / session keys
 	*/
 
-	callbackStateParam   = "state"
-	callbackCodeParam    = "code"
-	sessionUserID        = "userid"
-	sessionClientID      = "client_id"
-	sessionRedirectURI   = "redirect_uri"
-	sessionForceLogin    = "force_login"
-	sessionResponseType  = "response_type"
-	sessionScope         = "scope"
-	sessionInternalState = "internal_state"
-	sessionClientState   = "client_state"
-	sessionClaims        = "claims"
-	sessionAppID         = "app_id"
+	callbackStateParam     = "state"
+	callbackCodeParam      = "code"
+	inviteParam            = "invite"
+	sessionUserID          = "userid"
+	sessionTwoFactorUserID = "2fa_userid"
+	sessionClientID        = "client_id"
+	sessionRedirectURI     = "redirect_uri"
+	sessionForceLogin      = "force_login"
+	sessionResponseType    = "response_type"
+	sessionScope           = "scope"
+	sessionInternalState   = "internal_state"
+	sessionClientState     = "client_state"
+	sessionClaims          = "claims"
+	sessionAppID           = "app_id"
 )
 
 type Module struct {
@@ -96,7 +107,17 @@ func New(db db.DB, processor *processing.Processor, idp oidc.IDP) *Module {
 func (m *Module) RouteAuth(attachHandler func(method string, path string, f ...gin.HandlerFunc) gin.IRoutes) {
 	attachHandler(http.MethodGet, AuthSignInPath, m.SignInGETHandler)
 	attachHandler(http.MethodPost, AuthSignInPath, m.SignInPOSTHandler)
+	attachHandler(http.MethodGet, AuthTwoFactorPath, m.TwoFactorGETHandler)
+	attachHandler(http.MethodPost, AuthTwoFactorPath, m.TwoFactorPOSTHandler)
+	attachHandler(http.MethodGet, AuthSignUpPath, m.SignUpGETHandler)
+	attachHandler(http.MethodPost, AuthSignUpPath, m.SignUpPOSTHandler)
+	attachHandler(http.MethodGet, AuthCheckYourEmailPath, m.CheckYourEmailGETHandler)
+	attachHandler(http.MethodGet, AuthWaitForApprovalPath, m.WaitForApprovalGETHandler)
 	attachHandler(http.MethodGet, AuthCallbackPath, m.CallbackGETHandler)
+	attachHandler(http.MethodGet, AuthForgotPasswordPath, m.ForgotPasswordGETHandler)
+	attachHandler(http.MethodPost, AuthForgotPasswordPath, m.ForgotPasswordPOSTHandler)
+	attachHandler(http.MethodGet, AuthResetPasswordPath, m.ResetPasswordGETHandler)
+	attachHandler(http.MethodPost, AuthResetPasswordPath, m.ResetPasswordPOSTHandler)
 }
 
 // RouteOauth routes all paths that should have an 'oauth' prefix
@@ -138,6 +138,14 @@ func (m *Module) AuthorizeGETHandler(c *gin.Context) {
 		return
 	}
 
+	// Don't let the app ask for
+	// more than it registered for.
+	if err := oauth.CheckScopesPermitted(scope, app.Scopes); err != nil {
+		m.clearSession(s)
+		apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, err.Error(), oauth.HelpfulAdvice), m.processor.InstanceGetV1)
+		return
+	}
+
 	instance, errWithCode := m.processor.InstanceGetV1(c.Request.Context())
 	if errWithCode != nil {
 		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
@@ -293,6 +301,10 @@ func saveAuthFormToSession(s sessions.Session, form *apimodel.OAuthAuthorize) gt
 		form.Scope = "read"
 	}
 
+	if err := oauth.ValidateScopes(form.Scope); err != nil {
+		return gtserror.NewErrorBadRequest(err, err.Error(), oauth.HelpfulAdvice)
+	}
+
 	// save these values from the form so we can use them elsewhere in the session
 	s.Set(sessionForceLogin, form.ForceLogin)
 	s.Set(sessionResponseType, form.ResponseType)
@@ -113,6 +113,24 @@ func (suite *AuthAuthorizeTestSuite) TestAccountAuthorizeHandler() {
 	}
 }
 
+func (suite *AuthAuthorizeTestSuite) TestAuthorizeScopeNotRegistered() {
+	ctx, recorder := suite.newContext(http.MethodGet, auth.OauthAuthorizePath, nil, "")
+
+	// The app only registered for "read write follow push".
+	testSession := sessions.Default(ctx)
+	testSession.Set(sessionUserID, suite.testUsers["local_account_1"].ID)
+	testSession.Set(sessionClientID, suite.testApplications["application_1"].ClientID)
+	testSession.Set("redirect_uri", "http://localhost:8080")
+	testSession.Set("scope", "read admin:read")
+	if err := testSession.Save(); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.authModule.AuthorizeGETHandler(ctx)
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+	suite.Contains(recorder.Body.String(), "scope admin:read is not permitted by the scopes of the application")
+}
+
 func TestAccountUpdateTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthAuthorizeTestSuite))
 }
@@ -0,0 +1,139 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apiutil "github.com/superseriousbusiness/gotosocial/internal/api/util"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// forgotPassword wraps a form-submitted email address for a password reset request.
+type forgotPassword struct {
+	Email string `form:"email"`
+}
+
+// resetPassword wraps a form-submitted reset token and new password.
+type resetPassword struct {
+	Token       string `form:"token"`
+	NewPassword string `form:"new_password"`
+}
+
+// ForgotPasswordGETHandler should be served at https://example.org/auth/forgot_password.
+// It presents a form where the user can enter their email address to request a password reset.
+func (m *Module) ForgotPasswordGETHandler(c *gin.Context) {
+	if _, err := apiutil.NegotiateAccept(c, apiutil.HTMLAcceptHeaders...); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	instance, errWithCode := m.processor.InstanceGetV1(c.Request.Context())
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	c.HTML(http.StatusOK, "forgot-password.tmpl", gin.H{
+		"instance": instance,
+	})
+}
+
+// ForgotPasswordPOSTHandler should be served at https://example.org/auth/forgot_password.
+// It sends a password reset link to the submitted email address, if it belongs to a user.
+// The response is the same either way, so as not to reveal which addresses have accounts.
+func (m *Module) ForgotPasswordPOSTHandler(c *gin.Context) {
+	form := &forgotPassword{}
+	if err := c.ShouldBind(form); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, oauth.HelpfulAdvice), m.processor.InstanceGetV1)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if errWithCode := m.processor.User().PasswordResetRequest(ctx, form.Email); errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	instance, errWithCode := m.processor.InstanceGetV1(ctx)
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	c.HTML(http.StatusOK, "forgot-password.tmpl", gin.H{
+		"instance": instance,
+		"sent":     true,
+	})
+}
+
+// ResetPasswordGETHandler should be served at https://example.org/auth/reset_password.
+// It checks the reset token from the emailed link, and presents a form to set a new password.
+func (m *Module) ResetPasswordGETHandler(c *gin.Context) {
+	if _, err := apiutil.NegotiateAccept(c, apiutil.HTMLAcceptHeaders...); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	ctx := c.Request.Context()
+	token := c.Query("token")
+	if _, errWithCode := m.processor.User().PasswordResetCheck(ctx, token); errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	instance, errWithCode := m.processor.InstanceGetV1(ctx)
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	c.HTML(http.StatusOK, "reset-password.tmpl", gin.H{
+		"instance": instance,
+		"token":    token,
+	})
+}
+
+// ResetPasswordPOSTHandler should be served at https://example.org/auth/reset_password.
+// It sets the submitted new password for the user that the reset token belongs to.
+func (m *Module) ResetPasswordPOSTHandler(c *gin.Context) {
+	form := &resetPassword{}
+	if err := c.ShouldBind(form); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, oauth.HelpfulAdvice), m.processor.InstanceGetV1)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if errWithCode := m.processor.User().PasswordReset(ctx, form.Token, form.NewPassword); errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	instance, errWithCode := m.processor.InstanceGetV1(ctx)
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	c.HTML(http.StatusOK, "reset-password.tmpl", gin.H{
+		"instance": instance,
+		"reset":    true,
+	})
+}
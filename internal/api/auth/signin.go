@@ -98,6 +98,27 @@ func (m *Module) SignInPOSTHandler(c *gin.Context) {
 		return
 	}
 
+	user, err := m.db.GetUserByID(c.Request.Context(), userid)
+	if err != nil {
+		err := fmt.Errorf("user %s was not retrievable from db during sign in: %w", userid, err)
+		apiutil.ErrorHandler(c, gtserror.NewErrorInternalError(err, oauth.HelpfulAdvice), m.processor.InstanceGetV1)
+		return
+	}
+
+	if !user.TwoFactorEnabledAt.IsZero() {
+		// The user still needs to enter a code from
+		// their authenticator app before continuing.
+		s.Set(sessionTwoFactorUserID, userid)
+		if err := s.Save(); err != nil {
+			err := fmt.Errorf("error saving user id onto session: %s", err)
+			apiutil.ErrorHandler(c, gtserror.NewErrorInternalError(err, oauth.HelpfulAdvice), m.processor.InstanceGetV1)
+			return
+		}
+
+		c.Redirect(http.StatusFound, "/auth"+AuthTwoFactorPath)
+		return
+	}
+
 	s.Set(sessionUserID, userid)
 	if err := s.Save(); err != nil {
 		err := fmt.Errorf("error saving user id onto session: %s", err)
@@ -0,0 +1,182 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package auth
+
+import (
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	apiutil "github.com/superseriousbusiness/gotosocial/internal/api/util"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/validate"
+	"golang.org/x/text/language"
+)
+
+// defaultSignUpLocale is used for new accounts if the
+// browser doesn't tell us which language it prefers.
+const defaultSignUpLocale = "en"
+
+// signUp wraps a form-submitted sign up request.
+type signUp struct {
+	Username   string `form:"username"`
+	Email      string `form:"email"`
+	Password   string `form:"password"`
+	Reason     string `form:"reason"`
+	InviteCode string `form:"invite_code"`
+	Agreement  bool   `form:"agreement"`
+}
+
+// SignUpGETHandler should be served at https://example.org/auth/sign_up.
+// It presents a form where people can sign up for a new account, if
+// registration is open, or if they followed a link with a valid invite.
+func (m *Module) SignUpGETHandler(c *gin.Context) {
+	if _, err := apiutil.NegotiateAccept(c, apiutil.HTMLAcceptHeaders...); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	m.signUpPage(c, http.StatusOK, &apimodel.AccountCreateRequest{
+		InviteCode: c.Query(inviteParam),
+	}, nil)
+}
+
+// SignUpPOSTHandler should be served at https://example.org/auth/sign_up.
+// It creates a new account from the submitted form, and then redirects
+// to a page telling the new user what to do next. If the form can't be
+// accepted, the sign up page is shown again with the reason why.
+func (m *Module) SignUpPOSTHandler(c *gin.Context) {
+	signUpForm := &signUp{}
+	if err := c.ShouldBind(signUpForm); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, oauth.HelpfulAdvice), m.processor.InstanceGetV1)
+		return
+	}
+
+	form := &apimodel.AccountCreateRequest{
+		Username:   signUpForm.Username,
+		Email:      signUpForm.Email,
+		Password:   signUpForm.Password,
+		Reason:     signUpForm.Reason,
+		InviteCode: signUpForm.InviteCode,
+		Agreement:  signUpForm.Agreement,
+		Locale:     signUpLocale(c.GetHeader("Accept-Language")),
+	}
+
+	if err := validate.CreateAccount(form); err != nil {
+		m.signUpPage(c, http.StatusBadRequest, form, err)
+		return
+	}
+
+	signUpIP := net.ParseIP(c.ClientIP())
+	if signUpIP == nil {
+		err := errors.New("ip address could not be parsed from request")
+		apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+	form.IP = signUpIP
+
+	user, errWithCode := m.processor.Account().SignUp(c.Request.Context(), form)
+	if errWithCode != nil {
+		if errWithCode.Code() == http.StatusInternalServerError {
+			apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+			return
+		}
+
+		// Username taken, invalid invite etc;
+		// let the user have another go at it.
+		m.signUpPage(c, errWithCode.Code(), form, errors.New(errWithCode.Safe()))
+		return
+	}
+
+	if !*user.Approved {
+		c.Redirect(http.StatusSeeOther, "/auth"+AuthWaitForApprovalPath)
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, "/auth"+AuthCheckYourEmailPath)
+}
+
+// CheckYourEmailGETHandler should be served at https://example.org/auth/check_your_email.
+// It tells a new user to confirm their email address before they can sign in.
+func (m *Module) CheckYourEmailGETHandler(c *gin.Context) {
+	m.signUpNextStepPage(c, "check-your-email.tmpl")
+}
+
+// WaitForApprovalGETHandler should be served at https://example.org/auth/wait_for_approval.
+// It tells a new user that their account has to be approved before they can sign in.
+func (m *Module) WaitForApprovalGETHandler(c *gin.Context) {
+	m.signUpNextStepPage(c, "wait-for-approval.tmpl")
+}
+
+// signUpPage renders the sign up page with the given
+// status code, filled in with the given form values,
+// and showing the given error (if any).
+func (m *Module) signUpPage(c *gin.Context, code int, form *apimodel.AccountCreateRequest, err error) {
+	instance, errWithCode := m.processor.InstanceGetV1(c.Request.Context())
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	c.HTML(code, "sign-up.tmpl", gin.H{
+		"instance":       instance,
+		"open":           config.GetAccountsRegistrationOpen() || form.InviteCode != "",
+		"reasonRequired": config.GetAccountsReasonRequired(),
+		"form":           form,
+		"error":          err,
+	})
+}
+
+// signUpNextStepPage renders the given template,
+// which tells a new user what to do after signing up.
+func (m *Module) signUpNextStepPage(c *gin.Context, template string) {
+	if _, err := apiutil.NegotiateAccept(c, apiutil.HTMLAcceptHeaders...); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	instance, errWithCode := m.processor.InstanceGetV1(c.Request.Context())
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	c.HTML(http.StatusOK, template, gin.H{
+		"instance": instance,
+	})
+}
+
+// signUpLocale returns the base language most preferred in
+// the given Accept-Language header, or the default if none.
+func signUpLocale(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return defaultSignUpLocale
+	}
+
+	base, confidence := tags[0].Base()
+	if confidence == language.No {
+		return defaultSignUpLocale
+	}
+
+	return base.String()
+}
@@ -0,0 +1,187 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package auth_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/auth"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/uris"
+)
+
+type AuthSignUpTestSuite struct {
+	AuthStandardTestSuite
+}
+
+func (suite *AuthSignUpTestSuite) signUpForm() url.Values {
+	return url.Values{
+		"username":  {"new_person"},
+		"email":     {"new_person@example.org"},
+		"password":  {"sdfkjhsdfkjhsdfkjhsdf1!"},
+		"reason":    {"I'd like to join because this instance seems really great, honestly."},
+		"agreement": {"true"},
+	}
+}
+
+func (suite *AuthSignUpTestSuite) body(r io.Reader) string {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	return string(b)
+}
+
+func (suite *AuthSignUpTestSuite) TestSignUpGET() {
+	ctx, recorder := suite.newContext(http.MethodGet, "auth"+auth.AuthSignUpPath, nil, "")
+	suite.authModule.SignUpGETHandler(ctx)
+
+	suite.Equal(http.StatusOK, recorder.Code)
+	body := suite.body(recorder.Body)
+	suite.Contains(body, `<form action="/auth/sign_up" method="POST">`)
+	suite.Contains(body, `name="reason"`)
+	suite.Contains(body, "New accounts have to be approved by an admin")
+}
+
+func (suite *AuthSignUpTestSuite) TestSignUpGETClosed() {
+	config.SetAccountsRegistrationOpen(false)
+
+	ctx, recorder := suite.newContext(http.MethodGet, "auth"+auth.AuthSignUpPath, nil, "")
+	suite.authModule.SignUpGETHandler(ctx)
+
+	suite.Equal(http.StatusOK, recorder.Code)
+	body := suite.body(recorder.Body)
+	suite.NotContains(body, `<form action="/auth/sign_up" method="POST">`)
+	suite.Contains(body, "Registration is not open on this instance.")
+}
+
+func (suite *AuthSignUpTestSuite) TestSignUpGETClosedWithInvite() {
+	config.SetAccountsRegistrationOpen(false)
+
+	ctx, recorder := suite.newContext(http.MethodGet, "auth"+auth.AuthSignUpPath+"?invite=wkTbG4Ya", nil, "")
+	suite.authModule.SignUpGETHandler(ctx)
+
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Contains(suite.body(recorder.Body), `<input type="hidden" name="invite_code" value="wkTbG4Ya">`)
+}
+
+func (suite *AuthSignUpTestSuite) TestSignUpGETInviteLink() {
+	config.SetAccountsRegistrationOpen(false)
+
+	// Invite links handed out by the
+	// API must land on the sign-up page.
+	link, err := url.Parse(uris.GenerateURIForInvite("wkTbG4Ya"))
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal("/auth"+auth.AuthSignUpPath, link.Path)
+
+	ctx, recorder := suite.newContext(http.MethodGet, strings.TrimPrefix(link.RequestURI(), "/"), nil, "")
+	suite.authModule.SignUpGETHandler(ctx)
+
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Contains(suite.body(recorder.Body), `<input type="hidden" name="invite_code" value="wkTbG4Ya">`)
+}
+
+func (suite *AuthSignUpTestSuite) TestSignUpPOST() {
+	ctx, recorder := suite.newContext(http.MethodPost, "auth"+auth.AuthSignUpPath, []byte(suite.signUpForm().Encode()), "application/x-www-form-urlencoded")
+	ctx.Request.Header.Set("Accept-Language", "de-DE,de;q=0.9,en;q=0.8")
+	suite.authModule.SignUpPOSTHandler(ctx)
+
+	// approval is required, so new user should be told to wait
+	suite.Equal(http.StatusSeeOther, ctx.Writer.Status())
+	suite.Equal("/auth"+auth.AuthWaitForApprovalPath, recorder.Header().Get("Location"))
+
+	account, err := suite.db.GetAccountByUsernameDomain(context.Background(), "new_person", "")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	user, err := suite.db.GetUserByAccountID(context.Background(), account.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal("new_person@example.org", user.UnconfirmedEmail)
+	suite.Equal("de", user.Locale)
+	suite.Empty(user.CreatedByApplicationID)
+	suite.False(*user.Approved)
+}
+
+func (suite *AuthSignUpTestSuite) TestSignUpPOSTNoApprovalRequired() {
+	config.SetAccountsApprovalRequired(false)
+
+	ctx, recorder := suite.newContext(http.MethodPost, "auth"+auth.AuthSignUpPath, []byte(suite.signUpForm().Encode()), "application/x-www-form-urlencoded")
+	suite.authModule.SignUpPOSTHandler(ctx)
+
+	suite.Equal(http.StatusSeeOther, ctx.Writer.Status())
+	suite.Equal("/auth"+auth.AuthCheckYourEmailPath, recorder.Header().Get("Location"))
+}
+
+func (suite *AuthSignUpTestSuite) TestSignUpPOSTNoAgreement() {
+	form := suite.signUpForm()
+	form.Del("agreement")
+
+	ctx, recorder := suite.newContext(http.MethodPost, "auth"+auth.AuthSignUpPath, []byte(form.Encode()), "application/x-www-form-urlencoded")
+	suite.authModule.SignUpPOSTHandler(ctx)
+
+	// form should be shown again, keeping what was entered
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+	body := suite.body(recorder.Body)
+	suite.Contains(body, "agreement to terms and conditions not given")
+	suite.Contains(body, `value="new_person"`)
+}
+
+func (suite *AuthSignUpTestSuite) TestSignUpPOSTUsernameTaken() {
+	form := suite.signUpForm()
+	form.Set("username", "the_mighty_zork")
+
+	ctx, recorder := suite.newContext(http.MethodPost, "auth"+auth.AuthSignUpPath, []byte(form.Encode()), "application/x-www-form-urlencoded")
+	suite.authModule.SignUpPOSTHandler(ctx)
+
+	suite.Equal(http.StatusConflict, recorder.Code)
+	suite.Contains(suite.body(recorder.Body), "username the_mighty_zork is not available")
+}
+
+func (suite *AuthSignUpTestSuite) TestSignUpPOSTClosed() {
+	config.SetAccountsRegistrationOpen(false)
+
+	ctx, recorder := suite.newContext(http.MethodPost, "auth"+auth.AuthSignUpPath, []byte(suite.signUpForm().Encode()), "application/x-www-form-urlencoded")
+	suite.authModule.SignUpPOSTHandler(ctx)
+
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+	suite.Contains(suite.body(recorder.Body), "Registration is not open on this instance.")
+}
+
+func (suite *AuthSignUpTestSuite) TestWaitForApprovalGET() {
+	ctx, recorder := suite.newContext(http.MethodGet, "auth"+auth.AuthWaitForApprovalPath, nil, "")
+	suite.authModule.WaitForApprovalGETHandler(ctx)
+
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Contains(suite.body(recorder.Body), "Waiting for approval")
+}
+
+func TestAuthSignUpTestSuite(t *testing.T) {
+	suite.Run(t, &AuthSignUpTestSuite{})
+}
@@ -0,0 +1,114 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	apiutil "github.com/superseriousbusiness/gotosocial/internal/api/util"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+// twoFactor wraps a form-submitted two-factor authentication or recovery code.
+type twoFactor struct {
+	Code string `form:"code"`
+}
+
+// TwoFactorGETHandler should be served at https://example.org/auth/2fa.
+// Users who have two-factor authentication enabled are redirected here after
+// entering their email address and password, to enter a code from their
+// authenticator app. The form will then POST to TwoFactorPOSTHandler.
+func (m *Module) TwoFactorGETHandler(c *gin.Context) {
+	if _, err := apiutil.NegotiateAccept(c, apiutil.HTMLAcceptHeaders...); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
+	s := sessions.Default(c)
+	if _, ok := s.Get(sessionTwoFactorUserID).(string); !ok {
+		// Password step wasn't completed,
+		// so send them back to sign in.
+		c.Redirect(http.StatusSeeOther, "/auth"+AuthSignInPath)
+		return
+	}
+
+	instance, errWithCode := m.processor.InstanceGetV1(c.Request.Context())
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	c.HTML(http.StatusOK, "2fa.tmpl", gin.H{
+		"instance": instance,
+	})
+}
+
+// TwoFactorPOSTHandler should be served at https://example.org/auth/2fa.
+// It checks the submitted code for the user who completed the password step,
+// and if it's correct, continues on to the auth handler served at /auth.
+func (m *Module) TwoFactorPOSTHandler(c *gin.Context) {
+	s := sessions.Default(c)
+
+	userID, ok := s.Get(sessionTwoFactorUserID).(string)
+	if !ok {
+		m.clearSession(s)
+		err := fmt.Errorf("key %s was not found in session", sessionTwoFactorUserID)
+		apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, oauth.HelpfulAdvice), m.processor.InstanceGetV1)
+		return
+	}
+
+	form := &twoFactor{}
+	if err := c.ShouldBind(form); err != nil {
+		m.clearSession(s)
+		apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, oauth.HelpfulAdvice), m.processor.InstanceGetV1)
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := m.db.GetUserByID(ctx, userID)
+	if err != nil {
+		m.clearSession(s)
+		err := fmt.Errorf("user %s was not retrievable from db during two-factor check: %w", userID, err)
+		apiutil.ErrorHandler(c, gtserror.NewErrorInternalError(err, oauth.HelpfulAdvice), m.processor.InstanceGetV1)
+		return
+	}
+
+	// Incorrect codes are counted against the user (not the
+	// session), and the processor locks them out after too
+	// many, so codes can't be guessed with just a password.
+	if errWithCode := m.processor.User().TwoFactorCheck(ctx, user, form.Code); errWithCode != nil {
+		// don't clear session here, so the user can just press
+		// back and try again if they made a typo or something
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	s.Delete(sessionTwoFactorUserID)
+	s.Set(sessionUserID, user.ID)
+	if err := s.Save(); err != nil {
+		err := fmt.Errorf("error saving user id onto session: %s", err)
+		apiutil.ErrorHandler(c, gtserror.NewErrorInternalError(err, oauth.HelpfulAdvice), m.processor.InstanceGetV1)
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/oauth"+OauthAuthorizePath)
+}
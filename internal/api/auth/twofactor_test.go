@@ -0,0 +1,153 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/auth"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/totp"
+)
+
+const sessionTwoFactorUserID = "2fa_userid"
+
+type AuthTwoFactorTestSuite struct {
+	AuthStandardTestSuite
+}
+
+// enableTwoFactor enables two-factor auth for
+// the given user, returning the TOTP secret.
+func (suite *AuthTwoFactorTestSuite) enableTwoFactor(user *gtsmodel.User, account *gtsmodel.Account) string {
+	ctx := context.Background()
+
+	user, err := suite.db.GetUserByID(ctx, user.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	setup, errWithCode := suite.processor.User().TwoFactorSetup(ctx, user, account)
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+
+	// Enable with the previous period's code, as codes
+	// can only be used once, and tests will want to sign
+	// in with the current one.
+	code, err := totp.Code(setup.Secret, time.Now().Add(-totp.Period*time.Second))
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	if _, errWithCode := suite.processor.User().TwoFactorEnable(ctx, user, code); errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+
+	return setup.Secret
+}
+
+func (suite *AuthTwoFactorTestSuite) TestSignInWithTwoFactor() {
+	user := suite.testUsers["local_account_1"]
+	suite.enableTwoFactor(user, suite.testAccounts["local_account_1"])
+
+	form := url.Values{
+		"username": {user.Email},
+		"password": {"password"},
+	}
+	ctx, recorder := suite.newContext(http.MethodPost, "auth"+auth.AuthSignInPath, []byte(form.Encode()), "application/x-www-form-urlencoded")
+	suite.authModule.SignInPOSTHandler(ctx)
+
+	// user should be sent to enter their code, and not be signed in yet
+	suite.Equal(http.StatusFound, ctx.Writer.Status())
+	suite.Equal("/auth"+auth.AuthTwoFactorPath, recorder.Header().Get("Location"))
+
+	s := sessions.Default(ctx)
+	suite.Equal(user.ID, s.Get(sessionTwoFactorUserID))
+	suite.Nil(s.Get(sessionUserID))
+}
+
+func (suite *AuthTwoFactorTestSuite) TestTwoFactorPOST() {
+	user := suite.testUsers["local_account_1"]
+	secret := suite.enableTwoFactor(user, suite.testAccounts["local_account_1"])
+
+	code, err := totp.Code(secret, time.Now())
+	suite.NoError(err)
+
+	form := url.Values{"code": {code}}
+	ctx, recorder := suite.newContext(http.MethodPost, "auth"+auth.AuthTwoFactorPath, []byte(form.Encode()), "application/x-www-form-urlencoded")
+	s := sessions.Default(ctx)
+	s.Set(sessionTwoFactorUserID, user.ID)
+
+	suite.authModule.TwoFactorPOSTHandler(ctx)
+
+	// user should now be signed in
+	suite.Equal(http.StatusFound, ctx.Writer.Status())
+	suite.Equal("/oauth"+auth.OauthAuthorizePath, recorder.Header().Get("Location"))
+	suite.Equal(user.ID, s.Get(sessionUserID))
+	suite.Nil(s.Get(sessionTwoFactorUserID))
+}
+
+func (suite *AuthTwoFactorTestSuite) TestTwoFactorPOSTTooManyAttempts() {
+	user := suite.testUsers["local_account_1"]
+	secret := suite.enableTwoFactor(user, suite.testAccounts["local_account_1"])
+
+	// Use up all the attempts for the user.
+	dbUser, err := suite.db.GetUserByID(context.Background(), user.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	dbUser.TwoFactorAttempts = 5
+	dbUser.TwoFactorAttemptedAt = time.Now()
+	if err := suite.db.UpdateUser(context.Background(), dbUser, "two_factor_attempts", "two_factor_attempted_at"); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	code, err := totp.Code(secret, time.Now())
+	suite.NoError(err)
+
+	// A fresh session doesn't reset the count.
+	form := url.Values{"code": {code}}
+	ctx, _ := suite.newContext(http.MethodPost, "auth"+auth.AuthTwoFactorPath, []byte(form.Encode()), "application/x-www-form-urlencoded")
+	s := sessions.Default(ctx)
+	s.Set(sessionTwoFactorUserID, user.ID)
+
+	suite.authModule.TwoFactorPOSTHandler(ctx)
+
+	// user should not be signed in,
+	// even though the code was right
+	suite.Equal(http.StatusTooManyRequests, ctx.Writer.Status())
+	suite.Nil(s.Get(sessionUserID))
+}
+
+func (suite *AuthTwoFactorTestSuite) TestTwoFactorGETNoSession() {
+	ctx, recorder := suite.newContext(http.MethodGet, "auth"+auth.AuthTwoFactorPath, nil, "")
+	suite.authModule.TwoFactorGETHandler(ctx)
+
+	suite.Equal(http.StatusSeeOther, ctx.Writer.Status())
+	suite.Equal("/auth"+auth.AuthSignInPath, recorder.Header().Get("Location"))
+}
+
+func TestAuthTwoFactorTestSuite(t *testing.T) {
+	suite.Run(t, &AuthTwoFactorTestSuite{})
+}
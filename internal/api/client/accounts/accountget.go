@@ -59,6 +59,8 @@ import (
 //			description: bad request
 //		'401':
 //			description: unauthorized
+//		'403':
+//			description: forbidden
 //		'404':
 //			description: not found
 //		'406':
@@ -72,6 +74,11 @@ func (m *Module) AccountGETHandler(c *gin.Context) {
 		return
 	}
 
+	if err := authed.CheckScope(oauth.ScopeReadAccounts); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorForbidden(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
 	if _, err := apiutil.NegotiateAccept(c, apiutil.JSONAcceptHeaders...); err != nil {
 		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
 		return
@@ -126,6 +126,15 @@ import (
 //		type: array
 //		items:
 //			type: object
+//	-
+//		name: also_known_as_uris[]
+//		in: formData
+//		description: >-
+//			URIs of other accounts that this account is also known as (aliases).
+//			An account can only move to this account if its URI is included here.
+//		type: array
+//		items:
+//			type: string
 //
 //	security:
 //	- OAuth2 Bearer:
@@ -140,6 +149,8 @@ import (
 //			description: bad request
 //		'401':
 //			description: unauthorized
+//		'403':
+//			description: forbidden
 //		'404':
 //			description: not found
 //		'406':
@@ -153,6 +164,11 @@ func (m *Module) AccountUpdateCredentialsPATCHHandler(c *gin.Context) {
 		return
 	}
 
+	if err := authed.CheckScope(oauth.ScopeWriteAccounts); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorForbidden(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
 	if _, err := apiutil.NegotiateAccept(c, apiutil.JSONAcceptHeaders...); err != nil {
 		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
 		return
@@ -255,7 +271,8 @@ func parseUpdateAccountForm(c *gin.Context) (*apimodel.UpdateCredentialsRequest,
 			form.Source.StatusContentType == nil &&
 			form.FieldsAttributes == nil &&
 			form.CustomCSS == nil &&
-			form.EnableRSS == nil) {
+			form.EnableRSS == nil &&
+			form.AlsoKnownAsURIs == nil) {
 		return nil, errors.New("empty form submitted")
 	}
 
@@ -0,0 +1,120 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package accounts_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/accounts"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type MuteTestSuite struct {
+	AccountStandardTestSuite
+}
+
+func (suite *MuteTestSuite) postMute(targetAccountID string, form url.Values) (*httptest.ResponseRecorder, *apimodel.Relationship) {
+	testAcct := suite.testAccounts["local_account_1"]
+	recorder := httptest.NewRecorder()
+	ctx, _ := testrig.CreateGinTestContext(recorder, nil)
+	ctx.Set(oauth.SessionAuthorizedAccount, testAcct)
+	ctx.Set(oauth.SessionAuthorizedToken, oauth.DBTokenToToken(suite.testTokens["local_account_1"]))
+	ctx.Set(oauth.SessionAuthorizedApplication, suite.testApplications["application_1"])
+	ctx.Set(oauth.SessionAuthorizedUser, suite.testUsers["local_account_1"])
+	ctx.Request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("http://localhost:8080%s", strings.Replace(accounts.MutePath, ":id", targetAccountID, 1)), strings.NewReader(form.Encode()))
+	ctx.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	ctx.Params = gin.Params{
+		gin.Param{
+			Key:   accounts.IDKey,
+			Value: targetAccountID,
+		},
+	}
+
+	suite.accountsModule.AccountMutePOSTHandler(ctx)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	b, err := io.ReadAll(result.Body)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	if recorder.Code != http.StatusOK {
+		return recorder, nil
+	}
+
+	relationship := &apimodel.Relationship{}
+	if err := json.Unmarshal(b, relationship); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	return recorder, relationship
+}
+
+func (suite *MuteTestSuite) TestMuteSelf() {
+	testAcct := suite.testAccounts["local_account_1"]
+
+	recorder, _ := suite.postMute(testAcct.ID, url.Values{})
+
+	// status should be Not Acceptable due to attempted self-mute
+	suite.Equal(http.StatusNotAcceptable, recorder.Code)
+}
+
+func (suite *MuteTestSuite) TestMute() {
+	targetAcct := suite.testAccounts["local_account_2"]
+
+	recorder, relationship := suite.postMute(targetAcct.ID, url.Values{})
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.True(relationship.Muting)
+	suite.True(relationship.MutingNotifications)
+
+	// Update the mute to leave notifications alone.
+	recorder, relationship = suite.postMute(targetAcct.ID, url.Values{
+		"notifications": []string{"false"},
+		"duration":      []string{"3600"},
+	})
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.True(relationship.Muting)
+	suite.False(relationship.MutingNotifications)
+}
+
+func (suite *MuteTestSuite) TestMuteNegativeDuration() {
+	targetAcct := suite.testAccounts["local_account_2"]
+
+	recorder, _ := suite.postMute(targetAcct.ID, url.Values{
+		"duration": []string{"-1"},
+	})
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+}
+
+func TestMuteTestSuite(t *testing.T) {
+	suite.Run(t, new(MuteTestSuite))
+}
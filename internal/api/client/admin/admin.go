@@ -25,22 +25,32 @@ import (
 )
 
 const (
-	BasePath               = "/v1/admin"
-	EmojiPath              = BasePath + "/custom_emojis"
-	EmojiPathWithID        = EmojiPath + "/:" + IDKey
-	EmojiCategoriesPath    = EmojiPath + "/categories"
-	DomainBlocksPath       = BasePath + "/domain_blocks"
-	DomainBlocksPathWithID = DomainBlocksPath + "/:" + IDKey
-	AccountsPath           = BasePath + "/accounts"
-	AccountsPathWithID     = AccountsPath + "/:" + IDKey
-	AccountsActionPath     = AccountsPathWithID + "/action"
-	MediaCleanupPath       = BasePath + "/media_cleanup"
-	MediaRefetchPath       = BasePath + "/media_refetch"
-	ReportsPath            = BasePath + "/reports"
-	ReportsPathWithID      = ReportsPath + "/:" + IDKey
-	ReportsResolvePath     = ReportsPathWithID + "/resolve"
-	EmailPath              = BasePath + "/email"
-	EmailTestPath          = EmailPath + "/test"
+	BasePath                                = "/v1/admin"
+	EmojiPath                               = BasePath + "/custom_emojis"
+	EmojiPathWithID                         = EmojiPath + "/:" + IDKey
+	EmojiCategoriesPath                     = EmojiPath + "/categories"
+	DomainBlocksPath                        = BasePath + "/domain_blocks"
+	DomainBlocksPathWithID                  = DomainBlocksPath + "/:" + IDKey
+	DomainAllowsPath                        = BasePath + "/domain_allows"
+	DomainAllowsPathWithID                  = DomainAllowsPath + "/:" + IDKey
+	DomainPermissionSubscriptionsPath       = BasePath + "/domain_permission_subscriptions"
+	DomainPermissionSubscriptionsPathWithID = DomainPermissionSubscriptionsPath + "/:" + IDKey
+	DomainPermissionSubscriptionsTestPath   = DomainPermissionSubscriptionsPathWithID + "/test"
+	AccountsPath                            = BasePath + "/accounts"
+	AccountsPathWithID                      = AccountsPath + "/:" + IDKey
+	AccountsActionPath                      = AccountsPathWithID + "/action"
+	AccountsApprovePath                     = AccountsPathWithID + "/approve"
+	AccountsRejectPath                      = AccountsPathWithID + "/reject"
+	MediaCleanupPath                        = BasePath + "/media_cleanup"
+	MediaRefetchPath                        = BasePath + "/media_refetch"
+	ReportsPath                             = BasePath + "/reports"
+	ReportsPathWithID                       = ReportsPath + "/:" + IDKey
+	ReportsResolvePath                      = ReportsPathWithID + "/resolve"
+	EmailPath                               = BasePath + "/email"
+	EmailTestPath                           = EmailPath + "/test"
+	DeliveryQueuePath                       = BasePath + "/delivery_queue"
+	InstanceRulesPath                       = BasePath + "/instance/rules"
+	InstanceRulesPathWithID                 = InstanceRulesPath + "/:" + IDKey
 
 	IDKey                 = "id"
 	FilterQueryKey        = "filter"
@@ -49,6 +59,7 @@ const (
 	LimitKey              = "limit"
 	DomainQueryKey        = "domain"
 	ResolvedKey           = "resolved"
+	StatusKey             = "status"
 	AccountIDKey          = "account_id"
 	TargetAccountIDKey    = "target_account_id"
 	MaxIDKey              = "max_id"
@@ -81,8 +92,25 @@ func (m *Module) Route(attachHandler func(method string, path string, f ...gin.H
 	attachHandler(http.MethodGet, DomainBlocksPathWithID, m.DomainBlockGETHandler)
 	attachHandler(http.MethodDelete, DomainBlocksPathWithID, m.DomainBlockDELETEHandler)
 
+	// domain allow stuff
+	attachHandler(http.MethodPost, DomainAllowsPath, m.DomainAllowsPOSTHandler)
+	attachHandler(http.MethodGet, DomainAllowsPath, m.DomainAllowsGETHandler)
+	attachHandler(http.MethodGet, DomainAllowsPathWithID, m.DomainAllowGETHandler)
+	attachHandler(http.MethodDelete, DomainAllowsPathWithID, m.DomainAllowDELETEHandler)
+
+	// domain permission subscription stuff
+	attachHandler(http.MethodPost, DomainPermissionSubscriptionsPath, m.DomainPermissionSubscriptionPOSTHandler)
+	attachHandler(http.MethodGet, DomainPermissionSubscriptionsPath, m.DomainPermissionSubscriptionsGETHandler)
+	attachHandler(http.MethodGet, DomainPermissionSubscriptionsPathWithID, m.DomainPermissionSubscriptionGETHandler)
+	attachHandler(http.MethodDelete, DomainPermissionSubscriptionsPathWithID, m.DomainPermissionSubscriptionDELETEHandler)
+	attachHandler(http.MethodPost, DomainPermissionSubscriptionsTestPath, m.DomainPermissionSubscriptionTestPOSTHandler)
+
 	// accounts stuff
+	attachHandler(http.MethodGet, AccountsPath, m.AccountsGETHandler)
+	attachHandler(http.MethodGet, AccountsPathWithID, m.AccountGETHandler)
 	attachHandler(http.MethodPost, AccountsActionPath, m.AccountActionPOSTHandler)
+	attachHandler(http.MethodPost, AccountsApprovePath, m.AccountApprovePOSTHandler)
+	attachHandler(http.MethodPost, AccountsRejectPath, m.AccountRejectPOSTHandler)
 
 	// media stuff
 	attachHandler(http.MethodPost, MediaCleanupPath, m.MediaCleanupPOSTHandler)
@@ -95,4 +123,14 @@ func (m *Module) Route(attachHandler func(method string, path string, f ...gin.H
 
 	// email stuff
 	attachHandler(http.MethodPost, EmailTestPath, m.EmailTestPOSTHandler)
+
+	// federation stuff
+	attachHandler(http.MethodGet, DeliveryQueuePath, m.DeliveryQueueGETHandler)
+
+	// instance rules stuff
+	attachHandler(http.MethodGet, InstanceRulesPath, m.RulesGETHandler)
+	attachHandler(http.MethodPost, InstanceRulesPath, m.RulePOSTHandler)
+	attachHandler(http.MethodGet, InstanceRulesPathWithID, m.RuleGETHandler)
+	attachHandler(http.MethodPatch, InstanceRulesPathWithID, m.RulePATCHHandler)
+	attachHandler(http.MethodDelete, InstanceRulesPathWithID, m.RuleDELETEHandler)
 }
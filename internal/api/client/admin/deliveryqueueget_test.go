@@ -0,0 +1,123 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/admin"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type DeliveryQueueGetTestSuite struct {
+	AdminStandardTestSuite
+}
+
+func (suite *DeliveryQueueGetTestSuite) TestDeliveryQueueGet() {
+	var (
+		ctx          = context.Background()
+		now          = time.Now()
+		failingSince = time.Date(2023, 8, 28, 9, 0, 0, 0, time.UTC)
+		retryAt      = now.Add(time.Hour)
+	)
+
+	for _, delivery := range []*gtsmodel.Delivery{
+		{
+			ID:            "01H8XQ1TVDGK2M0P4W7RPJRQ2A",
+			PubKeyID:      "http://localhost:8080/users/the_mighty_zork/main-key",
+			InboxURI:      "https://unknown-instance.com/users/someone/inbox",
+			Domain:        "unknown-instance.com",
+			Data:          []byte(`{}`),
+			Attempts:      3,
+			NextAttemptAt: retryAt,
+		},
+		{
+			ID:            "01H8XQ2B4M7W6ZJ5Q9E1N3T8CV",
+			PubKeyID:      "http://localhost:8080/users/the_mighty_zork/main-key",
+			InboxURI:      "https://fossbros-anonymous.io/users/foss_satan/inbox",
+			Domain:        "fossbros-anonymous.io",
+			Data:          []byte(`{}`),
+			NextAttemptAt: now,
+		},
+	} {
+		if err := suite.db.PutDelivery(ctx, delivery); err != nil {
+			suite.FailNow(err.Error())
+		}
+	}
+
+	if err := suite.db.PutDeliveryFailure(ctx, &gtsmodel.DeliveryFailure{
+		ID:           "01H8XQ3F0R8YV2K6D4H7S9B1MZ",
+		Domain:       "unknown-instance.com",
+		FailingSince: failingSince,
+		Failures:     15,
+		RetryAt:      retryAt,
+		LastError:    "dial tcp: lookup unknown-instance.com: no such host",
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	recorder := httptest.NewRecorder()
+	ginCtx := suite.newContext(recorder, http.MethodGet, nil, admin.DeliveryQueuePath, "")
+
+	suite.adminModule.DeliveryQueueGETHandler(ginCtx)
+	suite.Equal(http.StatusOK, recorder.Code)
+
+	b, err := io.ReadAll(recorder.Body)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	queue := make(map[string]interface{})
+	if err := json.Unmarshal(b, &queue); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.EqualValues(2, queue["queue_depth"])
+
+	failingDomains := queue["failing_domains"].([]interface{})
+	suite.Len(failingDomains, 1)
+
+	failingDomain := failingDomains[0].(map[string]interface{})
+	suite.Equal("unknown-instance.com", failingDomain["domain"])
+	suite.Equal("2023-08-28T09:00:00.000Z", failingDomain["failing_since"])
+	suite.EqualValues(15, failingDomain["failures"])
+	suite.Equal("dial tcp: lookup unknown-instance.com: no such host", failingDomain["last_error"])
+	suite.Equal(true, failingDomain["unreachable"])
+	suite.NotNil(failingDomain["retry_at"])
+	suite.EqualValues(1, failingDomain["queued"])
+}
+
+func (suite *DeliveryQueueGetTestSuite) TestDeliveryQueueGetEmpty() {
+	recorder := httptest.NewRecorder()
+	ginCtx := suite.newContext(recorder, http.MethodGet, nil, admin.DeliveryQueuePath, "")
+
+	suite.adminModule.DeliveryQueueGETHandler(ginCtx)
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.Equal(`{"queue_depth":0,"failing_domains":[]}`, recorder.Body.String())
+}
+
+func TestDeliveryQueueGetTestSuite(t *testing.T) {
+	suite.Run(t, &DeliveryQueueGetTestSuite{})
+}
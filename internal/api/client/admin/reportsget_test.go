@@ -334,7 +334,7 @@ func (suite *ReportsGetTestSuite) TestReportsGetAll() {
       "created_by_application_id": "01F8MGXQRHYF5QPMTMXP78QC2F"
     },
     "statuses": [],
-    "rule_ids": [],
+    "rules": [],
     "action_taken_comment": "user was warned not to be a turtle anymore"
   },
   {
@@ -524,10 +524,11 @@ func (suite *ReportsGetTestSuite) TestReportsGetAll() {
         "tags": [],
         "emojis": [],
         "card": null,
-        "poll": null
+        "poll": null,
+        "edited_at": null
       }
     ],
-    "rule_ids": [],
+    "rules": [],
     "action_taken_comment": null
   }
 ]`, string(b))
@@ -736,10 +737,11 @@ func (suite *ReportsGetTestSuite) TestReportsGetCreatedByAccount() {
         "tags": [],
         "emojis": [],
         "card": null,
-        "poll": null
+        "poll": null,
+        "edited_at": null
       }
     ],
-    "rule_ids": [],
+    "rules": [],
     "action_taken_comment": null
   }
 ]`, string(b))
@@ -948,10 +950,11 @@ func (suite *ReportsGetTestSuite) TestReportsGetTargetAccount() {
         "tags": [],
         "emojis": [],
         "card": null,
-        "poll": null
+        "poll": null,
+        "edited_at": null
       }
     ],
-    "rule_ids": [],
+    "rules": [],
     "action_taken_comment": null
   }
 ]`, string(b))
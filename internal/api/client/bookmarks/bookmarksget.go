@@ -66,6 +66,8 @@ const (
 //					description: Links to the next and previous queries.
 //		'401':
 //			description: unauthorized
+//		'403':
+//			description: forbidden
 //		'406':
 //			description: not acceptable
 //		'500':
@@ -77,6 +79,11 @@ func (m *Module) BookmarksGETHandler(c *gin.Context) {
 		return
 	}
 
+	if err := authed.CheckScope(oauth.ScopeReadBookmarks); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorForbidden(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
 	if _, err := apiutil.NegotiateAccept(c, apiutil.JSONAcceptHeaders...); err != nil {
 		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
 		return
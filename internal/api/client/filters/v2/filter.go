@@ -0,0 +1,70 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package v2
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/processing"
+)
+
+const (
+	IDKey = "id"
+	// BasePath is the base path for serving the v2 filters API, minus the 'api' prefix
+	BasePath           = "/v2/filters"
+	BasePathWithID     = BasePath + "/:" + IDKey
+	KeywordsPathWithID = BasePathWithID + "/keywords"
+	KeywordPath        = BasePath + "/keywords"
+	KeywordPathWithID  = KeywordPath + "/:" + IDKey
+	StatusesPathWithID = BasePathWithID + "/statuses"
+	StatusPath         = BasePath + "/statuses"
+	StatusPathWithID   = StatusPath + "/:" + IDKey
+)
+
+type Module struct {
+	processor *processing.Processor
+}
+
+func New(processor *processing.Processor) *Module {
+	return &Module{
+		processor: processor,
+	}
+}
+
+func (m *Module) Route(attachHandler func(method string, path string, f ...gin.HandlerFunc) gin.IRoutes) {
+	// create / get / update / delete filters
+	attachHandler(http.MethodGet, BasePath, m.FiltersGETHandler)
+	attachHandler(http.MethodPost, BasePath, m.FilterPOSTHandler)
+	attachHandler(http.MethodGet, BasePathWithID, m.FilterGETHandler)
+	attachHandler(http.MethodPut, BasePathWithID, m.FilterPUTHandler)
+	attachHandler(http.MethodDelete, BasePathWithID, m.FilterDELETEHandler)
+
+	// create / get / update / delete filter keywords
+	attachHandler(http.MethodGet, KeywordsPathWithID, m.FilterKeywordsGETHandler)
+	attachHandler(http.MethodPost, KeywordsPathWithID, m.FilterKeywordPOSTHandler)
+	attachHandler(http.MethodGet, KeywordPathWithID, m.FilterKeywordGETHandler)
+	attachHandler(http.MethodPut, KeywordPathWithID, m.FilterKeywordPUTHandler)
+	attachHandler(http.MethodDelete, KeywordPathWithID, m.FilterKeywordDELETEHandler)
+
+	// create / get / delete filter statuses
+	attachHandler(http.MethodGet, StatusesPathWithID, m.FilterStatusesGETHandler)
+	attachHandler(http.MethodPost, StatusesPathWithID, m.FilterStatusPOSTHandler)
+	attachHandler(http.MethodGet, StatusPathWithID, m.FilterStatusGETHandler)
+	attachHandler(http.MethodDelete, StatusPathWithID, m.FilterStatusDELETEHandler)
+}
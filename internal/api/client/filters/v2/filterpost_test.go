@@ -0,0 +1,204 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package v2_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	filtersV2 "github.com/superseriousbusiness/gotosocial/internal/api/client/filters/v2"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+func (suite *FiltersTestSuite) postFilter(form url.Values, jsonBody []byte, expectedHTTPStatus int, expectedBody string) (*apimodel.FilterV2, error) {
+	// instantiate recorder + test context
+	recorder := httptest.NewRecorder()
+	ctx, _ := testrig.CreateGinTestContext(recorder, nil)
+	ctx.Set(oauth.SessionAuthorizedAccount, suite.testAccounts["local_account_1"])
+	ctx.Set(oauth.SessionAuthorizedToken, oauth.DBTokenToToken(suite.testTokens["local_account_1"]))
+	ctx.Set(oauth.SessionAuthorizedApplication, suite.testApplications["application_1"])
+	ctx.Set(oauth.SessionAuthorizedUser, suite.testUsers["local_account_1"])
+
+	// create the request
+	requestURL := config.GetProtocol() + "://" + config.GetHost() + "/api/" + filtersV2.BasePath
+	if jsonBody != nil {
+		ctx.Request = httptest.NewRequest(http.MethodPost, requestURL, bytes.NewReader(jsonBody))
+		ctx.Request.Header.Set("content-type", "application/json")
+	} else {
+		ctx.Request = httptest.NewRequest(http.MethodPost, requestURL, strings.NewReader(form.Encode()))
+		ctx.Request.Header.Set("content-type", "application/x-www-form-urlencoded")
+	}
+	ctx.Request.Header.Set("accept", "application/json")
+
+	// trigger the handler
+	suite.filtersModule.FilterPOSTHandler(ctx)
+
+	// read the response
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	b, err := io.ReadAll(result.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := gtserror.NewMultiError(2)
+
+	// check code + body
+	if resultCode := recorder.Code; expectedHTTPStatus != resultCode {
+		errs.Appendf("expected %d got %d", expectedHTTPStatus, resultCode)
+	}
+
+	// if we got an expected body, return early
+	if expectedBody != "" {
+		if string(b) != expectedBody {
+			errs.Appendf("expected %s got %s", expectedBody, string(b))
+		}
+		return nil, errs.Combine()
+	}
+
+	if err := errs.Combine(); err != nil {
+		return nil, err
+	}
+
+	resp := &apimodel.FilterV2{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (suite *FiltersTestSuite) TestPostFilterForm() {
+	form := url.Values{
+		"title":                             {"illuminati nonsense"},
+		"context[]":                         {"home", "public"},
+		"filter_action":                     {"hide"},
+		"keywords_attributes[][keyword]":    {"fnord", "illuminati"},
+		"keywords_attributes[][whole_word]": {"true", "false"},
+		"statuses_attributes[][status_id]":  {suite.testStatuses["admin_account_status_1"].ID},
+	}
+
+	filter, err := suite.postFilter(form, nil, http.StatusOK, "")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal("illuminati nonsense", filter.Title)
+	suite.Equal(apimodel.FilterActionHide, filter.FilterAction)
+	suite.Nil(filter.ExpiresAt)
+	suite.ElementsMatch(
+		[]apimodel.FilterContext{
+			apimodel.FilterContextHome,
+			apimodel.FilterContextPublic,
+		},
+		filter.Context,
+	)
+
+	if suite.Len(filter.Keywords, 2) {
+		keywords := map[string]bool{}
+		for _, keyword := range filter.Keywords {
+			keywords[keyword.Keyword] = keyword.WholeWord
+		}
+		suite.Equal(map[string]bool{"fnord": true, "illuminati": false}, keywords)
+	}
+
+	if suite.Len(filter.Statuses, 1) {
+		suite.Equal(suite.testStatuses["admin_account_status_1"].ID, filter.Statuses[0].StatusID)
+	}
+}
+
+func (suite *FiltersTestSuite) TestPostFilterJSON() {
+	body := []byte(`{
+		"title": "illuminati nonsense",
+		"context": ["thread"],
+		"expires_in": 86400,
+		"keywords_attributes": [
+			{"keyword": "fnord", "whole_word": true}
+		]
+	}`)
+
+	filter, err := suite.postFilter(nil, body, http.StatusOK, "")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal("illuminati nonsense", filter.Title)
+	suite.Equal(apimodel.FilterActionWarn, filter.FilterAction)
+	suite.NotNil(filter.ExpiresAt)
+	suite.ElementsMatch([]apimodel.FilterContext{apimodel.FilterContextThread}, filter.Context)
+	if suite.Len(filter.Keywords, 1) {
+		suite.Equal("fnord", filter.Keywords[0].Keyword)
+		suite.True(filter.Keywords[0].WholeWord)
+	}
+	suite.Empty(filter.Statuses)
+}
+
+func (suite *FiltersTestSuite) TestPostFilterDuplicateKeyword() {
+	form := url.Values{
+		"title":                          {"illuminati nonsense"},
+		"context[]":                      {"home"},
+		"keywords_attributes[][keyword]": {"fnord", "fnord"},
+	}
+
+	_, err := suite.postFilter(form, nil, http.StatusConflict, `{"error":"Conflict: duplicate filter keyword or status"}`)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+}
+
+func (suite *FiltersTestSuite) TestPostFilterUnknownStatus() {
+	form := url.Values{
+		"title":                            {"illuminati nonsense"},
+		"context[]":                        {"home"},
+		"statuses_attributes[][status_id]": {"01HB5ZEBKQK6VEBBY3FCX2Y2QY"},
+	}
+
+	_, err := suite.postFilter(form, nil, http.StatusNotFound, `{"error":"Not Found"}`)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+}
+
+func (suite *FiltersTestSuite) TestPostFilterBadAction() {
+	form := url.Values{
+		"title":         {"illuminati nonsense"},
+		"context[]":     {"home"},
+		"filter_action": {"shout"},
+	}
+
+	_, err := suite.postFilter(form, nil, http.StatusBadRequest, `{"error":"Bad Request: filter action 'shout' was not recognized, valid options are 'warn', 'hide'"}`)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+}
+
+func TestFiltersTestSuite(t *testing.T) {
+	suite.Run(t, new(FiltersTestSuite))
+}
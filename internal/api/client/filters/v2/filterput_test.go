@@ -0,0 +1,168 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package v2_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	filtersV2 "github.com/superseriousbusiness/gotosocial/internal/api/client/filters/v2"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+func (suite *FiltersTestSuite) putFilter(filterID string, form url.Values, expectedHTTPStatus int, expectedBody string) (*apimodel.FilterV2, error) {
+	// instantiate recorder + test context
+	recorder := httptest.NewRecorder()
+	ctx, _ := testrig.CreateGinTestContext(recorder, nil)
+	ctx.Set(oauth.SessionAuthorizedAccount, suite.testAccounts["local_account_1"])
+	ctx.Set(oauth.SessionAuthorizedToken, oauth.DBTokenToToken(suite.testTokens["local_account_1"]))
+	ctx.Set(oauth.SessionAuthorizedApplication, suite.testApplications["application_1"])
+	ctx.Set(oauth.SessionAuthorizedUser, suite.testUsers["local_account_1"])
+
+	// create the request
+	ctx.Request = httptest.NewRequest(http.MethodPut, config.GetProtocol()+"://"+config.GetHost()+"/api/"+filtersV2.BasePath+"/"+filterID, strings.NewReader(form.Encode()))
+	ctx.Request.Header.Set("accept", "application/json")
+	ctx.Request.Header.Set("content-type", "application/x-www-form-urlencoded")
+	ctx.AddParam("id", filterID)
+
+	// trigger the handler
+	suite.filtersModule.FilterPUTHandler(ctx)
+
+	// read the response
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	b, err := io.ReadAll(result.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := gtserror.NewMultiError(2)
+
+	// check code + body
+	if resultCode := recorder.Code; expectedHTTPStatus != resultCode {
+		errs.Appendf("expected %d got %d", expectedHTTPStatus, resultCode)
+	}
+
+	// if we got an expected body, return early
+	if expectedBody != "" {
+		if string(b) != expectedBody {
+			errs.Appendf("expected %s got %s", expectedBody, string(b))
+		}
+		return nil, errs.Combine()
+	}
+
+	if err := errs.Combine(); err != nil {
+		return nil, err
+	}
+
+	resp := &apimodel.FilterV2{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (suite *FiltersTestSuite) TestPutFilterTitleAndAction() {
+	id := suite.testFilters["local_account_1_filter_1"].ID
+	form := url.Values{
+		"title":         {"new title"},
+		"filter_action": {"hide"},
+	}
+
+	filter, err := suite.putFilter(id, form, http.StatusOK, "")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal("new title", filter.Title)
+	suite.Equal(apimodel.FilterActionHide, filter.FilterAction)
+
+	// Untouched fields should be unchanged.
+	suite.ElementsMatch(
+		[]apimodel.FilterContext{
+			apimodel.FilterContextHome,
+			apimodel.FilterContextPublic,
+			apimodel.FilterContextThread,
+		},
+		filter.Context,
+	)
+	suite.Len(filter.Keywords, 1)
+	suite.Len(filter.Statuses, 1)
+}
+
+func (suite *FiltersTestSuite) TestPutFilterKeywordsAndStatuses() {
+	var (
+		id             = suite.testFilters["local_account_1_filter_1"].ID
+		keywordID      = suite.testFilterKeywords["local_account_1_filter_1_keyword_1"].ID
+		filterStatusID = suite.testFilterStatuses["local_account_1_filter_1_status_1"].ID
+	)
+
+	form := url.Values{
+		// Update the existing keyword, and add a new one.
+		"keywords_attributes[][id]":         {keywordID, ""},
+		"keywords_attributes[][keyword]":    {"fnords", "illuminati"},
+		"keywords_attributes[][whole_word]": {"false", "true"},
+		// Delete the existing status.
+		"statuses_attributes[][id]":       {filterStatusID},
+		"statuses_attributes[][_destroy]": {"true"},
+	}
+
+	filter, err := suite.putFilter(id, form, http.StatusOK, "")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	if suite.Len(filter.Keywords, 2) {
+		keywords := map[string]bool{}
+		for _, keyword := range filter.Keywords {
+			keywords[keyword.Keyword] = keyword.WholeWord
+		}
+		suite.Equal(map[string]bool{"fnords": false, "illuminati": true}, keywords)
+	}
+	suite.Empty(filter.Statuses)
+}
+
+func (suite *FiltersTestSuite) TestPutFilterNothingToUpdate() {
+	id := suite.testFilters["local_account_1_filter_1"].ID
+
+	_, err := suite.putFilter(id, url.Values{}, http.StatusBadRequest, `{"error":"Bad Request: no fields set; nothing to update"}`)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+}
+
+func (suite *FiltersTestSuite) TestPutNonexistentFilter() {
+	form := url.Values{
+		"title": {"new title"},
+	}
+
+	_, err := suite.putFilter("01HB5ZEBKQK6VEBBY3FCX2Y2QY", form, http.StatusNotFound, `{"error":"Not Found"}`)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+}
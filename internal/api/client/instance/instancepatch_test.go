@@ -102,7 +102,15 @@ func (suite *InstancePatchTestSuite) TestInstancePatch1() {
         "image/gif",
         "image/png",
         "image/webp",
-        "video/mp4"
+        "image/heif",
+        "image/avif",
+        "video/mp4",
+        "video/quicktime",
+        "video/webm",
+        "audio/mpeg",
+        "audio/ogg",
+        "audio/x-flac",
+        "audio/x-wav"
       ],
       "image_size_limit": 10485760,
       "image_matrix_limit": 16777216,
@@ -160,7 +168,17 @@ func (suite *InstancePatchTestSuite) TestInstancePatch1() {
       "name": "admin"
     }
   },
-  "max_toot_chars": 5000
+  "max_toot_chars": 5000,
+  "rules": [
+    {
+      "id": "01HA0VEZ3P6Z8T4E1XW8B3YJ5K",
+      "text": "Be nice to each other."
+    },
+    {
+      "id": "01HA0VF8N2JQ6B5V4D7ZC9M1RT",
+      "text": "No spam or advertising."
+    }
+  ]
 }`, dst.String())
 }
 
@@ -206,7 +224,15 @@ func (suite *InstancePatchTestSuite) TestInstancePatch2() {
         "image/gif",
         "image/png",
         "image/webp",
-        "video/mp4"
+        "image/heif",
+        "image/avif",
+        "video/mp4",
+        "video/quicktime",
+        "video/webm",
+        "audio/mpeg",
+        "audio/ogg",
+        "audio/x-flac",
+        "audio/x-wav"
       ],
       "image_size_limit": 10485760,
       "image_matrix_limit": 16777216,
@@ -264,7 +290,17 @@ func (suite *InstancePatchTestSuite) TestInstancePatch2() {
       "name": "admin"
     }
   },
-  "max_toot_chars": 5000
+  "max_toot_chars": 5000,
+  "rules": [
+    {
+      "id": "01HA0VEZ3P6Z8T4E1XW8B3YJ5K",
+      "text": "Be nice to each other."
+    },
+    {
+      "id": "01HA0VF8N2JQ6B5V4D7ZC9M1RT",
+      "text": "No spam or advertising."
+    }
+  ]
 }`, dst.String())
 }
 
@@ -310,7 +346,15 @@ func (suite *InstancePatchTestSuite) TestInstancePatch3() {
         "image/gif",
         "image/png",
         "image/webp",
-        "video/mp4"
+        "image/heif",
+        "image/avif",
+        "video/mp4",
+        "video/quicktime",
+        "video/webm",
+        "audio/mpeg",
+        "audio/ogg",
+        "audio/x-flac",
+        "audio/x-wav"
       ],
       "image_size_limit": 10485760,
       "image_matrix_limit": 16777216,
@@ -368,7 +412,17 @@ func (suite *InstancePatchTestSuite) TestInstancePatch3() {
       "name": "admin"
     }
   },
-  "max_toot_chars": 5000
+  "max_toot_chars": 5000,
+  "rules": [
+    {
+      "id": "01HA0VEZ3P6Z8T4E1XW8B3YJ5K",
+      "text": "Be nice to each other."
+    },
+    {
+      "id": "01HA0VF8N2JQ6B5V4D7ZC9M1RT",
+      "text": "No spam or advertising."
+    }
+  ]
 }`, dst.String())
 }
 
@@ -465,7 +519,15 @@ func (suite *InstancePatchTestSuite) TestInstancePatch6() {
         "image/gif",
         "image/png",
         "image/webp",
-        "video/mp4"
+        "image/heif",
+        "image/avif",
+        "video/mp4",
+        "video/quicktime",
+        "video/webm",
+        "audio/mpeg",
+        "audio/ogg",
+        "audio/x-flac",
+        "audio/x-wav"
       ],
       "image_size_limit": 10485760,
       "image_matrix_limit": 16777216,
@@ -523,7 +585,17 @@ func (suite *InstancePatchTestSuite) TestInstancePatch6() {
       "name": "admin"
     }
   },
-  "max_toot_chars": 5000
+  "max_toot_chars": 5000,
+  "rules": [
+    {
+      "id": "01HA0VEZ3P6Z8T4E1XW8B3YJ5K",
+      "text": "Be nice to each other."
+    },
+    {
+      "id": "01HA0VF8N2JQ6B5V4D7ZC9M1RT",
+      "text": "No spam or advertising."
+    }
+  ]
 }`, dst.String())
 }
 
@@ -591,7 +663,15 @@ func (suite *InstancePatchTestSuite) TestInstancePatch8() {
         "image/gif",
         "image/png",
         "image/webp",
-        "video/mp4"
+        "image/heif",
+        "image/avif",
+        "video/mp4",
+        "video/quicktime",
+        "video/webm",
+        "audio/mpeg",
+        "audio/ogg",
+        "audio/x-flac",
+        "audio/x-wav"
       ],
       "image_size_limit": 10485760,
       "image_matrix_limit": 16777216,
@@ -651,7 +731,17 @@ func (suite *InstancePatchTestSuite) TestInstancePatch8() {
       "name": "admin"
     }
   },
-  "max_toot_chars": 5000
+  "max_toot_chars": 5000,
+  "rules": [
+    {
+      "id": "01HA0VEZ3P6Z8T4E1XW8B3YJ5K",
+      "text": "Be nice to each other."
+    },
+    {
+      "id": "01HA0VF8N2JQ6B5V4D7ZC9M1RT",
+      "text": "No spam or advertising."
+    }
+  ]
 }`, dst.String())
 
 	// extra bonus: check the v2 model thumbnail after the patch
@@ -732,7 +822,15 @@ func (suite *InstancePatchTestSuite) TestInstancePatch9() {
         "image/gif",
         "image/png",
         "image/webp",
-        "video/mp4"
+        "image/heif",
+        "image/avif",
+        "video/mp4",
+        "video/quicktime",
+        "video/webm",
+        "audio/mpeg",
+        "audio/ogg",
+        "audio/x-flac",
+        "audio/x-wav"
       ],
       "image_size_limit": 10485760,
       "image_matrix_limit": 16777216,
@@ -790,7 +888,17 @@ func (suite *InstancePatchTestSuite) TestInstancePatch9() {
       "name": "admin"
     }
   },
-  "max_toot_chars": 5000
+  "max_toot_chars": 5000,
+  "rules": [
+    {
+      "id": "01HA0VEZ3P6Z8T4E1XW8B3YJ5K",
+      "text": "Be nice to each other."
+    },
+    {
+      "id": "01HA0VF8N2JQ6B5V4D7ZC9M1RT",
+      "text": "No spam or advertising."
+    }
+  ]
 }`, dst.String())
 }
 
@@ -0,0 +1,180 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package invites_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/invites"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	apiutil "github.com/superseriousbusiness/gotosocial/internal/api/util"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type InviteTestSuite struct {
+	InvitesStandardTestSuite
+}
+
+// inviteRequest performs a request against the given
+// invites handler as local_account_1, with the given
+// invite ID (if any) and form body (if any).
+func (suite *InviteTestSuite) inviteRequest(
+	method string,
+	handler gin.HandlerFunc,
+	id string,
+	form url.Values,
+	expectedHTTPStatus int,
+) []byte {
+	var (
+		recorder = httptest.NewRecorder()
+		ctx, _   = testrig.CreateGinTestContext(recorder, nil)
+	)
+
+	ctx.Set(oauth.SessionAuthorizedAccount, suite.testAccounts["local_account_1"])
+	ctx.Set(oauth.SessionAuthorizedToken, oauth.DBTokenToToken(suite.testTokens["local_account_1"]))
+	ctx.Set(oauth.SessionAuthorizedApplication, suite.testApplications["application_1"])
+	ctx.Set(oauth.SessionAuthorizedUser, suite.testUsers["local_account_1"])
+
+	requestPath := config.GetProtocol() + "://" + config.GetHost() + "/api" + invites.BasePath
+	if id != "" {
+		requestPath += "/" + id
+		ctx.AddParam(apiutil.IDKey, id)
+	}
+
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	ctx.Request = httptest.NewRequest(method, requestPath, body)
+	ctx.Request.Header.Set("accept", "application/json")
+	if form != nil {
+		ctx.Request.Header.Set("content-type", "application/x-www-form-urlencoded")
+	}
+
+	handler(ctx)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	b, err := io.ReadAll(result.Body)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal(expectedHTTPStatus, recorder.Code, string(b))
+	return b
+}
+
+func (suite *InviteTestSuite) TestGetInvites() {
+	b := suite.inviteRequest(
+		http.MethodGet,
+		suite.invitesModule.InvitesGETHandler,
+		"",
+		nil,
+		http.StatusOK,
+	)
+
+	apiInvites := []*apimodel.Invite{}
+	if err := json.Unmarshal(b, &apiInvites); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Len(apiInvites, 1)
+	suite.Equal(suite.testInvites["local_account_1_invite_1"].ID, apiInvites[0].ID)
+	suite.Equal("Yx1bQ9cZ", apiInvites[0].Code)
+	suite.True(apiInvites[0].Expired)
+}
+
+func (suite *InviteTestSuite) TestCreateInviteNotAllowed() {
+	suite.inviteRequest(
+		http.MethodPost,
+		suite.invitesModule.InviteCreatePOSTHandler,
+		"",
+		url.Values{"max_uses": {"10"}},
+		http.StatusForbidden,
+	)
+}
+
+func (suite *InviteTestSuite) TestCreateAndRevokeInvite() {
+	config.SetAccountsAllowInvites(true)
+
+	b := suite.inviteRequest(
+		http.MethodPost,
+		suite.invitesModule.InviteCreatePOSTHandler,
+		"",
+		url.Values{
+			"max_uses":   {"10"},
+			"expires_in": {"86400"},
+			"autofollow": {"true"},
+		},
+		http.StatusOK,
+	)
+
+	invite := &apimodel.Invite{}
+	if err := json.Unmarshal(b, invite); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal(10, *invite.MaxUses)
+	suite.Equal(0, invite.Uses)
+	suite.NotNil(invite.ExpiresAt)
+	suite.True(invite.Autofollow)
+	suite.False(invite.Expired)
+	suite.Equal("http://localhost:8080/auth/sign_up?invite="+invite.Code, invite.URL)
+
+	b = suite.inviteRequest(
+		http.MethodDelete,
+		suite.invitesModule.InviteDELETEHandler,
+		invite.ID,
+		nil,
+		http.StatusOK,
+	)
+
+	revoked := &apimodel.Invite{}
+	if err := json.Unmarshal(b, revoked); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal(invite.ID, revoked.ID)
+	suite.True(revoked.Expired)
+}
+
+func (suite *InviteTestSuite) TestRevokeOtherUsersInvite() {
+	suite.inviteRequest(
+		http.MethodDelete,
+		suite.invitesModule.InviteDELETEHandler,
+		suite.testInvites["admin_account_invite_1"].ID,
+		nil,
+		http.StatusNotFound,
+	)
+}
+
+func TestInviteTestSuite(t *testing.T) {
+	suite.Run(t, &InviteTestSuite{})
+}
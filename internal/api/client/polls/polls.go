@@ -14,8 +14,7 @@
 //
 // You should have received a copy of the GNU Affero General Public License
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
-
-package filter
+package polls
 
 import (
 	"net/http"
@@ -25,8 +24,10 @@ import (
 )
 
 const (
-	// BasePath is the base path for serving the filters API, minus the 'api' prefix
-	BasePath = "/v1/filters"
+	IDKey         = "id"
+	BasePath      = "/v1/polls"
+	PollWithID    = BasePath + "/:" + IDKey
+	PollVotesPath = PollWithID + "/votes"
 )
 
 type Module struct {
@@ -40,5 +41,6 @@ func New(processor *processing.Processor) *Module {
 }
 
 func (m *Module) Route(attachHandler func(method string, path string, f ...gin.HandlerFunc) gin.IRoutes) {
-	attachHandler(http.MethodGet, BasePath, m.FiltersGETHandler)
+	attachHandler(http.MethodGet, PollWithID, m.PollGETHandler)
+	attachHandler(http.MethodPost, PollVotesPath, m.PollVotePOSTHandler)
 }
@@ -0,0 +1,349 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package push_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/push"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type PushSubscriptionTestSuite struct {
+	PushStandardTestSuite
+}
+
+// userAgentKeys returns a new base64url-encoded p256dh
+// public key and auth secret, as a browser would create.
+func userAgentKeys() (string, string) {
+	private, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	auth := make([]byte, 16)
+	if _, err := rand.Read(auth); err != nil {
+		panic(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(private.PublicKey().Bytes()),
+		base64.RawURLEncoding.EncodeToString(auth)
+}
+
+// pushSubscription performs a request against the push subscription
+// endpoint with the given method, handler, and body + content type,
+// as local_account_1 using the given token scope.
+func (suite *PushSubscriptionTestSuite) pushSubscription(
+	method string,
+	handler gin.HandlerFunc,
+	scope string,
+	body io.Reader,
+	contentType string,
+	expectedHTTPStatus int,
+) (*apimodel.WebPushSubscription, string) {
+	var (
+		recorder = httptest.NewRecorder()
+		ctx, _   = testrig.CreateGinTestContext(recorder, nil)
+	)
+
+	token := *suite.testTokens["local_account_1"]
+	if scope != "" {
+		token.Scope = scope
+	}
+
+	ctx.Set(oauth.SessionAuthorizedAccount, suite.testAccounts["local_account_1"])
+	ctx.Set(oauth.SessionAuthorizedToken, oauth.DBTokenToToken(&token))
+	ctx.Set(oauth.SessionAuthorizedApplication, suite.testApplications["application_1"])
+	ctx.Set(oauth.SessionAuthorizedUser, suite.testUsers["local_account_1"])
+
+	requestPath := config.GetProtocol() + "://" + config.GetHost() + "/api" + push.SubscriptionPath
+	ctx.Request = httptest.NewRequest(method, requestPath, body)
+	ctx.Request.Header.Set("accept", "application/json")
+	if contentType != "" {
+		ctx.Request.Header.Set("content-type", contentType)
+	}
+
+	handler(ctx)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	b, err := io.ReadAll(result.Body)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal(expectedHTTPStatus, recorder.Code, string(b))
+	if recorder.Code != http.StatusOK || method == http.MethodDelete {
+		return nil, string(b)
+	}
+
+	subscription := &apimodel.WebPushSubscription{}
+	if err := json.Unmarshal(b, subscription); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	return subscription, string(b)
+}
+
+func (suite *PushSubscriptionTestSuite) createForm(p256dh string, auth string) io.Reader {
+	return strings.NewReader(url.Values{
+		"subscription[endpoint]":       {"https://push.example.org/push/abcdef"},
+		"subscription[keys][p256dh]":   {p256dh},
+		"subscription[keys][auth]":     {auth},
+		"data[alerts][mention]":        {"true"},
+		"data[alerts][follow_request]": {"true"},
+		"data[policy]":                 {"followed"},
+	}.Encode())
+}
+
+func (suite *PushSubscriptionTestSuite) TestCreateForm() {
+	p256dh, auth := userAgentKeys()
+
+	subscription, _ := suite.pushSubscription(
+		http.MethodPost,
+		suite.pushModule.PushSubscriptionPOSTHandler,
+		"",
+		suite.createForm(p256dh, auth),
+		"application/x-www-form-urlencoded",
+		http.StatusOK,
+	)
+
+	suite.NotEmpty(subscription.ID)
+	suite.NotEmpty(subscription.ServerKey)
+	suite.Equal("https://push.example.org/push/abcdef", subscription.Endpoint)
+	suite.Equal("followed", subscription.Policy)
+	suite.Equal(apimodel.WebPushSubscriptionAlerts{
+		Mention:       true,
+		FollowRequest: true,
+	}, subscription.Alerts)
+
+	// Server key should be the same as
+	// the one advertised by the instance.
+	instance, errWithCode := suite.processor.InstanceGetV2(context.Background())
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+	suite.Equal(instance.Configuration.VAPID.PublicKey, subscription.ServerKey)
+
+	// Should be able to get it back.
+	got, _ := suite.pushSubscription(
+		http.MethodGet,
+		suite.pushModule.PushSubscriptionGETHandler,
+		"",
+		nil,
+		"",
+		http.StatusOK,
+	)
+	suite.Equal(subscription, got)
+}
+
+func (suite *PushSubscriptionTestSuite) TestCreateReplaceJSON() {
+	p256dh, auth := userAgentKeys()
+
+	first, _ := suite.pushSubscription(
+		http.MethodPost,
+		suite.pushModule.PushSubscriptionPOSTHandler,
+		"",
+		suite.createForm(p256dh, auth),
+		"application/x-www-form-urlencoded",
+		http.StatusOK,
+	)
+
+	// Create another subscription for the same
+	// token; this should replace the first one.
+	body, err := json.Marshal(map[string]interface{}{
+		"subscription": map[string]interface{}{
+			"endpoint": "https://push.example.org/push/ghijkl",
+			"keys": map[string]string{
+				"p256dh": p256dh,
+				"auth":   auth,
+			},
+		},
+		"data": map[string]interface{}{
+			"alerts": map[string]bool{
+				"favourite":     true,
+				"admin.sign_up": true,
+			},
+		},
+	})
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	second, _ := suite.pushSubscription(
+		http.MethodPost,
+		suite.pushModule.PushSubscriptionPOSTHandler,
+		"",
+		bytes.NewReader(body),
+		"application/json",
+		http.StatusOK,
+	)
+
+	suite.NotEqual(first.ID, second.ID)
+	suite.Equal("https://push.example.org/push/ghijkl", second.Endpoint)
+	suite.Equal("all", second.Policy)
+	suite.Equal(apimodel.WebPushSubscriptionAlerts{
+		Favourite:   true,
+		AdminSignup: true,
+	}, second.Alerts)
+
+	got, _ := suite.pushSubscription(
+		http.MethodGet,
+		suite.pushModule.PushSubscriptionGETHandler,
+		"",
+		nil,
+		"",
+		http.StatusOK,
+	)
+	suite.Equal(second, got)
+}
+
+func (suite *PushSubscriptionTestSuite) TestCreateBadKeys() {
+	_, auth := userAgentKeys()
+
+	_, body := suite.pushSubscription(
+		http.MethodPost,
+		suite.pushModule.PushSubscriptionPOSTHandler,
+		"",
+		suite.createForm("not a key", auth),
+		"application/x-www-form-urlencoded",
+		http.StatusBadRequest,
+	)
+	suite.Contains(body, "invalid subscription keys")
+}
+
+func (suite *PushSubscriptionTestSuite) TestCreateInsufficientScope() {
+	p256dh, auth := userAgentKeys()
+
+	_, body := suite.pushSubscription(
+		http.MethodPost,
+		suite.pushModule.PushSubscriptionPOSTHandler,
+		"read write",
+		suite.createForm(p256dh, auth),
+		"application/x-www-form-urlencoded",
+		http.StatusForbidden,
+	)
+	suite.Equal(`{"error":"Forbidden: token scope \"read write\" does not permit push"}`, body)
+}
+
+func (suite *PushSubscriptionTestSuite) TestUpdate() {
+	p256dh, auth := userAgentKeys()
+
+	created, _ := suite.pushSubscription(
+		http.MethodPost,
+		suite.pushModule.PushSubscriptionPOSTHandler,
+		"",
+		suite.createForm(p256dh, auth),
+		"application/x-www-form-urlencoded",
+		http.StatusOK,
+	)
+
+	// Turn off mentions, turn on reblogs,
+	// leave follow requests alone.
+	updated, _ := suite.pushSubscription(
+		http.MethodPut,
+		suite.pushModule.PushSubscriptionPUTHandler,
+		"",
+		strings.NewReader(`{"data":{"alerts":{"mention":false,"reblog":true},"policy":"none"}}`),
+		"application/json",
+		http.StatusOK,
+	)
+
+	suite.Equal(created.ID, updated.ID)
+	suite.Equal(created.Endpoint, updated.Endpoint)
+	suite.Equal("none", updated.Policy)
+	suite.Equal(apimodel.WebPushSubscriptionAlerts{
+		FollowRequest: true,
+		Reblog:        true,
+	}, updated.Alerts)
+
+	// Bad policy should be rejected.
+	_, body := suite.pushSubscription(
+		http.MethodPut,
+		suite.pushModule.PushSubscriptionPUTHandler,
+		"",
+		strings.NewReader(`{"data":{"policy":"everyone"}}`),
+		"application/json",
+		http.StatusBadRequest,
+	)
+	suite.Equal(`{"error":"Bad Request: policy \"everyone\" not recognized, must be one of all, followed, follower, none"}`, body)
+}
+
+func (suite *PushSubscriptionTestSuite) TestDelete() {
+	p256dh, auth := userAgentKeys()
+
+	suite.pushSubscription(
+		http.MethodPost,
+		suite.pushModule.PushSubscriptionPOSTHandler,
+		"",
+		suite.createForm(p256dh, auth),
+		"application/x-www-form-urlencoded",
+		http.StatusOK,
+	)
+
+	suite.pushSubscription(
+		http.MethodDelete,
+		suite.pushModule.PushSubscriptionDELETEHandler,
+		"",
+		nil,
+		"",
+		http.StatusOK,
+	)
+
+	// Should be gone now.
+	_, body := suite.pushSubscription(
+		http.MethodGet,
+		suite.pushModule.PushSubscriptionGETHandler,
+		"",
+		nil,
+		"",
+		http.StatusNotFound,
+	)
+	suite.Equal(`{"error":"Not Found"}`, body)
+
+	// Deleting again is fine.
+	suite.pushSubscription(
+		http.MethodDelete,
+		suite.pushModule.PushSubscriptionDELETEHandler,
+		"",
+		nil,
+		"",
+		http.StatusOK,
+	)
+}
+
+func TestPushSubscriptionTestSuite(t *testing.T) {
+	suite.Run(t, new(PushSubscriptionTestSuite))
+}
@@ -51,17 +51,13 @@ func (suite *ReportCreateTestSuite) createReport(expectedHTTPStatus int, expecte
 	// create the request
 	ctx.Request = httptest.NewRequest(http.MethodPost, config.GetProtocol()+"://"+config.GetHost()+"/api/"+reports.BasePath, nil)
 	ctx.Request.Header.Set("accept", "application/json")
-	ruleIDs := make([]string, 0, len(form.RuleIDs))
-	for _, r := range form.RuleIDs {
-		ruleIDs = append(ruleIDs, strconv.Itoa(r))
-	}
 	ctx.Request.Form = url.Values{
 		"account_id":   {form.AccountID},
 		"status_ids[]": form.StatusIDs,
 		"comment":      {form.Comment},
 		"forward":      {strconv.FormatBool(form.Forward)},
 		"category":     {form.Category},
-		"rule_ids[]":   ruleIDs,
+		"rule_ids[]":   form.RuleIDs,
 	}
 
 	// trigger the handler
@@ -194,6 +190,41 @@ func (suite *ReportCreateTestSuite) TestCreateReport7() {
 	suite.Nil(report)
 }
 
+func (suite *ReportCreateTestSuite) TestCreateReportWithRules() {
+	targetAccount := suite.testAccounts["remote_account_1"]
+	rule1 := suite.testRules["rule_1"]
+	rule2 := suite.testRules["rule_2"]
+
+	form := &apimodel.ReportCreateRequest{
+		AccountID: targetAccount.ID,
+		StatusIDs: []string{},
+		Comment:   "this is spam, and rude",
+		Forward:   false,
+		RuleIDs:   []string{rule1.ID, rule2.ID},
+	}
+
+	report, err := suite.createReport(http.StatusOK, "", form)
+	suite.NoError(err)
+	suite.NotEmpty(report)
+	suite.ReportOK(form, report)
+	suite.Equal(form.RuleIDs, report.RuleIDs)
+	suite.Equal("violation", report.Category)
+}
+
+func (suite *ReportCreateTestSuite) TestCreateReportDeletedRule() {
+	targetAccount := suite.testAccounts["remote_account_1"]
+	rule := suite.testRules["rule_deleted"]
+
+	form := &apimodel.ReportCreateRequest{
+		AccountID: targetAccount.ID,
+		RuleIDs:   []string{rule.ID},
+	}
+
+	report, err := suite.createReport(http.StatusBadRequest, `{"error":"Bad Request: rule with ID 01HA0VFJ6YHK2W3S8P1X4QG0EA does not exist"}`, form)
+	suite.NoError(err)
+	suite.Nil(report)
+}
+
 func TestReportCreateTestSuite(t *testing.T) {
 	suite.Run(t, &ReportCreateTestSuite{})
 }
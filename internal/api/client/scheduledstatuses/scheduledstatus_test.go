@@ -0,0 +1,208 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scheduledstatuses_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/scheduledstatuses"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	apiutil "github.com/superseriousbusiness/gotosocial/internal/api/util"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type ScheduledStatusTestSuite struct {
+	ScheduledStatusesStandardTestSuite
+}
+
+// scheduledStatusRequest performs a request against the given
+// scheduled statuses handler as local_account_1, with the given
+// scheduled status ID (if any) and form body (if any).
+func (suite *ScheduledStatusTestSuite) scheduledStatusRequest(
+	method string,
+	handler gin.HandlerFunc,
+	id string,
+	form url.Values,
+	expectedHTTPStatus int,
+) []byte {
+	var (
+		recorder = httptest.NewRecorder()
+		ctx, _   = testrig.CreateGinTestContext(recorder, nil)
+	)
+
+	ctx.Set(oauth.SessionAuthorizedAccount, suite.testAccounts["local_account_1"])
+	ctx.Set(oauth.SessionAuthorizedToken, oauth.DBTokenToToken(suite.testTokens["local_account_1"]))
+	ctx.Set(oauth.SessionAuthorizedApplication, suite.testApplications["application_1"])
+	ctx.Set(oauth.SessionAuthorizedUser, suite.testUsers["local_account_1"])
+
+	requestPath := config.GetProtocol() + "://" + config.GetHost() + "/api" + scheduledstatuses.BasePath
+	if id != "" {
+		requestPath += "/" + id
+		ctx.AddParam(apiutil.IDKey, id)
+	}
+
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	ctx.Request = httptest.NewRequest(method, requestPath, body)
+	ctx.Request.Header.Set("accept", "application/json")
+	if form != nil {
+		ctx.Request.Header.Set("content-type", "application/x-www-form-urlencoded")
+	}
+
+	handler(ctx)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+
+	b, err := io.ReadAll(result.Body)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal(expectedHTTPStatus, recorder.Code, string(b))
+	return b
+}
+
+func (suite *ScheduledStatusTestSuite) TestGetScheduledStatuses() {
+	b := suite.scheduledStatusRequest(
+		http.MethodGet,
+		suite.scheduledStatusesModule.ScheduledStatusesGETHandler,
+		"",
+		nil,
+		http.StatusOK,
+	)
+
+	scheduled := []*apimodel.ScheduledStatus{}
+	if err := json.Unmarshal(b, &scheduled); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Len(scheduled, 1)
+	suite.Equal(suite.testScheduled["local_account_1_scheduled_status_1"].ID, scheduled[0].ID)
+	suite.Equal("2099-01-01T12:00:00.000Z", scheduled[0].ScheduledAt)
+	suite.Equal("happy new year from the distant future!", scheduled[0].Params.Text)
+	suite.Equal("public", scheduled[0].Params.Visibility)
+}
+
+func (suite *ScheduledStatusTestSuite) TestGetScheduledStatus() {
+	scheduledStatus := suite.testScheduled["local_account_1_scheduled_status_1"]
+
+	b := suite.scheduledStatusRequest(
+		http.MethodGet,
+		suite.scheduledStatusesModule.ScheduledStatusGETHandler,
+		scheduledStatus.ID,
+		nil,
+		http.StatusOK,
+	)
+
+	apiScheduledStatus := &apimodel.ScheduledStatus{}
+	if err := json.Unmarshal(b, apiScheduledStatus); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal(scheduledStatus.ID, apiScheduledStatus.ID)
+	suite.Equal(scheduledStatus.ApplicationID, apiScheduledStatus.Params.ApplicationID)
+	suite.Empty(apiScheduledStatus.MediaAttachments)
+}
+
+func (suite *ScheduledStatusTestSuite) TestGetScheduledStatusNotFound() {
+	b := suite.scheduledStatusRequest(
+		http.MethodGet,
+		suite.scheduledStatusesModule.ScheduledStatusGETHandler,
+		"01H9J6B4M3Z0WTTBSDBV8QH7FQ",
+		nil,
+		http.StatusNotFound,
+	)
+
+	suite.Equal(`{"error":"Not Found"}`, string(b))
+}
+
+func (suite *ScheduledStatusTestSuite) TestPutScheduledStatus() {
+	scheduledStatus := suite.testScheduled["local_account_1_scheduled_status_1"]
+
+	b := suite.scheduledStatusRequest(
+		http.MethodPut,
+		suite.scheduledStatusesModule.ScheduledStatusPUTHandler,
+		scheduledStatus.ID,
+		url.Values{"scheduled_at": {"2100-06-01T09:30:00Z"}},
+		http.StatusOK,
+	)
+
+	apiScheduledStatus := &apimodel.ScheduledStatus{}
+	if err := json.Unmarshal(b, apiScheduledStatus); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal("2100-06-01T09:30:00.000Z", apiScheduledStatus.ScheduledAt)
+
+	dbScheduledStatus, err := suite.db.GetScheduledStatusByID(context.Background(), scheduledStatus.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.True(dbScheduledStatus.ScheduledAt.Equal(time.Date(2100, 6, 1, 9, 30, 0, 0, time.UTC)))
+}
+
+func (suite *ScheduledStatusTestSuite) TestPutScheduledStatusTooSoon() {
+	scheduledStatus := suite.testScheduled["local_account_1_scheduled_status_1"]
+
+	b := suite.scheduledStatusRequest(
+		http.MethodPut,
+		suite.scheduledStatusesModule.ScheduledStatusPUTHandler,
+		scheduledStatus.ID,
+		url.Values{"scheduled_at": {time.Now().Add(time.Minute).UTC().Format(time.RFC3339)}},
+		http.StatusUnprocessableEntity,
+	)
+
+	suite.Equal(`{"error":"Unprocessable Entity: scheduled_at must be at least 5 minutes in the future"}`, string(b))
+}
+
+func (suite *ScheduledStatusTestSuite) TestDeleteScheduledStatus() {
+	scheduledStatus := suite.testScheduled["local_account_1_scheduled_status_1"]
+
+	b := suite.scheduledStatusRequest(
+		http.MethodDelete,
+		suite.scheduledStatusesModule.ScheduledStatusDELETEHandler,
+		scheduledStatus.ID,
+		nil,
+		http.StatusOK,
+	)
+	suite.Equal(`{}`, string(b))
+
+	_, err := suite.db.GetScheduledStatusByID(context.Background(), scheduledStatus.ID)
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
+func TestScheduledStatusTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduledStatusTestSuite))
+}
@@ -64,6 +64,11 @@ const (
 
 	// ContextPath is used for fetching context of posts
 	ContextPath = BasePathWithID + "/context"
+
+	// HistoryPath is used for fetching the edit history of a status
+	HistoryPath = BasePathWithID + "/history"
+	// SourcePath is used for fetching the plain-text source of a status, for editing
+	SourcePath = BasePathWithID + "/source"
 )
 
 type Module struct {
@@ -77,11 +82,16 @@ func New(processor *processing.Processor) *Module {
 }
 
 func (m *Module) Route(attachHandler func(method string, path string, f ...gin.HandlerFunc) gin.IRoutes) {
-	// create / get / delete status
+	// create / get / edit / delete status
 	attachHandler(http.MethodPost, BasePath, m.StatusCreatePOSTHandler)
 	attachHandler(http.MethodGet, BasePathWithID, m.StatusGETHandler)
+	attachHandler(http.MethodPut, BasePathWithID, m.StatusPUTHandler)
 	attachHandler(http.MethodDelete, BasePathWithID, m.StatusDELETEHandler)
 
+	// edit history / source
+	attachHandler(http.MethodGet, HistoryPath, m.StatusHistoryGETHandler)
+	attachHandler(http.MethodGet, SourcePath, m.StatusSourceGETHandler)
+
 	// fave stuff
 	attachHandler(http.MethodPost, FavouritePath, m.StatusFavePOSTHandler)
 	attachHandler(http.MethodPost, UnfavouritePath, m.StatusUnfavePOSTHandler)
@@ -98,6 +108,10 @@ func (m *Module) Route(attachHandler func(method string, path string, f ...gin.H
 	attachHandler(http.MethodPost, BookmarkPath, m.StatusBookmarkPOSTHandler)
 	attachHandler(http.MethodPost, UnbookmarkPath, m.StatusUnbookmarkPOSTHandler)
 
+	// mute stuff
+	attachHandler(http.MethodPost, MutePath, m.StatusMutePOSTHandler)
+	attachHandler(http.MethodPost, UnmutePath, m.StatusUnmutePOSTHandler)
+
 	// context / status thread
 	attachHandler(http.MethodGet, ContextPath, m.StatusContextGETHandler)
 }
@@ -56,7 +56,11 @@ import (
 //
 //	responses:
 //		'200':
-//			description: "The newly created status."
+//			description: |-
+//				The newly created status.
+//
+//				If `scheduled_at` was given, the status isn't created yet, and
+//				the scheduled status is returned instead (see `scheduledStatus`).
 //			schema:
 //				"$ref": "#/definitions/status"
 //		'400':
@@ -69,6 +73,8 @@ import (
 //			description: not found
 //		'406':
 //			description: not acceptable
+//		'422':
+//			description: unprocessable
 //		'500':
 //			description: internal server error
 func (m *Module) StatusCreatePOSTHandler(c *gin.Context) {
@@ -78,6 +84,11 @@ func (m *Module) StatusCreatePOSTHandler(c *gin.Context) {
 		return
 	}
 
+	if err := authed.CheckScope(oauth.ScopeWriteStatuses); err != nil {
+		apiutil.ErrorHandler(c, gtserror.NewErrorForbidden(err, err.Error()), m.processor.InstanceGetV1)
+		return
+	}
+
 	if _, err := apiutil.NegotiateAccept(c, apiutil.JSONAcceptHeaders...); err != nil {
 		apiutil.ErrorHandler(c, gtserror.NewErrorNotAcceptable(err, err.Error()), m.processor.InstanceGetV1)
 		return
@@ -103,6 +114,19 @@ func (m *Module) StatusCreatePOSTHandler(c *gin.Context) {
 		return
 	}
 
+	if form.ScheduledAt != "" {
+		// Status is to be published later,
+		// so return the scheduled status.
+		apiScheduledStatus, errWithCode := m.processor.Status().ScheduledStatusCreate(c.Request.Context(), authed.Account, authed.Application, form)
+		if errWithCode != nil {
+			apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+			return
+		}
+
+		c.JSON(http.StatusOK, apiScheduledStatus)
+		return
+	}
+
 	apiStatus, errWithCode := m.processor.Status().Create(c.Request.Context(), authed.Account, authed.Application, form)
 	if errWithCode != nil {
 		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
@@ -145,6 +169,12 @@ func validateCreateStatus(form *apimodel.AdvancedStatusCreateForm) error {
 		if form.Poll.Options == nil {
 			return errors.New("poll with no options")
 		}
+		if len(form.Poll.Options) < 2 {
+			return errors.New("poll must have at least 2 options")
+		}
+		if form.Poll.ExpiresIn <= 0 {
+			return errors.New("poll with no expiry")
+		}
 		if len(form.Poll.Options) > maxPollOptions {
 			return fmt.Errorf("too many poll options provided, %d provided but limit is %d", len(form.Poll.Options), maxPollOptions)
 		}
@@ -100,6 +100,35 @@ func (suite *StatusCreateTestSuite) TestPostNewStatus() {
 	suite.NoError(err)
 }
 
+// Try to post a new status with a token that only has read scope.
+func (suite *StatusCreateTestSuite) TestPostNewStatusInsufficientScope() {
+	t := *suite.testTokens["local_account_1"]
+	t.Scope = "read"
+	oauthToken := oauth.DBTokenToToken(&t)
+
+	// setup
+	recorder := httptest.NewRecorder()
+	ctx, _ := testrig.CreateGinTestContext(recorder, nil)
+	ctx.Set(oauth.SessionAuthorizedApplication, suite.testApplications["application_1"])
+	ctx.Set(oauth.SessionAuthorizedToken, oauthToken)
+	ctx.Set(oauth.SessionAuthorizedUser, suite.testUsers["local_account_1"])
+	ctx.Set(oauth.SessionAuthorizedAccount, suite.testAccounts["local_account_1"])
+	ctx.Request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("http://localhost:8080/%s", statuses.BasePath), nil) // the endpoint we're hitting
+	ctx.Request.Header.Set("accept", "application/json")
+	ctx.Request.Form = url.Values{
+		"status": {"this should never be posted"},
+	}
+	suite.statusModule.StatusCreatePOSTHandler(ctx)
+
+	suite.EqualValues(http.StatusForbidden, recorder.Code)
+
+	result := recorder.Result()
+	defer result.Body.Close()
+	b, err := ioutil.ReadAll(result.Body)
+	suite.NoError(err)
+	suite.Equal(`{"error":"Forbidden: token scope \"read\" does not permit write:statuses"}`, string(b))
+}
+
 func (suite *StatusCreateTestSuite) TestPostNewStatusMarkdown() {
 	// set default post language of account 1 to markdown
 	testAccount := suite.testAccounts["local_account_1"]
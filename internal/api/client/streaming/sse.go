@@ -0,0 +1,642 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"codeberg.org/gruf/go-kv"
+	"github.com/gin-gonic/gin"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	apiutil "github.com/superseriousbusiness/gotosocial/internal/api/util"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	streampkg "github.com/superseriousbusiness/gotosocial/internal/stream"
+	"golang.org/x/exp/slices"
+)
+
+// replayLimit is the maximum number of each kind
+// of missed event that will be replayed to a client
+// resuming a Server-Sent Events stream.
+const replayLimit = 40
+
+// StreamUserGETHandler swagger:operation GET /api/v1/streaming/user streamUserGet
+//
+// Stream home timeline updates and notifications for the requesting account as Server-Sent Events.
+//
+// See the notes on Server-Sent Events streams at `/api/v1/streaming/public`.
+//
+//	---
+//	tags:
+//	- streaming
+//
+//	produces:
+//	- text/event-stream
+//
+//	parameters:
+//	-
+//		name: access_token
+//		type: string
+//		description: Access token for the requesting account. Can also be given in the Authorization header.
+//		in: query
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- read:statuses
+//
+//	responses:
+//		'200':
+//			description: Server-Sent Events stream.
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden
+func (m *Module) StreamUserGETHandler(c *gin.Context) {
+	m.serveSSE(c, streampkg.TimelineHome)
+}
+
+// StreamUserNotificationGETHandler swagger:operation GET /api/v1/streaming/user/notification streamUserNotificationGet
+//
+// Stream notifications for the requesting account as Server-Sent Events.
+//
+// See the notes on Server-Sent Events streams at `/api/v1/streaming/public`.
+//
+//	---
+//	tags:
+//	- streaming
+//
+//	produces:
+//	- text/event-stream
+//
+//	parameters:
+//	-
+//		name: access_token
+//		type: string
+//		description: Access token for the requesting account. Can also be given in the Authorization header.
+//		in: query
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- read:statuses
+//
+//	responses:
+//		'200':
+//			description: Server-Sent Events stream.
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden
+func (m *Module) StreamUserNotificationGETHandler(c *gin.Context) {
+	m.serveSSE(c, streampkg.TimelineNotifications)
+}
+
+// StreamPublicGETHandler swagger:operation GET /api/v1/streaming/public streamPublicGet
+//
+// Stream public timeline updates as Server-Sent Events.
+//
+// Server-Sent Events streams are an alternative to the websocket
+// stream at `/api/v1/streaming`, for clients that can't use websockets.
+// Each event has an `event` field giving its type, and a `data` field
+// containing the same payload as the equivalent websocket message.
+//
+// Comment lines are sent as a heartbeat every 30 seconds when there
+// are no events, so clients and proxies know the stream is still open.
+//
+// `update` and `notification` events carry an `id` field. Clients that
+// reconnect with this ID in the `Last-Event-ID` header will be sent
+// (up to 40 of each kind of) the updates and notifications they missed,
+// except on direct message and local hashtag streams.
+//
+// Public, local and hashtag streams can be opened without an access token
+// if this instance exposes its public timeline to unauthenticated users.
+//
+//	---
+//	tags:
+//	- streaming
+//
+//	produces:
+//	- text/event-stream
+//
+//	parameters:
+//	-
+//		name: access_token
+//		type: string
+//		description: Access token for the requesting account. Can also be given in the Authorization header.
+//		in: query
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- read:statuses
+//
+//	responses:
+//		'200':
+//			description: Server-Sent Events stream.
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden
+func (m *Module) StreamPublicGETHandler(c *gin.Context) {
+	m.serveSSE(c, streampkg.TimelinePublic)
+}
+
+// StreamPublicLocalGETHandler swagger:operation GET /api/v1/streaming/public/local streamPublicLocalGet
+//
+// Stream local timeline updates as Server-Sent Events.
+//
+// See the notes on Server-Sent Events streams at `/api/v1/streaming/public`.
+//
+//	---
+//	tags:
+//	- streaming
+//
+//	produces:
+//	- text/event-stream
+//
+//	parameters:
+//	-
+//		name: access_token
+//		type: string
+//		description: Access token for the requesting account. Can also be given in the Authorization header.
+//		in: query
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- read:statuses
+//
+//	responses:
+//		'200':
+//			description: Server-Sent Events stream.
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden
+func (m *Module) StreamPublicLocalGETHandler(c *gin.Context) {
+	m.serveSSE(c, streampkg.TimelineLocal)
+}
+
+// StreamHashtagGETHandler swagger:operation GET /api/v1/streaming/hashtag streamHashtagGet
+//
+// Stream updates for statuses with the given hashtag as Server-Sent Events.
+//
+// See the notes on Server-Sent Events streams at `/api/v1/streaming/public`.
+//
+//	---
+//	tags:
+//	- streaming
+//
+//	produces:
+//	- text/event-stream
+//
+//	parameters:
+//	-
+//		name: tag
+//		type: string
+//		description: Name of the hashtag to stream, without the leading `#`.
+//		in: query
+//		required: true
+//	-
+//		name: access_token
+//		type: string
+//		description: Access token for the requesting account. Can also be given in the Authorization header.
+//		in: query
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- read:statuses
+//
+//	responses:
+//		'200':
+//			description: Server-Sent Events stream.
+//		'400':
+//			description: bad request
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden
+func (m *Module) StreamHashtagGETHandler(c *gin.Context) {
+	m.serveSSE(c, streampkg.TimelineHashtag)
+}
+
+// StreamHashtagLocalGETHandler swagger:operation GET /api/v1/streaming/hashtag/local streamHashtagLocalGet
+//
+// Stream updates for local statuses with the given hashtag as Server-Sent Events.
+//
+// See the notes on Server-Sent Events streams at `/api/v1/streaming/public`.
+//
+//	---
+//	tags:
+//	- streaming
+//
+//	produces:
+//	- text/event-stream
+//
+//	parameters:
+//	-
+//		name: tag
+//		type: string
+//		description: Name of the hashtag to stream, without the leading `#`.
+//		in: query
+//		required: true
+//	-
+//		name: access_token
+//		type: string
+//		description: Access token for the requesting account. Can also be given in the Authorization header.
+//		in: query
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- read:statuses
+//
+//	responses:
+//		'200':
+//			description: Server-Sent Events stream.
+//		'400':
+//			description: bad request
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden
+func (m *Module) StreamHashtagLocalGETHandler(c *gin.Context) {
+	m.serveSSE(c, streampkg.TimelineHashtagLocal)
+}
+
+// StreamListGETHandler swagger:operation GET /api/v1/streaming/list streamListGet
+//
+// Stream updates for the given list as Server-Sent Events.
+//
+// See the notes on Server-Sent Events streams at `/api/v1/streaming/public`.
+//
+//	---
+//	tags:
+//	- streaming
+//
+//	produces:
+//	- text/event-stream
+//
+//	parameters:
+//	-
+//		name: list
+//		type: string
+//		description: ID of the list to stream.
+//		in: query
+//		required: true
+//	-
+//		name: access_token
+//		type: string
+//		description: Access token for the requesting account. Can also be given in the Authorization header.
+//		in: query
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- read:statuses
+//
+//	responses:
+//		'200':
+//			description: Server-Sent Events stream.
+//		'400':
+//			description: bad request
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden
+func (m *Module) StreamListGETHandler(c *gin.Context) {
+	m.serveSSE(c, streampkg.TimelineList)
+}
+
+// StreamDirectGETHandler swagger:operation GET /api/v1/streaming/direct streamDirectGet
+//
+// Stream updates for direct messages as Server-Sent Events.
+//
+// See the notes on Server-Sent Events streams at `/api/v1/streaming/public`.
+//
+//	---
+//	tags:
+//	- streaming
+//
+//	produces:
+//	- text/event-stream
+//
+//	parameters:
+//	-
+//		name: access_token
+//		type: string
+//		description: Access token for the requesting account. Can also be given in the Authorization header.
+//		in: query
+//
+//	security:
+//	- OAuth2 Bearer:
+//		- read:statuses
+//
+//	responses:
+//		'200':
+//			description: Server-Sent Events stream.
+//		'401':
+//			description: unauthorized
+//		'403':
+//			description: forbidden
+func (m *Module) StreamDirectGETHandler(c *gin.Context) {
+	m.serveSSE(c, streampkg.TimelineDirect)
+}
+
+// serveSSE opens a stream of the given type for the requesting
+// account, and writes messages from it into the response as
+// Server-Sent Events, until the client goes away.
+func (m *Module) serveSSE(c *gin.Context, streamType string) {
+	account, errWithCode := m.authorize(c, streamType)
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	// Append the list ID or tag name to
+	// the stream type, as for websockets.
+	var param string
+	switch streamType {
+	case streampkg.TimelineList:
+		param = c.Query(StreamListKey)
+		if param == "" {
+			err := errors.New("list parameter is required for list streams")
+			apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, err.Error()), m.processor.InstanceGetV1)
+			return
+		}
+
+	case streampkg.TimelineHashtag, streampkg.TimelineHashtagLocal:
+		tag := c.Query(StreamTagKey)
+		if tag == "" {
+			err := errors.New("tag parameter is required for hashtag streams")
+			apiutil.ErrorHandler(c, gtserror.NewErrorBadRequest(err, err.Error()), m.processor.InstanceGetV1)
+			return
+		}
+
+		param, errWithCode = normalizeTag(tag)
+		if errWithCode != nil {
+			apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+			return
+		}
+	}
+
+	fullStreamType := streamType
+	if param != "" {
+		fullStreamType += ":" + param
+	}
+
+	ctx := c.Request.Context()
+
+	stream, errWithCode := m.processor.Stream().Open(ctx, account, fullStreamType)
+	if errWithCode != nil {
+		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+		return
+	}
+
+	// Close processor channel when we're done so the
+	// processor knows not to send any more messages.
+	defer close(stream.Hangup)
+
+	l := log.
+		WithContext(ctx).
+		WithFields(kv.Fields{
+			{K: "username", V: usernameOf(account)},
+			{K: "streamID", V: stream.ID},
+		}...)
+
+	// The stream is expected to stay open for much longer than
+	// the server's write timeout, so clear the deadline for it.
+	rc := http.NewResponseController(c.Writer)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		l.Errorf("error clearing write deadline: %v", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	l.Info("opened server-sent events stream")
+
+	// Start the stream off with a comment,
+	// so the client gets the headers.
+	if err := writeSSEComment(c.Writer, ")"); err != nil {
+		l.Debugf("error writing to server-sent events stream: %v", err)
+		return
+	}
+
+	// Replay missed events to clients resuming a stream.
+	if lastEventID := c.GetHeader("Last-Event-ID"); lastEventID != "" {
+		messages, err := m.replay(ctx, account, streamType, param, lastEventID)
+		if err != nil {
+			// Not fatal, just carry on from now.
+			l.Errorf("error replaying missed events: %v", err)
+		}
+
+		for _, msg := range messages {
+			if err := writeSSEMessage(c.Writer, msg); err != nil {
+				l.Debugf("error writing to server-sent events stream: %v", err)
+				return
+			}
+		}
+	}
+
+	c.Writer.Flush()
+
+	// Create ticker to send keepalive heartbeats.
+	heartbeat := time.NewTicker(m.dTicker)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Client went away.
+			l.Info("closed server-sent events stream")
+			return
+
+		case msg, ok := <-stream.Messages:
+			if !ok {
+				// Stream closed.
+				return
+			}
+
+			l.Tracef("writing message to server-sent events stream: %+v", msg)
+			if err := writeSSEMessage(c.Writer, msg); err != nil {
+				l.Debugf("error writing to server-sent events stream: %v", err)
+				return
+			}
+
+			// Reset heartbeat on successful send.
+			heartbeat.Reset(m.dTicker)
+
+		case <-heartbeat.C:
+			if err := writeSSEComment(c.Writer, "thump"); err != nil {
+				l.Debugf("error writing heartbeat to server-sent events stream: %v", err)
+				return
+			}
+		}
+
+		c.Writer.Flush()
+	}
+}
+
+// writeSSEComment writes the given comment line
+// into a Server-Sent Events stream, and flushes it.
+func writeSSEComment(w gin.ResponseWriter, comment string) error {
+	if _, err := io.WriteString(w, ":"+comment+"\n\n"); err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
+
+// writeSSEMessage writes the given stream message as an
+// event into a Server-Sent Events stream. Updates and
+// notifications are given an ID, so that clients can
+// resume the stream from them if they get disconnected.
+func writeSSEMessage(w io.Writer, msg *streampkg.Message) error {
+	var b strings.Builder
+
+	b.WriteString("event: " + msg.Event + "\n")
+
+	switch msg.Event {
+	case streampkg.EventTypeUpdate, streampkg.EventTypeNotification:
+		var payload struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(msg.Payload), &payload); err == nil && payload.ID != "" {
+			b.WriteString("id: " + payload.ID + "\n")
+		}
+	}
+
+	for _, line := range strings.Split(msg.Payload, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// replay returns messages for the updates and notifications
+// that a client missed on a stream of the given type, since
+// the event with the given ID, in the order they happened.
+func (m *Module) replay(
+	ctx context.Context,
+	account *gtsmodel.Account,
+	streamType string,
+	param string,
+	lastEventID string,
+) ([]*streampkg.Message, error) {
+	type replayed struct {
+		id  string
+		msg *streampkg.Message
+	}
+
+	var (
+		authed = &oauth.Auth{Account: account}
+		items  []replayed
+		errs   = gtserror.NewMultiError(2)
+	)
+
+	// appendItems appends messages for the
+	// statuses or notifications in the given
+	// pageable response to the items slice.
+	appendItems := func(resp *apimodel.PageableResponse, errWithCode gtserror.WithCode) {
+		if errWithCode != nil {
+			errs.Append(errWithCode)
+			return
+		}
+
+		for _, item := range resp.Items {
+			var id, event string
+			switch item := item.(type) {
+			case *apimodel.Status:
+				id, event = item.ID, streampkg.EventTypeUpdate
+			case *apimodel.Notification:
+				id, event = item.ID, streampkg.EventTypeNotification
+			default:
+				continue
+			}
+
+			b, err := json.Marshal(item)
+			if err != nil {
+				errs.Appendf("error marshaling %s: %w", event, err)
+				continue
+			}
+
+			items = append(items, replayed{
+				id: id,
+				msg: &streampkg.Message{
+					Stream:  []string{streamType},
+					Event:   event,
+					Payload: string(b),
+				},
+			})
+		}
+	}
+
+	switch streamType {
+	case streampkg.TimelineHome:
+		appendItems(m.processor.Timeline().HomeTimelineGet(ctx, authed, "", "", lastEventID, replayLimit, false))
+		appendItems(m.processor.Timeline().NotificationsGet(ctx, authed, "", "", lastEventID, replayLimit, nil))
+
+	case streampkg.TimelineNotifications:
+		appendItems(m.processor.Timeline().NotificationsGet(ctx, authed, "", "", lastEventID, replayLimit, nil))
+
+	case streampkg.TimelinePublic:
+		appendItems(m.processor.Timeline().PublicTimelineGet(ctx, authed, "", "", lastEventID, replayLimit, false))
+
+	case streampkg.TimelineLocal:
+		appendItems(m.processor.Timeline().PublicTimelineGet(ctx, authed, "", "", lastEventID, replayLimit, true))
+
+	case streampkg.TimelineHashtag:
+		appendItems(m.processor.Timeline().TagTimelineGet(ctx, account, param, "", "", lastEventID, replayLimit))
+
+	case streampkg.TimelineList:
+		appendItems(m.processor.Timeline().ListTimelineGet(ctx, authed, param, "", "", lastEventID, replayLimit))
+
+	default:
+		// Not feasible to
+		// replay this stream.
+		return nil, nil
+	}
+
+	// Items are given newest first, but the client
+	// should get events in the order they happened.
+	slices.SortFunc(items, func(a, b replayed) bool {
+		return a.id < b.id
+	})
+
+	messages := make([]*streampkg.Message, len(items))
+	for i, item := range items {
+		messages[i] = item.msg
+	}
+
+	if err := errs.Combine(); err != nil {
+		return messages, fmt.Errorf("%w", err)
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,253 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package streaming_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/streaming"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+)
+
+// sseServer returns a test server serving the
+// streaming module's Server-Sent Events routes.
+func (suite *StreamingTestSuite) sseServer() *httptest.Server {
+	engine := gin.New()
+	module := streaming.New(suite.processor, 50*time.Millisecond, 4096)
+	module.RouteSSE(engine.Group("/api").Handle)
+	return httptest.NewServer(engine)
+}
+
+// openSSE opens a Server-Sent Events stream at the
+// given path, with the given Last-Event-ID if set.
+func (suite *StreamingTestSuite) openSSE(server *httptest.Server, path string, lastEventID string) *http.Response {
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api"+path, nil)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	return resp
+}
+
+// readSSE reads the next event or comment from the given
+// stream, returning its fields. Comments are returned with
+// the comment text as the value of the empty field name.
+func readSSE(r *bufio.Reader) (map[string]string, error) {
+	fields := make(map[string]string)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		if line == "" {
+			// End of event.
+			return fields, nil
+		}
+
+		name, value, _ := strings.Cut(line, ":")
+		fields[name] += strings.TrimPrefix(value, " ")
+	}
+}
+
+// readSSEEvent reads the next event from the
+// given stream, skipping over any comments.
+func (suite *StreamingTestSuite) readSSEEvent(r *bufio.Reader) map[string]string {
+	for {
+		fields, err := readSSE(r)
+		if err != nil {
+			suite.FailNow(err.Error())
+		}
+
+		if _, comment := fields[""]; !comment {
+			return fields
+		}
+	}
+}
+
+func (suite *StreamingTestSuite) TestSSENotification() {
+	server := suite.sseServer()
+	defer server.Close()
+
+	token := suite.testTokens["local_account_1"]
+	resp := suite.openSSE(server, streaming.UserNotificationPath+"?access_token="+token.Access, "")
+	defer resp.Body.Close()
+
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal("text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Stream starts with a comment.
+	r := bufio.NewReader(resp.Body)
+	fields, err := readSSE(r)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal(map[string]string{"": ")"}, fields)
+
+	// Stream is open now, so send it a notification.
+	notification := &apimodel.Notification{
+		ID:   "01HBGQA1R0VN7SH9GB4DDPPQGM",
+		Type: "mention",
+	}
+	if err := suite.processor.Stream().Notify(notification, suite.testAccounts["local_account_1"]); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	fields = suite.readSSEEvent(r)
+	suite.Equal("notification", fields["event"])
+	suite.Equal("01HBGQA1R0VN7SH9GB4DDPPQGM", fields["id"])
+
+	apiNotification := &apimodel.Notification{}
+	if err := json.Unmarshal([]byte(fields["data"]), apiNotification); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal(notification.ID, apiNotification.ID)
+}
+
+func (suite *StreamingTestSuite) TestSSEHeartbeat() {
+	server := suite.sseServer()
+	defer server.Close()
+
+	token := suite.testTokens["local_account_1"]
+	resp := suite.openSSE(server, streaming.UserPath+"?access_token="+token.Access, "")
+	defer resp.Body.Close()
+
+	r := bufio.NewReader(resp.Body)
+	for {
+		fields, err := readSSE(r)
+		if err != nil {
+			suite.FailNow(err.Error())
+		}
+
+		if fields[""] == "thump" {
+			// Got a heartbeat.
+			return
+		}
+	}
+}
+
+func (suite *StreamingTestSuite) TestSSEResume() {
+	server := suite.sseServer()
+	defer server.Close()
+
+	// Resume from just before the one
+	// notification local_account_1 has.
+	token := suite.testTokens["local_account_1"]
+	resp := suite.openSSE(server, streaming.UserNotificationPath+"?access_token="+token.Access, "01F8Q0ANPTWW10DAKTX7BRPBJ0")
+	defer resp.Body.Close()
+
+	fields := suite.readSSEEvent(bufio.NewReader(resp.Body))
+	suite.Equal("notification", fields["event"])
+	suite.Equal("01F8Q0ANPTWW10DAKTX7BRPBJP", fields["id"])
+	suite.Contains(fields["data"], `"type":"favourite"`)
+}
+
+func (suite *StreamingTestSuite) TestSSEUnauthorized() {
+	server := suite.sseServer()
+	defer server.Close()
+
+	resp := suite.openSSE(server, streaming.UserPath, "")
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+	suite.Contains(string(b), "Unauthorized")
+}
+
+func (suite *StreamingTestSuite) TestSSEHashtagNoTag() {
+	server := suite.sseServer()
+	defer server.Close()
+
+	token := suite.testTokens["local_account_1"]
+	resp := suite.openSSE(server, streaming.HashtagPath+"?access_token="+token.Access, "")
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+	suite.Equal(`{"error":"Bad Request: tag parameter is required for hashtag streams"}`, string(b))
+}
+
+func (suite *StreamingTestSuite) TestSSEPublicAnonymous() {
+	config.SetInstanceExposePublicTimeline(true)
+
+	server := suite.sseServer()
+	defer server.Close()
+
+	resp := suite.openSSE(server, streaming.HashtagLocalPath+"?tag=%23Welcome", "")
+	defer resp.Body.Close()
+
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	// Wait for the stream to open.
+	r := bufio.NewReader(resp.Body)
+	if _, err := readSSE(r); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	status, err := suite.db.GetStatusByID(context.Background(), suite.testStatuses["admin_account_status_1"].ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	if err := suite.processor.Stream().PublicUpdate(context.Background(), status); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	fields := suite.readSSEEvent(r)
+	suite.Equal("update", fields["event"])
+	suite.Equal(status.ID, fields["id"])
+}
+
+func (suite *StreamingTestSuite) TestSSEPublicAnonymousNotExposed() {
+	config.SetInstanceExposePublicTimeline(false)
+
+	server := suite.sseServer()
+	defer server.Close()
+
+	resp := suite.openSSE(server, streaming.PublicPath, "")
+	defer resp.Body.Close()
+
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
@@ -23,6 +23,7 @@ import (
 
 	"codeberg.org/gruf/go-kv"
 	apiutil "github.com/superseriousbusiness/gotosocial/internal/api/util"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/log"
@@ -62,9 +63,13 @@ import (
 //	-
 //		name: access_token
 //		type: string
-//		description: Access token for the requesting account.
+//		description: |-
+//			Access token for the requesting account.
+//
+//			Not required for `public`, `public:local`, `hashtag`
+//			and `hashtag:local` streams if this instance exposes
+//			its public timeline to unauthenticated users.
 //		in: query
-//		required: true
 //	-
 //		name: stream
 //		type: string
@@ -99,7 +104,7 @@ import (
 //
 //	security:
 //	- OAuth2 Bearer:
-//		- read:streaming
+//		- read:statuses
 //
 //	responses:
 //		'101':
@@ -146,45 +151,20 @@ import (
 //						example: "{\"id\":\"01FC3TZ5CFG6H65GCKCJRKA669\",\"created_at\":\"2021-08-02T16:25:52Z\",\"sensitive\":false,\"spoiler_text\":\"\",\"visibility\":\"public\",\"language\":\"en\",\"uri\":\"https://gts.superseriousbusiness.org/users/dumpsterqueer/statuses/01FC3TZ5CFG6H65GCKCJRKA669\",\"url\":\"https://gts.superseriousbusiness.org/@dumpsterqueer/statuses/01FC3TZ5CFG6H65GCKCJRKA669\",\"replies_count\":0,\"reblogs_count\":0,\"favourites_count\":0,\"favourited\":false,\"reblogged\":false,\"muted\":false,\"bookmarked\":fals…//gts.superseriousbusiness.org/fileserver/01JNN207W98SGG3CBJ76R5MVDN/header/original/019036W043D8FXPJKSKCX7G965.png\",\"header_static\":\"https://gts.superseriousbusiness.org/fileserver/01JNN207W98SGG3CBJ76R5MVDN/header/small/019036W043D8FXPJKSKCX7G965.png\",\"followers_count\":33,\"following_count\":28,\"statuses_count\":126,\"last_status_at\":\"2021-08-02T16:25:52Z\",\"emojis\":[],\"fields\":[]},\"media_attachments\":[],\"mentions\":[],\"tags\":[],\"emojis\":[],\"card\":null,\"poll\":null,\"text\":\"a\"}"
 //		'401':
 //			description: unauthorized
+//		'403':
+//			description: forbidden
 //		'400':
 //			description: bad request
 func (m *Module) StreamGETHandler(c *gin.Context) {
-	var (
-		account     *gtsmodel.Account
-		errWithCode gtserror.WithCode
-	)
-
-	// Try query param access token.
-	token := c.Query(AccessTokenQueryKey)
-	if token == "" {
-		// Try fallback HTTP header provided token.
-		token = c.GetHeader(AccessTokenHeader)
-	}
-
-	if token != "" {
-		// Token was provided, use it to authorize stream.
-		account, errWithCode = m.processor.Stream().Authorize(c.Request.Context(), token)
-	} else {
-		// No explicit token was provided:
-		// try regular oauth as a last resort.
-		account, errWithCode = func() (*gtsmodel.Account, gtserror.WithCode) {
-			authed, err := oauth.Authed(c, true, true, true, true)
-			if err != nil {
-				return nil, gtserror.NewErrorUnauthorized(err, err.Error())
-			}
-
-			return authed.Account, nil
-		}()
-	}
+	// Get the initial requested stream type, if there is one.
+	streamType := c.Query(StreamQueryKey)
 
+	account, errWithCode := m.authorize(c, streamType)
 	if errWithCode != nil {
 		apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
 		return
 	}
 
-	// Get the initial requested stream type, if there is one.
-	streamType := c.Query(StreamQueryKey)
-
 	// By appending other query params to the streamType, we
 	// can allow streaming for specific list IDs or hashtags.
 	// The streamType in this case will end up looking like
@@ -192,6 +172,11 @@ func (m *Module) StreamGETHandler(c *gin.Context) {
 	if list := c.Query(StreamListKey); list != "" {
 		streamType += ":" + list
 	} else if tag := c.Query(StreamTagKey); tag != "" {
+		tag, errWithCode := normalizeTag(tag)
+		if errWithCode != nil {
+			apiutil.ErrorHandler(c, errWithCode, m.processor.InstanceGetV1)
+			return
+		}
 		streamType += ":" + tag
 	}
 
@@ -208,10 +193,12 @@ func (m *Module) StreamGETHandler(c *gin.Context) {
 		return
 	}
 
+	username := usernameOf(account)
+
 	l := log.
 		WithContext(c.Request.Context()).
 		WithFields(kv.Fields{
-			{"username", account.Username},
+			{"username", username},
 			{"streamID", stream.ID},
 		}...)
 
@@ -235,7 +222,43 @@ func (m *Module) StreamGETHandler(c *gin.Context) {
 	// This prevents the upgrade handler from holding open any
 	// throttle / rate-limit request tokens which could become
 	// problematic on instances with multiple users.
-	go m.handleWSConn(account.Username, wsConn, stream)
+	go m.handleWSConn(username, wsConn, stream)
+}
+
+// authorize returns the account that the given stream request
+// is authorized for. The access token is taken from the query,
+// or from the websocket protocol header, falling back to regular
+// oauth for requests that give their token in the Authorization header.
+//
+// Requests for public and hashtag streams may be anonymous if the
+// instance exposes its public timeline, in which case the returned
+// account will be nil.
+func (m *Module) authorize(c *gin.Context, streamType string) (*gtsmodel.Account, gtserror.WithCode) {
+	// Try query param access token.
+	token := c.Query(AccessTokenQueryKey)
+	if token == "" {
+		// Try fallback HTTP header provided token.
+		token = c.GetHeader(AccessTokenHeader)
+	}
+
+	if token != "" {
+		// Token was provided, use it to authorize stream.
+		return m.processor.Stream().Authorize(c.Request.Context(), token)
+	}
+
+	// No explicit token was provided:
+	// try regular oauth as a last resort.
+	requireAuth := !(isPublicStreamType(streamType) && config.GetInstanceExposePublicTimeline())
+	authed, err := oauth.Authed(c, requireAuth, requireAuth, requireAuth, requireAuth)
+	if err != nil {
+		return nil, gtserror.NewErrorUnauthorized(err, err.Error())
+	}
+
+	if err := authed.CheckScope(oauth.ScopeReadStatuses); err != nil {
+		return nil, gtserror.NewErrorForbidden(err, err.Error())
+	}
+
+	return authed.Account, nil
 }
 
 // handleWSConn handles a two-way websocket streaming connection.
@@ -361,6 +384,13 @@ readLoop:
 			updateList, ok := msg["list"]
 			if ok {
 				updateStream += ":" + updateList
+			} else if updateTag, ok := msg["tag"]; ok {
+				updateTag, errWithCode := normalizeTag(updateTag)
+				if errWithCode != nil {
+					l.Warnf("invalid 'tag' field: %v", msg)
+					continue
+				}
+				updateStream += ":" + updateTag
 			}
 
 			switch updateType {
@@ -18,21 +18,35 @@
 package streaming
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/processing"
+	streampkg "github.com/superseriousbusiness/gotosocial/internal/stream"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
 )
 
 const (
-	BasePath            = "/v1/streaming"          // path for the streaming api, minus the 'api' prefix
-	StreamQueryKey      = "stream"                 // type of stream being requested
-	StreamListKey       = "list"                   // id of list being requested
-	StreamTagKey        = "tag"                    // name of tag being requested
-	AccessTokenQueryKey = "access_token"           // oauth access token
-	AccessTokenHeader   = "Sec-Websocket-Protocol" //nolint:gosec
+	BasePath             = "/v1/streaming"            // path for the streaming api, minus the 'api' prefix
+	UserPath             = BasePath + "/user"         // path for server-sent events home timeline + notifications stream
+	UserNotificationPath = UserPath + "/notification" // path for server-sent events notifications stream
+	PublicPath           = BasePath + "/public"       // path for server-sent events public timeline stream
+	PublicLocalPath      = PublicPath + "/local"      // path for server-sent events local timeline stream
+	HashtagPath          = BasePath + "/hashtag"      // path for server-sent events hashtag stream
+	HashtagLocalPath     = HashtagPath + "/local"     // path for server-sent events local hashtag stream
+	ListPath             = BasePath + "/list"         // path for server-sent events list stream
+	DirectPath           = BasePath + "/direct"       // path for server-sent events direct messages stream
+	StreamQueryKey       = "stream"                   // type of stream being requested
+	StreamListKey        = "list"                     // id of list being requested
+	StreamTagKey         = "tag"                      // name of tag being requested
+	AccessTokenQueryKey  = "access_token"             // oauth access token
+	AccessTokenHeader    = "Sec-Websocket-Protocol"   //nolint:gosec
 )
 
 type Module struct {
@@ -61,3 +75,54 @@ func New(processor *processing.Processor, dTicker time.Duration, wsBuf int) *Mod
 func (m *Module) Route(attachHandler func(method string, path string, f ...gin.HandlerFunc) gin.IRoutes) {
 	attachHandler(http.MethodGet, BasePath, m.StreamGETHandler)
 }
+
+// RouteSSE attaches the Server-Sent Events streaming routes. These are
+// kept separate from Route, as they hold their request open for as long as
+// the client is streaming, and so shouldn't go through throttling or gzip.
+func (m *Module) RouteSSE(attachHandler func(method string, path string, f ...gin.HandlerFunc) gin.IRoutes) {
+	attachHandler(http.MethodGet, UserPath, m.StreamUserGETHandler)
+	attachHandler(http.MethodGet, UserNotificationPath, m.StreamUserNotificationGETHandler)
+	attachHandler(http.MethodGet, PublicPath, m.StreamPublicGETHandler)
+	attachHandler(http.MethodGet, PublicLocalPath, m.StreamPublicLocalGETHandler)
+	attachHandler(http.MethodGet, HashtagPath, m.StreamHashtagGETHandler)
+	attachHandler(http.MethodGet, HashtagLocalPath, m.StreamHashtagLocalGETHandler)
+	attachHandler(http.MethodGet, ListPath, m.StreamListGETHandler)
+	attachHandler(http.MethodGet, DirectPath, m.StreamDirectGETHandler)
+}
+
+// isPublicStreamType returns whether the given stream
+// type is one that may be opened without an account.
+func isPublicStreamType(streamType string) bool {
+	switch streamType {
+	case streampkg.TimelinePublic,
+		streampkg.TimelineLocal,
+		streampkg.TimelineHashtag,
+		streampkg.TimelineHashtagLocal:
+		return true
+	default:
+		return false
+	}
+}
+
+// normalizeTag returns the given tag name in the
+// form used for hashtag stream types, ie., normalized
+// and lowercase, or an error if it isn't a valid tag.
+func normalizeTag(tag string) (string, gtserror.WithCode) {
+	tagNormal, ok := text.NormalizeHashtag(tag)
+	if !ok {
+		err := fmt.Errorf("tag %s is not a valid hashtag", tag)
+		return "", gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	return strings.ToLower(tagNormal), nil
+}
+
+// usernameOf returns the username of the
+// given account for logging, which may be
+// nil for anonymous streams.
+func usernameOf(account *gtsmodel.Account) string {
+	if account == nil {
+		return ""
+	}
+	return account.Username
+}
@@ -0,0 +1,145 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package user_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/api/client/user"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/totp"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type TwoFactorTestSuite struct {
+	UserStandardTestSuite
+}
+
+func (suite *TwoFactorTestSuite) newContext(recorder *httptest.ResponseRecorder, dbUser *gtsmodel.User, method string, path string, form url.Values) *gin.Context {
+	t := suite.testTokens["local_account_1"]
+	oauthToken := oauth.DBTokenToToken(t)
+
+	ctx, _ := testrig.CreateGinTestContext(recorder, nil)
+	ctx.Set(oauth.SessionAuthorizedApplication, suite.testApplications["application_1"])
+	ctx.Set(oauth.SessionAuthorizedToken, oauthToken)
+	ctx.Set(oauth.SessionAuthorizedUser, dbUser)
+	ctx.Set(oauth.SessionAuthorizedAccount, suite.testAccounts["local_account_1"])
+	ctx.Request = httptest.NewRequest(method, fmt.Sprintf("http://localhost:8080%s", path), nil)
+	ctx.Request.Header.Set("accept", "application/json")
+	ctx.Request.Form = form
+	return ctx
+}
+
+func (suite *TwoFactorTestSuite) TestTwoFactorSetupEnable() {
+	// set up a new secret
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, suite.testUsers["local_account_1"], http.MethodPost, user.TwoFactorSetupPath, nil)
+	suite.userModule.TwoFactorSetupPOSTHandler(ctx)
+	suite.EqualValues(http.StatusOK, recorder.Code)
+
+	b, err := ioutil.ReadAll(recorder.Result().Body)
+	suite.NoError(err)
+	setup := &apimodel.TwoFactorSetup{}
+	suite.NoError(json.Unmarshal(b, setup))
+	suite.NotEmpty(setup.Secret)
+
+	dbUser, err := suite.db.GetUserByID(context.Background(), suite.testUsers["local_account_1"].ID)
+	suite.NoError(err)
+
+	// enable it with a code from the secret
+	code, err := totp.Code(setup.Secret, time.Now())
+	suite.NoError(err)
+
+	recorder = httptest.NewRecorder()
+	ctx = suite.newContext(recorder, dbUser, http.MethodPost, user.TwoFactorEnablePath, url.Values{
+		"code": {code},
+	})
+	suite.userModule.TwoFactorEnablePOSTHandler(ctx)
+	suite.EqualValues(http.StatusOK, recorder.Code)
+
+	b, err = ioutil.ReadAll(recorder.Result().Body)
+	suite.NoError(err)
+	codes := &apimodel.TwoFactorRecoveryCodes{}
+	suite.NoError(json.Unmarshal(b, codes))
+	suite.Len(codes.RecoveryCodes, 10)
+
+	// status should now show it enabled
+	recorder = httptest.NewRecorder()
+	ctx = suite.newContext(recorder, dbUser, http.MethodGet, user.TwoFactorPath, nil)
+	suite.userModule.TwoFactorGETHandler(ctx)
+	suite.EqualValues(http.StatusOK, recorder.Code)
+
+	b, err = ioutil.ReadAll(recorder.Result().Body)
+	suite.NoError(err)
+	status := &apimodel.TwoFactor{}
+	suite.NoError(json.Unmarshal(b, status))
+	suite.NotNil(status.EnabledAt)
+}
+
+func (suite *TwoFactorTestSuite) TestTwoFactorEnableMissingCode() {
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, suite.testUsers["local_account_1"], http.MethodPost, user.TwoFactorEnablePath, url.Values{})
+	suite.userModule.TwoFactorEnablePOSTHandler(ctx)
+	suite.EqualValues(http.StatusBadRequest, recorder.Code)
+
+	b, err := ioutil.ReadAll(recorder.Result().Body)
+	suite.NoError(err)
+	suite.Equal(`{"error":"Bad Request: two-factor enable request missing field code"}`, string(b))
+}
+
+func (suite *TwoFactorTestSuite) TestTwoFactorEnableNotSetUp() {
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, suite.testUsers["local_account_1"], http.MethodPost, user.TwoFactorEnablePath, url.Values{
+		"code": {"123456"},
+	})
+	suite.userModule.TwoFactorEnablePOSTHandler(ctx)
+	suite.EqualValues(http.StatusUnprocessableEntity, recorder.Code)
+
+	b, err := ioutil.ReadAll(recorder.Result().Body)
+	suite.NoError(err)
+	suite.Equal(`{"error":"Unprocessable Entity: two-factor authentication has not been set up yet"}`, string(b))
+}
+
+func (suite *TwoFactorTestSuite) TestTwoFactorDisableMissingCode() {
+	recorder := httptest.NewRecorder()
+	ctx := suite.newContext(recorder, suite.testUsers["local_account_1"], http.MethodPost, user.TwoFactorDisablePath, url.Values{
+		"password": {"password"},
+	})
+	suite.userModule.TwoFactorDisablePOSTHandler(ctx)
+	suite.EqualValues(http.StatusBadRequest, recorder.Code)
+
+	b, err := ioutil.ReadAll(recorder.Result().Body)
+	suite.NoError(err)
+	suite.Equal(`{"error":"Bad Request: two-factor disable request missing field code"}`, string(b))
+}
+
+func TestTwoFactorTestSuite(t *testing.T) {
+	suite.Run(t, &TwoFactorTestSuite{})
+}
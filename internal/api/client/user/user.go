@@ -29,6 +29,14 @@ const (
 	BasePath = "/v1/user"
 	// PasswordChangePath is the path for POSTing a password change request.
 	PasswordChangePath = BasePath + "/password_change"
+	// TwoFactorPath is the path for getting the two-factor authentication status.
+	TwoFactorPath = BasePath + "/2fa"
+	// TwoFactorSetupPath is the path for POSTing a request to generate a new two-factor secret.
+	TwoFactorSetupPath = TwoFactorPath + "/setup"
+	// TwoFactorEnablePath is the path for POSTing a request to enable two-factor authentication.
+	TwoFactorEnablePath = TwoFactorPath + "/enable"
+	// TwoFactorDisablePath is the path for POSTing a request to disable two-factor authentication.
+	TwoFactorDisablePath = TwoFactorPath + "/disable"
 )
 
 type Module struct {
@@ -43,4 +51,8 @@ func New(processor *processing.Processor) *Module {
 
 func (m *Module) Route(attachHandler func(method string, path string, f ...gin.HandlerFunc) gin.IRoutes) {
 	attachHandler(http.MethodPost, PasswordChangePath, m.PasswordChangePOSTHandler)
+	attachHandler(http.MethodGet, TwoFactorPath, m.TwoFactorGETHandler)
+	attachHandler(http.MethodPost, TwoFactorSetupPath, m.TwoFactorSetupPOSTHandler)
+	attachHandler(http.MethodPost, TwoFactorEnablePath, m.TwoFactorEnablePOSTHandler)
+	attachHandler(http.MethodPost, TwoFactorDisablePath, m.TwoFactorDisablePOSTHandler)
 }
@@ -96,6 +96,9 @@ type Account struct {
 	// Role of the account on this instance.
 	// Omitted for remote accounts.
 	Role *AccountRole `json:"role,omitempty"`
+	// If set, indicates that this account has moved to
+	// the given account, and is therefore inactive.
+	Moved *Account `json:"moved,omitempty"`
 }
 
 // AccountCreateRequest models account creation parameters.
@@ -104,6 +107,9 @@ type Account struct {
 type AccountCreateRequest struct {
 	// Text that will be reviewed by moderators if registrations require manual approval.
 	Reason string `form:"reason" json:"reason" xml:"reason"`
+	// Code of an invite link to sign up with.
+	// Required if registrations are closed on this instance.
+	InviteCode string `form:"invite_code" json:"invite_code" xml:"invite_code"`
 	// The desired username for the account.
 	// swagger:parameters
 	// pattern: [a-z0-9_]{2,64}
@@ -163,6 +169,9 @@ type UpdateCredentialsRequest struct {
 	CustomCSS *string `form:"custom_css" json:"custom_css"`
 	// Enable RSS feed of public toots for this account at /@[username]/feed.rss
 	EnableRSS *bool `form:"enable_rss" json:"enable_rss"`
+	// URIs of other accounts that this account is also known as (aliases).
+	// Set this before moving another account to this account.
+	AlsoKnownAsURIs *[]string `form:"also_known_as_uris[]" json:"also_known_as_uris"`
 }
 
 // UpdateSource is to be used specifically in an UpdateCredentialsRequest.
@@ -205,6 +214,18 @@ type AccountFollowRequest struct {
 	Notify *bool `form:"notify" json:"notify" xml:"notify"`
 }
 
+// AccountMuteRequest models a request to mute an account.
+//
+// swagger:ignore
+type AccountMuteRequest struct {
+	// The id of the account to mute.
+	ID string `form:"-" json:"-" xml:"-"`
+	// Mute notifications as well as posts.
+	Notifications *bool `form:"notifications" json:"notifications" xml:"notifications"`
+	// How long the mute should last, in seconds. 0 means indefinitely.
+	Duration *int `form:"duration" json:"duration" xml:"duration"`
+}
+
 // AccountDeleteRequest models a request to delete an account.
 //
 // swagger:ignore
@@ -213,6 +234,16 @@ type AccountDeleteRequest struct {
 	Password string `form:"password" json:"password" xml:"password"`
 }
 
+// AccountMoveRequest models a request to move an account.
+//
+// swagger:ignore
+type AccountMoveRequest struct {
+	// Password of the account's user, for confirmation.
+	Password string `form:"password" json:"password" xml:"password"`
+	// ActivityPub URI of the account to move to.
+	MovedToURI string `form:"moved_to_uri" json:"moved_to_uri" xml:"moved_to_uri"`
+}
+
 // AccountRole models the role of an account.
 //
 // swagger:model accountRole
@@ -74,6 +74,18 @@ type AdminAccountInfo struct {
 	InvitedByAccountID string `json:"invited_by_account_id,omitempty"`
 }
 
+// AdminAccountRejectRequest can be submitted along with a POST to /api/v1/admin/accounts/{id}/reject
+//
+// swagger:ignore
+type AdminAccountRejectRequest struct {
+	// Comment to leave on the rejection, visible only to other admins + moderators.
+	PrivateComment string `form:"private_comment" json:"private_comment" xml:"private_comment"`
+	// Send an email to the rejected user to let them know about the rejection.
+	SendEmail bool `form:"send_email" json:"send_email" xml:"send_email"`
+	// Message to include in the rejection email, if send_email is true.
+	Message string `form:"message" json:"message" xml:"message"`
+}
+
 // AdminReport models the admin view of a report.
 //
 // swagger:model adminReport
@@ -117,9 +129,9 @@ type AdminReport struct {
 	// Array of  statuses that were submitted along with this report.
 	// Will be empty if no status IDs were submitted with the report.
 	Statuses []*Status `json:"statuses"`
-	// Array of rule IDs that were submitted along with this report.
-	// NOT IMPLEMENTED, will always be empty array.
-	Rules []interface{} `json:"rule_ids"`
+	// Array of rules that were submitted along with this report.
+	// Will be empty if no rule IDs were submitted with the report.
+	Rules []InstanceRule `json:"rules"`
 	// If an action was taken, what comment was made by the admin on the taken action?
 	// Will be null if not set / no action yet taken.
 	// example: Account was suspended.
@@ -189,3 +201,43 @@ type AdminSendTestEmailRequest struct {
 	// Email address to send the test email to.
 	Email string `form:"email" json:"email" xml:"email"`
 }
+
+// AdminDeliveryQueue models the admin view of the queue of outgoing federation deliveries.
+//
+// swagger:model adminDeliveryQueue
+type AdminDeliveryQueue struct {
+	// Total number of deliveries waiting to be (re)attempted.
+	// example: 42
+	QueueDepth int `json:"queue_depth"`
+	// Domains that deliveries are currently failing to.
+	FailingDomains []*AdminDeliveryFailure `json:"failing_domains"`
+}
+
+// AdminDeliveryFailure models a domain that outgoing federation deliveries are failing to.
+//
+// swagger:model adminDeliveryFailure
+type AdminDeliveryFailure struct {
+	// The domain that deliveries are failing to.
+	// example: example.org
+	Domain string `json:"domain"`
+	// Time of the first failure in the current run of failures (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	FailingSince string `json:"failing_since"`
+	// Number of consecutive failed deliveries to the domain.
+	// example: 12
+	Failures int `json:"failures"`
+	// Error returned by the most recent failed delivery.
+	// example: dial tcp: lookup example.org: no such host
+	LastError string `json:"last_error"`
+	// Whether the domain is considered unreachable, in which
+	// case deliveries to it are held until retry_at.
+	// example: true
+	Unreachable bool `json:"unreachable"`
+	// If the domain is unreachable, the time at which deliveries to it will next be attempted (ISO 8601 Datetime).
+	// Will be null if the domain is not considered unreachable.
+	// example: 2021-07-30T10:20:25+00:00
+	RetryAt *string `json:"retry_at"`
+	// Number of deliveries to the domain waiting to be (re)attempted.
+	// example: 7
+	Queued int `json:"queued"`
+}
@@ -79,3 +79,45 @@ type DomainBlockCreateRequest struct {
 	// public comment on the reason for the domain block
 	PublicComment string `form:"public_comment" json:"public_comment" xml:"public_comment"`
 }
+
+// DomainAllow represents an allow entry for one domain.
+//
+// swagger:model domainAllow
+type DomainAllow struct {
+	Domain
+	// The ID of the domain allow.
+	// example: 01FBW21XJA09XYX51KV5JVBW0F
+	// readonly: true
+	ID string `json:"id,omitempty"`
+	// Obfuscate the domain name when serving this domain allow publicly.
+	// example: false
+	Obfuscate bool `json:"obfuscate,omitempty"`
+	// Private comment for this allow, visible to our instance admins only.
+	// example: they are good people
+	PrivateComment string `json:"private_comment,omitempty"`
+	// The ID of the subscription that created/caused this domain allow.
+	// example: 01FBW25TF5J67JW3HFHZCSD23K
+	SubscriptionID string `json:"subscription_id,omitempty"`
+	// ID of the account that created this domain allow.
+	// example: 01FBW2758ZB6PBR200YPDDJK4C
+	CreatedBy string `json:"created_by,omitempty"`
+	// Time at which this allow was created (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+// DomainAllowCreateRequest is the form submitted as a POST to /api/v1/admin/domain_allows to create a new allow.
+//
+// swagger:model domainAllowCreateRequest
+type DomainAllowCreateRequest struct {
+	// A list of domains to allow. Only used if import=true is specified.
+	Domains *multipart.FileHeader `form:"domains" json:"domains" xml:"domains"`
+	// hostname/domain to allow
+	Domain string `form:"domain" json:"domain" xml:"domain"`
+	// whether the domain should be obfuscated when being displayed publicly
+	Obfuscate bool `form:"obfuscate" json:"obfuscate" xml:"obfuscate"`
+	// private comment for other admins on why the domain was allowed
+	PrivateComment string `form:"private_comment" json:"private_comment" xml:"private_comment"`
+	// public comment on the reason for the domain allow
+	PublicComment string `form:"public_comment" json:"public_comment" xml:"public_comment"`
+}
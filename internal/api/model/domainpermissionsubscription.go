@@ -0,0 +1,104 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+// DomainPermissionSubscription represents an auto-refreshing subscription to a list of domain permissions.
+//
+// swagger:model domainPermissionSubscription
+type DomainPermissionSubscription struct {
+	// The ID of the domain permission subscription.
+	// example: 01FBW21XJA09XYX51KV5JVBW0F
+	// readonly: true
+	ID string `json:"id"`
+	// Priority of this subscription compared to others of the same permission type. 0-255 (higher = higher priority).
+	// example: 100
+	Priority uint8 `json:"priority"`
+	// Moderator-set title for this list.
+	// example: Some List Of Baddies
+	Title string `json:"title"`
+	// The type of domain permission created by this subscription.
+	// enum:
+	//   - block
+	//   - allow
+	// example: block
+	PermissionType string `json:"permission_type"`
+	// If true, domain permissions matching the list which were not created by a subscription will be adopted by this subscription.
+	// example: false
+	AdoptOrphans bool `json:"adopt_orphans"`
+	// ID of the account that created this subscription.
+	// example: 01FBW2758ZB6PBR200YPDDJK4C
+	// readonly: true
+	CreatedBy string `json:"created_by"`
+	// Time at which the subscription was created (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	// readonly: true
+	CreatedAt string `json:"created_at"`
+	// URI to call in order to fetch the permissions list.
+	// example: https://www.example.org/blocklists/list1.csv
+	URI string `json:"uri"`
+	// MIME content type to use when parsing the permissions list.
+	// example: text/csv
+	ContentType string `json:"content_type"`
+	// Time of the most recent fetch attempt (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	// readonly: true
+	FetchedAt string `json:"fetched_at,omitempty"`
+	// Time of the most recent successful fetch (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	// readonly: true
+	SuccessfullyFetchedAt string `json:"successfully_fetched_at,omitempty"`
+	// If the most recent fetch attempt failed, this field will contain an error message related to the fetch attempt.
+	// example: Oopsie doopsie, we made a fucky wucky.
+	// readonly: true
+	Error string `json:"error,omitempty"`
+}
+
+// DomainPermissionSubscriptionCreateRequest is the form submitted as a POST to
+// /api/v1/admin/domain_permission_subscriptions to create a new subscription.
+//
+// swagger:ignore
+type DomainPermissionSubscriptionCreateRequest struct {
+	// Priority of this subscription compared to others of the same permission type. 0-255 (higher = higher priority).
+	Priority *int `form:"priority" json:"priority"`
+	// Moderator-set title for this list.
+	Title string `form:"title" json:"title"`
+	// Type of domain permission created by this subscription, either block or allow.
+	PermissionType string `form:"permission_type" json:"permission_type"`
+	// URI to call in order to fetch the permissions list.
+	URI string `form:"uri" json:"uri"`
+	// MIME content type to use when parsing the permissions list.
+	ContentType string `form:"content_type" json:"content_type"`
+	// Adopt matching domain permissions that were not created by a subscription.
+	AdoptOrphans bool `form:"adopt_orphans" json:"adopt_orphans"`
+}
+
+// DomainPermissionSubscriptionPreview describes the changes that would
+// be made to this instance's domain permissions by syncing a subscription.
+//
+// swagger:model domainPermissionSubscriptionPreview
+type DomainPermissionSubscriptionPreview struct {
+	// Domains for which a new domain permission would be created.
+	// example: ["example.org","baddies.example.com"]
+	Created []string `json:"created"`
+	// Domains for which an existing domain permission would be adopted by the subscription.
+	// example: ["example.net"]
+	Adopted []string `json:"adopted"`
+	// Domains for which an existing domain permission created by the subscription would be removed.
+	// example: ["nice.example.org"]
+	Removed []string `json:"removed"`
+}
@@ -63,8 +63,7 @@ type InstanceV2 struct {
 	//  Hints related to contacting a representative of the instance.
 	Contact InstanceV2Contact `json:"contact"`
 	// An itemized list of rules for this website.
-	// Currently not implemented (will always be empty array).
-	Rules []interface{} `json:"rules"`
+	Rules []InstanceRule `json:"rules"`
 }
 
 // Usage data for this instance.
@@ -138,6 +137,15 @@ type InstanceV2ConfigurationTranslation struct {
 	Enabled bool `json:"enabled"`
 }
 
+// Hints related to Web Push.
+//
+// swagger:model instanceV2ConfigurationVAPID
+type InstanceV2ConfigurationVAPID struct {
+	// The instance's VAPID public key, used for push notification subscriptions.
+	// example: BCkMmVlDiXUTOp0X0Y95WuzeAjr5u8d82jPNUQuL0dYyy-UVbvzk4Xz0Ap8BpJYtwsQfUwRSWLBAOWDBfEIK4Aw
+	PublicKey string `json:"public_key"`
+}
+
 // Configured values and limits for this instance.
 //
 // swagger:model instanceV2Configuration
@@ -156,6 +164,8 @@ type InstanceV2Configuration struct {
 	Translation InstanceV2ConfigurationTranslation `json:"translation"`
 	// Instance configuration pertaining to emojis.
 	Emojis InstanceConfigurationEmojis `json:"emojis"`
+	// Hints related to Web Push.
+	VAPID InstanceV2ConfigurationVAPID `json:"vapid"`
 }
 
 // Information about registering for this instance.
@@ -0,0 +1,67 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+// Invite models an invite link that can be used to sign up to this instance.
+//
+// swagger:model invite
+type Invite struct {
+	// The ID of the invite.
+	// example: 01H9PQ6Y3W6N1DHZ8Y8T3QNMB2
+	ID string `json:"id"`
+	// The code of the invite, to be submitted with a sign-up request.
+	// example: wkTbG4YaQ9cZx1bN
+	Code string `json:"code"`
+	// Link to the sign-up page with this invite.
+	// example: https://example.org/auth/sign_up?invite=wkTbG4YaQ9cZx1bN
+	URL string `json:"url"`
+	// Time at which the invite was created (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	CreatedAt string `json:"created_at"`
+	// Time at which the invite expires (ISO 8601 Datetime), or null if it never expires.
+	// example: 2021-07-31T09:20:25+00:00
+	ExpiresAt *string `json:"expires_at"`
+	// Maximum number of times the invite can be used, or null for unlimited.
+	// example: 5
+	MaxUses *int `json:"max_uses"`
+	// Number of times the invite has been used.
+	// example: 1
+	Uses int `json:"uses"`
+	// Accounts signing up with this invite will automatically follow the creator of the invite.
+	Autofollow bool `json:"autofollow"`
+	// The invite has expired or been used the maximum number of times, and can no longer be used.
+	Expired bool `json:"expired"`
+}
+
+// InviteCreateRequest models invite creation parameters.
+//
+// swagger:parameters inviteCreate
+type InviteCreateRequest struct {
+	// Maximum number of times the invite can be used. 0 or unset for unlimited.
+	// example: 5
+	// in: formData
+	MaxUses int `form:"max_uses" json:"max_uses" xml:"max_uses"`
+	// Number of seconds from now after which the invite expires. 0 or unset for never.
+	// example: 86400
+	// in: formData
+	ExpiresIn int `form:"expires_in" json:"expires_in" xml:"expires_in"`
+	// Accounts signing up with this invite will automatically follow the creator of the invite.
+	// default: false
+	// in: formData
+	Autofollow bool `form:"autofollow" json:"autofollow" xml:"autofollow"`
+}
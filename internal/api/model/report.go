@@ -39,7 +39,8 @@ type Report struct {
 	// example: Account was suspended.
 	ActionTakenComment *string `json:"action_taken_comment"`
 	// Under what category was this report created?
-	// example: spam
+	// "violation" if rules were submitted along with the report, "other" otherwise.
+	// example: violation
 	Category string `json:"category"`
 	// Comment submitted when the report was created.
 	// Will be empty if no comment was submitted.
@@ -54,8 +55,8 @@ type Report struct {
 	StatusIDs []string `json:"status_ids"`
 	// Array of rule IDs that were submitted along with this report.
 	// Will be empty if no rule IDs were submitted.
-	// example: [1, 2]
-	RuleIDs []int `json:"rule_ids"`
+	// example: ["01HA0VEZ3P6Z8T4E1XW8B3YJ5K","01HA0VF8N2JQ6B5V4D7ZC9M1RT"]
+	RuleIDs []string `json:"rule_ids"`
 	// Account that was reported.
 	TargetAccount *Account `json:"target_account"`
 }
@@ -83,14 +84,13 @@ type ReportCreateRequest struct {
 	// in: formData
 	Forward bool `form:"forward" json:"forward" xml:"forward"`
 	// Specify if the report is due to spam, violation of enumerated instance rules, or some other reason.
-	// Currently only 'other' is supported.
+	// Currently ignored: the category is set to 'violation' if rule IDs are given, or 'other' if not.
 	// example: other
 	// default: other
 	// in: formData
 	Category string `form:"category" json:"category" xml:"category"`
 	// IDs of rules on this instance which have been broken according to the reporter.
-	// This is currently not supported, provided only for API compatibility.
-	// example: [1, 2, 3]
+	// example: ["01HA0VEZ3P6Z8T4E1XW8B3YJ5K","01HA0VF8N2JQ6B5V4D7ZC9M1RT"]
 	// in: formData
-	RuleIDs []int `form:"rule_ids[]" json:"rule_ids" xml:"rule_ids"`
+	RuleIDs []string `form:"rule_ids[]" json:"rule_ids" xml:"rule_ids"`
 }
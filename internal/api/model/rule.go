@@ -0,0 +1,69 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+// InstanceRule models one of the rules of this instance.
+//
+// swagger:model instanceRule
+type InstanceRule struct {
+	// The ID of the rule.
+	// example: 01HA0VEZ3P6Z8T4E1XW8B3YJ5K
+	ID string `json:"id"`
+	// Text of the rule.
+	// example: Be nice to each other.
+	Text string `json:"text"`
+}
+
+// AdminInstanceRule models one of the rules of this instance, as seen by an admin.
+//
+// swagger:model adminInstanceRule
+type AdminInstanceRule struct {
+	// The ID of the rule.
+	// example: 01HA0VEZ3P6Z8T4E1XW8B3YJ5K
+	ID string `json:"id"`
+	// Time at which the rule was created (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	CreatedAt string `json:"created_at"`
+	// Time at which the rule was last updated (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	UpdatedAt string `json:"updated_at"`
+	// Text of the rule.
+	// example: Be nice to each other.
+	Text string `json:"text"`
+}
+
+// InstanceRuleCreateRequest models instance rule creation parameters.
+//
+// swagger:parameters ruleCreate
+type InstanceRuleCreateRequest struct {
+	// Text of the rule.
+	// example: Be nice to each other.
+	// in: formData
+	// required: true
+	Text string `form:"text" json:"text" xml:"text"`
+}
+
+// InstanceRuleUpdateRequest models instance rule update parameters.
+//
+// swagger:ignore
+type InstanceRuleUpdateRequest struct {
+	// New text of the rule.
+	Text *string `form:"text" json:"text" xml:"text"`
+	// New position of the rule in the list of rules, starting at 0.
+	Order *int `form:"order" json:"order" xml:"order"`
+}
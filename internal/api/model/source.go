@@ -38,4 +38,7 @@ type Source struct {
 	Fields []Field `json:"fields"`
 	// The number of pending follow requests.
 	FollowRequestsCount int `json:"follow_requests_count"`
+	// URIs of other accounts that this account is also known as (aliases).
+	// Other accounts can only move to this account if they're listed here.
+	AlsoKnownAsURIs []string `json:"also_known_as_uris,omitempty"`
 }
@@ -94,6 +94,13 @@ type Status struct {
 	// The poll attached to the status.
 	// nullable: true
 	Poll *Poll `json:"poll"`
+	// Timestamp of when the status was last edited (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	// nullable: true
+	EditedAt *string `json:"edited_at"`
+	// The filter and keywords that matched this status, if any.
+	// Only set when the status is being filtered in a context with a "warn" action.
+	Filtered []FilterResult `json:"filtered,omitempty"`
 	// Plain-text source of a status. Returned instead of content when status is deleted,
 	// so the user may redraft from the source text without the client having to reverse-engineer
 	// the original text from the HTML content.
@@ -0,0 +1,106 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+// StatusEdit represents one historical revision of a status,
+// containing partial information about the state of the status
+// at that revision.
+//
+// swagger:model statusEdit
+type StatusEdit struct {
+	// The content of this status at this revision.
+	// Should be HTML, but might also be plaintext in some cases.
+	// example: <p>Hey this is a status!</p>
+	Content string `json:"content"`
+	// Subject, summary, or content warning for the status at this revision.
+	// example: warning nsfw
+	SpoilerText string `json:"spoiler_text"`
+	// Status marked sensitive at this revision.
+	// example: false
+	Sensitive bool `json:"sensitive"`
+	// The date when this revision was created (ISO 8601 Datetime).
+	// example: 2021-07-30T09:20:25+00:00
+	CreatedAt string `json:"created_at"`
+	// The account that authored this status.
+	Account *Account `json:"account"`
+	// The poll attached to the status at this revision.
+	// Note that edits changing the poll options will be collapsed together into one edit, since this action resets the poll.
+	// nullable: true
+	Poll *StatusEditPoll `json:"poll"`
+	// Media that is attached to this status.
+	MediaAttachments []Attachment `json:"media_attachments"`
+	// Custom emoji to be used when rendering status content.
+	Emojis []Emoji `json:"emojis"`
+}
+
+// StatusEditPoll represents a poll attached to a status edit.
+//
+// swagger:model statusEditPoll
+type StatusEditPoll struct {
+	// Options being voted on.
+	Options []StatusEditPollOption `json:"options"`
+}
+
+// StatusEditPollOption represents one poll option contained on a status edit.
+//
+// swagger:model statusEditPollOption
+type StatusEditPollOption struct {
+	// The text value of the poll option. String.
+	Title string `json:"title"`
+}
+
+// StatusSource represents the source text of a
+// status as submitted to the API when it was created.
+//
+// swagger:model statusSource
+type StatusSource struct {
+	// ID of the status.
+	// example: 01FBVD42CQ3ZEEVMW180SBX03B
+	ID string `json:"id"`
+	// Plain-text source of a status.
+	Text string `json:"text"`
+	// Plain-text version of spoiler text.
+	SpoilerText string `json:"spoiler_text"`
+}
+
+// StatusEditRequest models status edit parameters.
+//
+// swagger:ignore
+type StatusEditRequest struct {
+	// Text content of the status.
+	// If media_ids is provided, this becomes optional.
+	// Attaching a poll is optional while status is provided.
+	Status string `form:"status" json:"status" xml:"status"`
+	// Text to be shown as a warning or subject before the actual content.
+	// Statuses are generally collapsed behind this field.
+	SpoilerText string `form:"spoiler_text" json:"spoiler_text" xml:"spoiler_text"`
+	// Status and attached media should be marked as sensitive.
+	Sensitive bool `form:"sensitive" json:"sensitive" xml:"sensitive"`
+	// ISO 639 language code for this status.
+	Language string `form:"language" json:"language" xml:"language"`
+	// Content type to use when parsing this status.
+	ContentType StatusContentType `form:"content_type" json:"content_type" xml:"content_type"`
+	// Array of Attachment ids to be attached as media.
+	// If provided, status becomes optional, and poll cannot be used.
+	//
+	// If the status is being submitted as a form, the key is 'media_ids[]',
+	// but if it's json or xml, the key is 'media_ids'.
+	MediaIDs []string `form:"media_ids[]" json:"media_ids" xml:"media_ids"`
+	// Poll to include with this status.
+	Poll *PollRequest `form:"poll" json:"poll" xml:"poll"`
+}
@@ -34,3 +34,59 @@ type PasswordChangeRequest struct {
 	// required: true
 	NewPassword string `form:"new_password" json:"new_password" xml:"new_password" validation:"required"`
 }
+
+// TwoFactor models the two-factor authentication status of a user.
+//
+// swagger:model twoFactor
+type TwoFactor struct {
+	// Time at which two-factor authentication was enabled (ISO 8601 Datetime), or null if it's not enabled.
+	// example: 2021-07-30T09:20:25+00:00
+	EnabledAt *string `json:"enabled_at"`
+}
+
+// TwoFactorSetup models a newly generated two-factor authentication secret.
+//
+// swagger:model twoFactorSetup
+type TwoFactorSetup struct {
+	// Base32-encoded TOTP secret, for entering manually into an authenticator app.
+	// example: JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP
+	Secret string `json:"secret"`
+	// otpauth:// provisioning URI of the secret, for showing as a QR code.
+	// example: otpauth://totp/example.org:some_user@example.org?algorithm=SHA1&digits=6&issuer=example.org&period=30&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP
+	URI string `json:"uri"`
+}
+
+// TwoFactorRecoveryCodes models the recovery codes generated when enabling two-factor authentication.
+//
+// swagger:model twoFactorRecoveryCodes
+type TwoFactorRecoveryCodes struct {
+	// One-time codes that can each be used once to sign in instead of a code from an authenticator app.
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorEnableRequest models two-factor authentication enable parameters.
+//
+// swagger:parameters userTwoFactorEnable
+type TwoFactorEnableRequest struct {
+	// Current code from the authenticator app the secret was added to.
+	//
+	// in: formData
+	// required: true
+	Code string `form:"code" json:"code" xml:"code" validation:"required"`
+}
+
+// TwoFactorDisableRequest models two-factor authentication disable parameters.
+//
+// swagger:parameters userTwoFactorDisable
+type TwoFactorDisableRequest struct {
+	// User's current password.
+	//
+	// in: formData
+	// required: true
+	Password string `form:"password" json:"password" xml:"password" validation:"required"`
+	// Current code from the authenticator app, or one of the recovery codes.
+	//
+	// in: formData
+	// required: true
+	Code string `form:"code" json:"code" xml:"code" validation:"required"`
+}
@@ -0,0 +1,124 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package model
+
+// WebPushSubscription represents a subscription to a Web Push server.
+//
+// swagger:model webPushSubscription
+type WebPushSubscription struct {
+	// The id of the push subscription in the database.
+	ID string `json:"id"`
+	// Where push alerts will be sent to.
+	Endpoint string `json:"endpoint"`
+	// The streaming server's VAPID key.
+	ServerKey string `json:"server_key"`
+	// Which alerts should be delivered to the endpoint.
+	Alerts WebPushSubscriptionAlerts `json:"alerts"`
+	// Which accounts to receive push notifications from.
+	// One of: all, followed, follower, none.
+	Policy string `json:"policy"`
+}
+
+// WebPushSubscriptionAlerts represents the specific
+// alerts that a Web Push subscription is subscribed to.
+//
+// swagger:model webPushSubscriptionAlerts
+type WebPushSubscriptionAlerts struct {
+	// Receive a push notification when someone has followed you?
+	Follow bool `json:"follow"`
+	// Receive a push notification when someone has requested to follow you?
+	FollowRequest bool `json:"follow_request"`
+	// Receive a push notification when a status you created has been favourited by someone else?
+	Favourite bool `json:"favourite"`
+	// Receive a push notification when someone else has mentioned you in a status?
+	Mention bool `json:"mention"`
+	// Receive a push notification when a status you created has been boosted by someone else?
+	Reblog bool `json:"reblog"`
+	// Receive a push notification when a poll you voted in or created has ended?
+	Poll bool `json:"poll"`
+	// Receive a push notification when a subscribed account posts a status?
+	Status bool `json:"status"`
+	// Receive a push notification when a new user has signed up?
+	AdminSignup bool `json:"admin.sign_up"`
+}
+
+// WebPushSubscriptionCreateRequest models a request to create a Web Push subscription.
+// Inner fields carry the nested form keys used by Mastodon clients, so both form data
+// and JSON bodies can be bound into the same struct.
+//
+// swagger:ignore
+type WebPushSubscriptionCreateRequest struct {
+	Subscription WebPushSubscriptionRequestSubscription `form:"subscription" json:"subscription"`
+	Data         WebPushSubscriptionRequestData         `form:"data" json:"data"`
+}
+
+// WebPushSubscriptionUpdateRequest models a request
+// to update the data of a Web Push subscription.
+//
+// swagger:ignore
+type WebPushSubscriptionUpdateRequest struct {
+	Data WebPushSubscriptionRequestData `form:"data" json:"data"`
+}
+
+// WebPushSubscriptionRequestSubscription models the push
+// service details of a Web Push subscription request.
+//
+// swagger:ignore
+type WebPushSubscriptionRequestSubscription struct {
+	// The endpoint URL that is called when a notification event occurs.
+	Endpoint string `form:"subscription[endpoint]" json:"endpoint"`
+	// Keys of the user agent.
+	Keys WebPushSubscriptionRequestKeys `form:"keys" json:"keys"`
+}
+
+// WebPushSubscriptionRequestKeys models the user
+// agent keys of a Web Push subscription request.
+//
+// swagger:ignore
+type WebPushSubscriptionRequestKeys struct {
+	// User agent public key. Base64 encoded string of a public key from a ECDH keypair using the prime256v1 curve.
+	P256dh string `form:"subscription[keys][p256dh]" json:"p256dh"`
+	// Auth secret. Base64 encoded string of 16 bytes of random data.
+	Auth string `form:"subscription[keys][auth]" json:"auth"`
+}
+
+// WebPushSubscriptionRequestData models the alert
+// settings and policy of a Web Push subscription request.
+//
+// swagger:ignore
+type WebPushSubscriptionRequestData struct {
+	Alerts WebPushSubscriptionRequestAlerts `form:"alerts" json:"alerts"`
+	// Which accounts to receive push notifications from.
+	Policy *string `form:"data[policy]" json:"policy"`
+}
+
+// WebPushSubscriptionRequestAlerts models the alerts to
+// set in a Web Push subscription request. Alerts left unset
+// are disabled on create, and left unchanged on update.
+//
+// swagger:ignore
+type WebPushSubscriptionRequestAlerts struct {
+	Follow        *bool `form:"data[alerts][follow]" json:"follow"`
+	FollowRequest *bool `form:"data[alerts][follow_request]" json:"follow_request"`
+	Favourite     *bool `form:"data[alerts][favourite]" json:"favourite"`
+	Mention       *bool `form:"data[alerts][mention]" json:"mention"`
+	Reblog        *bool `form:"data[alerts][reblog]" json:"reblog"`
+	Poll          *bool `form:"data[alerts][poll]" json:"poll"`
+	Status        *bool `form:"data[alerts][status]" json:"status"`
+	AdminSignup   *bool `form:"data[alerts][admin.sign_up]" json:"admin.sign_up"`
+}
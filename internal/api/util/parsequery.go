@@ -64,6 +64,16 @@ const (
 
 	DomainBlockExportKey = "export"
 	DomainBlockImportKey = "import"
+
+	/* Domain allow keys */
+
+	DomainAllowExportKey = "export"
+	DomainAllowImportKey = "import"
+
+	/* Domain permission subscription keys */
+
+	DomainPermissionSubscriptionTypeKey           = "permission_type"
+	DomainPermissionSubscriptionRemoveChildrenKey = "remove_children"
 )
 
 // parseError returns gtserror.WithCode set to 400 Bad Request, to indicate
@@ -129,6 +139,18 @@ func ParseDomainBlockImport(value string, defaultValue bool) (bool, gtserror.Wit
 	return parseBool(value, defaultValue, DomainBlockImportKey)
 }
 
+func ParseDomainAllowExport(value string, defaultValue bool) (bool, gtserror.WithCode) {
+	return parseBool(value, defaultValue, DomainAllowExportKey)
+}
+
+func ParseDomainAllowImport(value string, defaultValue bool) (bool, gtserror.WithCode) {
+	return parseBool(value, defaultValue, DomainAllowImportKey)
+}
+
+func ParseDomainPermissionSubscriptionRemoveChildren(value string, defaultValue bool) (bool, gtserror.WithCode) {
+	return parseBool(value, defaultValue, DomainPermissionSubscriptionRemoveChildrenKey)
+}
+
 /*
 	Parse functions for *REQUIRED* parameters.
 */
@@ -101,6 +101,9 @@ func (c *Caches) setuphooks() {
 
 		// Invalidate this account's block lists.
 		c.GTS.BlockIDs().Invalidate(account.ID)
+
+		// Invalidate this account's mute lists.
+		c.GTS.UserMuteIDs().Invalidate(account.ID)
 	})
 
 	c.GTS.Block().SetInvalidateCallback(func(block *gtsmodel.Block) {
@@ -166,6 +169,12 @@ func (c *Caches) setuphooks() {
 		)
 	})
 
+	c.GTS.Filter().SetInvalidateCallback(func(filter *gtsmodel.Filter) {
+		// Invalidate all cached keywords + statuses of this filter.
+		c.GTS.FilterKeyword().Invalidate("FilterID", filter.ID)
+		c.GTS.FilterStatus().Invalidate("FilterID", filter.ID)
+	})
+
 	c.GTS.List().SetInvalidateCallback(func(list *gtsmodel.List) {
 		// Invalidate all cached entries of this list.
 		c.GTS.ListEntry().Invalidate("ListID", list.ID)
@@ -183,6 +192,14 @@ func (c *Caches) setuphooks() {
 		}
 	})
 
+	c.GTS.PollVote().SetInvalidateCallback(func(vote *gtsmodel.PollVote) {
+		// Invalidate cached poll (contains no. votes).
+		c.GTS.Poll().Invalidate("ID", vote.PollID)
+
+		// Invalidate cached poll vote ID list for this poll.
+		c.GTS.PollVoteIDs().Invalidate(vote.PollID)
+	})
+
 	c.GTS.Status().SetInvalidateCallback(func(status *gtsmodel.Status) {
 		// Invalidate status ID cached visibility.
 		c.Visibility.Invalidate("ItemID", status.ID)
@@ -218,6 +235,14 @@ func (c *Caches) setuphooks() {
 		c.Visibility.Invalidate("ItemID", user.AccountID)
 		c.Visibility.Invalidate("RequesterID", user.AccountID)
 	})
+
+	c.GTS.UserMute().SetInvalidateCallback(func(mute *gtsmodel.UserMute) {
+		// Invalidate mute origin account ID cached visibility.
+		c.Visibility.Invalidate("RequesterID", mute.AccountID)
+
+		// Invalidate source account's mute lists.
+		c.GTS.UserMuteIDs().Invalidate(mute.AccountID)
+	})
 }
 
 // Sweep will sweep all the available caches to ensure none
@@ -231,8 +256,12 @@ func (c *Caches) Sweep(threshold float64) {
 	c.GTS.AccountNote().Trim(threshold)
 	c.GTS.Block().Trim(threshold)
 	c.GTS.BlockIDs().Trim(threshold)
+	c.GTS.Conversation().Trim(threshold)
 	c.GTS.Emoji().Trim(threshold)
 	c.GTS.EmojiCategory().Trim(threshold)
+	c.GTS.Filter().Trim(threshold)
+	c.GTS.FilterKeyword().Trim(threshold)
+	c.GTS.FilterStatus().Trim(threshold)
 	c.GTS.Follow().Trim(threshold)
 	c.GTS.FollowIDs().Trim(threshold)
 	c.GTS.FollowRequest().Trim(threshold)
@@ -244,11 +273,17 @@ func (c *Caches) Sweep(threshold float64) {
 	c.GTS.Media().Trim(threshold)
 	c.GTS.Mention().Trim(threshold)
 	c.GTS.Notification().Trim(threshold)
+	c.GTS.Poll().Trim(threshold)
+	c.GTS.PollVote().Trim(threshold)
+	c.GTS.PollVoteIDs().Trim(threshold)
 	c.GTS.Report().Trim(threshold)
 	c.GTS.Status().Trim(threshold)
+	c.GTS.StatusEdit().Trim(threshold)
 	c.GTS.StatusFave().Trim(threshold)
 	c.GTS.Tag().Trim(threshold)
 	c.GTS.Tombstone().Trim(threshold)
 	c.GTS.User().Trim(threshold)
+	c.GTS.UserMute().Trim(threshold)
+	c.GTS.UserMuteIDs().Trim(threshold)
 	c.Visibility.Trim(threshold)
 }
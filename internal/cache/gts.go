@@ -35,9 +35,14 @@ type GTSCaches struct {
 	block            *result.Cache[*gtsmodel.Block]
 	blockIDs         *SliceCache[string]
 	boostOfIDs       *SliceCache[string]
+	conversation     *result.Cache[*gtsmodel.Conversation]
+	domainAllow      *domain.BlockCache
 	domainBlock      *domain.BlockCache
 	emoji            *result.Cache[*gtsmodel.Emoji]
 	emojiCategory    *result.Cache[*gtsmodel.EmojiCategory]
+	filter           *result.Cache[*gtsmodel.Filter]
+	filterKeyword    *result.Cache[*gtsmodel.FilterKeyword]
+	filterStatus     *result.Cache[*gtsmodel.FilterStatus]
 	follow           *result.Cache[*gtsmodel.Follow]
 	followIDs        *SliceCache[string]
 	followRequest    *result.Cache[*gtsmodel.FollowRequest]
@@ -50,13 +55,19 @@ type GTSCaches struct {
 	media            *result.Cache[*gtsmodel.MediaAttachment]
 	mention          *result.Cache[*gtsmodel.Mention]
 	notification     *result.Cache[*gtsmodel.Notification]
+	poll             *result.Cache[*gtsmodel.Poll]
+	pollVote         *result.Cache[*gtsmodel.PollVote]
+	pollVoteIDs      *SliceCache[string]
 	report           *result.Cache[*gtsmodel.Report]
 	status           *result.Cache[*gtsmodel.Status]
+	statusEdit       *result.Cache[*gtsmodel.StatusEdit]
 	statusFave       *result.Cache[*gtsmodel.StatusFave]
 	statusFaveIDs    *SliceCache[string]
 	tag              *result.Cache[*gtsmodel.Tag]
 	tombstone        *result.Cache[*gtsmodel.Tombstone]
 	user             *result.Cache[*gtsmodel.User]
+	userMute         *result.Cache[*gtsmodel.UserMute]
+	userMuteIDs      *SliceCache[string]
 
 	// TODO: move out of GTS caches since unrelated to DB.
 	webfinger *ttl.Cache[string, string] // TTL=24hr, sweep=5min
@@ -70,9 +81,14 @@ func (c *GTSCaches) Init() {
 	c.initBlock()
 	c.initBlockIDs()
 	c.initBoostOfIDs()
+	c.initConversation()
+	c.initDomainAllow()
 	c.initDomainBlock()
 	c.initEmoji()
 	c.initEmojiCategory()
+	c.initFilter()
+	c.initFilterKeyword()
+	c.initFilterStatus()
 	c.initFollow()
 	c.initFollowIDs()
 	c.initFollowRequest()
@@ -85,13 +101,19 @@ func (c *GTSCaches) Init() {
 	c.initMedia()
 	c.initMention()
 	c.initNotification()
+	c.initPoll()
+	c.initPollVote()
+	c.initPollVoteIDs()
 	c.initReport()
 	c.initStatus()
+	c.initStatusEdit()
 	c.initStatusFave()
 	c.initTag()
 	c.initStatusFaveIDs()
 	c.initTombstone()
 	c.initUser()
+	c.initUserMute()
+	c.initUserMuteIDs()
 	c.initWebfinger()
 }
 
@@ -132,6 +154,16 @@ func (c *GTSCaches) BoostOfIDs() *SliceCache[string] {
 	return c.boostOfIDs
 }
 
+// Conversation provides access to the gtsmodel Conversation database cache.
+func (c *GTSCaches) Conversation() *result.Cache[*gtsmodel.Conversation] {
+	return c.conversation
+}
+
+// DomainAllow provides access to the domain allow database cache.
+func (c *GTSCaches) DomainAllow() *domain.BlockCache {
+	return c.domainAllow
+}
+
 // DomainBlock provides access to the domain block database cache.
 func (c *GTSCaches) DomainBlock() *domain.BlockCache {
 	return c.domainBlock
@@ -147,6 +179,21 @@ func (c *GTSCaches) EmojiCategory() *result.Cache[*gtsmodel.EmojiCategory] {
 	return c.emojiCategory
 }
 
+// Filter provides access to the gtsmodel Filter database cache.
+func (c *GTSCaches) Filter() *result.Cache[*gtsmodel.Filter] {
+	return c.filter
+}
+
+// FilterKeyword provides access to the gtsmodel FilterKeyword database cache.
+func (c *GTSCaches) FilterKeyword() *result.Cache[*gtsmodel.FilterKeyword] {
+	return c.filterKeyword
+}
+
+// FilterStatus provides access to the gtsmodel FilterStatus database cache.
+func (c *GTSCaches) FilterStatus() *result.Cache[*gtsmodel.FilterStatus] {
+	return c.filterStatus
+}
+
 // Follow provides access to the gtsmodel Follow database cache.
 func (c *GTSCaches) Follow() *result.Cache[*gtsmodel.Follow] {
 	return c.follow
@@ -215,6 +262,21 @@ func (c *GTSCaches) Notification() *result.Cache[*gtsmodel.Notification] {
 	return c.notification
 }
 
+// Poll provides access to the gtsmodel Poll database cache.
+func (c *GTSCaches) Poll() *result.Cache[*gtsmodel.Poll] {
+	return c.poll
+}
+
+// PollVote provides access to the gtsmodel PollVote database cache.
+func (c *GTSCaches) PollVote() *result.Cache[*gtsmodel.PollVote] {
+	return c.pollVote
+}
+
+// PollVoteIDs provides access to the poll vote IDs list database cache.
+func (c *GTSCaches) PollVoteIDs() *SliceCache[string] {
+	return c.pollVoteIDs
+}
+
 // Report provides access to the gtsmodel Report database cache.
 func (c *GTSCaches) Report() *result.Cache[*gtsmodel.Report] {
 	return c.report
@@ -225,6 +287,11 @@ func (c *GTSCaches) Status() *result.Cache[*gtsmodel.Status] {
 	return c.status
 }
 
+// StatusEdit provides access to the gtsmodel StatusEdit database cache.
+func (c *GTSCaches) StatusEdit() *result.Cache[*gtsmodel.StatusEdit] {
+	return c.statusEdit
+}
+
 // StatusFave provides access to the gtsmodel StatusFave database cache.
 func (c *GTSCaches) StatusFave() *result.Cache[*gtsmodel.StatusFave] {
 	return c.statusFave
@@ -250,6 +317,16 @@ func (c *GTSCaches) User() *result.Cache[*gtsmodel.User] {
 	return c.user
 }
 
+// UserMute provides access to the gtsmodel UserMute database cache.
+func (c *GTSCaches) UserMute() *result.Cache[*gtsmodel.UserMute] {
+	return c.userMute
+}
+
+// UserMuteIDs provides access to the user mute IDs database cache.
+func (c *GTSCaches) UserMuteIDs() *SliceCache[string] {
+	return c.userMuteIDs
+}
+
 // Webfinger provides access to the webfinger URL cache.
 func (c *GTSCaches) Webfinger() *ttl.Cache[string, string] {
 	return c.webfinger
@@ -355,6 +432,32 @@ func (c *GTSCaches) initBoostOfIDs() {
 	)}
 }
 
+func (c *GTSCaches) initConversation() {
+	// Calculate maximum cache size.
+	cap := calculateResultCacheMax(
+		sizeofConversation(), // model in-mem size.
+		config.GetCacheConversationMemRatio(),
+	)
+
+	log.Infof(nil, "Conversation cache size = %d", cap)
+
+	c.conversation = result.New([]result.Lookup{
+		{Name: "ID"},
+		{Name: "AccountID.ThreadID.OtherAccountsKey"},
+		{Name: "AccountID", Multi: true},
+	}, func(c1 *gtsmodel.Conversation) *gtsmodel.Conversation {
+		c2 := new(gtsmodel.Conversation)
+		*c2 = *c1
+		return c2
+	}, cap)
+
+	c.conversation.IgnoreErrors(ignoreErrors)
+}
+
+func (c *GTSCaches) initDomainAllow() {
+	c.domainAllow = new(domain.BlockCache)
+}
+
 func (c *GTSCaches) initDomainBlock() {
 	c.domainBlock = new(domain.BlockCache)
 }
@@ -404,6 +507,68 @@ func (c *GTSCaches) initEmojiCategory() {
 	c.emojiCategory.IgnoreErrors(ignoreErrors)
 }
 
+func (c *GTSCaches) initFilter() {
+	// Calculate maximum cache size.
+	cap := calculateResultCacheMax(
+		sizeofFilter(), // model in-mem size.
+		config.GetCacheFilterMemRatio(),
+	)
+
+	log.Infof(nil, "Filter cache size = %d", cap)
+
+	c.filter = result.New([]result.Lookup{
+		{Name: "ID"},
+	}, func(f1 *gtsmodel.Filter) *gtsmodel.Filter {
+		f2 := new(gtsmodel.Filter)
+		*f2 = *f1
+		return f2
+	}, cap)
+
+	c.filter.IgnoreErrors(ignoreErrors)
+}
+
+func (c *GTSCaches) initFilterKeyword() {
+	// Calculate maximum cache size.
+	cap := calculateResultCacheMax(
+		sizeofFilterKeyword(), // model in-mem size.
+		config.GetCacheFilterKeywordMemRatio(),
+	)
+
+	log.Infof(nil, "FilterKeyword cache size = %d", cap)
+
+	c.filterKeyword = result.New([]result.Lookup{
+		{Name: "ID"},
+		{Name: "FilterID", Multi: true},
+	}, func(k1 *gtsmodel.FilterKeyword) *gtsmodel.FilterKeyword {
+		k2 := new(gtsmodel.FilterKeyword)
+		*k2 = *k1
+		return k2
+	}, cap)
+
+	c.filterKeyword.IgnoreErrors(ignoreErrors)
+}
+
+func (c *GTSCaches) initFilterStatus() {
+	// Calculate maximum cache size.
+	cap := calculateResultCacheMax(
+		sizeofFilterStatus(), // model in-mem size.
+		config.GetCacheFilterStatusMemRatio(),
+	)
+
+	log.Infof(nil, "FilterStatus cache size = %d", cap)
+
+	c.filterStatus = result.New([]result.Lookup{
+		{Name: "ID"},
+		{Name: "FilterID", Multi: true},
+	}, func(s1 *gtsmodel.FilterStatus) *gtsmodel.FilterStatus {
+		s2 := new(gtsmodel.FilterStatus)
+		*s2 = *s1
+		return s2
+	}, cap)
+
+	c.filterStatus.IgnoreErrors(ignoreErrors)
+}
+
 func (c *GTSCaches) initFollow() {
 	// Calculate maximum cache size.
 	cap := calculateResultCacheMax(
@@ -638,6 +803,63 @@ func (c *GTSCaches) initNotification() {
 	c.notification.IgnoreErrors(ignoreErrors)
 }
 
+func (c *GTSCaches) initPoll() {
+	// Calculate maximum cache size.
+	cap := calculateResultCacheMax(
+		sizeofPoll(), // model in-mem size.
+		config.GetCachePollMemRatio(),
+	)
+
+	log.Infof(nil, "Poll cache size = %d", cap)
+
+	c.poll = result.New([]result.Lookup{
+		{Name: "ID"},
+		{Name: "StatusID"},
+	}, func(p1 *gtsmodel.Poll) *gtsmodel.Poll {
+		p2 := new(gtsmodel.Poll)
+		*p2 = *p1
+		return p2
+	}, cap)
+
+	c.poll.IgnoreErrors(ignoreErrors)
+}
+
+func (c *GTSCaches) initPollVote() {
+	// Calculate maximum cache size.
+	cap := calculateResultCacheMax(
+		sizeofPollVote(), // model in-mem size.
+		config.GetCachePollVoteMemRatio(),
+	)
+
+	log.Infof(nil, "PollVote cache size = %d", cap)
+
+	c.pollVote = result.New([]result.Lookup{
+		{Name: "ID"},
+		{Name: "PollID.AccountID"},
+		{Name: "PollID", Multi: true},
+	}, func(v1 *gtsmodel.PollVote) *gtsmodel.PollVote {
+		v2 := new(gtsmodel.PollVote)
+		*v2 = *v1
+		return v2
+	}, cap)
+
+	c.pollVote.IgnoreErrors(ignoreErrors)
+}
+
+func (c *GTSCaches) initPollVoteIDs() {
+	// Calculate maximum cache size.
+	cap := calculateSliceCacheMax(
+		config.GetCachePollVoteIDsMemRatio(),
+	)
+
+	log.Infof(nil, "PollVote IDs cache size = %d", cap)
+
+	c.pollVoteIDs = &SliceCache[string]{Cache: simple.New[string, []string](
+		0,
+		cap,
+	)}
+}
+
 func (c *GTSCaches) initReport() {
 	// Calculate maximum cache size.
 	cap := calculateResultCacheMax(
@@ -681,6 +903,26 @@ func (c *GTSCaches) initStatus() {
 	c.status.IgnoreErrors(ignoreErrors)
 }
 
+func (c *GTSCaches) initStatusEdit() {
+	// Calculate maximum cache size.
+	cap := calculateResultCacheMax(
+		sizeofStatusEdit(), // model in-mem size.
+		config.GetCacheStatusEditMemRatio(),
+	)
+
+	log.Infof(nil, "StatusEdit cache size = %d", cap)
+
+	c.statusEdit = result.New([]result.Lookup{
+		{Name: "ID"},
+	}, func(e1 *gtsmodel.StatusEdit) *gtsmodel.StatusEdit {
+		e2 := new(gtsmodel.StatusEdit)
+		*e2 = *e1
+		return e2
+	}, cap)
+
+	c.statusEdit.IgnoreErrors(ignoreErrors)
+}
+
 func (c *GTSCaches) initStatusFave() {
 	// Calculate maximum cache size.
 	cap := calculateResultCacheMax(
@@ -773,6 +1015,7 @@ func (c *GTSCaches) initUser() {
 		{Name: "AccountID"},
 		{Name: "Email"},
 		{Name: "ConfirmationToken"},
+		{Name: "ResetPasswordToken"},
 		{Name: "ExternalID"},
 	}, func(u1 *gtsmodel.User) *gtsmodel.User {
 		u2 := new(gtsmodel.User)
@@ -783,6 +1026,43 @@ func (c *GTSCaches) initUser() {
 	c.user.IgnoreErrors(ignoreErrors)
 }
 
+func (c *GTSCaches) initUserMute() {
+	// Calculate maximum cache size.
+	cap := calculateResultCacheMax(
+		sizeofUserMute(), // model in-mem size.
+		config.GetCacheUserMuteMemRatio(),
+	)
+
+	log.Infof(nil, "UserMute cache size = %d", cap)
+
+	c.userMute = result.New([]result.Lookup{
+		{Name: "ID"},
+		{Name: "AccountID.TargetAccountID"},
+		{Name: "AccountID", Multi: true},
+		{Name: "TargetAccountID", Multi: true},
+	}, func(m1 *gtsmodel.UserMute) *gtsmodel.UserMute {
+		m2 := new(gtsmodel.UserMute)
+		*m2 = *m1
+		return m2
+	}, cap)
+
+	c.userMute.IgnoreErrors(ignoreErrors)
+}
+
+func (c *GTSCaches) initUserMuteIDs() {
+	// Calculate maximum cache size.
+	cap := calculateSliceCacheMax(
+		config.GetCacheUserMuteIDsMemRatio(),
+	)
+
+	log.Infof(nil, "UserMute IDs cache size = %d", cap)
+
+	c.userMuteIDs = &SliceCache[string]{Cache: simple.New[string, []string](
+		0,
+		cap,
+	)}
+}
+
 func (c *GTSCaches) initWebfinger() {
 	// Calculate maximum cache size.
 	cap := calculateCacheMax(
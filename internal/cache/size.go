@@ -19,6 +19,7 @@ package cache
 
 import (
 	"crypto/rsa"
+	"strings"
 	"time"
 	"unsafe"
 
@@ -157,8 +158,12 @@ func totalOfRatios() float64 {
 		config.GetCacheAccountNoteMemRatio() +
 		config.GetCacheBlockMemRatio() +
 		config.GetCacheBlockIDsMemRatio() +
+		config.GetCacheConversationMemRatio() +
 		config.GetCacheEmojiMemRatio() +
 		config.GetCacheEmojiCategoryMemRatio() +
+		config.GetCacheFilterMemRatio() +
+		config.GetCacheFilterKeywordMemRatio() +
+		config.GetCacheFilterStatusMemRatio() +
 		config.GetCacheFollowMemRatio() +
 		config.GetCacheFollowIDsMemRatio() +
 		config.GetCacheFollowRequestMemRatio() +
@@ -170,12 +175,18 @@ func totalOfRatios() float64 {
 		config.GetCacheMediaMemRatio() +
 		config.GetCacheMentionMemRatio() +
 		config.GetCacheNotificationMemRatio() +
+		config.GetCachePollMemRatio() +
+		config.GetCachePollVoteMemRatio() +
+		config.GetCachePollVoteIDsMemRatio() +
 		config.GetCacheReportMemRatio() +
 		config.GetCacheStatusMemRatio() +
+		config.GetCacheStatusEditMemRatio() +
 		config.GetCacheStatusFaveMemRatio() +
 		config.GetCacheTagMemRatio() +
 		config.GetCacheTombstoneMemRatio() +
 		config.GetCacheUserMemRatio() +
+		config.GetCacheUserMuteMemRatio() +
+		config.GetCacheUserMuteIDsMemRatio() +
 		config.GetCacheWebfingerMemRatio() +
 		config.GetCacheVisibilityMemRatio()
 }
@@ -239,6 +250,20 @@ func sizeofBlock() uintptr {
 	}))
 }
 
+func sizeofConversation() uintptr {
+	return uintptr(size.Of(&gtsmodel.Conversation{
+		ID:               exampleID,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+		AccountID:        exampleID,
+		OtherAccountIDs:  []string{exampleID, exampleID, exampleID},
+		OtherAccountsKey: strings.Join([]string{exampleID, exampleID, exampleID}, ","),
+		ThreadID:         exampleID,
+		LastStatusID:     exampleID,
+		Read:             func() *bool { ok := true; return &ok }(),
+	}))
+}
+
 func sizeofEmoji() uintptr {
 	return uintptr(size.Of(&gtsmodel.Emoji{
 		ID:                     exampleID,
@@ -272,6 +297,46 @@ func sizeofEmojiCategory() uintptr {
 	}))
 }
 
+func sizeofFilter() uintptr {
+	return uintptr(size.Of(&gtsmodel.Filter{
+		ID:                   exampleID,
+		CreatedAt:            time.Now(),
+		UpdatedAt:            time.Now(),
+		ExpiresAt:            time.Now(),
+		AccountID:            exampleID,
+		Title:                exampleTextSmall,
+		Action:               gtsmodel.FilterActionHide,
+		ContextHome:          func() *bool { ok := true; return &ok }(),
+		ContextNotifications: func() *bool { ok := true; return &ok }(),
+		ContextPublic:        func() *bool { ok := true; return &ok }(),
+		ContextThread:        func() *bool { ok := true; return &ok }(),
+		ContextAccount:       func() *bool { ok := true; return &ok }(),
+	}))
+}
+
+func sizeofFilterKeyword() uintptr {
+	return uintptr(size.Of(&gtsmodel.FilterKeyword{
+		ID:        exampleID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		AccountID: exampleID,
+		FilterID:  exampleID,
+		Keyword:   exampleTextSmall,
+		WholeWord: func() *bool { ok := true; return &ok }(),
+	}))
+}
+
+func sizeofFilterStatus() uintptr {
+	return uintptr(size.Of(&gtsmodel.FilterStatus{
+		ID:        exampleID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		AccountID: exampleID,
+		FilterID:  exampleID,
+		StatusID:  exampleID,
+	}))
+}
+
 func sizeofFollow() uintptr {
 	return uintptr(size.Of(&gtsmodel.Follow{
 		ID:              exampleID,
@@ -403,6 +468,32 @@ func sizeofNotification() uintptr {
 	}))
 }
 
+func sizeofPoll() uintptr {
+	return uintptr(size.Of(&gtsmodel.Poll{
+		ID:         exampleID,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		Multiple:   func() *bool { ok := false; return &ok }(),
+		HideCounts: func() *bool { ok := false; return &ok }(),
+		Options:    []string{exampleTextSmall, exampleTextSmall, exampleTextSmall, exampleTextSmall},
+		Votes:      []int{69, 420, 1337, 1969},
+		Voters:     func() *int { i := 69; return &i }(),
+		StatusID:   exampleID,
+		ExpiresAt:  time.Now(),
+		ClosedAt:   time.Time{},
+	}))
+}
+
+func sizeofPollVote() uintptr {
+	return uintptr(size.Of(&gtsmodel.PollVote{
+		ID:        exampleID,
+		CreatedAt: time.Now(),
+		Choices:   []int{69, 420, 1337},
+		AccountID: exampleID,
+		PollID:    exampleID,
+	}))
+}
+
 func sizeofReport() uintptr {
 	return uintptr(size.Of(&gtsmodel.Report{
 		ID:                     exampleID,
@@ -455,6 +546,22 @@ func sizeofStatus() uintptr {
 	}))
 }
 
+func sizeofStatusEdit() uintptr {
+	return uintptr(size.Of(&gtsmodel.StatusEdit{
+		ID:             exampleID,
+		Content:        exampleText,
+		ContentWarning: exampleUsername, // similar length
+		Text:           exampleText,
+		Language:       "en",
+		Sensitive:      func() *bool { ok := false; return &ok }(),
+		AttachmentIDs:  []string{exampleID, exampleID, exampleID},
+		PollOptions:    []string{exampleTextSmall, exampleTextSmall, exampleTextSmall, exampleTextSmall},
+		PollVotes:      []int{69, 420, 1337, 1969},
+		StatusID:       exampleID,
+		CreatedAt:      time.Now(),
+	}))
+}
+
 func sizeofStatusFave() uintptr {
 	return uintptr(size.Of(&gtsmodel.StatusFave{
 		ID:              exampleID,
@@ -499,3 +606,15 @@ func sizeofVisibility() uintptr {
 func sizeofUser() uintptr {
 	return uintptr(size.Of(&gtsmodel.User{}))
 }
+
+func sizeofUserMute() uintptr {
+	return uintptr(size.Of(&gtsmodel.UserMute{
+		ID:              exampleID,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+		ExpiresAt:       time.Now(),
+		AccountID:       exampleID,
+		TargetAccountID: exampleID,
+		Notifications:   func() *bool { ok := true; return &ok }(),
+	}))
+}
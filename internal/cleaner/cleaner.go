@@ -136,4 +136,17 @@ func scheduleJobs(c *Cleaner) {
 		c.Emoji().All(doneCtx, config.GetMediaRemoteCacheDays())
 		log.Infof(nil, "finished media clean after %s", time.Since(start))
 	}).EveryAt(midnight, day))
+
+	// Schedule removal of expired account mutes every few minutes,
+	// (mute expiry is also checked on read, this just tidies up).
+	c.state.Workers.Scheduler.Schedule(sched.NewJob(func(start time.Time) {
+		n, err := c.state.DB.DeleteExpiredMutes(doneCtx, start)
+		if err != nil {
+			log.Errorf(nil, "error deleting expired mutes: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Infof(nil, "deleted %d expired mutes after %s", n, time.Since(start))
+		}
+	}).Every(5 * time.Minute))
 }
@@ -342,6 +342,23 @@ func (m *Media) pruneUnused(ctx context.Context, media *gtsmodel.MediaAttachment
 		}
 	}
 
+	if media.ScheduledStatusID != "" {
+		// Check whether still attached to a status scheduled for publication.
+		scheduledStatus, err := m.state.DB.GetScheduledStatusByID(ctx, media.ScheduledStatusID)
+		if err != nil && !errors.Is(err, db.ErrNoEntries) {
+			return false, gtserror.Newf("error fetching scheduled status by id %s: %w", media.ScheduledStatusID, err)
+		}
+
+		if scheduledStatus != nil {
+			for _, id := range scheduledStatus.MediaIDs {
+				if id == media.ID {
+					l.Debug("skipping as attached to scheduled status")
+					return false, nil
+				}
+			}
+		}
+	}
+
 	// Check whether we have the required status for media.
 	status, missing, err := m.getRelatedStatus(ctx, media)
 	if err != nil {
@@ -442,6 +459,23 @@ func (m *Media) uncacheRemote(ctx context.Context, after time.Time, media *gtsmo
 		return false, nil
 	}
 
+	if media.ScheduledStatusID != "" {
+		// Check whether still attached to a status scheduled for publication.
+		scheduledStatus, err := m.state.DB.GetScheduledStatusByID(ctx, media.ScheduledStatusID)
+		if err != nil && !errors.Is(err, db.ErrNoEntries) {
+			return false, gtserror.Newf("error fetching scheduled status by id %s: %w", media.ScheduledStatusID, err)
+		}
+
+		if scheduledStatus != nil {
+			for _, id := range scheduledStatus.MediaIDs {
+				if id == media.ID {
+					l.Debug("skipping as attached to scheduled status")
+					return false, nil
+				}
+			}
+		}
+	}
+
 	// Check whether we have the required status for media.
 	status, missing, err := m.getRelatedStatus(ctx, media)
 	if err != nil {
@@ -75,12 +75,13 @@ type Configuration struct {
 	WebTemplateBaseDir string `name:"web-template-base-dir" usage:"Basedir for html templating files for rendering pages and composing emails."`
 	WebAssetBaseDir    string `name:"web-asset-base-dir" usage:"Directory to serve static assets from, accessible at example.org/assets/"`
 
-	InstanceExposePeers            bool `name:"instance-expose-peers" usage:"Allow unauthenticated users to query /api/v1/instance/peers?filter=open"`
-	InstanceExposeSuspended        bool `name:"instance-expose-suspended" usage:"Expose suspended instances via web UI, and allow unauthenticated users to query /api/v1/instance/peers?filter=suspended"`
-	InstanceExposeSuspendedWeb     bool `name:"instance-expose-suspended-web" usage:"Expose list of suspended instances as webpage on /about/suspended"`
-	InstanceExposePublicTimeline   bool `name:"instance-expose-public-timeline" usage:"Allow unauthenticated users to query /api/v1/timelines/public"`
-	InstanceDeliverToSharedInboxes bool `name:"instance-deliver-to-shared-inboxes" usage:"Deliver federated messages to shared inboxes, if they're available."`
-	InstanceInjectMastodonVersion  bool `name:"instance-inject-mastodon-version" usage:"This injects a Mastodon compatible version in /api/v1/instance to help Mastodon clients that use that version for feature detection"`
+	InstanceFederationMode         string `name:"instance-federation-mode" usage:"Set instance federation mode. Options: [blocklist, allowlist]"`
+	InstanceExposePeers            bool   `name:"instance-expose-peers" usage:"Allow unauthenticated users to query /api/v1/instance/peers?filter=open"`
+	InstanceExposeSuspended        bool   `name:"instance-expose-suspended" usage:"Expose suspended instances via web UI, and allow unauthenticated users to query /api/v1/instance/peers?filter=suspended"`
+	InstanceExposeSuspendedWeb     bool   `name:"instance-expose-suspended-web" usage:"Expose list of suspended instances as webpage on /about/suspended"`
+	InstanceExposePublicTimeline   bool   `name:"instance-expose-public-timeline" usage:"Allow unauthenticated users to query /api/v1/timelines/public"`
+	InstanceDeliverToSharedInboxes bool   `name:"instance-deliver-to-shared-inboxes" usage:"Deliver federated messages to shared inboxes, if they're available."`
+	InstanceInjectMastodonVersion  bool   `name:"instance-inject-mastodon-version" usage:"This injects a Mastodon compatible version in /api/v1/instance to help Mastodon clients that use that version for feature detection"`
 
 	AccountsRegistrationOpen bool `name:"accounts-registration-open" usage:"Allow anyone to submit an account signup request. If false, server will be invite-only."`
 	AccountsApprovalRequired bool `name:"accounts-approval-required" usage:"Do account signups require approval by an admin or moderator before user can log in? If false, new registrations will be automatically approved."`
@@ -88,7 +89,13 @@ type Configuration struct {
 	AccountsAllowCustomCSS   bool `name:"accounts-allow-custom-css" usage:"Allow accounts to enable custom CSS for their profile pages and statuses."`
 	AccountsCustomCSSLength  int  `name:"accounts-custom-css-length" usage:"Maximum permitted length (characters) of custom CSS for accounts."`
 
+	AccountsRejectionCooldown time.Duration `name:"accounts-rejection-cooldown" usage:"Duration for which the username and email address of a rejected sign-up cannot be used for a new sign-up. 0 allows immediate reuse."`
+
+	AccountsAllowInvites          bool `name:"accounts-allow-invites" usage:"Allow users who aren't admins or moderators to create invite links. Admins and moderators can always create them."`
+	AccountsInvitesBypassApproval bool `name:"accounts-invites-bypass-approval" usage:"Automatically approve sign-ups made with an invite link, even if accounts-approval-required is true."`
+
 	MediaImageMaxSize        bytesize.Size `name:"media-image-max-size" usage:"Max size of accepted images in bytes"`
+	MediaImageTranscode      bool          `name:"media-image-transcode" usage:"Transcode uploaded images that browsers can't display (e.g. HEIC) to JPEG or PNG. If false, the original is stored as-is."`
 	MediaVideoMaxSize        bytesize.Size `name:"media-video-max-size" usage:"Max size of accepted videos in bytes"`
 	MediaDescriptionMinChars int           `name:"media-description-min-chars" usage:"Min required chars for an image description"`
 	MediaDescriptionMaxChars int           `name:"media-description-max-chars" usage:"Max permitted chars for an image description"`
@@ -151,6 +158,8 @@ type Configuration struct {
 	AdvancedThrottlingRetryAfter time.Duration `name:"advanced-throttling-retry-after" usage:"Retry-After duration response to send for throttled requests."`
 	AdvancedSenderMultiplier     int           `name:"advanced-sender-multiplier" usage:"Multiplier to use per cpu for batching outgoing fedi messages. 0 or less turns batching off (not recommended)."`
 
+	AdvancedPersistWorkerMessages bool `name:"advanced-persist-worker-messages" usage:"Store queued client API and federator worker messages in the database, so that they can be replayed after a restart or crash."`
+
 	// HTTPClient configuration vars.
 	HTTPClient HTTPClientConfiguration `name:"http-client"`
 
@@ -181,8 +190,12 @@ type CacheConfiguration struct {
 	BlockMemRatio            float64       `name:"block-mem-ratio"`
 	BlockIDsMemRatio         float64       `name:"block-mem-ratio"`
 	BoostOfIDsMemRatio       float64       `name:"boost-of-ids-mem-ratio"`
+	ConversationMemRatio     float64       `name:"conversation-mem-ratio"`
 	EmojiMemRatio            float64       `name:"emoji-mem-ratio"`
 	EmojiCategoryMemRatio    float64       `name:"emoji-category-mem-ratio"`
+	FilterMemRatio           float64       `name:"filter-mem-ratio"`
+	FilterKeywordMemRatio    float64       `name:"filter-keyword-mem-ratio"`
+	FilterStatusMemRatio     float64       `name:"filter-status-mem-ratio"`
 	FollowMemRatio           float64       `name:"follow-mem-ratio"`
 	FollowIDsMemRatio        float64       `name:"follow-ids-mem-ratio"`
 	FollowRequestMemRatio    float64       `name:"follow-request-mem-ratio"`
@@ -195,13 +208,19 @@ type CacheConfiguration struct {
 	MediaMemRatio            float64       `name:"media-mem-ratio"`
 	MentionMemRatio          float64       `name:"mention-mem-ratio"`
 	NotificationMemRatio     float64       `name:"notification-mem-ratio"`
+	PollMemRatio             float64       `name:"poll-mem-ratio"`
+	PollVoteMemRatio         float64       `name:"poll-vote-mem-ratio"`
+	PollVoteIDsMemRatio      float64       `name:"poll-vote-ids-mem-ratio"`
 	ReportMemRatio           float64       `name:"report-mem-ratio"`
 	StatusMemRatio           float64       `name:"status-mem-ratio"`
+	StatusEditMemRatio       float64       `name:"status-edit-mem-ratio"`
 	StatusFaveMemRatio       float64       `name:"status-fave-mem-ratio"`
 	StatusFaveIDsMemRatio    float64       `name:"status-fave-ids-mem-ratio"`
 	TagMemRatio              float64       `name:"tag-mem-ratio"`
 	TombstoneMemRatio        float64       `name:"tombstone-mem-ratio"`
 	UserMemRatio             float64       `name:"user-mem-ratio"`
+	UserMuteMemRatio         float64       `name:"user-mute-mem-ratio"`
+	UserMuteIDsMemRatio      float64       `name:"user-mute-ids-mem-ratio"`
 	WebfingerMemRatio        float64       `name:"webfinger-mem-ratio"`
 	VisibilityMemRatio       float64       `name:"visibility-mem-ratio"`
 }
@@ -56,6 +56,7 @@ var Defaults = Configuration{
 	WebTemplateBaseDir: "./web/template/",
 	WebAssetBaseDir:    "./web/assets/",
 
+	InstanceFederationMode:         InstanceFederationModeDefault,
 	InstanceExposePeers:            false,
 	InstanceExposeSuspended:        false,
 	InstanceExposeSuspendedWeb:     false,
@@ -67,7 +68,13 @@ var Defaults = Configuration{
 	AccountsAllowCustomCSS:   false,
 	AccountsCustomCSSLength:  10000,
 
+	AccountsRejectionCooldown: 7 * 24 * time.Hour, // 1 week
+
+	AccountsAllowInvites:          false,
+	AccountsInvitesBypassApproval: false,
+
 	MediaImageMaxSize:        10 * bytesize.MiB,
+	MediaImageTranscode:      true,
 	MediaVideoMaxSize:        40 * bytesize.MiB,
 	MediaDescriptionMinChars: 0,
 	MediaDescriptionMaxChars: 500,
@@ -125,6 +132,8 @@ var Defaults = Configuration{
 	AdvancedThrottlingRetryAfter: time.Second * 30,
 	AdvancedSenderMultiplier:     2, // 2 senders per CPU
 
+	AdvancedPersistWorkerMessages: false,
+
 	Cache: CacheConfiguration{
 		// Rough memory target that the total
 		// size of all State.Caches will attempt
@@ -150,8 +159,12 @@ var Defaults = Configuration{
 		BlockMemRatio:            3,
 		BlockIDsMemRatio:         3,
 		BoostOfIDsMemRatio:       3,
+		ConversationMemRatio:     1,
 		EmojiMemRatio:            3,
 		EmojiCategoryMemRatio:    0.1,
+		FilterMemRatio:           0.5,
+		FilterKeywordMemRatio:    0.5,
+		FilterStatusMemRatio:     0.5,
 		FollowMemRatio:           4,
 		FollowIDsMemRatio:        4,
 		FollowRequestMemRatio:    2,
@@ -164,13 +177,19 @@ var Defaults = Configuration{
 		MediaMemRatio:            4,
 		MentionMemRatio:          5,
 		NotificationMemRatio:     5,
+		PollMemRatio:             2,
+		PollVoteMemRatio:         2,
+		PollVoteIDsMemRatio:      2,
 		ReportMemRatio:           1,
 		StatusMemRatio:           18,
+		StatusEditMemRatio:       2,
 		StatusFaveMemRatio:       5,
 		StatusFaveIDsMemRatio:    3,
 		TagMemRatio:              3,
 		TombstoneMemRatio:        2,
 		UserMemRatio:             0.1,
+		UserMuteMemRatio:         2,
+		UserMuteIDsMemRatio:      3,
 		WebfingerMemRatio:        0.1,
 		VisibilityMemRatio:       2,
 	},
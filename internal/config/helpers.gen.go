@@ -724,6 +724,31 @@ func GetWebAssetBaseDir() string { return global.GetWebAssetBaseDir() }
 // SetWebAssetBaseDir safely sets the value for global configuration 'WebAssetBaseDir' field
 func SetWebAssetBaseDir(v string) { global.SetWebAssetBaseDir(v) }
 
+// GetInstanceFederationMode safely fetches the Configuration value for state's 'InstanceFederationMode' field
+func (st *ConfigState) GetInstanceFederationMode() (v string) {
+	st.mutex.RLock()
+	v = st.config.InstanceFederationMode
+	st.mutex.RUnlock()
+	return
+}
+
+// SetInstanceFederationMode safely sets the Configuration value for state's 'InstanceFederationMode' field
+func (st *ConfigState) SetInstanceFederationMode(v string) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.InstanceFederationMode = v
+	st.reloadToViper()
+}
+
+// InstanceFederationModeFlag returns the flag name for the 'InstanceFederationMode' field
+func InstanceFederationModeFlag() string { return "instance-federation-mode" }
+
+// GetInstanceFederationMode safely fetches the value for global configuration 'InstanceFederationMode' field
+func GetInstanceFederationMode() string { return global.GetInstanceFederationMode() }
+
+// SetInstanceFederationMode safely sets the value for global configuration 'InstanceFederationMode' field
+func SetInstanceFederationMode(v string) { global.SetInstanceFederationMode(v) }
+
 // GetInstanceExposePeers safely fetches the Configuration value for state's 'InstanceExposePeers' field
 func (st *ConfigState) GetInstanceExposePeers() (v bool) {
 	st.mutex.RLock()
@@ -999,6 +1024,81 @@ func GetAccountsCustomCSSLength() int { return global.GetAccountsCustomCSSLength
 // SetAccountsCustomCSSLength safely sets the value for global configuration 'AccountsCustomCSSLength' field
 func SetAccountsCustomCSSLength(v int) { global.SetAccountsCustomCSSLength(v) }
 
+// GetAccountsRejectionCooldown safely fetches the Configuration value for state's 'AccountsRejectionCooldown' field
+func (st *ConfigState) GetAccountsRejectionCooldown() (v time.Duration) {
+	st.mutex.RLock()
+	v = st.config.AccountsRejectionCooldown
+	st.mutex.RUnlock()
+	return
+}
+
+// SetAccountsRejectionCooldown safely sets the Configuration value for state's 'AccountsRejectionCooldown' field
+func (st *ConfigState) SetAccountsRejectionCooldown(v time.Duration) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.AccountsRejectionCooldown = v
+	st.reloadToViper()
+}
+
+// AccountsRejectionCooldownFlag returns the flag name for the 'AccountsRejectionCooldown' field
+func AccountsRejectionCooldownFlag() string { return "accounts-rejection-cooldown" }
+
+// GetAccountsRejectionCooldown safely fetches the value for global configuration 'AccountsRejectionCooldown' field
+func GetAccountsRejectionCooldown() time.Duration { return global.GetAccountsRejectionCooldown() }
+
+// SetAccountsRejectionCooldown safely sets the value for global configuration 'AccountsRejectionCooldown' field
+func SetAccountsRejectionCooldown(v time.Duration) { global.SetAccountsRejectionCooldown(v) }
+
+// GetAccountsAllowInvites safely fetches the Configuration value for state's 'AccountsAllowInvites' field
+func (st *ConfigState) GetAccountsAllowInvites() (v bool) {
+	st.mutex.RLock()
+	v = st.config.AccountsAllowInvites
+	st.mutex.RUnlock()
+	return
+}
+
+// SetAccountsAllowInvites safely sets the Configuration value for state's 'AccountsAllowInvites' field
+func (st *ConfigState) SetAccountsAllowInvites(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.AccountsAllowInvites = v
+	st.reloadToViper()
+}
+
+// AccountsAllowInvitesFlag returns the flag name for the 'AccountsAllowInvites' field
+func AccountsAllowInvitesFlag() string { return "accounts-allow-invites" }
+
+// GetAccountsAllowInvites safely fetches the value for global configuration 'AccountsAllowInvites' field
+func GetAccountsAllowInvites() bool { return global.GetAccountsAllowInvites() }
+
+// SetAccountsAllowInvites safely sets the value for global configuration 'AccountsAllowInvites' field
+func SetAccountsAllowInvites(v bool) { global.SetAccountsAllowInvites(v) }
+
+// GetAccountsInvitesBypassApproval safely fetches the Configuration value for state's 'AccountsInvitesBypassApproval' field
+func (st *ConfigState) GetAccountsInvitesBypassApproval() (v bool) {
+	st.mutex.RLock()
+	v = st.config.AccountsInvitesBypassApproval
+	st.mutex.RUnlock()
+	return
+}
+
+// SetAccountsInvitesBypassApproval safely sets the Configuration value for state's 'AccountsInvitesBypassApproval' field
+func (st *ConfigState) SetAccountsInvitesBypassApproval(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.AccountsInvitesBypassApproval = v
+	st.reloadToViper()
+}
+
+// AccountsInvitesBypassApprovalFlag returns the flag name for the 'AccountsInvitesBypassApproval' field
+func AccountsInvitesBypassApprovalFlag() string { return "accounts-invites-bypass-approval" }
+
+// GetAccountsInvitesBypassApproval safely fetches the value for global configuration 'AccountsInvitesBypassApproval' field
+func GetAccountsInvitesBypassApproval() bool { return global.GetAccountsInvitesBypassApproval() }
+
+// SetAccountsInvitesBypassApproval safely sets the value for global configuration 'AccountsInvitesBypassApproval' field
+func SetAccountsInvitesBypassApproval(v bool) { global.SetAccountsInvitesBypassApproval(v) }
+
 // GetMediaImageMaxSize safely fetches the Configuration value for state's 'MediaImageMaxSize' field
 func (st *ConfigState) GetMediaImageMaxSize() (v bytesize.Size) {
 	st.mutex.RLock()
@@ -1024,6 +1124,31 @@ func GetMediaImageMaxSize() bytesize.Size { return global.GetMediaImageMaxSize()
 // SetMediaImageMaxSize safely sets the value for global configuration 'MediaImageMaxSize' field
 func SetMediaImageMaxSize(v bytesize.Size) { global.SetMediaImageMaxSize(v) }
 
+// GetMediaImageTranscode safely fetches the Configuration value for state's 'MediaImageTranscode' field
+func (st *ConfigState) GetMediaImageTranscode() (v bool) {
+	st.mutex.RLock()
+	v = st.config.MediaImageTranscode
+	st.mutex.RUnlock()
+	return
+}
+
+// SetMediaImageTranscode safely sets the Configuration value for state's 'MediaImageTranscode' field
+func (st *ConfigState) SetMediaImageTranscode(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.MediaImageTranscode = v
+	st.reloadToViper()
+}
+
+// MediaImageTranscodeFlag returns the flag name for the 'MediaImageTranscode' field
+func MediaImageTranscodeFlag() string { return "media-image-transcode" }
+
+// GetMediaImageTranscode safely fetches the value for global configuration 'MediaImageTranscode' field
+func GetMediaImageTranscode() bool { return global.GetMediaImageTranscode() }
+
+// SetMediaImageTranscode safely sets the value for global configuration 'MediaImageTranscode' field
+func SetMediaImageTranscode(v bool) { global.SetMediaImageTranscode(v) }
+
 // GetMediaVideoMaxSize safely fetches the Configuration value for state's 'MediaVideoMaxSize' field
 func (st *ConfigState) GetMediaVideoMaxSize() (v bytesize.Size) {
 	st.mutex.RLock()
@@ -2324,6 +2449,31 @@ func GetAdvancedSenderMultiplier() int { return global.GetAdvancedSenderMultipli
 // SetAdvancedSenderMultiplier safely sets the value for global configuration 'AdvancedSenderMultiplier' field
 func SetAdvancedSenderMultiplier(v int) { global.SetAdvancedSenderMultiplier(v) }
 
+// GetAdvancedPersistWorkerMessages safely fetches the Configuration value for state's 'AdvancedPersistWorkerMessages' field
+func (st *ConfigState) GetAdvancedPersistWorkerMessages() (v bool) {
+	st.mutex.RLock()
+	v = st.config.AdvancedPersistWorkerMessages
+	st.mutex.RUnlock()
+	return
+}
+
+// SetAdvancedPersistWorkerMessages safely sets the Configuration value for state's 'AdvancedPersistWorkerMessages' field
+func (st *ConfigState) SetAdvancedPersistWorkerMessages(v bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.AdvancedPersistWorkerMessages = v
+	st.reloadToViper()
+}
+
+// AdvancedPersistWorkerMessagesFlag returns the flag name for the 'AdvancedPersistWorkerMessages' field
+func AdvancedPersistWorkerMessagesFlag() string { return "advanced-persist-worker-messages" }
+
+// GetAdvancedPersistWorkerMessages safely fetches the value for global configuration 'AdvancedPersistWorkerMessages' field
+func GetAdvancedPersistWorkerMessages() bool { return global.GetAdvancedPersistWorkerMessages() }
+
+// SetAdvancedPersistWorkerMessages safely sets the value for global configuration 'AdvancedPersistWorkerMessages' field
+func SetAdvancedPersistWorkerMessages(v bool) { global.SetAdvancedPersistWorkerMessages(v) }
+
 // GetHTTPClientAllowIPs safely fetches the Configuration value for state's 'HTTPClient.AllowIPs' field
 func (st *ConfigState) GetHTTPClientAllowIPs() (v []string) {
 	st.mutex.RLock()
@@ -2574,6 +2724,31 @@ func GetCacheBoostOfIDsMemRatio() float64 { return global.GetCacheBoostOfIDsMemR
 // SetCacheBoostOfIDsMemRatio safely sets the value for global configuration 'Cache.BoostOfIDsMemRatio' field
 func SetCacheBoostOfIDsMemRatio(v float64) { global.SetCacheBoostOfIDsMemRatio(v) }
 
+// GetCacheConversationMemRatio safely fetches the Configuration value for state's 'Cache.ConversationMemRatio' field
+func (st *ConfigState) GetCacheConversationMemRatio() (v float64) {
+	st.mutex.RLock()
+	v = st.config.Cache.ConversationMemRatio
+	st.mutex.RUnlock()
+	return
+}
+
+// SetCacheConversationMemRatio safely sets the Configuration value for state's 'Cache.ConversationMemRatio' field
+func (st *ConfigState) SetCacheConversationMemRatio(v float64) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.Cache.ConversationMemRatio = v
+	st.reloadToViper()
+}
+
+// CacheConversationMemRatioFlag returns the flag name for the 'Cache.ConversationMemRatio' field
+func CacheConversationMemRatioFlag() string { return "cache-conversation-mem-ratio" }
+
+// GetCacheConversationMemRatio safely fetches the value for global configuration 'Cache.ConversationMemRatio' field
+func GetCacheConversationMemRatio() float64 { return global.GetCacheConversationMemRatio() }
+
+// SetCacheConversationMemRatio safely sets the value for global configuration 'Cache.ConversationMemRatio' field
+func SetCacheConversationMemRatio(v float64) { global.SetCacheConversationMemRatio(v) }
+
 // GetCacheEmojiMemRatio safely fetches the Configuration value for state's 'Cache.EmojiMemRatio' field
 func (st *ConfigState) GetCacheEmojiMemRatio() (v float64) {
 	st.mutex.RLock()
@@ -2624,6 +2799,81 @@ func GetCacheEmojiCategoryMemRatio() float64 { return global.GetCacheEmojiCatego
 // SetCacheEmojiCategoryMemRatio safely sets the value for global configuration 'Cache.EmojiCategoryMemRatio' field
 func SetCacheEmojiCategoryMemRatio(v float64) { global.SetCacheEmojiCategoryMemRatio(v) }
 
+// GetCacheFilterMemRatio safely fetches the Configuration value for state's 'Cache.FilterMemRatio' field
+func (st *ConfigState) GetCacheFilterMemRatio() (v float64) {
+	st.mutex.RLock()
+	v = st.config.Cache.FilterMemRatio
+	st.mutex.RUnlock()
+	return
+}
+
+// SetCacheFilterMemRatio safely sets the Configuration value for state's 'Cache.FilterMemRatio' field
+func (st *ConfigState) SetCacheFilterMemRatio(v float64) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.Cache.FilterMemRatio = v
+	st.reloadToViper()
+}
+
+// CacheFilterMemRatioFlag returns the flag name for the 'Cache.FilterMemRatio' field
+func CacheFilterMemRatioFlag() string { return "cache-filter-mem-ratio" }
+
+// GetCacheFilterMemRatio safely fetches the value for global configuration 'Cache.FilterMemRatio' field
+func GetCacheFilterMemRatio() float64 { return global.GetCacheFilterMemRatio() }
+
+// SetCacheFilterMemRatio safely sets the value for global configuration 'Cache.FilterMemRatio' field
+func SetCacheFilterMemRatio(v float64) { global.SetCacheFilterMemRatio(v) }
+
+// GetCacheFilterKeywordMemRatio safely fetches the Configuration value for state's 'Cache.FilterKeywordMemRatio' field
+func (st *ConfigState) GetCacheFilterKeywordMemRatio() (v float64) {
+	st.mutex.RLock()
+	v = st.config.Cache.FilterKeywordMemRatio
+	st.mutex.RUnlock()
+	return
+}
+
+// SetCacheFilterKeywordMemRatio safely sets the Configuration value for state's 'Cache.FilterKeywordMemRatio' field
+func (st *ConfigState) SetCacheFilterKeywordMemRatio(v float64) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.Cache.FilterKeywordMemRatio = v
+	st.reloadToViper()
+}
+
+// CacheFilterKeywordMemRatioFlag returns the flag name for the 'Cache.FilterKeywordMemRatio' field
+func CacheFilterKeywordMemRatioFlag() string { return "cache-filter-keyword-mem-ratio" }
+
+// GetCacheFilterKeywordMemRatio safely fetches the value for global configuration 'Cache.FilterKeywordMemRatio' field
+func GetCacheFilterKeywordMemRatio() float64 { return global.GetCacheFilterKeywordMemRatio() }
+
+// SetCacheFilterKeywordMemRatio safely sets the value for global configuration 'Cache.FilterKeywordMemRatio' field
+func SetCacheFilterKeywordMemRatio(v float64) { global.SetCacheFilterKeywordMemRatio(v) }
+
+// GetCacheFilterStatusMemRatio safely fetches the Configuration value for state's 'Cache.FilterStatusMemRatio' field
+func (st *ConfigState) GetCacheFilterStatusMemRatio() (v float64) {
+	st.mutex.RLock()
+	v = st.config.Cache.FilterStatusMemRatio
+	st.mutex.RUnlock()
+	return
+}
+
+// SetCacheFilterStatusMemRatio safely sets the Configuration value for state's 'Cache.FilterStatusMemRatio' field
+func (st *ConfigState) SetCacheFilterStatusMemRatio(v float64) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.Cache.FilterStatusMemRatio = v
+	st.reloadToViper()
+}
+
+// CacheFilterStatusMemRatioFlag returns the flag name for the 'Cache.FilterStatusMemRatio' field
+func CacheFilterStatusMemRatioFlag() string { return "cache-filter-status-mem-ratio" }
+
+// GetCacheFilterStatusMemRatio safely fetches the value for global configuration 'Cache.FilterStatusMemRatio' field
+func GetCacheFilterStatusMemRatio() float64 { return global.GetCacheFilterStatusMemRatio() }
+
+// SetCacheFilterStatusMemRatio safely sets the value for global configuration 'Cache.FilterStatusMemRatio' field
+func SetCacheFilterStatusMemRatio(v float64) { global.SetCacheFilterStatusMemRatio(v) }
+
 // GetCacheFollowMemRatio safely fetches the Configuration value for state's 'Cache.FollowMemRatio' field
 func (st *ConfigState) GetCacheFollowMemRatio() (v float64) {
 	st.mutex.RLock()
@@ -2924,6 +3174,81 @@ func GetCacheNotificationMemRatio() float64 { return global.GetCacheNotification
 // SetCacheNotificationMemRatio safely sets the value for global configuration 'Cache.NotificationMemRatio' field
 func SetCacheNotificationMemRatio(v float64) { global.SetCacheNotificationMemRatio(v) }
 
+// GetCachePollMemRatio safely fetches the Configuration value for state's 'Cache.PollMemRatio' field
+func (st *ConfigState) GetCachePollMemRatio() (v float64) {
+	st.mutex.RLock()
+	v = st.config.Cache.PollMemRatio
+	st.mutex.RUnlock()
+	return
+}
+
+// SetCachePollMemRatio safely sets the Configuration value for state's 'Cache.PollMemRatio' field
+func (st *ConfigState) SetCachePollMemRatio(v float64) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.Cache.PollMemRatio = v
+	st.reloadToViper()
+}
+
+// CachePollMemRatioFlag returns the flag name for the 'Cache.PollMemRatio' field
+func CachePollMemRatioFlag() string { return "cache-poll-mem-ratio" }
+
+// GetCachePollMemRatio safely fetches the value for global configuration 'Cache.PollMemRatio' field
+func GetCachePollMemRatio() float64 { return global.GetCachePollMemRatio() }
+
+// SetCachePollMemRatio safely sets the value for global configuration 'Cache.PollMemRatio' field
+func SetCachePollMemRatio(v float64) { global.SetCachePollMemRatio(v) }
+
+// GetCachePollVoteMemRatio safely fetches the Configuration value for state's 'Cache.PollVoteMemRatio' field
+func (st *ConfigState) GetCachePollVoteMemRatio() (v float64) {
+	st.mutex.RLock()
+	v = st.config.Cache.PollVoteMemRatio
+	st.mutex.RUnlock()
+	return
+}
+
+// SetCachePollVoteMemRatio safely sets the Configuration value for state's 'Cache.PollVoteMemRatio' field
+func (st *ConfigState) SetCachePollVoteMemRatio(v float64) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.Cache.PollVoteMemRatio = v
+	st.reloadToViper()
+}
+
+// CachePollVoteMemRatioFlag returns the flag name for the 'Cache.PollVoteMemRatio' field
+func CachePollVoteMemRatioFlag() string { return "cache-poll-vote-mem-ratio" }
+
+// GetCachePollVoteMemRatio safely fetches the value for global configuration 'Cache.PollVoteMemRatio' field
+func GetCachePollVoteMemRatio() float64 { return global.GetCachePollVoteMemRatio() }
+
+// SetCachePollVoteMemRatio safely sets the value for global configuration 'Cache.PollVoteMemRatio' field
+func SetCachePollVoteMemRatio(v float64) { global.SetCachePollVoteMemRatio(v) }
+
+// GetCachePollVoteIDsMemRatio safely fetches the Configuration value for state's 'Cache.PollVoteIDsMemRatio' field
+func (st *ConfigState) GetCachePollVoteIDsMemRatio() (v float64) {
+	st.mutex.RLock()
+	v = st.config.Cache.PollVoteIDsMemRatio
+	st.mutex.RUnlock()
+	return
+}
+
+// SetCachePollVoteIDsMemRatio safely sets the Configuration value for state's 'Cache.PollVoteIDsMemRatio' field
+func (st *ConfigState) SetCachePollVoteIDsMemRatio(v float64) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.Cache.PollVoteIDsMemRatio = v
+	st.reloadToViper()
+}
+
+// CachePollVoteIDsMemRatioFlag returns the flag name for the 'Cache.PollVoteIDsMemRatio' field
+func CachePollVoteIDsMemRatioFlag() string { return "cache-poll-vote-ids-mem-ratio" }
+
+// GetCachePollVoteIDsMemRatio safely fetches the value for global configuration 'Cache.PollVoteIDsMemRatio' field
+func GetCachePollVoteIDsMemRatio() float64 { return global.GetCachePollVoteIDsMemRatio() }
+
+// SetCachePollVoteIDsMemRatio safely sets the value for global configuration 'Cache.PollVoteIDsMemRatio' field
+func SetCachePollVoteIDsMemRatio(v float64) { global.SetCachePollVoteIDsMemRatio(v) }
+
 // GetCacheReportMemRatio safely fetches the Configuration value for state's 'Cache.ReportMemRatio' field
 func (st *ConfigState) GetCacheReportMemRatio() (v float64) {
 	st.mutex.RLock()
@@ -2974,6 +3299,31 @@ func GetCacheStatusMemRatio() float64 { return global.GetCacheStatusMemRatio() }
 // SetCacheStatusMemRatio safely sets the value for global configuration 'Cache.StatusMemRatio' field
 func SetCacheStatusMemRatio(v float64) { global.SetCacheStatusMemRatio(v) }
 
+// GetCacheStatusEditMemRatio safely fetches the Configuration value for state's 'Cache.StatusEditMemRatio' field
+func (st *ConfigState) GetCacheStatusEditMemRatio() (v float64) {
+	st.mutex.RLock()
+	v = st.config.Cache.StatusEditMemRatio
+	st.mutex.RUnlock()
+	return
+}
+
+// SetCacheStatusEditMemRatio safely sets the Configuration value for state's 'Cache.StatusEditMemRatio' field
+func (st *ConfigState) SetCacheStatusEditMemRatio(v float64) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.Cache.StatusEditMemRatio = v
+	st.reloadToViper()
+}
+
+// CacheStatusEditMemRatioFlag returns the flag name for the 'Cache.StatusEditMemRatio' field
+func CacheStatusEditMemRatioFlag() string { return "cache-status-edit-mem-ratio" }
+
+// GetCacheStatusEditMemRatio safely fetches the value for global configuration 'Cache.StatusEditMemRatio' field
+func GetCacheStatusEditMemRatio() float64 { return global.GetCacheStatusEditMemRatio() }
+
+// SetCacheStatusEditMemRatio safely sets the value for global configuration 'Cache.StatusEditMemRatio' field
+func SetCacheStatusEditMemRatio(v float64) { global.SetCacheStatusEditMemRatio(v) }
+
 // GetCacheStatusFaveMemRatio safely fetches the Configuration value for state's 'Cache.StatusFaveMemRatio' field
 func (st *ConfigState) GetCacheStatusFaveMemRatio() (v float64) {
 	st.mutex.RLock()
@@ -3099,6 +3449,56 @@ func GetCacheUserMemRatio() float64 { return global.GetCacheUserMemRatio() }
 // SetCacheUserMemRatio safely sets the value for global configuration 'Cache.UserMemRatio' field
 func SetCacheUserMemRatio(v float64) { global.SetCacheUserMemRatio(v) }
 
+// GetCacheUserMuteMemRatio safely fetches the Configuration value for state's 'Cache.UserMuteMemRatio' field
+func (st *ConfigState) GetCacheUserMuteMemRatio() (v float64) {
+	st.mutex.RLock()
+	v = st.config.Cache.UserMuteMemRatio
+	st.mutex.RUnlock()
+	return
+}
+
+// SetCacheUserMuteMemRatio safely sets the Configuration value for state's 'Cache.UserMuteMemRatio' field
+func (st *ConfigState) SetCacheUserMuteMemRatio(v float64) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.Cache.UserMuteMemRatio = v
+	st.reloadToViper()
+}
+
+// CacheUserMuteMemRatioFlag returns the flag name for the 'Cache.UserMuteMemRatio' field
+func CacheUserMuteMemRatioFlag() string { return "cache-user-mute-mem-ratio" }
+
+// GetCacheUserMuteMemRatio safely fetches the value for global configuration 'Cache.UserMuteMemRatio' field
+func GetCacheUserMuteMemRatio() float64 { return global.GetCacheUserMuteMemRatio() }
+
+// SetCacheUserMuteMemRatio safely sets the value for global configuration 'Cache.UserMuteMemRatio' field
+func SetCacheUserMuteMemRatio(v float64) { global.SetCacheUserMuteMemRatio(v) }
+
+// GetCacheUserMuteIDsMemRatio safely fetches the Configuration value for state's 'Cache.UserMuteIDsMemRatio' field
+func (st *ConfigState) GetCacheUserMuteIDsMemRatio() (v float64) {
+	st.mutex.RLock()
+	v = st.config.Cache.UserMuteIDsMemRatio
+	st.mutex.RUnlock()
+	return
+}
+
+// SetCacheUserMuteIDsMemRatio safely sets the Configuration value for state's 'Cache.UserMuteIDsMemRatio' field
+func (st *ConfigState) SetCacheUserMuteIDsMemRatio(v float64) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	st.config.Cache.UserMuteIDsMemRatio = v
+	st.reloadToViper()
+}
+
+// CacheUserMuteIDsMemRatioFlag returns the flag name for the 'Cache.UserMuteIDsMemRatio' field
+func CacheUserMuteIDsMemRatioFlag() string { return "cache-user-mute-ids-mem-ratio" }
+
+// GetCacheUserMuteIDsMemRatio safely fetches the value for global configuration 'Cache.UserMuteIDsMemRatio' field
+func GetCacheUserMuteIDsMemRatio() float64 { return global.GetCacheUserMuteIDsMemRatio() }
+
+// SetCacheUserMuteIDsMemRatio safely sets the value for global configuration 'Cache.UserMuteIDsMemRatio' field
+func SetCacheUserMuteIDsMemRatio(v float64) { global.SetCacheUserMuteIDsMemRatio(v) }
+
 // GetCacheWebfingerMemRatio safely fetches the Configuration value for state's 'Cache.WebfingerMemRatio' field
 func (st *ConfigState) GetCacheWebfingerMemRatio() (v float64) {
 	st.mutex.RLock()
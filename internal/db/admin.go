@@ -26,7 +26,9 @@ import (
 // Admin contains functions related to instance administration (new signups etc).
 type Admin interface {
 	// IsUsernameAvailable checks whether a given username is available on our domain.
-	// Returns an error if the username is already taken, or something went wrong in the db.
+	// A username is not available if it's already taken, or if it was used for a sign-up
+	// that was rejected less than config.AccountsRejectionCooldown ago.
+	// Returns an error if something went wrong in the db.
 	IsUsernameAvailable(ctx context.Context, username string) (bool, error)
 
 	// IsEmailAvailable checks whether a given email address for a new account is available to be used on our domain.
@@ -34,8 +36,20 @@ type Admin interface {
 	// A) the email is already associated with an account
 	// B) we block signups from this email domain
 	// C) something went wrong in the db
+	// The email is also not available if it was used for a sign-up that was
+	// rejected less than config.AccountsRejectionCooldown ago.
 	IsEmailAvailable(ctx context.Context, email string) (bool, error)
 
+	// GetLocalAccounts gets limit n local accounts (ie., accounts with a user), optionally
+	// filtered on whether the user has been approved. Parameters that are nil / empty / zero are ignored.
+	GetLocalAccounts(ctx context.Context, approved *bool, maxID string, sinceID string, minID string, limit int) ([]*gtsmodel.Account, error)
+
+	// GetDeniedUserByID returns the denied user tombstone with the given id, if it exists.
+	GetDeniedUserByID(ctx context.Context, id string) (*gtsmodel.DeniedUser, error)
+
+	// PutDeniedUser puts the given denied user tombstone in the database.
+	PutDeniedUser(ctx context.Context, deniedUser *gtsmodel.DeniedUser) error
+
 	// NewSignup creates a new user in the database with the given parameters.
 	// By the time this function is called, it should be assumed that all the parameters have passed validation!
 	NewSignup(ctx context.Context, newSignup gtsmodel.NewSignup) (*gtsmodel.User, error)
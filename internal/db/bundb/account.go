@@ -254,7 +254,7 @@ func (a *accountDB) getAccount(ctx context.Context, lookup string, dbQuery func(
 func (a *accountDB) PopulateAccount(ctx context.Context, account *gtsmodel.Account) error {
 	var (
 		err  error
-		errs = gtserror.NewMultiError(3)
+		errs = gtserror.NewMultiError(4)
 	)
 
 	if account.AvatarMediaAttachment == nil && account.AvatarMediaAttachmentID != "" {
@@ -290,6 +290,17 @@ func (a *accountDB) PopulateAccount(ctx context.Context, account *gtsmodel.Accou
 		}
 	}
 
+	if account.MovedTo == nil && account.MovedToAccountID != "" {
+		// Account moved-to account is not set, fetch from database.
+		account.MovedTo, err = a.state.DB.GetAccountByID(
+			gtscontext.SetBarebones(ctx),
+			account.MovedToAccountID,
+		)
+		if err != nil {
+			errs.Appendf("error populating moved to account: %w", err)
+		}
+	}
+
 	if err := errs.Combine(); err != nil {
 		return gtserror.Newf("%w", err)
 	}
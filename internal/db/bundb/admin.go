@@ -55,7 +55,13 @@ func (a *adminDB) IsUsernameAvailable(ctx context.Context, username string) (boo
 		Column("account.id").
 		Where("? = ?", bun.Ident("account.username"), username).
 		Where("? IS NULL", bun.Ident("account.domain"))
-	return a.db.NotExists(ctx, q)
+	available, err := a.db.NotExists(ctx, q)
+	if err != nil || !available {
+		return available, err
+	}
+
+	// check if this username was used for a recently rejected sign-up
+	return a.notRecentlyDenied(ctx, "username", username)
 }
 
 func (a *adminDB) IsEmailAvailable(ctx context.Context, email string) (bool, error) {
@@ -87,9 +93,116 @@ func (a *adminDB) IsEmailAvailable(ctx context.Context, email string) (bool, err
 		Column("user.id").
 		Where("? = ?", bun.Ident("user.email"), email).
 		WhereOr("? = ?", bun.Ident("user.unconfirmed_email"), email)
+	available, err := a.db.NotExists(ctx, q)
+	if err != nil || !available {
+		return available, err
+	}
+
+	// check if this email was used for a recently rejected sign-up
+	return a.notRecentlyDenied(ctx, "email", email)
+}
+
+// notRecentlyDenied returns true if the given value for the given
+// denied_users column doesn't belong to a sign-up that was rejected
+// within the configured rejection cooldown period.
+func (a *adminDB) notRecentlyDenied(ctx context.Context, column string, value string) (bool, error) {
+	cooldown := config.GetAccountsRejectionCooldown()
+	if cooldown <= 0 {
+		// No cooldown configured.
+		return true, nil
+	}
+
+	q := a.db.
+		NewSelect().
+		TableExpr("? AS ?", bun.Ident("denied_users"), bun.Ident("denied_user")).
+		Column("denied_user.id").
+		Where("? = ?", bun.Ident("denied_user."+column), value).
+		Where("? > ?", bun.Ident("denied_user.created_at"), time.Now().Add(-cooldown))
 	return a.db.NotExists(ctx, q)
 }
 
+func (a *adminDB) GetLocalAccounts(ctx context.Context, approved *bool, maxID string, sinceID string, minID string, limit int) ([]*gtsmodel.Account, error) {
+	accountIDs := []string{}
+
+	q := a.db.
+		NewSelect().
+		TableExpr("? AS ?", bun.Ident("accounts"), bun.Ident("account")).
+		Column("account.id").
+		// Only select accounts that have a user.
+		Join(
+			"JOIN ? AS ? ON ? = ?",
+			bun.Ident("users"), bun.Ident("user"),
+			bun.Ident("user.account_id"), bun.Ident("account.id"),
+		).
+		Where("? IS NULL", bun.Ident("account.domain")).
+		Order("account.id DESC")
+
+	if approved != nil {
+		q = q.Where("? = ?", bun.Ident("user.approved"), *approved)
+	}
+
+	if maxID != "" {
+		q = q.Where("? < ?", bun.Ident("account.id"), maxID)
+	}
+
+	if sinceID != "" {
+		q = q.Where("? > ?", bun.Ident("account.id"), sinceID)
+	}
+
+	if minID != "" {
+		q = q.Where("? > ?", bun.Ident("account.id"), minID)
+	}
+
+	if limit != 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Scan(ctx, &accountIDs); err != nil {
+		return nil, a.db.ProcessError(err)
+	}
+
+	// Catch case of no accounts early
+	if len(accountIDs) == 0 {
+		return nil, db.ErrNoEntries
+	}
+
+	// Allocate return slice (will be at most len accountIDs)
+	accounts := make([]*gtsmodel.Account, 0, len(accountIDs))
+	for _, id := range accountIDs {
+		account, err := a.state.DB.GetAccountByID(ctx, id)
+		if err != nil {
+			log.Errorf(ctx, "error getting account %q: %v", id, err)
+			continue
+		}
+
+		// Append to return slice
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
+func (a *adminDB) GetDeniedUserByID(ctx context.Context, id string) (*gtsmodel.DeniedUser, error) {
+	deniedUser := &gtsmodel.DeniedUser{}
+	if err := a.db.
+		NewSelect().
+		Model(deniedUser).
+		Where("? = ?", bun.Ident("denied_user.id"), id).
+		Scan(ctx); err != nil {
+		return nil, a.db.ProcessError(err)
+	}
+
+	return deniedUser, nil
+}
+
+func (a *adminDB) PutDeniedUser(ctx context.Context, deniedUser *gtsmodel.DeniedUser) error {
+	_, err := a.db.
+		NewInsert().
+		Model(deniedUser).
+		Exec(ctx)
+	return a.db.ProcessError(err)
+}
+
 func (a *adminDB) NewSignup(ctx context.Context, newSignup gtsmodel.NewSignup) (*gtsmodel.User, error) {
 	// If something went wrong previously while doing a new
 	// sign up with this username, we might already have an
@@ -190,6 +303,7 @@ func (a *adminDB) NewSignup(ctx context.Context, newSignup gtsmodel.NewSignup) (
 		UnconfirmedEmail:       newSignup.Email,
 		CreatedByApplicationID: newSignup.AppID,
 		ExternalID:             newSignup.ExternalID,
+		InviteID:               newSignup.InviteID,
 	}
 
 	if newSignup.EmailVerified {
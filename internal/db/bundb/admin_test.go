@@ -20,9 +20,11 @@ package bundb_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
-	gtsmodel "github.com/superseriousbusiness/gotosocial/internal/db/bundb/migrations/20211113114307_init"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/testrig"
 )
 
@@ -68,6 +70,61 @@ func (suite *AdminTestSuite) TestIsEmailAvailableDomainBlocked() {
 	suite.False(available)
 }
 
+func (suite *AdminTestSuite) TestIsUsernameAndEmailAvailableRecentlyDenied() {
+	ctx := context.Background()
+
+	if err := suite.db.PutDeniedUser(ctx, &gtsmodel.DeniedUser{
+		ID:                "01H8MQ7SBQWCMVQRKQ93QVF0AB",
+		Email:             "someone@somewhere.com",
+		Username:          "someone_completely_different",
+		DeniedByAccountID: suite.testAccounts["admin_account"].ID,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Username + email of the rejected
+	// sign-up should not be available.
+	available, err := suite.db.IsUsernameAvailable(ctx, "someone_completely_different")
+	suite.NoError(err)
+	suite.False(available)
+
+	available, err = suite.db.IsEmailAvailable(ctx, "someone@somewhere.com")
+	suite.NoError(err)
+	suite.False(available)
+
+	// Once the cooldown has passed, they should be available again.
+	config.SetAccountsRejectionCooldown(time.Nanosecond)
+	defer config.SetAccountsRejectionCooldown(7 * 24 * time.Hour)
+
+	available, err = suite.db.IsUsernameAvailable(ctx, "someone_completely_different")
+	suite.NoError(err)
+	suite.True(available)
+
+	available, err = suite.db.IsEmailAvailable(ctx, "someone@somewhere.com")
+	suite.NoError(err)
+	suite.True(available)
+}
+
+func (suite *AdminTestSuite) TestGetLocalAccountsPending() {
+	pending := false
+	accounts, err := suite.db.GetLocalAccounts(context.Background(), &pending, "", "", "", 0)
+	suite.NoError(err)
+	if suite.Len(accounts, 1) {
+		suite.Equal(suite.testAccounts["unconfirmed_account"].ID, accounts[0].ID)
+	}
+}
+
+func (suite *AdminTestSuite) TestGetLocalAccountsAll() {
+	accounts, err := suite.db.GetLocalAccounts(context.Background(), nil, "", "", "", 0)
+	suite.NoError(err)
+	suite.Len(accounts, len(testrig.NewTestUsers()))
+
+	// Accounts should be sorted newest first.
+	for i := 1; i < len(accounts); i++ {
+		suite.Greater(accounts[i-1].ID, accounts[i].ID)
+	}
+}
+
 func (suite *AdminTestSuite) TestCreateInstanceAccount() {
 	// reinitialize db caches to clear
 	suite.state.Caches.Init()
@@ -61,25 +61,36 @@ type DBService struct {
 	db.Account
 	db.Admin
 	db.Basic
+	db.Conversation
+	db.Delivery
 	db.Domain
 	db.Emoji
+	db.Filter
 	db.Instance
+	db.Invite
 	db.List
 	db.Marker
 	db.Media
 	db.Mention
 	db.Notification
+	db.Poll
 	db.Relationship
 	db.Report
+	db.Rule
+	db.ScheduledStatus
 	db.Search
 	db.Session
 	db.Status
 	db.StatusBookmark
+	db.StatusEdit
 	db.StatusFave
+	db.StatusMute
 	db.Tag
 	db.Timeline
 	db.User
 	db.Tombstone
+	db.WebPush
+	db.WorkerMessage
 	db *WrappedDB
 }
 
@@ -171,6 +182,14 @@ func NewBunDBService(ctx context.Context, state *state.State) (db.DB, error) {
 		Basic: &basicDB{
 			db: db,
 		},
+		Conversation: &conversationDB{
+			db:    db,
+			state: state,
+		},
+		Delivery: &deliveryDB{
+			db:    db,
+			state: state,
+		},
 		Domain: &domainDB{
 			db:    db,
 			state: state,
@@ -179,10 +198,18 @@ func NewBunDBService(ctx context.Context, state *state.State) (db.DB, error) {
 			db:    db,
 			state: state,
 		},
+		Filter: &filterDB{
+			db:    db,
+			state: state,
+		},
 		Instance: &instanceDB{
 			db:    db,
 			state: state,
 		},
+		Invite: &inviteDB{
+			db:    db,
+			state: state,
+		},
 		List: &listDB{
 			db:    db,
 			state: state,
@@ -203,6 +230,10 @@ func NewBunDBService(ctx context.Context, state *state.State) (db.DB, error) {
 			db:    db,
 			state: state,
 		},
+		Poll: &pollDB{
+			db:    db,
+			state: state,
+		},
 		Relationship: &relationshipDB{
 			db:    db,
 			state: state,
@@ -211,6 +242,14 @@ func NewBunDBService(ctx context.Context, state *state.State) (db.DB, error) {
 			db:    db,
 			state: state,
 		},
+		Rule: &ruleDB{
+			db:    db,
+			state: state,
+		},
+		ScheduledStatus: &scheduledStatusDB{
+			db:    db,
+			state: state,
+		},
 		Search: &searchDB{
 			db:    db,
 			state: state,
@@ -226,10 +265,18 @@ func NewBunDBService(ctx context.Context, state *state.State) (db.DB, error) {
 			db:    db,
 			state: state,
 		},
+		StatusEdit: &statusEditDB{
+			db:    db,
+			state: state,
+		},
 		StatusFave: &statusFaveDB{
 			db:    db,
 			state: state,
 		},
+		StatusMute: &statusMuteDB{
+			db:    db,
+			state: state,
+		},
 		Tag: &tagDB{
 			conn:  db,
 			state: state,
@@ -246,6 +293,14 @@ func NewBunDBService(ctx context.Context, state *state.State) (db.DB, error) {
 			db:    db,
 			state: state,
 		},
+		WebPush: &webPushDB{
+			db:    db,
+			state: state,
+		},
+		WorkerMessage: &workerMessageDB{
+			db:    db,
+			state: state,
+		},
 		db: db,
 	}
 
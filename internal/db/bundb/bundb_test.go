@@ -33,24 +33,27 @@ type BunDBStandardTestSuite struct {
 	state state.State
 
 	// standard suite models
-	testTokens       map[string]*gtsmodel.Token
-	testClients      map[string]*gtsmodel.Client
-	testApplications map[string]*gtsmodel.Application
-	testUsers        map[string]*gtsmodel.User
-	testAccounts     map[string]*gtsmodel.Account
-	testAttachments  map[string]*gtsmodel.MediaAttachment
-	testStatuses     map[string]*gtsmodel.Status
-	testTags         map[string]*gtsmodel.Tag
-	testMentions     map[string]*gtsmodel.Mention
-	testFollows      map[string]*gtsmodel.Follow
-	testEmojis       map[string]*gtsmodel.Emoji
-	testReports      map[string]*gtsmodel.Report
-	testBookmarks    map[string]*gtsmodel.StatusBookmark
-	testFaves        map[string]*gtsmodel.StatusFave
-	testLists        map[string]*gtsmodel.List
-	testListEntries  map[string]*gtsmodel.ListEntry
-	testAccountNotes map[string]*gtsmodel.AccountNote
-	testMarkers      map[string]*gtsmodel.Marker
+	testTokens         map[string]*gtsmodel.Token
+	testClients        map[string]*gtsmodel.Client
+	testApplications   map[string]*gtsmodel.Application
+	testUsers          map[string]*gtsmodel.User
+	testAccounts       map[string]*gtsmodel.Account
+	testAttachments    map[string]*gtsmodel.MediaAttachment
+	testStatuses       map[string]*gtsmodel.Status
+	testTags           map[string]*gtsmodel.Tag
+	testMentions       map[string]*gtsmodel.Mention
+	testFollows        map[string]*gtsmodel.Follow
+	testEmojis         map[string]*gtsmodel.Emoji
+	testReports        map[string]*gtsmodel.Report
+	testBookmarks      map[string]*gtsmodel.StatusBookmark
+	testFaves          map[string]*gtsmodel.StatusFave
+	testLists          map[string]*gtsmodel.List
+	testListEntries    map[string]*gtsmodel.ListEntry
+	testAccountNotes   map[string]*gtsmodel.AccountNote
+	testMarkers        map[string]*gtsmodel.Marker
+	testFilters        map[string]*gtsmodel.Filter
+	testFilterKeywords map[string]*gtsmodel.FilterKeyword
+	testFilterStatuses map[string]*gtsmodel.FilterStatus
 }
 
 func (suite *BunDBStandardTestSuite) SetupSuite() {
@@ -72,6 +75,9 @@ func (suite *BunDBStandardTestSuite) SetupSuite() {
 	suite.testListEntries = testrig.NewTestListEntries()
 	suite.testAccountNotes = testrig.NewTestAccountNotes()
 	suite.testMarkers = testrig.NewTestMarkers()
+	suite.testFilters = testrig.NewTestFilters()
+	suite.testFilterKeywords = testrig.NewTestFilterKeywords()
+	suite.testFilterStatuses = testrig.NewTestFilterStatuses()
 }
 
 func (suite *BunDBStandardTestSuite) SetupTest() {
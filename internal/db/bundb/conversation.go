@@ -0,0 +1,390 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtscontext"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/paging"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/uptrace/bun"
+)
+
+type conversationDB struct {
+	db    *WrappedDB
+	state *state.State
+}
+
+func (c *conversationDB) GetConversationByID(ctx context.Context, id string) (*gtsmodel.Conversation, error) {
+	return c.getConversation(
+		ctx,
+		"ID",
+		func(conversation *gtsmodel.Conversation) error {
+			return c.db.NewSelect().
+				Model(conversation).
+				Where("? = ?", bun.Ident("conversation.id"), id).
+				Scan(ctx)
+		},
+		id,
+	)
+}
+
+func (c *conversationDB) GetConversationByThreadAndAccountIDs(ctx context.Context, accountID string, threadID string, otherAccountIDs []string) (*gtsmodel.Conversation, error) {
+	otherAccountsKey := gtsmodel.ConversationOtherAccountsKey(otherAccountIDs)
+	return c.getConversation(
+		ctx,
+		"AccountID.ThreadID.OtherAccountsKey",
+		func(conversation *gtsmodel.Conversation) error {
+			return c.db.NewSelect().
+				Model(conversation).
+				Where("? = ?", bun.Ident("conversation.account_id"), accountID).
+				Where("? = ?", bun.Ident("conversation.thread_id"), threadID).
+				Where("? = ?", bun.Ident("conversation.other_accounts_key"), otherAccountsKey).
+				Scan(ctx)
+		},
+		accountID,
+		threadID,
+		otherAccountsKey,
+	)
+}
+
+func (c *conversationDB) getConversation(ctx context.Context, lookup string, dbQuery func(*gtsmodel.Conversation) error, keyParts ...any) (*gtsmodel.Conversation, error) {
+	conversation, err := c.state.Caches.GTS.Conversation().Load(lookup, func() (*gtsmodel.Conversation, error) {
+		var conversation gtsmodel.Conversation
+
+		// Not cached! Perform database query.
+		if err := dbQuery(&conversation); err != nil {
+			return nil, c.db.ProcessError(err)
+		}
+
+		return &conversation, nil
+	}, keyParts...)
+	if err != nil {
+		// already processed
+		return nil, err
+	}
+
+	if gtscontext.Barebones(ctx) {
+		// Only a barebones model was requested.
+		return conversation, nil
+	}
+
+	if err := c.state.DB.PopulateConversation(ctx, conversation); err != nil {
+		return nil, err
+	}
+
+	return conversation, nil
+}
+
+func (c *conversationDB) GetConversationsByOwnerAccountID(ctx context.Context, accountID string, page *paging.Pager) ([]*gtsmodel.Conversation, error) {
+	var (
+		conversationIDs []string
+		frontToBack     = true
+	)
+
+	q := c.db.
+		NewSelect().
+		TableExpr("? AS ?", bun.Ident("conversations"), bun.Ident("conversation")).
+		Column("conversation.id").
+		Where("? = ?", bun.Ident("conversation.account_id"), accountID)
+
+	if page != nil {
+		// Conversations are paged by their most
+		// recent status, not the conversation ID.
+		if page.MaxID != "" {
+			q = q.Where("? < ?", bun.Ident("conversation.last_status_id"), page.MaxID)
+		}
+
+		if page.SinceID != "" {
+			q = q.Where("? > ?", bun.Ident("conversation.last_status_id"), page.SinceID)
+		} else if page.MinID != "" {
+			// We only support minID if
+			// no sinceID is provided.
+			q = q.Where("? > ?", bun.Ident("conversation.last_status_id"), page.MinID)
+			frontToBack = false
+		}
+
+		if page.Limit > 0 {
+			q = q.Limit(page.Limit)
+		}
+	}
+
+	if frontToBack {
+		// Page down.
+		q = q.Order("conversation.last_status_id DESC")
+	} else {
+		// Page up.
+		q = q.Order("conversation.last_status_id ASC")
+	}
+
+	if err := q.Scan(ctx, &conversationIDs); err != nil {
+		return nil, c.db.ProcessError(err)
+	}
+
+	if len(conversationIDs) == 0 {
+		return nil, nil
+	}
+
+	// If we're paging up, we still want conversations
+	// to be sorted by last status ID desc, so reverse.
+	if !frontToBack {
+		for l, r := 0, len(conversationIDs)-1; l < r; l, r = l+1, r-1 {
+			conversationIDs[l], conversationIDs[r] = conversationIDs[r], conversationIDs[l]
+		}
+	}
+
+	// Select each conversation using its ID to ensure cache used.
+	conversations := make([]*gtsmodel.Conversation, 0, len(conversationIDs))
+	for _, id := range conversationIDs {
+		conversation, err := c.GetConversationByID(ctx, id)
+		if err != nil {
+			log.Errorf(ctx, "error fetching conversation %q: %v", id, err)
+			continue
+		}
+		conversations = append(conversations, conversation)
+	}
+
+	return conversations, nil
+}
+
+func (c *conversationDB) PopulateConversation(ctx context.Context, conversation *gtsmodel.Conversation) error {
+	var (
+		err  error
+		errs = gtserror.NewMultiError(3)
+	)
+
+	if conversation.Account == nil {
+		// Conversation account is not set, fetch from the database.
+		conversation.Account, err = c.state.DB.GetAccountByID(
+			gtscontext.SetBarebones(ctx),
+			conversation.AccountID,
+		)
+		if err != nil {
+			errs.Appendf("error populating conversation owner account: %w", err)
+		}
+	}
+
+	if conversation.OtherAccounts == nil {
+		// Other accounts are not set, fetch from the database.
+		otherAccounts := make([]*gtsmodel.Account, 0, len(conversation.OtherAccountIDs))
+		for _, id := range conversation.OtherAccountIDs {
+			account, err := c.state.DB.GetAccountByID(
+				gtscontext.SetBarebones(ctx),
+				id,
+			)
+			if err != nil {
+				errs.Appendf("error populating conversation other account %s: %w", id, err)
+				continue
+			}
+			otherAccounts = append(otherAccounts, account)
+		}
+		conversation.OtherAccounts = otherAccounts
+	}
+
+	if conversation.LastStatus == nil {
+		// Last status is not set, fetch from the database.
+		conversation.LastStatus, err = c.state.DB.GetStatusByID(
+			ctx,
+			conversation.LastStatusID,
+		)
+		if err != nil {
+			errs.Appendf("error populating conversation last status: %w", err)
+		}
+	}
+
+	return errs.Combine()
+}
+
+func (c *conversationDB) PutConversation(ctx context.Context, conversation *gtsmodel.Conversation) error {
+	// Ensure participants key is in sync with participants.
+	conversation.OtherAccountsKey = gtsmodel.ConversationOtherAccountsKey(conversation.OtherAccountIDs)
+
+	return c.state.Caches.GTS.Conversation().Store(conversation, func() error {
+		_, err := c.db.NewInsert().Model(conversation).Exec(ctx)
+		return c.db.ProcessError(err)
+	})
+}
+
+func (c *conversationDB) UpdateConversation(ctx context.Context, conversation *gtsmodel.Conversation, columns ...string) error {
+	conversation.UpdatedAt = time.Now()
+	if len(columns) > 0 {
+		// If we're updating by column, ensure "updated_at" is included.
+		columns = append(columns, "updated_at")
+	}
+
+	return c.state.Caches.GTS.Conversation().Store(conversation, func() error {
+		_, err := c.db.NewUpdate().
+			Model(conversation).
+			Where("? = ?", bun.Ident("conversation.id"), conversation.ID).
+			Column(columns...).
+			Exec(ctx)
+		return c.db.ProcessError(err)
+	})
+}
+
+func (c *conversationDB) LinkConversationToStatus(ctx context.Context, conversationID string, statusID string) error {
+	conversationToStatus := &gtsmodel.ConversationToStatus{
+		ConversationID: conversationID,
+		StatusID:       statusID,
+	}
+
+	if _, err := c.db.NewInsert().
+		Model(conversationToStatus).
+		On("CONFLICT (?, ?) DO NOTHING", bun.Ident("conversation_id"), bun.Ident("status_id")).
+		Exec(ctx); err != nil {
+		return c.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+func (c *conversationDB) DeleteConversationByID(ctx context.Context, id string) error {
+	defer c.state.Caches.GTS.Conversation().Invalidate("ID", id)
+
+	// Delete links from this conversation to statuses.
+	if _, err := c.db.NewDelete().
+		Table("conversation_to_statuses").
+		Where("? = ?", bun.Ident("conversation_id"), id).
+		Exec(ctx); err != nil {
+		return c.db.ProcessError(err)
+	}
+
+	// Delete the conversation itself.
+	if _, err := c.db.NewDelete().
+		Table("conversations").
+		Where("? = ?", bun.Ident("id"), id).
+		Exec(ctx); err != nil {
+		return c.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+func (c *conversationDB) DeleteConversationsByOwnerAccountID(ctx context.Context, accountID string) error {
+	defer c.state.Caches.GTS.Conversation().Invalidate("AccountID", accountID)
+
+	return c.db.RunInTx(ctx, func(tx bun.Tx) error {
+		// Delete links from the account's conversations to statuses.
+		if _, err := tx.NewDelete().
+			Table("conversation_to_statuses").
+			Where("? IN (?)",
+				bun.Ident("conversation_id"),
+				tx.NewSelect().
+					Table("conversations").
+					Column("id").
+					Where("? = ?", bun.Ident("account_id"), accountID),
+			).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		// Delete the conversations themselves.
+		_, err := tx.NewDelete().
+			Table("conversations").
+			Where("? = ?", bun.Ident("account_id"), accountID).
+			Exec(ctx)
+		return err
+	})
+}
+
+func (c *conversationDB) DeleteStatusFromConversations(ctx context.Context, statusID string) error {
+	var conversationIDs []string
+
+	// Get the conversations this status is in.
+	if err := c.db.NewSelect().
+		Table("conversation_to_statuses").
+		Column("conversation_id").
+		Where("? = ?", bun.Ident("status_id"), statusID).
+		Scan(ctx, &conversationIDs); err != nil {
+		return c.db.ProcessError(err)
+	}
+
+	if len(conversationIDs) == 0 {
+		// Nothing to do.
+		return nil
+	}
+
+	// Remove the links to this status.
+	if _, err := c.db.NewDelete().
+		Table("conversation_to_statuses").
+		Where("? = ?", bun.Ident("status_id"), statusID).
+		Exec(ctx); err != nil {
+		return c.db.ProcessError(err)
+	}
+
+	errs := gtserror.NewMultiError(len(conversationIDs))
+
+	for _, id := range conversationIDs {
+		if err := c.removeStatusFromConversation(ctx, id, statusID); err != nil {
+			errs.Appendf("error removing status from conversation %s: %w", id, err)
+		}
+	}
+
+	return errs.Combine()
+}
+
+// removeStatusFromConversation updates the conversation with given
+// ID after the given status was unlinked from it, either setting a
+// new last status or deleting the conversation if it is now empty.
+func (c *conversationDB) removeStatusFromConversation(ctx context.Context, conversationID string, statusID string) error {
+	conversation, err := c.GetConversationByID(gtscontext.SetBarebones(ctx), conversationID)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			// Already gone.
+			return nil
+		}
+		return err
+	}
+
+	if conversation.LastStatusID != statusID {
+		// Most recent status
+		// unchanged, nothing to do.
+		return nil
+	}
+
+	// Find the most recent remaining status.
+	var lastStatusID string
+	if err := c.db.NewSelect().
+		Table("conversation_to_statuses").
+		Column("status_id").
+		Where("? = ?", bun.Ident("conversation_id"), conversationID).
+		Order("status_id DESC").
+		Limit(1).
+		Scan(ctx, &lastStatusID); err != nil {
+		err = c.db.ProcessError(err)
+		if !errors.Is(err, db.ErrNoEntries) {
+			return err
+		}
+	}
+
+	if lastStatusID == "" {
+		// Conversation has no statuses left.
+		return c.DeleteConversationByID(ctx, conversationID)
+	}
+
+	conversation.LastStatusID = lastStatusID
+	conversation.LastStatus = nil
+	return c.UpdateConversation(ctx, conversation, "last_status_id")
+}
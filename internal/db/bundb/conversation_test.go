@@ -0,0 +1,121 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/paging"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+type ConversationTestSuite struct {
+	BunDBStandardTestSuite
+}
+
+func (suite *ConversationTestSuite) putConversation(ctx context.Context) *gtsmodel.Conversation {
+	dmStatus := suite.testStatuses["local_account_2_status_6"]
+
+	conversation := &gtsmodel.Conversation{
+		ID:              "01H7E4XKF8W7R3AB5C2D8GPN1T",
+		AccountID:       suite.testAccounts["local_account_1"].ID,
+		OtherAccountIDs: []string{dmStatus.AccountID},
+		ThreadID:        dmStatus.ID,
+		LastStatusID:    dmStatus.ID,
+		Read:            util.Ptr(false),
+	}
+
+	if err := suite.db.PutConversation(ctx, conversation); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	if err := suite.db.LinkConversationToStatus(ctx, conversation.ID, dmStatus.ID); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	return conversation
+}
+
+func (suite *ConversationTestSuite) TestGetConversation() {
+	ctx := context.Background()
+	conversation := suite.putConversation(ctx)
+
+	dbConversation, err := suite.db.GetConversationByThreadAndAccountIDs(ctx,
+		conversation.AccountID,
+		conversation.ThreadID,
+		conversation.OtherAccountIDs,
+	)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal(conversation.ID, dbConversation.ID)
+	suite.NotNil(dbConversation.LastStatus)
+	suite.Len(dbConversation.OtherAccounts, 1)
+
+	conversations, err := suite.db.GetConversationsByOwnerAccountID(ctx,
+		conversation.AccountID,
+		&paging.Pager{Limit: 20},
+	)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	if suite.Len(conversations, 1) {
+		suite.Equal(conversation.ID, conversations[0].ID)
+	}
+
+	// Paging past the last status should return nothing.
+	conversations, err = suite.db.GetConversationsByOwnerAccountID(ctx,
+		conversation.AccountID,
+		&paging.Pager{MaxID: conversation.LastStatusID, Limit: 20},
+	)
+	suite.NoError(err)
+	suite.Empty(conversations)
+}
+
+func (suite *ConversationTestSuite) TestDeleteStatusFromConversations() {
+	ctx := context.Background()
+	conversation := suite.putConversation(ctx)
+
+	// Removing the only status should remove the conversation.
+	if err := suite.db.DeleteStatusFromConversations(ctx, conversation.LastStatusID); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	_, err := suite.db.GetConversationByID(ctx, conversation.ID)
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
+func (suite *ConversationTestSuite) TestDeleteConversationsByOwnerAccountID() {
+	ctx := context.Background()
+	conversation := suite.putConversation(ctx)
+
+	if err := suite.db.DeleteConversationsByOwnerAccountID(ctx, conversation.AccountID); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	_, err := suite.db.GetConversationByID(ctx, conversation.ID)
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
+func TestConversationTestSuite(t *testing.T) {
+	suite.Run(t, new(ConversationTestSuite))
+}
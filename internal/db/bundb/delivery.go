@@ -0,0 +1,177 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/uptrace/bun"
+)
+
+type deliveryDB struct {
+	db    *WrappedDB
+	state *state.State
+}
+
+func (d *deliveryDB) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*gtsmodel.Delivery, error) {
+	deliveries := []*gtsmodel.Delivery{}
+
+	if err := d.db.
+		NewSelect().
+		Model(&deliveries).
+		Where("? <= ?", bun.Ident("delivery.next_attempt_at"), now).
+		OrderExpr("? ASC", bun.Ident("delivery.next_attempt_at")).
+		Limit(limit).
+		Scan(ctx); err != nil {
+		return nil, d.db.ProcessError(err)
+	}
+
+	return deliveries, nil
+}
+
+func (d *deliveryDB) CountDeliveries(ctx context.Context) (int, error) {
+	return d.db.
+		NewSelect().
+		TableExpr("? AS ?", bun.Ident("deliveries"), bun.Ident("delivery")).
+		Count(ctx)
+}
+
+func (d *deliveryDB) CountDeliveriesForDomain(ctx context.Context, domain string) (int, error) {
+	return d.db.
+		NewSelect().
+		TableExpr("? AS ?", bun.Ident("deliveries"), bun.Ident("delivery")).
+		Where("? = ?", bun.Ident("delivery.domain"), domain).
+		Count(ctx)
+}
+
+func (d *deliveryDB) PutDelivery(ctx context.Context, delivery *gtsmodel.Delivery) error {
+	if _, err := d.db.
+		NewInsert().
+		Model(delivery).
+		Exec(ctx); err != nil {
+		return d.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+func (d *deliveryDB) UpdateDelivery(ctx context.Context, delivery *gtsmodel.Delivery, columns ...string) error {
+	delivery.UpdatedAt = time.Now()
+	if len(columns) > 0 {
+		// If we're updating by column,
+		// ensure "updated_at" is included.
+		columns = append(columns, "updated_at")
+	}
+
+	if _, err := d.db.
+		NewUpdate().
+		Model(delivery).
+		Column(columns...).
+		Where("? = ?", bun.Ident("delivery.id"), delivery.ID).
+		Exec(ctx); err != nil {
+		return d.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+func (d *deliveryDB) DeleteDeliveryByID(ctx context.Context, id string) error {
+	if _, err := d.db.
+		NewDelete().
+		TableExpr("? AS ?", bun.Ident("deliveries"), bun.Ident("delivery")).
+		Where("? = ?", bun.Ident("delivery.id"), id).
+		Exec(ctx); err != nil {
+		return d.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+func (d *deliveryDB) GetDeliveryFailure(ctx context.Context, domain string) (*gtsmodel.DeliveryFailure, error) {
+	var failure gtsmodel.DeliveryFailure
+
+	if err := d.db.
+		NewSelect().
+		Model(&failure).
+		Where("? = ?", bun.Ident("delivery_failure.domain"), domain).
+		Scan(ctx); err != nil {
+		return nil, d.db.ProcessError(err)
+	}
+
+	return &failure, nil
+}
+
+func (d *deliveryDB) GetDeliveryFailures(ctx context.Context) ([]*gtsmodel.DeliveryFailure, error) {
+	failures := []*gtsmodel.DeliveryFailure{}
+
+	if err := d.db.
+		NewSelect().
+		Model(&failures).
+		OrderExpr("? ASC", bun.Ident("delivery_failure.domain")).
+		Scan(ctx); err != nil {
+		return nil, d.db.ProcessError(err)
+	}
+
+	return failures, nil
+}
+
+func (d *deliveryDB) PutDeliveryFailure(ctx context.Context, failure *gtsmodel.DeliveryFailure) error {
+	if _, err := d.db.
+		NewInsert().
+		Model(failure).
+		Exec(ctx); err != nil {
+		return d.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+func (d *deliveryDB) UpdateDeliveryFailure(ctx context.Context, failure *gtsmodel.DeliveryFailure, columns ...string) error {
+	failure.UpdatedAt = time.Now()
+	if len(columns) > 0 {
+		// If we're updating by column,
+		// ensure "updated_at" is included.
+		columns = append(columns, "updated_at")
+	}
+
+	if _, err := d.db.
+		NewUpdate().
+		Model(failure).
+		Column(columns...).
+		Where("? = ?", bun.Ident("delivery_failure.id"), failure.ID).
+		Exec(ctx); err != nil {
+		return d.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+func (d *deliveryDB) DeleteDeliveryFailure(ctx context.Context, domain string) error {
+	if _, err := d.db.
+		NewDelete().
+		TableExpr("? AS ?", bun.Ident("delivery_failures"), bun.Ident("delivery_failure")).
+		Where("? = ?", bun.Ident("delivery_failure.domain"), domain).
+		Exec(ctx); err != nil {
+		return d.db.ProcessError(err)
+	}
+
+	return nil
+}
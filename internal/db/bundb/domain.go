@@ -20,6 +20,7 @@ package bundb
 import (
 	"context"
 	"net/url"
+	"time"
 
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
@@ -34,6 +35,136 @@ type domainDB struct {
 	state *state.State
 }
 
+func (d *domainDB) CreateDomainAllow(ctx context.Context, allow *gtsmodel.DomainAllow) error {
+	// Normalize the domain as punycode
+	var err error
+	allow.Domain, err = util.Punify(allow.Domain)
+	if err != nil {
+		return err
+	}
+
+	// Attempt to store domain allow in DB
+	if _, err := d.db.NewInsert().
+		Model(allow).
+		Exec(ctx); err != nil {
+		return d.db.ProcessError(err)
+	}
+
+	// Clear the domain allow cache (for later reload)
+	d.state.Caches.GTS.DomainAllow().Clear()
+
+	return nil
+}
+
+func (d *domainDB) GetDomainAllow(ctx context.Context, domain string) (*gtsmodel.DomainAllow, error) {
+	// Normalize the domain as punycode
+	domain, err := util.Punify(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check for easy case, domain referencing *us*
+	if domain == "" || domain == config.GetAccountDomain() ||
+		domain == config.GetHost() {
+		return nil, db.ErrNoEntries
+	}
+
+	var allow gtsmodel.DomainAllow
+
+	// Look for allow matching domain in DB
+	q := d.db.
+		NewSelect().
+		Model(&allow).
+		Where("? = ?", bun.Ident("domain_allow.domain"), domain)
+	if err := q.Scan(ctx); err != nil {
+		return nil, d.db.ProcessError(err)
+	}
+
+	return &allow, nil
+}
+
+func (d *domainDB) GetDomainAllows(ctx context.Context) ([]*gtsmodel.DomainAllow, error) {
+	allows := []*gtsmodel.DomainAllow{}
+
+	if err := d.db.
+		NewSelect().
+		Model(&allows).
+		Scan(ctx); err != nil {
+		return nil, d.db.ProcessError(err)
+	}
+
+	return allows, nil
+}
+
+func (d *domainDB) GetDomainAllowByID(ctx context.Context, id string) (*gtsmodel.DomainAllow, error) {
+	var allow gtsmodel.DomainAllow
+
+	q := d.db.
+		NewSelect().
+		Model(&allow).
+		Where("? = ?", bun.Ident("domain_allow.id"), id)
+	if err := q.Scan(ctx); err != nil {
+		return nil, d.db.ProcessError(err)
+	}
+
+	return &allow, nil
+}
+
+func (d *domainDB) GetDomainAllowsBySubscriptionID(ctx context.Context, subscriptionID string) ([]*gtsmodel.DomainAllow, error) {
+	allows := []*gtsmodel.DomainAllow{}
+
+	if err := d.db.
+		NewSelect().
+		Model(&allows).
+		Where("? = ?", bun.Ident("domain_allow.subscription_id"), subscriptionID).
+		Scan(ctx); err != nil {
+		return nil, d.db.ProcessError(err)
+	}
+
+	return allows, nil
+}
+
+func (d *domainDB) UpdateDomainAllow(ctx context.Context, allow *gtsmodel.DomainAllow, columns ...string) error {
+	allow.UpdatedAt = time.Now()
+	if len(columns) > 0 {
+		// If we're updating by column,
+		// ensure "updated_at" is included.
+		columns = append(columns, "updated_at")
+	}
+
+	if _, err := d.db.
+		NewUpdate().
+		Model(allow).
+		Column(columns...).
+		Where("? = ?", bun.Ident("domain_allow.id"), allow.ID).
+		Exec(ctx); err != nil {
+		return d.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+func (d *domainDB) DeleteDomainAllow(ctx context.Context, domain string) error {
+	// Normalize the domain as punycode
+	domain, err := util.Punify(domain)
+	if err != nil {
+		return err
+	}
+
+	// Attempt to delete domain allow
+	if _, err := d.db.NewDelete().
+		Model((*gtsmodel.DomainAllow)(nil)).
+		Where("? = ?", bun.Ident("domain_allow.domain"), domain).
+		Exec(ctx); err != nil {
+		return d.db.ProcessError(err)
+	}
+
+	// Clear the domain allow cache (for later reload)
+	d.state.Caches.GTS.DomainAllow().Clear()
+
+	return nil
+}
+
 func (d *domainDB) CreateDomainBlock(ctx context.Context, block *gtsmodel.DomainBlock) error {
 	// Normalize the domain as punycode
 	var err error
@@ -109,6 +240,40 @@ func (d *domainDB) GetDomainBlockByID(ctx context.Context, id string) (*gtsmodel
 	return &block, nil
 }
 
+func (d *domainDB) GetDomainBlocksBySubscriptionID(ctx context.Context, subscriptionID string) ([]*gtsmodel.DomainBlock, error) {
+	blocks := []*gtsmodel.DomainBlock{}
+
+	if err := d.db.
+		NewSelect().
+		Model(&blocks).
+		Where("? = ?", bun.Ident("domain_block.subscription_id"), subscriptionID).
+		Scan(ctx); err != nil {
+		return nil, d.db.ProcessError(err)
+	}
+
+	return blocks, nil
+}
+
+func (d *domainDB) UpdateDomainBlock(ctx context.Context, block *gtsmodel.DomainBlock, columns ...string) error {
+	block.UpdatedAt = time.Now()
+	if len(columns) > 0 {
+		// If we're updating by column,
+		// ensure "updated_at" is included.
+		columns = append(columns, "updated_at")
+	}
+
+	if _, err := d.db.
+		NewUpdate().
+		Model(block).
+		Column(columns...).
+		Where("? = ?", bun.Ident("domain_block.id"), block.ID).
+		Exec(ctx); err != nil {
+		return d.db.ProcessError(err)
+	}
+
+	return nil
+}
+
 func (d *domainDB) DeleteDomainBlock(ctx context.Context, domain string) error {
 	// Normalize the domain as punycode
 	domain, err := util.Punify(domain)
@@ -130,6 +295,83 @@ func (d *domainDB) DeleteDomainBlock(ctx context.Context, domain string) error {
 	return nil
 }
 
+func (d *domainDB) CreateDomainPermissionSubscription(ctx context.Context, sub *gtsmodel.DomainPermissionSubscription) error {
+	if _, err := d.db.NewInsert().
+		Model(sub).
+		Exec(ctx); err != nil {
+		return d.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+func (d *domainDB) GetDomainPermissionSubscriptionByID(ctx context.Context, id string) (*gtsmodel.DomainPermissionSubscription, error) {
+	var sub gtsmodel.DomainPermissionSubscription
+
+	q := d.db.
+		NewSelect().
+		Model(&sub).
+		Where("? = ?", bun.Ident("domain_permission_subscription.id"), id)
+	if err := q.Scan(ctx); err != nil {
+		return nil, d.db.ProcessError(err)
+	}
+
+	return &sub, nil
+}
+
+func (d *domainDB) GetDomainPermissionSubscriptions(ctx context.Context, permType gtsmodel.DomainPermissionType) ([]*gtsmodel.DomainPermissionSubscription, error) {
+	subs := []*gtsmodel.DomainPermissionSubscription{}
+
+	q := d.db.
+		NewSelect().
+		Model(&subs).
+		// Highest priority first, oldest
+		// first for subs of equal priority.
+		OrderExpr("? DESC", bun.Ident("domain_permission_subscription.priority")).
+		OrderExpr("? ASC", bun.Ident("domain_permission_subscription.id"))
+
+	if permType != "" {
+		q = q.Where("? = ?", bun.Ident("domain_permission_subscription.permission_type"), permType)
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, d.db.ProcessError(err)
+	}
+
+	return subs, nil
+}
+
+func (d *domainDB) UpdateDomainPermissionSubscription(ctx context.Context, sub *gtsmodel.DomainPermissionSubscription, columns ...string) error {
+	sub.UpdatedAt = time.Now()
+	if len(columns) > 0 {
+		// If we're updating by column,
+		// ensure "updated_at" is included.
+		columns = append(columns, "updated_at")
+	}
+
+	if _, err := d.db.
+		NewUpdate().
+		Model(sub).
+		Column(columns...).
+		Where("? = ?", bun.Ident("domain_permission_subscription.id"), sub.ID).
+		Exec(ctx); err != nil {
+		return d.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+func (d *domainDB) DeleteDomainPermissionSubscription(ctx context.Context, id string) error {
+	if _, err := d.db.NewDelete().
+		Model((*gtsmodel.DomainPermissionSubscription)(nil)).
+		Where("? = ?", bun.Ident("domain_permission_subscription.id"), id).
+		Exec(ctx); err != nil {
+		return d.db.ProcessError(err)
+	}
+
+	return nil
+}
+
 func (d *domainDB) IsDomainBlocked(ctx context.Context, domain string) (bool, error) {
 	// Normalize the domain as punycode
 	domain, err := util.Punify(domain)
@@ -144,7 +386,7 @@ func (d *domainDB) IsDomainBlocked(ctx context.Context, domain string) (bool, er
 	}
 
 	// Check the cache for a domain block (hydrating the cache with callback if necessary)
-	return d.state.Caches.GTS.DomainBlock().IsBlocked(domain, func() ([]string, error) {
+	blocked, err := d.state.Caches.GTS.DomainBlock().IsBlocked(domain, func() ([]string, error) {
 		var domains []string
 
 		// Scan list of all blocked domains from DB
@@ -157,6 +399,39 @@ func (d *domainDB) IsDomainBlocked(ctx context.Context, domain string) (bool, er
 
 		return domains, nil
 	})
+	if err != nil || blocked {
+		// Explicit blocks always
+		// take precedence.
+		return blocked, err
+	}
+
+	if config.GetInstanceFederationMode() != config.InstanceFederationModeAllowlist {
+		// In blocklist mode, anything
+		// not blocked is permitted.
+		return false, nil
+	}
+
+	// In allowlist mode, check the cache for a domain allow (hydrating the cache with callback if necessary)
+	allowed, err := d.state.Caches.GTS.DomainAllow().IsBlocked(domain, func() ([]string, error) {
+		var domains []string
+
+		// Scan list of all allowed domains from DB
+		q := d.db.NewSelect().
+			Table("domain_allows").
+			Column("domain")
+		if err := q.Scan(ctx, &domains); err != nil {
+			return nil, d.db.ProcessError(err)
+		}
+
+		return domains, nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// Anything not explicitly
+	// allowed is blocked.
+	return !allowed, nil
 }
 
 func (d *domainDB) AreDomainsBlocked(ctx context.Context, domains []string) (bool, error) {
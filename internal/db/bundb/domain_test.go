@@ -23,6 +23,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
@@ -159,6 +161,125 @@ func (suite *DomainTestSuite) TestIsDomainBlockedNonASCII2() {
 	suite.True(blocked)
 }
 
+func (suite *DomainTestSuite) TestIsDomainBlockedAllowlistMode() {
+	ctx := context.Background()
+
+	config.SetInstanceFederationMode(config.InstanceFederationModeAllowlist)
+	defer config.SetInstanceFederationMode(config.InstanceFederationModeDefault)
+
+	domainAllow := &gtsmodel.DomainAllow{
+		ID:                 "01H8KY9MJQFWE712Y6KQH8TZ5R",
+		Domain:             "good.apples",
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+		CreatedByAccount:   suite.testAccounts["admin_account"],
+	}
+
+	// no domain allow exists for the given domain yet
+	blocked, err := suite.db.IsDomainBlocked(ctx, "some."+domainAllow.Domain)
+	suite.NoError(err)
+	suite.True(blocked)
+
+	err = suite.db.CreateDomainAllow(ctx, domainAllow)
+	suite.NoError(err)
+
+	// domain allow now exists, covering subdomains too
+	blocked, err = suite.db.IsDomainBlocked(ctx, "some."+domainAllow.Domain)
+	suite.NoError(err)
+	suite.False(blocked)
+
+	// other domains are still blocked
+	blocked, err = suite.db.IsDomainBlocked(ctx, "bad.apples")
+	suite.NoError(err)
+	suite.True(blocked)
+
+	// we never block ourselves
+	blocked, err = suite.db.IsDomainBlocked(ctx, config.GetHost())
+	suite.NoError(err)
+	suite.False(blocked)
+
+	domainBlock := &gtsmodel.DomainBlock{
+		ID:                 "01G204214Y9TNJEBX39C7G88SW",
+		Domain:             "some." + domainAllow.Domain,
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+		CreatedByAccount:   suite.testAccounts["admin_account"],
+	}
+
+	err = suite.db.CreateDomainBlock(ctx, domainBlock)
+	suite.NoError(err)
+
+	// explicit block takes precedence over the allow
+	blocked, err = suite.db.IsDomainBlocked(ctx, domainBlock.Domain)
+	suite.NoError(err)
+	suite.True(blocked)
+
+	// back in blocklist mode, allows don't matter
+	config.SetInstanceFederationMode(config.InstanceFederationModeBlocklist)
+	blocked, err = suite.db.IsDomainBlocked(ctx, "bad.apples")
+	suite.NoError(err)
+	suite.False(blocked)
+}
+
+func (suite *DomainTestSuite) TestDomainPermissionSubscriptions() {
+	ctx := context.Background()
+
+	adoptOrphans := false
+	lowPriority := &gtsmodel.DomainPermissionSubscription{
+		ID:                 "01H8AS2S39MJJXSXZVC1KG5QQF",
+		Priority:           10,
+		PermissionType:     gtsmodel.DomainPermissionBlock,
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+		URI:                "https://lists.example.org/low.csv",
+		ContentType:        "text/csv",
+		AdoptOrphans:       &adoptOrphans,
+	}
+	highPriority := &gtsmodel.DomainPermissionSubscription{
+		ID:                 "01H8AS3FQQN0P1TM2Y9PJSBW9K",
+		Priority:           200,
+		PermissionType:     gtsmodel.DomainPermissionBlock,
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+		URI:                "https://lists.example.org/high.csv",
+		ContentType:        "text/csv",
+		AdoptOrphans:       &adoptOrphans,
+	}
+
+	for _, sub := range []*gtsmodel.DomainPermissionSubscription{lowPriority, highPriority} {
+		err := suite.db.CreateDomainPermissionSubscription(ctx, sub)
+		suite.NoError(err)
+	}
+
+	// subscriptions should be returned highest priority first
+	subs, err := suite.db.GetDomainPermissionSubscriptions(ctx, gtsmodel.DomainPermissionBlock)
+	suite.NoError(err)
+	suite.Len(subs, 2)
+	suite.Equal(highPriority.ID, subs[0].ID)
+	suite.Equal(lowPriority.ID, subs[1].ID)
+
+	// no allow subscriptions exist
+	subs, err = suite.db.GetDomainPermissionSubscriptions(ctx, gtsmodel.DomainPermissionAllow)
+	suite.NoError(err)
+	suite.Empty(subs)
+
+	// duplicate uris are not allowed
+	duplicate := *lowPriority
+	duplicate.ID = "01H8AS4JQ5X3Z6YPW0QF8B6CEH"
+	err = suite.db.CreateDomainPermissionSubscription(ctx, &duplicate)
+	suite.ErrorIs(err, db.ErrAlreadyExists)
+
+	lowPriority.Error = "oh no"
+	err = suite.db.UpdateDomainPermissionSubscription(ctx, lowPriority, "error")
+	suite.NoError(err)
+
+	dbSub, err := suite.db.GetDomainPermissionSubscriptionByID(ctx, lowPriority.ID)
+	suite.NoError(err)
+	suite.Equal("oh no", dbSub.Error)
+
+	err = suite.db.DeleteDomainPermissionSubscription(ctx, lowPriority.ID)
+	suite.NoError(err)
+
+	_, err = suite.db.GetDomainPermissionSubscriptionByID(ctx, lowPriority.ID)
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
 func TestDomainTestSuite(t *testing.T) {
 	suite.Run(t, new(DomainTestSuite))
 }
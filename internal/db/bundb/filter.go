@@ -0,0 +1,525 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtscontext"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/uptrace/bun"
+)
+
+type filterDB struct {
+	db    *WrappedDB
+	state *state.State
+}
+
+/*
+	FILTER FUNCTIONS
+*/
+
+func (f *filterDB) GetFilterByID(ctx context.Context, id string) (*gtsmodel.Filter, error) {
+	filter, err := f.state.Caches.GTS.Filter().Load("ID", func() (*gtsmodel.Filter, error) {
+		var filter gtsmodel.Filter
+
+		// Not cached! Perform database query.
+		if err := f.db.
+			NewSelect().
+			Model(&filter).
+			Where("? = ?", bun.Ident("filter.id"), id).
+			Scan(ctx); err != nil {
+			return nil, f.db.ProcessError(err)
+		}
+
+		return &filter, nil
+	}, id)
+	if err != nil {
+		// already processed
+		return nil, err
+	}
+
+	if gtscontext.Barebones(ctx) {
+		// Only a barebones model was requested.
+		return filter, nil
+	}
+
+	if err := f.state.DB.PopulateFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func (f *filterDB) GetFiltersForAccountID(ctx context.Context, accountID string) ([]*gtsmodel.Filter, error) {
+	// Fetch IDs of all filters owned by this account.
+	var filterIDs []string
+	if err := f.db.
+		NewSelect().
+		TableExpr("? AS ?", bun.Ident("filters"), bun.Ident("filter")).
+		Column("filter.id").
+		Where("? = ?", bun.Ident("filter.account_id"), accountID).
+		Order("filter.id DESC").
+		Scan(ctx, &filterIDs); err != nil {
+		return nil, f.db.ProcessError(err)
+	}
+
+	if len(filterIDs) == 0 {
+		return nil, nil
+	}
+
+	// Select each filter using its ID to ensure cache used.
+	filters := make([]*gtsmodel.Filter, 0, len(filterIDs))
+	for _, id := range filterIDs {
+		filter, err := f.state.DB.GetFilterByID(ctx, id)
+		if err != nil {
+			log.Errorf(ctx, "error fetching filter %q: %v", id, err)
+			continue
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+func (f *filterDB) PopulateFilter(ctx context.Context, filter *gtsmodel.Filter) error {
+	var (
+		err  error
+		errs = gtserror.NewMultiError(2)
+	)
+
+	if filter.Keywords == nil {
+		// Filter keywords are not set, fetch from the database.
+		filter.Keywords, err = f.state.DB.GetFilterKeywordsForFilterID(
+			gtscontext.SetBarebones(ctx),
+			filter.ID,
+		)
+		if err != nil {
+			errs.Appendf("error populating filter keywords: %w", err)
+		}
+		for _, keyword := range filter.Keywords {
+			keyword.Filter = filter
+		}
+	}
+
+	if filter.Statuses == nil {
+		// Filter statuses are not set, fetch from the database.
+		filter.Statuses, err = f.state.DB.GetFilterStatusesForFilterID(
+			gtscontext.SetBarebones(ctx),
+			filter.ID,
+		)
+		if err != nil {
+			errs.Appendf("error populating filter statuses: %w", err)
+		}
+		for _, status := range filter.Statuses {
+			status.Filter = filter
+		}
+	}
+
+	if err := errs.Combine(); err != nil {
+		return gtserror.Newf("%w", err)
+	}
+
+	return nil
+}
+
+func (f *filterDB) PutFilter(ctx context.Context, filter *gtsmodel.Filter) error {
+	defer invalidateAccountTimelines(ctx, f.state, filter.AccountID)
+
+	// Insert the filter, along with any keywords and statuses, in one transaction.
+	return f.db.RunInTx(ctx, func(tx bun.Tx) error {
+		if _, err := tx.
+			NewInsert().
+			Model(filter).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		if len(filter.Keywords) > 0 {
+			if _, err := tx.
+				NewInsert().
+				Model(&filter.Keywords).
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		if len(filter.Statuses) > 0 {
+			if _, err := tx.
+				NewInsert().
+				Model(&filter.Statuses).
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (f *filterDB) UpdateFilter(ctx context.Context, filter *gtsmodel.Filter, columns ...string) error {
+	filter.UpdatedAt = time.Now()
+	if len(columns) > 0 {
+		// If we're updating by column, ensure "updated_at" is included.
+		columns = append(columns, "updated_at")
+	}
+
+	defer invalidateAccountTimelines(ctx, f.state, filter.AccountID)
+
+	return f.state.Caches.GTS.Filter().Store(filter, func() error {
+		_, err := f.db.
+			NewUpdate().
+			Model(filter).
+			Where("? = ?", bun.Ident("filter.id"), filter.ID).
+			Column(columns...).
+			Exec(ctx)
+		return f.db.ProcessError(err)
+	})
+}
+
+func (f *filterDB) DeleteFilterByID(ctx context.Context, id string) error {
+	// Load filter by ID into cache to ensure we can perform
+	// all necessary cache invalidation hooks on removal.
+	filter, err := f.GetFilterByID(
+		// Don't populate the filter;
+		// we only want the filter ID.
+		gtscontext.SetBarebones(ctx),
+		id,
+	)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		// NOTE: even if db.ErrNoEntries is returned, we
+		// still run the below transaction to ensure related
+		// objects are appropriately deleted.
+		return err
+	}
+
+	defer func() {
+		// Invalidate this filter (and its keywords + statuses) from cache.
+		f.state.Caches.GTS.Filter().Invalidate("ID", id)
+
+		if filter != nil {
+			invalidateAccountTimelines(ctx, f.state, filter.AccountID)
+		}
+	}()
+
+	return f.db.RunInTx(ctx, func(tx bun.Tx) error {
+		// Delete all keywords attached to filter.
+		if _, err := tx.NewDelete().
+			Table("filter_keywords").
+			Where("? = ?", bun.Ident("filter_id"), id).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		// Delete all statuses attached to filter.
+		if _, err := tx.NewDelete().
+			Table("filter_statuses").
+			Where("? = ?", bun.Ident("filter_id"), id).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		// Delete the filter itself.
+		_, err := tx.NewDelete().
+			Table("filters").
+			Where("? = ?", bun.Ident("id"), id).
+			Exec(ctx)
+		return err
+	})
+}
+
+/*
+	FILTER KEYWORD FUNCTIONS
+*/
+
+func (f *filterDB) GetFilterKeywordByID(ctx context.Context, id string) (*gtsmodel.FilterKeyword, error) {
+	filterKeyword, err := f.state.Caches.GTS.FilterKeyword().Load("ID", func() (*gtsmodel.FilterKeyword, error) {
+		var filterKeyword gtsmodel.FilterKeyword
+
+		// Not cached! Perform database query.
+		if err := f.db.
+			NewSelect().
+			Model(&filterKeyword).
+			Where("? = ?", bun.Ident("filter_keyword.id"), id).
+			Scan(ctx); err != nil {
+			return nil, f.db.ProcessError(err)
+		}
+
+		return &filterKeyword, nil
+	}, id)
+	if err != nil {
+		// already processed
+		return nil, err
+	}
+
+	// Prepare the keyword's regular expression.
+	if err := filterKeyword.Compile(); err != nil {
+		return nil, gtserror.Newf("error compiling filter keyword regex: %w", err)
+	}
+
+	if gtscontext.Barebones(ctx) {
+		// Only a barebones model was requested.
+		return filterKeyword, nil
+	}
+
+	if err := f.state.DB.PopulateFilterKeyword(ctx, filterKeyword); err != nil {
+		return nil, err
+	}
+
+	return filterKeyword, nil
+}
+
+func (f *filterDB) GetFilterKeywordsForFilterID(ctx context.Context, filterID string) ([]*gtsmodel.FilterKeyword, error) {
+	return f.getFilterKeywords(ctx, "filter_id", filterID)
+}
+
+func (f *filterDB) GetFilterKeywordsForAccountID(ctx context.Context, accountID string) ([]*gtsmodel.FilterKeyword, error) {
+	return f.getFilterKeywords(ctx, "account_id", accountID)
+}
+
+func (f *filterDB) getFilterKeywords(ctx context.Context, idColumn string, id string) ([]*gtsmodel.FilterKeyword, error) {
+	var filterKeywordIDs []string
+	if err := f.db.
+		NewSelect().
+		TableExpr("? AS ?", bun.Ident("filter_keywords"), bun.Ident("filter_keyword")).
+		Column("filter_keyword.id").
+		Where("? = ?", bun.Ident("filter_keyword."+idColumn), id).
+		Order("filter_keyword.id ASC").
+		Scan(ctx, &filterKeywordIDs); err != nil {
+		return nil, f.db.ProcessError(err)
+	}
+
+	if len(filterKeywordIDs) == 0 {
+		return nil, nil
+	}
+
+	// Select each filter keyword using its ID to ensure cache used.
+	filterKeywords := make([]*gtsmodel.FilterKeyword, 0, len(filterKeywordIDs))
+	for _, id := range filterKeywordIDs {
+		filterKeyword, err := f.state.DB.GetFilterKeywordByID(ctx, id)
+		if err != nil {
+			log.Errorf(ctx, "error fetching filter keyword %q: %v", id, err)
+			continue
+		}
+		filterKeywords = append(filterKeywords, filterKeyword)
+	}
+
+	return filterKeywords, nil
+}
+
+func (f *filterDB) PopulateFilterKeyword(ctx context.Context, filterKeyword *gtsmodel.FilterKeyword) error {
+	if filterKeyword.Filter == nil {
+		// Filter is not set, fetch from the cache or database.
+		filter, err := f.state.DB.GetFilterByID(
+			// Don't populate the filter with all of its keywords and statuses or we'll just end up back here.
+			gtscontext.SetBarebones(ctx),
+			filterKeyword.FilterID,
+		)
+		if err != nil {
+			return gtserror.Newf("error populating filter keyword filter: %w", err)
+		}
+		filterKeyword.Filter = filter
+	}
+
+	return nil
+}
+
+func (f *filterDB) PutFilterKeyword(ctx context.Context, filterKeyword *gtsmodel.FilterKeyword) error {
+	defer invalidateAccountTimelines(ctx, f.state, filterKeyword.AccountID)
+
+	return f.state.Caches.GTS.FilterKeyword().Store(filterKeyword, func() error {
+		_, err := f.db.
+			NewInsert().
+			Model(filterKeyword).
+			Exec(ctx)
+		return f.db.ProcessError(err)
+	})
+}
+
+func (f *filterDB) UpdateFilterKeyword(ctx context.Context, filterKeyword *gtsmodel.FilterKeyword, columns ...string) error {
+	filterKeyword.UpdatedAt = time.Now()
+	if len(columns) > 0 {
+		// If we're updating by column, ensure "updated_at" is included.
+		columns = append(columns, "updated_at")
+	}
+
+	defer invalidateAccountTimelines(ctx, f.state, filterKeyword.AccountID)
+
+	return f.state.Caches.GTS.FilterKeyword().Store(filterKeyword, func() error {
+		_, err := f.db.
+			NewUpdate().
+			Model(filterKeyword).
+			Where("? = ?", bun.Ident("filter_keyword.id"), filterKeyword.ID).
+			Column(columns...).
+			Exec(ctx)
+		return f.db.ProcessError(err)
+	})
+}
+
+func (f *filterDB) DeleteFilterKeywordByID(ctx context.Context, id string) error {
+	filterKeyword, err := f.GetFilterKeywordByID(gtscontext.SetBarebones(ctx), id)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			// Already gone.
+			return nil
+		}
+		return err
+	}
+
+	defer func() {
+		f.state.Caches.GTS.FilterKeyword().Invalidate("ID", id)
+		invalidateAccountTimelines(ctx, f.state, filterKeyword.AccountID)
+	}()
+
+	if _, err := f.db.
+		NewDelete().
+		Table("filter_keywords").
+		Where("? = ?", bun.Ident("id"), id).
+		Exec(ctx); err != nil {
+		return f.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+/*
+	FILTER STATUS FUNCTIONS
+*/
+
+func (f *filterDB) GetFilterStatusByID(ctx context.Context, id string) (*gtsmodel.FilterStatus, error) {
+	filterStatus, err := f.state.Caches.GTS.FilterStatus().Load("ID", func() (*gtsmodel.FilterStatus, error) {
+		var filterStatus gtsmodel.FilterStatus
+
+		// Not cached! Perform database query.
+		if err := f.db.
+			NewSelect().
+			Model(&filterStatus).
+			Where("? = ?", bun.Ident("filter_status.id"), id).
+			Scan(ctx); err != nil {
+			return nil, f.db.ProcessError(err)
+		}
+
+		return &filterStatus, nil
+	}, id)
+	if err != nil {
+		// already processed
+		return nil, err
+	}
+
+	if gtscontext.Barebones(ctx) {
+		// Only a barebones model was requested.
+		return filterStatus, nil
+	}
+
+	if err := f.state.DB.PopulateFilterStatus(ctx, filterStatus); err != nil {
+		return nil, err
+	}
+
+	return filterStatus, nil
+}
+
+func (f *filterDB) GetFilterStatusesForFilterID(ctx context.Context, filterID string) ([]*gtsmodel.FilterStatus, error) {
+	var filterStatusIDs []string
+	if err := f.db.
+		NewSelect().
+		TableExpr("? AS ?", bun.Ident("filter_statuses"), bun.Ident("filter_status")).
+		Column("filter_status.id").
+		Where("? = ?", bun.Ident("filter_status.filter_id"), filterID).
+		Order("filter_status.id ASC").
+		Scan(ctx, &filterStatusIDs); err != nil {
+		return nil, f.db.ProcessError(err)
+	}
+
+	if len(filterStatusIDs) == 0 {
+		return nil, nil
+	}
+
+	// Select each filter status using its ID to ensure cache used.
+	filterStatuses := make([]*gtsmodel.FilterStatus, 0, len(filterStatusIDs))
+	for _, id := range filterStatusIDs {
+		filterStatus, err := f.state.DB.GetFilterStatusByID(ctx, id)
+		if err != nil {
+			log.Errorf(ctx, "error fetching filter status %q: %v", id, err)
+			continue
+		}
+		filterStatuses = append(filterStatuses, filterStatus)
+	}
+
+	return filterStatuses, nil
+}
+
+func (f *filterDB) PopulateFilterStatus(ctx context.Context, filterStatus *gtsmodel.FilterStatus) error {
+	if filterStatus.Filter == nil {
+		// Filter is not set, fetch from the cache or database.
+		filter, err := f.state.DB.GetFilterByID(
+			// Don't populate the filter with all of its keywords and statuses or we'll just end up back here.
+			gtscontext.SetBarebones(ctx),
+			filterStatus.FilterID,
+		)
+		if err != nil {
+			return gtserror.Newf("error populating filter status filter: %w", err)
+		}
+		filterStatus.Filter = filter
+	}
+
+	return nil
+}
+
+func (f *filterDB) PutFilterStatus(ctx context.Context, filterStatus *gtsmodel.FilterStatus) error {
+	defer invalidateAccountTimelines(ctx, f.state, filterStatus.AccountID)
+
+	return f.state.Caches.GTS.FilterStatus().Store(filterStatus, func() error {
+		_, err := f.db.
+			NewInsert().
+			Model(filterStatus).
+			Exec(ctx)
+		return f.db.ProcessError(err)
+	})
+}
+
+func (f *filterDB) DeleteFilterStatusByID(ctx context.Context, id string) error {
+	filterStatus, err := f.GetFilterStatusByID(gtscontext.SetBarebones(ctx), id)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			// Already gone.
+			return nil
+		}
+		return err
+	}
+
+	defer func() {
+		f.state.Caches.GTS.FilterStatus().Invalidate("ID", id)
+		invalidateAccountTimelines(ctx, f.state, filterStatus.AccountID)
+	}()
+
+	if _, err := f.db.
+		NewDelete().
+		Table("filter_statuses").
+		Where("? = ?", bun.Ident("id"), id).
+		Exec(ctx); err != nil {
+		return f.db.ProcessError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+type FilterTestSuite struct {
+	BunDBStandardTestSuite
+}
+
+func (suite *FilterTestSuite) TestGetFilterByID() {
+	testFilter := suite.testFilters["local_account_1_filter_1"]
+
+	filter, err := suite.db.GetFilterByID(context.Background(), testFilter.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal(testFilter.ID, filter.ID)
+	suite.Equal(testFilter.Title, filter.Title)
+	suite.Equal(testFilter.Action, filter.Action)
+	suite.Len(filter.Keywords, 1)
+	suite.Len(filter.Statuses, 1)
+	suite.Equal(suite.testFilterKeywords["local_account_1_filter_1_keyword_1"].ID, filter.Keywords[0].ID)
+	suite.Equal(suite.testFilterStatuses["local_account_1_filter_1_status_1"].ID, filter.Statuses[0].ID)
+}
+
+func (suite *FilterTestSuite) TestGetFilterKeywordByIDCompilesRegexp() {
+	testFilterKeyword := suite.testFilterKeywords["local_account_1_filter_1_keyword_1"]
+
+	filterKeyword, err := suite.db.GetFilterKeywordByID(context.Background(), testFilterKeyword.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.NotNil(filterKeyword.Regexp)
+	suite.True(filterKeyword.Regexp.MatchString("what is FNORD anyway"))
+	suite.False(filterKeyword.Regexp.MatchString("fnordish"))
+	suite.NotNil(filterKeyword.Filter)
+}
+
+func (suite *FilterTestSuite) TestPutUpdateDeleteFilter() {
+	var (
+		ctx         = context.Background()
+		testAccount = suite.testAccounts["local_account_1"]
+	)
+
+	filter := &gtsmodel.Filter{
+		ID:                   "01H7A1ZBCQXH0KT6D3XRZ5YHPT",
+		AccountID:            testAccount.ID,
+		Title:                "cats",
+		Action:               gtsmodel.FilterActionHide,
+		ContextHome:          util.Ptr(true),
+		ContextNotifications: util.Ptr(false),
+		ContextPublic:        util.Ptr(true),
+		ContextThread:        util.Ptr(false),
+		ContextAccount:       util.Ptr(false),
+	}
+	filter.Keywords = []*gtsmodel.FilterKeyword{
+		{
+			ID:        "01H7A1ZBCQ5Y4FCA0CQ2DNPQ4D",
+			AccountID: testAccount.ID,
+			FilterID:  filter.ID,
+			Keyword:   "cat",
+			WholeWord: util.Ptr(false),
+		},
+	}
+
+	if err := suite.db.PutFilter(ctx, filter); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	filters, err := suite.db.GetFiltersForAccountID(ctx, testAccount.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Len(filters, 2)
+
+	// Adding the same keyword again should fail.
+	err = suite.db.PutFilterKeyword(ctx, &gtsmodel.FilterKeyword{
+		ID:        "01H7A1ZBCQ0ZQ3A6GGW2J9TC1Y",
+		AccountID: testAccount.ID,
+		FilterID:  filter.ID,
+		Keyword:   "cat",
+		WholeWord: util.Ptr(true),
+	})
+	suite.ErrorIs(err, db.ErrAlreadyExists)
+
+	filter.Title = "dogs"
+	if err := suite.db.UpdateFilter(ctx, filter, "title"); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	dbFilter, err := suite.db.GetFilterByID(ctx, filter.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal("dogs", dbFilter.Title)
+
+	if err := suite.db.DeleteFilterByID(ctx, filter.ID); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	_, err = suite.db.GetFilterByID(ctx, filter.ID)
+	suite.ErrorIs(err, db.ErrNoEntries)
+
+	_, err = suite.db.GetFilterKeywordByID(ctx, filter.Keywords[0].ID)
+	if !errors.Is(err, db.ErrNoEntries) {
+		suite.FailNow("expected filter keyword to be deleted along with filter")
+	}
+}
+
+func TestFilterTestSuite(t *testing.T) {
+	suite.Run(t, new(FilterTestSuite))
+}
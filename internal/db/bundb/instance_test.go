@@ -128,6 +128,15 @@ func (suite *InstanceTestSuite) TestGetInstanceModeratorAddressesNoAdmin() {
 	suite.Empty(addresses)
 }
 
+func (suite *InstanceTestSuite) TestGetInstanceModerators() {
+	// We have one admin user by default.
+	moderators, err := suite.db.GetInstanceModerators(context.Background())
+	suite.NoError(err)
+	if suite.Len(moderators, 1) {
+		suite.Equal(suite.testAccounts["admin_account"].ID, moderators[0].ID)
+	}
+}
+
 func TestInstanceTestSuite(t *testing.T) {
 	suite.Run(t, new(InstanceTestSuite))
 }
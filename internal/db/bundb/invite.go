@@ -0,0 +1,186 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/uptrace/bun"
+)
+
+type inviteDB struct {
+	db    *WrappedDB
+	state *state.State
+}
+
+func (i *inviteDB) GetInviteByID(ctx context.Context, id string) (*gtsmodel.Invite, error) {
+	return i.getInvite(ctx, "id", id)
+}
+
+func (i *inviteDB) GetInviteByCode(ctx context.Context, code string) (*gtsmodel.Invite, error) {
+	return i.getInvite(ctx, "code", code)
+}
+
+func (i *inviteDB) getInvite(ctx context.Context, column string, value string) (*gtsmodel.Invite, error) {
+	var invite gtsmodel.Invite
+
+	if err := i.db.
+		NewSelect().
+		Model(&invite).
+		Where("? = ?", bun.Ident("invite."+column), value).
+		Scan(ctx); err != nil {
+		return nil, i.db.ProcessError(err)
+	}
+
+	return &invite, nil
+}
+
+func (i *inviteDB) GetInvitesByUserID(ctx context.Context, userID string) ([]*gtsmodel.Invite, error) {
+	invites := []*gtsmodel.Invite{}
+
+	if err := i.db.
+		NewSelect().
+		Model(&invites).
+		Where("? = ?", bun.Ident("invite.user_id"), userID).
+		OrderExpr("? DESC", bun.Ident("invite.id")).
+		Scan(ctx); err != nil {
+		return nil, i.db.ProcessError(err)
+	}
+
+	return invites, nil
+}
+
+func (i *inviteDB) PopulateInvite(ctx context.Context, invite *gtsmodel.Invite) error {
+	if invite.User == nil {
+		// Invite user is not set, fetch from database.
+		user, err := i.state.DB.GetUserByID(ctx, invite.UserID)
+		if err != nil {
+			return gtserror.Newf("error populating invite user: %w", err)
+		}
+		invite.User = user
+	}
+
+	return nil
+}
+
+func (i *inviteDB) PutInvite(ctx context.Context, invite *gtsmodel.Invite) error {
+	if _, err := i.db.
+		NewInsert().
+		Model(invite).
+		Exec(ctx); err != nil {
+		return i.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+func (i *inviteDB) UpdateInvite(ctx context.Context, invite *gtsmodel.Invite, columns ...string) error {
+	invite.UpdatedAt = time.Now()
+	if len(columns) > 0 {
+		// If we're updating by column,
+		// ensure "updated_at" is included.
+		columns = append(columns, "updated_at")
+	}
+
+	if _, err := i.db.
+		NewUpdate().
+		Model(invite).
+		Column(columns...).
+		Where("? = ?", bun.Ident("invite.id"), invite.ID).
+		Exec(ctx); err != nil {
+		return i.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+func (i *inviteDB) ClaimInviteUse(ctx context.Context, invite *gtsmodel.Invite, now time.Time) error {
+	// Check validity in the same statement as
+	// the increment, so that concurrent sign-ups
+	// can't take the invite past its max uses.
+	result, err := i.db.
+		NewUpdate().
+		TableExpr("? AS ?", bun.Ident("invites"), bun.Ident("invite")).
+		Set("? = ? + 1", bun.Ident("uses"), bun.Ident("uses")).
+		Set("? = ?", bun.Ident("updated_at"), now).
+		Where("? = ?", bun.Ident("invite.id"), invite.ID).
+		WhereGroup(" AND ", func(q *bun.UpdateQuery) *bun.UpdateQuery {
+			return q.
+				Where("? = 0", bun.Ident("invite.max_uses")).
+				WhereOr("? < ?", bun.Ident("invite.uses"), bun.Ident("invite.max_uses"))
+		}).
+		WhereGroup(" AND ", func(q *bun.UpdateQuery) *bun.UpdateQuery {
+			return q.
+				Where("? IS NULL", bun.Ident("invite.expires_at")).
+				WhereOr("? > ?", bun.Ident("invite.expires_at"), now)
+		}).
+		Exec(ctx)
+	if err != nil {
+		return i.db.ProcessError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return i.db.ProcessError(err)
+	}
+
+	if rowsAffected == 0 {
+		// Expired, used up, or gone.
+		return db.ErrNoEntries
+	}
+
+	invite.Uses++
+	invite.UpdatedAt = now
+	return nil
+}
+
+func (i *inviteDB) ReleaseInviteUse(ctx context.Context, invite *gtsmodel.Invite) error {
+	invite.UpdatedAt = time.Now()
+	if _, err := i.db.
+		NewUpdate().
+		TableExpr("? AS ?", bun.Ident("invites"), bun.Ident("invite")).
+		Set("? = ? - 1", bun.Ident("uses"), bun.Ident("uses")).
+		Set("? = ?", bun.Ident("updated_at"), invite.UpdatedAt).
+		Where("? = ?", bun.Ident("invite.id"), invite.ID).
+		Where("? > 0", bun.Ident("invite.uses")).
+		Exec(ctx); err != nil {
+		return i.db.ProcessError(err)
+	}
+
+	if invite.Uses > 0 {
+		invite.Uses--
+	}
+	return nil
+}
+
+func (i *inviteDB) DeleteInvitesByUserID(ctx context.Context, userID string) error {
+	if _, err := i.db.
+		NewDelete().
+		TableExpr("? AS ?", bun.Ident("invites"), bun.Ident("invite")).
+		Where("? = ?", bun.Ident("invite.user_id"), userID).
+		Exec(ctx); err != nil {
+		return i.db.ProcessError(err)
+	}
+
+	return nil
+}
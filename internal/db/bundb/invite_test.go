@@ -0,0 +1,120 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+)
+
+type InviteTestSuite struct {
+	BunDBStandardTestSuite
+}
+
+func (suite *InviteTestSuite) TestClaimInviteUseUntilUsedUp() {
+	ctx := context.Background()
+
+	// This invite has 1 use out of 5.
+	invite, err := suite.db.GetInviteByID(ctx, "01H9PQ6Y3W6N1DHZ8Y8T3QNMB2")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	for i := 0; i < 4; i++ {
+		suite.NoError(suite.db.ClaimInviteUse(ctx, invite, time.Now()))
+	}
+	suite.Equal(5, invite.Uses)
+
+	// Used up now.
+	suite.ErrorIs(suite.db.ClaimInviteUse(ctx, invite, time.Now()), db.ErrNoEntries)
+
+	dbInvite, err := suite.db.GetInviteByID(ctx, invite.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal(5, dbInvite.Uses)
+
+	// Giving a use back makes it claimable again.
+	suite.NoError(suite.db.ReleaseInviteUse(ctx, invite))
+	suite.NoError(suite.db.ClaimInviteUse(ctx, invite, time.Now()))
+}
+
+func (suite *InviteTestSuite) TestClaimInviteUseConcurrent() {
+	ctx := context.Background()
+
+	invite, err := suite.db.GetInviteByID(ctx, "01H9PQ6Y3W6N1DHZ8Y8T3QNMB2")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		claimed int
+	)
+
+	// 10 sign-ups race for the remaining 4 uses.
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// Each sign-up has its own copy of the invite.
+			copied := *invite
+			if err := suite.db.ClaimInviteUse(ctx, &copied, time.Now()); err == nil {
+				mu.Lock()
+				claimed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	suite.Equal(4, claimed)
+
+	dbInvite, err := suite.db.GetInviteByID(ctx, invite.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal(5, dbInvite.Uses)
+}
+
+func (suite *InviteTestSuite) TestClaimInviteUseExpired() {
+	ctx := context.Background()
+
+	// This invite expired in 2023.
+	invite, err := suite.db.GetInviteByID(ctx, "01H9PQAE8A2DJ6Q7GZ5NAQ1F7E")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.ErrorIs(suite.db.ClaimInviteUse(ctx, invite, time.Now()), db.ErrNoEntries)
+	suite.Equal(0, invite.Uses)
+
+	// But it could be claimed before it expired.
+	suite.NoError(suite.db.ClaimInviteUse(ctx, invite, invite.ExpiresAt.Add(-time.Hour)))
+}
+
+func TestInviteTestSuite(t *testing.T) {
+	suite.Run(t, new(InviteTestSuite))
+}
@@ -0,0 +1,503 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtscontext"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/uptrace/bun"
+)
+
+type pollDB struct {
+	db    *WrappedDB
+	state *state.State
+}
+
+func (p *pollDB) GetPollByID(ctx context.Context, id string) (*gtsmodel.Poll, error) {
+	return p.getPoll(
+		ctx,
+		"ID",
+		func(poll *gtsmodel.Poll) error {
+			return p.db.NewSelect().
+				Model(poll).
+				Where("? = ?", bun.Ident("poll.id"), id).
+				Scan(ctx)
+		},
+		id,
+	)
+}
+
+func (p *pollDB) GetPollByStatusID(ctx context.Context, statusID string) (*gtsmodel.Poll, error) {
+	return p.getPoll(
+		ctx,
+		"StatusID",
+		func(poll *gtsmodel.Poll) error {
+			return p.db.NewSelect().
+				Model(poll).
+				Where("? = ?", bun.Ident("poll.status_id"), statusID).
+				Scan(ctx)
+		},
+		statusID,
+	)
+}
+
+func (p *pollDB) getPoll(ctx context.Context, lookup string, dbQuery func(*gtsmodel.Poll) error, keyParts ...any) (*gtsmodel.Poll, error) {
+	// Fetch poll from database cache with loader callback
+	poll, err := p.state.Caches.GTS.Poll().Load(lookup, func() (*gtsmodel.Poll, error) {
+		var poll gtsmodel.Poll
+
+		// Not cached! Perform database query.
+		if err := dbQuery(&poll); err != nil {
+			return nil, p.db.ProcessError(err)
+		}
+
+		// Ensure vote slice
+		// is expected length.
+		poll.CheckVotes()
+
+		return &poll, nil
+	}, keyParts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if gtscontext.Barebones(ctx) {
+		// no need to fully populate.
+		return poll, nil
+	}
+
+	// Further populate the poll fields where applicable.
+	if err := p.PopulatePoll(ctx, poll); err != nil {
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+func (p *pollDB) GetExpiredPolls(ctx context.Context) ([]*gtsmodel.Poll, error) {
+	var pollIDs []string
+
+	// Select all polls with:
+	// - an expiry date in the past
+	// - not yet closed
+	if err := p.db.NewSelect().
+		Table("polls").
+		Column("id").
+		Where("? <= ?", bun.Ident("expires_at"), time.Now()).
+		Where("? IS NULL", bun.Ident("closed_at")).
+		Scan(ctx, &pollIDs); err != nil {
+		return nil, p.db.ProcessError(err)
+	}
+
+	// Preallocate a slice to contain the poll models.
+	polls := make([]*gtsmodel.Poll, 0, len(pollIDs))
+
+	for _, id := range pollIDs {
+		// Attempt to fetch poll from DB.
+		poll, err := p.GetPollByID(ctx, id)
+		if err != nil {
+			log.Errorf(ctx, "error getting poll %s: %v", id, err)
+			continue
+		}
+
+		// Append poll to return slice.
+		polls = append(polls, poll)
+	}
+
+	return polls, nil
+}
+
+func (p *pollDB) PopulatePoll(ctx context.Context, poll *gtsmodel.Poll) error {
+	var err error
+
+	if poll.Status == nil {
+		// Poll status is not set, fetch from database.
+		poll.Status, err = p.state.DB.GetStatusByID(
+			gtscontext.SetBarebones(ctx),
+			poll.StatusID,
+		)
+		if err != nil {
+			return gtserror.Newf("error populating poll status: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *pollDB) PutPoll(ctx context.Context, poll *gtsmodel.Poll) error {
+	// Ensure vote slice
+	// is expected length.
+	poll.CheckVotes()
+
+	return p.state.Caches.GTS.Poll().Store(poll, func() error {
+		_, err := p.db.NewInsert().Model(poll).Exec(ctx)
+		return p.db.ProcessError(err)
+	})
+}
+
+func (p *pollDB) UpdatePoll(ctx context.Context, poll *gtsmodel.Poll, cols ...string) error {
+	// Ensure vote slice
+	// is expected length.
+	poll.CheckVotes()
+
+	poll.UpdatedAt = time.Now()
+	if len(cols) > 0 {
+		// If we're updating by column, ensure "updated_at" is included.
+		cols = append(cols, "updated_at")
+	}
+
+	return p.state.Caches.GTS.Poll().Store(poll, func() error {
+		_, err := p.db.NewUpdate().
+			Model(poll).
+			Column(cols...).
+			Where("? = ?", bun.Ident("poll.id"), poll.ID).
+			Exec(ctx)
+		return p.db.ProcessError(err)
+	})
+}
+
+func (p *pollDB) DeletePollByID(ctx context.Context, id string) error {
+	if err := p.db.RunInTx(ctx, func(tx bun.Tx) error {
+		// Delete all votes in poll.
+		if _, err := tx.NewDelete().
+			Table("poll_votes").
+			Where("? = ?", bun.Ident("poll_id"), id).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		// Delete the poll itself.
+		_, err := tx.NewDelete().
+			Table("polls").
+			Where("? = ?", bun.Ident("id"), id).
+			Exec(ctx)
+		return err
+	}); err != nil {
+		return p.db.ProcessError(err)
+	}
+
+	// Invalidate poll by ID from cache.
+	p.state.Caches.GTS.Poll().Invalidate("ID", id)
+
+	// Invalidate all related poll votes and vote IDs.
+	p.state.Caches.GTS.PollVote().Invalidate("PollID", id)
+	p.state.Caches.GTS.PollVoteIDs().Invalidate(id)
+
+	return nil
+}
+
+func (p *pollDB) GetPollVoteByID(ctx context.Context, id string) (*gtsmodel.PollVote, error) {
+	return p.getPollVote(
+		ctx,
+		"ID",
+		func(vote *gtsmodel.PollVote) error {
+			return p.db.NewSelect().
+				Model(vote).
+				Where("? = ?", bun.Ident("poll_vote.id"), id).
+				Scan(ctx)
+		},
+		id,
+	)
+}
+
+func (p *pollDB) GetPollVoteBy(ctx context.Context, pollID string, accountID string) (*gtsmodel.PollVote, error) {
+	return p.getPollVote(
+		ctx,
+		"PollID.AccountID",
+		func(vote *gtsmodel.PollVote) error {
+			return p.db.NewSelect().
+				Model(vote).
+				Where("? = ?", bun.Ident("poll_vote.poll_id"), pollID).
+				Where("? = ?", bun.Ident("poll_vote.account_id"), accountID).
+				Scan(ctx)
+		},
+		pollID,
+		accountID,
+	)
+}
+
+func (p *pollDB) getPollVote(ctx context.Context, lookup string, dbQuery func(*gtsmodel.PollVote) error, keyParts ...any) (*gtsmodel.PollVote, error) {
+	// Fetch vote from database cache with loader callback
+	vote, err := p.state.Caches.GTS.PollVote().Load(lookup, func() (*gtsmodel.PollVote, error) {
+		var vote gtsmodel.PollVote
+
+		// Not cached! Perform database query.
+		if err := dbQuery(&vote); err != nil {
+			return nil, p.db.ProcessError(err)
+		}
+
+		return &vote, nil
+	}, keyParts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if gtscontext.Barebones(ctx) {
+		// no need to fully populate.
+		return vote, nil
+	}
+
+	// Populate the vote model.
+	if err := p.PopulatePollVote(ctx, vote); err != nil {
+		return nil, err
+	}
+
+	return vote, nil
+}
+
+func (p *pollDB) GetPollVotes(ctx context.Context, pollID string) ([]*gtsmodel.PollVote, error) {
+	// Fetch the vote IDs for poll.
+	voteIDs, err := p.getPollVoteIDs(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Preallocate slice of expected length.
+	votes := make([]*gtsmodel.PollVote, 0, len(voteIDs))
+
+	for _, id := range voteIDs {
+		// Attempt to fetch vote from DB.
+		vote, err := p.GetPollVoteByID(ctx, id)
+		if err != nil {
+			log.Errorf(ctx, "error getting poll vote %s: %v", id, err)
+			continue
+		}
+
+		// Append vote to slice.
+		votes = append(votes, vote)
+	}
+
+	return votes, nil
+}
+
+func (p *pollDB) getPollVoteIDs(ctx context.Context, pollID string) ([]string, error) {
+	return p.state.Caches.GTS.PollVoteIDs().Load(pollID, func() ([]string, error) {
+		var voteIDs []string
+
+		// Vote IDs not in cache, perform DB query!
+		if err := p.db.NewSelect().
+			Table("poll_votes").
+			Column("id").
+			Where("? = ?", bun.Ident("poll_id"), pollID).
+			Scan(ctx, &voteIDs); err != nil {
+			return nil, p.db.ProcessError(err)
+		}
+
+		return voteIDs, nil
+	})
+}
+
+func (p *pollDB) PopulatePollVote(ctx context.Context, vote *gtsmodel.PollVote) error {
+	var (
+		err  error
+		errs = gtserror.NewMultiError(2)
+	)
+
+	if vote.Account == nil {
+		// Vote account is not set, fetch from database.
+		vote.Account, err = p.state.DB.GetAccountByID(
+			gtscontext.SetBarebones(ctx),
+			vote.AccountID,
+		)
+		if err != nil {
+			errs.Appendf("error populating vote account: %w", err)
+		}
+	}
+
+	if vote.Poll == nil {
+		// Vote poll is not set, fetch from database.
+		vote.Poll, err = p.GetPollByID(
+			gtscontext.SetBarebones(ctx),
+			vote.PollID,
+		)
+		if err != nil {
+			errs.Appendf("error populating vote poll: %w", err)
+		}
+	}
+
+	if err := errs.Combine(); err != nil {
+		return gtserror.Newf("%w", err)
+	}
+
+	return nil
+}
+
+func (p *pollDB) PutPollVote(ctx context.Context, vote *gtsmodel.PollVote) error {
+	return p.state.Caches.GTS.PollVote().Store(vote, func() error {
+		return p.db.RunInTx(ctx, func(tx bun.Tx) error {
+			// Try insert vote into database.
+			if _, err := tx.NewInsert().
+				Model(vote).
+				Exec(ctx); err != nil {
+				return err
+			}
+
+			var poll gtsmodel.Poll
+
+			// Select poll counts from DB.
+			if err := tx.NewSelect().
+				Model(&poll).
+				Column("options", "votes", "voters").
+				Where("? = ?", bun.Ident("poll.id"), vote.PollID).
+				Scan(ctx); err != nil {
+				return err
+			}
+
+			// Increment poll votes for choices.
+			poll.IncrementVotes(vote.Choices)
+
+			// Finally, update the poll entry.
+			_, err := tx.NewUpdate().
+				Model(&poll).
+				Column("votes", "voters").
+				Where("? = ?", bun.Ident("poll.id"), vote.PollID).
+				Exec(ctx)
+			return err
+		})
+	})
+}
+
+func (p *pollDB) DeletePollVotes(ctx context.Context, pollID string) error {
+	err := p.db.RunInTx(ctx, func(tx bun.Tx) error {
+		// Delete all votes in poll.
+		if _, err := tx.NewDelete().
+			Table("poll_votes").
+			Where("? = ?", bun.Ident("poll_id"), pollID).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		var poll gtsmodel.Poll
+
+		// Select poll options from DB.
+		if err := tx.NewSelect().
+			Model(&poll).
+			Column("options").
+			Where("? = ?", bun.Ident("poll.id"), pollID).
+			Scan(ctx); err != nil {
+			return err
+		}
+
+		// Reset all votes.
+		poll.ResetVotes()
+
+		// Finally, update the poll entry.
+		_, err := tx.NewUpdate().
+			Model(&poll).
+			Column("votes", "voters").
+			Where("? = ?", bun.Ident("poll.id"), pollID).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil &&
+		!errors.Is(err, db.ErrNoEntries) {
+		return p.db.ProcessError(err)
+	}
+
+	// Invalidate poll vote and poll entry from caches.
+	p.state.Caches.GTS.Poll().Invalidate("ID", pollID)
+	p.state.Caches.GTS.PollVote().Invalidate("PollID", pollID)
+	p.state.Caches.GTS.PollVoteIDs().Invalidate(pollID)
+
+	return nil
+}
+
+func (p *pollDB) DeletePollVoteBy(ctx context.Context, pollID string, accountID string) error {
+	// Fetch poll vote for this account in poll.
+	vote, err := p.GetPollVoteBy(ctx, pollID, accountID)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			// Already gone.
+			return nil
+		}
+		return err
+	}
+
+	if err := p.db.RunInTx(ctx, func(tx bun.Tx) error {
+		// Delete this vote from database.
+		if _, err := tx.NewDelete().
+			Table("poll_votes").
+			Where("? = ?", bun.Ident("id"), vote.ID).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		var poll gtsmodel.Poll
+
+		// Select poll counts from DB.
+		if err := tx.NewSelect().
+			Model(&poll).
+			Column("options", "votes", "voters").
+			Where("? = ?", bun.Ident("poll.id"), pollID).
+			Scan(ctx); err != nil {
+			return err
+		}
+
+		// Decrement poll votes for choices.
+		poll.DecrementVotes(vote.Choices)
+
+		// Finally, update the poll entry.
+		_, err := tx.NewUpdate().
+			Model(&poll).
+			Column("votes", "voters").
+			Where("? = ?", bun.Ident("poll.id"), pollID).
+			Exec(ctx)
+		return err
+	}); err != nil &&
+		!errors.Is(err, db.ErrNoEntries) {
+		return p.db.ProcessError(err)
+	}
+
+	// Invalidate the poll vote from cache, this
+	// also invalidates the related poll and IDs.
+	p.state.Caches.GTS.PollVote().Invalidate("ID", vote.ID)
+
+	return nil
+}
+
+func (p *pollDB) DeletePollVotesByAccountID(ctx context.Context, accountID string) error {
+	var pollIDs []string
+
+	// Select all polls this account
+	// has registered a poll vote in.
+	if err := p.db.NewSelect().
+		Table("poll_votes").
+		Column("poll_id").
+		Where("? = ?", bun.Ident("account_id"), accountID).
+		Scan(ctx, &pollIDs); err != nil {
+		return p.db.ProcessError(err)
+	}
+
+	for _, id := range pollIDs {
+		// Delete vote by this account in poll.
+		if err := p.DeletePollVoteBy(ctx, id, accountID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+/*
+GoToSocial
+Copyright (C) 2021-2023 GoToSocial Authors admin@gotosocial.org
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+package bundb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+type PollTestSuite struct {
+	BunDBStandardTestSuite
+}
+
+func (suite *PollTestSuite) newTestPoll(statusID string) *gtsmodel.Poll {
+	multiple := false
+	hideCounts := false
+
+	poll := &gtsmodel.Poll{
+		ID:         id.NewULID(),
+		Multiple:   &multiple,
+		HideCounts: &hideCounts,
+		Options:    []string{"yes", "no", "maybe"},
+		StatusID:   statusID,
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	poll.ResetVotes()
+
+	if err := suite.db.PutPoll(context.Background(), poll); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	return poll
+}
+
+func (suite *PollTestSuite) TestPutGetPoll() {
+	ctx := context.Background()
+	testStatus := suite.testStatuses["local_account_1_status_1"]
+	poll := suite.newTestPoll(testStatus.ID)
+
+	dbPoll, err := suite.db.GetPollByID(ctx, poll.ID)
+	suite.NoError(err)
+	suite.Equal(poll.Options, dbPoll.Options)
+	suite.Equal([]int{0, 0, 0}, dbPoll.Votes)
+	suite.Equal(0, *dbPoll.Voters)
+
+	dbPoll, err = suite.db.GetPollByStatusID(ctx, testStatus.ID)
+	suite.NoError(err)
+	suite.Equal(poll.ID, dbPoll.ID)
+}
+
+func (suite *PollTestSuite) TestPutPollVote() {
+	ctx := context.Background()
+	poll := suite.newTestPoll(suite.testStatuses["local_account_1_status_1"].ID)
+	voter := suite.testAccounts["local_account_2"]
+
+	if err := suite.db.PutPollVote(ctx, &gtsmodel.PollVote{
+		ID:        id.NewULID(),
+		Choices:   []int{2},
+		AccountID: voter.ID,
+		PollID:    poll.ID,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Poll counts should be updated.
+	dbPoll, err := suite.db.GetPollByID(ctx, poll.ID)
+	suite.NoError(err)
+	suite.Equal([]int{0, 0, 1}, dbPoll.Votes)
+	suite.Equal(1, *dbPoll.Voters)
+
+	vote, err := suite.db.GetPollVoteBy(ctx, poll.ID, voter.ID)
+	suite.NoError(err)
+	suite.Equal([]int{2}, vote.Choices)
+	suite.NotNil(vote.Account)
+	suite.NotNil(vote.Poll)
+
+	votes, err := suite.db.GetPollVotes(ctx, poll.ID)
+	suite.NoError(err)
+	suite.Len(votes, 1)
+
+	// Deleting the votes by account should reset counts.
+	if err := suite.db.DeletePollVotesByAccountID(ctx, voter.ID); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	dbPoll, err = suite.db.GetPollByID(ctx, poll.ID)
+	suite.NoError(err)
+	suite.Equal([]int{0, 0, 0}, dbPoll.Votes)
+	suite.Equal(0, *dbPoll.Voters)
+
+	_, err = suite.db.GetPollVoteBy(ctx, poll.ID, voter.ID)
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
+func (suite *PollTestSuite) TestGetExpiredPolls() {
+	ctx := context.Background()
+	poll := suite.newTestPoll(suite.testStatuses["local_account_1_status_1"].ID)
+
+	polls, err := suite.db.GetExpiredPolls(ctx)
+	suite.NoError(err)
+	suite.Empty(polls)
+
+	// Set the poll expiry in the past.
+	poll.ExpiresAt = time.Now().Add(-time.Minute)
+	if err := suite.db.UpdatePoll(ctx, poll, "expires_at"); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	polls, err = suite.db.GetExpiredPolls(ctx)
+	suite.NoError(err)
+	suite.Len(polls, 1)
+	suite.Equal(poll.ID, polls[0].ID)
+
+	// Once closed it's no longer returned.
+	poll.ClosedAt = time.Now()
+	if err := suite.db.UpdatePoll(ctx, poll, "closed_at"); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	polls, err = suite.db.GetExpiredPolls(ctx)
+	suite.NoError(err)
+	suite.Empty(polls)
+}
+
+func (suite *PollTestSuite) TestDeletePoll() {
+	ctx := context.Background()
+	poll := suite.newTestPoll(suite.testStatuses["local_account_1_status_1"].ID)
+
+	if err := suite.db.DeletePollByID(ctx, poll.ID); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	_, err := suite.db.GetPollByID(ctx, poll.ID)
+	if !errors.Is(err, db.ErrNoEntries) {
+		suite.FailNow("expected poll to be deleted")
+	}
+}
+
+func TestPollTestSuite(t *testing.T) {
+	suite.Run(t, new(PollTestSuite))
+}
@@ -20,6 +20,7 @@ package bundb
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtscontext"
@@ -86,6 +87,21 @@ func (r *relationshipDB) GetRelationship(ctx context.Context, requestingAccount
 		return nil, gtserror.Newf("error checking blockedBy: %w", err)
 	}
 
+	// check if the requesting account is muting the target account
+	mute, err := r.GetMute(
+		gtscontext.SetBarebones(ctx),
+		requestingAccount,
+		targetAccount,
+	)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return nil, gtserror.Newf("error checking muting: %w", err)
+	}
+
+	if mute != nil && !mute.Expired(time.Now()) {
+		rel.Muting = true
+		rel.MutingNotifications = *mute.Notifications
+	}
+
 	// retrieve a note by the requesting account on the target account, if there is one
 	note, err := r.GetNote(
 		gtscontext.SetBarebones(ctx),
@@ -365,3 +381,12 @@ func newSelectBlocks(db *WrappedDB, accountID string) *bun.SelectQuery {
 		Where("? = ?", bun.Ident("account_id"), accountID).
 		OrderExpr("? DESC", bun.Ident("updated_at"))
 }
+
+// newSelectUserMutes returns a new select query for all rows in the user_mutes table with account_id = accountID.
+func newSelectUserMutes(db *WrappedDB, accountID string) *bun.SelectQuery {
+	return db.NewSelect().
+		TableExpr("?", bun.Ident("user_mutes")).
+		ColumnExpr("?", bun.Ident("id")).
+		Where("? = ?", bun.Ident("account_id"), accountID).
+		OrderExpr("? DESC", bun.Ident("id"))
+}
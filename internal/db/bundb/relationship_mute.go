@@ -0,0 +1,277 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtscontext"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/paging"
+	"github.com/uptrace/bun"
+)
+
+func (r *relationshipDB) IsMuted(ctx context.Context, sourceAccountID string, targetAccountID string) (bool, error) {
+	mute, err := r.GetMute(
+		gtscontext.SetBarebones(ctx),
+		sourceAccountID,
+		targetAccountID,
+	)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return false, err
+	}
+	return (mute != nil && !mute.Expired(time.Now())), nil
+}
+
+func (r *relationshipDB) GetMuteByID(ctx context.Context, id string) (*gtsmodel.UserMute, error) {
+	return r.getMute(
+		ctx,
+		"ID",
+		func(mute *gtsmodel.UserMute) error {
+			return r.db.NewSelect().Model(mute).
+				Where("? = ?", bun.Ident("user_mute.id"), id).
+				Scan(ctx)
+		},
+		id,
+	)
+}
+
+func (r *relationshipDB) GetMute(ctx context.Context, sourceAccountID string, targetAccountID string) (*gtsmodel.UserMute, error) {
+	return r.getMute(
+		ctx,
+		"AccountID.TargetAccountID",
+		func(mute *gtsmodel.UserMute) error {
+			return r.db.NewSelect().Model(mute).
+				Where("? = ?", bun.Ident("user_mute.account_id"), sourceAccountID).
+				Where("? = ?", bun.Ident("user_mute.target_account_id"), targetAccountID).
+				Scan(ctx)
+		},
+		sourceAccountID,
+		targetAccountID,
+	)
+}
+
+func (r *relationshipDB) GetMutesByIDs(ctx context.Context, ids []string) ([]*gtsmodel.UserMute, error) {
+	// Preallocate slice of expected length.
+	mutes := make([]*gtsmodel.UserMute, 0, len(ids))
+
+	for _, id := range ids {
+		// Fetch mute model for this ID.
+		mute, err := r.GetMuteByID(ctx, id)
+		if err != nil {
+			log.Errorf(ctx, "error getting mute %q: %v", id, err)
+			continue
+		}
+
+		// Append to return slice.
+		mutes = append(mutes, mute)
+	}
+
+	return mutes, nil
+}
+
+func (r *relationshipDB) GetAccountMutes(ctx context.Context, accountID string, page *paging.Pager) ([]*gtsmodel.UserMute, error) {
+	// Load mute IDs from cache with database loader callback.
+	muteIDs, err := r.state.Caches.GTS.UserMuteIDs().LoadRange(accountID, func() ([]string, error) {
+		var muteIDs []string
+
+		// Mute IDs not in cache, perform DB query!
+		q := newSelectUserMutes(r.db, accountID)
+		if _, err := q.Exec(ctx, &muteIDs); err != nil {
+			return nil, r.db.ProcessError(err)
+		}
+
+		return muteIDs, nil
+	}, page.PageDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert these IDs to full mute objects.
+	return r.GetMutesByIDs(ctx, muteIDs)
+}
+
+func (r *relationshipDB) getMute(ctx context.Context, lookup string, dbQuery func(*gtsmodel.UserMute) error, keyParts ...any) (*gtsmodel.UserMute, error) {
+	// Fetch mute from cache with loader callback
+	mute, err := r.state.Caches.GTS.UserMute().Load(lookup, func() (*gtsmodel.UserMute, error) {
+		var mute gtsmodel.UserMute
+
+		// Not cached! Perform database query
+		if err := dbQuery(&mute); err != nil {
+			return nil, r.db.ProcessError(err)
+		}
+
+		return &mute, nil
+	}, keyParts...)
+	if err != nil {
+		// already processed
+		return nil, err
+	}
+
+	if gtscontext.Barebones(ctx) {
+		// Only a barebones model was requested.
+		return mute, nil
+	}
+
+	// Set the mute source account
+	mute.Account, err = r.state.DB.GetAccountByID(
+		gtscontext.SetBarebones(ctx),
+		mute.AccountID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting mute source account: %w", err)
+	}
+
+	// Set the mute target account
+	mute.TargetAccount, err = r.state.DB.GetAccountByID(
+		gtscontext.SetBarebones(ctx),
+		mute.TargetAccountID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting mute target account: %w", err)
+	}
+
+	return mute, nil
+}
+
+func (r *relationshipDB) PutMute(ctx context.Context, mute *gtsmodel.UserMute) error {
+	// Muted statuses may already be in this account's timelines.
+	defer invalidateAccountTimelines(ctx, r.state, mute.AccountID)
+
+	return r.state.Caches.GTS.UserMute().Store(mute, func() error {
+		_, err := r.db.NewInsert().Model(mute).Exec(ctx)
+		return r.db.ProcessError(err)
+	})
+}
+
+func (r *relationshipDB) UpdateMute(ctx context.Context, mute *gtsmodel.UserMute, columns ...string) error {
+	mute.UpdatedAt = time.Now()
+	if len(columns) > 0 {
+		// If we're updating by column, ensure "updated_at" is included.
+		columns = append(columns, "updated_at")
+	}
+
+	// Drop any cached mute lists / visibility / timelines
+	// that may be affected by the changed fields.
+	defer invalidateAccountTimelines(ctx, r.state, mute.AccountID)
+	defer r.state.Caches.GTS.UserMute().Invalidate("ID", mute.ID)
+
+	_, err := r.db.NewUpdate().
+		Model(mute).
+		Where("? = ?", bun.Ident("user_mute.id"), mute.ID).
+		Column(columns...).
+		Exec(ctx)
+	return r.db.ProcessError(err)
+}
+
+func (r *relationshipDB) DeleteMuteByID(ctx context.Context, id string) error {
+	// Load mute into cache before attempting a delete,
+	// as we need it cached in order to trigger the invalidate
+	// callback. This in turn invalidates others.
+	mute, err := r.GetMuteByID(gtscontext.SetBarebones(ctx), id)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			// not an issue.
+			err = nil
+		}
+		return err
+	}
+
+	// Drop this now-cached mute on return after delete,
+	// along with timelines that may be missing statuses.
+	defer invalidateAccountTimelines(ctx, r.state, mute.AccountID)
+	defer r.state.Caches.GTS.UserMute().Invalidate("ID", id)
+
+	// Finally delete mute from DB.
+	_, err = r.db.NewDelete().
+		Table("user_mutes").
+		Where("? = ?", bun.Ident("id"), id).
+		Exec(ctx)
+	return r.db.ProcessError(err)
+}
+
+func (r *relationshipDB) DeleteAccountMutes(ctx context.Context, accountID string) error {
+	var muteIDs []string
+
+	// Get full list of IDs.
+	if err := r.db.NewSelect().
+		Column("id").
+		Table("user_mutes").
+		WhereOr("? = ? OR ? = ?",
+			bun.Ident("account_id"),
+			accountID,
+			bun.Ident("target_account_id"),
+			accountID,
+		).
+		Scan(ctx, &muteIDs); err != nil {
+		return r.db.ProcessError(err)
+	}
+
+	defer func() {
+		// Invalidate all account's incoming / outoing mutes on return.
+		r.state.Caches.GTS.UserMute().Invalidate("AccountID", accountID)
+		r.state.Caches.GTS.UserMute().Invalidate("TargetAccountID", accountID)
+	}()
+
+	// Load all mutes into cache, this *really* isn't great
+	// but it is the only way we can ensure we invalidate all
+	// related caches correctly (e.g. visibility).
+	for _, id := range muteIDs {
+		_, err := r.GetMuteByID(ctx, id)
+		if err != nil && !errors.Is(err, db.ErrNoEntries) {
+			return err
+		}
+	}
+
+	// Finally delete all from DB.
+	_, err := r.db.NewDelete().
+		Table("user_mutes").
+		Where("? IN (?)", bun.Ident("id"), bun.In(muteIDs)).
+		Exec(ctx)
+	return r.db.ProcessError(err)
+}
+
+func (r *relationshipDB) DeleteExpiredMutes(ctx context.Context, now time.Time) (int, error) {
+	var muteIDs []string
+
+	// Get full list of expired mute IDs.
+	if err := r.db.NewSelect().
+		Column("id").
+		Table("user_mutes").
+		Where("? IS NOT NULL", bun.Ident("expires_at")).
+		Where("? <= ?", bun.Ident("expires_at"), now).
+		Scan(ctx, &muteIDs); err != nil {
+		return 0, r.db.ProcessError(err)
+	}
+
+	// Delete each mute individually,
+	// so that dependent caches (e.g.
+	// visibility) are invalidated.
+	for _, id := range muteIDs {
+		if err := r.DeleteMuteByID(ctx, id); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(muteIDs), nil
+}
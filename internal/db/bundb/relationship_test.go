@@ -28,6 +28,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
 	"github.com/superseriousbusiness/gotosocial/testrig"
 )
 
@@ -959,6 +960,118 @@ func (suite *RelationshipTestSuite) TestPutNote() {
 	suite.Equal("bar", note.Comment)
 }
 
+func (suite *RelationshipTestSuite) TestIsMuted() {
+	ctx := context.Background()
+
+	account1 := suite.testAccounts["local_account_1"].ID
+	account2 := suite.testAccounts["local_account_2"].ID
+
+	// no mutes exist between account 1 and account 2
+	muted, err := suite.db.IsMuted(ctx, account1, account2)
+	suite.NoError(err)
+	suite.False(muted)
+
+	// have account1 mute account2
+	if err := suite.db.PutMute(ctx, &gtsmodel.UserMute{
+		ID:              "01H7DM1T6K5TR1P3V0C4GKJQ2D",
+		AccountID:       account1,
+		TargetAccountID: account2,
+		Notifications:   util.Ptr(true),
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// account 1 now mutes account 2
+	muted, err = suite.db.IsMuted(ctx, account1, account2)
+	suite.NoError(err)
+	suite.True(muted)
+
+	// account 2 doesn't mute account 1
+	muted, err = suite.db.IsMuted(ctx, account2, account1)
+	suite.NoError(err)
+	suite.False(muted)
+
+	// notifications should be muted too
+	relationship, err := suite.db.GetRelationship(ctx, account1, account2)
+	suite.NoError(err)
+	suite.True(relationship.Muting)
+	suite.True(relationship.MutingNotifications)
+}
+
+func (suite *RelationshipTestSuite) TestDeleteExpiredMutes() {
+	ctx := context.Background()
+
+	account1 := suite.testAccounts["local_account_1"].ID
+	account2 := suite.testAccounts["local_account_2"].ID
+	account3 := suite.testAccounts["admin_account"].ID
+
+	// have account1 mute account2, which has already expired
+	if err := suite.db.PutMute(ctx, &gtsmodel.UserMute{
+		ID:              "01H7DM1T6K5TR1P3V0C4GKJQ2D",
+		ExpiresAt:       time.Now().Add(-time.Minute),
+		AccountID:       account1,
+		TargetAccountID: account2,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// have account1 mute account3 for another hour
+	if err := suite.db.PutMute(ctx, &gtsmodel.UserMute{
+		ID:              "01H7DM4HT1XKMG0T1ZJ1D5HCA0",
+		ExpiresAt:       time.Now().Add(time.Hour),
+		AccountID:       account1,
+		TargetAccountID: account3,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// expired mute should not be in effect
+	muted, err := suite.db.IsMuted(ctx, account1, account2)
+	suite.NoError(err)
+	suite.False(muted)
+
+	muted, err = suite.db.IsMuted(ctx, account1, account3)
+	suite.NoError(err)
+	suite.True(muted)
+
+	// only the expired mute should be deleted
+	deleted, err := suite.db.DeleteExpiredMutes(ctx, time.Now())
+	suite.NoError(err)
+	suite.Equal(1, deleted)
+
+	mute, err := suite.db.GetMute(ctx, account1, account2)
+	suite.ErrorIs(err, db.ErrNoEntries)
+	suite.Nil(mute)
+
+	mute, err = suite.db.GetMute(ctx, account1, account3)
+	suite.NoError(err)
+	suite.Equal("01H7DM4HT1XKMG0T1ZJ1D5HCA0", mute.ID)
+}
+
+func (suite *RelationshipTestSuite) TestDeleteAccountMutes() {
+	ctx := context.Background()
+
+	// put a mute in first
+	account1 := suite.testAccounts["local_account_1"].ID
+	account2 := suite.testAccounts["local_account_2"].ID
+	if err := suite.db.PutMute(ctx, &gtsmodel.UserMute{
+		ID:              "01H7DM1T6K5TR1P3V0C4GKJQ2D",
+		AccountID:       account1,
+		TargetAccountID: account2,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// delete the mute by targetAccountID
+	err := suite.db.DeleteAccountMutes(ctx, account2)
+	suite.NoError(err)
+
+	// mute should be gone
+	mute, err := suite.db.GetMute(ctx, account1, account2)
+	suite.ErrorIs(err, db.ErrNoEntries)
+	suite.Nil(mute)
+}
+
 func TestRelationshipTestSuite(t *testing.T) {
 	suite.Run(t, new(RelationshipTestSuite))
 }
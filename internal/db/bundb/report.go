@@ -155,6 +155,14 @@ func (r *reportDB) getReport(ctx context.Context, lookup string, dbQuery func(*g
 		}
 	}
 
+	if len(report.RuleIDs) > 0 {
+		// Fetch reported rules
+		report.Rules, err = r.state.DB.GetRulesByIDs(ctx, report.RuleIDs)
+		if err != nil {
+			return nil, fmt.Errorf("error getting report rules: %w", err)
+		}
+	}
+
 	if report.ActionTakenByAccountID != "" {
 		// Set the report action taken by account
 		report.ActionTakenByAccount, err = r.state.DB.GetAccountByID(ctx, report.ActionTakenByAccountID)
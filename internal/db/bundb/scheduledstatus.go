@@ -0,0 +1,203 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/uptrace/bun"
+)
+
+type scheduledStatusDB struct {
+	db    *WrappedDB
+	state *state.State
+}
+
+func (s *scheduledStatusDB) GetScheduledStatusByID(ctx context.Context, id string) (*gtsmodel.ScheduledStatus, error) {
+	var scheduledStatus gtsmodel.ScheduledStatus
+
+	if err := s.db.
+		NewSelect().
+		Model(&scheduledStatus).
+		Where("? = ?", bun.Ident("scheduled_status.id"), id).
+		Scan(ctx); err != nil {
+		return nil, s.db.ProcessError(err)
+	}
+
+	return &scheduledStatus, nil
+}
+
+func (s *scheduledStatusDB) GetScheduledStatusesForAccountID(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int) ([]*gtsmodel.ScheduledStatus, error) {
+	scheduledStatuses := []*gtsmodel.ScheduledStatus{}
+
+	q := s.db.
+		NewSelect().
+		Model(&scheduledStatuses).
+		Where("? = ?", bun.Ident("scheduled_status.account_id"), accountID)
+
+	if maxID != "" {
+		q = q.Where("? < ?", bun.Ident("scheduled_status.id"), maxID)
+	}
+
+	if sinceID != "" {
+		q = q.Where("? > ?", bun.Ident("scheduled_status.id"), sinceID)
+	}
+
+	if minID != "" {
+		// Page up from minID,
+		// we'll reverse later.
+		q = q.
+			Where("? > ?", bun.Ident("scheduled_status.id"), minID).
+			OrderExpr("? ASC", bun.Ident("scheduled_status.id"))
+	} else {
+		q = q.OrderExpr("? DESC", bun.Ident("scheduled_status.id"))
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, s.db.ProcessError(err)
+	}
+
+	if minID != "" {
+		// Return newest first,
+		// like other pages.
+		for i, j := 0, len(scheduledStatuses)-1; i < j; i, j = i+1, j-1 {
+			scheduledStatuses[i], scheduledStatuses[j] = scheduledStatuses[j], scheduledStatuses[i]
+		}
+	}
+
+	return scheduledStatuses, nil
+}
+
+func (s *scheduledStatusDB) GetDueScheduledStatuses(ctx context.Context, now time.Time) ([]*gtsmodel.ScheduledStatus, error) {
+	scheduledStatuses := []*gtsmodel.ScheduledStatus{}
+
+	if err := s.db.
+		NewSelect().
+		Model(&scheduledStatuses).
+		Where("? <= ?", bun.Ident("scheduled_status.scheduled_at"), now).
+		OrderExpr("? ASC", bun.Ident("scheduled_status.scheduled_at")).
+		Scan(ctx); err != nil {
+		return nil, s.db.ProcessError(err)
+	}
+
+	return scheduledStatuses, nil
+}
+
+func (s *scheduledStatusDB) CountScheduledStatusesForAccountID(ctx context.Context, accountID string) (int, error) {
+	return s.db.
+		NewSelect().
+		TableExpr("? AS ?", bun.Ident("scheduled_statuses"), bun.Ident("scheduled_status")).
+		Where("? = ?", bun.Ident("scheduled_status.account_id"), accountID).
+		Count(ctx)
+}
+
+func (s *scheduledStatusDB) PopulateScheduledStatus(ctx context.Context, scheduledStatus *gtsmodel.ScheduledStatus) error {
+	var (
+		err  error
+		errs = gtserror.NewMultiError(3)
+	)
+
+	if scheduledStatus.Account == nil {
+		// Scheduled status account is not set, fetch from database.
+		scheduledStatus.Account, err = s.state.DB.GetAccountByID(ctx, scheduledStatus.AccountID)
+		if err != nil {
+			errs.Appendf("error populating scheduled status account: %w", err)
+		}
+	}
+
+	if scheduledStatus.Application == nil {
+		// Scheduled status application is not set, fetch from database.
+		application := &gtsmodel.Application{}
+		if err := s.state.DB.GetByID(ctx, scheduledStatus.ApplicationID, application); err != nil {
+			errs.Appendf("error populating scheduled status application: %w", err)
+		} else {
+			scheduledStatus.Application = application
+		}
+	}
+
+	if len(scheduledStatus.MediaIDs) != len(scheduledStatus.MediaAttachments) {
+		// Scheduled status media attachments are not set, fetch from database.
+		scheduledStatus.MediaAttachments, err = s.state.DB.GetAttachmentsByIDs(ctx, scheduledStatus.MediaIDs)
+		if err != nil && !errors.Is(err, db.ErrNoEntries) {
+			errs.Appendf("error populating scheduled status media attachments: %w", err)
+		}
+	}
+
+	return errs.Combine()
+}
+
+func (s *scheduledStatusDB) PutScheduledStatus(ctx context.Context, scheduledStatus *gtsmodel.ScheduledStatus) error {
+	if _, err := s.db.
+		NewInsert().
+		Model(scheduledStatus).
+		Exec(ctx); err != nil {
+		return s.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+func (s *scheduledStatusDB) UpdateScheduledStatus(ctx context.Context, scheduledStatus *gtsmodel.ScheduledStatus, columns ...string) error {
+	scheduledStatus.UpdatedAt = time.Now()
+	if len(columns) > 0 {
+		// If we're updating by column,
+		// ensure "updated_at" is included.
+		columns = append(columns, "updated_at")
+	}
+
+	if _, err := s.db.
+		NewUpdate().
+		Model(scheduledStatus).
+		Column(columns...).
+		Where("? = ?", bun.Ident("scheduled_status.id"), scheduledStatus.ID).
+		Exec(ctx); err != nil {
+		return s.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+func (s *scheduledStatusDB) DeleteScheduledStatusByID(ctx context.Context, id string) error {
+	return s.deleteScheduledStatuses(ctx, "id", id)
+}
+
+func (s *scheduledStatusDB) DeleteScheduledStatusesByAccountID(ctx context.Context, accountID string) error {
+	return s.deleteScheduledStatuses(ctx, "account_id", accountID)
+}
+
+func (s *scheduledStatusDB) deleteScheduledStatuses(ctx context.Context, column string, value string) error {
+	if _, err := s.db.
+		NewDelete().
+		TableExpr("? AS ?", bun.Ident("scheduled_statuses"), bun.Ident("scheduled_status")).
+		Where("? = ?", bun.Ident("scheduled_status."+column), value).
+		Exec(ctx); err != nil {
+		return s.db.ProcessError(err)
+	}
+
+	return nil
+}
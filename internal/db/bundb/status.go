@@ -30,6 +30,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/log"
 	"github.com/superseriousbusiness/gotosocial/internal/state"
 	"github.com/uptrace/bun"
+	"golang.org/x/exp/slices"
 )
 
 type statusDB struct {
@@ -147,7 +148,7 @@ func (s *statusDB) getStatus(ctx context.Context, lookup string, dbQuery func(*g
 func (s *statusDB) PopulateStatus(ctx context.Context, status *gtsmodel.Status) error {
 	var (
 		err  error
-		errs = gtserror.NewMultiError(9)
+		errs = gtserror.NewMultiError(10)
 	)
 
 	if status.Account == nil {
@@ -253,6 +254,31 @@ func (s *statusDB) PopulateStatus(ctx context.Context, status *gtsmodel.Status)
 		}
 	}
 
+	if status.PollID != "" && status.Poll == nil {
+		// Status poll is not set, fetch from database.
+		status.Poll, err = s.state.DB.GetPollByID(
+			gtscontext.SetBarebones(ctx),
+			status.PollID,
+		)
+		if err != nil {
+			errs.Appendf("error populating status poll: %w", err)
+		} else {
+			// Set poll's status to this one.
+			status.Poll.Status = status
+		}
+	}
+
+	if !status.EditsPopulated() {
+		// Status edits are out-of-date with IDs, repopulate.
+		status.Edits, err = s.state.DB.GetStatusEditsByIDs(
+			gtscontext.SetBarebones(ctx),
+			status.EditIDs,
+		)
+		if err != nil {
+			errs.Appendf("error populating status edits: %w", err)
+		}
+	}
+
 	if !status.EmojisPopulated() {
 		// Status emojis are out-of-date with IDs, repopulate.
 		status.Emojis, err = s.state.DB.GetEmojisByIDs(
@@ -376,6 +402,34 @@ func (s *statusDB) UpdateStatus(ctx context.Context, status *gtsmodel.Status, co
 				}
 			}
 
+			if len(columns) == 0 || slices.Contains(columns, "emojis") {
+				// remove links between this status and any emojis it no longer uses
+				q := tx.
+					NewDelete().
+					TableExpr("? AS ?", bun.Ident("status_to_emojis"), bun.Ident("status_to_emoji")).
+					Where("? = ?", bun.Ident("status_to_emoji.status_id"), status.ID)
+				if len(status.EmojiIDs) > 0 {
+					q = q.Where("? NOT IN (?)", bun.Ident("status_to_emoji.emoji_id"), bun.In(status.EmojiIDs))
+				}
+				if _, err := q.Exec(ctx); err != nil {
+					return err
+				}
+			}
+
+			if len(columns) == 0 || slices.Contains(columns, "tags") {
+				// remove links between this status and any tags it no longer uses
+				q := tx.
+					NewDelete().
+					TableExpr("? AS ?", bun.Ident("status_to_tags"), bun.Ident("status_to_tag")).
+					Where("? = ?", bun.Ident("status_to_tag.status_id"), status.ID)
+				if len(status.TagIDs) > 0 {
+					q = q.Where("? NOT IN (?)", bun.Ident("status_to_tag.tag_id"), bun.In(status.TagIDs))
+				}
+				if _, err := q.Exec(ctx); err != nil {
+					return err
+				}
+			}
+
 			// change the status ID of the media attachments to the new status
 			for _, a := range status.Attachments {
 				a.StatusID = status.ID
@@ -636,11 +690,40 @@ func (s *statusDB) getStatusBoostIDs(ctx context.Context, statusID string) ([]st
 	})
 }
 
+func (s *statusDB) GetStatusThreadRootID(ctx context.Context, status *gtsmodel.Status) (string, error) {
+	rootID := status.ID
+
+	for id := status.InReplyToID; id != ""; {
+		// We only need the parent IDs.
+		parent, err := s.GetStatusByID(gtscontext.SetBarebones(ctx), id)
+		if err != nil {
+			if errors.Is(err, db.ErrNoEntries) {
+				// Parent was deleted (or never dereferenced),
+				// treat the last known status as the root.
+				break
+			}
+			return "", err
+		}
+
+		// Set the next parent ID
+		rootID = parent.ID
+		id = parent.InReplyToID
+	}
+
+	return rootID, nil
+}
+
 func (s *statusDB) IsStatusMutedBy(ctx context.Context, status *gtsmodel.Status, accountID string) (bool, error) {
+	// Mutes are keyed on the thread root.
+	rootID, err := s.GetStatusThreadRootID(ctx, status)
+	if err != nil {
+		return false, err
+	}
+
 	q := s.db.
 		NewSelect().
 		TableExpr("? AS ?", bun.Ident("status_mutes"), bun.Ident("status_mute")).
-		Where("? = ?", bun.Ident("status_mute.status_id"), status.ID).
+		Where("? = ?", bun.Ident("status_mute.status_id"), rootID).
 		Where("? = ?", bun.Ident("status_mute.account_id"), accountID)
 
 	return s.db.Exists(ctx, q)
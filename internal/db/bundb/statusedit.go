@@ -0,0 +1,130 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtscontext"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/uptrace/bun"
+)
+
+type statusEditDB struct {
+	db    *WrappedDB
+	state *state.State
+}
+
+func (s *statusEditDB) GetStatusEditByID(ctx context.Context, id string) (*gtsmodel.StatusEdit, error) {
+	// Fetch edit from database cache with loader callback.
+	edit, err := s.state.Caches.GTS.StatusEdit().Load("ID", func() (*gtsmodel.StatusEdit, error) {
+		var edit gtsmodel.StatusEdit
+
+		// Not cached! Perform database query.
+		if err := s.db.NewSelect().
+			Model(&edit).
+			Where("? = ?", bun.Ident("status_edit.id"), id).
+			Scan(ctx); err != nil {
+			return nil, s.db.ProcessError(err)
+		}
+
+		return &edit, nil
+	}, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if gtscontext.Barebones(ctx) {
+		// no need to fully populate.
+		return edit, nil
+	}
+
+	// Further populate the edit fields where applicable.
+	if err := s.PopulateStatusEdit(ctx, edit); err != nil {
+		return nil, err
+	}
+
+	return edit, nil
+}
+
+func (s *statusEditDB) GetStatusEditsByIDs(ctx context.Context, ids []string) ([]*gtsmodel.StatusEdit, error) {
+	edits := make([]*gtsmodel.StatusEdit, 0, len(ids))
+
+	for _, id := range ids {
+		// Attempt to fetch status edit from DB.
+		edit, err := s.GetStatusEditByID(ctx, id)
+		if err != nil {
+			log.Errorf(ctx, "error getting status edit %q: %v", id, err)
+			continue
+		}
+
+		// Append edit to return slice.
+		edits = append(edits, edit)
+	}
+
+	return edits, nil
+}
+
+func (s *statusEditDB) PopulateStatusEdit(ctx context.Context, edit *gtsmodel.StatusEdit) error {
+	var err error
+
+	if !edit.AttachmentsPopulated() {
+		// Fetch all attachments for status edit's IDs.
+		edit.Attachments, err = s.state.DB.GetAttachmentsByIDs(
+			ctx, // these are already barebones
+			edit.AttachmentIDs,
+		)
+		if err != nil {
+			return gtserror.Newf("error populating status edit attachments: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *statusEditDB) PutStatusEdit(ctx context.Context, edit *gtsmodel.StatusEdit) error {
+	return s.state.Caches.GTS.StatusEdit().Store(edit, func() error {
+		_, err := s.db.NewInsert().Model(edit).Exec(ctx)
+		return s.db.ProcessError(err)
+	})
+}
+
+func (s *statusEditDB) DeleteStatusEdits(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		// Nothing to do.
+		return nil
+	}
+
+	// Delete all edits with given IDs from the database.
+	if _, err := s.db.NewDelete().
+		Table("status_edits").
+		Where("? IN (?)", bun.Ident("id"), bun.In(ids)).
+		Exec(ctx); err != nil {
+		return s.db.ProcessError(err)
+	}
+
+	// Invalidate all the deleted edits from the cache.
+	for _, id := range ids {
+		s.state.Caches.GTS.StatusEdit().Invalidate("ID", id)
+	}
+
+	return nil
+}
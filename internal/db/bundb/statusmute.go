@@ -0,0 +1,139 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/uptrace/bun"
+)
+
+type statusMuteDB struct {
+	db    *WrappedDB
+	state *state.State
+}
+
+func (s *statusMuteDB) GetStatusMuteID(ctx context.Context, accountID string, statusID string) (string, error) {
+	var id string
+
+	q := s.db.
+		NewSelect().
+		TableExpr("? AS ?", bun.Ident("status_mutes"), bun.Ident("status_mute")).
+		Column("status_mute.id").
+		Where("? = ?", bun.Ident("status_mute.account_id"), accountID).
+		Where("? = ?", bun.Ident("status_mute.status_id"), statusID).
+		Limit(1)
+
+	if err := q.Scan(ctx, &id); err != nil {
+		return "", s.db.ProcessError(err)
+	}
+
+	return id, nil
+}
+
+func (s *statusMuteDB) PutStatusMute(ctx context.Context, statusMute *gtsmodel.StatusMute) error {
+	if _, err := s.db.
+		NewInsert().
+		Model(statusMute).
+		Exec(ctx); err != nil {
+		return s.db.ProcessError(err)
+	}
+
+	s.invalidate(ctx, statusMute.AccountID)
+	return nil
+}
+
+func (s *statusMuteDB) DeleteStatusMute(ctx context.Context, id string) error {
+	q := s.db.
+		NewDelete().
+		Table("status_mutes").
+		Where("? = ?", bun.Ident("id"), id)
+
+	return s.deleteStatusMutes(ctx, q)
+}
+
+func (s *statusMuteDB) DeleteStatusMutes(ctx context.Context, targetAccountID string, originAccountID string) error {
+	if targetAccountID == "" && originAccountID == "" {
+		return errors.New("DeleteStatusMutes: one of targetAccountID or originAccountID must be set")
+	}
+
+	q := s.db.
+		NewDelete().
+		Table("status_mutes")
+
+	if targetAccountID != "" {
+		q = q.Where("? = ?", bun.Ident("target_account_id"), targetAccountID)
+	}
+
+	if originAccountID != "" {
+		q = q.Where("? = ?", bun.Ident("account_id"), originAccountID)
+	}
+
+	return s.deleteStatusMutes(ctx, q)
+}
+
+func (s *statusMuteDB) DeleteStatusMutesForStatus(ctx context.Context, statusID string) error {
+	q := s.db.
+		NewDelete().
+		Table("status_mutes").
+		Where("? = ?", bun.Ident("status_id"), statusID)
+
+	return s.deleteStatusMutes(ctx, q)
+}
+
+// deleteStatusMutes executes the given DELETE query, invalidating
+// cached visibilities + timelines of the accounts that owned the mutes.
+func (s *statusMuteDB) deleteStatusMutes(ctx context.Context, q *bun.DeleteQuery) error {
+	var accountIDs []string
+
+	// Execute query, returning the
+	// account IDs of deleted mutes.
+	if _, err := q.
+		Returning("account_id").
+		Exec(ctx, &accountIDs); err != nil {
+		if err == sql.ErrNoRows {
+			// Not an issue, only due
+			// to us doing a RETURNING.
+			err = nil
+		}
+		return s.db.ProcessError(err)
+	}
+
+	// Collate (deduplicating) account IDs.
+	accountIDs = collate(func(i int) string {
+		return accountIDs[i]
+	}, len(accountIDs))
+
+	for _, accountID := range accountIDs {
+		s.invalidate(ctx, accountID)
+	}
+
+	return nil
+}
+
+// invalidate drops cached visibility for statuses requested
+// by the given account, and its home + list timelines, as
+// these may include statuses in a newly (un)muted thread.
+func (s *statusMuteDB) invalidate(ctx context.Context, accountID string) {
+	s.state.Caches.Visibility.Invalidate("RequesterID", accountID)
+	invalidateAccountTimelines(ctx, s.state, accountID)
+}
@@ -0,0 +1,85 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type StatusMuteTestSuite struct {
+	BunDBStandardTestSuite
+}
+
+func (suite *StatusMuteTestSuite) TestGetStatusThreadRootID() {
+	ctx := context.Background()
+
+	// Top-level status is its own root.
+	rootStatus := suite.testStatuses["local_account_1_status_1"]
+	rootID, err := suite.db.GetStatusThreadRootID(ctx, rootStatus)
+	suite.NoError(err)
+	suite.Equal(rootStatus.ID, rootID)
+
+	// Reply has the top-level status as root.
+	rootID, err = suite.db.GetStatusThreadRootID(ctx, suite.testStatuses["admin_account_status_3"])
+	suite.NoError(err)
+	suite.Equal(rootStatus.ID, rootID)
+}
+
+func (suite *StatusMuteTestSuite) TestPutDeleteStatusMute() {
+	ctx := context.Background()
+
+	testAccount := suite.testAccounts["local_account_2"]
+	rootStatus := suite.testStatuses["local_account_1_status_1"]
+	replyStatus := suite.testStatuses["admin_account_status_3"]
+
+	if err := suite.db.PutStatusMute(ctx, &gtsmodel.StatusMute{
+		ID:              "01H7DPZ9Y8P2T4QW5N1XJ3K6RB",
+		AccountID:       testAccount.ID,
+		TargetAccountID: rootStatus.AccountID,
+		StatusID:        rootStatus.ID,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Whole thread should now be muted.
+	muted, err := suite.db.IsStatusMutedBy(ctx, replyStatus, testAccount.ID)
+	suite.NoError(err)
+	suite.True(muted)
+
+	// Delete the mutes for the root status.
+	if err := suite.db.DeleteStatusMutesForStatus(ctx, rootStatus.ID); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	muted, err = suite.db.IsStatusMutedBy(ctx, replyStatus, testAccount.ID)
+	suite.NoError(err)
+	suite.False(muted)
+
+	id, err := suite.db.GetStatusMuteID(ctx, testAccount.ID, rootStatus.ID)
+	suite.Empty(id)
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
+func TestStatusMuteTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusMuteTestSuite))
+}
@@ -124,6 +124,24 @@ func (u *userDB) GetUserByConfirmationToken(ctx context.Context, confirmationTok
 	}, confirmationToken)
 }
 
+func (u *userDB) GetUserByResetPasswordToken(ctx context.Context, resetPasswordToken string) (*gtsmodel.User, error) {
+	return u.state.Caches.GTS.User().Load("ResetPasswordToken", func() (*gtsmodel.User, error) {
+		var user gtsmodel.User
+
+		q := u.db.
+			NewSelect().
+			Model(&user).
+			Relation("Account").
+			Where("? = ?", bun.Ident("user.reset_password_token"), resetPasswordToken)
+
+		if err := q.Scan(ctx); err != nil {
+			return nil, u.db.ProcessError(err)
+		}
+
+		return &user, nil
+	}, resetPasswordToken)
+}
+
 func (u *userDB) GetAllUsers(ctx context.Context) ([]*gtsmodel.User, error) {
 	var users []*gtsmodel.User
 	q := u.db.
@@ -157,6 +175,11 @@ func (u *userDB) UpdateUser(ctx context.Context, user *gtsmodel.User, columns ..
 		columns = append(columns, "updated_at")
 	}
 
+	// Drop any cached copy first, as otherwise lookups
+	// by a key that has since changed (e.g. a cleared
+	// reset password token) would still return it.
+	u.state.Caches.GTS.User().Invalidate("ID", user.ID)
+
 	return u.state.Caches.GTS.User().Store(user, func() error {
 		_, err := u.db.
 			NewUpdate().
@@ -168,6 +191,179 @@ func (u *userDB) UpdateUser(ctx context.Context, user *gtsmodel.User, columns ..
 	})
 }
 
+func (u *userDB) UpdateUserTwoFactorCounter(ctx context.Context, user *gtsmodel.User, counter int64) error {
+	// Drop any cached copy, as
+	// we're updating it directly.
+	defer u.state.Caches.GTS.User().Invalidate("ID", user.ID)
+
+	updatedAt := time.Now()
+
+	// Only ever move the counter forward, in
+	// the same statement as the check, so that
+	// concurrent requests can't both use a code.
+	result, err := u.db.
+		NewUpdate().
+		TableExpr("? AS ?", bun.Ident("users"), bun.Ident("user")).
+		Set("? = ?", bun.Ident("two_factor_last_counter"), counter).
+		Set("? = ?", bun.Ident("updated_at"), updatedAt).
+		Where("? = ?", bun.Ident("user.id"), user.ID).
+		Where("? < ?", bun.Ident("user.two_factor_last_counter"), counter).
+		Exec(ctx)
+	if err != nil {
+		return u.db.ProcessError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return u.db.ProcessError(err)
+	}
+
+	if rowsAffected == 0 {
+		// Counter was not greater.
+		return db.ErrNoEntries
+	}
+
+	user.TwoFactorLastCounter = counter
+	user.UpdatedAt = updatedAt
+	return nil
+}
+
+func (u *userDB) UpdateUserTwoFactorRecoveryCodes(ctx context.Context, user *gtsmodel.User, codes []string) error {
+	// Drop any cached copy, as
+	// we're updating it directly.
+	defer u.state.Caches.GTS.User().Invalidate("ID", user.ID)
+
+	updatedAt := time.Now()
+
+	// Store no codes as null,
+	// same as the nullzero column.
+	var value interface{}
+	if len(codes) > 0 {
+		value = codes
+	}
+
+	// Only replace the codes if they're still the
+	// ones we had, in the same statement as the
+	// check, so that concurrent requests can't
+	// both use the same recovery code.
+	q := u.db.
+		NewUpdate().
+		TableExpr("? AS ?", bun.Ident("users"), bun.Ident("user")).
+		Set("? = ?", bun.Ident("two_factor_recovery_codes"), value).
+		Set("? = ?", bun.Ident("updated_at"), updatedAt).
+		Where("? = ?", bun.Ident("user.id"), user.ID)
+
+	if len(user.TwoFactorRecoveryCodes) == 0 {
+		q = q.Where("? IS NULL", bun.Ident("user.two_factor_recovery_codes"))
+	} else {
+		q = q.Where("? = ?", bun.Ident("user.two_factor_recovery_codes"), user.TwoFactorRecoveryCodes)
+	}
+
+	result, err := q.Exec(ctx)
+	if err != nil {
+		return u.db.ProcessError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return u.db.ProcessError(err)
+	}
+
+	if rowsAffected == 0 {
+		// Codes were changed meanwhile.
+		return db.ErrNoEntries
+	}
+
+	user.TwoFactorRecoveryCodes = codes
+	user.UpdatedAt = updatedAt
+	return nil
+}
+
+func (u *userDB) UpdateUserPasswordByResetToken(ctx context.Context, user *gtsmodel.User, token string) error {
+	// Drop any cached copy, as
+	// we're updating it directly.
+	defer u.state.Caches.GTS.User().Invalidate("ID", user.ID)
+
+	updatedAt := time.Now()
+
+	// Only set the password if the token is still
+	// stored, clearing it in the same statement as
+	// the check, so that a token can't be used by
+	// concurrent requests to set two passwords.
+	result, err := u.db.
+		NewUpdate().
+		TableExpr("? AS ?", bun.Ident("users"), bun.Ident("user")).
+		Set("? = ?", bun.Ident("encrypted_password"), user.EncryptedPassword).
+		Set("? = NULL", bun.Ident("reset_password_token")).
+		Set("? = NULL", bun.Ident("reset_password_sent_at")).
+		Set("? = ?", bun.Ident("updated_at"), updatedAt).
+		Where("? = ?", bun.Ident("user.id"), user.ID).
+		Where("? = ?", bun.Ident("user.reset_password_token"), token).
+		Exec(ctx)
+	if err != nil {
+		return u.db.ProcessError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return u.db.ProcessError(err)
+	}
+
+	if rowsAffected == 0 {
+		// Token was used meanwhile.
+		return db.ErrNoEntries
+	}
+
+	user.ResetPasswordToken = ""
+	user.ResetPasswordSentAt = time.Time{}
+	user.UpdatedAt = updatedAt
+	return nil
+}
+
+func (u *userDB) ClaimTwoFactorAttempt(ctx context.Context, user *gtsmodel.User, max int, lockout time.Duration) error {
+	// Drop any cached copy, as
+	// we're updating it directly.
+	defer u.state.Caches.GTS.User().Invalidate("ID", user.ID)
+
+	var (
+		now    = time.Now()
+		cutoff = now.Add(-lockout)
+
+		attemptsCol    = bun.Ident("two_factor_attempts")
+		attemptedAtCol = bun.Ident("two_factor_attempted_at")
+	)
+
+	// Check and count the attempt in one
+	// statement, so that concurrent requests
+	// can't get in more than max attempts.
+	// If the last attempt was long enough ago,
+	// the count starts over.
+	var attempts int
+	if _, err := u.db.
+		NewUpdate().
+		Table("users").
+		Set("? = CASE WHEN ? IS NULL OR ? <= ? THEN 1 ELSE ? + 1 END",
+			attemptsCol, attemptedAtCol, attemptedAtCol, cutoff, attemptsCol).
+		Set("? = ?", attemptedAtCol, now).
+		Where("? = ?", bun.Ident("id"), user.ID).
+		WhereGroup(" AND ", func(q *bun.UpdateQuery) *bun.UpdateQuery {
+			return q.
+				Where("? < ?", attemptsCol, max).
+				WhereOr("? IS NULL", attemptedAtCol).
+				WhereOr("? <= ?", attemptedAtCol, cutoff)
+		}).
+		Returning("?", attemptsCol).
+		Exec(ctx, &attempts); err != nil {
+		// No rows means
+		// we're locked out.
+		return u.db.ProcessError(err)
+	}
+
+	user.TwoFactorAttempts = attempts
+	user.TwoFactorAttemptedAt = now
+	return nil
+}
+
 func (u *userDB) DeleteUserByID(ctx context.Context, userID string) error {
 	defer u.state.Caches.GTS.User().Invalidate("ID", userID)
 
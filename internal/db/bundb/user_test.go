@@ -20,8 +20,10 @@ package bundb_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
@@ -72,6 +74,42 @@ func (suite *UserTestSuite) TestUpdateUserSelectedColumns() {
 	suite.Equal(testUser.AccountID, dbUser.AccountID)
 }
 
+func (suite *UserTestSuite) TestUpdateUserPasswordByResetToken() {
+	ctx := context.Background()
+	testUser := suite.testUsers["local_account_1"]
+
+	const token = "1d1aa44b-afa4-49c8-ac4b-eceb61715cc6"
+	testUser.ResetPasswordToken = token
+	testUser.ResetPasswordSentAt = time.Now()
+	if err := suite.db.UpdateUser(ctx, testUser, "reset_password_token", "reset_password_sent_at"); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Two requests racing to
+	// use the same reset token.
+	user1 := new(gtsmodel.User)
+	*user1 = *testUser
+	user1.EncryptedPassword = "first"
+
+	user2 := new(gtsmodel.User)
+	*user2 = *testUser
+	user2.EncryptedPassword = "second"
+
+	err := suite.db.UpdateUserPasswordByResetToken(ctx, user1, token)
+	suite.NoError(err)
+	suite.Empty(user1.ResetPasswordToken)
+
+	// Only the first wins.
+	err = suite.db.UpdateUserPasswordByResetToken(ctx, user2, token)
+	suite.ErrorIs(err, db.ErrNoEntries)
+
+	dbUser, err := suite.db.GetUserByID(ctx, testUser.ID)
+	suite.NoError(err)
+	suite.Equal("first", dbUser.EncryptedPassword)
+	suite.Empty(dbUser.ResetPasswordToken)
+	suite.True(dbUser.ResetPasswordSentAt.IsZero())
+}
+
 func TestUserTestSuite(t *testing.T) {
 	suite.Run(t, new(UserTestSuite))
 }
@@ -18,9 +18,13 @@
 package bundb
 
 import (
+	"context"
 	"strings"
 
 	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtscontext"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
 	"github.com/uptrace/bun"
 )
 
@@ -132,3 +136,25 @@ func parseWhere(w db.Where) (query string, args []interface{}) {
 	args = []interface{}{bun.Ident(w.Key), w.Value}
 	return
 }
+
+// invalidateAccountTimelines removes the home and list timelines of
+// the given account, for when the prepared statuses in them may no
+// longer reflect what the account should see (e.g. after a change to
+// their filters or mutes). They will be recreated on next access.
+func invalidateAccountTimelines(ctx context.Context, state *state.State, accountID string) {
+	if err := state.Timelines.Home.RemoveTimeline(ctx, accountID); err != nil {
+		log.Errorf(ctx, "error invalidating home timeline: %q", err)
+	}
+
+	lists, err := state.DB.GetListsForAccountID(gtscontext.SetBarebones(ctx), accountID)
+	if err != nil {
+		log.Errorf(ctx, "error fetching lists for account %s: %q", accountID, err)
+		return
+	}
+
+	for _, list := range lists {
+		if err := state.Timelines.List.RemoveTimeline(ctx, list.ID); err != nil {
+			log.Errorf(ctx, "error invalidating list timeline: %q", err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package bundb
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/uptrace/bun"
+)
+
+type workerMessageDB struct {
+	db    *WrappedDB
+	state *state.State
+}
+
+func (w *workerMessageDB) GetWorkerMessages(ctx context.Context, worker gtsmodel.WorkerType) ([]*gtsmodel.WorkerMessage, error) {
+	msgs := []*gtsmodel.WorkerMessage{}
+
+	if err := w.db.
+		NewSelect().
+		Model(&msgs).
+		Where("? = ?", bun.Ident("worker_message.worker"), worker).
+		OrderExpr("? ASC", bun.Ident("worker_message.id")).
+		Scan(ctx); err != nil {
+		return nil, w.db.ProcessError(err)
+	}
+
+	return msgs, nil
+}
+
+func (w *workerMessageDB) PutWorkerMessage(ctx context.Context, msg *gtsmodel.WorkerMessage) error {
+	if _, err := w.db.
+		NewInsert().
+		Model(msg).
+		Exec(ctx); err != nil {
+		return w.db.ProcessError(err)
+	}
+
+	return nil
+}
+
+func (w *workerMessageDB) DeleteWorkerMessageByID(ctx context.Context, id string) error {
+	if _, err := w.db.
+		NewDelete().
+		TableExpr("? AS ?", bun.Ident("worker_messages"), bun.Ident("worker_message")).
+		Where("? = ?", bun.Ident("worker_message.id"), id).
+		Exec(ctx); err != nil {
+		return w.db.ProcessError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/paging"
+)
+
+type Conversation interface {
+	// GetConversationByID gets a single conversation by ID.
+	GetConversationByID(ctx context.Context, id string) (*gtsmodel.Conversation, error)
+
+	// GetConversationByThreadAndAccountIDs retrieves a conversation owned by the given account
+	// in the given thread, between the given set of other accounts (in any order).
+	GetConversationByThreadAndAccountIDs(ctx context.Context, accountID string, threadID string, otherAccountIDs []string) (*gtsmodel.Conversation, error)
+
+	// GetConversationsByOwnerAccountID gets a page of conversations owned by the given account,
+	// ordered by, and paged using, the ID of the most recent status in each conversation.
+	GetConversationsByOwnerAccountID(ctx context.Context, accountID string, page *paging.Pager) ([]*gtsmodel.Conversation, error)
+
+	// PopulateConversation ensures that all sub-models of a conversation are populated (e.g. accounts, last status).
+	PopulateConversation(ctx context.Context, conversation *gtsmodel.Conversation) error
+
+	// PutConversation stores the given conversation.
+	PutConversation(ctx context.Context, conversation *gtsmodel.Conversation) error
+
+	// UpdateConversation updates the given conversation. Updates all columns if none specified.
+	UpdateConversation(ctx context.Context, conversation *gtsmodel.Conversation, columns ...string) error
+
+	// LinkConversationToStatus creates a link between the given conversation and status,
+	// so that the conversation can be updated if the status is later deleted.
+	LinkConversationToStatus(ctx context.Context, conversationID string, statusID string) error
+
+	// DeleteConversationByID deletes a conversation, removing it from the owning account's conversation list.
+	DeleteConversationByID(ctx context.Context, id string) error
+
+	// DeleteConversationsByOwnerAccountID deletes all conversations owned by the given account.
+	DeleteConversationsByOwnerAccountID(ctx context.Context, accountID string) error
+
+	// DeleteStatusFromConversations handles when a status is deleted by updating the last status
+	// of conversations that contained it, or deleting conversations that no longer have any statuses.
+	DeleteStatusFromConversations(ctx context.Context, statusID string) error
+}
@@ -27,23 +27,34 @@ type DB interface {
 	Account
 	Admin
 	Basic
+	Conversation
+	Delivery
 	Domain
 	Emoji
+	Filter
 	Instance
+	Invite
 	List
 	Marker
 	Media
 	Mention
 	Notification
+	Poll
 	Relationship
 	Report
+	Rule
+	ScheduledStatus
 	Search
 	Session
 	Status
 	StatusBookmark
+	StatusEdit
 	StatusFave
+	StatusMute
 	Tag
 	Timeline
 	User
 	Tombstone
+	WebPush
+	WorkerMessage
 }
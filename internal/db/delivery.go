@@ -0,0 +1,61 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// Delivery contains functions related to the queue of outgoing federation deliveries.
+type Delivery interface {
+	// GetDueDeliveries returns up to limit queued deliveries that are due to be attempted at the given time, oldest first.
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*gtsmodel.Delivery, error)
+
+	// CountDeliveries returns the total number of queued deliveries.
+	CountDeliveries(ctx context.Context) (int, error)
+
+	// CountDeliveriesForDomain returns the number of queued deliveries to the given domain.
+	CountDeliveriesForDomain(ctx context.Context, domain string) (int, error)
+
+	// PutDelivery stores the given delivery in the queue.
+	PutDelivery(ctx context.Context, delivery *gtsmodel.Delivery) error
+
+	// UpdateDelivery updates the given delivery, setting the provided columns (empty for all).
+	UpdateDelivery(ctx context.Context, delivery *gtsmodel.Delivery, columns ...string) error
+
+	// DeleteDeliveryByID removes the delivery with the given id from the queue, if it exists.
+	DeleteDeliveryByID(ctx context.Context, id string) error
+
+	// GetDeliveryFailure returns the delivery failure entry for the given domain, if deliveries to it are failing.
+	GetDeliveryFailure(ctx context.Context, domain string) (*gtsmodel.DeliveryFailure, error)
+
+	// GetDeliveryFailures returns all delivery failure entries, ordered by domain.
+	GetDeliveryFailures(ctx context.Context) ([]*gtsmodel.DeliveryFailure, error)
+
+	// PutDeliveryFailure stores the given delivery failure entry.
+	PutDeliveryFailure(ctx context.Context, failure *gtsmodel.DeliveryFailure) error
+
+	// UpdateDeliveryFailure updates the given delivery failure entry, setting the provided columns (empty for all).
+	UpdateDeliveryFailure(ctx context.Context, failure *gtsmodel.DeliveryFailure, columns ...string) error
+
+	// DeleteDeliveryFailure removes the delivery failure entry for the given domain, if it exists.
+	DeleteDeliveryFailure(ctx context.Context, domain string) error
+}
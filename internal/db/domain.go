@@ -24,8 +24,33 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
 
-// Domain contains DB functions related to domains and domain blocks.
+// Domain contains DB functions related to domains, domain allows, and domain blocks.
 type Domain interface {
+	/*
+		Block/allow storage + retrieval functions.
+	*/
+
+	// CreateDomainAllow puts the given instance-level domain allow into the database.
+	CreateDomainAllow(ctx context.Context, allow *gtsmodel.DomainAllow) error
+
+	// GetDomainAllow returns one instance-level domain allow with the given domain, if it exists.
+	GetDomainAllow(ctx context.Context, domain string) (*gtsmodel.DomainAllow, error)
+
+	// GetDomainAllowByID returns one instance-level domain allow with the given id, if it exists.
+	GetDomainAllowByID(ctx context.Context, id string) (*gtsmodel.DomainAllow, error)
+
+	// GetDomainAllows returns all instance-level domain allows currently enforced by this instance.
+	GetDomainAllows(ctx context.Context) ([]*gtsmodel.DomainAllow, error)
+
+	// GetDomainAllowsBySubscriptionID returns all instance-level domain allows created by the given subscription.
+	GetDomainAllowsBySubscriptionID(ctx context.Context, subscriptionID string) ([]*gtsmodel.DomainAllow, error)
+
+	// UpdateDomainAllow updates the given domain allow, setting the provided columns (empty for all).
+	UpdateDomainAllow(ctx context.Context, allow *gtsmodel.DomainAllow, columns ...string) error
+
+	// DeleteDomainAllow deletes an instance-level domain allow with the given domain, if it exists.
+	DeleteDomainAllow(ctx context.Context, domain string) error
+
 	// CreateDomainBlock puts the given instance-level domain block into the database.
 	CreateDomainBlock(ctx context.Context, block *gtsmodel.DomainBlock) error
 
@@ -38,10 +63,41 @@ type Domain interface {
 	// GetDomainBlocks returns all instance-level domain blocks currently enforced by this instance.
 	GetDomainBlocks(ctx context.Context) ([]*gtsmodel.DomainBlock, error)
 
+	// GetDomainBlocksBySubscriptionID returns all instance-level domain blocks created by the given subscription.
+	GetDomainBlocksBySubscriptionID(ctx context.Context, subscriptionID string) ([]*gtsmodel.DomainBlock, error)
+
+	// UpdateDomainBlock updates the given domain block, setting the provided columns (empty for all).
+	UpdateDomainBlock(ctx context.Context, block *gtsmodel.DomainBlock, columns ...string) error
+
 	// DeleteDomainBlock deletes an instance-level domain block with the given domain, if it exists.
 	DeleteDomainBlock(ctx context.Context, domain string) error
 
+	/*
+		Domain permission subscription functions.
+	*/
+
+	// CreateDomainPermissionSubscription puts the given domain permission subscription into the database.
+	CreateDomainPermissionSubscription(ctx context.Context, sub *gtsmodel.DomainPermissionSubscription) error
+
+	// GetDomainPermissionSubscriptionByID returns the domain permission subscription with the given id, if it exists.
+	GetDomainPermissionSubscriptionByID(ctx context.Context, id string) (*gtsmodel.DomainPermissionSubscription, error)
+
+	// GetDomainPermissionSubscriptions returns all domain permission subscriptions of the given permission
+	// type (or of all types, if permType is empty), ordered by priority descending (highest priority first).
+	GetDomainPermissionSubscriptions(ctx context.Context, permType gtsmodel.DomainPermissionType) ([]*gtsmodel.DomainPermissionSubscription, error)
+
+	// UpdateDomainPermissionSubscription updates the given domain permission subscription, setting the provided columns (empty for all).
+	UpdateDomainPermissionSubscription(ctx context.Context, sub *gtsmodel.DomainPermissionSubscription, columns ...string) error
+
+	// DeleteDomainPermissionSubscription deletes the domain permission subscription with the given id, if it exists.
+	DeleteDomainPermissionSubscription(ctx context.Context, id string) error
+
+	/*
+		Block/allow checking functions.
+	*/
+
 	// IsDomainBlocked checks if an instance-level domain block exists for the given domain string (eg., `example.org`).
+	// When running in allowlist federation mode, a domain without a matching domain allow is also considered blocked.
 	IsDomainBlocked(ctx context.Context, domain string) (bool, error)
 
 	// AreDomainsBlocked checks if an instance-level domain block exists for any of the given domains strings, and returns true if even one is found.
@@ -0,0 +1,58 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// Invite contains functions related to invite links.
+type Invite interface {
+	// GetInviteByID returns the invite with the given id, if it exists.
+	GetInviteByID(ctx context.Context, id string) (*gtsmodel.Invite, error)
+
+	// GetInviteByCode returns the invite with the given code, if it exists.
+	GetInviteByCode(ctx context.Context, code string) (*gtsmodel.Invite, error)
+
+	// GetInvitesByUserID returns all invites created by the given user, newest first.
+	GetInvitesByUserID(ctx context.Context, userID string) ([]*gtsmodel.Invite, error)
+
+	// PopulateInvite ensures that the invite's user is populated.
+	PopulateInvite(ctx context.Context, invite *gtsmodel.Invite) error
+
+	// PutInvite stores the given invite.
+	PutInvite(ctx context.Context, invite *gtsmodel.Invite) error
+
+	// UpdateInvite updates the given invite, setting the provided columns (empty for all).
+	UpdateInvite(ctx context.Context, invite *gtsmodel.Invite, columns ...string) error
+
+	// ClaimInviteUse atomically counts one use of the given invite, as long as it
+	// is still valid at the given time. If the invite has expired or has been used
+	// up (possibly by a concurrent sign-up), db.ErrNoEntries will be returned.
+	ClaimInviteUse(ctx context.Context, invite *gtsmodel.Invite, now time.Time) error
+
+	// ReleaseInviteUse gives back a use of the given invite that was
+	// claimed with ClaimInviteUse, for a sign-up that didn't go through.
+	ReleaseInviteUse(ctx context.Context, invite *gtsmodel.Invite) error
+
+	// DeleteInvitesByUserID deletes all invites created by the given user.
+	DeleteInvitesByUserID(ctx context.Context, userID string) error
+}
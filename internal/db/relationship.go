@@ -19,6 +19,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/paging"
@@ -175,4 +176,33 @@ type Relationship interface {
 
 	// PutNote creates or updates a private note.
 	PutNote(ctx context.Context, note *gtsmodel.AccountNote) error
+
+	// IsMuted checks whether source account has an unexpired mute in place against target.
+	IsMuted(ctx context.Context, sourceAccountID string, targetAccountID string) (bool, error)
+
+	// GetMuteByID fetches mute with given ID from the database.
+	GetMuteByID(ctx context.Context, id string) (*gtsmodel.UserMute, error)
+
+	// GetMute returns the mute from account1 targeting account2, if it exists, or an error if it doesn't.
+	// Note that the returned mute may have expired; check with UserMute.Expired().
+	GetMute(ctx context.Context, account1 string, account2 string) (*gtsmodel.UserMute, error)
+
+	// GetAccountMutes returns all mutes originating from the given account, with given optional paging parameters.
+	GetAccountMutes(ctx context.Context, accountID string, paging *paging.Pager) ([]*gtsmodel.UserMute, error)
+
+	// PutMute attempts to place the given account mute in the database.
+	PutMute(ctx context.Context, mute *gtsmodel.UserMute) error
+
+	// UpdateMute updates one mute by ID.
+	UpdateMute(ctx context.Context, mute *gtsmodel.UserMute, columns ...string) error
+
+	// DeleteMuteByID removes mute with given ID from the database.
+	DeleteMuteByID(ctx context.Context, id string) error
+
+	// DeleteAccountMutes will delete all database mutes to / from the given account ID.
+	DeleteAccountMutes(ctx context.Context, accountID string) error
+
+	// DeleteExpiredMutes will delete all database mutes that expired at or before the given time,
+	// returning the number of mutes deleted.
+	DeleteExpiredMutes(ctx context.Context, now time.Time) (int, error)
 }
@@ -0,0 +1,43 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// Rule contains functions related to instance rules.
+type Rule interface {
+	// GetRuleByID returns the rule with the given id, if it exists.
+	// Deleted rules are returned too, so that reports can refer to them.
+	GetRuleByID(ctx context.Context, id string) (*gtsmodel.Rule, error)
+
+	// GetRulesByIDs returns the rules with the given ids, including deleted rules.
+	GetRulesByIDs(ctx context.Context, ids []string) ([]*gtsmodel.Rule, error)
+
+	// GetActiveRules returns all rules that haven't been deleted, in order.
+	GetActiveRules(ctx context.Context) ([]*gtsmodel.Rule, error)
+
+	// PutRule stores the given rule.
+	PutRule(ctx context.Context, rule *gtsmodel.Rule) error
+
+	// UpdateRule updates the given rule, setting the provided columns (empty for all).
+	UpdateRule(ctx context.Context, rule *gtsmodel.Rule, columns ...string) error
+}
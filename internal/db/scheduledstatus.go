@@ -0,0 +1,55 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// ScheduledStatus contains functions related to statuses scheduled for later publication.
+type ScheduledStatus interface {
+	// GetScheduledStatusByID returns the scheduled status with the given id, if it exists.
+	GetScheduledStatusByID(ctx context.Context, id string) (*gtsmodel.ScheduledStatus, error)
+
+	// GetScheduledStatusesForAccountID returns a page of scheduled statuses owned by the given account, newest first.
+	GetScheduledStatusesForAccountID(ctx context.Context, accountID string, maxID string, sinceID string, minID string, limit int) ([]*gtsmodel.ScheduledStatus, error)
+
+	// GetDueScheduledStatuses returns all scheduled statuses due to be published at or before the given time.
+	GetDueScheduledStatuses(ctx context.Context, now time.Time) ([]*gtsmodel.ScheduledStatus, error)
+
+	// CountScheduledStatusesForAccountID returns the number of scheduled statuses owned by the given account.
+	CountScheduledStatusesForAccountID(ctx context.Context, accountID string) (int, error)
+
+	// PopulateScheduledStatus ensures that the scheduled status' account, application and media attachments are populated.
+	PopulateScheduledStatus(ctx context.Context, scheduledStatus *gtsmodel.ScheduledStatus) error
+
+	// PutScheduledStatus stores the given scheduled status.
+	PutScheduledStatus(ctx context.Context, scheduledStatus *gtsmodel.ScheduledStatus) error
+
+	// UpdateScheduledStatus updates the given scheduled status, setting the provided columns (empty for all).
+	UpdateScheduledStatus(ctx context.Context, scheduledStatus *gtsmodel.ScheduledStatus, columns ...string) error
+
+	// DeleteScheduledStatusByID deletes the scheduled status with the given id, if it exists.
+	DeleteScheduledStatusByID(ctx context.Context, id string) error
+
+	// DeleteScheduledStatusesByAccountID deletes all scheduled statuses owned by the given account.
+	DeleteScheduledStatusesByAccountID(ctx context.Context, accountID string) error
+}
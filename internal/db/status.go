@@ -80,7 +80,12 @@ type Status interface {
 	// If onlyDirect is true, only the immediate children will be returned.
 	GetStatusChildren(ctx context.Context, status *gtsmodel.Status, onlyDirect bool, minID string) ([]*gtsmodel.Status, error)
 
-	// IsStatusMutedBy checks if a given status has been muted by a given account ID
+	// GetStatusThreadRootID returns the ID of the top-most status in the reply
+	// chain of the given status that is known to the database. For a top-level
+	// status this is just the ID of the status itself.
+	GetStatusThreadRootID(ctx context.Context, status *gtsmodel.Status) (string, error)
+
+	// IsStatusMutedBy checks if the thread containing a given status has been muted by a given account ID
 	IsStatusMutedBy(ctx context.Context, status *gtsmodel.Status, accountID string) (bool, error)
 
 	// IsStatusBookmarkedBy checks if a given status has been bookmarked by a given account ID
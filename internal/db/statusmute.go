@@ -0,0 +1,58 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"context"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+type StatusMute interface {
+	// GetStatusMuteID is a shortcut function for returning just the database ID
+	// of a status mute created by the given accountID, targeting the given statusID.
+	//
+	// Status mutes are keyed on the root of a thread, see GetStatusThreadRootID.
+	GetStatusMuteID(ctx context.Context, accountID string, statusID string) (string, error)
+
+	// PutStatusMute inserts the given statusMute into the database.
+	PutStatusMute(ctx context.Context, statusMute *gtsmodel.StatusMute) error
+
+	// DeleteStatusMute deletes one status mute with the given ID.
+	DeleteStatusMute(ctx context.Context, id string) error
+
+	// DeleteStatusMutes mass deletes status mutes targeting targetAccountID
+	// and/or originating from originAccountID.
+	//
+	// If targetAccountID is set and originAccountID isn't, all status mutes
+	// that target the given account will be deleted.
+	//
+	// If originAccountID is set and targetAccountID isn't, all status mutes
+	// originating from the given account will be deleted.
+	//
+	// If both are set, then status mutes that target targetAccountID and
+	// originate from originAccountID will be deleted.
+	//
+	// At least one parameter must not be an empty string.
+	DeleteStatusMutes(ctx context.Context, targetAccountID string, originAccountID string) error
+
+	// DeleteStatusMutesForStatus deletes all status mutes that target the
+	// given status ID. This is useful when a status has been deleted, and you need
+	// to clean up after it.
+	DeleteStatusMutesForStatus(ctx context.Context, statusID string) error
+}
@@ -19,6 +19,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 )
@@ -37,10 +38,30 @@ type User interface {
 	GetUserByExternalID(ctx context.Context, id string) (*gtsmodel.User, error)
 	// GetUserByConfirmationToken returns one user by its confirmation token, or an error if something goes wrong.
 	GetUserByConfirmationToken(ctx context.Context, confirmationToken string) (*gtsmodel.User, error)
+	// GetUserByResetPasswordToken returns one user by its reset password token, or an error if something goes wrong.
+	GetUserByResetPasswordToken(ctx context.Context, resetPasswordToken string) (*gtsmodel.User, error)
 	// PutUser will attempt to place user in the database
 	PutUser(ctx context.Context, user *gtsmodel.User) error
 	// UpdateUser updates one user by its primary key, updating either only the specified columns, or all of them.
 	UpdateUser(ctx context.Context, user *gtsmodel.User, columns ...string) error
+	// UpdateUserTwoFactorCounter stores the given period counter as that of the last
+	// TOTP code accepted for the user, as long as it's greater than the stored one.
+	// If it isn't, the code has already been used, and db.ErrNoEntries is returned.
+	UpdateUserTwoFactorCounter(ctx context.Context, user *gtsmodel.User, counter int64) error
+	// UpdateUserTwoFactorRecoveryCodes replaces the recovery codes of the user with the
+	// given codes, as long as the stored ones are still those on the user model. If
+	// they aren't, they've been changed meanwhile, and db.ErrNoEntries is returned.
+	UpdateUserTwoFactorRecoveryCodes(ctx context.Context, user *gtsmodel.User, codes []string) error
+	// UpdateUserPasswordByResetToken sets the user's encrypted password to the one on the
+	// user model, and clears their reset password token, as long as the stored token is
+	// still the given one. If it isn't, it's been used meanwhile, and db.ErrNoEntries is returned.
+	UpdateUserPasswordByResetToken(ctx context.Context, user *gtsmodel.User, token string) error
+	// ClaimTwoFactorAttempt counts an attempt at entering a two-factor code for the user.
+	// Once max attempts have been made without the count being reset (by setting
+	// TwoFactorAttempts to 0), no more can be made until lockout has passed since the
+	// last one, and db.ErrNoEntries is returned. Attempts more than lockout apart
+	// start the count over.
+	ClaimTwoFactorAttempt(ctx context.Context, user *gtsmodel.User, max int, lockout time.Duration) error
 	// DeleteUserByID deletes one user by its ID.
 	DeleteUserByID(ctx context.Context, userID string) error
 }
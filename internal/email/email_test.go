@@ -117,6 +117,25 @@ func (suite *EmailTestSuite) TestTemplateReportLocalToLocal() {
 	suite.Equal("To: user@example.org\r\nFrom: test@example.org\r\nSubject: GoToSocial New Report\r\n\r\nHello moderator of Test Instance (https://example.org)!\r\n\r\nSomeone from your instance has reported another user from your instance.\r\n\r\nTo view the report, paste the following link into your browser: https://example.org/settings/admin/reports/01GVJHN1RTYZCZTCXVPPPKBX6R\r\n\r\n", suite.sentEmails["user@example.org"])
 }
 
+func (suite *EmailTestSuite) TestTemplateReportWithRules() {
+	// Someone from our instance has reported another
+	// user on our instance for breaking some rules.
+	reportData := email.NewReportData{
+		InstanceURL:        "https://example.org",
+		InstanceName:       "Test Instance",
+		ReportURL:          "https://example.org/settings/admin/reports/01GVJHN1RTYZCZTCXVPPPKBX6R",
+		ReportDomain:       "",
+		ReportTargetDomain: "",
+		ReportRules:        []string{"Be nice to each other.", "No spam or advertising."},
+	}
+
+	if err := suite.sender.SendNewReportEmail([]string{"user@example.org"}, reportData); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Len(suite.sentEmails, 1)
+	suite.Equal("To: user@example.org\r\nFrom: test@example.org\r\nSubject: GoToSocial New Report\r\n\r\nHello moderator of Test Instance (https://example.org)!\r\n\r\nSomeone from your instance has reported another user from your instance.\r\n\r\nAccording to the reporter, the following rules were broken:\r\n- Be nice to each other.\r\n- No spam or advertising.\r\n\r\nTo view the report, paste the following link into your browser: https://example.org/settings/admin/reports/01GVJHN1RTYZCZTCXVPPPKBX6R\r\n\r\n", suite.sentEmails["user@example.org"])
+}
+
 func (suite *EmailTestSuite) TestTemplateReportMoreThanOneModeratorAddress() {
 	reportData := email.NewReportData{
 		InstanceURL:        "https://example.org",
@@ -185,6 +204,51 @@ func (suite *EmailTestSuite) TestTemplateReportClosedLocalAccountNoComment() {
 	suite.Equal("To: user@example.org\r\nFrom: test@example.org\r\nSubject: GoToSocial Report Closed\r\n\r\nHello !\r\n\r\nYou recently reported the account @1happyturtle to the moderator(s) of Test Instance (https://example.org).\r\n\r\nThe report you submitted has now been closed.\r\n\r\nThe moderator who closed the report did not leave a comment.\r\n\r\n", suite.sentEmails["user@example.org"])
 }
 
+func (suite *EmailTestSuite) TestTemplateNewSignup() {
+	newSignupData := email.NewSignupData{
+		InstanceURL:    "https://example.org",
+		InstanceName:   "Test Instance",
+		SignupUsername: "someone",
+		SignupEmail:    "someone@example.com",
+		SignupReason:   "I'd like to join please.",
+		SignupURL:      "https://example.org/settings/admin/accounts/01H8MSFWHG9SDH8GGVZTRT3S1M",
+	}
+
+	if err := suite.sender.SendNewSignupEmail([]string{"admin@example.org"}, newSignupData); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Len(suite.sentEmails, 1)
+	suite.Equal("To: admin@example.org\r\nFrom: test@example.org\r\nSubject: GoToSocial New Sign-Up\r\n\r\nHello moderator of Test Instance (https://example.org)!\r\n\r\nSomeone has signed up for a new account on your instance with the username someone and the email address someone@example.com.\r\n\r\nThey gave the following reason for signing up: I'd like to join please.\r\n\r\nTo approve or reject the sign-up, paste the following link into your browser: https://example.org/settings/admin/accounts/01H8MSFWHG9SDH8GGVZTRT3S1M\r\n\r\n", suite.sentEmails["admin@example.org"])
+}
+
+func (suite *EmailTestSuite) TestTemplateSignupApproved() {
+	signupApprovedData := email.SignupApprovedData{
+		Username:     "someone",
+		InstanceURL:  "https://example.org",
+		InstanceName: "Test Instance",
+	}
+
+	if err := suite.sender.SendSignupApprovedEmail("user@example.org", signupApprovedData); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Len(suite.sentEmails, 1)
+	suite.Equal("To: user@example.org\r\nFrom: test@example.org\r\nSubject: GoToSocial Sign-Up Approved\r\n\r\nHello someone!\r\n\r\nYour sign-up request for Test Instance (https://example.org) has been approved by a moderator.\r\n\r\nIf you have already confirmed your email address, you can now log in to your new account.\r\n\r\n", suite.sentEmails["user@example.org"])
+}
+
+func (suite *EmailTestSuite) TestTemplateSignupRejectedNoMessage() {
+	signupRejectedData := email.SignupRejectedData{
+		Username:     "someone",
+		InstanceURL:  "https://example.org",
+		InstanceName: "Test Instance",
+	}
+
+	if err := suite.sender.SendSignupRejectedEmail("user@example.org", signupRejectedData); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Len(suite.sentEmails, 1)
+	suite.Equal("To: user@example.org\r\nFrom: test@example.org\r\nSubject: GoToSocial Sign-Up Rejected\r\n\r\nHello someone!\r\n\r\nYour sign-up request for Test Instance (https://example.org) has been rejected by a moderator.\r\n\r\nThe moderator who rejected the sign-up did not leave a message.\r\n\r\n", suite.sentEmails["user@example.org"])
+}
+
 func TestEmailTestSuite(t *testing.T) {
 	suite.Run(t, new(EmailTestSuite))
 }
@@ -37,6 +37,10 @@ type NewReportData struct {
 	// Domain targeted by the report.
 	// Can be empty string for local reports targeting local users.
 	ReportTargetDomain string
+	// Text of the instance rules that were
+	// broken, according to the reporter.
+	// Can be empty if no rules were given.
+	ReportRules []string
 }
 
 func (s *sender) SendNewReportEmail(toAddresses []string, data NewReportData) error {
@@ -31,7 +31,7 @@ type ResetData struct {
 	// Name of the instance to present to the receiver.
 	InstanceName string
 	// Link to present to the receiver to click on and begin the reset process.
-	// Should be a full link with protocol eg., https://example.org/reset_password?token=some-reset-password-token
+	// Should be a full link with protocol eg., https://example.org/auth/reset_password?token=some-reset-password-token
 	ResetLink string
 }
 
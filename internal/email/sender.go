@@ -46,6 +46,21 @@ type Sender interface {
 	// SendReportClosedEmail sends an email notification to the given address, letting them
 	// know that a report that they created has been closed / resolved by an admin.
 	SendReportClosedEmail(toAddress string, data ReportClosedData) error
+
+	// SendNewSignupEmail sends an email notification to the given addresses, letting them
+	// know that a new sign-up has been submitted to the instance and is awaiting approval.
+	//
+	// It is expected that the toAddresses have already been filtered to ensure that they
+	// all belong to admins + moderators.
+	SendNewSignupEmail(toAddresses []string, data NewSignupData) error
+
+	// SendSignupApprovedEmail sends an email to the given address, letting them
+	// know that their sign-up request has been approved by an admin or moderator.
+	SendSignupApprovedEmail(toAddress string, data SignupApprovedData) error
+
+	// SendSignupRejectedEmail sends an email to the given address, letting them
+	// know that their sign-up request has been rejected by an admin or moderator.
+	SendSignupRejectedEmail(toAddress string, data SignupRejectedData) error
 }
 
 // NewSender returns a new email Sender interface with the given configuration, or an error if something goes wrong.
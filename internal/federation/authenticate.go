@@ -119,6 +119,20 @@ func (f *federator) AuthenticateFederatedRequest(ctx context.Context, requestedU
 		l.Trace("public key is ours, no dereference needed")
 		requestingAccountURI, pubKey, errWithCode = f.derefDBOnly(ctx, pubKeyIDStr)
 	} else {
+		// Make sure we actually federate with the key's domain
+		// before doing any work; the signature check middleware
+		// should have caught this already, but be thorough.
+		blocked, err := f.db.IsURIBlocked(ctx, pubKeyID)
+		if err != nil {
+			err := gtserror.Newf("error checking domain block for %s: %w", pubKeyID.Host, err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		if blocked {
+			err := gtserror.Newf("domain %s is blocked", pubKeyID.Host)
+			return nil, gtserror.NewErrorForbidden(err)
+		}
+
 		l.Trace("public key is not ours, checking if we need to dereference")
 		requestingAccountURI, pubKey, errWithCode = f.deref(ctx, requestedUsername, pubKeyIDStr, pubKeyID)
 	}
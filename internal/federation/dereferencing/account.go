@@ -302,6 +302,13 @@ func (d *deref) enrichAccount(ctx context.Context, requestUser string, uri *url.
 	}
 
 	if account.Username != "" {
+		// Check whether this account's domain is blocked before we make any requests to it.
+		if blocked, err := d.state.DB.IsDomainBlocked(ctx, account.Domain); err != nil {
+			return nil, nil, gtserror.Newf("error checking blocked domain: %w", err)
+		} else if blocked {
+			return nil, nil, gtserror.Newf("%s is blocked", account.Domain)
+		}
+
 		// A username was provided so we can attempt a webfinger, this ensures up-to-date accountdomain info.
 		accDomain, accURI, err := d.fingerRemoteAccount(ctx, tsport, account.Username, account.Domain)
 		if err != nil {
@@ -463,6 +470,10 @@ func (d *deref) enrichAccount(ctx context.Context, requestUser string, uri *url.
 		latestAcc.CreatedAt = account.CreatedAt
 		latestAcc.Language = account.Language
 
+		// Account moves are only set by Move
+		// activities, so keep any existing value.
+		latestAcc.MovedToAccountID = account.MovedToAccountID
+
 		// This is an existing account, update the model in the database.
 		if err := d.state.DB.UpdateAccount(ctx, latestAcc); err != nil {
 			return nil, nil, gtserror.Newf("error updating database: %w", err)
@@ -34,6 +34,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/log"
 	"github.com/superseriousbusiness/gotosocial/internal/media"
 	"github.com/superseriousbusiness/gotosocial/internal/transport"
+	"golang.org/x/exp/slices"
 )
 
 // statusUpToDate returns whether the given status model is both updateable
@@ -283,6 +284,14 @@ func (d *deref) enrichStatus(
 	latestStatus.FetchedAt = time.Now()
 	latestStatus.Local = status.Local
 
+	if !status.CreatedAt.IsZero() {
+		// Ensure the existing status is populated, so
+		// it can be compared against the latest version.
+		if err := d.state.DB.PopulateStatus(ctx, status); err != nil {
+			log.Errorf(ctx, "error populating existing status %s: %v", uri, err)
+		}
+	}
+
 	// Ensure the status' mentions are populated, and pass in existing to check for changes.
 	if err := d.fetchStatusMentions(ctx, requestUser, status, latestStatus); err != nil {
 		return nil, nil, gtserror.Newf("error populating mentions for status %s: %w", uri, err)
@@ -303,6 +312,16 @@ func (d *deref) enrichStatus(
 		return nil, nil, gtserror.Newf("error populating emojis for status %s: %w", uri, err)
 	}
 
+	// Ensure the status' poll is populated, passing in existing to check for changes.
+	if err := d.fetchStatusPoll(ctx, status, latestStatus); err != nil {
+		return nil, nil, gtserror.Newf("error populating poll for status %s: %w", uri, err)
+	}
+
+	// Check for any edits to the status, storing the existing version in its history if so.
+	if err := d.handleStatusEdit(ctx, status, latestStatus); err != nil {
+		return nil, nil, gtserror.Newf("error handling edit for status %s: %w", uri, err)
+	}
+
 	if status.CreatedAt.IsZero() {
 		// CreatedAt will be zero if no local copy was
 		// found in one of the GetStatusBy___() functions.
@@ -329,6 +348,90 @@ func (d *deref) enrichStatus(
 	return latestStatus, apubStatus, nil
 }
 
+// handleStatusEdit compares the existing status model to
+// the latest dereferenced version, and if any of the content
+// has changed, stores the existing version as a historical
+// StatusEdit, appending it to the latest version's history.
+func (d *deref) handleStatusEdit(ctx context.Context, existing, status *gtsmodel.Status) error {
+	// Carry over the existing edit history.
+	status.EditIDs = existing.EditIDs
+	status.Edits = existing.Edits
+
+	if existing.CreatedAt.IsZero() {
+		// This is a new status,
+		// so there's no history.
+		return nil
+	}
+
+	var (
+		existingPollOptions []string
+		latestPollOptions   []string
+	)
+
+	if existing.Poll != nil {
+		existingPollOptions = existing.Poll.Options
+	}
+
+	if status.Poll != nil {
+		latestPollOptions = status.Poll.Options
+	}
+
+	if existing.Content == status.Content &&
+		existing.ContentWarning == status.ContentWarning &&
+		*existing.Sensitive == *status.Sensitive &&
+		slices.Equal(existing.AttachmentIDs, status.AttachmentIDs) &&
+		slices.Equal(existingPollOptions, latestPollOptions) {
+		// Nothing significant changed, keep
+		// the existing edited time (if any).
+		status.EditedAt = existing.EditedAt
+		return nil
+	}
+
+	// Take a snapshot of the existing
+	// version, to store in the history.
+	edit := &gtsmodel.StatusEdit{
+		ID:             id.NewULID(),
+		Content:        existing.Content,
+		ContentWarning: existing.ContentWarning,
+		Text:           existing.Text,
+		Language:       existing.Language,
+		Sensitive:      existing.Sensitive,
+		AttachmentIDs:  existing.AttachmentIDs,
+		Attachments:    existing.Attachments,
+		StatusID:       status.ID,
+		CreatedAt:      existing.EditedAt,
+	}
+
+	if edit.CreatedAt.IsZero() {
+		// Status never edited before,
+		// so this version is the original.
+		edit.CreatedAt = existing.CreatedAt
+	}
+
+	if existing.Poll != nil {
+		// Include poll options + votes at time of edit.
+		edit.PollOptions = existing.Poll.Options
+		edit.PollVotes = existing.Poll.Votes
+	}
+
+	// Insert the historical version of the status.
+	if err := d.state.DB.PutStatusEdit(ctx, edit); err != nil {
+		return gtserror.Newf("error putting status edit in database: %w", err)
+	}
+
+	// Append the edit to the latest version's history.
+	status.EditIDs = append(slices.Clone(existing.EditIDs), edit.ID)
+	status.Edits = append(slices.Clone(existing.Edits), edit)
+
+	if status.EditedAt.IsZero() {
+		// Remote didn't tell us when
+		// the status was edited, use now.
+		status.EditedAt = time.Now()
+	}
+
+	return nil
+}
+
 func (d *deref) fetchStatusMentions(ctx context.Context, requestUser string, existing, status *gtsmodel.Status) error {
 	// Allocate new slice to take the yet-to-be created mention IDs.
 	status.MentionIDs = make([]string, len(status.Mentions))
@@ -452,6 +555,82 @@ func (d *deref) fetchStatusTags(ctx context.Context, requestUser string, status
 	return nil
 }
 
+func (d *deref) fetchStatusPoll(ctx context.Context, existing, status *gtsmodel.Status) error {
+	var (
+		// insertStatusPoll generates ID and inserts the poll attached to status into the database.
+		insertStatusPoll = func(ctx context.Context, status *gtsmodel.Status) error {
+			status.Poll.ID = id.NewULID()
+			status.Poll.StatusID = status.ID
+			status.Poll.Status = status
+			status.PollID = status.Poll.ID
+
+			if err := d.state.DB.PutPoll(ctx, status.Poll); err != nil {
+				return gtserror.Newf("error putting poll in database: %w", err)
+			}
+
+			return nil
+		}
+
+		// deleteStatusPoll deletes the poll with ID, and all attached votes, from the database.
+		deleteStatusPoll = func(ctx context.Context, pollID string) error {
+			if err := d.state.DB.DeletePollByID(ctx, pollID); err != nil {
+				return gtserror.Newf("error deleting existing poll from database: %w", err)
+			}
+			return nil
+		}
+	)
+
+	switch {
+	case existing.Poll == nil && status.Poll == nil:
+		// no poll before or after, nothing to do.
+		return nil
+
+	case existing.Poll == nil && status.Poll != nil:
+		// no previous poll, insert new poll!
+		return insertStatusPoll(ctx, status)
+
+	case status.Poll == nil:
+		// existing poll has been deleted, remove this.
+		status.PollID = ""
+		return deleteStatusPoll(ctx, existing.PollID)
+
+	case !slices.Equal(existing.Poll.Options, status.Poll.Options):
+		// poll options have changed, so all
+		// previous votes are now invalidated.
+		if err := deleteStatusPoll(ctx, existing.PollID); err != nil {
+			return err
+		}
+
+		// insert latest poll version.
+		return insertStatusPoll(ctx, status)
+
+	default:
+		// Same poll, update the remote-provided counts,
+		// end / closed times on our existing copy.
+		existing.Poll.Votes = status.Poll.Votes
+		existing.Poll.Voters = status.Poll.Voters
+		existing.Poll.HideCounts = status.Poll.HideCounts
+		existing.Poll.ExpiresAt = status.Poll.ExpiresAt
+		existing.Poll.ClosedAt = status.Poll.ClosedAt
+
+		if err := d.state.DB.UpdatePoll(ctx, existing.Poll,
+			"votes",
+			"voters",
+			"hide_counts",
+			"expires_at",
+			"closed_at",
+		); err != nil {
+			return gtserror.Newf("error updating poll in database: %w", err)
+		}
+
+		// Keep the existing poll model.
+		status.PollID = existing.PollID
+		status.Poll = existing.Poll
+		status.Poll.Status = status
+		return nil
+	}
+}
+
 func (d *deref) fetchStatusAttachments(ctx context.Context, tsport transport.Transport, existing, status *gtsmodel.Status) error {
 	// Allocate new slice to take the yet-to-be fetched attachment IDs.
 	status.AttachmentIDs = make([]string, len(status.Attachments))
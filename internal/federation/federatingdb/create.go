@@ -28,10 +28,13 @@ import (
 	"github.com/superseriousbusiness/activity/streams/vocab"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtscontext"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
 	"github.com/superseriousbusiness/gotosocial/internal/log"
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"golang.org/x/exp/slices"
 )
 
 // Create adds a new entry to the database which must be able to be
@@ -142,9 +145,14 @@ func (f *federatingDB) activityCreate(ctx context.Context, asType vocab.Type, re
 		// we have a type -- what is it?
 		asObjectTypeName := asObjectType.GetTypeName()
 		switch asObjectTypeName {
-		case ap.ObjectNote:
-			// CREATE A NOTE
-			if err := f.createNote(ctx, objectIter.GetActivityStreamsNote(), receivingAccount, requestingAccount); err != nil {
+		case ap.ObjectNote, ap.ActivityQuestion:
+			// CREATE A NOTE (OR QUESTION)
+			statusable, ok := asObjectType.(ap.Statusable)
+			if !ok {
+				errs = append(errs, fmt.Sprintf("could not convert %s to statusable", asObjectTypeName))
+				continue
+			}
+			if err := f.createStatusable(ctx, statusable, receivingAccount, requestingAccount); err != nil {
 				errs = append(errs, err.Error())
 			}
 		default:
@@ -159,14 +167,20 @@ func (f *federatingDB) activityCreate(ctx context.Context, asType vocab.Type, re
 	return nil
 }
 
-// createNote handles a Create activity with a Note type.
-func (f *federatingDB) createNote(ctx context.Context, note vocab.ActivityStreamsNote, receivingAccount *gtsmodel.Account, requestingAccount *gtsmodel.Account) error {
+// createStatusable handles a Create activity with a Note or Question type.
+func (f *federatingDB) createStatusable(ctx context.Context, note ap.Statusable, receivingAccount *gtsmodel.Account, requestingAccount *gtsmodel.Account) error {
 	l := log.WithContext(ctx).
 		WithFields(kv.Fields{
 			{"receivingAccount", receivingAccount.URI},
 			{"requestingAccount", requestingAccount.URI},
 		}...)
 
+	// Check if this is actually a vote in one of our polls,
+	// which are sent as a named Note in reply to the Question.
+	if handled, err := f.createPollVote(ctx, note, requestingAccount); handled || err != nil {
+		return err
+	}
+
 	// Check if we have a forward.
 	// In other words, was the note posted to our inbox by at least one actor who actually created the note, or are they just forwarding it?
 	forward := true
@@ -174,7 +188,7 @@ func (f *federatingDB) createNote(ctx context.Context, note vocab.ActivityStream
 	// note should have an attributedTo
 	noteAttributedTo := note.GetActivityStreamsAttributedTo()
 	if noteAttributedTo == nil {
-		return errors.New("createNote: note had no attributedTo")
+		return errors.New("createStatusable: note had no attributedTo")
 	}
 
 	// compare the attributedTo(s) with the actor who posted this to our inbox
@@ -213,7 +227,7 @@ func (f *federatingDB) createNote(ctx context.Context, note vocab.ActivityStream
 
 	status, err := f.typeConverter.ASStatusToStatus(ctx, note)
 	if err != nil {
-		return fmt.Errorf("createNote: error converting note to status: %s", err)
+		return fmt.Errorf("createStatusable: error converting note to status: %s", err)
 	}
 
 	// id the status based on the time it was created
@@ -223,14 +237,32 @@ func (f *federatingDB) createNote(ctx context.Context, note vocab.ActivityStream
 	}
 	status.ID = statusID
 
+	if status.Poll != nil {
+		// Store the attached poll, this must be
+		// inserted before the status referencing it.
+		status.Poll.ID = id.NewULID()
+		status.Poll.StatusID = status.ID
+		status.Poll.Status = status
+		status.PollID = status.Poll.ID
+
+		if err := f.state.DB.PutPoll(ctx, status.Poll); err != nil {
+			return fmt.Errorf("createStatusable: database error inserting poll: %s", err)
+		}
+	}
+
 	if err := f.state.DB.PutStatus(ctx, status); err != nil {
 		if errors.Is(err, db.ErrAlreadyExists) {
 			// the status already exists in the database, which means we've already handled everything else,
-			// so we can just return nil here and be done with it.
+			// so we can just return nil here and be done with it (after tidying up our now-unused poll).
+			if status.PollID != "" {
+				if err := f.state.DB.DeletePollByID(ctx, status.PollID); err != nil {
+					return fmt.Errorf("createStatusable: database error deleting poll: %s", err)
+				}
+			}
 			return nil
 		}
 		// an actual error has happened
-		return fmt.Errorf("createNote: database error inserting status: %s", err)
+		return fmt.Errorf("createStatusable: database error inserting status: %s", err)
 	}
 
 	f.state.Workers.EnqueueFederator(ctx, messages.FromFederator{
@@ -244,6 +276,104 @@ func (f *federatingDB) createNote(ctx context.Context, note vocab.ActivityStream
 	return nil
 }
 
+// createPollVote checks whether the given statusable is a vote in a
+// local poll (i.e. a Note with a name but no content, in reply to a
+// local status with a poll attached), and if so stores the vote. The
+// returned bool indicates whether statusable was handled as a vote.
+func (f *federatingDB) createPollVote(ctx context.Context, statusable ap.Statusable, requestingAccount *gtsmodel.Account) (bool, error) {
+	if statusable.GetTypeName() != ap.ObjectNote {
+		// Votes are always Notes.
+		return false, nil
+	}
+
+	name := ap.ExtractName(statusable)
+	if name == "" || ap.ExtractContent(statusable) != "" {
+		// Votes have a name (the
+		// option) and no content.
+		return false, nil
+	}
+
+	inReplyToURI := ap.ExtractInReplyToURI(statusable)
+	if inReplyToURI == nil {
+		return false, nil
+	}
+
+	// Check if we have the replied-to status, we only need the poll ID.
+	status, err := f.state.DB.GetStatusByURI(gtscontext.SetBarebones(ctx), inReplyToURI.String())
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			return false, nil
+		}
+		return true, gtserror.Newf("db error getting status %s: %w", inReplyToURI, err)
+	}
+
+	if !*status.Local || status.PollID == "" {
+		// Not a vote in one of our polls.
+		return false, nil
+	}
+
+	// Votes may only be delivered by the voter themselves.
+	attributedTo, err := ap.ExtractAttributedToURI(statusable)
+	if err != nil || attributedTo.String() != requestingAccount.URI {
+		return true, gtserror.Newf("vote in poll %s not attributed to requester %s", status.PollID, requestingAccount.URI)
+	}
+
+	poll, err := f.state.DB.GetPollByID(ctx, status.PollID)
+	if err != nil {
+		return true, gtserror.Newf("db error getting poll %s: %w", status.PollID, err)
+	}
+
+	if poll.Closed() || poll.Expired() {
+		log.Debugf(ctx, "ignoring vote in closed poll %s", poll.ID)
+		return true, nil
+	}
+
+	choice := poll.GetChoice(name)
+	if choice == -1 {
+		return true, gtserror.Newf("invalid choice %q for poll %s", name, poll.ID)
+	}
+
+	// Check for an existing vote by this account. Multiple-choice votes
+	// are delivered as one Note per choice, so these are merged together.
+	existing, err := f.state.DB.GetPollVoteBy(ctx, poll.ID, requestingAccount.ID)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return true, gtserror.Newf("db error getting existing vote in poll %s: %w", poll.ID, err)
+	}
+
+	choices := []int{choice}
+
+	if existing != nil {
+		if !*poll.Multiple || slices.Contains(existing.Choices, choice) {
+			// Already voted for this.
+			return true, nil
+		}
+
+		// Merge existing choices with this new one.
+		choices = append(choices, existing.Choices...)
+		slices.Sort(choices)
+
+		// Drop the existing vote, it'll be replaced below.
+		if err := f.state.DB.DeletePollVoteBy(ctx, poll.ID, requestingAccount.ID); err != nil {
+			return true, gtserror.Newf("db error deleting existing vote in poll %s: %w", poll.ID, err)
+		}
+	}
+
+	vote := &gtsmodel.PollVote{
+		ID:        id.NewULID(),
+		Choices:   choices,
+		AccountID: requestingAccount.ID,
+		Account:   requestingAccount,
+		PollID:    poll.ID,
+		Poll:      poll,
+	}
+
+	if err := f.state.DB.PutPollVote(ctx, vote); err != nil {
+		return true, gtserror.Newf("db error inserting vote in poll %s: %w", poll.ID, err)
+	}
+
+	return true, nil
+}
+
 /*
 	FOLLOW HANDLERS
 */
@@ -0,0 +1,87 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package federatingdb
+
+import (
+	"context"
+	"errors"
+
+	"codeberg.org/gruf/go-logger/v2/level"
+	"github.com/superseriousbusiness/activity/streams/vocab"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+)
+
+func (f *federatingDB) Move(ctx context.Context, move vocab.ActivityStreamsMove) error {
+	if log.Level() >= level.DEBUG {
+		i, err := marshalItem(move)
+		if err != nil {
+			return err
+		}
+		l := log.WithContext(ctx).
+			WithField("move", i)
+		l.Debug("entering Move")
+	}
+
+	receivingAccount, requestingAccount, internal := extractFromCtx(ctx)
+	if internal {
+		return nil // Already processed.
+	}
+
+	// Only accounts can be moved, and only by themselves,
+	// so the object of the move must be the requester.
+	objectIRI, err := ap.ExtractObjectURI(move)
+	if err != nil {
+		return gtserror.Newf("error extracting move object: %w", err)
+	}
+
+	if objectIRI.String() != requestingAccount.URI {
+		return errors.New("Move: move object account and requesting account were not the same")
+	}
+
+	// Extract the account being moved to.
+	targetProp := move.GetActivityStreamsTarget()
+	if targetProp == nil || targetProp.Len() == 0 {
+		return errors.New("Move: no target set on vocab.ActivityStreamsMove")
+	}
+
+	targetIRI := targetProp.At(0).GetIRI()
+	if t := targetProp.At(0).GetType(); targetIRI == nil && t != nil && t.GetJSONLDId() != nil {
+		// Target was provided as
+		// an object, use its ID.
+		targetIRI = t.GetJSONLDId().GetIRI()
+	}
+
+	if targetIRI == nil {
+		return errors.New("Move: could not extract target IRI from vocab.ActivityStreamsMove")
+	}
+
+	// Verifying the move requires dereferencing
+	// the target account, so process it async.
+	f.state.Workers.EnqueueFederator(ctx, messages.FromFederator{
+		APObjectType:     ap.ObjectProfile,
+		APActivityType:   ap.ActivityMove,
+		APIri:            targetIRI,
+		GTSModel:         requestingAccount,
+		ReceivingAccount: receivingAccount,
+	})
+
+	return nil
+}
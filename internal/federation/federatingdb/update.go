@@ -19,11 +19,13 @@ package federatingdb
 
 import (
 	"context"
+	"errors"
 
 	"codeberg.org/gruf/go-logger/v2/level"
 	"github.com/superseriousbusiness/activity/streams/vocab"
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/log"
@@ -59,6 +61,8 @@ func (f *federatingDB) Update(ctx context.Context, asType vocab.Type) error {
 	switch asType.GetTypeName() {
 	case ap.ActorApplication, ap.ActorGroup, ap.ActorOrganization, ap.ActorPerson, ap.ActorService:
 		return f.updateAccountable(ctx, receivingAccount, requestingAccount, asType)
+	case ap.ObjectNote, ap.ActivityQuestion:
+		return f.updateStatusable(ctx, receivingAccount, requestingAccount, asType)
 	}
 
 	return nil
@@ -103,3 +107,54 @@ func (f *federatingDB) updateAccountable(ctx context.Context, receivingAcct *gts
 
 	return nil
 }
+
+func (f *federatingDB) updateStatusable(ctx context.Context, receivingAcct *gtsmodel.Account, requestingAcct *gtsmodel.Account, asType vocab.Type) error {
+	// Ensure delivered asType is a valid Statusable model.
+	statusable, ok := asType.(ap.Statusable)
+	if !ok {
+		return gtserror.Newf("could not convert vocab.Type %T to Statusable", asType)
+	}
+
+	// Extract AP URI of the updated Statusable model.
+	idProp := statusable.GetJSONLDId()
+	if idProp == nil || !idProp.IsIRI() {
+		return gtserror.New("Statusable id prop was nil or not IRI")
+	}
+	updatedStatusURI := idProp.GetIRI()
+
+	// Don't try to update local statuses, it will break things.
+	if updatedStatusURI.Host == config.GetHost() {
+		return nil
+	}
+
+	// Get the status we have on file for this URI string.
+	status, err := f.state.DB.GetStatusByURI(ctx, updatedStatusURI.String())
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			// We don't know this status,
+			// so there's nothing to update.
+			return nil
+		}
+		return gtserror.Newf("error fetching status from db: %w", err)
+	}
+
+	// Ensure that this status was created by the requesting account.
+	if status.AccountURI != requestingAcct.URI {
+		return gtserror.Newf("update for %s was requested by %s, this is not valid", status.URI, requestingAcct.URI)
+	}
+
+	// Pass in to the processor the existing version of the status
+	// that we have, plus the Statusable representation that was
+	// delivered along with the Update, for further asynchronous
+	// updating of eg., content, attachments, poll counts. The
+	// actual db inserts / updates will take place there.
+	f.state.Workers.EnqueueFederator(ctx, messages.FromFederator{
+		APObjectType:     statusable.GetTypeName(),
+		APActivityType:   ap.ActivityUpdate,
+		GTSModel:         status,
+		APObjectModel:    statusable,
+		ReceivingAccount: receivingAcct,
+	})
+
+	return nil
+}
@@ -67,4 +67,5 @@ type NewSignup struct {
 	EmailVerified bool   // Mark submitted email address as already verified (optional).
 	ExternalID    string // ID of this user in external OIDC system (optional).
 	Admin         bool   // Mark new user as an admin user (optional).
+	InviteID      string // ID of the invite used to sign up (optional).
 }
@@ -0,0 +1,58 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gtsmodel
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// Conversation represents direct messages between the owner account and a set of other accounts.
+type Conversation struct {
+	ID               string     `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                         // id of this item in the database
+	CreatedAt        time.Time  `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                  // when was item created
+	UpdatedAt        time.Time  `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                  // when was item last updated
+	AccountID        string     `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull,unique:conversationthreadaccounts"` // Account that owns the conversation
+	Account          *Account   `validate:"-" bun:"-"`                                                                            // Account corresponding to accountID
+	OtherAccountIDs  []string   `validate:"-" bun:"other_account_ids,array"`                                                      // Other accounts participating in the conversation, sorted
+	OtherAccounts    []*Account `validate:"-" bun:"-"`                                                                            // Other accounts corresponding to otherAccountIDs
+	OtherAccountsKey string     `validate:"-" bun:",notnull,unique:conversationthreadaccounts"`                                   // Deterministic key of otherAccountIDs, see ConversationOtherAccountsKey
+	ThreadID         string     `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull,unique:conversationthreadaccounts"` // ID of the root status of the thread this conversation is in
+	LastStatusID     string     `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                                   // ID of the most recent status in this conversation
+	LastStatus       *Status    `validate:"-" bun:"-"`                                                                            // Status corresponding to lastStatusID
+	Read             *bool      `validate:"-" bun:",default:false"`                                                               // Has the owner read the most recent status in this conversation?
+}
+
+// ConversationOtherAccountsKey returns a deterministic
+// key for the given set of other account IDs, used to
+// group direct statuses into conversations by participants.
+func ConversationOtherAccountsKey(otherAccountIDs []string) string {
+	ids := slices.Clone(otherAccountIDs)
+	slices.Sort(ids)
+	return strings.Join(ids, ",")
+}
+
+// ConversationToStatus links a Conversation to one of its statuses.
+type ConversationToStatus struct {
+	ConversationID string        `validate:"required,ulid" bun:"type:CHAR(26),unique:conversationtostatus,nullzero,notnull"`
+	Conversation   *Conversation `validate:"-" bun:"rel:belongs-to"`
+	StatusID       string        `validate:"required,ulid" bun:"type:CHAR(26),unique:conversationtostatus,nullzero,notnull"`
+	Status         *Status       `validate:"-" bun:"rel:belongs-to"`
+}
@@ -0,0 +1,56 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gtsmodel
+
+import "time"
+
+// Delivery represents one queued delivery of a serialized
+// ActivityStreams object to one remote inbox. Deliveries are
+// persisted before they're first attempted, so that they can
+// be retried with backoff, even across restarts, until they
+// succeed or have been attempted too many times.
+type Delivery struct {
+	ID            string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt     time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt     time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	PubKeyID      string    `validate:"required,url" bun:",nullzero,notnull"`                                // URI of the public key of the local account whose key should be used to sign the delivery.
+	InboxURI      string    `validate:"required,url" bun:",nullzero,notnull"`                                // URI of the remote inbox to deliver to.
+	Domain        string    `validate:"required" bun:",nullzero,notnull"`                                    // Domain (host) of the remote inbox.
+	Data          []byte    `validate:"required" bun:",nullzero,notnull"`                                    // Serialized ActivityStreams object to deliver.
+	Attempts      int       `validate:"-" bun:",notnull,default:0"`                                          // Number of failed attempts so far.
+	NextAttemptAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull"`                           // Don't attempt delivery again before this time.
+	LastError     string    `validate:"-" bun:",nullzero"`                                                   // Error returned by the most recent failed attempt, if any.
+}
+
+// DeliveryFailure represents a remote domain that deliveries
+// have been failing to since FailingSince. Once a domain has
+// failed enough times in a row, deliveries to it are held in
+// the queue without being attempted until RetryAt, so that
+// unreachable peers don't tie up the delivery workers.
+//
+// An entry is removed as soon as a delivery to the domain succeeds.
+type DeliveryFailure struct {
+	ID           string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt    time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt    time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	Domain       string    `validate:"required" bun:",nullzero,notnull,unique"`                             // Domain that deliveries are failing to.
+	FailingSince time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull"`                           // Time of the first failure in the current run of failures.
+	Failures     int       `validate:"-" bun:",notnull,default:0"`                                          // Number of consecutive failed deliveries to the domain.
+	RetryAt      time.Time `validate:"-" bun:"type:timestamptz,nullzero"`                                   // If set, hold deliveries to this domain until this time.
+	LastError    string    `validate:"-" bun:",nullzero"`                                                   // Error returned by the most recent failed delivery.
+}
@@ -0,0 +1,44 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gtsmodel
+
+import (
+	"net"
+	"time"
+)
+
+// DeniedUser is a tombstone left behind when an admin or moderator rejects
+// a sign-up request. The user and account created by the sign-up request are
+// removed, but the username and email address are kept here so that they can't
+// be used again for a new sign-up until config.AccountsRejectionCooldown has passed.
+type DeniedUser struct {
+	ID                     string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt              time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt              time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	Email                  string    `validate:"required" bun:",nullzero,notnull"`                                    // Email address provided on the sign-up form.
+	Username               string    `validate:"required" bun:",nullzero,notnull"`                                    // Username provided on the sign-up form.
+	SignUpIP               net.IP    `validate:"-" bun:",nullzero"`                                                   // From what IP was the sign-up request submitted?
+	Locale                 string    `validate:"-" bun:",nullzero"`                                                   // Locale provided on the sign-up form.
+	CreatedByApplicationID string    `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                         // Which application was used to submit the sign-up request?
+	SignUpReason           string    `validate:"-" bun:",nullzero"`                                                   // Reason provided on the sign-up form.
+	PrivateComment         string    `validate:"-" bun:",nullzero"`                                                   // Comment left by the rejecting admin/moderator, only visible to other admins/moderators.
+	SendEmail              *bool     `validate:"-" bun:",nullzero,notnull,default:false"`                             // Was an email sent to the rejected user to let them know about the rejection?
+	Message                string    `validate:"-" bun:",nullzero"`                                                   // Message included in the rejection email, if any.
+	DeniedByAccountID      string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // ID of the admin/moderator account that rejected the sign-up request.
+	DeniedByAccount        *Account  `validate:"-" bun:"-"`                                                           // Account corresponding to DeniedByAccountID.
+}
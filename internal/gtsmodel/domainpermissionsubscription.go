@@ -0,0 +1,55 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gtsmodel
+
+import "time"
+
+// DomainPermissionSubscription represents a subscription to a remote or
+// local list of domain permissions (blocks or allows), which is fetched
+// and synced periodically. Permissions created through a subscription
+// have their SubscriptionID set to the ID of the subscription.
+type DomainPermissionSubscription struct {
+	ID                    string               `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt             time.Time            `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt             time.Time            `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	Priority              uint8                `validate:"-" bun:""`                                                            // Priority of this subscription compared to others of the same permission type; higher takes precedence.
+	Title                 string               `validate:"-" bun:",nullzero"`                                                   // Moderator-set title for this list.
+	PermissionType        DomainPermissionType `validate:"oneof=block allow" bun:",nullzero,notnull"`                           // Type of permissions created by this subscription.
+	CreatedByAccountID    string               `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // Account ID of the creator of this subscription.
+	CreatedByAccount      *Account             `validate:"-" bun:"-"`                                                           // Account corresponding to createdByAccountID.
+	URI                   string               `validate:"required,url" bun:",nullzero,notnull,unique"`                         // URI of the domain permission list, either http(s) or file.
+	ContentType           string               `validate:"oneof=text/csv application/json text/plain" bun:",nullzero,notnull"`  // Content type to expect from the URI.
+	AdoptOrphans          *bool                `validate:"-" bun:",nullzero,notnull,default:false"`                             // If true, adopt permissions that match the list but were not created by a subscription.
+	FetchedAt             time.Time            `validate:"-" bun:"type:timestamptz,nullzero"`                                   // Time when fetch of URI was last attempted.
+	SuccessfullyFetchedAt time.Time            `validate:"-" bun:"type:timestamptz,nullzero"`                                   // Time when the URI was last successfully fetched.
+	Error                 string               `validate:"-" bun:",nullzero"`                                                   // If the last fetch attempt failed, the error message.
+}
+
+// DomainPermissionType is the type of
+// a domain permission, ie., block or allow.
+type DomainPermissionType string
+
+const (
+	// DomainPermissionBlock denotes
+	// a domain block permission.
+	DomainPermissionBlock DomainPermissionType = "block"
+
+	// DomainPermissionAllow denotes
+	// a domain allow permission.
+	DomainPermissionAllow DomainPermissionType = "allow"
+)
@@ -0,0 +1,98 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gtsmodel
+
+import (
+	"regexp"
+	"time"
+)
+
+// Filter stores a filter created by a local account.
+type Filter struct {
+	ID                   string           `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt            time.Time        `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt            time.Time        `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	ExpiresAt            time.Time        `validate:"-" bun:"type:timestamptz,nullzero"`                                   // Time filter should expire. If null, should not expire.
+	AccountID            string           `validate:"required,ulid" bun:"type:CHAR(26),notnull,nullzero"`                  // ID of the local account that created the filter.
+	Title                string           `validate:"required" bun:",nullzero,notnull"`                                    // The name of the filter.
+	Action               FilterAction     `validate:"oneof=warn hide" bun:",nullzero,notnull"`                             // The action to take.
+	Keywords             []*FilterKeyword `validate:"-" bun:"-"`                                                           // Keywords for this filter.
+	Statuses             []*FilterStatus  `validate:"-" bun:"-"`                                                           // Statuses for this filter.
+	ContextHome          *bool            `validate:"-" bun:",nullzero,notnull,default:false"`                             // Apply filter to home timeline and lists.
+	ContextNotifications *bool            `validate:"-" bun:",nullzero,notnull,default:false"`                             // Apply filter to notifications.
+	ContextPublic        *bool            `validate:"-" bun:",nullzero,notnull,default:false"`                             // Apply filter to local and federated timelines.
+	ContextThread        *bool            `validate:"-" bun:",nullzero,notnull,default:false"`                             // Apply filter when viewing a status's associated thread.
+	ContextAccount       *bool            `validate:"-" bun:",nullzero,notnull,default:false"`                             // Apply filter when viewing an account profile.
+}
+
+// Expired returns whether the filter has expired at the given time.
+// Filters with no expiration timestamp will never expire.
+func (f *Filter) Expired(now time.Time) bool {
+	return !f.ExpiresAt.IsZero() && !f.ExpiresAt.After(now)
+}
+
+// FilterKeyword stores a single keyword to filter statuses against.
+type FilterKeyword struct {
+	ID        string         `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                                     // id of this item in the database
+	CreatedAt time.Time      `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                              // when was item created
+	UpdatedAt time.Time      `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                              // when was item last updated
+	AccountID string         `validate:"required,ulid" bun:"type:CHAR(26),notnull,nullzero"`                                               // ID of the local account that created the filter keyword.
+	FilterID  string         `validate:"required,ulid" bun:"type:CHAR(26),notnull,nullzero,unique:filter_keywords_filter_id_keyword_uniq"` // ID of the filter that this keyword belongs to.
+	Filter    *Filter        `validate:"-" bun:"-"`                                                                                        // Filter corresponding to FilterID
+	Keyword   string         `validate:"required" bun:",notnull,unique:filter_keywords_filter_id_keyword_uniq"`                            // The keyword or phrase to filter against.
+	WholeWord *bool          `validate:"-" bun:",nullzero,notnull,default:false"`                                                          // Should the filter consider word boundaries?
+	Regexp    *regexp.Regexp `validate:"-" bun:"-"`                                                                                        // pre-prepared regular expression
+}
+
+// Compile will compile this FilterKeyword as a prepared regular expression.
+func (k *FilterKeyword) Compile() (err error) {
+	var wordBreak string
+	if k.WholeWord != nil && *k.WholeWord {
+		wordBreak = `\b`
+	}
+
+	// Compile keyword filter regexp.
+	quoted := regexp.QuoteMeta(k.Keyword)
+	k.Regexp, err = regexp.Compile(`(?i)` + wordBreak + quoted + wordBreak)
+	return // caller is expected to wrap this error
+}
+
+// FilterStatus stores a single status to filter.
+type FilterStatus struct {
+	ID        string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                                       // id of this item in the database
+	CreatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                                // when was item created
+	UpdatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                                // when was item last updated
+	AccountID string    `validate:"required,ulid" bun:"type:CHAR(26),notnull,nullzero"`                                                 // ID of the local account that created the filter status.
+	FilterID  string    `validate:"required,ulid" bun:"type:CHAR(26),notnull,nullzero,unique:filter_statuses_filter_id_status_id_uniq"` // ID of the filter that this status belongs to.
+	Filter    *Filter   `validate:"-" bun:"-"`                                                                                          // Filter corresponding to FilterID
+	StatusID  string    `validate:"required,ulid" bun:"type:CHAR(26),notnull,nullzero,unique:filter_statuses_filter_id_status_id_uniq"` // The status to filter.
+}
+
+// FilterAction represents the action to take
+// when a status matches a filter.
+type FilterAction string
+
+const (
+	// FilterActionWarn means that the status should
+	// be shown behind a warning, naming the filter.
+	FilterActionWarn FilterAction = "warn"
+
+	// FilterActionHide means that the status should
+	// be removed from the response entirely.
+	FilterActionHide FilterAction = "hide"
+)
@@ -0,0 +1,46 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gtsmodel
+
+import "time"
+
+// Invite represents an invite link created by a local user,
+// which can be used to sign up to this instance even when
+// registrations are closed.
+type Invite struct {
+	ID         string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt  time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	UpdatedAt  time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item last updated
+	Code       string    `validate:"required" bun:",nullzero,notnull,unique"`                             // Random code used in the invite link.
+	UserID     string    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                  // ID of the user who created the invite.
+	User       *User     `validate:"-" bun:"-"`                                                           // User corresponding to UserID.
+	MaxUses    int       `validate:"min=0" bun:",notnull,default:0"`                                      // How many times can the invite be used? 0 for unlimited.
+	Uses       int       `validate:"min=0" bun:",notnull,default:0"`                                      // How many times has the invite been used?
+	ExpiresAt  time.Time `validate:"-" bun:"type:timestamptz,nullzero"`                                   // When does the invite stop working? Zero for never.
+	Autofollow *bool     `validate:"-" bun:",nullzero,notnull,default:false"`                             // Should accounts signing up with the invite follow the creator?
+}
+
+// Valid returns whether the invite can still be used
+// to sign up at the given time, ie., it hasn't expired
+// and hasn't been used the maximum number of times.
+func (i *Invite) Valid(now time.Time) bool {
+	if !i.ExpiresAt.IsZero() && !now.Before(i.ExpiresAt) {
+		return false
+	}
+	return i.MaxUses == 0 || i.Uses < i.MaxUses
+}
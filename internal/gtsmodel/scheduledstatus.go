@@ -0,0 +1,51 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gtsmodel
+
+import "time"
+
+// ScheduledStatus represents a status that an account has asked to be
+// published at a later time. It holds the parameters the status was
+// submitted with, so that it can be created as normal once it's due.
+type ScheduledStatus struct {
+	ID               string             `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                       // id of this item in the database
+	CreatedAt        time.Time          `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                // when was item created
+	UpdatedAt        time.Time          `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`                // when was item last updated
+	AccountID        string             `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                                 // ID of the account that scheduled the status.
+	Account          *Account           `validate:"-" bun:"-"`                                                                          // Account corresponding to AccountID.
+	ApplicationID    string             `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                                 // ID of the application the status was scheduled with.
+	Application      *Application       `validate:"-" bun:"-"`                                                                          // Application corresponding to ApplicationID.
+	ScheduledAt      time.Time          `validate:"required" bun:"type:timestamptz,nullzero,notnull"`                                   // When should the status be published?
+	Text             string             `validate:"-" bun:""`                                                                           // Text content of the status, as submitted.
+	MediaIDs         []string           `validate:"dive,ulid" bun:"attachments,array"`                                                  // IDs of media attachments to attach to the status.
+	MediaAttachments []*MediaAttachment `validate:"-" bun:"-"`                                                                          // Attachments corresponding to MediaIDs.
+	PollOptions      []string           `validate:"-" bun:",nullzero"`                                                                  // Options of the poll to attach to the status, if any.
+	PollExpiresIn    int                `validate:"-" bun:",nullzero"`                                                                  // Duration in seconds the poll should be open for.
+	PollMultiple     *bool              `validate:"-" bun:",nullzero,notnull,default:false"`                                            // Allow multiple choices on the poll?
+	PollHideTotals   *bool              `validate:"-" bun:",nullzero,notnull,default:false"`                                            // Hide vote counts until the poll ends?
+	InReplyToID      string             `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                                        // ID of the status being replied to, if any.
+	Sensitive        *bool              `validate:"-" bun:",nullzero,notnull,default:false"`                                            // Mark the status and its media as sensitive?
+	SpoilerText      string             `validate:"-" bun:""`                                                                           // Content warning for the status.
+	Visibility       Visibility         `validate:"omitempty,oneof=public unlocked followers_only mutuals_only direct" bun:",nullzero"` // Visibility of the status; the account default if empty.
+	Federated        *bool              `validate:"-" bun:",nullzero"`                                                                  // Advanced visibility flag, if set.
+	Boostable        *bool              `validate:"-" bun:",nullzero"`                                                                  // Advanced visibility flag, if set.
+	Replyable        *bool              `validate:"-" bun:",nullzero"`                                                                  // Advanced visibility flag, if set.
+	Likeable         *bool              `validate:"-" bun:",nullzero"`                                                                  // Advanced visibility flag, if set.
+	Language         string             `validate:"-" bun:",nullzero"`                                                                  // ISO 639 language code of the status; the account default if empty.
+	ContentType      string             `validate:"-" bun:",nullzero"`                                                                  // Content type to parse the text as; the account default if empty.
+}
@@ -66,6 +66,11 @@ type Status struct {
 	Boostable                *bool              `validate:"-" bun:",notnull"`                                                                          // This status can be boosted/reblogged
 	Replyable                *bool              `validate:"-" bun:",notnull"`                                                                          // This status can be replied to
 	Likeable                 *bool              `validate:"-" bun:",notnull"`                                                                          // This status can be liked/faved
+	PollID                   string             `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                                               // ID of the poll attached to this status, if any
+	Poll                     *Poll              `validate:"-" bun:"-"`                                                                                 // poll corresponding to pollID
+	EditIDs                  []string           `validate:"dive,ulid" bun:"edits,array"`                                                               // Database IDs of previous versions of this status, in order of edit
+	Edits                    []*StatusEdit      `validate:"-" bun:"-"`                                                                                 // Previous versions of this status corresponding to editIDs
+	EditedAt                 time.Time          `validate:"-" bun:"type:timestamptz,nullzero"`                                                         // when this status was last edited, if ever
 }
 
 // GetID implements timeline.Timelineable{}.
@@ -128,6 +133,27 @@ func (s *Status) AttachmentsPopulated() bool {
 	return true
 }
 
+// EditsPopulated returns whether edits are populated according to current EditIDs.
+func (s *Status) EditsPopulated() bool {
+	if len(s.EditIDs) != len(s.Edits) {
+		// this is the quickest indicator.
+		return false
+	}
+
+	// Edits must be in same order.
+	for i, id := range s.EditIDs {
+		if s.Edits[i] == nil {
+			log.Warnf(nil, "nil edit in slice for status %s", s.URI)
+			continue
+		}
+		if s.Edits[i].ID != id {
+			return false
+		}
+	}
+
+	return true
+}
+
 // TagsPopulated returns whether tags are populated according to current TagIDs.
 func (s *Status) TagsPopulated() bool {
 	if len(s.TagIDs) != len(s.Tags) {
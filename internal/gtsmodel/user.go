@@ -38,7 +38,7 @@ type User struct {
 	LastSignInAt           time.Time    `validate:"-" bun:"type:timestamptz,nullzero"`                                   // When did this user last sign in?
 	LastSignInIP           net.IP       `validate:"-" bun:",nullzero"`                                                   // What's the previous IP of this user?
 	SignInCount            int          `validate:"min=0" bun:",notnull,default:0"`                                      // How many times has this user signed in?
-	InviteID               string       `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                         // id of the user who invited this user (who let this joker in?)
+	InviteID               string       `validate:"omitempty,ulid" bun:"type:CHAR(26),nullzero"`                         // id of the invite used by this user to sign up (who let this joker in?)
 	ChosenLanguages        []string     `validate:"-" bun:",nullzero"`                                                   // What languages does this user want to see?
 	FilteredLanguages      []string     `validate:"-" bun:",nullzero"`                                                   // What languages does this user not want to see?
 	Locale                 string       `validate:"-" bun:",nullzero"`                                                   // In what timezone/locale is this user located?
@@ -56,4 +56,10 @@ type User struct {
 	ResetPasswordToken     string       `validate:"required_with=ResetPasswordSentAt" bun:",nullzero"`                   // The generated token that the user can use to reset their password
 	ResetPasswordSentAt    time.Time    `validate:"required_with=ResetPasswordToken" bun:"type:timestamptz,nullzero"`    // When did we email the user their reset-password email?
 	ExternalID             string       `validate:"-" bun:",nullzero,unique"`                                            // If the login for the user is managed externally (e.g OIDC), we need to keep a stable reference to the external object (e.g OIDC sub claim)
+	TwoFactorSecret        string       `validate:"-" bun:",nullzero"`                                                   // Encrypted TOTP secret for two-factor authentication, set when the user begins enrolment.
+	TwoFactorEnabledAt     time.Time    `validate:"-" bun:"type:timestamptz,nullzero"`                                   // When did the user enable two-factor authentication? Zero if not enabled.
+	TwoFactorRecoveryCodes []string     `validate:"-" bun:",nullzero"`                                                   // Hashes of the user's unused two-factor recovery codes.
+	TwoFactorLastCounter   int64        `validate:"-" bun:",notnull"`                                                    // Period counter of the last TOTP code accepted for this user, so that codes can't be used twice.
+	TwoFactorAttempts      int          `validate:"-" bun:",notnull"`                                                    // Number of two-factor codes entered since the last correct one, within the lockout window.
+	TwoFactorAttemptedAt   time.Time    `validate:"-" bun:"type:timestamptz,nullzero"`                                   // When was a two-factor code last entered for this user?
 }
@@ -0,0 +1,93 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gtsmodel
+
+import "time"
+
+// WebPushSubscription represents a subscription to Web Push notifications for
+// one oauth token, as created by a client through /api/v1/push/subscription.
+// Each token can have at most one subscription, which is removed along with it.
+type WebPushSubscription struct {
+	ID                 string                    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`                    // id of this item in the database
+	CreatedAt          time.Time                 `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`             // when was item created
+	UpdatedAt          time.Time                 `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"`             // when was item last updated
+	AccountID          string                    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull"`                              // ID of the account that owns this subscription.
+	TokenID            string                    `validate:"required,ulid" bun:"type:CHAR(26),nullzero,notnull,unique"`                       // ID of the token that this subscription was created with.
+	Endpoint           string                    `validate:"required,url" bun:",nullzero,notnull"`                                            // URL of the push service endpoint to deliver notifications to.
+	Auth               string                    `validate:"required" bun:",nullzero,notnull"`                                                // Base64url-encoded auth secret of the user agent, used for payload encryption.
+	P256dh             string                    `validate:"required" bun:",nullzero,notnull"`                                                // Base64url-encoded P-256 ECDH public key of the user agent, used for payload encryption.
+	AlertFollow        *bool                     `validate:"-" bun:",nullzero,notnull,default:false"`                                         // Deliver follow notifications?
+	AlertFollowRequest *bool                     `validate:"-" bun:",nullzero,notnull,default:false"`                                         // Deliver follow request notifications?
+	AlertFavourite     *bool                     `validate:"-" bun:",nullzero,notnull,default:false"`                                         // Deliver favourite notifications?
+	AlertMention       *bool                     `validate:"-" bun:",nullzero,notnull,default:false"`                                         // Deliver mention notifications?
+	AlertReblog        *bool                     `validate:"-" bun:",nullzero,notnull,default:false"`                                         // Deliver reblog notifications?
+	AlertPoll          *bool                     `validate:"-" bun:",nullzero,notnull,default:false"`                                         // Deliver poll notifications?
+	AlertStatus        *bool                     `validate:"-" bun:",nullzero,notnull,default:false"`                                         // Deliver new status notifications?
+	AlertAdminSignup   *bool                     `validate:"-" bun:",nullzero,notnull,default:false"`                                         // Deliver new sign-up notifications (admins/moderators only)?
+	Policy             WebPushNotificationPolicy `validate:"required,oneof=all followed follower none" bun:",nullzero,notnull,default:'all'"` // From whom should notifications be delivered?
+	LastErrorAt        time.Time                 `validate:"-" bun:"type:timestamptz,nullzero"`                                               // When did delivery to the endpoint last fail?
+	FailureCount       int                       `validate:"-" bun:",notnull,default:0"`                                                      // Number of consecutive failed deliveries to the endpoint.
+}
+
+// WebPushNotificationPolicy determines from
+// whom notifications are delivered via Web Push.
+type WebPushNotificationPolicy string
+
+const (
+	WebPushNotificationPolicyAll      WebPushNotificationPolicy = "all"      // Deliver notifications from anyone.
+	WebPushNotificationPolicyFollowed WebPushNotificationPolicy = "followed" // Deliver notifications only from accounts the user follows.
+	WebPushNotificationPolicyFollower WebPushNotificationPolicy = "follower" // Deliver notifications only from accounts following the user.
+	WebPushNotificationPolicyNone     WebPushNotificationPolicy = "none"     // Don't deliver any notifications.
+)
+
+// Alerts returns whether notifications of the
+// given type should be delivered to this subscription.
+func (w *WebPushSubscription) Alerts(notificationType NotificationType) bool {
+	var alert *bool
+
+	switch notificationType {
+	case NotificationFollow:
+		alert = w.AlertFollow
+	case NotificationFollowRequest:
+		alert = w.AlertFollowRequest
+	case NotificationFave:
+		alert = w.AlertFavourite
+	case NotificationMention:
+		alert = w.AlertMention
+	case NotificationReblog:
+		alert = w.AlertReblog
+	case NotificationPoll:
+		alert = w.AlertPoll
+	case NotificationStatus:
+		alert = w.AlertStatus
+	case NotificationSignup:
+		alert = w.AlertAdminSignup
+	}
+
+	return alert != nil && *alert
+}
+
+// VAPIDKeyPair is the instance's P-256 key pair used to sign Voluntary
+// Application Server Identification (VAPID) claims for Web Push deliveries.
+// There's only ever one of these, created the first time it's needed.
+type VAPIDKeyPair struct {
+	ID        string    `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt time.Time `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	Public    string    `validate:"required" bun:",nullzero,notnull"`                                    // Base64url-encoded uncompressed public key, as handed to clients.
+	Private   string    `validate:"required" bun:",nullzero,notnull"`                                    // Base64url-encoded private key scalar.
+}
@@ -0,0 +1,40 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gtsmodel
+
+import "time"
+
+// WorkerMessage represents a message queued for one of the
+// client API or federator worker pools, persisted so that it
+// can be replayed if it hasn't been processed by the time the
+// instance shuts down or crashes. Data holds the serialized
+// message, which references GTS models by their IDs.
+type WorkerMessage struct {
+	ID        string     `validate:"required,ulid" bun:"type:CHAR(26),pk,nullzero,notnull,unique"`        // id of this item in the database
+	CreatedAt time.Time  `validate:"-" bun:"type:timestamptz,nullzero,notnull,default:current_timestamp"` // when was item created
+	Worker    WorkerType `validate:"oneof=client_api federator" bun:",nullzero,notnull"`                  // Worker pool that this message is queued for.
+	Data      []byte     `validate:"required" bun:",nullzero,notnull"`                                    // Serialized message.
+}
+
+// WorkerType denotes a worker pool that messages can be queued for.
+type WorkerType string
+
+const (
+	WorkerTypeClientAPI WorkerType = "client_api" // Client API worker pool.
+	WorkerTypeFederator WorkerType = "federator"  // Federator worker pool.
+)
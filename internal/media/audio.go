@@ -0,0 +1,894 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package media
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/disintegration/imaging"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+)
+
+// id3PictureFrontCover is the picture type of
+// front cover art in ID3 and FLAC picture blocks.
+const id3PictureFrontCover = 3
+
+// maxAudioChunkSize is the maximum size of a tag or
+// metadata chunk that will be read into memory when
+// probing audio, as sizes are declared by the file.
+const maxAudioChunkSize = 32 * 1024 * 1024
+
+// readAudioChunk reads a tag or metadata chunk of the
+// given declared size. The buffer grows only as data
+// is actually read, so a bogus size in a short file
+// cannot force a large allocation.
+func readAudioChunk(r io.Reader, size int64) ([]byte, error) {
+	if size > maxAudioChunkSize {
+		return nil, fmt.Errorf("chunk size %d exceeds maximum", size)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(r, size))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(b)) < size {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return b, nil
+}
+
+type gtsAudio struct {
+	cover    *gtsImage // embedded cover art, nil if none
+	duration float32   // in seconds
+	bitrate  uint64
+}
+
+// audioInfo contains information probed from an audio stream.
+type audioInfo struct {
+	duration  float64 // in seconds
+	bitrate   uint64  // 0 if to be derived from size
+	cover     []byte  // encoded cover art image
+	coverType uint32  // picture type of cover
+}
+
+// addPicture sets the embedded picture of given type
+// as cover art, preferring any front cover found.
+func (info *audioInfo) addPicture(typ uint32, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	if info.cover == nil || (typ == id3PictureFrontCover && info.coverType != id3PictureFrontCover) {
+		info.cover = data
+		info.coverType = typ
+	}
+}
+
+// decodeAudio decodes metadata and any embedded cover art from the given
+// audio stream of content type, where size is the total size of the stream.
+func decodeAudio(r io.Reader, contentType string, size int64) (*gtsAudio, error) {
+	var (
+		br   = bufio.NewReader(r)
+		info *audioInfo
+		err  error
+	)
+
+	switch contentType {
+	case mimeAudioMpeg:
+		info, err = probeMP3(br)
+	case mimeAudioOgg:
+		info, err = probeOgg(br)
+	case mimeAudioFlac:
+		info, err = probeFLAC(br)
+	case mimeAudioWav:
+		info, err = probeWAV(br)
+	default:
+		err = fmt.Errorf("unsupported audio type %s", contentType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if info.duration <= 0 || math.IsInf(info.duration, 0) || math.IsNaN(info.duration) {
+		return nil, errors.New("error determining audio metadata: [duration]")
+	}
+
+	audio := gtsAudio{
+		duration: float32(info.duration),
+		bitrate:  info.bitrate,
+	}
+
+	if audio.bitrate == 0 {
+		// Derive average bitrate
+		// from the overall size.
+		audio.bitrate = uint64(float64(size*8) / info.duration)
+	}
+
+	if info.cover != nil {
+		audio.cover, err = decodeImage(bytes.NewReader(info.cover), imaging.AutoOrientation(true))
+		if err != nil {
+			log.Warnf(nil, "error decoding audio cover art, ignoring: %v", err)
+		}
+	}
+
+	return &audio, nil
+}
+
+// audioPlaceholder generates a placeholder image
+// for audio attachments without cover art.
+func audioPlaceholder() *gtsImage {
+	const (
+		width  = 640
+		height = 360
+		bars   = 40
+		barW   = width / bars
+	)
+
+	img := blankImage(width, height).image.(*image.RGBA)
+
+	// Draw a stylised waveform of bars with
+	// heights from a few overlapping sine waves.
+	fill := &image.Uniform{color.RGBA{140, 141, 150, 255}}
+	for i := 0; i < bars; i++ {
+		x := float64(i) / bars * 2 * math.Pi
+		amp := 0.15 + 0.5*math.Abs(math.Sin(x)*math.Cos(2.5*x)+0.3*math.Sin(7*x))
+		if amp > 0.9 {
+			amp = 0.9
+		}
+
+		h := int(amp * height / 2)
+		rect := image.Rect(i*barW+2, height/2-h, (i+1)*barW-2, height/2+h)
+		draw.Draw(img, rect, fill, image.Point{}, draw.Src)
+	}
+
+	return &gtsImage{image: img}
+}
+
+// mp3 frame header tables, indexed
+// by the header's field values.
+var (
+	// mp3Bitrates in kbps by MPEG-1 (or not), then layer, then index.
+	mp3Bitrates = [2][3][16]int{
+		{ // MPEG-2 and MPEG-2.5
+			{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+			{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+			{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		},
+		{ // MPEG-1
+			{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+			{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+			{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+		},
+	}
+
+	// mp3SampleRates in Hz by version, then index.
+	mp3SampleRates = [4][3]int{
+		{11025, 12000, 8000},  // MPEG-2.5
+		{},                    // reserved
+		{22050, 24000, 16000}, // MPEG-2
+		{44100, 48000, 32000}, // MPEG-1
+	}
+)
+
+// mp3Frame contains information
+// from an mp3 audio frame header.
+type mp3Frame struct {
+	size       int // in bytes, including header
+	samples    int
+	sampleRate int
+	xingOffset int // 0 if not layer III
+}
+
+// parseMP3Frame parses the given 4 byte mp3 frame
+// header, returning false if it isn't a valid header.
+func parseMP3Frame(h []byte) (mp3Frame, bool) {
+	var (
+		version    = (h[1] >> 3) & 3
+		layer      = 4 - int((h[1]>>1)&3) // 1, 2 or 3
+		bitrateIdx = h[2] >> 4
+		rateIdx    = (h[2] >> 2) & 3
+		padding    = int((h[2] >> 1) & 1)
+		mono       = h[3]>>6 == 3
+	)
+
+	if h[0] != 0xFF || h[1]&0xE0 != 0xE0 ||
+		version == 1 || layer == 4 ||
+		bitrateIdx == 0 || bitrateIdx == 15 || // free format unsupported
+		rateIdx == 3 {
+		return mp3Frame{}, false
+	}
+
+	mpeg1 := 0
+	if version == 3 {
+		mpeg1 = 1
+	}
+
+	f := mp3Frame{
+		sampleRate: mp3SampleRates[version][rateIdx],
+	}
+	bitrate := mp3Bitrates[mpeg1][layer-1][bitrateIdx] * 1000
+
+	switch {
+	case layer == 1:
+		f.samples = 384
+		f.size = (12*bitrate/f.sampleRate + padding) * 4
+	case layer == 2 || mpeg1 == 1:
+		f.samples = 1152
+		f.size = 144*bitrate/f.sampleRate + padding
+	default:
+		f.samples = 576
+		f.size = 72*bitrate/f.sampleRate + padding
+	}
+
+	if layer == 3 {
+		// Offset of a Xing / Info
+		// header, after side info.
+		switch {
+		case mpeg1 == 1 && !mono:
+			f.xingOffset = 36
+		case mpeg1 == 1 || !mono:
+			f.xingOffset = 21
+		default:
+			f.xingOffset = 13
+		}
+	}
+
+	return f, true
+}
+
+// probeMP3 probes mp3 audio, reading cover art from any
+// ID3v2 tags, and determining the duration and bitrate
+// by scanning the headers of all audio frames.
+func probeMP3(br *bufio.Reader) (*audioInfo, error) {
+	info := new(audioInfo)
+
+	// Parse any ID3v2 tags.
+	for {
+		hdr, err := br.Peek(10)
+		if err != nil || string(hdr[:3]) != "ID3" {
+			break
+		}
+
+		size := 10 + int(id3SyncSafe(hdr[6:10]))
+		if hdr[5]&0x10 != 0 {
+			// Footer present.
+			size += 10
+		}
+
+		tag, err := readAudioChunk(br, int64(size))
+		if err != nil {
+			return nil, fmt.Errorf("error reading id3 tag: %w", err)
+		}
+
+		parseID3(tag, info)
+	}
+
+	var (
+		header  uint32 // constant header fields of first frame
+		rate    int
+		samples uint64
+		size    uint64
+	)
+
+	for {
+		h, err := br.Peek(4)
+		if err != nil {
+			// End of stream.
+			break
+		}
+
+		if string(h[:3]) == "TAG" {
+			// ID3v1 tag
+			// at the end.
+			break
+		}
+
+		f, ok := parseMP3Frame(h)
+
+		// Fields that must not change between frames:
+		// version, layer and sample rate index.
+		fields := uint32(h[1]&0xFE)<<8 | uint32(h[2]&0x0C)
+
+		if !ok || (rate != 0 && fields != header) {
+			// Skip junk until the next
+			// frame header is found.
+			if _, err := br.Discard(1); err != nil {
+				break
+			}
+			continue
+		}
+
+		if rate == 0 {
+			header = fields
+			rate = f.sampleRate
+
+			// The first frame may be a Xing / Info (or
+			// VBRI) header frame, containing no audio.
+			if b, err := br.Peek(40); err == nil && f.xingOffset > 0 {
+				if tag := string(b[f.xingOffset : f.xingOffset+4]); tag == "Xing" || tag == "Info" || string(b[36:40]) == "VBRI" {
+					if _, err := br.Discard(f.size); err != nil {
+						break
+					}
+					continue
+				}
+			}
+		}
+
+		if _, err := br.Discard(f.size); err != nil {
+			// Truncated
+			// last frame.
+			break
+		}
+
+		samples += uint64(f.samples)
+		size += uint64(f.size)
+	}
+
+	if rate == 0 || samples == 0 {
+		return nil, errors.New("no mpeg audio frames found")
+	}
+
+	info.duration = float64(samples) / float64(rate)
+	info.bitrate = uint64(float64(size*8) / info.duration)
+	return info, nil
+}
+
+// id3SyncSafe decodes a 4 byte ID3v2 "sync safe" integer.
+func id3SyncSafe(b []byte) uint32 {
+	return uint32(b[0]&0x7f)<<21 | uint32(b[1]&0x7f)<<14 | uint32(b[2]&0x7f)<<7 | uint32(b[3]&0x7f)
+}
+
+// id3Unsync reverses ID3v2 unsynchronisation, removing the
+// zero bytes inserted after each 0xFF byte in the given data.
+func id3Unsync(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	for i := 0; i < len(b); i++ {
+		out = append(out, b[i])
+		if b[i] == 0xFF && i+1 < len(b) && b[i+1] == 0x00 {
+			i++
+		}
+	}
+	return out
+}
+
+// parseID3 parses an ID3v2 tag (including its
+// header), adding any attached pictures to info.
+func parseID3(tag []byte, info *audioInfo) {
+	if len(tag) < 10 {
+		return
+	}
+
+	version, flags := tag[3], tag[5]
+	size := int(id3SyncSafe(tag[6:10]))
+	if size > len(tag)-10 {
+		// Declared size runs
+		// past the given data.
+		return
+	}
+	data := tag[10 : 10+size]
+
+	if flags&0x80 != 0 && version < 4 {
+		// Whole tag is unsynchronised.
+		data = id3Unsync(data)
+	}
+
+	if flags&0x40 != 0 && len(data) >= 4 {
+		// Skip extended header.
+		var size int
+		if version == 3 {
+			size = 4 + int(binary.BigEndian.Uint32(data))
+		} else {
+			size = int(id3SyncSafe(data))
+		}
+		if size > len(data) {
+			return
+		}
+		data = data[size:]
+	}
+
+	// Frame header sizes.
+	idLen, hdrLen := 4, 10
+	if version == 2 {
+		idLen, hdrLen = 3, 6
+	}
+
+	for len(data) >= hdrLen && data[0] != 0 {
+		id := string(data[:idLen])
+
+		var size int
+		switch version {
+		case 2:
+			size = int(data[3])<<16 | int(data[4])<<8 | int(data[5])
+		case 3:
+			size = int(binary.BigEndian.Uint32(data[4:]))
+		default:
+			size = int(id3SyncSafe(data[4:]))
+		}
+
+		if size < 0 || size > len(data)-hdrLen {
+			return
+		}
+
+		var fmtFlags byte
+		if version > 2 {
+			fmtFlags = data[9]
+		}
+
+		frame := data[hdrLen : hdrLen+size]
+		data = data[hdrLen+size:]
+
+		if id != "APIC" && id != "PIC" {
+			continue
+		}
+
+		// Handle frame format flags.
+		switch version {
+		case 3:
+			if fmtFlags&0xC0 != 0 {
+				// Compressed or
+				// encrypted.
+				continue
+			}
+			if fmtFlags&0x20 != 0 && len(frame) > 0 {
+				// Group ID.
+				frame = frame[1:]
+			}
+		case 4:
+			if fmtFlags&0x0C != 0 {
+				// Compressed or
+				// encrypted.
+				continue
+			}
+			if fmtFlags&0x40 != 0 && len(frame) > 0 {
+				// Group ID.
+				frame = frame[1:]
+			}
+			if fmtFlags&0x01 != 0 && len(frame) >= 4 {
+				// Data length indicator.
+				frame = frame[4:]
+			}
+			if fmtFlags&0x02 != 0 || flags&0x80 != 0 {
+				frame = id3Unsync(frame)
+			}
+		}
+
+		info.addPicture(parseID3Picture(frame, version))
+	}
+}
+
+// parseID3Picture parses the given APIC (or ID3v2.2 PIC) frame
+// data, returning the picture type and picture data, or nil
+// data if it's invalid.
+func parseID3Picture(b []byte, version byte) (uint32, []byte) {
+	if len(b) < 1 {
+		return 0, nil
+	}
+	enc := b[0]
+	b = b[1:]
+
+	// Skip image format (3 chars
+	// in 2.2, else a MIME type).
+	if version == 2 {
+		if len(b) < 3 {
+			return 0, nil
+		}
+		b = b[3:]
+	} else {
+		i := bytes.IndexByte(b, 0)
+		if i < 0 {
+			return 0, nil
+		}
+		b = b[i+1:]
+	}
+
+	if len(b) < 1 {
+		return 0, nil
+	}
+	typ := uint32(b[0])
+	b = b[1:]
+
+	// Skip description, terminated by a
+	// null character of given encoding.
+	if enc == 1 || enc == 2 {
+		// UTF-16.
+		for i := 0; ; i += 2 {
+			if i+1 >= len(b) {
+				return 0, nil
+			}
+			if b[i] == 0 && b[i+1] == 0 {
+				b = b[i+2:]
+				break
+			}
+		}
+	} else {
+		i := bytes.IndexByte(b, 0)
+		if i < 0 {
+			return 0, nil
+		}
+		b = b[i+1:]
+	}
+
+	return typ, b
+}
+
+// probeOgg probes Ogg audio, reading the codec and cover art
+// from the first logical stream's header packets, and the
+// duration from the granule position of its last page.
+func probeOgg(br *bufio.Reader) (*audioInfo, error) {
+	var (
+		hdr     [27]byte
+		lacing  [255]byte
+		serial  uint32
+		pages   int
+		packets [][]byte // first two packets
+		partial []byte   // incomplete packet
+		granule int64
+	)
+
+	for ; ; pages++ {
+		if _, err := io.ReadFull(br, hdr[:]); err != nil {
+			if err == io.EOF && pages > 0 {
+				break
+			}
+			return nil, fmt.Errorf("error reading ogg page: %w", err)
+		}
+
+		if string(hdr[:4]) != "OggS" || hdr[4] != 0 {
+			return nil, errors.New("invalid ogg page")
+		}
+
+		nsegs := int(hdr[26])
+		if _, err := io.ReadFull(br, lacing[:nsegs]); err != nil {
+			return nil, fmt.Errorf("error reading ogg page: %w", err)
+		}
+
+		var size int
+		for _, l := range lacing[:nsegs] {
+			size += int(l)
+		}
+
+		s := binary.LittleEndian.Uint32(hdr[14:18])
+		if pages == 0 {
+			// The first page begins
+			// the stream of interest.
+			serial = s
+		}
+
+		if s == serial {
+			// -1 indicates no packet
+			// finishes on this page.
+			if g := int64(binary.LittleEndian.Uint64(hdr[6:14])); g != -1 {
+				granule = g
+			}
+		}
+
+		if s != serial || len(packets) == 2 {
+			if _, err := br.Discard(size); err != nil {
+				return nil, fmt.Errorf("error reading ogg page: %w", err)
+			}
+			continue
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(br, data); err != nil {
+			return nil, fmt.Errorf("error reading ogg page: %w", err)
+		}
+
+		// Reassemble header packets from the
+		// segments, which finish a packet when
+		// less than the maximum segment size.
+		for _, l := range lacing[:nsegs] {
+			partial = append(partial, data[:l]...)
+			data = data[l:]
+			if len(partial) > maxAudioChunkSize {
+				return nil, errors.New("ogg header packet too large")
+			}
+			if l < 255 && len(packets) < 2 {
+				packets = append(packets, partial)
+				partial = nil
+			}
+		}
+	}
+
+	if len(packets) < 2 {
+		return nil, errors.New("missing ogg header packets")
+	}
+
+	var (
+		info     = new(audioInfo)
+		ident    = packets[0]
+		comments = packets[1]
+		rate     float64
+		preSkip  int64
+	)
+
+	switch {
+	case len(ident) >= 19 && string(ident[:8]) == "OpusHead":
+		// Opus granule positions are always
+		// at 48kHz, including pre-skip samples.
+		rate = 48000
+		preSkip = int64(binary.LittleEndian.Uint16(ident[10:12]))
+		if len(comments) >= 8 && string(comments[:8]) == "OpusTags" {
+			parseVorbisComment(comments[8:], info)
+		}
+
+	case len(ident) >= 16 && string(ident[:7]) == "\x01vorbis":
+		rate = float64(binary.LittleEndian.Uint32(ident[12:16]))
+		if len(comments) >= 7 && string(comments[:7]) == "\x03vorbis" {
+			parseVorbisComment(comments[7:], info)
+		}
+
+	case len(ident) >= 17+flacStreamInfoSize && string(ident[:5]) == "\x7fFLAC":
+		// Mapping header, "fLaC",
+		// STREAMINFO block header.
+		r, _ := parseFLACStreamInfo(ident[17:])
+		rate = float64(r)
+		if len(comments) >= 4 {
+			parseVorbisComment(comments[4:], info)
+		}
+
+	default:
+		return nil, errors.New("unsupported ogg codec")
+	}
+
+	if rate > 0 {
+		info.duration = float64(granule-preSkip) / rate
+	}
+
+	return info, nil
+}
+
+// parseVorbisComment parses a Vorbis comment
+// header, adding any cover art pictures to info.
+func parseVorbisComment(b []byte, info *audioInfo) {
+	// next returns the next length
+	// prefixed string (or count).
+	next := func() ([]byte, bool) {
+		if len(b) < 4 {
+			return nil, false
+		}
+		n := binary.LittleEndian.Uint32(b)
+		b = b[4:]
+		if uint64(n) > uint64(len(b)) {
+			return nil, false
+		}
+		s := b[:n]
+		b = b[n:]
+		return s, true
+	}
+
+	// Skip vendor string.
+	if _, ok := next(); !ok {
+		return
+	}
+
+	if len(b) < 4 {
+		return
+	}
+	count := binary.LittleEndian.Uint32(b)
+	b = b[4:]
+
+	const key = "METADATA_BLOCK_PICTURE="
+	for i := uint32(0); i < count; i++ {
+		c, ok := next()
+		if !ok {
+			return
+		}
+
+		if len(c) < len(key) || !strings.EqualFold(string(c[:len(key)]), key) {
+			continue
+		}
+
+		block, err := base64.StdEncoding.DecodeString(string(c[len(key):]))
+		if err != nil {
+			continue
+		}
+
+		info.addPicture(parseFLACPicture(block))
+	}
+}
+
+// flacStreamInfoSize is the size of a FLAC STREAMINFO block.
+const flacStreamInfoSize = 34
+
+// parseFLACStreamInfo parses the sample rate and total
+// samples from the given FLAC STREAMINFO block data.
+func parseFLACStreamInfo(b []byte) (uint32, uint64) {
+	// 20 bits sample rate, 3 bits channels,
+	// 5 bits bits per sample, 36 bits samples.
+	v := binary.BigEndian.Uint64(b[10:18])
+	return uint32(v >> 44), v & (1<<36 - 1)
+}
+
+// parseFLACPicture parses the given FLAC PICTURE block
+// data, returning the picture type and picture data, or
+// nil data if it's invalid.
+func parseFLACPicture(b []byte) (uint32, []byte) {
+	u32 := func() (uint32, bool) {
+		if len(b) < 4 {
+			return 0, false
+		}
+		v := binary.BigEndian.Uint32(b)
+		b = b[4:]
+		return v, true
+	}
+	skip := func() bool {
+		n, ok := u32()
+		if !ok || uint64(n) > uint64(len(b)) {
+			return false
+		}
+		b = b[n:]
+		return true
+	}
+
+	typ, ok := u32()
+	if !ok || !skip() || !skip() { // MIME type, description
+		return 0, nil
+	}
+
+	// Width, height, depth, colours.
+	if len(b) < 16 {
+		return 0, nil
+	}
+	b = b[16:]
+
+	n, ok := u32()
+	if !ok || uint64(n) > uint64(len(b)) {
+		return 0, nil
+	}
+
+	return typ, b[:n]
+}
+
+// probeFLAC probes FLAC audio, reading the duration from the
+// STREAMINFO block and cover art from any picture blocks.
+func probeFLAC(br *bufio.Reader) (*audioInfo, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(br, magic[:]); err != nil || string(magic[:]) != "fLaC" {
+		return nil, errors.New("invalid flac header")
+	}
+
+	var (
+		info    = new(audioInfo)
+		hdr     [4]byte
+		rate    uint32
+		samples uint64
+	)
+
+	for last := false; !last; {
+		if _, err := io.ReadFull(br, hdr[:]); err != nil {
+			return nil, fmt.Errorf("error reading flac metadata: %w", err)
+		}
+
+		last = hdr[0]&0x80 != 0
+		typ := hdr[0] & 0x7f
+		size := int(hdr[1])<<16 | int(hdr[2])<<8 | int(hdr[3])
+
+		switch typ {
+		case 0, 4, 6: // STREAMINFO, VORBIS_COMMENT, PICTURE
+			b, err := readAudioChunk(br, int64(size))
+			if err != nil {
+				return nil, fmt.Errorf("error reading flac metadata: %w", err)
+			}
+
+			switch typ {
+			case 0:
+				if size < flacStreamInfoSize {
+					return nil, errors.New("invalid flac streaminfo")
+				}
+				rate, samples = parseFLACStreamInfo(b)
+			case 4:
+				parseVorbisComment(b, info)
+			case 6:
+				info.addPicture(parseFLACPicture(b))
+			}
+
+		default:
+			if _, err := br.Discard(size); err != nil {
+				return nil, fmt.Errorf("error reading flac metadata: %w", err)
+			}
+		}
+	}
+
+	if rate > 0 {
+		info.duration = float64(samples) / float64(rate)
+	}
+
+	return info, nil
+}
+
+// probeWAV probes WAV audio, reading the duration and bitrate
+// from its format and data chunks, and cover art from any ID3
+// chunk.
+func probeWAV(br *bufio.Reader) (*audioInfo, error) {
+	var hdr [12]byte
+	if _, err := io.ReadFull(br, hdr[:]); err != nil || string(hdr[:4]) != "RIFF" || string(hdr[8:]) != "WAVE" {
+		return nil, errors.New("invalid wav header")
+	}
+
+	var (
+		info     = new(audioInfo)
+		chunk    [8]byte
+		byteRate uint32
+		dataSize uint64
+	)
+
+	for {
+		if _, err := io.ReadFull(br, chunk[:]); err != nil {
+			// End of stream.
+			break
+		}
+
+		id := string(chunk[:4])
+		size := binary.LittleEndian.Uint32(chunk[4:])
+
+		switch id {
+		case "fmt ", "id3 ", "ID3 ":
+			b, err := readAudioChunk(br, int64(size))
+			if err != nil {
+				return nil, fmt.Errorf("error reading wav chunk: %w", err)
+			}
+
+			if id == "fmt " {
+				if size < 16 {
+					return nil, errors.New("invalid wav format chunk")
+				}
+				byteRate = binary.LittleEndian.Uint32(b[8:12])
+			} else {
+				parseID3(b, info)
+			}
+
+		case "data":
+			// Size may be unknown (streamed)
+			// so count the bytes of data.
+			n, _ := br.Discard(int(size))
+			dataSize += uint64(n)
+
+		default:
+			if _, err := br.Discard(int(size)); err != nil {
+				return nil, fmt.Errorf("error reading wav chunk: %w", err)
+			}
+		}
+
+		if size&1 == 1 {
+			// Chunks are
+			// word aligned.
+			_, _ = br.Discard(1)
+		}
+	}
+
+	if byteRate == 0 {
+		return nil, errors.New("missing wav format")
+	}
+
+	info.duration = float64(dataSize) / float64(byteRate)
+	info.bitrate = uint64(byteRate) * 8
+	return info, nil
+}
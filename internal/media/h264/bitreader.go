@@ -0,0 +1,137 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package h264
+
+import "errors"
+
+// errTruncated is returned when a read runs past
+// the end of the available bitstream data.
+var errTruncated = errors.New("h264: truncated bitstream")
+
+// bitReader reads fixed and variable length
+// codes from an RBSP (raw byte sequence payload).
+type bitReader struct {
+	buf []byte
+	pos int // current position in bits
+	end int // position of rbsp_stop_one_bit, or -1 if not yet found
+	err error
+}
+
+// newBitReader returns a bitReader for the given RBSP.
+func newBitReader(rbsp []byte) *bitReader {
+	return &bitReader{buf: rbsp, end: -1}
+}
+
+// unescapeRBSP removes emulation prevention bytes from
+// a NAL unit payload, returning the underlying RBSP.
+func unescapeRBSP(nal []byte) []byte {
+	rbsp := make([]byte, 0, len(nal))
+	zeros := 0
+	for _, b := range nal {
+		if zeros >= 2 && b == 0x03 {
+			// Skip emulation_prevention_three_byte.
+			zeros = 0
+			continue
+		}
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+		rbsp = append(rbsp, b)
+	}
+	return rbsp
+}
+
+// u1 reads a single bit.
+func (r *bitReader) u1() uint32 {
+	if r.pos >= len(r.buf)*8 {
+		r.err = errTruncated
+		return 0
+	}
+	b := r.buf[r.pos>>3] >> (7 - uint(r.pos&7)) & 1
+	r.pos++
+	return uint32(b)
+}
+
+// u reads an n bit unsigned integer, where n <= 32.
+func (r *bitReader) u(n int) uint32 {
+	var v uint32
+	for i := 0; i < n; i++ {
+		v = v<<1 | r.u1()
+	}
+	return v
+}
+
+// flag reads a single bit as a bool.
+func (r *bitReader) flag() bool {
+	return r.u1() == 1
+}
+
+// ue reads an unsigned Exp-Golomb code.
+func (r *bitReader) ue() uint32 {
+	zeros := 0
+	for r.u1() == 0 {
+		if r.err != nil || zeros == 32 {
+			r.err = errors.New("h264: invalid exp-golomb code")
+			return 0
+		}
+		zeros++
+	}
+	return (1<<uint(zeros) - 1) + r.u(zeros)
+}
+
+// se reads a signed Exp-Golomb code.
+func (r *bitReader) se() int32 {
+	k := r.ue()
+	if k&1 == 1 {
+		return int32((k + 1) >> 1)
+	}
+	return -int32(k >> 1)
+}
+
+// aligned returns whether reader is at a byte boundary.
+func (r *bitReader) aligned() bool {
+	return r.pos&7 == 0
+}
+
+// align skips to the next byte boundary.
+func (r *bitReader) align() {
+	r.pos = (r.pos + 7) &^ 7
+}
+
+// moreRBSPData returns whether there is any more data
+// before the rbsp_trailing_bits at the end of the RBSP.
+func (r *bitReader) moreRBSPData() bool {
+	if r.end < 0 {
+		r.end = 0
+		for i := len(r.buf) - 1; i >= 0; i-- {
+			if b := r.buf[i]; b != 0 {
+				// Find the last set bit, this is the stop bit.
+				bit := 7
+				for b&1 == 0 {
+					b >>= 1
+					bit--
+				}
+				r.end = i*8 + bit
+				break
+			}
+		}
+	}
+	return r.pos < r.end
+}
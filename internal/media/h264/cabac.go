@@ -0,0 +1,627 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package h264
+
+import "fmt"
+
+var (
+	// rangeTabLPS holds codIRangeLPS values by
+	// pStateIdx and qCodIRangeIdx (Table 9-44).
+	rangeTabLPS = [64][4]uint8{
+		{128, 176, 208, 240}, {128, 167, 197, 227}, {128, 158, 187, 216}, {123, 150, 178, 205},
+		{116, 142, 169, 195}, {111, 135, 160, 185}, {105, 128, 152, 175}, {100, 122, 144, 166},
+		{95, 116, 137, 158}, {90, 110, 130, 150}, {85, 104, 123, 142}, {81, 99, 117, 135},
+		{77, 94, 111, 128}, {73, 89, 105, 122}, {69, 85, 100, 116}, {66, 80, 95, 110},
+		{62, 76, 90, 104}, {59, 72, 86, 99}, {56, 69, 81, 94}, {53, 65, 77, 89},
+		{51, 62, 73, 85}, {48, 59, 69, 80}, {46, 56, 66, 76}, {43, 53, 63, 72},
+		{41, 50, 59, 69}, {39, 48, 56, 65}, {37, 45, 54, 62}, {35, 43, 51, 59},
+		{33, 41, 48, 56}, {32, 39, 46, 53}, {30, 37, 43, 50}, {29, 35, 41, 48},
+		{27, 33, 39, 45}, {26, 31, 37, 43}, {24, 30, 35, 41}, {23, 28, 33, 39},
+		{22, 27, 32, 37}, {21, 26, 30, 35}, {20, 24, 29, 33}, {19, 23, 27, 31},
+		{18, 22, 26, 30}, {17, 21, 25, 28}, {16, 20, 23, 27}, {15, 19, 22, 25},
+		{14, 18, 21, 24}, {14, 17, 20, 23}, {13, 16, 19, 22}, {12, 15, 18, 21},
+		{12, 14, 17, 20}, {11, 14, 16, 19}, {11, 13, 15, 18}, {10, 12, 15, 17},
+		{10, 12, 14, 16}, {9, 11, 13, 15}, {9, 11, 12, 14}, {8, 10, 12, 14},
+		{8, 9, 11, 13}, {7, 9, 11, 12}, {7, 9, 10, 12}, {7, 8, 10, 11},
+		{6, 8, 9, 11}, {6, 7, 9, 10}, {6, 7, 8, 9}, {2, 2, 2, 2},
+	}
+
+	// transIdxLPS holds the state transitions after
+	// decoding the least probable symbol (Table 9-45).
+	transIdxLPS = [64]uint8{
+		0, 0, 1, 2, 2, 4, 4, 5, 6, 7, 8, 9, 9, 11, 11, 12,
+		13, 13, 15, 15, 16, 16, 18, 18, 19, 19, 21, 21, 22, 22, 23, 24,
+		24, 25, 26, 26, 27, 27, 28, 29, 29, 30, 30, 30, 31, 32, 32, 33,
+		33, 33, 34, 34, 35, 35, 35, 36, 36, 36, 37, 37, 37, 38, 38, 63,
+	}
+
+	// cabacInitI holds the context variable initialisation values (m, n)
+	// of I slices, from ctxIdx 0 (Tables 9-12 to 9-33). Context variables
+	// for syntax elements that don't appear in I slices, or are only used
+	// for field coding or 4:4:4 chroma, are zero and left uninitialised.
+	cabacInitI = func() (t [436][2]int8) {
+		set := func(start int, vals ...[2]int8) {
+			copy(t[start:], vals)
+		}
+
+		// mb_type (SI prefix, I).
+		set(0,
+			[2]int8{20, -15}, [2]int8{2, 54}, [2]int8{3, 74}, [2]int8{20, -15},
+			[2]int8{2, 54}, [2]int8{3, 74}, [2]int8{-28, 127}, [2]int8{-23, 104},
+			[2]int8{-6, 53}, [2]int8{-1, 54}, [2]int8{7, 51},
+		)
+
+		// mb_qp_delta, intra_chroma_pred_mode,
+		// prev_intra*_pred_mode_flag, rem_intra*_pred_mode.
+		set(60,
+			[2]int8{0, 41}, [2]int8{0, 63}, [2]int8{0, 63}, [2]int8{0, 63},
+			[2]int8{-9, 83}, [2]int8{4, 86}, [2]int8{0, 97}, [2]int8{-7, 72},
+			[2]int8{13, 41}, [2]int8{3, 62},
+		)
+
+		// mb_field_decoding_flag, coded_block_pattern, coded_block_flag.
+		set(70,
+			[2]int8{0, 11}, [2]int8{1, 55}, [2]int8{0, 69}, [2]int8{-17, 127},
+			[2]int8{-13, 102}, [2]int8{0, 82}, [2]int8{-7, 74}, [2]int8{-21, 107},
+			[2]int8{-27, 127}, [2]int8{-31, 127}, [2]int8{-24, 127}, [2]int8{-18, 95},
+			[2]int8{-27, 127}, [2]int8{-21, 114}, [2]int8{-30, 127}, [2]int8{-17, 123},
+			[2]int8{-12, 115}, [2]int8{-16, 122}, [2]int8{-11, 115}, [2]int8{-12, 63},
+			[2]int8{-2, 68}, [2]int8{-15, 84}, [2]int8{-13, 104}, [2]int8{-3, 70},
+			[2]int8{-8, 93}, [2]int8{-10, 90}, [2]int8{-30, 127}, [2]int8{-1, 74},
+			[2]int8{-6, 97}, [2]int8{-7, 91}, [2]int8{-20, 127}, [2]int8{-4, 56},
+			[2]int8{-5, 82}, [2]int8{-7, 76}, [2]int8{-22, 125},
+		)
+
+		// significant_coeff_flag (frame coded).
+		set(105,
+			[2]int8{-7, 93}, [2]int8{-11, 87}, [2]int8{-3, 77}, [2]int8{-5, 71},
+			[2]int8{-4, 63}, [2]int8{-4, 68}, [2]int8{-12, 84}, [2]int8{-7, 62},
+			[2]int8{-7, 65}, [2]int8{8, 61}, [2]int8{5, 56}, [2]int8{-2, 66},
+			[2]int8{1, 64}, [2]int8{0, 61}, [2]int8{-2, 78}, [2]int8{1, 50},
+			[2]int8{7, 52}, [2]int8{10, 35}, [2]int8{0, 44}, [2]int8{11, 38},
+			[2]int8{1, 45}, [2]int8{0, 46}, [2]int8{5, 44}, [2]int8{31, 17},
+			[2]int8{1, 51}, [2]int8{7, 50}, [2]int8{28, 19}, [2]int8{16, 33},
+			[2]int8{14, 62}, [2]int8{-13, 108}, [2]int8{-15, 100}, [2]int8{-13, 101},
+			[2]int8{-13, 91}, [2]int8{-12, 94}, [2]int8{-10, 88}, [2]int8{-16, 84},
+			[2]int8{-10, 86}, [2]int8{-7, 83}, [2]int8{-13, 87}, [2]int8{-19, 94},
+			[2]int8{1, 70}, [2]int8{0, 72}, [2]int8{-5, 74}, [2]int8{18, 59},
+			[2]int8{-8, 102}, [2]int8{-15, 100}, [2]int8{0, 95}, [2]int8{-4, 75},
+			[2]int8{2, 72}, [2]int8{-11, 75}, [2]int8{-3, 71}, [2]int8{15, 46},
+			[2]int8{-13, 69}, [2]int8{0, 62}, [2]int8{0, 65}, [2]int8{21, 37},
+			[2]int8{-15, 72}, [2]int8{9, 57}, [2]int8{16, 54}, [2]int8{0, 62},
+			[2]int8{12, 72},
+		)
+
+		// last_significant_coeff_flag (frame coded).
+		set(166,
+			[2]int8{24, 0}, [2]int8{15, 9}, [2]int8{8, 25}, [2]int8{13, 18},
+			[2]int8{15, 9}, [2]int8{13, 19}, [2]int8{10, 37}, [2]int8{12, 18},
+			[2]int8{6, 29}, [2]int8{20, 33}, [2]int8{15, 30}, [2]int8{4, 45},
+			[2]int8{1, 58}, [2]int8{0, 62}, [2]int8{7, 61}, [2]int8{12, 38},
+			[2]int8{11, 45}, [2]int8{15, 39}, [2]int8{11, 42}, [2]int8{13, 44},
+			[2]int8{16, 45}, [2]int8{12, 41}, [2]int8{10, 49}, [2]int8{30, 34},
+			[2]int8{18, 42}, [2]int8{10, 55}, [2]int8{17, 51}, [2]int8{17, 46},
+			[2]int8{0, 89}, [2]int8{26, -19}, [2]int8{22, -17}, [2]int8{26, -17},
+			[2]int8{30, -25}, [2]int8{28, -20}, [2]int8{33, -23}, [2]int8{37, -27},
+			[2]int8{33, -23}, [2]int8{40, -28}, [2]int8{38, -17}, [2]int8{33, -11},
+			[2]int8{40, -15}, [2]int8{41, -6}, [2]int8{38, 1}, [2]int8{41, 17},
+			[2]int8{30, -6}, [2]int8{27, 3}, [2]int8{26, 22}, [2]int8{37, -16},
+			[2]int8{35, -4}, [2]int8{38, -8}, [2]int8{38, -3}, [2]int8{37, 3},
+			[2]int8{38, 5}, [2]int8{42, 0}, [2]int8{35, 16}, [2]int8{39, 22},
+			[2]int8{14, 48}, [2]int8{27, 37}, [2]int8{21, 60}, [2]int8{12, 68},
+			[2]int8{2, 97},
+		)
+
+		// coeff_abs_level_minus1.
+		set(227,
+			[2]int8{-3, 71}, [2]int8{-6, 42}, [2]int8{-5, 50}, [2]int8{-3, 54},
+			[2]int8{-2, 62}, [2]int8{0, 58}, [2]int8{1, 63}, [2]int8{-2, 72},
+			[2]int8{-1, 74}, [2]int8{-9, 91}, [2]int8{-5, 67}, [2]int8{-5, 27},
+			[2]int8{-3, 39}, [2]int8{-2, 44}, [2]int8{0, 46}, [2]int8{-16, 64},
+			[2]int8{-8, 68}, [2]int8{-10, 78}, [2]int8{-6, 77}, [2]int8{-10, 86},
+			[2]int8{-12, 92}, [2]int8{-15, 55}, [2]int8{-10, 60}, [2]int8{-6, 62},
+			[2]int8{-4, 65}, [2]int8{-12, 73}, [2]int8{-8, 76}, [2]int8{-7, 80},
+			[2]int8{-9, 88}, [2]int8{-17, 110}, [2]int8{-11, 97}, [2]int8{-20, 84},
+			[2]int8{-11, 79}, [2]int8{-6, 73}, [2]int8{-4, 74}, [2]int8{-13, 86},
+			[2]int8{-13, 96}, [2]int8{-11, 97}, [2]int8{-19, 117}, [2]int8{-8, 78},
+			[2]int8{-5, 33}, [2]int8{-4, 48}, [2]int8{-2, 53}, [2]int8{-3, 62},
+			[2]int8{-13, 71}, [2]int8{-10, 79}, [2]int8{-12, 86}, [2]int8{-13, 90},
+			[2]int8{-14, 97},
+		)
+
+		// transform_size_8x8_flag, and significant_coeff_flag,
+		// last_significant_coeff_flag and coeff_abs_level_minus1
+		// for 8x8 blocks (frame coded).
+		set(399,
+			[2]int8{31, 21}, [2]int8{31, 31}, [2]int8{25, 50},
+			[2]int8{-17, 120}, [2]int8{-20, 112}, [2]int8{-18, 114}, [2]int8{-11, 85},
+			[2]int8{-15, 92}, [2]int8{-14, 89}, [2]int8{-26, 71}, [2]int8{-15, 81},
+			[2]int8{-14, 80}, [2]int8{0, 68}, [2]int8{-14, 70}, [2]int8{-24, 56},
+			[2]int8{-23, 68}, [2]int8{-24, 50}, [2]int8{-11, 74}, [2]int8{23, -13},
+			[2]int8{26, -13}, [2]int8{40, -15}, [2]int8{49, -14}, [2]int8{44, 3},
+			[2]int8{45, 6}, [2]int8{44, 34}, [2]int8{33, 54}, [2]int8{19, 82},
+			[2]int8{-3, 75}, [2]int8{-1, 23}, [2]int8{1, 34}, [2]int8{1, 43},
+			[2]int8{0, 54}, [2]int8{-2, 55}, [2]int8{0, 61}, [2]int8{1, 64},
+			[2]int8{0, 68}, [2]int8{-9, 92},
+		)
+
+		return
+	}()
+
+	// Offsets of the context variables of residual block syntax elements,
+	// and of each ctxBlockCat within them (Tables 9-34 and 9-40).
+	codedBlockFlagOffset = [5]int{85 + 0, 85 + 4, 85 + 8, 85 + 12, 85 + 16}
+	significantOffset    = [6]int{105 + 0, 105 + 15, 105 + 29, 105 + 44, 105 + 47, 402}
+	lastOffset           = [6]int{166 + 0, 166 + 15, 166 + 29, 166 + 44, 166 + 47, 417}
+	absLevelOffset       = [6]int{227 + 0, 227 + 10, 227 + 20, 227 + 30, 227 + 39, 426}
+
+	// significant8x8 and last8x8 map 8x8 block scan positions to ctxIdxInc
+	// of significant_coeff_flag and last_significant_coeff_flag (Table 9-43).
+	significant8x8 = [63]uint8{
+		0, 1, 2, 3, 4, 5, 5, 4, 4, 3, 3, 4, 4, 4, 5, 5,
+		4, 4, 4, 4, 3, 3, 6, 7, 7, 7, 8, 9, 10, 9, 8, 7,
+		7, 6, 11, 12, 13, 11, 6, 7, 8, 9, 14, 10, 9, 8, 6, 11,
+		12, 13, 11, 6, 9, 14, 10, 9, 11, 12, 13, 11, 14, 10, 12,
+	}
+	last8x8 = [63]uint8{
+		0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+		2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2,
+		3, 3, 3, 3, 3, 3, 3, 3, 4, 4, 4, 4, 4, 4, 4, 4,
+		5, 5, 5, 5, 6, 6, 6, 6, 7, 7, 7, 7, 8, 8, 8,
+	}
+)
+
+// cabacContext is a CABAC context variable.
+type cabacContext struct {
+	state uint8 // pStateIdx
+	mps   uint8 // valMPS
+}
+
+// cabac parses macroblock layer syntax elements
+// of slices using CABAC entropy coding (9.3).
+type cabac struct {
+	sd  *sliceDecoder
+	r   *bitReader
+	rng uint32 // codIRange
+	off uint32 // codIOffset
+	ctx [len(cabacInitI)]cabacContext
+}
+
+// newCABAC returns a CABAC decoder for slice, with context variables
+// initialised for the slice QP, and the decoding engine initialised
+// at the current position of the slice data.
+func newCABAC(sd *sliceDecoder) *cabac {
+	c := &cabac{sd: sd, r: sd.r}
+
+	// Initialise context variables (9.3.1.1).
+	qp := clip3(0, 51, sd.hdr.qp)
+	for i, mn := range cabacInitI {
+		m, n := int(mn[0]), int(mn[1])
+		pre := clip3(1, 126, (m*qp)>>4+n)
+		if pre <= 63 {
+			c.ctx[i] = cabacContext{state: uint8(63 - pre), mps: 0}
+		} else {
+			c.ctx[i] = cabacContext{state: uint8(pre - 64), mps: 1}
+		}
+	}
+
+	c.start()
+	return c
+}
+
+// start initialises the arithmetic decoding engine (9.3.1.2).
+func (c *cabac) start() {
+	c.rng = 510
+	c.off = c.r.u(9)
+	if c.off >= 510 {
+		c.sd.fail(fmt.Errorf("invalid cabac offset"))
+	}
+}
+
+// decision decodes a bin using given context variable (9.3.3.2.1).
+func (c *cabac) decision(ctxIdx int) int {
+	ctx := &c.ctx[ctxIdx]
+	lps := uint32(rangeTabLPS[ctx.state][(c.rng>>6)&3])
+	c.rng -= lps
+
+	var bin uint8
+	if c.off >= c.rng {
+		bin = 1 - ctx.mps
+		c.off -= c.rng
+		c.rng = lps
+		if ctx.state == 0 {
+			ctx.mps = 1 - ctx.mps
+		}
+		ctx.state = transIdxLPS[ctx.state]
+	} else {
+		bin = ctx.mps
+		if ctx.state < 62 {
+			ctx.state++
+		}
+	}
+
+	for c.rng < 256 {
+		c.rng <<= 1
+		c.off = c.off<<1 | c.r.u1()
+	}
+
+	return int(bin)
+}
+
+// bypass decodes a bin using the bypass decoding process (9.3.3.2.3).
+func (c *cabac) bypass() int {
+	c.off = c.off<<1 | c.r.u1()
+	if c.off >= c.rng {
+		c.off -= c.rng
+		return 1
+	}
+	return 0
+}
+
+// terminate decodes a bin using the decoding
+// process for binary decisions before termination
+// (9.3.3.2.2), as used for end_of_slice_flag and
+// the bin of mb_type indicating I_PCM.
+func (c *cabac) terminate() int {
+	c.rng -= 2
+	if c.off >= c.rng {
+		return 1
+	}
+	for c.rng < 256 {
+		c.rng <<= 1
+		c.off = c.off<<1 | c.r.u1()
+	}
+	return 0
+}
+
+func (c *cabac) mbType() int {
+	// ctxIdxInc of the first bin counts available
+	// neighbours that aren't I_NxN (9.3.3.1.1.3).
+	inc := 0
+	if mb := c.sd.mbA(); mb != nil && mb.mbType != mbTypeINxN {
+		inc++
+	}
+	if mb := c.sd.mbB(); mb != nil && mb.mbType != mbTypeINxN {
+		inc++
+	}
+
+	// Binarization of Table 9-36.
+	if c.decision(3+inc) == 0 {
+		return mbTypeINxN
+	}
+	if c.terminate() == 1 {
+		return mbTypeIPCM
+	}
+
+	mbType := 1 + 12*c.decision(6)
+	if c.decision(7) == 1 {
+		mbType += 4 + 4*c.decision(8)
+	}
+	mbType += 2 * c.decision(9)
+	mbType += c.decision(10)
+	return mbType
+}
+
+func (c *cabac) transformSize8x8Flag() bool {
+	inc := 0
+	if mb := c.sd.mbA(); mb != nil && mb.transform8x8 {
+		inc++
+	}
+	if mb := c.sd.mbB(); mb != nil && mb.transform8x8 {
+		inc++
+	}
+	return c.decision(399+inc) == 1
+}
+
+func (c *cabac) prevIntraPredModeFlag() bool {
+	return c.decision(68) == 1
+}
+
+func (c *cabac) remIntraPredMode() int {
+	v := c.decision(69)
+	v |= c.decision(69) << 1
+	v |= c.decision(69) << 2
+	return v
+}
+
+func (c *cabac) intraChromaPredMode() int {
+	inc := 0
+	for i, mb := range []*macroblock{c.sd.mbA(), c.sd.mbB()} {
+		if mb != nil && mb.mbType != mbTypeIPCM && mb.chromaPredMode != 0 {
+			inc += 1 << i >> i
+		}
+	}
+
+	// Truncated unary, cMax = 3.
+	if c.decision(64+inc) == 0 {
+		return 0
+	}
+	if c.decision(64+3) == 0 {
+		return 1
+	}
+	if c.decision(64+3) == 0 {
+		return 2
+	}
+	return 3
+}
+
+func (c *cabac) codedBlockPattern() int {
+	mbA, mbB := c.sd.mbA(), c.sd.mbB()
+
+	// lumaBit returns whether the 8x8 block containing
+	// 4x4 block pos of mb has a coded luma residual,
+	// considering unavailable and I_PCM macroblocks
+	// to have coded residuals (9.3.3.1.1.4).
+	lumaBit := func(mb *macroblock, blk8 int) bool {
+		return mb == nil || mb.mbType == mbTypeIPCM || mb.cbp&(1<<blk8) != 0
+	}
+
+	// Prefix, with ctxIdxInc for each 8x8 block based on
+	// whether the blocks to the left and above are coded.
+	cbp := 0
+	for blk8 := 0; blk8 < 4; blk8++ {
+		x, y := blk8&1, blk8>>1
+
+		var left, above bool
+		if x == 0 {
+			left = lumaBit(mbA, blk8+1)
+		} else {
+			left = cbp&(1<<(blk8-1)) != 0
+		}
+		if y == 0 {
+			above = lumaBit(mbB, blk8+2)
+		} else {
+			above = cbp&(1<<(blk8-2)) != 0
+		}
+
+		inc := 0
+		if !left {
+			inc++
+		}
+		if !above {
+			inc += 2
+		}
+		cbp |= c.decision(73+inc) << blk8
+	}
+
+	if c.sd.pic.sps.chromaFormatIDC == 0 {
+		return cbp
+	}
+
+	// Suffix, with ctxIdxInc for each bin based
+	// on the neighbours' coded chroma residuals.
+	chroma := func(mb *macroblock) int {
+		switch {
+		case mb == nil:
+			return 0
+		case mb.mbType == mbTypeIPCM:
+			return 2
+		default:
+			return mb.cbp >> 4
+		}
+	}
+	a, b := chroma(mbA), chroma(mbB)
+
+	inc := 0
+	if a != 0 {
+		inc++
+	}
+	if b != 0 {
+		inc += 2
+	}
+	if c.decision(77+inc) == 0 {
+		return cbp
+	}
+
+	inc = 4
+	if a == 2 {
+		inc++
+	}
+	if b == 2 {
+		inc += 2
+	}
+	return cbp | (1+c.decision(77+inc))<<4
+}
+
+func (c *cabac) mbQPDelta() int {
+	// ctxIdxInc of first bin depends on whether the previous
+	// macroblock in decoding order had a non-zero mb_qp_delta.
+	inc := 0
+	if c.sd.curr > c.sd.hdr.firstMB {
+		prev := &c.sd.pic.mbs[c.sd.curr-1]
+		if prev.mbType != mbTypeIPCM && (prev.isI16x16() || prev.cbp != 0) && prev.qpDelta != 0 {
+			inc = 1
+		}
+	}
+
+	// Unary, mapped as in Table 9-3.
+	k := 0
+	for c.decision(60+inc) == 1 {
+		if k++; k > 52 {
+			c.sd.fail(fmt.Errorf("invalid mb_qp_delta"))
+			return 0
+		}
+		inc = 2
+		if k > 1 {
+			inc = 3
+		}
+	}
+
+	if k&1 == 1 {
+		return (k + 1) / 2
+	}
+	return -k / 2
+}
+
+func (c *cabac) pcmDone() {
+	c.start()
+}
+
+func (c *cabac) endOfSlice() bool {
+	return c.terminate() == 1
+}
+
+// codedBlockFlag decodes the coded_block_flag of a residual block,
+// using ctxIdxInc based on the neighbouring blocks (9.3.3.1.1.9).
+func (c *cabac) codedBlockFlag(cat int, comp int, bx, by int) bool {
+	size := 4
+	if comp > 0 {
+		size = 2
+	}
+
+	// flag returns condTermFlagN for the block
+	// blk of macroblock mb, of a neighbour N.
+	flag := func(mb *macroblock, blk int) int {
+		switch {
+		case mb == nil, mb.mbType == mbTypeIPCM:
+			// Unavailable macroblocks, given the
+			// current macroblock is intra, count
+			// as coded, same as I_PCM.
+			return 1
+		case cat == catLumaDC:
+			if mb.dcCoded[0] {
+				return 1
+			}
+		case cat == catChromaDC:
+			if mb.cbp>>4 != 0 && mb.dcCoded[comp] {
+				return 1
+			}
+		case cat == catChromaAC:
+			if mb.cbp>>4 == 2 && mb.nz[comp][blk] != 0 {
+				return 1
+			}
+		default:
+			if mb.nz[0][blk] != 0 {
+				return 1
+			}
+		}
+		return 0
+	}
+
+	var inc int
+	if cat == catLumaDC || cat == catChromaDC {
+		inc = flag(c.sd.mbA(), 0) + 2*flag(c.sd.mbB(), 0)
+	} else {
+		mbA, a := c.sd.blockA(bx, by, size)
+		mbB, b := c.sd.blockB(bx, by, size)
+		inc = flag(mbA, a) + 2*flag(mbB, b)
+	}
+
+	return c.decision(codedBlockFlagOffset[cat]+inc) == 1
+}
+
+// residualBlock parses a residual_block_cabac() (7.3.5.3.3, 9.3).
+func (c *cabac) residualBlock(coeffs []int32, cat int, comp int, bx, by int) int {
+	if cat != catLuma8x8 && !c.codedBlockFlag(cat, comp, bx, by) {
+		return 0
+	}
+
+	// Parse significance map.
+	var sig [64]bool
+	numCoeff := len(coeffs)
+	for i := 0; i < numCoeff-1; i++ {
+		var sigInc, lastInc int
+		switch cat {
+		case catChromaDC:
+			sigInc = i
+			if sigInc > 2 {
+				sigInc = 2
+			}
+			lastInc = sigInc
+		case catLuma8x8:
+			sigInc = int(significant8x8[i])
+			lastInc = int(last8x8[i])
+		default:
+			sigInc, lastInc = i, i
+		}
+
+		if c.decision(significantOffset[cat]+sigInc) == 1 {
+			sig[i] = true
+			if c.decision(lastOffset[cat]+lastInc) == 1 {
+				numCoeff = i + 1
+				break
+			}
+		}
+	}
+	sig[numCoeff-1] = true
+
+	// Parse levels in reverse scan order.
+	var eq1, gt1, n int
+	maxGt1Inc := 4
+	if cat == catChromaDC {
+		maxGt1Inc = 3
+	}
+	for i := numCoeff - 1; i >= 0; i-- {
+		if !sig[i] {
+			continue
+		}
+
+		// Prefix, truncated unary with cMax = 14.
+		inc := 0
+		if gt1 == 0 {
+			inc = eq1 + 1
+			if inc > 4 {
+				inc = 4
+			}
+		}
+
+		level := 1
+		if c.decision(absLevelOffset[cat]+inc) == 1 {
+			inc = gt1
+			if inc > maxGt1Inc {
+				inc = maxGt1Inc
+			}
+			inc += 5
+
+			level++
+			for level < 15 && c.decision(absLevelOffset[cat]+inc) == 1 {
+				level++
+			}
+
+			if level == 15 {
+				// Suffix, Exp-Golomb with k = 0.
+				k := 0
+				for c.bypass() == 1 {
+					level += 1 << uint(k)
+					if k++; k > 24 {
+						c.sd.fail(fmt.Errorf("invalid coeff_abs_level_minus1"))
+						return 0
+					}
+				}
+				for k--; k >= 0; k-- {
+					level += c.bypass() << uint(k)
+				}
+			}
+		}
+
+		if level == 1 {
+			eq1++
+		} else {
+			gt1++
+		}
+
+		if c.bypass() == 1 {
+			coeffs[i] = -int32(level)
+		} else {
+			coeffs[i] = int32(level)
+		}
+		n++
+	}
+
+	return n
+}
@@ -0,0 +1,413 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package h264
+
+import "fmt"
+
+// vlcTable is a variable length code table,
+// mapping codes of each length to values.
+type vlcTable map[uint32]int
+
+// newVLCTable returns a vlcTable for the given code lengths
+// and values, in which the index of each code is its value
+// and codes with zero length are unused.
+func newVLCTable(lens, codes []uint8) vlcTable {
+	t := make(vlcTable, len(lens))
+	for v, l := range lens {
+		if l > 0 {
+			t[uint32(l)<<16|uint32(codes[v])] = v
+		}
+	}
+	return t
+}
+
+// read reads a code from r, returning
+// its value, or -1 if code is invalid.
+func (t vlcTable) read(r *bitReader) int {
+	var code uint32
+	for l := uint32(1); l <= 16 && r.err == nil; l++ {
+		code = code<<1 | r.u1()
+		if v, ok := t[l<<16|code]; ok {
+			return v
+		}
+	}
+	return -1
+}
+
+var (
+	// coeffTokenTables holds the coeff_token tables
+	// for 0 <= nC < 2, 2 <= nC < 4, 4 <= nC < 8, and
+	// 8 <= nC, with values of TotalCoeff*4 + TrailingOnes
+	// (Table 9-5).
+	coeffTokenTables = [4]vlcTable{
+		newVLCTable([]uint8{
+			1, 0, 0, 0,
+			6, 2, 0, 0, 8, 6, 3, 0, 9, 8, 7, 5, 10, 9, 8, 6,
+			11, 10, 9, 7, 13, 11, 10, 8, 13, 13, 11, 9, 13, 13, 13, 10,
+			14, 14, 13, 11, 14, 14, 14, 13, 15, 15, 14, 14, 15, 15, 15, 14,
+			16, 15, 15, 15, 16, 16, 16, 15, 16, 16, 16, 16, 16, 16, 16, 16,
+		}, []uint8{
+			1, 0, 0, 0,
+			5, 1, 0, 0, 7, 4, 1, 0, 7, 6, 5, 3, 7, 6, 5, 3,
+			7, 6, 5, 4, 15, 6, 5, 4, 11, 14, 5, 4, 8, 10, 13, 4,
+			15, 14, 9, 4, 11, 10, 13, 12, 15, 14, 9, 12, 11, 10, 13, 8,
+			15, 1, 9, 12, 11, 14, 13, 8, 7, 10, 9, 12, 4, 6, 5, 8,
+		}),
+		newVLCTable([]uint8{
+			2, 0, 0, 0,
+			6, 2, 0, 0, 6, 5, 3, 0, 7, 6, 6, 4, 8, 6, 6, 4,
+			8, 7, 7, 5, 9, 8, 8, 6, 11, 9, 9, 6, 11, 11, 11, 7,
+			12, 11, 11, 9, 12, 12, 12, 11, 12, 12, 12, 11, 13, 13, 13, 12,
+			13, 13, 13, 13, 13, 14, 13, 13, 14, 14, 14, 13, 14, 14, 14, 14,
+		}, []uint8{
+			3, 0, 0, 0,
+			11, 2, 0, 0, 7, 7, 3, 0, 7, 10, 9, 5, 7, 6, 5, 4,
+			4, 6, 5, 6, 7, 6, 5, 8, 15, 6, 5, 4, 11, 14, 13, 4,
+			15, 10, 9, 4, 11, 14, 13, 12, 8, 10, 9, 8, 15, 14, 13, 12,
+			11, 10, 9, 12, 7, 11, 6, 8, 9, 8, 10, 1, 7, 6, 5, 4,
+		}),
+		newVLCTable([]uint8{
+			4, 0, 0, 0,
+			6, 4, 0, 0, 6, 5, 4, 0, 6, 5, 5, 4, 7, 5, 5, 4,
+			7, 5, 5, 4, 7, 6, 6, 4, 7, 6, 6, 4, 8, 7, 7, 5,
+			8, 8, 7, 6, 9, 8, 8, 7, 9, 9, 8, 8, 9, 9, 9, 8,
+			10, 9, 9, 9, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10,
+		}, []uint8{
+			15, 0, 0, 0,
+			15, 14, 0, 0, 11, 15, 13, 0, 8, 12, 14, 12, 15, 10, 11, 11,
+			11, 8, 9, 10, 9, 14, 13, 9, 8, 10, 9, 8, 15, 14, 13, 13,
+			11, 14, 10, 12, 15, 10, 13, 12, 11, 14, 9, 12, 8, 10, 13, 8,
+			13, 7, 9, 12, 9, 12, 11, 10, 5, 8, 7, 6, 1, 4, 3, 2,
+		}),
+		newVLCTable([]uint8{
+			6, 0, 0, 0,
+			6, 6, 0, 0, 6, 6, 6, 0, 6, 6, 6, 6, 6, 6, 6, 6,
+			6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
+			6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
+			6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
+		}, []uint8{
+			3, 0, 0, 0,
+			0, 1, 0, 0, 4, 5, 6, 0, 8, 9, 10, 11, 12, 13, 14, 15,
+			16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
+			32, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, 44, 45, 46, 47,
+			48, 49, 50, 51, 52, 53, 54, 55, 56, 57, 58, 59, 60, 61, 62, 63,
+		}),
+	}
+
+	// chromaDCCoeffTokenTable holds the coeff_token
+	// table for 4:2:0 chroma DC blocks (nC = -1).
+	chromaDCCoeffTokenTable = newVLCTable([]uint8{
+		2, 0, 0, 0,
+		6, 1, 0, 0,
+		6, 6, 3, 0,
+		6, 7, 7, 6,
+		6, 8, 8, 7,
+	}, []uint8{
+		1, 0, 0, 0,
+		7, 1, 0, 0,
+		4, 6, 1, 0,
+		3, 3, 2, 5,
+		2, 3, 2, 0,
+	})
+
+	// totalZerosTables holds the total_zeros tables for
+	// 4x4 blocks, by TotalCoeff - 1 (Tables 9-7 and 9-8).
+	totalZerosTables = func() (t [15]vlcTable) {
+		lens := [15][]uint8{
+			{1, 3, 3, 4, 4, 5, 5, 6, 6, 7, 7, 8, 8, 9, 9, 9},
+			{3, 3, 3, 3, 3, 4, 4, 4, 4, 5, 5, 6, 6, 6, 6},
+			{4, 3, 3, 3, 4, 4, 3, 3, 4, 5, 5, 6, 5, 6},
+			{5, 3, 4, 4, 3, 3, 3, 4, 3, 4, 5, 5, 5},
+			{4, 4, 4, 3, 3, 3, 3, 3, 4, 5, 4, 5},
+			{6, 5, 3, 3, 3, 3, 3, 3, 4, 3, 6},
+			{6, 5, 3, 3, 3, 2, 3, 4, 3, 6},
+			{6, 4, 5, 3, 2, 2, 3, 3, 6},
+			{6, 6, 4, 2, 2, 3, 2, 5},
+			{5, 5, 3, 2, 2, 2, 4},
+			{4, 4, 3, 3, 1, 3},
+			{4, 4, 2, 1, 3},
+			{3, 3, 1, 2},
+			{2, 2, 1},
+			{1, 1},
+		}
+		codes := [15][]uint8{
+			{1, 3, 2, 3, 2, 3, 2, 3, 2, 3, 2, 3, 2, 3, 2, 1},
+			{7, 6, 5, 4, 3, 5, 4, 3, 2, 3, 2, 3, 2, 1, 0},
+			{5, 7, 6, 5, 4, 3, 4, 3, 2, 3, 2, 1, 1, 0},
+			{3, 7, 5, 4, 6, 5, 4, 3, 3, 2, 2, 1, 0},
+			{5, 4, 3, 7, 6, 5, 4, 3, 2, 1, 1, 0},
+			{1, 1, 7, 6, 5, 4, 3, 2, 1, 1, 0},
+			{1, 1, 5, 4, 3, 3, 2, 1, 1, 0},
+			{1, 1, 1, 3, 3, 2, 2, 1, 0},
+			{1, 0, 1, 3, 2, 1, 1, 1},
+			{1, 0, 1, 3, 2, 1, 1},
+			{0, 1, 1, 2, 1, 3},
+			{0, 1, 1, 1, 1},
+			{0, 1, 1, 1},
+			{0, 1, 1},
+			{0, 1},
+		}
+		for i := range t {
+			t[i] = newVLCTable(lens[i], codes[i])
+		}
+		return
+	}()
+
+	// chromaDCTotalZerosTables holds the total_zeros tables for
+	// 4:2:0 chroma DC blocks, by TotalCoeff - 1 (Table 9-9a).
+	chromaDCTotalZerosTables = [3]vlcTable{
+		newVLCTable([]uint8{1, 2, 3, 3}, []uint8{1, 1, 1, 0}),
+		newVLCTable([]uint8{1, 2, 2}, []uint8{1, 1, 0}),
+		newVLCTable([]uint8{1, 1}, []uint8{1, 0}),
+	}
+
+	// runBeforeTables holds the run_before tables
+	// by Min(zerosLeft, 7) - 1 (Table 9-10).
+	runBeforeTables = [7]vlcTable{
+		newVLCTable([]uint8{1, 1}, []uint8{1, 0}),
+		newVLCTable([]uint8{1, 2, 2}, []uint8{1, 1, 0}),
+		newVLCTable([]uint8{2, 2, 2, 2}, []uint8{3, 2, 1, 0}),
+		newVLCTable([]uint8{2, 2, 2, 3, 3}, []uint8{3, 2, 1, 1, 0}),
+		newVLCTable([]uint8{2, 2, 3, 3, 3, 3}, []uint8{3, 2, 3, 2, 1, 0}),
+		newVLCTable([]uint8{2, 3, 3, 3, 3, 3, 3}, []uint8{3, 0, 1, 3, 2, 5, 4}),
+		newVLCTable(
+			[]uint8{3, 3, 3, 3, 3, 3, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+			[]uint8{7, 6, 5, 4, 3, 2, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		),
+	}
+
+	// intraCBP maps coded_block_pattern codeNum to values for
+	// intra macroblocks, for 4:2:0 and monochrome (Table 9-4).
+	intraCBP = [48]uint8{
+		47, 31, 15, 0, 23, 27, 29, 30, 7, 11, 13, 14, 39, 43, 45, 46,
+		16, 3, 5, 10, 12, 19, 21, 26, 28, 35, 37, 42, 44, 1, 2, 4,
+		8, 17, 18, 20, 24, 6, 9, 22, 25, 32, 33, 34, 36, 40, 38, 41,
+	}
+	intraCBPMono = [16]uint8{15, 0, 7, 11, 13, 14, 3, 5, 10, 12, 1, 2, 4, 8, 6, 9}
+)
+
+// cavlc parses macroblock layer syntax elements
+// of slices using CAVLC entropy coding (9.2).
+type cavlc struct {
+	sd *sliceDecoder
+	r  *bitReader
+}
+
+func (c *cavlc) mbType() int {
+	return int(c.r.ue())
+}
+
+func (c *cavlc) transformSize8x8Flag() bool {
+	return c.r.flag()
+}
+
+func (c *cavlc) prevIntraPredModeFlag() bool {
+	return c.r.flag()
+}
+
+func (c *cavlc) remIntraPredMode() int {
+	return int(c.r.u(3))
+}
+
+func (c *cavlc) intraChromaPredMode() int {
+	return int(c.r.ue())
+}
+
+func (c *cavlc) codedBlockPattern() int {
+	k := c.r.ue()
+	if c.sd.pic.sps.chromaFormatIDC == 0 {
+		if k < uint32(len(intraCBPMono)) {
+			return int(intraCBPMono[k])
+		}
+	} else if k < uint32(len(intraCBP)) {
+		return int(intraCBP[k])
+	}
+	c.sd.fail(fmt.Errorf("invalid coded_block_pattern %d", k))
+	return 0
+}
+
+func (c *cavlc) mbQPDelta() int {
+	return int(c.r.se())
+}
+
+func (c *cavlc) pcmDone() {}
+
+func (c *cavlc) endOfSlice() bool {
+	return !c.r.moreRBSPData()
+}
+
+// nC returns the coeff_token table selector for
+// the 4x4 block at (bx, by) of component (9.2.1).
+func (c *cavlc) nC(comp int, bx, by int) int {
+	size := 4
+	if comp > 0 {
+		size = 2
+	}
+
+	total := func(mb *macroblock, blk int) int {
+		if mb.mbType == mbTypeIPCM {
+			return 16
+		}
+		return int(mb.nz[comp][blk])
+	}
+
+	mbA, a := c.sd.blockA(bx, by, size)
+	mbB, b := c.sd.blockB(bx, by, size)
+	switch {
+	case mbA != nil && mbB != nil:
+		return (total(mbA, a) + total(mbB, b) + 1) >> 1
+	case mbA != nil:
+		return total(mbA, a)
+	case mbB != nil:
+		return total(mbB, b)
+	default:
+		return 0
+	}
+}
+
+// residualBlock parses a residual_block_cavlc() (7.3.5.3.2, 9.2).
+func (c *cavlc) residualBlock(coeffs []int32, cat int, comp int, bx, by int) int {
+	maxNumCoeff := len(coeffs)
+
+	// Parse coeff_token.
+	var token int
+	if cat == catChromaDC {
+		token = chromaDCCoeffTokenTable.read(c.r)
+	} else {
+		switch nC := c.nC(comp, bx, by); {
+		case nC < 2:
+			token = coeffTokenTables[0].read(c.r)
+		case nC < 4:
+			token = coeffTokenTables[1].read(c.r)
+		case nC < 8:
+			token = coeffTokenTables[2].read(c.r)
+		default:
+			token = coeffTokenTables[3].read(c.r)
+		}
+	}
+
+	totalCoeff, trailingOnes := token>>2, token&3
+	if token < 0 || totalCoeff > maxNumCoeff {
+		c.sd.fail(fmt.Errorf("invalid coeff_token"))
+		return 0
+	}
+	if totalCoeff == 0 {
+		return 0
+	}
+
+	// Parse levels (9.2.2).
+	var levels [16]int32
+	suffixLength := 0
+	if totalCoeff > 10 && trailingOnes < 3 {
+		suffixLength = 1
+	}
+
+	for i := 0; i < totalCoeff; i++ {
+		if i < trailingOnes {
+			levels[i] = 1 - 2*int32(c.r.u1())
+			continue
+		}
+
+		prefix := 0
+		for c.r.u1() == 0 {
+			if prefix++; prefix > 32 || c.r.err != nil {
+				c.sd.fail(fmt.Errorf("invalid level_prefix"))
+				return 0
+			}
+		}
+
+		code := prefix
+		if code > 15 {
+			code = 15
+		}
+		code <<= uint(suffixLength)
+
+		if suffixLength > 0 || prefix >= 14 {
+			size := suffixLength
+			if prefix == 14 && suffixLength == 0 {
+				size = 4
+			} else if prefix >= 15 {
+				size = prefix - 3
+			}
+			code += int(c.r.u(size))
+		}
+		if prefix >= 15 && suffixLength == 0 {
+			code += 15
+		}
+		if prefix >= 16 {
+			code += 1<<uint(prefix-3) - 4096
+		}
+		if i == trailingOnes && trailingOnes < 3 {
+			code += 2
+		}
+
+		if code%2 == 0 {
+			levels[i] = int32(code+2) >> 1
+		} else {
+			levels[i] = int32(-code-1) >> 1
+		}
+
+		if suffixLength == 0 {
+			suffixLength = 1
+		}
+		abs := levels[i]
+		if abs < 0 {
+			abs = -abs
+		}
+		if abs > 3<<uint(suffixLength-1) && suffixLength < 6 {
+			suffixLength++
+		}
+	}
+
+	// Parse total_zeros and run_before (9.2.3).
+	zerosLeft := 0
+	if totalCoeff < maxNumCoeff {
+		if cat == catChromaDC {
+			zerosLeft = chromaDCTotalZerosTables[totalCoeff-1].read(c.r)
+		} else {
+			zerosLeft = totalZerosTables[totalCoeff-1].read(c.r)
+		}
+		if zerosLeft < 0 || totalCoeff+zerosLeft > maxNumCoeff {
+			c.sd.fail(fmt.Errorf("invalid total_zeros"))
+			return 0
+		}
+	}
+
+	pos := totalCoeff + zerosLeft - 1
+	for i := 0; i < totalCoeff; i++ {
+		coeffs[pos] = levels[i]
+		if i == totalCoeff-1 || zerosLeft == 0 {
+			pos--
+			continue
+		}
+
+		tbl := zerosLeft
+		if tbl > 7 {
+			tbl = 7
+		}
+		run := runBeforeTables[tbl-1].read(c.r)
+		if run < 0 || run > zerosLeft {
+			c.sd.fail(fmt.Errorf("invalid run_before"))
+			return 0
+		}
+		zerosLeft -= run
+		pos -= run + 1
+	}
+
+	return totalCoeff
+}
@@ -0,0 +1,233 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package h264
+
+var (
+	// alphaTab and betaTab map indexA and indexB
+	// to the thresholds α′ and β′ (Table 8-16).
+	alphaTab = [52]int{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		4, 4, 5, 6, 7, 8, 9, 10, 12, 13, 15, 17, 20, 22, 25, 28,
+		32, 36, 40, 45, 50, 56, 63, 71, 80, 90, 101, 113, 127, 144, 162, 182,
+		203, 226, 255, 255,
+	}
+	betaTab = [52]int{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		2, 2, 2, 3, 3, 3, 3, 4, 4, 4, 6, 6, 7, 7, 8, 8,
+		9, 9, 10, 10, 11, 11, 12, 12, 13, 13, 14, 14, 15, 15, 16, 16,
+		17, 17, 18, 18,
+	}
+
+	// tc0Tab maps indexA to t′C0 for bS = 3 (Table 8-17), the
+	// only bS < 4 that occurs between intra macroblocks.
+	tc0Tab = [52]int{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 3,
+		3, 3, 4, 4, 4, 5, 6, 6, 7, 8, 9, 10, 11, 13, 14, 16,
+		18, 20, 23, 25,
+	}
+)
+
+// deblock applies the deblocking filter process (8.7) to
+// the decoded picture. As all macroblocks are intra coded,
+// macroblock edges always have bS = 4, and internal
+// transform block edges bS = 3.
+func (pic *picture) deblock() {
+	for mby := 0; mby < pic.height; mby++ {
+		for mbx := 0; mbx < pic.width; mbx++ {
+			pic.deblockMB(mbx, mby)
+		}
+	}
+}
+
+// deblockMB filters the edges of the macroblock at (mbx, mby),
+// vertical edges first, followed by horizontal edges.
+func (pic *picture) deblockMB(mbx, mby int) {
+	mb := &pic.mbs[mby*pic.width+mbx]
+	hdr := pic.slices[mb.slice-1]
+	if hdr.disableDeblock == 1 {
+		return
+	}
+
+	// filterEdge returns whether the edge against
+	// neighbouring macroblock mbN is to be filtered.
+	filterEdge := func(mbN *macroblock) bool {
+		return hdr.disableDeblock != 2 || mbN.slice == mb.slice
+	}
+
+	var left, top *macroblock
+	if mbx > 0 {
+		if mbN := &pic.mbs[mby*pic.width+mbx-1]; filterEdge(mbN) {
+			left = mbN
+		}
+	}
+	if mby > 0 {
+		if mbN := &pic.mbs[(mby-1)*pic.width+mbx]; filterEdge(mbN) {
+			top = mbN
+		}
+	}
+
+	lumaStride := pic.width * 16
+	chromaStride := pic.width * 8
+	lumaOff := mby*16*lumaStride + mbx*16
+	chromaOff := mby*8*chromaStride + mbx*8
+
+	for dir, mbN := range [2]*macroblock{left, top} {
+		// Step across, and along the edge.
+		lumaAcross, lumaAlong := 1, lumaStride
+		chromaAcross, chromaAlong := 1, chromaStride
+		if dir == 1 {
+			lumaAcross, lumaAlong = lumaStride, 1
+			chromaAcross, chromaAlong = chromaStride, 1
+		}
+
+		// Luma edges.
+		for e := 0; e < 4; e++ {
+			if e == 0 && mbN == nil {
+				continue
+			}
+			if e&1 == 1 && mb.transform8x8 {
+				continue
+			}
+
+			qp, bS := mb.qpY(), 3
+			if e == 0 {
+				qp, bS = (mbN.qpY()+qp+1)>>1, 4
+			}
+
+			pic.filterEdge(pic.luma, lumaOff+4*e*lumaAcross, lumaAcross, lumaAlong, 16, bS, qp, hdr, false)
+		}
+
+		if pic.sps.chromaFormatIDC == 0 {
+			continue
+		}
+
+		// Chroma edges.
+		for c, plane := range [][]uint8{pic.cb, pic.cr} {
+			offset := hdr.pps.chromaQPOffset[c]
+			for e := 0; e < 2; e++ {
+				if e == 0 && mbN == nil {
+					continue
+				}
+
+				qp, bS := mb.qpC(offset), 3
+				if e == 0 {
+					qp, bS = (mbN.qpC(offset)+qp+1)>>1, 4
+				}
+
+				pic.filterEdge(plane, chromaOff+4*e*chromaAcross, chromaAcross, chromaAlong, 8, bS, qp, hdr, true)
+			}
+		}
+	}
+}
+
+// qpY returns the luma QP of macroblock as used by the
+// deblocking filter, which is 0 for I_PCM macroblocks.
+func (mb *macroblock) qpY() int {
+	if mb.mbType == mbTypeIPCM {
+		return 0
+	}
+	return mb.qp
+}
+
+// qpC returns the chroma QP of macroblock for
+// given chroma_qp_index_offset, as used by the
+// deblocking filter.
+func (mb *macroblock) qpC(offset int) int {
+	return chromaQP[clip3(0, 51, mb.qpY()+offset)]
+}
+
+// filterEdge filters n sets of samples across an edge (8.7.2), starting
+// at off in plane, with across stepping from p0 to q0 and along stepping
+// to the next set of samples.
+func (pic *picture) filterEdge(plane []uint8, off, across, along, n, bS, qp int, hdr *sliceHeader, chroma bool) {
+	indexA := clip3(0, 51, qp+hdr.filterOffsetA)
+	indexB := clip3(0, 51, qp+hdr.filterOffsetB)
+	alpha, beta := alphaTab[indexA], betaTab[indexB]
+	if alpha == 0 || beta == 0 {
+		// Nothing can be filtered.
+		return
+	}
+	tc0 := tc0Tab[indexA]
+
+	for i := 0; i < n; i++ {
+		o := off + i*along
+		p0, q0 := int(plane[o-across]), int(plane[o])
+		p1, q1 := int(plane[o-2*across]), int(plane[o+across])
+		if abs(p0-q0) >= alpha || abs(p1-p0) >= beta || abs(q1-q0) >= beta {
+			continue
+		}
+
+		if chroma {
+			if bS < 4 {
+				delta := clip3(-(tc0 + 1), tc0+1, ((q0-p0)<<2+(p1-q1)+4)>>3)
+				plane[o-across] = clip1(int32(p0 + delta))
+				plane[o] = clip1(int32(q0 - delta))
+			} else {
+				plane[o-across] = uint8((2*p1 + p0 + q1 + 2) >> 2)
+				plane[o] = uint8((2*q1 + q0 + p1 + 2) >> 2)
+			}
+			continue
+		}
+
+		p2, q2 := int(plane[o-3*across]), int(plane[o+2*across])
+		ap, aq := abs(p2-p0), abs(q2-q0)
+
+		if bS < 4 {
+			tc := tc0
+			if ap < beta {
+				tc++
+				plane[o-2*across] = uint8(p1 + clip3(-tc0, tc0, (p2+(p0+q0+1)>>1-p1<<1)>>1))
+			}
+			if aq < beta {
+				tc++
+				plane[o+across] = uint8(q1 + clip3(-tc0, tc0, (q2+(p0+q0+1)>>1-q1<<1)>>1))
+			}
+			delta := clip3(-tc, tc, ((q0-p0)<<2+(p1-q1)+4)>>3)
+			plane[o-across] = clip1(int32(p0 + delta))
+			plane[o] = clip1(int32(q0 - delta))
+			continue
+		}
+
+		strong := abs(p0-q0) < alpha>>2+2
+		if ap < beta && strong {
+			p3 := int(plane[o-4*across])
+			plane[o-across] = uint8((p2 + 2*p1 + 2*p0 + 2*q0 + q1 + 4) >> 3)
+			plane[o-2*across] = uint8((p2 + p1 + p0 + q0 + 2) >> 2)
+			plane[o-3*across] = uint8((2*p3 + 3*p2 + p1 + p0 + q0 + 4) >> 3)
+		} else {
+			plane[o-across] = uint8((2*p1 + p0 + q1 + 2) >> 2)
+		}
+		if aq < beta && strong {
+			q3 := int(plane[o+3*across])
+			plane[o] = uint8((p1 + 2*p0 + 2*q0 + 2*q1 + q2 + 4) >> 3)
+			plane[o+across] = uint8((p0 + q0 + q1 + q2 + 2) >> 2)
+			plane[o+2*across] = uint8((2*q3 + 3*q2 + q1 + q0 + p0 + 4) >> 3)
+		} else {
+			plane[o] = uint8((2*q1 + q0 + p1 + 2) >> 2)
+		}
+	}
+}
+
+// abs returns the absolute value of v.
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
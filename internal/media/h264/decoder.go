@@ -0,0 +1,393 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package h264
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+)
+
+// ErrNotIntra is returned when decoding a picture that
+// can't be decoded alone, since it is predicted from
+// other pictures in the stream. Decoding should be
+// attempted again with a later sample in the stream.
+var ErrNotIntra = errors.New("h264: picture is not intra coded")
+
+// NAL unit types (Table 7-1).
+const (
+	nalSlice    = 1
+	nalSliceDPA = 2
+	nalSliceDPB = 3
+	nalSliceDPC = 4
+	nalSliceIDR = 5
+	nalSPS      = 7
+	nalPPS      = 8
+)
+
+// Decoder decodes individual intra coded pictures from
+// an H.264 / AVC elementary stream in the length-prefixed
+// form used by MP4 and Matroska containers.
+//
+// Only the subset of the standard needed to decode
+// progressive 8-bit 4:2:0 (or monochrome) I pictures
+// is supported, which covers the keyframes of streams
+// produced by common encoders for most profiles.
+type Decoder struct {
+	lengthSize int
+	width      int
+	height     int
+	sps        map[uint32]*sps
+	pps        map[uint32]*pps
+}
+
+// NewDecoder returns a new Decoder configured from the given
+// AVCDecoderConfigurationRecord, as found in the payload of an
+// MP4 'avcC' box, or the CodecPrivate element of a Matroska track.
+//
+// Width and height are those given for the video by its container.
+// Any SPS for pictures of another size is rejected, so that a stream
+// can't have the decoder allocate far more than its size suggests.
+func NewDecoder(config []byte, width int, height int) (*Decoder, error) {
+	if len(config) < 7 || config[0] != 1 {
+		return nil, errors.New("h264: invalid decoder configuration record")
+	}
+
+	d := &Decoder{
+		lengthSize: int(config[4]&3) + 1,
+		width:      width,
+		height:     height,
+		sps:        make(map[uint32]*sps),
+		pps:        make(map[uint32]*pps),
+	}
+
+	// Parse parameter sets from record.
+	b := config[5:]
+	for _, typ := range []byte{nalSPS, nalPPS} {
+		if len(b) < 1 {
+			return nil, errors.New("h264: truncated decoder configuration record")
+		}
+
+		n := int(b[0])
+		if typ == nalSPS {
+			n &= 0x1f
+		}
+		b = b[1:]
+
+		for i := 0; i < n; i++ {
+			if len(b) < 2 {
+				return nil, errors.New("h264: truncated decoder configuration record")
+			}
+			sz := int(binary.BigEndian.Uint16(b))
+			if len(b) < 2+sz || sz < 1 {
+				return nil, errors.New("h264: truncated decoder configuration record")
+			}
+			if err := d.parameterSet(b[2 : 2+sz]); err != nil {
+				return nil, err
+			}
+			b = b[2+sz:]
+		}
+	}
+
+	return d, nil
+}
+
+// parameterSet parses and stores the given SPS or PPS NAL unit.
+func (d *Decoder) parameterSet(nal []byte) error {
+	rbsp := unescapeRBSP(nal[1:])
+	switch nal[0] & 0x1f {
+	case nalSPS:
+		s, err := parseSPS(rbsp)
+		if err != nil {
+			return err
+		}
+		if !s.hasSize(d.width, d.height) {
+			return fmt.Errorf("h264: sps picture size %dx%d doesn't match video size %dx%d",
+				s.widthMbs*16, s.heightMbs*16, d.width, d.height)
+		}
+		d.sps[s.id] = s
+	case nalPPS:
+		p, err := parsePPS(rbsp, d.sps)
+		if err != nil {
+			return err
+		}
+		d.pps[p.id] = p
+	}
+	return nil
+}
+
+// Decode decodes the picture contained in the given sample (a
+// complete access unit of length-prefixed NAL units), returning
+// ErrNotIntra if the picture isn't coded using only I slices.
+func (d *Decoder) Decode(sample []byte) (*image.YCbCr, error) {
+	var pic *picture
+
+	for len(sample) > 0 {
+		if len(sample) < d.lengthSize {
+			return nil, errTruncated
+		}
+
+		var sz int
+		for i := 0; i < d.lengthSize; i++ {
+			sz = sz<<8 | int(sample[i])
+		}
+		sample = sample[d.lengthSize:]
+		if sz > len(sample) {
+			return nil, errTruncated
+		}
+
+		nal := sample[:sz]
+		sample = sample[sz:]
+		if len(nal) == 0 {
+			continue
+		}
+
+		switch typ := nal[0] & 0x1f; typ {
+		case nalSPS, nalPPS:
+			if err := d.parameterSet(nal); err != nil {
+				return nil, err
+			}
+
+		case nalSlice, nalSliceIDR:
+			var err error
+			if pic, err = d.decodeSlice(pic, nal); err != nil {
+				return nil, err
+			}
+
+		case nalSliceDPA, nalSliceDPB, nalSliceDPC:
+			return nil, fmt.Errorf("%w: data partitioning", ErrUnsupported)
+		}
+	}
+
+	if pic == nil {
+		return nil, errors.New("h264: sample contains no picture")
+	}
+
+	for i := range pic.mbs {
+		if pic.mbs[i].slice == 0 {
+			return nil, errors.New("h264: picture has missing macroblocks")
+		}
+	}
+
+	pic.deblock()
+	return pic.image(), nil
+}
+
+// decodeSlice decodes the given slice NAL unit into
+// picture, allocating the picture if it's nil.
+func (d *Decoder) decodeSlice(pic *picture, nal []byte) (*picture, error) {
+	r := newBitReader(unescapeRBSP(nal[1:]))
+
+	hdr, err := d.parseSliceHeader(r, nal[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if hdr == nil {
+		// Redundant slice.
+		return pic, nil
+	}
+
+	if pic == nil {
+		pic = newPicture(hdr.pps.sps)
+	} else if pic.sps != hdr.pps.sps {
+		return nil, errors.New("h264: slices of picture refer to different sps")
+	}
+
+	if hdr.firstMB >= len(pic.mbs) {
+		return nil, fmt.Errorf("h264: invalid first_mb_in_slice %d", hdr.firstMB)
+	}
+
+	pic.slices = append(pic.slices, hdr)
+	sd := &sliceDecoder{
+		pic: pic,
+		hdr: hdr,
+		num: len(pic.slices),
+		r:   r,
+		qp:  hdr.qp,
+	}
+
+	if err := sd.decode(); err != nil {
+		return nil, err
+	}
+
+	return pic, nil
+}
+
+// sliceHeader is a parsed slice_header() of an I slice.
+type sliceHeader struct {
+	pps            *pps
+	firstMB        int
+	qp             int
+	disableDeblock uint32
+	filterOffsetA  int
+	filterOffsetB  int
+}
+
+// parseSliceHeader parses a slice_header(), returning
+// nil if the slice is a redundant coded slice.
+func (d *Decoder) parseSliceHeader(r *bitReader, nalHdr byte) (*sliceHeader, error) {
+	hdr := new(sliceHeader)
+	idr := nalHdr&0x1f == nalSliceIDR
+
+	hdr.firstMB = int(r.ue())
+	switch sliceType := r.ue(); {
+	case sliceType > 9:
+		return nil, fmt.Errorf("h264: invalid slice_type %d", sliceType)
+	case sliceType%5 == 4:
+		return nil, fmt.Errorf("%w: SI slices", ErrUnsupported)
+	case sliceType%5 != 2:
+		return nil, ErrNotIntra
+	}
+
+	ppsID := r.ue()
+	hdr.pps = d.pps[ppsID]
+	if hdr.pps == nil {
+		return nil, fmt.Errorf("h264: slice refers to unknown pps %d", ppsID)
+	}
+	sps := hdr.pps.sps
+
+	r.u(int(sps.log2MaxFrameNum)) // frame_num
+	if idr {
+		r.ue() // idr_pic_id
+	}
+
+	switch sps.pocType {
+	case 0:
+		r.u(int(sps.log2MaxPocLsb)) // pic_order_cnt_lsb
+		if hdr.pps.bottomFieldPicOrder {
+			r.se() // delta_pic_order_cnt_bottom
+		}
+	case 1:
+		if !sps.pocAlwaysZero {
+			r.se() // delta_pic_order_cnt[0]
+			if hdr.pps.bottomFieldPicOrder {
+				r.se() // delta_pic_order_cnt[1]
+			}
+		}
+	}
+
+	if hdr.pps.redundantPicCnt && r.ue() > 0 {
+		return nil, nil
+	}
+
+	if nalHdr&0x60 != 0 {
+		// dec_ref_pic_marking()
+		if idr {
+			r.flag() // no_output_of_prior_pics_flag
+			r.flag() // long_term_reference_flag
+		} else if r.flag() {
+			for i := 0; r.err == nil; i++ {
+				op := r.ue()
+				if op == 0 {
+					break
+				} else if op > 6 || i > 66 {
+					return nil, errors.New("h264: invalid memory_management_control_operation")
+				}
+				if op == 1 || op == 3 {
+					r.ue() // difference_of_pic_nums_minus1
+				}
+				if op == 2 {
+					r.ue() // long_term_pic_num
+				}
+				if op == 3 || op == 6 {
+					r.ue() // long_term_frame_idx
+				}
+				if op == 4 {
+					r.ue() // max_long_term_frame_idx_plus1
+				}
+			}
+		}
+	}
+
+	hdr.qp = hdr.pps.picInitQP + int(r.se())
+	if hdr.qp < 0 || hdr.qp > 51 {
+		return nil, fmt.Errorf("h264: invalid slice qp %d", hdr.qp)
+	}
+
+	if hdr.pps.deblockingControl {
+		hdr.disableDeblock = r.ue()
+		if hdr.disableDeblock > 2 {
+			return nil, fmt.Errorf("h264: invalid disable_deblocking_filter_idc %d", hdr.disableDeblock)
+		}
+		if hdr.disableDeblock != 1 {
+			hdr.filterOffsetA = int(r.se()) * 2
+			hdr.filterOffsetB = int(r.se()) * 2
+		}
+	}
+
+	return hdr, r.err
+}
+
+// picture is a decoded picture, and the per-macroblock
+// state needed for decoding and deblocking it.
+type picture struct {
+	sps    *sps
+	width  int // in macroblocks
+	height int // in macroblocks
+	luma   []uint8
+	cb, cr []uint8
+	mbs    []macroblock
+	slices []*sliceHeader
+}
+
+// newPicture allocates a new picture for given SPS.
+func newPicture(sps *sps) *picture {
+	w, h := sps.widthMbs, sps.heightMbs
+	pic := &picture{
+		sps:    sps,
+		width:  w,
+		height: h,
+		luma:   make([]uint8, w*16*h*16),
+		cb:     make([]uint8, w*8*h*8),
+		cr:     make([]uint8, w*8*h*8),
+		mbs:    make([]macroblock, w*h),
+	}
+
+	if sps.chromaFormatIDC == 0 {
+		// Monochrome pictures
+		// have neutral chroma.
+		for i := range pic.cb {
+			pic.cb[i] = 128
+			pic.cr[i] = 128
+		}
+	}
+
+	return pic
+}
+
+// image returns the cropped picture as an image.
+func (pic *picture) image() *image.YCbCr {
+	w, h := pic.width*16, pic.height*16
+	img := &image.YCbCr{
+		Y:              pic.luma,
+		Cb:             pic.cb,
+		Cr:             pic.cr,
+		YStride:        w,
+		CStride:        w / 2,
+		SubsampleRatio: image.YCbCrSubsampleRatio420,
+		Rect:           image.Rect(0, 0, w, h),
+	}
+
+	s := pic.sps
+	return img.SubImage(image.Rect(
+		s.cropLeft, s.cropTop,
+		w-s.cropRight, h-s.cropBottom,
+	)).(*image.YCbCr)
+}
@@ -0,0 +1,176 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package h264_test
+
+import (
+	"image"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/abema/go-mp4"
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/media/h264"
+)
+
+type DecoderTestSuite struct {
+	suite.Suite
+}
+
+// readConfig reads the AVC decoder configuration
+// record of the video track of the mp4 file at path.
+func (suite *DecoderTestSuite) readConfig(path string) []byte {
+	f, err := os.Open(path)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	defer f.Close()
+
+	avcC, err := mp4.ExtractBox(f, nil, mp4.BoxPath{
+		mp4.BoxTypeMoov(),
+		mp4.BoxTypeTrak(),
+		mp4.BoxTypeMdia(),
+		mp4.BoxTypeMinf(),
+		mp4.BoxTypeStbl(),
+		mp4.BoxTypeStsd(),
+		mp4.BoxTypeAvc1(),
+		mp4.BoxTypeAvcC(),
+	})
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Len(avcC, 1)
+
+	config := make([]byte, avcC[0].Size-avcC[0].HeaderSize)
+	if _, err := f.Seek(int64(avcC[0].Offset+avcC[0].HeaderSize), io.SeekStart); err != nil {
+		suite.FailNow(err.Error())
+	}
+	if _, err := io.ReadFull(f, config); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	return config
+}
+
+// decodeSamples decodes the first n samples of
+// the video track of the mp4 file at path.
+func (suite *DecoderTestSuite) decodeSamples(path string, n int) ([]*image.YCbCr, []error) {
+	config := suite.readConfig(path)
+
+	f, err := os.Open(path)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	defer f.Close()
+
+	info, err := mp4.Probe(f)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	var (
+		imgs []*image.YCbCr
+		errs []error
+	)
+
+	for _, tr := range info.Tracks {
+		if tr.AVC == nil {
+			continue
+		}
+
+		dec, err := h264.NewDecoder(config, int(tr.AVC.Width), int(tr.AVC.Height))
+		if err != nil {
+			suite.FailNow(err.Error())
+		}
+
+		var si int
+		for _, chunk := range tr.Chunks {
+			offset := chunk.DataOffset
+			for i := 0; i < int(chunk.SamplesPerChunk) && si < n; i++ {
+				sample := tr.Samples[si]
+				si++
+
+				b := make([]byte, sample.Size)
+				if _, err := f.Seek(int64(offset), io.SeekStart); err != nil {
+					suite.FailNow(err.Error())
+				}
+				if _, err := io.ReadFull(f, b); err != nil {
+					suite.FailNow(err.Error())
+				}
+				offset += uint64(sample.Size)
+
+				img, err := dec.Decode(b)
+				imgs = append(imgs, img)
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return imgs, errs
+}
+
+func (suite *DecoderTestSuite) TestDecodeCAVLC() {
+	// Baseline profile, CAVLC.
+	imgs, errs := suite.decodeSamples("../test/birdnest-original.mp4", 2)
+	suite.NoError(errs[0])
+	suite.Equal(image.Rect(0, 0, 404, 720), imgs[0].Bounds())
+	suite.ErrorIs(errs[1], h264.ErrNotIntra)
+}
+
+func (suite *DecoderTestSuite) TestDecodeCABAC() {
+	// Main profile, CABAC.
+	imgs, errs := suite.decodeSamples("../test/test-mp4-original.mp4", 2)
+	suite.NoError(errs[0])
+	suite.Equal(image.Rect(0, 0, 338, 240), imgs[0].Bounds())
+	suite.ErrorIs(errs[1], h264.ErrNotIntra)
+}
+
+func (suite *DecoderTestSuite) TestDecodeCABAC8x8() {
+	// High profile, CABAC with 8x8 transforms.
+	imgs, errs := suite.decodeSamples("../test/longer-mp4-original.mp4", 2)
+	suite.NoError(errs[0])
+	suite.Equal(image.Rect(0, 0, 600, 330), imgs[0].Bounds())
+	suite.ErrorIs(errs[1], h264.ErrNotIntra)
+}
+
+func (suite *DecoderTestSuite) TestNewDecoderInvalid() {
+	_, err := h264.NewDecoder([]byte{1, 2, 3}, 16, 16)
+	suite.EqualError(err, "h264: invalid decoder configuration record")
+}
+
+func (suite *DecoderTestSuite) TestNewDecoderTooBig() {
+	// Record with the SPS of a baseline
+	// profile picture of 1024x1024 mbs.
+	config := []byte{
+		0x01, 0x42, 0xc0, 0x1e, 0xff, 0xe1, 0x00, 0x0b,
+		0x67, 0x42, 0xc0, 0x1e, 0xf8, 0x00, 0x80, 0x00,
+		0x04, 0x00, 0xc8, 0x00,
+	}
+	_, err := h264.NewDecoder(config, 16384, 16384)
+	suite.EqualError(err, "h264: invalid picture size 1024x1024 mbs")
+}
+
+func (suite *DecoderTestSuite) TestNewDecoderWrongSize() {
+	config := suite.readConfig("../test/test-mp4-original.mp4")
+	_, err := h264.NewDecoder(config, 1920, 1080)
+	suite.EqualError(err, "h264: sps picture size 352x240 doesn't match video size 1920x1080")
+}
+
+func TestDecoderTestSuite(t *testing.T) {
+	suite.Run(t, new(DecoderTestSuite))
+}
@@ -0,0 +1,23 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package h264 implements a minimal H.264 (ITU-T Rec. H.264 | ISO/IEC 14496-10)
+// video decoder, sufficient to decode intra coded pictures such as the key frames
+// used for video thumbnails. Pictures must be progressive, 8 bits per sample and
+// 4:2:0 or monochrome, which covers the Baseline, Main and High profiles; inter
+// prediction is not supported, so pictures containing P or B slices are rejected.
+package h264
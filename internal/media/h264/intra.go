@@ -0,0 +1,373 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package h264
+
+// Intra prediction modes for 4x4 and 8x8 luma blocks (Table 8-2 / 8-3).
+const (
+	predVertical = iota
+	predHorizontal
+	predDC
+	predDiagonalDownLeft
+	predDiagonalDownRight
+	predVerticalRight
+	predHorizontalDown
+	predVerticalLeft
+	predHorizontalUp
+)
+
+// Intra prediction modes for 16x16 luma blocks (Table 8-4).
+const (
+	pred16x16Vertical = iota
+	pred16x16Horizontal
+	pred16x16DC
+	pred16x16Plane
+)
+
+// Intra prediction modes for chroma blocks (Table 8-5).
+const (
+	predChromaDC = iota
+	predChromaHorizontal
+	predChromaVertical
+	predChromaPlane
+)
+
+// neighbours holds the reference samples for intra prediction of a block of
+// width n. Index 0 of both top and left holds the top-left sample p[-1, -1],
+// followed by p[x, -1] for x = 0..2n-1 in top, and p[-1, y] for y = 0..n-1 in
+// left. Unavailable top-right samples must already have been substituted.
+type neighbours struct {
+	top, left       []int32
+	hasTop, hasLeft bool
+	hasTopLeft      bool
+}
+
+// dc returns the mean of n available neighbouring samples above
+// and / or to the left of a block, or 128 if none are available.
+func (nb *neighbours) dc(n int, shift uint) int32 {
+	var sum int32
+	switch {
+	case nb.hasTop && nb.hasLeft:
+		for i := 1; i <= n; i++ {
+			sum += nb.top[i] + nb.left[i]
+		}
+		return (sum + int32(n)) >> (shift + 1)
+	case nb.hasTop:
+		for i := 1; i <= n; i++ {
+			sum += nb.top[i]
+		}
+	case nb.hasLeft:
+		for i := 1; i <= n; i++ {
+			sum += nb.left[i]
+		}
+	default:
+		return 128
+	}
+	return (sum + int32(n)>>1) >> shift
+}
+
+// filter8x8 performs the reference sample
+// filtering process for Intra_8x8 (8.3.2.2.1).
+func (nb *neighbours) filter8x8() {
+	top := make([]int32, len(nb.top))
+	left := make([]int32, len(nb.left))
+	copy(top, nb.top)
+	copy(left, nb.left)
+
+	p := func(x, y int) int32 {
+		if y < 0 {
+			return nb.top[x+1]
+		}
+		return nb.left[y+1]
+	}
+
+	if nb.hasTop {
+		if nb.hasTopLeft {
+			top[1] = (p(-1, -1) + 2*p(0, -1) + p(1, -1) + 2) >> 2
+		} else {
+			top[1] = (3*p(0, -1) + p(1, -1) + 2) >> 2
+		}
+		for x := 1; x < 15; x++ {
+			top[x+1] = (p(x-1, -1) + 2*p(x, -1) + p(x+1, -1) + 2) >> 2
+		}
+		top[16] = (p(14, -1) + 3*p(15, -1) + 2) >> 2
+	}
+
+	if nb.hasTopLeft {
+		var v int32
+		switch {
+		case nb.hasTop && nb.hasLeft:
+			v = (p(0, -1) + 2*p(-1, -1) + p(-1, 0) + 2) >> 2
+		case nb.hasTop:
+			v = (3*p(-1, -1) + p(0, -1) + 2) >> 2
+		case nb.hasLeft:
+			v = (3*p(-1, -1) + p(-1, 0) + 2) >> 2
+		default:
+			v = p(-1, -1)
+		}
+		top[0], left[0] = v, v
+	}
+
+	if nb.hasLeft {
+		if nb.hasTopLeft {
+			left[1] = (p(-1, -1) + 2*p(-1, 0) + p(-1, 1) + 2) >> 2
+		} else {
+			left[1] = (3*p(-1, 0) + p(-1, 1) + 2) >> 2
+		}
+		for y := 1; y < 7; y++ {
+			left[y+1] = (p(-1, y-1) + 2*p(-1, y) + p(-1, y+1) + 2) >> 2
+		}
+		left[8] = (p(-1, 6) + 3*p(-1, 7) + 2) >> 2
+	}
+
+	nb.top, nb.left = top, left
+}
+
+// predNxN writes the Intra_4x4 or Intra_8x8 prediction
+// (8.3.1.2 / 8.3.2.2) of an n x n block to dst.
+func predNxN(dst []uint8, stride int, n int, mode int, nb *neighbours) {
+	p := func(x, y int) int32 {
+		if y < 0 {
+			return nb.top[x+1]
+		}
+		return nb.left[y+1]
+	}
+
+	// 3-tap and 2-tap filters.
+	f3 := func(a, b, c int32) int32 { return (a + 2*b + c + 2) >> 2 }
+	f2 := func(a, b int32) int32 { return (a + b + 1) >> 1 }
+
+	var dc int32
+	if mode == predDC {
+		shift := uint(2)
+		if n == 8 {
+			shift = 3
+		}
+		dc = nb.dc(n, shift)
+	}
+
+	for y := 0; y < n; y++ {
+		row := dst[y*stride : y*stride+n]
+		for x := range row {
+			var v int32
+			switch mode {
+			case predVertical:
+				v = p(x, -1)
+
+			case predHorizontal:
+				v = p(-1, y)
+
+			case predDC:
+				v = dc
+
+			case predDiagonalDownLeft:
+				if x == n-1 && y == n-1 {
+					v = (p(2*n-2, -1) + 3*p(2*n-1, -1) + 2) >> 2
+				} else {
+					v = f3(p(x+y, -1), p(x+y+1, -1), p(x+y+2, -1))
+				}
+
+			case predDiagonalDownRight:
+				switch {
+				case x > y:
+					v = f3(p(x-y-2, -1), p(x-y-1, -1), p(x-y, -1))
+				case x < y:
+					v = f3(p(-1, y-x-2), p(-1, y-x-1), p(-1, y-x))
+				default:
+					v = f3(p(0, -1), p(-1, -1), p(-1, 0))
+				}
+
+			case predVerticalRight:
+				switch z := 2*x - y; {
+				case z >= 0 && z&1 == 0:
+					v = f2(p(x-y>>1-1, -1), p(x-y>>1, -1))
+				case z >= 0:
+					v = f3(p(x-y>>1-2, -1), p(x-y>>1-1, -1), p(x-y>>1, -1))
+				case z == -1:
+					v = f3(p(-1, 0), p(-1, -1), p(0, -1))
+				default:
+					v = f3(p(-1, y-2*x-1), p(-1, y-2*x-2), p(-1, y-2*x-3))
+				}
+
+			case predHorizontalDown:
+				switch z := 2*y - x; {
+				case z >= 0 && z&1 == 0:
+					v = f2(p(-1, y-x>>1-1), p(-1, y-x>>1))
+				case z >= 0:
+					v = f3(p(-1, y-x>>1-2), p(-1, y-x>>1-1), p(-1, y-x>>1))
+				case z == -1:
+					v = f3(p(-1, 0), p(-1, -1), p(0, -1))
+				default:
+					v = f3(p(x-2*y-1, -1), p(x-2*y-2, -1), p(x-2*y-3, -1))
+				}
+
+			case predVerticalLeft:
+				if y&1 == 0 {
+					v = f2(p(x+y>>1, -1), p(x+y>>1+1, -1))
+				} else {
+					v = f3(p(x+y>>1, -1), p(x+y>>1+1, -1), p(x+y>>1+2, -1))
+				}
+
+			case predHorizontalUp:
+				switch z := x + 2*y; {
+				case z > 2*n-3:
+					v = p(-1, n-1)
+				case z == 2*n-3:
+					v = (p(-1, n-2) + 3*p(-1, n-1) + 2) >> 2
+				case z&1 == 0:
+					v = f2(p(-1, y+x>>1), p(-1, y+x>>1+1))
+				default:
+					v = f3(p(-1, y+x>>1), p(-1, y+x>>1+1), p(-1, y+x>>1+2))
+				}
+			}
+			row[x] = uint8(v)
+		}
+	}
+}
+
+// pred16x16 writes the Intra_16x16 prediction (8.3.3) of a macroblock to dst.
+func pred16x16(dst []uint8, stride int, mode int, nb *neighbours) {
+	switch mode {
+	case pred16x16Vertical:
+		for y := 0; y < 16; y++ {
+			for x := 0; x < 16; x++ {
+				dst[y*stride+x] = uint8(nb.top[x+1])
+			}
+		}
+
+	case pred16x16Horizontal:
+		for y := 0; y < 16; y++ {
+			for x := 0; x < 16; x++ {
+				dst[y*stride+x] = uint8(nb.left[y+1])
+			}
+		}
+
+	case pred16x16DC:
+		dc := uint8(nb.dc(16, 4))
+		for y := 0; y < 16; y++ {
+			for x := 0; x < 16; x++ {
+				dst[y*stride+x] = dc
+			}
+		}
+
+	case pred16x16Plane:
+		predPlane(dst, stride, 16, 16, 5, 5, nb)
+	}
+}
+
+// predChroma writes the chroma intra prediction (8.3.4)
+// of a 4:2:0 macroblock chroma component to dst.
+func predChroma(dst []uint8, stride int, mode int, nb *neighbours) {
+	switch mode {
+	case predChromaDC:
+		// Each 4x4 chroma block has its
+		// own DC prediction (8.3.4.1-3).
+		for blk := 0; blk < 4; blk++ {
+			xO, yO := (blk&1)*4, (blk>>1)*4
+
+			var sumTop, sumLeft int32
+			for i := 1; i <= 4; i++ {
+				sumTop += nb.top[xO+i]
+				sumLeft += nb.left[yO+i]
+			}
+
+			dc := int32(128)
+			switch {
+			case (xO == 0) == (yO == 0):
+				// Top-left and bottom-right blocks
+				// use both neighbours if available.
+				switch {
+				case nb.hasTop && nb.hasLeft:
+					dc = (sumTop + sumLeft + 4) >> 3
+				case nb.hasLeft:
+					dc = (sumLeft + 2) >> 2
+				case nb.hasTop:
+					dc = (sumTop + 2) >> 2
+				}
+			case yO == 0:
+				// Top-right block prefers samples above.
+				switch {
+				case nb.hasTop:
+					dc = (sumTop + 2) >> 2
+				case nb.hasLeft:
+					dc = (sumLeft + 2) >> 2
+				}
+			default:
+				// Bottom-left block prefers samples to the left.
+				switch {
+				case nb.hasLeft:
+					dc = (sumLeft + 2) >> 2
+				case nb.hasTop:
+					dc = (sumTop + 2) >> 2
+				}
+			}
+
+			for y := 0; y < 4; y++ {
+				for x := 0; x < 4; x++ {
+					dst[(yO+y)*stride+xO+x] = uint8(dc)
+				}
+			}
+		}
+
+	case predChromaHorizontal:
+		for y := 0; y < 8; y++ {
+			for x := 0; x < 8; x++ {
+				dst[y*stride+x] = uint8(nb.left[y+1])
+			}
+		}
+
+	case predChromaVertical:
+		for y := 0; y < 8; y++ {
+			for x := 0; x < 8; x++ {
+				dst[y*stride+x] = uint8(nb.top[x+1])
+			}
+		}
+
+	case predChromaPlane:
+		predPlane(dst, stride, 8, 8, 34, 34, nb)
+	}
+}
+
+// predPlane writes a plane prediction of a w x h block to dst,
+// with gradient multipliers mulH and mulV (8.3.3.4 / 8.3.4.4).
+func predPlane(dst []uint8, stride int, w, h int, mulH, mulV int32, nb *neighbours) {
+	p := func(x, y int) int32 {
+		if y < 0 {
+			return nb.top[x+1]
+		}
+		return nb.left[y+1]
+	}
+
+	var dH, dV int32
+	for x := 0; x < w/2; x++ {
+		dH += int32(x+1) * (p(w/2+x, -1) - p(w/2-2-x, -1))
+	}
+	for y := 0; y < h/2; y++ {
+		dV += int32(y+1) * (p(-1, h/2+y) - p(-1, h/2-2-y))
+	}
+
+	a := 16 * (p(-1, h-1) + p(w-1, -1))
+	b := (mulH*dH + 32) >> 6
+	c := (mulV*dV + 32) >> 6
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := (a + b*int32(x-(w/2-1)) + c*int32(y-(h/2-1)) + 16) >> 5
+			dst[y*stride+x] = clip1(v)
+		}
+	}
+}
@@ -0,0 +1,393 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package h264
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupported is returned when a stream
+// uses coding tools this package can't decode.
+var ErrUnsupported = errors.New("h264: unsupported stream")
+
+// sps is a parsed sequence parameter set.
+type sps struct {
+	id              uint32
+	profileIDC      uint32
+	chromaFormatIDC uint32
+	transformBypass bool
+	scalingPresent  bool
+	scaling4x4      [6][16]uint8 // in zig-zag scan order
+	scaling8x8      [2][64]uint8 // in zig-zag scan order
+	log2MaxFrameNum uint32
+	pocType         uint32
+	log2MaxPocLsb   uint32
+	pocAlwaysZero   bool
+	widthMbs        int
+	heightMbs       int
+	cropLeft        int // in luma samples
+	cropRight       int // in luma samples
+	cropTop         int // in luma samples
+	cropBottom      int // in luma samples
+}
+
+// maxFrameMbs is the maximum permitted number of
+// macroblocks in a picture, that of level 6.2 (Table A-1).
+// This bounds the memory allocated for a picture, which
+// the sizes in an SPS could otherwise make huge.
+const maxFrameMbs = 139264
+
+// hasSize returns whether pictures of the SPS are of the
+// given size, either as coded or once cropped for output.
+func (s *sps) hasSize(width, height int) bool {
+	w, h := s.widthMbs*16, s.heightMbs*16
+	return (width == w && height == h) ||
+		(width == w-s.cropLeft-s.cropRight && height == h-s.cropTop-s.cropBottom)
+}
+
+// pps is a parsed picture parameter set.
+type pps struct {
+	id                  uint32
+	sps                 *sps
+	cabac               bool
+	bottomFieldPicOrder bool
+	picInitQP           int
+	chromaQPOffset      [2]int
+	deblockingControl   bool
+	redundantPicCnt     bool
+	transform8x8        bool
+	levelScale4x4       [3][6][16]int32 // intra Y, Cb, Cr; by qP%6; in raster order
+	levelScale8x8       [6][64]int32    // intra Y; by qP%6; in raster order
+	scaling4x4          [6][16]uint8
+	scaling8x8          [2][64]uint8
+}
+
+var (
+	// flat16 is the Flat_4x4_16 and
+	// Flat_8x8_16 scaling list values.
+	flat16 = func() (l [64]uint8) {
+		for i := range l {
+			l[i] = 16
+		}
+		return
+	}()
+
+	// default4x4 holds Default_4x4_Intra and Default_4x4_Inter (Table 7-3).
+	default4x4 = [2][16]uint8{
+		{6, 13, 13, 20, 20, 20, 28, 28, 28, 28, 32, 32, 32, 37, 37, 42},
+		{10, 14, 14, 20, 20, 20, 24, 24, 24, 24, 27, 27, 27, 30, 30, 34},
+	}
+
+	// default8x8 holds Default_8x8_Intra and Default_8x8_Inter (Table 7-4).
+	default8x8 = [2][64]uint8{
+		{
+			6, 10, 10, 13, 11, 13, 16, 16, 16, 16, 18, 18, 18, 18, 18, 23,
+			23, 23, 23, 23, 23, 25, 25, 25, 25, 25, 25, 25, 27, 27, 27, 27,
+			27, 27, 27, 27, 29, 29, 29, 29, 29, 29, 29, 31, 31, 31, 31, 31,
+			31, 33, 33, 33, 33, 33, 36, 36, 36, 36, 38, 38, 38, 40, 40, 42,
+		},
+		{
+			9, 13, 13, 15, 13, 15, 17, 17, 17, 17, 19, 19, 19, 19, 19, 21,
+			21, 21, 21, 21, 21, 22, 22, 22, 22, 22, 22, 22, 24, 24, 24, 24,
+			24, 24, 24, 24, 25, 25, 25, 25, 25, 25, 25, 27, 27, 27, 27, 27,
+			27, 28, 28, 28, 28, 28, 30, 30, 30, 30, 32, 32, 32, 33, 33, 35,
+		},
+	}
+)
+
+// parseScalingList parses a scaling_list() into given list, returning
+// whether the default scaling matrix should be used instead.
+func parseScalingList(r *bitReader, list []uint8) bool {
+	last, next := int32(8), int32(8)
+	for j := range list {
+		if next != 0 {
+			next = (last + r.se() + 256) % 256
+			if j == 0 && next == 0 {
+				return true
+			}
+		}
+		if next != 0 {
+			last = next
+		}
+		list[j] = uint8(last)
+	}
+	return false
+}
+
+// parseScalingMatrix parses the scaling lists of an SPS or PPS, applying fall-back
+// rule A (when fallback is nil), or fall-back rule B using the given SPS lists.
+func parseScalingMatrix(r *bitReader, n8x8 int, l4x4 *[6][16]uint8, l8x8 *[2][64]uint8, fallback *sps) {
+	for i := 0; i < 6; i++ {
+		if r.flag() {
+			if parseScalingList(r, l4x4[i][:]) {
+				l4x4[i] = default4x4[i/3]
+			}
+			continue
+		}
+		switch {
+		case i%3 != 0:
+			// Fall back to previous list.
+			l4x4[i] = l4x4[i-1]
+		case fallback != nil:
+			l4x4[i] = fallback.scaling4x4[i]
+		default:
+			l4x4[i] = default4x4[i/3]
+		}
+	}
+
+	for i := 0; i < n8x8; i++ {
+		var list [64]uint8
+		if r.flag() {
+			if parseScalingList(r, list[:]) {
+				list = default8x8[i%2]
+			}
+		} else if i >= 2 {
+			// 4:4:4 chroma lists fall back to
+			// the previous list of same type.
+			continue
+		} else if fallback != nil {
+			list = fallback.scaling8x8[i]
+		} else {
+			list = default8x8[i]
+		}
+		if i < 2 {
+			l8x8[i] = list
+		}
+	}
+}
+
+// parseSPS parses a seq_parameter_set_rbsp().
+func parseSPS(rbsp []byte) (*sps, error) {
+	r := newBitReader(rbsp)
+	s := new(sps)
+
+	s.profileIDC = r.u(8)
+	r.u(16) // constraint flags, reserved bits, level_idc
+	s.id = r.ue()
+	s.chromaFormatIDC = 1
+
+	switch s.profileIDC {
+	case 100, 110, 122, 244, 44, 83, 86, 118, 128, 138, 139, 134, 135:
+		s.chromaFormatIDC = r.ue()
+		if s.chromaFormatIDC == 3 {
+			r.flag() // separate_colour_plane_flag
+		}
+		if r.ue() != 0 || r.ue() != 0 {
+			// Only 8-bit samples are supported.
+			return nil, fmt.Errorf("%w: bit depth > 8", ErrUnsupported)
+		}
+		s.transformBypass = r.flag()
+		s.scalingPresent = r.flag()
+		if s.scalingPresent {
+			n8x8 := 2
+			if s.chromaFormatIDC == 3 {
+				n8x8 = 6
+			}
+			parseScalingMatrix(r, n8x8, &s.scaling4x4, &s.scaling8x8, nil)
+		}
+	}
+
+	if !s.scalingPresent {
+		for i := range s.scaling4x4 {
+			copy(s.scaling4x4[i][:], flat16[:])
+		}
+		for i := range s.scaling8x8 {
+			copy(s.scaling8x8[i][:], flat16[:])
+		}
+	}
+
+	s.log2MaxFrameNum = r.ue() + 4
+	s.pocType = r.ue()
+	switch s.pocType {
+	case 0:
+		s.log2MaxPocLsb = r.ue() + 4
+	case 1:
+		s.pocAlwaysZero = r.flag()
+		r.se() // offset_for_non_ref_pic
+		r.se() // offset_for_top_to_bottom_field
+		n := r.ue()
+		if n > 255 {
+			return nil, errors.New("h264: invalid num_ref_frames_in_pic_order_cnt_cycle")
+		}
+		for i := uint32(0); i < n; i++ {
+			r.se() // offset_for_ref_frame
+		}
+	}
+
+	r.ue()   // max_num_ref_frames
+	r.flag() // gaps_in_frame_num_value_allowed_flag
+	s.widthMbs = int(r.ue()) + 1
+	s.heightMbs = int(r.ue()) + 1
+	if !r.flag() {
+		// Field coding (frame_mbs_only_flag = 0) is not supported.
+		return nil, fmt.Errorf("%w: interlaced", ErrUnsupported)
+	}
+	r.flag() // direct_8x8_inference_flag
+
+	if r.flag() {
+		// Crop units for 4:2:0, or
+		// monochrome, progressive frames.
+		unitX, unitY := 2, 2
+		if s.chromaFormatIDC == 0 {
+			unitX, unitY = 1, 1
+		}
+		s.cropLeft = int(r.ue()) * unitX
+		s.cropRight = int(r.ue()) * unitX
+		s.cropTop = int(r.ue()) * unitY
+		s.cropBottom = int(r.ue()) * unitY
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	switch {
+	case s.chromaFormatIDC > 1:
+		return nil, fmt.Errorf("%w: chroma format %d", ErrUnsupported, s.chromaFormatIDC)
+	case s.widthMbs > 1024 || s.heightMbs > 1024 || s.widthMbs*s.heightMbs > maxFrameMbs:
+		return nil, fmt.Errorf("h264: invalid picture size %dx%d mbs", s.widthMbs, s.heightMbs)
+	case s.cropLeft+s.cropRight >= s.widthMbs*16 ||
+		s.cropTop+s.cropBottom >= s.heightMbs*16:
+		return nil, errors.New("h264: invalid frame cropping")
+	}
+
+	return s, nil
+}
+
+// parsePPS parses a pic_parameter_set_rbsp(),
+// using the given map to look up its SPS.
+func parsePPS(rbsp []byte, spss map[uint32]*sps) (*pps, error) {
+	r := newBitReader(rbsp)
+	p := new(pps)
+
+	p.id = r.ue()
+	spsID := r.ue()
+	p.sps = spss[spsID]
+	if p.sps == nil {
+		return nil, fmt.Errorf("h264: pps refers to unknown sps %d", spsID)
+	}
+
+	p.cabac = r.flag()
+	p.bottomFieldPicOrder = r.flag()
+	if r.ue() != 0 {
+		// Flexible macroblock ordering is not supported.
+		return nil, fmt.Errorf("%w: slice groups", ErrUnsupported)
+	}
+	r.ue()   // num_ref_idx_l0_default_active_minus1
+	r.ue()   // num_ref_idx_l1_default_active_minus1
+	r.flag() // weighted_pred_flag
+	r.u(2)   // weighted_bipred_idc
+	p.picInitQP = 26 + int(r.se())
+	r.se() // pic_init_qs_minus26
+	p.chromaQPOffset[0] = int(r.se())
+	p.chromaQPOffset[1] = p.chromaQPOffset[0]
+	p.deblockingControl = r.flag()
+	r.flag() // constrained_intra_pred_flag
+	p.redundantPicCnt = r.flag()
+
+	p.scaling4x4 = p.sps.scaling4x4
+	p.scaling8x8 = p.sps.scaling8x8
+
+	if r.moreRBSPData() {
+		p.transform8x8 = r.flag()
+		if r.flag() {
+			n8x8 := 0
+			if p.transform8x8 {
+				n8x8 = 2
+				if p.sps.chromaFormatIDC == 3 {
+					n8x8 = 6
+				}
+			}
+			var fallback *sps
+			if p.sps.scalingPresent {
+				fallback = p.sps
+			}
+			parseScalingMatrix(r, n8x8, &p.scaling4x4, &p.scaling8x8, fallback)
+		}
+		p.chromaQPOffset[1] = int(r.se())
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	p.initLevelScale()
+	return p, nil
+}
+
+// initLevelScale calculates the LevelScale4x4 and LevelScale8x8
+// functions (8.5.9) for the intra scaling lists of the PPS.
+func (p *pps) initLevelScale() {
+	v4x4 := [6][3]int32{
+		{10, 16, 13},
+		{11, 18, 14},
+		{13, 20, 16},
+		{14, 23, 18},
+		{16, 25, 20},
+		{18, 29, 23},
+	}
+	v8x8 := [6][6]int32{
+		{20, 18, 32, 19, 25, 24},
+		{22, 19, 35, 21, 28, 26},
+		{26, 23, 42, 24, 33, 31},
+		{28, 25, 45, 26, 35, 33},
+		{32, 28, 51, 30, 40, 38},
+		{36, 32, 58, 34, 46, 43},
+	}
+
+	for m := 0; m < 6; m++ {
+		for k := 0; k < 16; k++ {
+			pos := zigzag4x4[k]
+			i, j := pos/4, pos%4
+			var v int32
+			switch {
+			case i%2 == 0 && j%2 == 0:
+				v = v4x4[m][0]
+			case i%2 == 1 && j%2 == 1:
+				v = v4x4[m][1]
+			default:
+				v = v4x4[m][2]
+			}
+			for c := 0; c < 3; c++ {
+				p.levelScale4x4[c][m][pos] = int32(p.scaling4x4[c][k]) * v
+			}
+		}
+
+		for k := 0; k < 64; k++ {
+			pos := zigzag8x8[k]
+			i, j := pos/8, pos%8
+			var v int32
+			switch {
+			case i%4 == 0 && j%4 == 0:
+				v = v8x8[m][0]
+			case i%2 == 1 && j%2 == 1:
+				v = v8x8[m][1]
+			case i%4 == 2 && j%4 == 2:
+				v = v8x8[m][2]
+			case (i%4 == 0 && j%2 == 1) || (i%2 == 1 && j%4 == 0):
+				v = v8x8[m][3]
+			case (i%4 == 0 && j%4 == 2) || (i%4 == 2 && j%4 == 0):
+				v = v8x8[m][4]
+			default:
+				v = v8x8[m][5]
+			}
+			p.levelScale8x8[m][pos] = int32(p.scaling8x8[0][k]) * v
+		}
+	}
+}
@@ -0,0 +1,590 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package h264
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Macroblock types of I slices (Table 7-11),
+// values in between are the Intra_16x16 types.
+const (
+	mbTypeINxN = 0
+	mbTypeIPCM = 25
+)
+
+// Residual block types, and ctxBlockCat
+// values used by CABAC (Table 9-42).
+const (
+	catLumaDC = iota
+	catLumaAC
+	catLuma4x4
+	catChromaDC
+	catChromaAC
+	catLuma8x8
+)
+
+// blkIdx4x4 maps 4x4 luma block raster positions
+// within a macroblock to their decoding order.
+var blkIdx4x4 = [16]int{0, 1, 4, 5, 2, 3, 6, 7, 8, 9, 12, 13, 10, 11, 14, 15}
+
+// macroblock holds the state of a decoded macroblock
+// needed to decode its neighbours and deblock it.
+type macroblock struct {
+	slice          int // 1-based index of containing slice, 0 if not yet decoded
+	mbType         int
+	transform8x8   bool
+	qp             int
+	qpDelta        int
+	cbp            int // luma in bits 0-3, chroma in bits 4-5
+	chromaPredMode int
+
+	// predModes holds the Intra_4x4 or Intra_8x8
+	// prediction modes by 4x4 block raster position.
+	predModes [16]int8
+
+	// nz holds the number of non-zero coefficients
+	// of each 4x4 block of luma, Cb and Cr, by raster
+	// position (2x2 for chroma). For Intra_16x16 this
+	// only counts AC coefficients.
+	nz [3][16]uint8
+
+	// dcCoded holds the coded_block_flag of
+	// the luma (Intra_16x16), Cb and Cr DC blocks.
+	dcCoded [3]bool
+}
+
+// isI16x16 returns whether macroblock is of an Intra_16x16 type.
+func (mb *macroblock) isI16x16() bool {
+	return mb.mbType > mbTypeINxN && mb.mbType < mbTypeIPCM
+}
+
+// entropyDecoder parses macroblock layer syntax
+// elements, using either CAVLC or CABAC.
+type entropyDecoder interface {
+	mbType() int
+	transformSize8x8Flag() bool
+	prevIntraPredModeFlag() bool
+	remIntraPredMode() int
+	intraChromaPredMode() int
+	codedBlockPattern() int
+	mbQPDelta() int
+
+	// residualBlock parses the coefficients of a residual block in
+	// scan order into coeffs, returning the number that are non-zero.
+	// The comp, bx and by arguments give the colour component and 4x4
+	// block position within the macroblock of the block being parsed.
+	residualBlock(coeffs []int32, cat int, comp int, bx, by int) int
+
+	// pcmDone is called after reading I_PCM samples.
+	pcmDone()
+
+	// endOfSlice returns whether the last
+	// macroblock of the slice has been parsed.
+	endOfSlice() bool
+}
+
+// residual holds the parsed residual
+// coefficients of a macroblock in scan order.
+type residual struct {
+	lumaDC   [16]int32
+	luma     [16][16]int32 // by 4x4 block raster position
+	luma8x8  [4][64]int32
+	chromaDC [2][4]int32
+	chromaAC [2][4][16]int32 // by 4x4 block raster position
+}
+
+// sliceDecoder decodes the macroblocks of a slice.
+type sliceDecoder struct {
+	pic *picture
+	hdr *sliceHeader
+	num int
+	r   *bitReader
+	ed  entropyDecoder
+
+	curr     int // address of current macroblock
+	mbx, mby int // position of current macroblock
+	qp       int // QPY of the previous macroblock
+	res      residual
+}
+
+// decode decodes all macroblocks of the slice.
+func (sd *sliceDecoder) decode() error {
+	if sd.hdr.pps.cabac {
+		// Skip cabac_alignment_one_bit.
+		sd.r.align()
+		sd.ed = newCABAC(sd)
+	} else {
+		sd.ed = &cavlc{sd: sd, r: sd.r}
+	}
+
+	for addr := sd.hdr.firstMB; ; addr++ {
+		if addr >= len(sd.pic.mbs) {
+			return errors.New("h264: slice overruns picture")
+		}
+		if sd.pic.mbs[addr].slice != 0 {
+			return errors.New("h264: overlapping slices")
+		}
+
+		sd.curr = addr
+		sd.mbx = addr % sd.pic.width
+		sd.mby = addr / sd.pic.width
+		sd.decodeMacroblock()
+
+		if sd.r.err != nil {
+			return fmt.Errorf("h264: error decoding macroblock %d: %w", addr, sd.r.err)
+		}
+
+		if sd.ed.endOfSlice() {
+			return sd.r.err
+		}
+	}
+}
+
+// fail records an error decoding the slice,
+// which stops all further reads from the slice.
+func (sd *sliceDecoder) fail(err error) {
+	if sd.r.err == nil {
+		sd.r.err = err
+	}
+	sd.r.pos = len(sd.r.buf) * 8
+}
+
+// mbAt returns the macroblock at given position if it's
+// available for prediction of the current macroblock.
+func (sd *sliceDecoder) mbAt(mbx, mby int) *macroblock {
+	if mbx < 0 || mby < 0 || mbx >= sd.pic.width || mby >= sd.pic.height {
+		return nil
+	}
+	mb := &sd.pic.mbs[mby*sd.pic.width+mbx]
+	if mb.slice != sd.num {
+		return nil
+	}
+	return mb
+}
+
+// mbA returns the macroblock to the left, if available.
+func (sd *sliceDecoder) mbA() *macroblock { return sd.mbAt(sd.mbx-1, sd.mby) }
+
+// mbB returns the macroblock above, if available.
+func (sd *sliceDecoder) mbB() *macroblock { return sd.mbAt(sd.mbx, sd.mby-1) }
+
+// blockA returns the macroblock containing the block to the left
+// of block (bx, by) of the current macroblock, and that block's
+// raster index, for a component that's size x size blocks wide.
+func (sd *sliceDecoder) blockA(bx, by, size int) (*macroblock, int) {
+	if bx > 0 {
+		return sd.mbAt(sd.mbx, sd.mby), by*size + bx - 1
+	}
+	return sd.mbA(), by*size + size - 1
+}
+
+// blockB returns the macroblock containing the block above
+// block (bx, by) of the current macroblock, and that block's
+// raster index, for a component that's size x size blocks wide.
+func (sd *sliceDecoder) blockB(bx, by, size int) (*macroblock, int) {
+	if by > 0 {
+		return sd.mbAt(sd.mbx, sd.mby), (by-1)*size + bx
+	}
+	return sd.mbB(), (size-1)*size + bx
+}
+
+// decodeMacroblock parses and reconstructs the current macroblock.
+func (sd *sliceDecoder) decodeMacroblock() {
+	mb := &sd.pic.mbs[sd.curr]
+	*mb = macroblock{slice: sd.num}
+	sd.res = residual{}
+
+	mb.mbType = sd.ed.mbType()
+	if mb.mbType < 0 || mb.mbType > mbTypeIPCM {
+		sd.fail(fmt.Errorf("invalid mb_type %d", mb.mbType))
+		return
+	}
+
+	if mb.mbType == mbTypeIPCM {
+		sd.decodePCM(mb)
+		return
+	}
+
+	chroma := sd.pic.sps.chromaFormatIDC != 0
+
+	if mb.mbType == mbTypeINxN {
+		if sd.hdr.pps.transform8x8 {
+			mb.transform8x8 = sd.ed.transformSize8x8Flag()
+		}
+
+		// Parse prediction modes.
+		if mb.transform8x8 {
+			for blk := 0; blk < 4; blk++ {
+				x4, y4 := (blk&1)*2, (blk>>1)*2
+				mode := sd.intraPredMode(x4, y4)
+				for _, i := range []int{0, 1, 4, 5} {
+					mb.predModes[y4*4+x4+i] = int8(mode)
+				}
+			}
+		} else {
+			for blk := 0; blk < 16; blk++ {
+				x4 := (blk>>2&1)*2 + blk&1
+				y4 := (blk>>3)*2 + blk>>1&1
+				mb.predModes[y4*4+x4] = int8(sd.intraPredMode(x4, y4))
+			}
+		}
+	}
+
+	if chroma {
+		mb.chromaPredMode = sd.ed.intraChromaPredMode()
+		if mb.chromaPredMode > predChromaPlane {
+			sd.fail(fmt.Errorf("invalid intra_chroma_pred_mode %d", mb.chromaPredMode))
+			return
+		}
+	}
+
+	if mb.isI16x16() {
+		t := mb.mbType - 1
+		mb.cbp = (t / 4 % 3) << 4
+		if t >= 12 {
+			mb.cbp |= 15
+		}
+	} else {
+		mb.cbp = sd.ed.codedBlockPattern()
+	}
+
+	if mb.cbp != 0 || mb.isI16x16() {
+		mb.qpDelta = sd.ed.mbQPDelta()
+		if mb.qpDelta < -26 || mb.qpDelta > 25 {
+			sd.fail(fmt.Errorf("invalid mb_qp_delta %d", mb.qpDelta))
+			return
+		}
+		sd.qp = (sd.qp + mb.qpDelta + 52) % 52
+	}
+	mb.qp = sd.qp
+
+	if sd.hdr.pps.sps.transformBypass && mb.qp == 0 {
+		sd.fail(fmt.Errorf("%w: lossless macroblocks", ErrUnsupported))
+		return
+	}
+
+	sd.parseResidual(mb, chroma)
+	if sd.r.err != nil {
+		return
+	}
+
+	sd.reconstructLuma(mb)
+	if chroma {
+		sd.reconstructChroma(mb)
+	}
+}
+
+// intraPredMode parses the prediction mode of the Intra_4x4 or
+// Intra_8x8 block with top-left 4x4 block at (x4, y4) (8.3.1.1).
+func (sd *sliceDecoder) intraPredMode(x4, y4 int) int {
+	mode := predDC
+
+	mbA, a := sd.blockA(x4, y4, 4)
+	mbB, b := sd.blockB(x4, y4, 4)
+	if mbA != nil && mbB != nil {
+		modeA, modeB := predDC, predDC
+		if mbA.mbType == mbTypeINxN {
+			modeA = int(mbA.predModes[a])
+		}
+		if mbB.mbType == mbTypeINxN {
+			modeB = int(mbB.predModes[b])
+		}
+		mode = modeA
+		if modeB < mode {
+			mode = modeB
+		}
+	}
+
+	if sd.ed.prevIntraPredModeFlag() {
+		return mode
+	}
+
+	if rem := sd.ed.remIntraPredMode(); rem < mode {
+		return rem
+	} else {
+		return rem + 1
+	}
+}
+
+// parseResidual parses the residual coefficients of macroblock.
+func (sd *sliceDecoder) parseResidual(mb *macroblock, chroma bool) {
+	cabac := sd.hdr.pps.cabac
+	res := &sd.res
+
+	if mb.isI16x16() {
+		n := sd.ed.residualBlock(res.lumaDC[:], catLumaDC, 0, 0, 0)
+		mb.dcCoded[0] = n > 0
+	}
+
+	for blk8 := 0; blk8 < 4; blk8++ {
+		if mb.cbp&(1<<blk8) == 0 {
+			continue
+		}
+
+		x8, y8 := (blk8&1)*2, (blk8>>1)*2
+		if mb.transform8x8 && cabac {
+			n := sd.ed.residualBlock(res.luma8x8[blk8][:], catLuma8x8, 0, x8, y8)
+			for _, i := range []int{0, 1, 4, 5} {
+				mb.nz[0][y8*4+x8+i] = uint8(n)
+			}
+			continue
+		}
+
+		for blk4 := 0; blk4 < 4; blk4++ {
+			x4, y4 := x8+blk4&1, y8+blk4>>1
+			pos := y4*4 + x4
+
+			var n int
+			switch {
+			case mb.transform8x8:
+				// CAVLC interleaves 8x8 block
+				// coefficients in four 4x4 blocks.
+				var coeffs [16]int32
+				n = sd.ed.residualBlock(coeffs[:], catLuma4x4, 0, x4, y4)
+				for i, c := range coeffs {
+					res.luma8x8[blk8][4*i+blk4] = c
+				}
+			case mb.isI16x16():
+				n = sd.ed.residualBlock(res.luma[pos][1:], catLumaAC, 0, x4, y4)
+			default:
+				n = sd.ed.residualBlock(res.luma[pos][:], catLuma4x4, 0, x4, y4)
+			}
+			mb.nz[0][pos] = uint8(n)
+		}
+	}
+
+	if !chroma {
+		return
+	}
+
+	if mb.cbp>>4 != 0 {
+		for c := 0; c < 2; c++ {
+			n := sd.ed.residualBlock(res.chromaDC[c][:], catChromaDC, c+1, 0, 0)
+			mb.dcCoded[c+1] = n > 0
+		}
+	}
+
+	if mb.cbp>>4 == 2 {
+		for c := 0; c < 2; c++ {
+			for blk := 0; blk < 4; blk++ {
+				n := sd.ed.residualBlock(res.chromaAC[c][blk][1:], catChromaAC, c+1, blk&1, blk>>1)
+				mb.nz[c+1][blk] = uint8(n)
+			}
+		}
+	}
+}
+
+// decodePCM reads the samples of an I_PCM macroblock.
+func (sd *sliceDecoder) decodePCM(mb *macroblock) {
+	mb.qp = sd.qp
+	mb.cbp = 0x2f
+	for c := range mb.nz {
+		for i := range mb.nz[c] {
+			mb.nz[c][i] = 16
+		}
+		mb.dcCoded[c] = true
+	}
+
+	// Skip pcm_alignment_zero_bit.
+	sd.r.align()
+
+	pic := sd.pic
+	stride := pic.width * 16
+	off := sd.mby*16*stride + sd.mbx*16
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			pic.luma[off+y*stride+x] = uint8(sd.r.u(8))
+		}
+	}
+
+	if pic.sps.chromaFormatIDC != 0 {
+		stride /= 2
+		off = sd.mby*8*stride + sd.mbx*8
+		for _, plane := range [][]uint8{pic.cb, pic.cr} {
+			for y := 0; y < 8; y++ {
+				for x := 0; x < 8; x++ {
+					plane[off+y*stride+x] = uint8(sd.r.u(8))
+				}
+			}
+		}
+	}
+
+	sd.ed.pcmDone()
+}
+
+// neighbours gathers the reference samples for intra prediction of
+// the n x n block at (bx, by) within the current macroblock, from
+// a plane of given stride with mbSize x mbSize sample macroblocks.
+func (sd *sliceDecoder) neighbours(plane []uint8, stride, mbSize int, bx, by, n int, hasTopRight bool) *neighbours {
+	nb := &neighbours{
+		top:     make([]int32, 2*n+1),
+		left:    make([]int32, n+1),
+		hasTop:  by > 0 || sd.mbB() != nil,
+		hasLeft: bx > 0 || sd.mbA() != nil,
+	}
+
+	switch {
+	case bx > 0 && by > 0:
+		nb.hasTopLeft = true
+	case bx > 0:
+		nb.hasTopLeft = nb.hasTop
+	case by > 0:
+		nb.hasTopLeft = nb.hasLeft
+	default:
+		nb.hasTopLeft = sd.mbAt(sd.mbx-1, sd.mby-1) != nil
+	}
+
+	x0 := sd.mbx*mbSize + bx
+	y0 := sd.mby*mbSize + by
+
+	if nb.hasTop {
+		row := plane[(y0-1)*stride+x0:]
+		for x := 0; x < n; x++ {
+			nb.top[x+1] = int32(row[x])
+		}
+		for x := n; x < 2*n; x++ {
+			if hasTopRight {
+				nb.top[x+1] = int32(row[x])
+			} else {
+				nb.top[x+1] = nb.top[n]
+			}
+		}
+	}
+
+	if nb.hasLeft {
+		for y := 0; y < n; y++ {
+			nb.left[y+1] = int32(plane[(y0+y)*stride+x0-1])
+		}
+	}
+
+	if nb.hasTopLeft {
+		nb.top[0] = int32(plane[(y0-1)*stride+x0-1])
+		nb.left[0] = nb.top[0]
+	}
+
+	return nb
+}
+
+// hasTopRight returns whether the samples above and to the right of the
+// n x n luma block at (bx, by) of the current macroblock are available.
+func (sd *sliceDecoder) hasTopRight(bx, by, n int) bool {
+	switch {
+	case by == 0 && bx+n < 16:
+		return sd.mbB() != nil
+	case by == 0:
+		return sd.mbAt(sd.mbx+1, sd.mby-1) != nil
+	case bx+n >= 16:
+		return false
+	case n == 4:
+		// Available if already decoded.
+		return blkIdx4x4[(by/4-1)*4+bx/4+1] < blkIdx4x4[by+bx/4]
+	default:
+		return true
+	}
+}
+
+// reconstructLuma predicts the luma samples of macroblock, and
+// adds the transformed residual to them (8.3.1 - 8.3.3, 8.5).
+func (sd *sliceDecoder) reconstructLuma(mb *macroblock) {
+	pic := sd.pic
+	pps := sd.hdr.pps
+	stride := pic.width * 16
+	mbOff := sd.mby*16*stride + sd.mbx*16
+	ls := &pps.levelScale4x4[0][mb.qp%6]
+
+	switch {
+	case mb.isI16x16():
+		nb := sd.neighbours(pic.luma, stride, 16, 0, 0, 16, false)
+		pred16x16(pic.luma[mbOff:], stride, (mb.mbType-1)%4, nb)
+
+		dc := lumaDC(&sd.res.lumaDC, ls[0], mb.qp)
+		for pos := 0; pos < 16; pos++ {
+			var d [16]int32
+			d[0] = dc[pos]
+			scale4x4(&d, &sd.res.luma[pos], ls, mb.qp, true)
+			off := mbOff + (pos/4)*4*stride + (pos%4)*4
+			idct4x4(pic.luma[off:], stride, &d)
+		}
+
+	case mb.transform8x8:
+		for blk := 0; blk < 4; blk++ {
+			bx, by := (blk&1)*8, (blk>>1)*8
+			off := mbOff + by*stride + bx
+
+			nb := sd.neighbours(pic.luma, stride, 16, bx, by, 8, sd.hasTopRight(bx, by, 8))
+			nb.filter8x8()
+			predNxN(pic.luma[off:], stride, 8, int(mb.predModes[by+bx/4]), nb)
+
+			if mb.cbp&(1<<blk) != 0 {
+				var d [64]int32
+				scale8x8(&d, &sd.res.luma8x8[blk], &pps.levelScale8x8[mb.qp%6], mb.qp)
+				idct8x8(pic.luma[off:], stride, &d)
+			}
+		}
+
+	default:
+		for blk := 0; blk < 16; blk++ {
+			x4 := (blk>>2&1)*2 + blk&1
+			y4 := (blk>>3)*2 + blk>>1&1
+			bx, by := x4*4, y4*4
+			pos := y4*4 + x4
+			off := mbOff + by*stride + bx
+
+			nb := sd.neighbours(pic.luma, stride, 16, bx, by, 4, sd.hasTopRight(bx, by, 4))
+			predNxN(pic.luma[off:], stride, 4, int(mb.predModes[pos]), nb)
+
+			if mb.nz[0][pos] != 0 {
+				var d [16]int32
+				scale4x4(&d, &sd.res.luma[pos], ls, mb.qp, false)
+				idct4x4(pic.luma[off:], stride, &d)
+			}
+		}
+	}
+}
+
+// reconstructChroma predicts the chroma samples of macroblock,
+// and adds the transformed residual to them (8.3.4, 8.5).
+func (sd *sliceDecoder) reconstructChroma(mb *macroblock) {
+	pic := sd.pic
+	pps := sd.hdr.pps
+	stride := pic.width * 8
+	mbOff := sd.mby*8*stride + sd.mbx*8
+
+	for c, plane := range [][]uint8{pic.cb, pic.cr} {
+		nb := sd.neighbours(plane, stride, 8, 0, 0, 8, false)
+		predChroma(plane[mbOff:], stride, mb.chromaPredMode, nb)
+
+		if mb.cbp>>4 == 0 {
+			continue
+		}
+
+		qp := chromaQP[clip3(0, 51, mb.qp+pps.chromaQPOffset[c])]
+		ls := &pps.levelScale4x4[c+1][qp%6]
+		dc := chromaDC(&sd.res.chromaDC[c], ls[0], qp)
+
+		for blk := 0; blk < 4; blk++ {
+			var d [16]int32
+			d[0] = dc[blk]
+			scale4x4(&d, &sd.res.chromaAC[c][blk], ls, qp, true)
+			off := mbOff + (blk>>1)*4*stride + (blk&1)*4
+			idct4x4(plane[off:], stride, &d)
+		}
+	}
+}
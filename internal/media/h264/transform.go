@@ -0,0 +1,229 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package h264
+
+var (
+	// zigzag4x4 maps 4x4 zig-zag scan positions
+	// to raster positions within the block (Table 8-13).
+	zigzag4x4 = [16]int{0, 1, 4, 8, 5, 2, 3, 6, 9, 12, 13, 10, 7, 11, 14, 15}
+
+	// zigzag8x8 maps 8x8 zig-zag scan positions
+	// to raster positions within the block (Table 8-14).
+	zigzag8x8 = [64]int{
+		0, 1, 8, 16, 9, 2, 3, 10,
+		17, 24, 32, 25, 18, 11, 4, 5,
+		12, 19, 26, 33, 40, 48, 41, 34,
+		27, 20, 13, 6, 7, 14, 21, 28,
+		35, 42, 49, 56, 57, 50, 43, 36,
+		29, 22, 15, 23, 30, 37, 44, 51,
+		58, 59, 52, 45, 38, 31, 39, 46,
+		53, 60, 61, 54, 47, 55, 62, 63,
+	}
+
+	// chromaQP maps qPI to QPC (Table 8-15).
+	chromaQP = [52]int{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29,
+		29, 30, 31, 32, 32, 33, 34, 34, 35, 35, 36, 36, 37, 37,
+		37, 38, 38, 38, 39, 39, 39, 39,
+	}
+)
+
+// clip1 clips a sample value to the 8-bit range.
+func clip1(v int32) uint8 {
+	switch {
+	case v < 0:
+		return 0
+	case v > 255:
+		return 255
+	default:
+		return uint8(v)
+	}
+}
+
+// clip3 clips v to the range [lo, hi].
+func clip3(lo, hi, v int) int {
+	switch {
+	case v < lo:
+		return lo
+	case v > hi:
+		return hi
+	default:
+		return v
+	}
+}
+
+// scale4x4 scales the coefficients of a 4x4 block from scan order into
+// raster order (8.5.12.1), leaving d[0] untouched if it holds a DC value
+// that has already been scaled (Intra16x16 and chroma blocks).
+func scale4x4(d *[16]int32, c *[16]int32, ls *[16]int32, qp int, hasDC bool) {
+	start := 0
+	if hasDC {
+		start = 1
+	}
+	for k := start; k < 16; k++ {
+		if c[k] == 0 {
+			continue
+		}
+		pos := zigzag4x4[k]
+		if qp >= 24 {
+			d[pos] = (c[k] * ls[pos]) << uint(qp/6-4)
+		} else {
+			d[pos] = (c[k]*ls[pos] + 1<<uint(3-qp/6)) >> uint(4-qp/6)
+		}
+	}
+}
+
+// scale8x8 scales the coefficients of an 8x8 block
+// from scan order into raster order (8.5.13.1).
+func scale8x8(d *[64]int32, c *[64]int32, ls *[64]int32, qp int) {
+	for k := 0; k < 64; k++ {
+		if c[k] == 0 {
+			continue
+		}
+		pos := zigzag8x8[k]
+		if qp >= 36 {
+			d[pos] = (c[k] * ls[pos]) << uint(qp/6-6)
+		} else {
+			d[pos] = (c[k]*ls[pos] + 1<<uint(5-qp/6)) >> uint(6-qp/6)
+		}
+	}
+}
+
+// lumaDC performs the inverse transform and scaling of Intra16x16 luma DC
+// coefficients (8.5.10), given in scan order, returning the DC values in
+// raster order of the 4x4 blocks they belong to.
+func lumaDC(c *[16]int32, ls int32, qp int) (dc [16]int32) {
+	var m [16]int32
+	for k := 0; k < 16; k++ {
+		m[zigzag4x4[k]] = c[k]
+	}
+
+	// Rows, then columns.
+	for i := 0; i < 4; i++ {
+		r := m[i*4 : i*4+4]
+		a, b, c, d := r[0]+r[1], r[0]-r[1], r[2]+r[3], r[2]-r[3]
+		r[0], r[1], r[2], r[3] = a+c, a-c, b-d, b+d
+	}
+	for j := 0; j < 4; j++ {
+		a, b := m[j]+m[4+j], m[j]-m[4+j]
+		c, d := m[8+j]+m[12+j], m[8+j]-m[12+j]
+		m[j], m[4+j], m[8+j], m[12+j] = a+c, a-c, b-d, b+d
+	}
+
+	for i, f := range m {
+		if qp >= 36 {
+			dc[i] = (f * ls) << uint(qp/6-6)
+		} else {
+			dc[i] = (f*ls + 1<<uint(5-qp/6)) >> uint(6-qp/6)
+		}
+	}
+	return
+}
+
+// chromaDC performs the inverse transform and scaling of
+// 4:2:0 chroma DC coefficients (8.5.11), given in raster order.
+func chromaDC(c *[4]int32, ls int32, qp int) (dc [4]int32) {
+	f := [4]int32{
+		c[0] + c[1] + c[2] + c[3],
+		c[0] - c[1] + c[2] - c[3],
+		c[0] + c[1] - c[2] - c[3],
+		c[0] - c[1] - c[2] + c[3],
+	}
+	for i := range f {
+		dc[i] = ((f[i] * ls) << uint(qp/6)) >> 5
+	}
+	return
+}
+
+// idct4x4 performs the inverse 4x4 transform (8.5.12.2) of scaled
+// coefficients d, adding the residual to the samples at dst.
+func idct4x4(dst []uint8, stride int, d *[16]int32) {
+	var h [16]int32
+	for i := 0; i < 4; i++ {
+		r := d[i*4 : i*4+4]
+		e0, e1 := r[0]+r[2], r[0]-r[2]
+		e2, e3 := r[1]>>1-r[3], r[1]+r[3]>>1
+		h[i*4], h[i*4+1], h[i*4+2], h[i*4+3] = e0+e3, e1+e2, e1-e2, e0-e3
+	}
+	for j := 0; j < 4; j++ {
+		e0, e1 := h[j]+h[8+j], h[j]-h[8+j]
+		e2, e3 := h[4+j]>>1-h[12+j], h[4+j]+h[12+j]>>1
+		h[j], h[4+j], h[8+j], h[12+j] = e0+e3, e1+e2, e1-e2, e0-e3
+	}
+	for y := 0; y < 4; y++ {
+		row := dst[y*stride : y*stride+4]
+		for x := range row {
+			row[x] = clip1(int32(row[x]) + (h[y*4+x]+32)>>6)
+		}
+	}
+}
+
+// idct8x8 performs the inverse 8x8 transform (8.5.13.2) of scaled
+// coefficients d, adding the residual to the samples at dst.
+func idct8x8(dst []uint8, stride int, d *[64]int32) {
+	var m [64]int32
+	transform := func(in func(int) int32, out func(int, int32)) {
+		e0 := in(0) + in(4)
+		e1 := -in(3) + in(5) - in(7) - in(7)>>1
+		e2 := in(0) - in(4)
+		e3 := in(1) + in(7) - in(3) - in(3)>>1
+		e4 := in(2)>>1 - in(6)
+		e5 := -in(1) + in(7) + in(5) + in(5)>>1
+		e6 := in(2) + in(6)>>1
+		e7 := in(3) + in(5) + in(1) + in(1)>>1
+
+		f0 := e0 + e6
+		f1 := e1 + e7>>2
+		f2 := e2 + e4
+		f3 := e3 + e5>>2
+		f4 := e2 - e4
+		f5 := e3>>2 - e5
+		f6 := e0 - e6
+		f7 := e7 - e1>>2
+
+		out(0, f0+f7)
+		out(1, f2+f5)
+		out(2, f4+f3)
+		out(3, f6+f1)
+		out(4, f6-f1)
+		out(5, f4-f3)
+		out(6, f2-f5)
+		out(7, f0-f7)
+	}
+
+	for i := 0; i < 8; i++ {
+		transform(
+			func(k int) int32 { return d[i*8+k] },
+			func(k int, v int32) { m[i*8+k] = v },
+		)
+	}
+	for j := 0; j < 8; j++ {
+		transform(
+			func(k int) int32 { return m[k*8+j] },
+			func(k int, v int32) { m[k*8+j] = v },
+		)
+	}
+
+	for y := 0; y < 8; y++ {
+		row := dst[y*stride : y*stride+8]
+		for x := range row {
+			row[x] = clip1(int32(row[x]) + (m[y*8+x]+32)>>6)
+		}
+	}
+}
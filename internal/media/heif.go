@@ -0,0 +1,923 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"math"
+
+	"github.com/disintegration/imaging"
+	"github.com/h2non/filetype"
+	"github.com/h2non/filetype/matchers"
+	"github.com/h2non/filetype/types"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/media/hevc"
+)
+
+// typeAVIF is the file type of AVIF images,
+// which filetype doesn't detect by itself.
+var typeAVIF = types.NewType(mimeAvif, mimeImageAvif)
+
+func init() {
+	filetype.AddMatcher(typeAVIF, isAVIF)
+}
+
+// heifMaxPixels is the maximum permitted size of a HEIF
+// image in pixels, to bound memory usage. This is enough
+// for the 48 megapixel photos of recent phones.
+const heifMaxPixels = 1 << 26
+
+// heifAlphaTypes are the auxiliary image
+// types denoting an image's alpha plane.
+var heifAlphaTypes = []string{
+	"urn:mpeg:hevc:2015:auxid:1",
+	"urn:mpeg:mpegB:cicp:systems:auxiliary:alpha",
+}
+
+var errHEIFInvalid = errors.New("invalid heif file")
+
+// heifFile contains the items of a HEIF (HEIC or AVIF) file,
+// as described by its meta box, see ISO/IEC 23008-12.
+type heifFile struct {
+	data    []byte // whole file
+	idat    []byte // item data box contents
+	primary uint32
+	items   map[uint32]*heifItem
+}
+
+// heifItem is a single item of a HEIF file, e.g.
+// a coded image, a derived image or metadata.
+type heifItem struct {
+	id      uint32
+	typ     string      // item type, e.g. "hvc1" or "grid"
+	content string      // content type of "mime" items
+	method  uint64      // construction method, 0 (file) or 1 (idat)
+	extents [][2]uint64 // offset and length of each extent
+	props   []heifProp  // associated properties in order
+	dimg    []uint32    // input images of derived images
+	auxl    uint32      // image this is an auxiliary image of
+}
+
+// heifProp is an item property box.
+type heifProp struct {
+	typ  string
+	data []byte
+}
+
+// isAVIF returns whether the given file header is that of an AVIF
+// image or image sequence, going by the brands of its ftyp box.
+func isAVIF(hdr []byte) bool {
+	if len(hdr) < 16 || string(hdr[4:8]) != "ftyp" {
+		return false
+	}
+
+	switch string(hdr[8:12]) {
+	case "avif", "avis":
+		return true
+	case "mif1", "msf1":
+	default:
+		return false
+	}
+
+	size := int(binary.BigEndian.Uint32(hdr))
+	if size > len(hdr) {
+		size = len(hdr)
+	}
+
+	// Check compatible brands.
+	for i := 16; i+4 <= size; i += 4 {
+		if string(hdr[i:i+4]) == "avif" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// decodeHEIF decodes the primary image of the given HEIF file. Where the
+// image can't be decoded, e.g. as it's AV1 coded, a blank image of its
+// dimensions is returned instead.
+func decodeHEIF(data []byte) (*gtsImage, error) {
+	h, err := parseHEIF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := h.primaryImage()
+	if err == nil {
+		return &gtsImage{image: img}, nil
+	}
+
+	item := h.items[h.primary]
+	if item == nil {
+		return nil, err
+	}
+
+	width, height := item.size()
+	if width == 0 || height == 0 {
+		return nil, err
+	}
+
+	log.Warnf(nil, "error decoding heif image, using blank image: %v", err)
+	return &gtsImage{image: item.transform(blankImage(width, height).image)}, nil
+}
+
+// transcodeHEIF decodes the primary image of the given HEIF file and
+// encodes it as a PNG where it has transparency, else as a JPEG, returning
+// a stream of the encoded image and its file type.
+func transcodeHEIF(data []byte) (io.Reader, types.Type, error) {
+	h, err := parseHEIF(data)
+	if err != nil {
+		return nil, types.Type{}, err
+	}
+
+	img, err := h.primaryImage()
+	if err != nil {
+		return nil, types.Type{}, err
+	}
+
+	if o, ok := img.(interface{ Opaque() bool }); ok && !o.Opaque() {
+		return (&gtsImage{image: img}).ToPNG(), matchers.TypePng, nil
+	}
+
+	return (&gtsImage{image: img}).ToJPEG(&jpeg.Options{
+		Quality: 90,
+	}), matchers.TypeJpeg, nil
+}
+
+// stripHEIFMetadata zeroes the data of any Exif or XMP metadata items of
+// the given HEIF file in place, leaving the file's structure untouched.
+func stripHEIFMetadata(data []byte) error {
+	h, err := parseHEIF(data)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range h.items {
+		if item.typ != "Exif" &&
+			(item.typ != "mime" || item.content != "application/rdf+xml") {
+			continue
+		}
+
+		src, err := h.source(item)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range item.extents {
+			b, err := extent(src, e)
+			if err != nil {
+				return err
+			}
+			for i := range b {
+				b[i] = 0
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseHEIF parses the meta box of the given HEIF file.
+func parseHEIF(data []byte) (*heifFile, error) {
+	h := heifFile{
+		data:  data,
+		items: make(map[uint32]*heifItem),
+	}
+
+	var meta []byte
+	if err := heifBoxes(data, func(typ string, b []byte) error {
+		if typ == "meta" && meta == nil {
+			meta = b
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if len(meta) < 4 {
+		return nil, errors.New("meta box not found")
+	}
+
+	// Properties and their associations are
+	// resolved once all boxes have been read.
+	var (
+		props []heifProp
+		assoc map[uint32][]int
+	)
+
+	if err := heifBoxes(meta[4:], func(typ string, b []byte) error {
+		switch typ {
+		case "hdlr":
+			r := heifReader{b: b}
+			r.skip(8)
+			if handler := r.fourCC(); r.err == nil && handler != "pict" {
+				return fmt.Errorf("unsupported handler type %q", handler)
+			}
+			return r.err
+
+		case "pitm":
+			r := heifReader{b: b}
+			h.primary = uint32(r.uint(r.idSize()))
+			return r.err
+
+		case "iinf":
+			return h.parseIINF(b)
+
+		case "iloc":
+			return h.parseILOC(b)
+
+		case "iref":
+			return h.parseIREF(b)
+
+		case "idat":
+			h.idat = b
+			return nil
+
+		case "iprp":
+			return heifBoxes(b, func(typ string, b []byte) error {
+				switch typ {
+				case "ipco":
+					return heifBoxes(b, func(typ string, b []byte) error {
+						props = append(props, heifProp{typ: typ, data: b})
+						return nil
+					})
+				case "ipma":
+					var err error
+					assoc, err = parseIPMA(b)
+					return err
+				}
+				return nil
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if h.primary == 0 {
+		// The primary item box is mandatory, but is
+		// missing from some single image files, so
+		// default to the first image item there.
+		for id, item := range h.items {
+			switch item.typ {
+			case "hvc1", "av01", "grid":
+				if h.primary == 0 || id < h.primary {
+					h.primary = id
+				}
+			}
+		}
+	}
+
+	for id, indices := range assoc {
+		item := h.items[id]
+		if item == nil {
+			continue
+		}
+		for _, i := range indices {
+			// Indices are 1-based,
+			// 0 meaning no property.
+			if i > 0 && i <= len(props) {
+				item.props = append(item.props, props[i-1])
+			}
+		}
+	}
+
+	return &h, nil
+}
+
+// primaryImage decodes the primary image, applying any alpha plane,
+// cropping, rotation and mirroring.
+func (h *heifFile) primaryImage() (image.Image, error) {
+	item := h.items[h.primary]
+	if item == nil {
+		return nil, fmt.Errorf("primary item %d not found", h.primary)
+	}
+
+	img, err := h.decodeItem(item)
+	if err != nil {
+		return nil, err
+	}
+
+	if alpha := h.alphaOf(item); alpha != nil {
+		mask, err := h.decodeItem(alpha)
+		if err != nil {
+			log.Warnf(nil, "error decoding heif alpha plane, ignoring: %v", err)
+		} else {
+			img = withAlpha(img, mask)
+		}
+	}
+
+	return item.transform(img), nil
+}
+
+// item returns the item with the given ID,
+// adding it to the file if it doesn't exist.
+func (h *heifFile) item(id uint32) *heifItem {
+	item := h.items[id]
+	if item == nil {
+		item = &heifItem{id: id}
+		h.items[id] = item
+	}
+	return item
+}
+
+// parseIINF parses the item info box.
+func (h *heifFile) parseIINF(b []byte) error {
+	r := heifReader{b: b}
+	r.skip(r.idSize()) // entry count
+	if r.err != nil {
+		return r.err
+	}
+
+	return heifBoxes(r.b, func(typ string, b []byte) error {
+		if typ != "infe" {
+			return nil
+		}
+
+		r := heifReader{b: b}
+		version := r.uint(1)
+		r.skip(3)
+		if version < 2 {
+			// Versions 0 and 1 predate
+			// item types, so are of no use.
+			return r.err
+		}
+
+		idSize := 2
+		if version > 2 {
+			idSize = 4
+		}
+
+		item := h.item(uint32(r.uint(idSize)))
+		r.skip(2) // protection index
+		item.typ = r.fourCC()
+		r.string() // item name
+		if item.typ == "mime" {
+			item.content = r.string()
+		}
+
+		return r.err
+	})
+}
+
+// parseILOC parses the item location box.
+func (h *heifFile) parseILOC(b []byte) error {
+	r := heifReader{b: b}
+	version := r.uint(1)
+	r.skip(3)
+
+	sizes := r.uint(2)
+	offsetSize := int(sizes >> 12)
+	lengthSize := int(sizes >> 8 & 0xf)
+	baseOffsetSize := int(sizes >> 4 & 0xf)
+	indexSize := 0
+	if version == 1 || version == 2 {
+		indexSize = int(sizes & 0xf)
+	}
+
+	idSize := 2
+	if version == 2 {
+		idSize = 4
+	}
+
+	count := r.uint(idSize)
+	for i := uint64(0); i < count && r.err == nil; i++ {
+		item := h.item(uint32(r.uint(idSize)))
+		if version == 1 || version == 2 {
+			item.method = r.uint(2) & 0xf
+		}
+		r.skip(2) // data reference index
+		base := r.uint(baseOffsetSize)
+
+		extents := r.uint(2)
+		item.extents = nil
+		for j := uint64(0); j < extents && r.err == nil; j++ {
+			r.skip(indexSize)
+			offset := r.uint(offsetSize)
+			length := r.uint(lengthSize)
+			item.extents = append(item.extents, [2]uint64{base + offset, length})
+		}
+	}
+
+	return r.err
+}
+
+// parseIREF parses the item reference box.
+func (h *heifFile) parseIREF(b []byte) error {
+	r := heifReader{b: b}
+	version := r.uint(1)
+	r.skip(3)
+	if r.err != nil {
+		return r.err
+	}
+
+	idSize := 2
+	if version > 0 {
+		idSize = 4
+	}
+
+	return heifBoxes(r.b, func(typ string, b []byte) error {
+		r := heifReader{b: b}
+		from := h.item(uint32(r.uint(idSize)))
+		count := r.uint(2)
+
+		var to []uint32
+		for i := uint64(0); i < count && r.err == nil; i++ {
+			to = append(to, uint32(r.uint(idSize)))
+		}
+
+		switch {
+		case r.err != nil:
+			return r.err
+		case typ == "dimg":
+			from.dimg = to
+		case typ == "auxl" && len(to) > 0:
+			from.auxl = to[0]
+		}
+
+		return nil
+	})
+}
+
+// parseIPMA parses the item property association box, returning
+// the 1-based property indices associated with each item ID.
+func parseIPMA(b []byte) (map[uint32][]int, error) {
+	r := heifReader{b: b}
+	version := r.uint(1)
+	flags := r.uint(3)
+
+	idSize := 2
+	if version > 0 {
+		idSize = 4
+	}
+
+	indexSize, indexMask := 1, uint64(0x7f)
+	if flags&1 != 0 {
+		indexSize, indexMask = 2, 0x7fff
+	}
+
+	assoc := make(map[uint32][]int)
+	count := r.uint(4)
+	for i := uint64(0); i < count && r.err == nil; i++ {
+		id := uint32(r.uint(idSize))
+		n := r.uint(1)
+		for j := uint64(0); j < n && r.err == nil; j++ {
+			// Top bit is the essential flag.
+			index := r.uint(indexSize) & indexMask
+			assoc[id] = append(assoc[id], int(index))
+		}
+	}
+
+	return assoc, r.err
+}
+
+// source returns the data an item's extents are offsets into.
+func (h *heifFile) source(item *heifItem) ([]byte, error) {
+	switch item.method {
+	case 0:
+		return h.data, nil
+	case 1:
+		return h.idat, nil
+	default:
+		return nil, fmt.Errorf("unsupported item construction method %d", item.method)
+	}
+}
+
+// itemData returns the data of the given item.
+func (h *heifFile) itemData(item *heifItem) ([]byte, error) {
+	src, err := h.source(item)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(item.extents) == 1 {
+		return extent(src, item.extents[0])
+	}
+
+	var data []byte
+	for _, e := range item.extents {
+		b, err := extent(src, e)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b...)
+	}
+
+	return data, nil
+}
+
+// extent returns the given offset and length extent of src,
+// where a length of 0 means the rest of src.
+func extent(src []byte, e [2]uint64) ([]byte, error) {
+	offset, length := e[0], e[1]
+	if offset > uint64(len(src)) {
+		return nil, errHEIFInvalid
+	}
+	if length == 0 {
+		length = uint64(len(src)) - offset
+	}
+	if length > uint64(len(src))-offset {
+		return nil, errHEIFInvalid
+	}
+	return src[offset : offset+length], nil
+}
+
+// alphaOf returns the alpha plane auxiliary image of item, if any.
+func (h *heifFile) alphaOf(item *heifItem) *heifItem {
+	for _, aux := range h.items {
+		if aux.auxl != item.id {
+			continue
+		}
+
+		auxC := aux.prop("auxC")
+		if auxC == nil {
+			continue
+		}
+
+		r := heifReader{b: auxC.data}
+		r.skip(4)
+		auxType := r.string()
+		for _, typ := range heifAlphaTypes {
+			if auxType == typ {
+				return aux
+			}
+		}
+	}
+	return nil
+}
+
+// decodeItem decodes the given coded or grid image item.
+func (h *heifFile) decodeItem(item *heifItem) (image.Image, error) {
+	switch item.typ {
+	case "hvc1":
+		return h.decodeHEVC(item)
+	case "grid":
+		return h.decodeGrid(item)
+	default:
+		return nil, fmt.Errorf("unsupported image item type %q", item.typ)
+	}
+}
+
+// decodeHEVC decodes the given HEVC coded image item.
+func (h *heifFile) decodeHEVC(item *heifItem) (image.Image, error) {
+	hvcC := item.prop("hvcC")
+	if hvcC == nil {
+		return nil, fmt.Errorf("item %d has no decoder configuration", item.id)
+	}
+
+	// The coded image must be the size given by
+	// its ispe property, which is then a cap on
+	// what the decoder can be made to allocate.
+	width, height := item.size()
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("item %d has no valid image size", item.id)
+	}
+
+	dec, err := hevc.NewDecoder(hvcC.data, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := h.itemData(item)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := dec.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+// decodeGrid decodes the given grid image item, which is
+// made up of its input images placed in rows and columns.
+func (h *heifFile) decodeGrid(item *heifItem) (image.Image, error) {
+	data, err := h.itemData(item)
+	if err != nil {
+		return nil, err
+	}
+
+	r := heifReader{b: data}
+	r.skip(1) // version
+	flags := r.uint(1)
+	rows := int(r.uint(1)) + 1
+	cols := int(r.uint(1)) + 1
+
+	fieldSize := 2
+	if flags&1 != 0 {
+		fieldSize = 4
+	}
+
+	width := int(r.uint(fieldSize))
+	height := int(r.uint(fieldSize))
+
+	switch {
+	case r.err != nil:
+		return nil, r.err
+	case width == 0 || height == 0 || width > heifMaxPixels ||
+		height > heifMaxPixels || width*height > heifMaxPixels:
+		return nil, fmt.Errorf("invalid grid dimensions %dx%d", width, height)
+	case len(item.dimg) != rows*cols:
+		return nil, fmt.Errorf("grid has %d tiles, expected %d", len(item.dimg), rows*cols)
+	}
+
+	var (
+		dst          *image.RGBA
+		tileW, tileH int
+	)
+
+	for i, id := range item.dimg {
+		tile := h.items[id]
+		if tile == nil || tile.typ != "hvc1" {
+			return nil, fmt.Errorf("invalid grid tile %d", id)
+		}
+
+		img, err := h.decodeHEVC(tile)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding grid tile %d: %w", id, err)
+		}
+
+		b := img.Bounds()
+		if dst == nil {
+			// All tiles share the same size,
+			// and must cover the whole image.
+			tileW, tileH = b.Dx(), b.Dy()
+			if width > cols*tileW || height > rows*tileH {
+				return nil, fmt.Errorf("grid tiles don't cover %dx%d image", width, height)
+			}
+			dst = image.NewRGBA(image.Rect(0, 0, width, height))
+		}
+
+		at := image.Pt(i%cols*tileW, i/cols*tileH)
+		draw.Draw(dst, b.Sub(b.Min).Add(at), img, b.Min, draw.Src)
+	}
+
+	return dst, nil
+}
+
+// prop returns the first associated property of given type, if any.
+func (item *heifItem) prop(typ string) *heifProp {
+	for i := range item.props {
+		if item.props[i].typ == typ {
+			return &item.props[i]
+		}
+	}
+	return nil
+}
+
+// size returns the image dimensions given by the item's ispe
+// property, before any transformations are applied.
+func (item *heifItem) size() (int, int) {
+	ispe := item.prop("ispe")
+	if ispe == nil {
+		return 0, 0
+	}
+
+	r := heifReader{b: ispe.data}
+	r.skip(4)
+	width, height := r.uint(4), r.uint(4)
+	if r.err != nil || width > heifMaxPixels || height > heifMaxPixels ||
+		width*height > heifMaxPixels {
+		return 0, 0
+	}
+
+	return int(width), int(height)
+}
+
+// transform applies the item's transformative properties, i.e. clean
+// aperture cropping, rotation and mirroring, to img in their given order.
+func (item *heifItem) transform(img image.Image) image.Image {
+	for _, prop := range item.props {
+		if len(prop.data) == 0 {
+			continue
+		}
+
+		switch prop.typ {
+		case "clap":
+			img = cleanAperture(img, prop.data)
+
+		case "irot":
+			// Anti-clockwise
+			// in 90° steps.
+			switch prop.data[0] & 3 {
+			case 1:
+				img = imaging.Rotate90(img)
+			case 2:
+				img = imaging.Rotate180(img)
+			case 3:
+				img = imaging.Rotate270(img)
+			}
+
+		case "imir":
+			// Mirrored about a vertical
+			// (0) or horizontal (1) axis.
+			if prop.data[0]&1 == 0 {
+				img = imaging.FlipH(img)
+			} else {
+				img = imaging.FlipV(img)
+			}
+		}
+	}
+	return img
+}
+
+// cleanAperture crops img to the clean aperture given by clap property data.
+func cleanAperture(img image.Image, clap []byte) image.Image {
+	r := heifReader{b: clap}
+	var v [8]float64
+	for i := range v {
+		if i%2 == 0 && i >= 4 {
+			// Offsets are signed.
+			v[i] = float64(int32(r.uint(4)))
+		} else {
+			v[i] = float64(r.uint(4))
+		}
+	}
+
+	if r.err != nil || v[1] == 0 || v[3] == 0 || v[5] == 0 || v[7] == 0 {
+		return img
+	}
+
+	var (
+		b       = img.Bounds()
+		width   = v[0] / v[1]
+		height  = v[2] / v[3]
+		centerX = v[4]/v[5] + float64(b.Dx()-1)/2
+		centerY = v[6]/v[7] + float64(b.Dy()-1)/2
+		left    = int(math.Round(centerX - (width-1)/2))
+		top     = int(math.Round(centerY - (height-1)/2))
+	)
+
+	rect := image.Rect(left, top,
+		left+int(math.Round(width)),
+		top+int(math.Round(height)),
+	).Add(b.Min).Intersect(b)
+	if rect.Empty() || rect == b {
+		return img
+	}
+
+	return imaging.Crop(img, rect)
+}
+
+// withAlpha returns img with its alpha channel set from the luma
+// of mask, which is resized to match img if its size differs.
+func withAlpha(img image.Image, mask image.Image) image.Image {
+	dst := imaging.Clone(img)
+	size := dst.Bounds().Size()
+	if mask.Bounds().Size() != size {
+		mask = imaging.Resize(mask, size.X, size.Y, imaging.Linear)
+	}
+
+	alpha := imaging.Clone(mask)
+	for i := 3; i < len(dst.Pix); i += 4 {
+		dst.Pix[i] = alpha.Pix[i-3]
+	}
+
+	return dst
+}
+
+// heifBoxes calls fn with the type and contents of each
+// box in b in turn, returning early if fn returns an error.
+func heifBoxes(b []byte, fn func(typ string, b []byte) error) error {
+	for len(b) > 0 {
+		if len(b) < 8 {
+			return errHEIFInvalid
+		}
+
+		size := uint64(binary.BigEndian.Uint32(b))
+		typ := string(b[4:8])
+		hdrSize := uint64(8)
+
+		switch size {
+		case 0:
+			// Box extends to
+			// the end of b.
+			size = uint64(len(b))
+		case 1:
+			if len(b) < 16 {
+				return errHEIFInvalid
+			}
+			size = binary.BigEndian.Uint64(b[8:])
+			hdrSize = 16
+		}
+
+		if size < hdrSize || size > uint64(len(b)) {
+			return errHEIFInvalid
+		}
+
+		if err := fn(typ, b[hdrSize:size]); err != nil {
+			return err
+		}
+
+		b = b[size:]
+	}
+	return nil
+}
+
+// heifReader reads big-endian fields from box contents,
+// storing an error rather than reading past the end.
+type heifReader struct {
+	b   []byte
+	err error
+}
+
+// uint reads an n byte unsigned integer, for n from 0 to 8.
+func (r *heifReader) uint(n int) uint64 {
+	if r.err != nil {
+		return 0
+	}
+
+	if n > len(r.b) {
+		r.err = errHEIFInvalid
+		return 0
+	}
+
+	var v uint64
+	for _, c := range r.b[:n] {
+		v = v<<8 | uint64(c)
+	}
+
+	r.b = r.b[n:]
+	return v
+}
+
+// idSize reads a full box version and flags,
+// returning the size of item IDs in that version.
+func (r *heifReader) idSize() int {
+	if version := r.uint(1); version > 0 {
+		r.skip(3)
+		return 4
+	}
+	r.skip(3)
+	return 2
+}
+
+// skip skips n bytes.
+func (r *heifReader) skip(n int) {
+	if r.err != nil {
+		return
+	}
+
+	if n > len(r.b) {
+		r.err = errHEIFInvalid
+		return
+	}
+
+	r.b = r.b[n:]
+}
+
+// fourCC reads a four character code.
+func (r *heifReader) fourCC() string {
+	if r.err != nil {
+		return ""
+	}
+
+	if len(r.b) < 4 {
+		r.err = errHEIFInvalid
+		return ""
+	}
+
+	s := string(r.b[:4])
+	r.b = r.b[4:]
+	return s
+}
+
+// string reads a null-terminated string.
+func (r *heifReader) string() string {
+	if r.err != nil {
+		return ""
+	}
+	for i, c := range r.b {
+		if c == 0 {
+			s := string(r.b[:i])
+			r.b = r.b[i+1:]
+			return s
+		}
+	}
+	r.err = errHEIFInvalid
+	return ""
+}
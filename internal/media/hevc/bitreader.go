@@ -0,0 +1,119 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package hevc
+
+import "errors"
+
+// errTruncated is returned when a read runs past
+// the end of the available bitstream data.
+var errTruncated = errors.New("hevc: truncated bitstream")
+
+// bitReader reads fixed and variable length
+// codes from an RBSP (raw byte sequence payload).
+type bitReader struct {
+	buf []byte
+	pos int // current position in bits
+	err error
+}
+
+// newBitReader returns a bitReader for the given RBSP.
+func newBitReader(rbsp []byte) *bitReader {
+	return &bitReader{buf: rbsp}
+}
+
+// unescapeRBSP removes emulation prevention bytes from
+// a NAL unit payload, returning the underlying RBSP.
+func unescapeRBSP(nal []byte) []byte {
+	rbsp := make([]byte, 0, len(nal))
+	zeros := 0
+	for _, b := range nal {
+		if zeros >= 2 && b == 0x03 {
+			// Skip emulation_prevention_three_byte.
+			zeros = 0
+			continue
+		}
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+		rbsp = append(rbsp, b)
+	}
+	return rbsp
+}
+
+// u1 reads a single bit.
+func (r *bitReader) u1() uint32 {
+	if r.pos >= len(r.buf)*8 {
+		r.err = errTruncated
+		return 0
+	}
+	b := r.buf[r.pos>>3] >> (7 - uint(r.pos&7)) & 1
+	r.pos++
+	return uint32(b)
+}
+
+// u reads an n bit unsigned integer, where n <= 32.
+func (r *bitReader) u(n int) uint32 {
+	var v uint32
+	for i := 0; i < n; i++ {
+		v = v<<1 | r.u1()
+	}
+	return v
+}
+
+// flag reads a single bit as a bool.
+func (r *bitReader) flag() bool {
+	return r.u1() == 1
+}
+
+// ue reads an unsigned Exp-Golomb code.
+func (r *bitReader) ue() uint32 {
+	zeros := 0
+	for r.u1() == 0 {
+		if r.err != nil || zeros == 32 {
+			r.err = errors.New("hevc: invalid exp-golomb code")
+			return 0
+		}
+		zeros++
+	}
+	return (1<<uint(zeros) - 1) + r.u(zeros)
+}
+
+// se reads a signed Exp-Golomb code.
+func (r *bitReader) se() int32 {
+	k := r.ue()
+	if k&1 == 1 {
+		return int32((k + 1) >> 1)
+	}
+	return -int32(k >> 1)
+}
+
+// skip skips n bits.
+func (r *bitReader) skip(n int) {
+	r.pos += n
+	if r.pos > len(r.buf)*8 {
+		r.pos = len(r.buf) * 8
+		r.err = errTruncated
+	}
+}
+
+// align skips to the next byte boundary.
+func (r *bitReader) align() {
+	r.pos = (r.pos + 7) &^ 7
+}
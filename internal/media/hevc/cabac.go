@@ -0,0 +1,346 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package hevc
+
+import "errors"
+
+var (
+	// rangeTabLPS holds rangeTabLps values by
+	// pStateIdx and qRangeIdx (Table 9-46).
+	rangeTabLPS = [64][4]uint8{
+		{128, 176, 208, 240}, {128, 167, 197, 227}, {128, 158, 187, 216}, {123, 150, 178, 205},
+		{116, 142, 169, 195}, {111, 135, 160, 185}, {105, 128, 152, 175}, {100, 122, 144, 166},
+		{95, 116, 137, 158}, {90, 110, 130, 150}, {85, 104, 123, 142}, {81, 99, 117, 135},
+		{77, 94, 111, 128}, {73, 89, 105, 122}, {69, 85, 100, 116}, {66, 80, 95, 110},
+		{62, 76, 90, 104}, {59, 72, 86, 99}, {56, 69, 81, 94}, {53, 65, 77, 89},
+		{51, 62, 73, 85}, {48, 59, 69, 80}, {46, 56, 66, 76}, {43, 53, 63, 72},
+		{41, 50, 59, 69}, {39, 48, 56, 65}, {37, 45, 54, 62}, {35, 43, 51, 59},
+		{33, 41, 48, 56}, {32, 39, 46, 53}, {30, 37, 43, 50}, {29, 35, 41, 48},
+		{27, 33, 39, 45}, {26, 31, 37, 43}, {24, 30, 35, 41}, {23, 28, 33, 39},
+		{22, 27, 32, 37}, {21, 26, 30, 35}, {20, 24, 29, 33}, {19, 23, 27, 31},
+		{18, 22, 26, 30}, {17, 21, 25, 28}, {16, 20, 23, 27}, {15, 19, 22, 25},
+		{14, 18, 21, 24}, {14, 17, 20, 23}, {13, 16, 19, 22}, {12, 15, 18, 21},
+		{12, 14, 17, 20}, {11, 14, 16, 19}, {11, 13, 15, 18}, {10, 12, 15, 17},
+		{10, 12, 14, 16}, {9, 11, 13, 15}, {9, 11, 12, 14}, {8, 10, 12, 14},
+		{8, 9, 11, 13}, {7, 9, 11, 12}, {7, 9, 10, 12}, {7, 8, 10, 11},
+		{6, 8, 9, 11}, {6, 7, 9, 10}, {6, 7, 8, 9}, {2, 2, 2, 2},
+	}
+
+	// transIdxLPS holds the state transitions after
+	// decoding the least probable symbol (Table 9-47).
+	transIdxLPS = [64]uint8{
+		0, 0, 1, 2, 2, 4, 4, 5, 6, 7, 8, 9, 9, 11, 11, 12,
+		13, 13, 15, 15, 16, 16, 18, 18, 19, 19, 21, 21, 22, 22, 23, 24,
+		24, 25, 26, 26, 27, 27, 28, 29, 29, 30, 30, 30, 31, 32, 32, 33,
+		33, 33, 34, 34, 35, 35, 35, 36, 36, 36, 37, 37, 37, 38, 38, 63,
+	}
+
+	// cabacInit holds the context variable initValues for
+	// I slices, indexed by ctxIdx (Tables 9-5 to 9-37).
+	cabacInit = func() (t [numContexts]uint8) {
+		set := func(start int, vals ...uint8) {
+			copy(t[start:], vals)
+		}
+		set(ctxSaoMerge, 153)
+		set(ctxSaoType, 200)
+		set(ctxSplitCU, 139, 141, 157)
+		set(ctxTransquantBypass, 154)
+		set(ctxCuQPDelta, 154, 154)
+		set(ctxPartMode, 184)
+		set(ctxPrevIntraLuma, 184)
+		set(ctxIntraChroma, 63)
+		set(ctxSplitTransform, 153, 138, 138)
+		set(ctxCbfLuma, 111, 141)
+		set(ctxCbfChroma, 94, 138, 182, 154, 154)
+		set(ctxTransformSkip, 139, 139)
+		last := []uint8{
+			110, 110, 124, 125, 140, 153, 125, 127, 140,
+			109, 111, 143, 127, 111, 79, 108, 123, 63,
+		}
+		set(ctxLastX, last...)
+		set(ctxLastY, last...)
+		set(ctxCodedSubBlock, 91, 171, 134, 141)
+		set(ctxSig,
+			111, 111, 125, 110, 110, 94, 124, 108, 124, 107, 125, 141, 179, 153,
+			125, 107, 125, 141, 179, 153, 125, 107, 125, 141, 179, 153, 125, 140,
+			139, 182, 182, 152, 136, 152, 136, 153, 136, 139, 111, 136, 139, 111,
+		)
+		set(ctxGreater1,
+			140, 92, 137, 138, 140, 152, 138, 139, 153, 74, 149, 92,
+			139, 107, 122, 152, 140, 179, 166, 182, 140, 227, 122, 197,
+		)
+		set(ctxGreater2, 138, 153, 136, 167, 152, 152)
+		return
+	}()
+)
+
+// Offsets of the context variables for each syntax element.
+const (
+	ctxSaoMerge         = 0
+	ctxSaoType          = 1
+	ctxSplitCU          = 2
+	ctxTransquantBypass = 5
+	ctxCuQPDelta        = 6
+	ctxPartMode         = 8
+	ctxPrevIntraLuma    = 9
+	ctxIntraChroma      = 10
+	ctxSplitTransform   = 11
+	ctxCbfLuma          = 14
+	ctxCbfChroma        = 16
+	ctxTransformSkip    = 21
+	ctxLastX            = 23
+	ctxLastY            = 41
+	ctxCodedSubBlock    = 59
+	ctxSig              = 63
+	ctxGreater1         = 105
+	ctxGreater2         = 129
+	numContexts         = 135
+)
+
+// cabacContext is a CABAC context variable.
+type cabacContext struct {
+	state uint8 // pStateIdx
+	mps   uint8 // valMps
+}
+
+// cabac is the CABAC arithmetic decoding
+// engine and context variables of a slice (9.3).
+type cabac struct {
+	r   *bitReader
+	rng uint32 // ivlCurrRange
+	off uint32 // ivlOffset
+	ctx [numContexts]cabacContext
+}
+
+// initContexts initialises the context
+// variables for given SliceQpY (9.3.2.2).
+func (c *cabac) initContexts(qp int) {
+	qp = clip3(0, 51, qp)
+	for i, v := range cabacInit {
+		m := int(v>>4)*5 - 45
+		n := int(v&15)<<3 - 16
+		pre := clip3(1, 126, (m*qp)>>4+n)
+		if pre <= 63 {
+			c.ctx[i] = cabacContext{state: uint8(63 - pre), mps: 0}
+		} else {
+			c.ctx[i] = cabacContext{state: uint8(pre - 64), mps: 1}
+		}
+	}
+}
+
+// start initialises the arithmetic decoding engine (9.3.2.5).
+func (c *cabac) start() {
+	c.rng = 510
+	c.off = c.r.u(9)
+	if c.off >= 510 && c.r.err == nil {
+		c.r.err = errors.New("hevc: invalid cabac offset")
+	}
+}
+
+// decision decodes a bin using given context variable (9.3.4.3.2).
+func (c *cabac) decision(ctxIdx int) int {
+	ctx := &c.ctx[ctxIdx]
+	lps := uint32(rangeTabLPS[ctx.state][(c.rng>>6)&3])
+	c.rng -= lps
+
+	var bin uint8
+	if c.off >= c.rng {
+		bin = 1 - ctx.mps
+		c.off -= c.rng
+		c.rng = lps
+		if ctx.state == 0 {
+			ctx.mps = 1 - ctx.mps
+		}
+		ctx.state = transIdxLPS[ctx.state]
+	} else {
+		bin = ctx.mps
+		if ctx.state < 62 {
+			ctx.state++
+		}
+	}
+
+	for c.rng < 256 {
+		c.rng <<= 1
+		c.off = c.off<<1 | c.r.u1()
+	}
+
+	return int(bin)
+}
+
+// bypass decodes a bin using the bypass decoding process (9.3.4.3.4).
+func (c *cabac) bypass() int {
+	c.off = c.off<<1 | c.r.u1()
+	if c.off >= c.rng {
+		c.off -= c.rng
+		return 1
+	}
+	return 0
+}
+
+// bypassBits decodes an n bit fixed length value of bypass bins.
+func (c *cabac) bypassBits(n int) int {
+	v := 0
+	for i := 0; i < n; i++ {
+		v = v<<1 | c.bypass()
+	}
+	return v
+}
+
+// terminate decodes a bin using the decoding process for binary
+// decisions before termination (9.3.4.3.5), as used for pcm_flag,
+// end_of_slice_segment_flag and end_of_subset_one_bit. After a bin
+// equal to 1, the bitstream is positioned after the last bit of the
+// arithmetic code, so is ready for byte alignment.
+func (c *cabac) terminate() int {
+	c.rng -= 2
+	if c.off >= c.rng {
+		return 1
+	}
+	for c.rng < 256 {
+		c.rng <<= 1
+		c.off = c.off<<1 | c.r.u1()
+	}
+	return 0
+}
+
+// saoTypeIdx decodes sao_type_idx_luma or sao_type_idx_chroma.
+func (c *cabac) saoTypeIdx() int {
+	if c.decision(ctxSaoType) == 0 {
+		return 0
+	}
+	return 1 + c.bypass()
+}
+
+// saoOffsetAbs decodes sao_offset_abs,
+// for samples of given bit depth.
+func (c *cabac) saoOffsetAbs(bitDepth int) int {
+	if bitDepth > 10 {
+		bitDepth = 10
+	}
+	max := 1<<uint(bitDepth-5) - 1
+	v := 0
+	for v < max && c.bypass() == 1 {
+		v++
+	}
+	return v
+}
+
+// partModeNxN decodes the part_mode of an intra coding
+// unit, returning whether it's PART_NxN.
+func (c *cabac) partModeNxN() bool {
+	return c.decision(ctxPartMode) == 0
+}
+
+// mpmIdx decodes mpm_idx.
+func (c *cabac) mpmIdx() int {
+	if c.bypass() == 0 {
+		return 0
+	}
+	return 1 + c.bypass()
+}
+
+// intraChromaPredMode decodes intra_chroma_pred_mode.
+func (c *cabac) intraChromaPredMode() int {
+	if c.decision(ctxIntraChroma) == 0 {
+		return 4
+	}
+	return c.bypassBits(2)
+}
+
+// cuQPDelta decodes cu_qp_delta_abs and cu_qp_delta_sign_flag.
+func (c *cabac) cuQPDelta() int {
+	v := 0
+	for v < 5 {
+		inc := 0
+		if v > 0 {
+			inc = 1
+		}
+		if c.decision(ctxCuQPDelta+inc) == 0 {
+			break
+		}
+		v++
+	}
+	if v == 5 {
+		v += c.expGolomb(0)
+	}
+	if v > 0 && c.bypass() == 1 {
+		return -v
+	}
+	return v
+}
+
+// expGolomb decodes a k-th order Exp-Golomb (EGk) bin string of bypass bins.
+func (c *cabac) expGolomb(k int) int {
+	v := 0
+	for c.bypass() == 1 {
+		v += 1 << uint(k)
+		k++
+		if k == 32 {
+			c.r.err = errors.New("hevc: invalid exp-golomb bin string")
+			return 0
+		}
+	}
+	return v + c.bypassBits(k)
+}
+
+// lastSigCoeffPrefix decodes last_sig_coeff_x_prefix or
+// last_sig_coeff_y_prefix, using the contexts from given offset.
+func (c *cabac) lastSigCoeffPrefix(ctxBase, log2Size, cIdx int) int {
+	var offset, shift int
+	if cIdx == 0 {
+		offset = 3*(log2Size-2) + (log2Size-1)>>2
+		shift = (log2Size + 1) >> 2
+	} else {
+		offset = 15
+		shift = log2Size - 2
+	}
+	max := log2Size<<1 - 1
+	v := 0
+	for v < max && c.decision(ctxBase+offset+v>>uint(shift)) == 1 {
+		v++
+	}
+	return v
+}
+
+// lastSigCoeff decodes the suffix, if any, of a last significant
+// coefficient position with given prefix, returning the position.
+func (c *cabac) lastSigCoeff(prefix int) int {
+	if prefix <= 3 {
+		return prefix
+	}
+	n := prefix>>1 - 1
+	return (1<<uint(n))*(2+prefix&1) + c.bypassBits(n)
+}
+
+// coeffAbsLevelRemaining decodes coeff_abs_level_remaining
+// with given Rice parameter (9.3.3.11).
+func (c *cabac) coeffAbsLevelRemaining(rice int) int {
+	prefix := 0
+	for c.bypass() == 1 {
+		prefix++
+		if prefix == 32 {
+			c.r.err = errors.New("hevc: invalid coeff_abs_level_remaining")
+			return 0
+		}
+	}
+	if prefix <= 3 {
+		return prefix<<uint(rice) + c.bypassBits(rice)
+	}
+	n := prefix - 3 + rice
+	return (1<<uint(prefix-3)+2)<<uint(rice) + c.bypassBits(n)
+}
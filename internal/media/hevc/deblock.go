@@ -0,0 +1,232 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package hevc
+
+var (
+	// betaTable holds β′ by Q (Table 8-12).
+	betaTable = [52]uint8{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 20, 22, 24,
+		26, 28, 30, 32, 34, 36, 38, 40, 42, 44, 46, 48, 50, 52, 54, 56,
+		58, 60, 62, 64,
+	}
+
+	// tcTable holds tC′ by Q (Table 8-12).
+	tcTable = [54]uint8{
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 3,
+		3, 3, 3, 4, 4, 4, 5, 5, 6, 6, 7, 8, 9, 10, 11, 13,
+		14, 16, 18, 20, 22, 24,
+	}
+)
+
+// deblock applies the deblocking filter to the picture (8.7.2),
+// filtering all vertical edges, and then all horizontal edges.
+// Since all blocks are intra coded, every transform block edge
+// on the 8x8 grid is filtered with a boundary strength of 2.
+func (pic *picture) deblock() {
+	for _, vertical := range []bool{true, false} {
+		for y := 0; y < pic.height[0]; y += 4 {
+			for x := 0; x < pic.width[0]; x += 4 {
+				if vertical && x%8 == 0 || !vertical && y%8 == 0 {
+					pic.filterEdge(x, y, vertical)
+				}
+			}
+		}
+	}
+}
+
+// filterEdge filters the edge of length 4 at the left
+// or top of the block at given luma sample, if it's a
+// transform block edge that should be filtered.
+func (pic *picture) filterEdge(x, y int, vertical bool) {
+	xP, yP, flag := x-1, y, uint8(blockEdgeV)
+	if !vertical {
+		xP, yP, flag = x, y-1, blockEdgeH
+	}
+	if xP < 0 || yP < 0 {
+		return
+	}
+
+	q, p := pic.blockAt(x, y), pic.blockAt(xP, yP)
+	if q.flags&flag == 0 {
+		return
+	}
+
+	ctbQ, ctbP := pic.ctbAt(x, y), pic.ctbAt(xP, yP)
+	hdr := pic.ctbs[ctbQ].hdr
+	if hdr.deblockingDisabled {
+		return
+	}
+	if ctbQ != ctbP {
+		if pic.ctbs[ctbP].hdr.sliceAddr != hdr.sliceAddr && !hdr.filterAcrossSlices {
+			return
+		}
+		if pic.tileOf(ctbP) != pic.tileOf(ctbQ) && !pic.pps.filterAcrossTiles {
+			return
+		}
+	}
+
+	filterP, filterQ := p.flags&blockNoFilter == 0, q.flags&blockNoFilter == 0
+	qpP, qpQ := int(p.qp), int(q.qp)
+	const bS = 2
+
+	// Luma samples are addressed relative to q0 of the first
+	// line, with step between samples across the edge, and
+	// stride between lines of the edge.
+	s := pic.sps
+	step, stride := 1, pic.width[0]
+	if !vertical {
+		step, stride = pic.width[0], 1
+	}
+	pic.filterLuma(pic.planes[0], y*pic.width[0]+x, step, stride, qpP, qpQ, bS, hdr, filterP, filterQ)
+
+	// Chroma edges are filtered on the 8x8 chroma sample grid.
+	if s.chromaFormatIDC == 0 || (vertical && x%16 != 0) || (!vertical && y%16 != 0) {
+		return
+	}
+	step, stride = 1, pic.width[1]
+	if !vertical {
+		step, stride = pic.width[1], 1
+	}
+	pos := (y/2)*pic.width[1] + x/2
+	for c, offset := range [2]int{pic.pps.cbQPOffset, pic.pps.crQPOffset} {
+		qpC := chromaQP((qpP+qpQ+1)>>1 + offset)
+		tc := int32(tcTable[clip3(0, 53, qpC+2*(bS-1)+hdr.tcOffset)]) << uint(s.bitDepthC-8)
+		plane := pic.planes[1+c]
+		max := int32(1)<<uint(s.bitDepthC) - 1
+		for k := 0; k < 2; k++ {
+			i := pos + k*stride
+			p0, p1 := int32(plane[i-step]), int32(plane[i-2*step])
+			q0, q1 := int32(plane[i]), int32(plane[i+step])
+			delta := int32(clip3(int(-tc), int(tc), int((((q0-p0)<<2)+p1-q1+4)>>3)))
+			if filterP {
+				plane[i-step] = uint16(clip32(0, max, p0+delta))
+			}
+			if filterQ {
+				plane[i] = uint16(clip32(0, max, q0-delta))
+			}
+		}
+	}
+}
+
+// filterLuma filters an edge segment of 4 lines of luma samples
+// (8.7.2.5.3, 8.7.2.5.6 and 8.7.2.5.7), where pos is the index of
+// sample q0 of the first line.
+func (pic *picture) filterLuma(plane []uint16, pos, step, stride, qpP, qpQ, bS int, hdr *sliceHeader, filterP, filterQ bool) {
+	s := pic.sps
+	qpL := (qpQ + qpP + 1) >> 1
+	beta := int32(betaTable[clip3(0, 51, qpL+hdr.betaOffset)]) << uint(s.bitDepthY-8)
+	tc := int32(tcTable[clip3(0, 53, qpL+2*(bS-1)+hdr.tcOffset)]) << uint(s.bitDepthY-8)
+	if tc == 0 && beta == 0 {
+		return
+	}
+
+	sample := func(line, i int) int32 {
+		return int32(plane[pos+line*stride+i*step])
+	}
+
+	// Decisions for the edge segment (8.7.2.5.3).
+	dp0 := abs32(sample(0, -3) - 2*sample(0, -2) + sample(0, -1))
+	dp3 := abs32(sample(3, -3) - 2*sample(3, -2) + sample(3, -1))
+	dq0 := abs32(sample(0, 2) - 2*sample(0, 1) + sample(0, 0))
+	dq3 := abs32(sample(3, 2) - 2*sample(3, 1) + sample(3, 0))
+	dpq0, dpq3 := dp0+dq0, dp3+dq3
+	dp, dq := dp0+dp3, dq0+dq3
+	if dpq0+dpq3 >= beta {
+		return
+	}
+
+	strong := func(line int, dpq int32) bool {
+		p0, p3 := sample(line, -1), sample(line, -4)
+		q0, q3 := sample(line, 0), sample(line, 3)
+		return 2*dpq < beta>>2 &&
+			abs32(p3-p0)+abs32(q0-q3) < beta>>3 &&
+			abs32(p0-q0) < (5*tc+1)>>1
+	}
+	dE := 1
+	if strong(0, dpq0) && strong(3, dpq3) {
+		dE = 2
+	}
+	dEp := dp < (beta+beta>>1)>>3
+	dEq := dq < (beta+beta>>1)>>3
+
+	max := int32(1)<<uint(s.bitDepthY) - 1
+	for line := 0; line < 4; line++ {
+		i := pos + line*stride
+		var p, q [4]int32
+		for k := 0; k < 4; k++ {
+			p[k] = int32(plane[i-(k+1)*step])
+			q[k] = int32(plane[i+k*step])
+		}
+
+		var np, nq [3]int32 // filtered samples
+		nDp, nDq := 0, 0
+		if dE == 2 {
+			tc2 := 2 * tc
+			np[0] = clip32(p[0]-tc2, p[0]+tc2, (p[2]+2*p[1]+2*p[0]+2*q[0]+q[1]+4)>>3)
+			np[1] = clip32(p[1]-tc2, p[1]+tc2, (p[2]+p[1]+p[0]+q[0]+2)>>2)
+			np[2] = clip32(p[2]-tc2, p[2]+tc2, (2*p[3]+3*p[2]+p[1]+p[0]+q[0]+4)>>3)
+			nq[0] = clip32(q[0]-tc2, q[0]+tc2, (p[1]+2*p[0]+2*q[0]+2*q[1]+q[2]+4)>>3)
+			nq[1] = clip32(q[1]-tc2, q[1]+tc2, (p[0]+q[0]+q[1]+q[2]+2)>>2)
+			nq[2] = clip32(q[2]-tc2, q[2]+tc2, (p[0]+q[0]+q[1]+3*q[2]+2*q[3]+4)>>3)
+			nDp, nDq = 3, 3
+		} else {
+			delta := (9*(q[0]-p[0]) - 3*(q[1]-p[1]) + 8) >> 4
+			if abs32(delta) >= tc*10 {
+				continue
+			}
+			delta = clip32(-tc, tc, delta)
+			np[0] = clip32(0, max, p[0]+delta)
+			nq[0] = clip32(0, max, q[0]-delta)
+			nDp, nDq = 1, 1
+			if dEp {
+				deltaP := clip32(-(tc >> 1), tc>>1, (((p[2]+p[0]+1)>>1)-p[1]+delta)>>1)
+				np[1] = clip32(0, max, p[1]+deltaP)
+				nDp = 2
+			}
+			if dEq {
+				deltaQ := clip32(-(tc >> 1), tc>>1, (((q[2]+q[0]+1)>>1)-q[1]-delta)>>1)
+				nq[1] = clip32(0, max, q[1]+deltaQ)
+				nDq = 2
+			}
+		}
+
+		if filterP {
+			for k := 0; k < nDp; k++ {
+				plane[i-(k+1)*step] = uint16(np[k])
+			}
+		}
+		if filterQ {
+			for k := 0; k < nDq; k++ {
+				plane[i+k*step] = uint16(nq[k])
+			}
+		}
+	}
+}
+
+// clip32 clamps v to the range [lo, hi].
+func clip32(lo, hi, v int32) int32 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
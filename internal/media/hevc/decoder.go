@@ -0,0 +1,668 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package hevc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+)
+
+// ErrNotIntra is returned when decoding a picture that
+// can't be decoded alone, since it is predicted from
+// other pictures in the stream.
+var ErrNotIntra = errors.New("hevc: picture is not intra coded")
+
+// NAL unit types (Table 7-1).
+const (
+	nalBLAWLP   = 16
+	nalIDRWRADL = 19
+	nalIDRNLP   = 20
+	nalCRA      = 21
+	nalRsvIRAP  = 23
+	nalVPS      = 32
+	nalSPS      = 33
+	nalPPS      = 34
+)
+
+// Decoder decodes individual intra coded pictures from
+// an HEVC elementary stream in the length-prefixed form
+// used by HEIF and MP4 containers.
+//
+// Only the subset of the standard needed to decode 4:2:0
+// (or monochrome) I pictures of up to 12 bits per sample
+// is supported, which covers the coded images of HEIF
+// files produced by phones and common encoders.
+type Decoder struct {
+	lengthSize int
+	width      int
+	height     int
+	sps        map[uint32]*sps
+	pps        map[uint32]*pps
+}
+
+// NewDecoder returns a new Decoder configured from the
+// given HEVCDecoderConfigurationRecord, as found in
+// the payload of an 'hvcC' box.
+//
+// Width and height are those given for the image or video by
+// its container, e.g. by an 'ispe' property. Any SPS for pictures
+// of another size is rejected, so that a stream can't have the
+// decoder allocate far more than its size suggests.
+func NewDecoder(config []byte, width int, height int) (*Decoder, error) {
+	if len(config) < 23 || config[0] != 1 {
+		return nil, errors.New("hevc: invalid decoder configuration record")
+	}
+
+	d := &Decoder{
+		lengthSize: int(config[21]&3) + 1,
+		width:      width,
+		height:     height,
+		sps:        make(map[uint32]*sps),
+		pps:        make(map[uint32]*pps),
+	}
+
+	// Parse parameter sets from record.
+	n := int(config[22])
+	b := config[23:]
+	for i := 0; i < n; i++ {
+		if len(b) < 3 {
+			return nil, errors.New("hevc: truncated decoder configuration record")
+		}
+		nalus := int(binary.BigEndian.Uint16(b[1:]))
+		b = b[3:]
+
+		for j := 0; j < nalus; j++ {
+			if len(b) < 2 {
+				return nil, errors.New("hevc: truncated decoder configuration record")
+			}
+			sz := int(binary.BigEndian.Uint16(b))
+			if len(b) < 2+sz || sz < 2 {
+				return nil, errors.New("hevc: truncated decoder configuration record")
+			}
+			if err := d.parameterSet(b[2 : 2+sz]); err != nil {
+				return nil, err
+			}
+			b = b[2+sz:]
+		}
+	}
+
+	return d, nil
+}
+
+// parameterSet parses and stores the given SPS or PPS NAL
+// unit, ignoring any other type of NAL unit, such as a VPS.
+func (d *Decoder) parameterSet(nal []byte) error {
+	if nalLayerID(nal) != 0 {
+		return nil
+	}
+
+	rbsp := unescapeRBSP(nal[2:])
+	switch nalType(nal) {
+	case nalSPS:
+		s, err := parseSPS(rbsp)
+		if err != nil {
+			return err
+		}
+		if !s.hasSize(d.width, d.height) {
+			return fmt.Errorf("hevc: sps picture size %dx%d doesn't match image size %dx%d",
+				s.width, s.height, d.width, d.height)
+		}
+		d.sps[s.id] = s
+	case nalPPS:
+		p, err := parsePPS(rbsp)
+		if err != nil {
+			return err
+		}
+		d.pps[p.id] = p
+	}
+	return nil
+}
+
+// nalType returns the nal_unit_type of a NAL unit.
+func nalType(nal []byte) int {
+	return int(nal[0]>>1) & 0x3f
+}
+
+// nalLayerID returns the nuh_layer_id of a NAL unit.
+func nalLayerID(nal []byte) int {
+	return int(nal[0]&1)<<5 | int(nal[1]>>3)
+}
+
+// Decode decodes the picture contained in the given sample (a
+// complete access unit of length-prefixed NAL units), returning
+// ErrNotIntra if the picture isn't coded using only I slices.
+func (d *Decoder) Decode(sample []byte) (*image.YCbCr, error) {
+	var pic *picture
+
+	for len(sample) > 0 {
+		if len(sample) < d.lengthSize {
+			return nil, errTruncated
+		}
+
+		var sz int
+		for i := 0; i < d.lengthSize; i++ {
+			sz = sz<<8 | int(sample[i])
+		}
+		sample = sample[d.lengthSize:]
+		if sz > len(sample) {
+			return nil, errTruncated
+		}
+
+		nal := sample[:sz]
+		sample = sample[sz:]
+		if len(nal) < 2 || nalLayerID(nal) != 0 {
+			continue
+		}
+
+		switch typ := nalType(nal); {
+		case typ == nalSPS || typ == nalPPS:
+			if err := d.parameterSet(nal); err != nil {
+				return nil, err
+			}
+
+		case typ <= 9 || typ >= nalBLAWLP && typ <= nalCRA:
+			if pic != nil && nal[2]&0x80 != 0 {
+				// The first_slice_segment_in_pic_flag of a
+				// following picture, only the first picture
+				// in the sample is decoded.
+				return pic.finish()
+			}
+			var err error
+			if pic, err = d.decodeSlice(pic, nal); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if pic == nil {
+		return nil, errors.New("hevc: sample contains no picture")
+	}
+
+	return pic.finish()
+}
+
+// decodeSlice decodes the given slice segment NAL
+// unit into picture, allocating the picture if the
+// slice segment is the first in the picture.
+func (d *Decoder) decodeSlice(pic *picture, nal []byte) (*picture, error) {
+	r := newBitReader(unescapeRBSP(nal[2:]))
+
+	first := r.flag()
+	if !first && pic == nil {
+		return nil, errors.New("hevc: slice segment without first slice segment of picture")
+	}
+
+	hdr, err := d.parseSliceHeader(r, pic, nalType(nal), first)
+	if err != nil {
+		return nil, err
+	}
+
+	if first {
+		if pic, err = newPicture(hdr.sps, hdr.pps); err != nil {
+			return nil, err
+		}
+	}
+
+	if hdr.segmentAddr >= len(pic.ctbs) {
+		return nil, fmt.Errorf("hevc: invalid slice_segment_address %d", hdr.segmentAddr)
+	}
+
+	if !hdr.dependent {
+		pic.hdr = hdr
+	}
+
+	sd := &sliceDecoder{
+		pic: pic,
+		hdr: hdr,
+		r:   r,
+	}
+
+	if err := sd.decode(); err != nil {
+		return nil, err
+	}
+
+	return pic, nil
+}
+
+// sliceHeader is a parsed slice_segment_header() of an I slice,
+// with the values of a dependent slice segment inferred from the
+// preceding independent slice segment.
+type sliceHeader struct {
+	sps                *sps
+	pps                *pps
+	dependent          bool
+	segmentAddr        int // slice_segment_address, in raster scan
+	sliceAddr          int // SliceAddrRs
+	saoLuma            bool
+	saoChroma          bool
+	qp                 int // SliceQpY
+	cbQPOffset         int
+	crQPOffset         int
+	deblockingDisabled bool
+	betaOffset         int
+	tcOffset           int
+	filterAcrossSlices bool
+}
+
+// parseSliceHeader parses a slice_segment_header(),
+// after its first_slice_segment_in_pic_flag.
+func (d *Decoder) parseSliceHeader(r *bitReader, pic *picture, typ int, first bool) (*sliceHeader, error) {
+	if typ >= nalBLAWLP && typ <= nalRsvIRAP {
+		r.flag() // no_output_of_prior_pics_flag
+	}
+
+	ppsID := r.ue()
+	p := d.pps[ppsID]
+	if p == nil {
+		return nil, fmt.Errorf("hevc: slice refers to unknown pps %d", ppsID)
+	}
+	s := d.sps[p.spsID]
+	if s == nil {
+		return nil, fmt.Errorf("hevc: pps refers to unknown sps %d", p.spsID)
+	}
+	if pic != nil && pic.pps != p {
+		return nil, errors.New("hevc: slice segments of picture refer to different pps")
+	}
+
+	hdr := new(sliceHeader)
+	if !first {
+		if p.dependentSlices {
+			hdr.dependent = r.flag()
+		}
+		hdr.segmentAddr = int(r.u(ceilLog2(s.widthCtbs * s.heightCtbs)))
+	}
+
+	if hdr.dependent {
+		if pic.hdr == nil {
+			return nil, errors.New("hevc: dependent slice segment without slice")
+		}
+
+		// Infer values from the slice header.
+		addr := hdr.segmentAddr
+		*hdr = *pic.hdr
+		hdr.dependent = true
+		hdr.segmentAddr = addr
+	} else {
+		hdr.sps = s
+		hdr.pps = p
+		hdr.sliceAddr = hdr.segmentAddr
+
+		r.skip(p.numExtraSliceBits) // slice_reserved_flag
+		switch sliceType := r.ue(); {
+		case sliceType > 2:
+			return nil, fmt.Errorf("hevc: invalid slice_type %d", sliceType)
+		case sliceType != 2:
+			return nil, ErrNotIntra
+		}
+
+		if p.outputFlagPresent {
+			r.flag() // pic_output_flag
+		}
+
+		if typ != nalIDRWRADL && typ != nalIDRNLP {
+			r.u(s.log2MaxPocLsb) // slice_pic_order_cnt_lsb
+			if !r.flag() {
+				// short_term_ref_pic_set_sps_flag = 0
+				if _, err := parseShortTermRPS(r, s.numShortTermRPS, s.numDeltaPocs, true); err != nil {
+					return nil, err
+				}
+			} else if s.numShortTermRPS > 1 {
+				r.u(ceilLog2(s.numShortTermRPS)) // short_term_ref_pic_set_idx
+			}
+			if s.longTermRefs {
+				numLtSPS := uint32(0)
+				if s.numLongTermRefs > 0 {
+					numLtSPS = r.ue()
+				}
+				numLt := r.ue()
+				if numLtSPS > uint32(s.numLongTermRefs) || numLt > 32 {
+					return nil, errors.New("hevc: invalid number of long-term pictures")
+				}
+				for i := uint32(0); i < numLtSPS+numLt; i++ {
+					if i < numLtSPS {
+						if s.numLongTermRefs > 1 {
+							r.u(ceilLog2(s.numLongTermRefs)) // lt_idx_sps
+						}
+					} else {
+						r.u(s.log2MaxPocLsb) // poc_lsb_lt
+						r.flag()             // used_by_curr_pic_lt_flag
+					}
+					if r.flag() {
+						r.ue() // delta_poc_msb_cycle_lt
+					}
+				}
+			}
+			if s.temporalMVP {
+				r.flag() // slice_temporal_mvp_enabled_flag
+			}
+		}
+
+		if s.sao {
+			hdr.saoLuma = r.flag()
+			if s.chromaFormatIDC != 0 {
+				hdr.saoChroma = r.flag()
+			}
+		}
+
+		hdr.qp = p.initQP + int(r.se())
+		if hdr.qp < -6*(s.bitDepthY-8) || hdr.qp > 51 {
+			return nil, fmt.Errorf("hevc: invalid slice qp %d", hdr.qp)
+		}
+
+		if p.sliceChromaQPOffsets {
+			hdr.cbQPOffset = int(r.se())
+			hdr.crQPOffset = int(r.se())
+		}
+
+		hdr.deblockingDisabled = p.deblockingDisabled
+		hdr.betaOffset = p.betaOffset
+		hdr.tcOffset = p.tcOffset
+		if p.deblockingOverride && r.flag() {
+			// deblocking_filter_override_flag = 1
+			hdr.deblockingDisabled = r.flag()
+			if !hdr.deblockingDisabled {
+				hdr.betaOffset = int(r.se()) * 2
+				hdr.tcOffset = int(r.se()) * 2
+			}
+		}
+
+		hdr.filterAcrossSlices = p.filterAcrossSlices
+		if p.filterAcrossSlices && (hdr.saoLuma || hdr.saoChroma || !hdr.deblockingDisabled) {
+			hdr.filterAcrossSlices = r.flag()
+		}
+	}
+
+	if p.tiles || p.entropySync {
+		// Entry points aren't needed, since
+		// substreams are decoded in order.
+		n := r.ue()
+		if n > 0 {
+			bits := int(r.ue()) + 1
+			if bits > 32 || n > 440 {
+				return nil, errors.New("hevc: invalid entry points")
+			}
+			r.skip(int(n) * bits)
+		}
+	}
+
+	if p.sliceHeaderExtension {
+		r.skip(int(r.ue()) * 8) // slice_segment_header_extension_data_byte
+	}
+
+	// byte_alignment()
+	if r.u1() != 1 {
+		r.err = errors.New("hevc: invalid slice header alignment")
+	}
+	r.align()
+
+	return hdr, r.err
+}
+
+// ceilLog2 returns Ceil(Log2(n)).
+func ceilLog2(n int) int {
+	bits := 0
+	for 1<<uint(bits) < n {
+		bits++
+	}
+	return bits
+}
+
+// Flags of a block.
+const (
+	blockNoFilter = 1 << iota // samples aren't modified by in-loop filters
+	blockEdgeV                // left edge is a transform block edge
+	blockEdgeH                // top edge is a transform block edge
+)
+
+// block holds the decoding state
+// of a 4x4 block of luma samples.
+type block struct {
+	depth uint8 // CtDepth
+	mode  uint8 // IntraPredModeY
+	qp    int8  // QpY
+	flags uint8
+}
+
+// saoParams holds the sample adaptive
+// offset parameters of a CTB component.
+type saoParams struct {
+	typ     uint8 // SaoTypeIdx
+	class   uint8 // SaoEoClass, or sao_band_position
+	offsets [4]int8
+}
+
+// ctb holds the decoding state of a coding tree block.
+type ctb struct {
+	hdr *sliceHeader
+	sao [3]saoParams
+}
+
+// picture is a decoded picture, and the per-block
+// state needed for decoding and filtering it.
+type picture struct {
+	sps    *sps
+	pps    *pps
+	hdr    *sliceHeader // of the current independent slice segment
+	planes [3][]uint16
+	width  [3]int
+	height [3]int
+	ctbs   []ctb   // in raster scan
+	blocks []block // in raster scan
+	colBd  []int   // tile column boundaries, in CTBs
+	rowBd  []int   // tile row boundaries, in CTBs
+	rsToTs []int   // CtbAddrRsToTs
+	tsToRs []int   // CtbAddrTsToRs
+	tileID []int   // TileId, by tile scan address
+
+	// State carried between slice segments.
+	qpY    int                       // QpY of the previous coding unit
+	ctxWPP [numContexts]cabacContext // TableStateIdxWpp
+	ctxDs  [numContexts]cabacContext // TableStateIdxDs
+}
+
+// newPicture allocates a new picture for given SPS and PPS.
+func newPicture(s *sps, p *pps) (*picture, error) {
+	pic := &picture{
+		sps:    s,
+		pps:    p,
+		ctbs:   make([]ctb, s.widthCtbs*s.heightCtbs),
+		blocks: make([]block, (s.width/4)*(s.height/4)),
+	}
+
+	pic.width[0], pic.height[0] = s.width, s.height
+	pic.width[1], pic.height[1] = s.width/2, s.height/2
+	pic.width[2], pic.height[2] = s.width/2, s.height/2
+	for c := range pic.planes {
+		pic.planes[c] = make([]uint16, pic.width[c]*pic.height[c])
+	}
+
+	if s.chromaFormatIDC == 0 {
+		// Monochrome pictures
+		// have neutral chroma.
+		for c := 1; c < 3; c++ {
+			for i := range pic.planes[c] {
+				pic.planes[c][i] = 1 << uint(s.bitDepthC-1)
+			}
+		}
+	}
+
+	if err := pic.initTiles(); err != nil {
+		return nil, err
+	}
+
+	return pic, nil
+}
+
+// initTiles derives the tile boundaries and the
+// conversions between CTB raster and tile scan (6.5.1).
+func (pic *picture) initTiles() error {
+	s, p := pic.sps, pic.pps
+	if p.tileCols > s.widthCtbs || p.tileRows > s.heightCtbs {
+		return errors.New("hevc: more tiles than coding tree blocks")
+	}
+
+	bounds := func(n, size int, sizes []int) ([]int, error) {
+		bd := make([]int, n+1)
+		for i := 0; i < n; i++ {
+			switch {
+			case p.uniformSpacing:
+				bd[i+1] = ((i + 1) * size) / n
+			case i < n-1:
+				bd[i+1] = bd[i] + sizes[i]
+			default:
+				bd[i+1] = size
+			}
+			if bd[i+1] <= bd[i] || bd[i+1] > size {
+				return nil, errors.New("hevc: invalid tile sizes")
+			}
+		}
+		return bd, nil
+	}
+
+	var err error
+	if pic.colBd, err = bounds(p.tileCols, s.widthCtbs, p.colWidths); err != nil {
+		return err
+	}
+	if pic.rowBd, err = bounds(p.tileRows, s.heightCtbs, p.rowHeights); err != nil {
+		return err
+	}
+
+	n := len(pic.ctbs)
+	pic.rsToTs = make([]int, n)
+	pic.tsToRs = make([]int, n)
+	pic.tileID = make([]int, n)
+
+	ts := 0
+	for j := 0; j < p.tileRows; j++ {
+		for i := 0; i < p.tileCols; i++ {
+			for y := pic.rowBd[j]; y < pic.rowBd[j+1]; y++ {
+				for x := pic.colBd[i]; x < pic.colBd[i+1]; x++ {
+					rs := y*s.widthCtbs + x
+					pic.rsToTs[rs] = ts
+					pic.tsToRs[ts] = rs
+					pic.tileID[ts] = j*p.tileCols + i
+					ts++
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// tileOf returns the TileId of the CTB at given raster scan address.
+func (pic *picture) tileOf(rs int) int {
+	return pic.tileID[pic.rsToTs[rs]]
+}
+
+// blockAt returns the block containing given luma sample.
+func (pic *picture) blockAt(x, y int) *block {
+	return &pic.blocks[(y>>2)*(pic.width[0]>>2)+x>>2]
+}
+
+// ctbAt returns the raster scan address of
+// the CTB containing given luma sample.
+func (pic *picture) ctbAt(x, y int) int {
+	log2 := uint(pic.sps.log2CtbSize)
+	return (y>>log2)*pic.sps.widthCtbs + x>>log2
+}
+
+// finish applies the in-loop filters to the decoded
+// picture, and returns it as a cropped 8-bit image.
+func (pic *picture) finish() (*image.YCbCr, error) {
+	for i := range pic.ctbs {
+		if pic.ctbs[i].hdr == nil {
+			return nil, errors.New("hevc: picture has missing coding tree blocks")
+		}
+	}
+
+	pic.deblock()
+	pic.applySAO()
+	return pic.image(), nil
+}
+
+// image returns the cropped picture as an 8-bit image,
+// expanding limited range samples to full range.
+func (pic *picture) image() *image.YCbCr {
+	s := pic.sps
+	w := s.width - s.confLeft - s.confRight
+	h := s.height - s.confTop - s.confBottom
+	img := image.NewYCbCr(image.Rect(0, 0, w, h), image.YCbCrSubsampleRatio420)
+
+	// Build look-up tables to convert
+	// samples of each component.
+	var luts [3][]uint8
+	for c := range luts {
+		bitDepth, lo, hi := s.bitDepthY, 16, 235
+		if c > 0 {
+			bitDepth, hi = s.bitDepthC, 240
+		}
+		lut := make([]uint8, 1<<uint(bitDepth))
+		for i := range lut {
+			// Scale to 8-bit range with rounding.
+			v := i
+			if bitDepth > 8 {
+				shift := uint(bitDepth - 8)
+				v = (i + 1<<(shift-1)) >> shift
+			}
+			if !s.fullRange {
+				if c == 0 {
+					v = (v - lo) * 255 / (hi - lo)
+				} else {
+					v = (v-128)*255/(hi-lo) + 128
+				}
+			}
+			lut[i] = uint8(clip3(0, 255, v))
+		}
+		luts[c] = lut
+	}
+
+	for y := 0; y < h; y++ {
+		src := pic.planes[0][(y+s.confTop)*pic.width[0]+s.confLeft:]
+		dst := img.Y[y*img.YStride:]
+		for x := 0; x < w; x++ {
+			dst[x] = luts[0][src[x]]
+		}
+	}
+
+	// Chroma is cropped to even sample positions, since the
+	// offsets are always even for 4:2:0 pictures, and the
+	// chroma of odd image dimensions is rounded up.
+	cw, ch := (w+1)/2, (h+1)/2
+	for c := 1; c < 3; c++ {
+		plane := img.Cb
+		if c == 2 {
+			plane = img.Cr
+		}
+		for y := 0; y < ch; y++ {
+			sy := y + s.confTop/2
+			if sy >= pic.height[c] {
+				sy = pic.height[c] - 1
+			}
+			src := pic.planes[c][sy*pic.width[c]+s.confLeft/2:]
+			dst := plane[y*img.CStride:]
+			for x := 0; x < cw && s.confLeft/2+x < pic.width[c]; x++ {
+				dst[x] = luts[c][src[x]]
+			}
+		}
+	}
+
+	return img
+}
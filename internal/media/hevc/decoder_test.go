@@ -0,0 +1,142 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package hevc_test
+
+import (
+	"encoding/binary"
+	"image"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/media/hevc"
+)
+
+type DecoderTestSuite struct {
+	suite.Suite
+}
+
+// readTiles returns the decoder configuration of the HEIF file at
+// path, along with the data of each of its items, in item ID order.
+// Only the simple layout of our test files is supported: a single
+// hvcC property and one extent per item, with 4 byte offsets and
+// lengths in a version 1 iloc box.
+func (suite *DecoderTestSuite) readTiles(path string) ([]byte, [][]byte) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	var (
+		config []byte
+		tiles  [][]byte
+	)
+
+	// boxes calls fn with the type and
+	// contents of each box in b in turn.
+	boxes := func(b []byte, fn func(typ string, b []byte)) {
+		for len(b) >= 8 {
+			size := binary.BigEndian.Uint32(b)
+			fn(string(b[4:8]), b[8:size])
+			b = b[size:]
+		}
+	}
+
+	boxes(data, func(typ string, b []byte) {
+		if typ != "meta" {
+			return
+		}
+		boxes(b[4:], func(typ string, b []byte) {
+			switch typ {
+			case "iprp":
+				boxes(b, func(typ string, b []byte) {
+					if typ == "ipco" {
+						boxes(b, func(typ string, b []byte) {
+							if typ == "hvcC" {
+								config = b
+							}
+						})
+					}
+				})
+
+			case "iloc":
+				count := int(binary.BigEndian.Uint16(b[6:]))
+				b = b[8:]
+				for i := 0; i < count; i++ {
+					method := binary.BigEndian.Uint16(b[2:])
+					offset := binary.BigEndian.Uint32(b[8:])
+					length := binary.BigEndian.Uint32(b[12:])
+					if method == 0 {
+						tiles = append(tiles, data[offset:offset+length])
+					}
+					b = b[16:]
+				}
+			}
+		})
+	})
+
+	suite.NotNil(config)
+	return config, tiles
+}
+
+func (suite *DecoderTestSuite) TestDecodeTiles() {
+	// Grid of six 512x512 tiles from an iPhone photo,
+	// followed by an Exif metadata item.
+	config, tiles := suite.readTiles("../test/test-heic.heic")
+	suite.Len(tiles, 7)
+
+	for _, tile := range tiles[:6] {
+		dec, err := hevc.NewDecoder(config, 512, 512)
+		if err != nil {
+			suite.FailNow(err.Error())
+		}
+
+		img, err := dec.Decode(tile)
+		suite.NoError(err)
+		suite.Equal(image.Rect(0, 0, 512, 512), img.Bounds())
+		suite.Equal(image.YCbCrSubsampleRatio420, img.SubsampleRatio)
+	}
+}
+
+func (suite *DecoderTestSuite) TestDecodeTruncated() {
+	config, tiles := suite.readTiles("../test/test-heic.heic")
+
+	dec, err := hevc.NewDecoder(config, 512, 512)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	_, err = dec.Decode(tiles[0][:len(tiles[0])/2])
+	suite.Error(err)
+}
+
+func (suite *DecoderTestSuite) TestNewDecoderInvalid() {
+	_, err := hevc.NewDecoder([]byte{1, 2, 3}, 16, 16)
+	suite.EqualError(err, "hevc: invalid decoder configuration record")
+}
+
+func (suite *DecoderTestSuite) TestNewDecoderWrongSize() {
+	config, _ := suite.readTiles("../test/test-heic.heic")
+
+	_, err := hevc.NewDecoder(config, 4032, 3024)
+	suite.EqualError(err, "hevc: sps picture size 512x512 doesn't match image size 4032x3024")
+}
+
+func TestDecoderTestSuite(t *testing.T) {
+	suite.Run(t, new(DecoderTestSuite))
+}
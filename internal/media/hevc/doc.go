@@ -0,0 +1,24 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package hevc implements a minimal HEVC (ITU-T Rec. H.265 | ISO/IEC 23008-2)
+// decoder, sufficient to decode the intra coded pictures used to store still
+// images in HEIF containers, such as the HEIC photos taken by phones. Pictures
+// must be 4:2:0 or monochrome, with at most 12 bits per sample, which covers
+// the Main, Main 10 and Main Still Picture profiles; inter prediction and the
+// coding tools of the format range extensions are not supported.
+package hevc
@@ -0,0 +1,267 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package hevc
+
+var (
+	// intraPredAngle holds the angle parameter
+	// of each angular mode, from mode 2 (Table 8-4).
+	intraPredAngle = [33]int32{
+		32, 26, 21, 17, 13, 9, 5, 2, 0, -2, -5, -9, -13, -17, -21, -26,
+		-32, -26, -21, -17, -13, -9, -5, -2, 0, 2, 5, 9, 13, 17, 21, 26, 32,
+	}
+
+	// invAngle holds the inverse angle parameter
+	// of modes 11 to 25 (Table 8-5).
+	invAngle = [15]int32{
+		-4096, -1638, -910, -630, -482, -390, -315, -256,
+		-315, -390, -482, -630, -910, -1638, -4096,
+	}
+)
+
+// predictIntra writes the intra prediction of a transform block
+// of given component (8.4.4.2) to the picture, where (x0, y0) is
+// the position of the block in samples of the component.
+func (sd *sliceDecoder) predictIntra(cIdx, x0, y0, log2, mode int) {
+	pic, s := sd.pic, sd.pic.sps
+	n := 1 << uint(log2)
+	plane, stride := pic.planes[cIdx], pic.width[cIdx]
+
+	bitDepth := s.bitDepthY
+	scale := 1 // luma samples per sample of component
+	if cIdx > 0 {
+		bitDepth = s.bitDepthC
+		scale = 2
+	}
+	unit := 4 / scale // samples per minimum block
+
+	// Gather the neighbouring samples in the order used for
+	// substitution of unavailable samples (8.4.4.2.2), from
+	// p[-1][2n-1] up to p[-1][-1], then p[0][-1] to p[2n-1][-1].
+	total := 4*n + 1
+	refs, avail := sd.subst[:total], sd.avail[:total]
+	xCurr, yCurr := x0*scale, y0*scale
+	any := false
+	gather := func(i, x, y, dx, dy, count int) {
+		ok := sd.available(xCurr, yCurr, x*scale, y*scale)
+		any = any || ok
+		for j := 0; j < count; j++ {
+			avail[i+j] = ok
+			if ok {
+				refs[i+j] = int32(plane[(y+j*dy)*stride+x+j*dx])
+			}
+		}
+	}
+	for y := 2*n - unit; y >= 0; y -= unit {
+		gather(2*n-y-unit, x0-1, y0+y+unit-1, 0, -1, unit)
+	}
+	gather(2*n, x0-1, y0-1, 0, 0, 1)
+	for x := 0; x < 2*n; x += unit {
+		gather(2*n+1+x, x0+x, y0-1, 1, 0, unit)
+	}
+
+	if !any {
+		v := int32(1) << uint(bitDepth-1)
+		for i := range refs {
+			refs[i] = v
+		}
+	} else {
+		if !avail[0] {
+			for i := range refs {
+				if avail[i] {
+					refs[0] = refs[i]
+					break
+				}
+			}
+		}
+		for i := 1; i < total; i++ {
+			if !avail[i] {
+				refs[i] = refs[i-1]
+			}
+		}
+	}
+
+	// Arrange the samples with left[1+y] holding p[-1][y],
+	// top[1+x] holding p[x][-1], and the corner p[-1][-1]
+	// in both left[0] and top[0].
+	left, top := sd.left[:2*n+1], sd.top[:2*n+1]
+	for i := 0; i < 2*n; i++ {
+		left[1+i] = refs[2*n-1-i]
+		top[1+i] = refs[2*n+1+i]
+	}
+	left[0], top[0] = refs[2*n], refs[2*n]
+
+	// Filter the neighbouring samples (8.4.4.2.3).
+	if cIdx == 0 && n > 4 && mode != modeDC {
+		dist := abs(mode - modeVer)
+		if d := abs(mode - modeHor); d < dist {
+			dist = d
+		}
+		threshold := 0
+		switch n {
+		case 8:
+			threshold = 7
+		case 16:
+			threshold = 1
+		}
+		if dist > threshold {
+			sd.filterRefs(n, bitDepth)
+		}
+	}
+
+	max := int32(1)<<uint(bitDepth) - 1
+	set := func(x, y int, v int32) {
+		plane[(y0+y)*stride+x0+x] = uint16(v)
+	}
+
+	switch {
+	case mode == modePlanar:
+		shift := uint(log2 + 1)
+		for y := 0; y < n; y++ {
+			for x := 0; x < n; x++ {
+				v := int32(n-1-x)*left[1+y] + int32(x+1)*top[1+n] +
+					int32(n-1-y)*top[1+x] + int32(y+1)*left[1+n] + int32(n)
+				set(x, y, v>>shift)
+			}
+		}
+
+	case mode == modeDC:
+		dc := int32(n)
+		for i := 1; i <= n; i++ {
+			dc += left[i] + top[i]
+		}
+		dc >>= uint(log2 + 1)
+		for y := 0; y < n; y++ {
+			for x := 0; x < n; x++ {
+				set(x, y, dc)
+			}
+		}
+		if cIdx == 0 && n < 32 {
+			set(0, 0, (left[1]+2*dc+top[1]+2)>>2)
+			for i := 1; i < n; i++ {
+				set(i, 0, (top[1+i]+3*dc+2)>>2)
+				set(0, i, (left[1+i]+3*dc+2)>>2)
+			}
+		}
+
+	default:
+		// Angular modes, where horizontal modes are predicted
+		// as vertical modes with the axes swapped.
+		vertical := mode >= 18
+		main, side := top, left
+		if !vertical {
+			main, side = left, top
+		}
+		angle := intraPredAngle[mode-2]
+
+		// Build the reference array, with ref[n+i] holding ref[i].
+		ref := sd.refs[:3*n+1]
+		copy(ref[n:], main[:n+1])
+		if angle < 0 {
+			if last := (n * int(angle)) >> 5; last < -1 {
+				inv := invAngle[mode-11]
+				for x := last; x <= -1; x++ {
+					ref[n+x] = side[(int32(x)*inv+128)>>8]
+				}
+			}
+		} else {
+			copy(ref[2*n+1:], main[n+1:2*n+1])
+		}
+
+		for j := 0; j < n; j++ {
+			pos := int32(j+1) * angle
+			idx, fact := int(pos>>5), pos&31
+			for i := 0; i < n; i++ {
+				v := ref[n+i+idx+1]
+				if fact != 0 {
+					v = ((32-fact)*v + fact*ref[n+i+idx+2] + 16) >> 5
+				}
+				if vertical {
+					set(i, j, v)
+				} else {
+					set(j, i, v)
+				}
+			}
+		}
+
+		if cIdx == 0 && n < 32 && angle == 0 {
+			// Filter the edge of pure
+			// vertical or horizontal modes.
+			for i := 0; i < n; i++ {
+				v := main[1] + (side[1+i]-side[0])>>1
+				if v < 0 {
+					v = 0
+				} else if v > max {
+					v = max
+				}
+				if vertical {
+					set(0, i, v)
+				} else {
+					set(i, 0, v)
+				}
+			}
+		}
+	}
+}
+
+// filterRefs filters the neighbouring samples of
+// an n x n luma block (8.4.4.2.3), using bi-linear
+// interpolation for strong intra smoothing.
+func (sd *sliceDecoder) filterRefs(n, bitDepth int) {
+	left, top := sd.left[:2*n+1], sd.top[:2*n+1]
+	corner := left[0]
+
+	if sd.pic.sps.strongIntraSmooth && n == 32 {
+		threshold := int32(1) << uint(bitDepth-5)
+		if abs32(corner+top[2*n]-2*top[n]) < threshold &&
+			abs32(corner+left[2*n]-2*left[n]) < threshold {
+			for i := 0; i < 63; i++ {
+				left[1+i] = ((63-int32(i))*corner + int32(i+1)*left[64] + 32) >> 6
+				top[1+i] = ((63-int32(i))*corner + int32(i+1)*top[64] + 32) >> 6
+			}
+			return
+		}
+	}
+
+	var filtered [2][2*32 + 1]int32
+	for j, refs := range [2][]int32{left, top} {
+		f := filtered[j][:2*n+1]
+		f[0] = (left[1] + 2*corner + top[1] + 2) >> 2
+		for i := 1; i < 2*n; i++ {
+			f[i] = (refs[i-1] + 2*refs[i] + refs[i+1] + 2) >> 2
+		}
+		f[2*n] = refs[2*n]
+	}
+	copy(left, filtered[0][:2*n+1])
+	copy(top, filtered[1][:2*n+1])
+}
+
+// abs returns the absolute value of v.
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// abs32 returns the absolute value of v.
+func abs32(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,715 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package hevc
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupported is returned when a stream
+// uses coding tools this package can't decode.
+var ErrUnsupported = errors.New("hevc: unsupported stream")
+
+// sps is a parsed sequence parameter set.
+type sps struct {
+	id                uint32
+	chromaFormatIDC   uint32
+	width             int // in luma samples
+	height            int // in luma samples
+	confLeft          int // in luma samples
+	confRight         int // in luma samples
+	confTop           int // in luma samples
+	confBottom        int // in luma samples
+	bitDepthY         int
+	bitDepthC         int
+	log2MaxPocLsb     int
+	log2MinCbSize     int
+	log2CtbSize       int
+	log2MinTbSize     int
+	log2MaxTbSize     int
+	maxTrDepthIntra   int
+	scalingEnabled    bool
+	scaling           *scalingList
+	amp               bool
+	sao               bool
+	pcm               bool
+	pcmBitDepthY      int
+	pcmBitDepthC      int
+	log2MinPcmSize    int
+	log2MaxPcmSize    int
+	pcmLoopFilterOff  bool
+	numShortTermRPS   int
+	numDeltaPocs      []int // of each st_ref_pic_set()
+	longTermRefs      bool
+	numLongTermRefs   int
+	temporalMVP       bool
+	strongIntraSmooth bool
+	fullRange         bool
+
+	// Derived values.
+	widthCtbs  int
+	heightCtbs int
+}
+
+// pps is a parsed picture parameter set.
+type pps struct {
+	id                   uint32
+	spsID                uint32
+	dependentSlices      bool
+	outputFlagPresent    bool
+	numExtraSliceBits    int
+	signDataHiding       bool
+	cabacInitPresent     bool
+	initQP               int
+	transformSkip        bool
+	cuQPDelta            bool
+	diffCuQPDeltaDepth   int
+	cbQPOffset           int
+	crQPOffset           int
+	sliceChromaQPOffsets bool
+	transquantBypass     bool
+	tiles                bool
+	entropySync          bool
+	tileCols             int
+	tileRows             int
+	uniformSpacing       bool
+	colWidths            []int // in CTBs, when not uniformly spaced
+	rowHeights           []int // in CTBs, when not uniformly spaced
+	filterAcrossTiles    bool
+	filterAcrossSlices   bool
+	deblockingOverride   bool
+	deblockingDisabled   bool
+	betaOffset           int
+	tcOffset             int
+	scaling              *scalingList
+	listsModification    bool
+	sliceHeaderExtension bool
+}
+
+// scalingList holds the ScalingFactor arrays
+// for intra coded blocks, indexed by sizeId and
+// colour component, in raster order.
+type scalingList struct {
+	factors [4][3][]uint8
+}
+
+var (
+	// defaultScaling holds the default intra and inter
+	// scaling list values for 8x8 to 32x32 blocks, in
+	// up-right diagonal scan order (Table 7-6).
+	defaultScaling = [2][64]uint8{
+		{
+			16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 17, 16, 17, 16, 17, 18,
+			17, 18, 18, 17, 18, 21, 19, 20, 21, 20, 19, 21, 24, 22, 22, 24,
+			24, 22, 22, 24, 25, 25, 27, 30, 27, 25, 25, 29, 31, 35, 35, 31,
+			29, 36, 41, 44, 41, 36, 47, 54, 54, 47, 65, 70, 65, 88, 88, 115,
+		},
+		{
+			16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 17, 17, 17, 17, 17, 18,
+			18, 18, 18, 18, 18, 20, 20, 20, 20, 20, 20, 20, 24, 24, 24, 24,
+			24, 24, 24, 24, 25, 25, 25, 25, 25, 25, 25, 28, 28, 28, 28, 28,
+			28, 33, 33, 33, 33, 33, 41, 41, 41, 41, 54, 54, 54, 71, 71, 91,
+		},
+	}
+
+	// defaultScalingList holds the ScalingFactor arrays
+	// used when no scaling list data is signalled.
+	defaultScalingList = func() *scalingList {
+		var lists [4][6][64]uint8
+		var dc [4][6]uint8
+		for sizeID := 0; sizeID < 4; sizeID++ {
+			for matrixID := 0; matrixID < 6; matrixID++ {
+				if sizeID == 0 {
+					for i := 0; i < 16; i++ {
+						lists[0][matrixID][i] = 16
+					}
+				} else {
+					lists[sizeID][matrixID] = defaultScaling[matrixID/3]
+				}
+				dc[sizeID][matrixID] = 16
+			}
+		}
+		return newScalingList(&lists, &dc)
+	}()
+)
+
+// parseScalingListData parses a scaling_list_data(),
+// returning the derived ScalingFactor arrays.
+func parseScalingListData(r *bitReader) (*scalingList, error) {
+	var lists [4][6][64]uint8
+	var dc [4][6]uint8
+
+	for sizeID := 0; sizeID < 4; sizeID++ {
+		step := 1
+		if sizeID == 3 {
+			step = 3
+		}
+
+		coefNum := 64
+		if sizeID == 0 {
+			coefNum = 16
+		}
+
+		for matrixID := 0; matrixID < 6; matrixID += step {
+			if !r.flag() {
+				// scaling_list_pred_mode_flag = 0, so the list is
+				// predicted from a reference or default list.
+				delta := int(r.ue()) * step
+				if delta > matrixID {
+					return nil, errors.New("hevc: invalid scaling_list_pred_matrix_id_delta")
+				}
+				if delta == 0 {
+					if sizeID == 0 {
+						for i := 0; i < 16; i++ {
+							lists[0][matrixID][i] = 16
+						}
+					} else {
+						lists[sizeID][matrixID] = defaultScaling[matrixID/3]
+					}
+					dc[sizeID][matrixID] = 16
+				} else {
+					lists[sizeID][matrixID] = lists[sizeID][matrixID-delta]
+					dc[sizeID][matrixID] = dc[sizeID][matrixID-delta]
+				}
+				continue
+			}
+
+			next := int32(8)
+			if sizeID > 1 {
+				next = r.se() + 8
+				if next < 1 || next > 255 {
+					return nil, errors.New("hevc: invalid scaling_list_dc_coef_minus8")
+				}
+				dc[sizeID][matrixID] = uint8(next)
+			}
+			for i := 0; i < coefNum; i++ {
+				next = (next + r.se() + 256) % 256
+				if next == 0 {
+					return nil, errors.New("hevc: invalid scaling_list_delta_coef")
+				}
+				lists[sizeID][matrixID][i] = uint8(next)
+			}
+			if sizeID <= 1 {
+				dc[sizeID][matrixID] = lists[sizeID][matrixID][0]
+			}
+		}
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return newScalingList(&lists, &dc), nil
+}
+
+// newScalingList derives the intra ScalingFactor arrays
+// from the given scaling lists and DC coefficients (7.4.5).
+func newScalingList(lists *[4][6][64]uint8, dc *[4][6]uint8) *scalingList {
+	l := new(scalingList)
+	for sizeID := 0; sizeID < 4; sizeID++ {
+		size := 4 << uint(sizeID)
+		for c := 0; c < 3; c++ {
+			matrixID := c
+			if sizeID == 3 {
+				// There are no 32x32 chroma lists, but
+				// 32x32 chroma blocks can't occur in the
+				// supported chroma formats anyway.
+				matrixID = 0
+			}
+
+			f := make([]uint8, size*size)
+			if sizeID == 0 {
+				scan := scanOrder[2][scanDiag]
+				for i := 0; i < 16; i++ {
+					x, y := int(scan[i][0]), int(scan[i][1])
+					f[y*4+x] = lists[0][matrixID][i]
+				}
+			} else {
+				// Lists for larger blocks
+				// are upsampled from 8x8.
+				scan := scanOrder[3][scanDiag]
+				ratio := size / 8
+				for i := 0; i < 64; i++ {
+					x, y := int(scan[i][0]), int(scan[i][1])
+					for j := 0; j < ratio; j++ {
+						for k := 0; k < ratio; k++ {
+							f[(y*ratio+j)*size+x*ratio+k] = lists[sizeID][matrixID][i]
+						}
+					}
+				}
+				if sizeID > 1 {
+					f[0] = dc[sizeID][matrixID]
+				}
+			}
+			l.factors[sizeID][c] = f
+		}
+	}
+	return l
+}
+
+// parseProfileTierLevel skips a profile_tier_level()
+// with profilePresentFlag equal to 1.
+func parseProfileTierLevel(r *bitReader, maxSubLayers int) {
+	r.skip(96) // general profile, tier and level
+	var profilePresent, levelPresent [8]bool
+	for i := 0; i < maxSubLayers; i++ {
+		profilePresent[i] = r.flag()
+		levelPresent[i] = r.flag()
+	}
+	if maxSubLayers > 0 {
+		r.skip(2 * (8 - maxSubLayers)) // reserved_zero_2bits
+	}
+	for i := 0; i < maxSubLayers; i++ {
+		if profilePresent[i] {
+			r.skip(88)
+		}
+		if levelPresent[i] {
+			r.skip(8)
+		}
+	}
+}
+
+// parseShortTermRPS parses an st_ref_pic_set(), returning
+// the number of pictures in the reference picture set,
+// given the numbers in each of the preceding sets.
+func parseShortTermRPS(r *bitReader, idx int, numDeltaPocs []int, inSliceHeader bool) (int, error) {
+	if idx != 0 && r.flag() {
+		// inter_ref_pic_set_prediction_flag = 1.
+		delta := 1
+		if inSliceHeader {
+			delta = int(r.ue()) + 1
+		}
+		if delta > idx {
+			return 0, errors.New("hevc: invalid delta_idx_minus1")
+		}
+		r.flag() // delta_rps_sign
+		r.ue()   // abs_delta_rps_minus1
+
+		n := 0
+		for j := 0; j <= numDeltaPocs[idx-delta] && r.err == nil; j++ {
+			// used_by_curr_pic_flag, use_delta_flag
+			if r.flag() || r.flag() {
+				n++
+			}
+		}
+		return n, r.err
+	}
+
+	neg, pos := r.ue(), r.ue()
+	if neg > 16 || pos > 16 {
+		return 0, errors.New("hevc: invalid st_ref_pic_set")
+	}
+	for i := uint32(0); i < neg+pos && r.err == nil; i++ {
+		r.ue()   // delta_poc_sX_minus1
+		r.flag() // used_by_curr_pic_sX_flag
+	}
+	return int(neg + pos), r.err
+}
+
+// parseHRD skips an hrd_parameters()
+// with commonInfPresentFlag equal to 1.
+func parseHRD(r *bitReader, maxSubLayers int) {
+	nal, vcl := r.flag(), r.flag()
+	subPic := false
+	if nal || vcl {
+		subPic = r.flag()
+		if subPic {
+			r.skip(8 + 5 + 1 + 5)
+		}
+		r.skip(4 + 4) // bit_rate_scale, cpb_size_scale
+		if subPic {
+			r.skip(4) // cpb_size_du_scale
+		}
+		r.skip(5 + 5 + 5)
+	}
+
+	for i := 0; i <= maxSubLayers && r.err == nil; i++ {
+		fixedRate := r.flag() // fixed_pic_rate_general_flag
+		if !fixedRate {
+			fixedRate = r.flag() // fixed_pic_rate_within_cvs_flag
+		}
+		lowDelay := false
+		if fixedRate {
+			r.ue() // elemental_duration_in_tc_minus1
+		} else {
+			lowDelay = r.flag()
+		}
+		cpbCnt := uint32(0)
+		if !lowDelay {
+			cpbCnt = r.ue()
+			if cpbCnt > 31 {
+				r.err = errors.New("hevc: invalid cpb_cnt_minus1")
+				return
+			}
+		}
+		for _, present := range []bool{nal, vcl} {
+			if !present {
+				continue
+			}
+			// sub_layer_hrd_parameters()
+			for j := uint32(0); j <= cpbCnt; j++ {
+				r.ue() // bit_rate_value_minus1
+				r.ue() // cpb_size_value_minus1
+				if subPic {
+					r.ue() // cpb_size_du_value_minus1
+					r.ue() // bit_rate_du_value_minus1
+				}
+				r.flag() // cbr_flag
+			}
+		}
+	}
+}
+
+// parseVUI parses the vui_parameters() of given SPS.
+func parseVUI(r *bitReader, s *sps, maxSubLayers int) {
+	if r.flag() {
+		// aspect_ratio_info_present_flag
+		if r.u(8) == 255 {
+			r.skip(32) // sar_width, sar_height
+		}
+	}
+	if r.flag() {
+		r.flag() // overscan_appropriate_flag
+	}
+	if r.flag() {
+		// video_signal_type_present_flag
+		r.u(3) // video_format
+		s.fullRange = r.flag()
+		if r.flag() {
+			r.skip(24) // colour description
+		}
+	}
+	if r.flag() {
+		r.ue() // chroma_sample_loc_type_top_field
+		r.ue() // chroma_sample_loc_type_bottom_field
+	}
+	r.flag() // neutral_chroma_indication_flag
+	r.flag() // field_seq_flag
+	r.flag() // frame_field_info_present_flag
+	if r.flag() {
+		// default_display_window_flag
+		r.ue()
+		r.ue()
+		r.ue()
+		r.ue()
+	}
+	if r.flag() {
+		// vui_timing_info_present_flag
+		r.skip(64)
+		if r.flag() {
+			r.ue() // vui_num_ticks_poc_diff_one_minus1
+		}
+		if r.flag() {
+			parseHRD(r, maxSubLayers)
+		}
+	}
+	if r.flag() {
+		// bitstream_restriction_flag
+		r.skip(3)
+		for i := 0; i < 5; i++ {
+			r.ue()
+		}
+	}
+}
+
+// maxLumaPs is the maximum permitted number of luma samples
+// in a picture, that of levels 6 to 6.2 (Table A.8). This
+// bounds the memory allocated for a picture, which the sizes
+// in an SPS could otherwise make huge.
+const maxLumaPs = 35651584
+
+// hasSize returns whether pictures of the SPS are of the given
+// size, either as coded or once cropped to the conformance window.
+func (s *sps) hasSize(width, height int) bool {
+	return (width == s.width && height == s.height) ||
+		(width == s.width-s.confLeft-s.confRight && height == s.height-s.confTop-s.confBottom)
+}
+
+// parseSPS parses a seq_parameter_set_rbsp().
+func parseSPS(rbsp []byte) (*sps, error) {
+	r := newBitReader(rbsp)
+	s := &sps{fullRange: true}
+
+	r.u(4) // sps_video_parameter_set_id
+	maxSubLayers := int(r.u(3))
+	r.flag() // sps_temporal_id_nesting_flag
+	parseProfileTierLevel(r, maxSubLayers)
+
+	s.id = r.ue()
+	if s.id > 15 {
+		return nil, fmt.Errorf("hevc: invalid sps id %d", s.id)
+	}
+
+	s.chromaFormatIDC = r.ue()
+	if s.chromaFormatIDC == 3 && r.flag() {
+		return nil, fmt.Errorf("%w: separate colour planes", ErrUnsupported)
+	}
+	if s.chromaFormatIDC > 1 {
+		return nil, fmt.Errorf("%w: chroma format %d", ErrUnsupported, s.chromaFormatIDC)
+	}
+
+	s.width = int(r.ue())
+	s.height = int(r.ue())
+	if r.flag() {
+		// Conformance window offsets are in chroma
+		// samples, which for 4:2:0 are 2 luma samples.
+		unit := 2
+		if s.chromaFormatIDC == 0 {
+			unit = 1
+		}
+		s.confLeft = int(r.ue()) * unit
+		s.confRight = int(r.ue()) * unit
+		s.confTop = int(r.ue()) * unit
+		s.confBottom = int(r.ue()) * unit
+	}
+
+	s.bitDepthY = int(r.ue()) + 8
+	s.bitDepthC = int(r.ue()) + 8
+	if s.bitDepthY > 12 || s.bitDepthC > 12 {
+		return nil, fmt.Errorf("%w: bit depth > 12", ErrUnsupported)
+	}
+
+	s.log2MaxPocLsb = int(r.ue()) + 4
+	if s.log2MaxPocLsb > 16 {
+		return nil, errors.New("hevc: invalid log2_max_pic_order_cnt_lsb_minus4")
+	}
+	first := maxSubLayers
+	if r.flag() {
+		// sps_sub_layer_ordering_info_present_flag
+		first = 0
+	}
+	for i := first; i <= maxSubLayers; i++ {
+		r.ue() // sps_max_dec_pic_buffering_minus1
+		r.ue() // sps_max_num_reorder_pics
+		r.ue() // sps_max_latency_increase_plus1
+	}
+
+	s.log2MinCbSize = int(r.ue()) + 3
+	s.log2CtbSize = s.log2MinCbSize + int(r.ue())
+	s.log2MinTbSize = int(r.ue()) + 2
+	s.log2MaxTbSize = s.log2MinTbSize + int(r.ue())
+	r.ue() // max_transform_hierarchy_depth_inter
+	s.maxTrDepthIntra = int(r.ue())
+
+	switch {
+	case r.err != nil:
+		return nil, r.err
+	case s.log2CtbSize < 4 || s.log2CtbSize > 6:
+		return nil, fmt.Errorf("hevc: invalid ctb size %d", 1<<uint(s.log2CtbSize))
+	case s.log2MinTbSize >= s.log2MinCbSize || s.log2MaxTbSize > 5 || s.log2MaxTbSize > s.log2CtbSize:
+		return nil, errors.New("hevc: invalid transform block sizes")
+	case s.maxTrDepthIntra > s.log2CtbSize-s.log2MinTbSize:
+		return nil, errors.New("hevc: invalid max_transform_hierarchy_depth_intra")
+	case s.width <= 0 || s.height <= 0 || s.width > 16888 || s.height > 16888 ||
+		s.width*s.height > maxLumaPs:
+		return nil, fmt.Errorf("hevc: invalid picture size %dx%d", s.width, s.height)
+	case s.width%(1<<uint(s.log2MinCbSize)) != 0 || s.height%(1<<uint(s.log2MinCbSize)) != 0:
+		return nil, errors.New("hevc: picture size not a multiple of min coding block size")
+	case s.confLeft+s.confRight >= s.width || s.confTop+s.confBottom >= s.height:
+		return nil, errors.New("hevc: invalid conformance window")
+	}
+
+	s.scalingEnabled = r.flag()
+	if s.scalingEnabled {
+		s.scaling = defaultScalingList
+		if r.flag() {
+			// sps_scaling_list_data_present_flag
+			var err error
+			if s.scaling, err = parseScalingListData(r); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	s.amp = r.flag()
+	s.sao = r.flag()
+	s.pcm = r.flag()
+	if s.pcm {
+		s.pcmBitDepthY = int(r.u(4)) + 1
+		s.pcmBitDepthC = int(r.u(4)) + 1
+		s.log2MinPcmSize = int(r.ue()) + 3
+		s.log2MaxPcmSize = s.log2MinPcmSize + int(r.ue())
+		s.pcmLoopFilterOff = r.flag()
+		if s.pcmBitDepthY > s.bitDepthY || s.pcmBitDepthC > s.bitDepthC || s.log2MaxPcmSize > 5 {
+			return nil, errors.New("hevc: invalid pcm parameters")
+		}
+	}
+
+	s.numShortTermRPS = int(r.ue())
+	if s.numShortTermRPS > 64 {
+		return nil, errors.New("hevc: invalid num_short_term_ref_pic_sets")
+	}
+	s.numDeltaPocs = make([]int, s.numShortTermRPS)
+	for i := range s.numDeltaPocs {
+		n, err := parseShortTermRPS(r, i, s.numDeltaPocs, false)
+		if err != nil {
+			return nil, err
+		}
+		s.numDeltaPocs[i] = n
+	}
+
+	s.longTermRefs = r.flag()
+	if s.longTermRefs {
+		s.numLongTermRefs = int(r.ue())
+		if s.numLongTermRefs > 32 {
+			return nil, errors.New("hevc: invalid num_long_term_ref_pics_sps")
+		}
+		for i := 0; i < s.numLongTermRefs; i++ {
+			r.u(s.log2MaxPocLsb) // lt_ref_pic_poc_lsb_sps
+			r.flag()             // used_by_curr_pic_lt_sps_flag
+		}
+	}
+
+	s.temporalMVP = r.flag()
+	s.strongIntraSmooth = r.flag()
+	if r.flag() {
+		parseVUI(r, s, maxSubLayers)
+	}
+
+	if r.flag() {
+		// sps_extension_present_flag
+		if r.flag() {
+			// sps_range_extension()
+			if r.u(9) != 0 {
+				return nil, fmt.Errorf("%w: range extensions", ErrUnsupported)
+			}
+		}
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	ctbSize := 1 << uint(s.log2CtbSize)
+	s.widthCtbs = (s.width + ctbSize - 1) / ctbSize
+	s.heightCtbs = (s.height + ctbSize - 1) / ctbSize
+	return s, nil
+}
+
+// parsePPS parses a pic_parameter_set_rbsp().
+func parsePPS(rbsp []byte) (*pps, error) {
+	r := newBitReader(rbsp)
+	p := new(pps)
+
+	p.id = r.ue()
+	if p.id > 63 {
+		return nil, fmt.Errorf("hevc: invalid pps id %d", p.id)
+	}
+	p.spsID = r.ue()
+	p.dependentSlices = r.flag()
+	p.outputFlagPresent = r.flag()
+	p.numExtraSliceBits = int(r.u(3))
+	p.signDataHiding = r.flag()
+	p.cabacInitPresent = r.flag()
+	r.ue() // num_ref_idx_l0_default_active_minus1
+	r.ue() // num_ref_idx_l1_default_active_minus1
+	p.initQP = 26 + int(r.se())
+	r.flag() // constrained_intra_pred_flag
+	p.transformSkip = r.flag()
+	p.cuQPDelta = r.flag()
+	if p.cuQPDelta {
+		p.diffCuQPDeltaDepth = int(r.ue())
+	}
+	p.cbQPOffset = int(r.se())
+	p.crQPOffset = int(r.se())
+	p.sliceChromaQPOffsets = r.flag()
+	r.flag() // weighted_pred_flag
+	r.flag() // weighted_bipred_flag
+	p.transquantBypass = r.flag()
+	p.tiles = r.flag()
+	p.entropySync = r.flag()
+
+	p.tileCols, p.tileRows = 1, 1
+	p.uniformSpacing = true
+	if p.tiles {
+		p.tileCols = int(r.ue()) + 1
+		p.tileRows = int(r.ue()) + 1
+		if p.tileCols > 20 || p.tileRows > 22 {
+			return nil, errors.New("hevc: invalid number of tiles")
+		}
+		p.uniformSpacing = r.flag()
+		if !p.uniformSpacing {
+			p.colWidths = make([]int, p.tileCols-1)
+			for i := range p.colWidths {
+				p.colWidths[i] = int(r.ue()) + 1
+			}
+			p.rowHeights = make([]int, p.tileRows-1)
+			for i := range p.rowHeights {
+				p.rowHeights[i] = int(r.ue()) + 1
+			}
+		}
+		p.filterAcrossTiles = r.flag()
+	}
+
+	p.filterAcrossSlices = r.flag()
+	if r.flag() {
+		// deblocking_filter_control_present_flag
+		p.deblockingOverride = r.flag()
+		p.deblockingDisabled = r.flag()
+		if !p.deblockingDisabled {
+			p.betaOffset = int(r.se()) * 2
+			p.tcOffset = int(r.se()) * 2
+		}
+	}
+
+	if r.flag() {
+		// pps_scaling_list_data_present_flag
+		var err error
+		if p.scaling, err = parseScalingListData(r); err != nil {
+			return nil, err
+		}
+	}
+
+	p.listsModification = r.flag()
+	r.ue() // log2_parallel_merge_level_minus2
+	p.sliceHeaderExtension = r.flag()
+
+	if r.flag() {
+		// pps_extension_present_flag
+		if r.flag() {
+			// pps_range_extension()
+			if p.transformSkip && r.ue() != 0 {
+				return nil, fmt.Errorf("%w: range extensions", ErrUnsupported)
+			}
+			if r.flag() || r.flag() {
+				// cross_component_prediction_enabled_flag,
+				// chroma_qp_offset_list_enabled_flag
+				return nil, fmt.Errorf("%w: range extensions", ErrUnsupported)
+			}
+			if r.ue() != 0 || r.ue() != 0 {
+				// log2_sao_offset_scale_luma, chroma
+				return nil, fmt.Errorf("%w: range extensions", ErrUnsupported)
+			}
+		}
+	}
+
+	switch {
+	case r.err != nil:
+		return nil, r.err
+	case p.initQP < -26 || p.initQP > 51:
+		// The lower bound depends on bit depth,
+		// which is checked against the SPS later.
+		return nil, errors.New("hevc: invalid init_qp_minus26")
+	case p.cbQPOffset < -12 || p.cbQPOffset > 12 ||
+		p.crQPOffset < -12 || p.crQPOffset > 12:
+		return nil, errors.New("hevc: invalid chroma qp offset")
+	}
+
+	return p, nil
+}
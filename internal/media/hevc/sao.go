@@ -0,0 +1,184 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package hevc
+
+// saoEdgeOffsets holds the positions of the neighbouring
+// samples used by each edge offset class (Table 8-11).
+var saoEdgeOffsets = [4][2][2]int{
+	{{-1, 0}, {1, 0}},
+	{{0, -1}, {0, 1}},
+	{{-1, -1}, {1, 1}},
+	{{1, -1}, {-1, 1}},
+}
+
+// applySAO applies the sample adaptive offset
+// process to the deblocked picture (8.7.3).
+func (pic *picture) applySAO() {
+	s := pic.sps
+	if !s.sao {
+		return
+	}
+
+	// The offsets are applied to a copy of
+	// the deblocked samples of each component.
+	var deblocked [3][]uint16
+	for c := range deblocked {
+		if c > 0 && s.chromaFormatIDC == 0 {
+			break
+		}
+		for i := range pic.ctbs {
+			if pic.ctbs[i].sao[c].typ != 0 {
+				deblocked[c] = make([]uint16, len(pic.planes[c]))
+				copy(deblocked[c], pic.planes[c])
+				break
+			}
+		}
+	}
+
+	for rs := range pic.ctbs {
+		for c, src := range deblocked {
+			if src != nil && pic.ctbs[rs].sao[c].typ != 0 {
+				pic.saoCTB(rs, c, src)
+			}
+		}
+	}
+}
+
+// saoCTB applies the sample adaptive offsets of
+// given component to a CTB, using the given
+// deblocked samples of the component.
+func (pic *picture) saoCTB(rs, c int, src []uint16) {
+	s := pic.sps
+	sao := &pic.ctbs[rs].sao[c]
+	hdr := pic.ctbs[rs].hdr
+
+	bitDepth, scale := s.bitDepthY, 1
+	if c > 0 {
+		bitDepth, scale = s.bitDepthC, 2
+	}
+	max := int32(1)<<uint(bitDepth) - 1
+
+	size := (1 << uint(s.log2CtbSize)) / scale
+	x0, y0 := (rs%s.widthCtbs)*size, (rs/s.widthCtbs)*size
+	x1, y1 := x0+size, y0+size
+	width, height := pic.width[c], pic.height[c]
+	if x1 > width {
+		x1 = width
+	}
+	if y1 > height {
+		y1 = height
+	}
+	plane := pic.planes[c]
+
+	// Determine which of the neighbouring CTBs may
+	// be used by the edge offsets of this CTB.
+	var usable [3][3]bool
+	ctbX, ctbY := rs%s.widthCtbs, rs/s.widthCtbs
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			x, y := ctbX+dx, ctbY+dy
+			if x < 0 || y < 0 || x >= s.widthCtbs || y >= s.heightCtbs {
+				continue
+			}
+			nb := y*s.widthCtbs + x
+			nbHdr := pic.ctbs[nb].hdr
+			ok := true
+			if nbHdr.sliceAddr != hdr.sliceAddr {
+				// Filtering across a slice boundary is controlled
+				// by the later slice in decoding order.
+				if pic.rsToTs[nb] < pic.rsToTs[rs] {
+					ok = hdr.filterAcrossSlices
+				} else {
+					ok = nbHdr.filterAcrossSlices
+				}
+			}
+			if pic.tileOf(nb) != pic.tileOf(rs) && !pic.pps.filterAcrossTiles {
+				ok = false
+			}
+			usable[dy+1][dx+1] = ok
+		}
+	}
+
+	var bandTable [32]int
+	if sao.typ == 1 {
+		for k := 0; k < 4; k++ {
+			bandTable[(k+int(sao.class))&31] = k + 1
+		}
+	}
+	bandShift := uint(bitDepth - 5)
+
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			if pic.blockAt(x*scale, y*scale).flags&blockNoFilter != 0 {
+				continue
+			}
+
+			v := int32(src[y*width+x])
+			var offset int32
+			if sao.typ == 1 {
+				if k := bandTable[v>>bandShift]; k > 0 {
+					offset = int32(sao.offsets[k-1])
+				}
+			} else {
+				edgeIdx := 2
+				skip := false
+				for _, d := range saoEdgeOffsets[sao.class] {
+					nx, ny := x+d[0], y+d[1]
+					if nx < 0 || ny < 0 || nx >= width || ny >= height {
+						skip = true
+						break
+					}
+					ux, uy := 1, 1
+					if nx < x0 {
+						ux = 0
+					} else if nx >= x0+size {
+						ux = 2
+					}
+					if ny < y0 {
+						uy = 0
+					} else if ny >= y0+size {
+						uy = 2
+					}
+					if !usable[uy][ux] {
+						skip = true
+						break
+					}
+					nv := int32(src[ny*width+nx])
+					if v < nv {
+						edgeIdx--
+					} else if v > nv {
+						edgeIdx++
+					}
+				}
+				if skip {
+					continue
+				}
+				switch edgeIdx {
+				case 0, 1:
+					offset = int32(sao.offsets[edgeIdx])
+				case 3, 4:
+					offset = int32(sao.offsets[edgeIdx-1])
+				}
+			}
+
+			if offset != 0 {
+				plane[y*width+x] = uint16(clip32(0, max, v+offset))
+			}
+		}
+	}
+}
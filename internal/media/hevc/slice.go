@@ -0,0 +1,930 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package hevc
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Intra prediction modes (Table 8-1).
+const (
+	modePlanar = 0
+	modeDC     = 1
+	modeHor    = 10
+	modeVer    = 26
+)
+
+// sliceDecoder decodes the coding tree units of a slice segment.
+type sliceDecoder struct {
+	pic *picture
+	hdr *sliceHeader
+	r   *bitReader
+	c   cabac
+
+	ctbAddr int // CtbAddrInRs of current CTB
+
+	// Quantization state.
+	qpYPred        int // qPY_PRED of current quantization group
+	qpY            int // QpY of current coding unit
+	qpCb, qpCr     int // Qp′Cb and Qp′Cr of current coding unit
+	cuQPDeltaCoded bool
+	cuQPDelta      int // CuQpDeltaVal
+
+	// State of current coding unit.
+	bypass       bool // cu_transquant_bypass_flag
+	nxn          bool // IntraSplitFlag
+	maxTrafo     int  // MaxTrafoDepth
+	chromaMode   int  // IntraPredModeC
+	coeffs       [32 * 32]int32
+	res          [32 * 32]int32
+	left, top    [2*32 + 1]int32 // intra prediction reference samples
+	subst        [4*32 + 1]int32
+	avail        [4*32 + 1]bool
+	refs         [3*32 + 1]int32
+	sigPos       [16]int
+	greater1     [16]bool
+	codedSbFlags [8][8]bool
+}
+
+// decode decodes all coding tree units of the slice segment.
+func (sd *sliceDecoder) decode() error {
+	pic, hdr := sd.pic, sd.hdr
+	s, p := pic.sps, pic.pps
+
+	sd.c.r = sd.r
+	sd.c.start()
+
+	for ts, first := pic.rsToTs[hdr.segmentAddr], true; ; first = false {
+		rs := pic.tsToRs[ts]
+		if pic.ctbs[rs].hdr != nil {
+			return errors.New("hevc: overlapping slice segments")
+		}
+		pic.ctbs[rs].hdr = hdr
+		sd.ctbAddr = rs
+
+		ctbX, ctbY := rs%s.widthCtbs, rs/s.widthCtbs
+		tileStart := ts == 0 || pic.tileID[ts] != pic.tileID[ts-1]
+		rowStart := p.entropySync && (ctbX == 0 || pic.tileID[ts] != pic.tileOf(rs-1))
+
+		// Initialise context variables (9.3.1).
+		switch {
+		case tileStart:
+			sd.c.initContexts(hdr.qp)
+		case rowStart:
+			tr := rs - s.widthCtbs + 1
+			if ctbY > 0 && ctbX+1 < s.widthCtbs &&
+				pic.ctbs[tr].hdr != nil &&
+				pic.ctbs[tr].hdr.sliceAddr == hdr.sliceAddr &&
+				pic.tileOf(tr) == pic.tileID[ts] {
+				sd.c.ctx = pic.ctxWPP
+			} else {
+				sd.c.initContexts(hdr.qp)
+			}
+		case first && hdr.dependent:
+			sd.c.ctx = pic.ctxDs
+		case first:
+			sd.c.initContexts(hdr.qp)
+		}
+
+		if tileStart || rowStart || (first && !hdr.dependent) {
+			// First quantization group in
+			// a slice, tile or CTB row.
+			pic.qpY = hdr.qp
+		}
+
+		sd.decodeCTU(ctbX, ctbY)
+
+		if p.entropySync && (ctbX == 1 || rs > 1 && pic.tileID[ts] != pic.tileOf(rs-2)) {
+			pic.ctxWPP = sd.c.ctx
+		}
+
+		if sd.r.err != nil {
+			return fmt.Errorf("hevc: error decoding coding tree unit %d: %w", rs, sd.r.err)
+		}
+
+		// end_of_slice_segment_flag
+		if sd.c.terminate() == 1 {
+			if p.dependentSlices {
+				pic.ctxDs = sd.c.ctx
+			}
+			return nil
+		}
+
+		ts++
+		if ts >= len(pic.ctbs) {
+			return errors.New("hevc: slice segment overruns picture")
+		}
+
+		rs = pic.tsToRs[ts]
+		if (p.tiles && pic.tileID[ts] != pic.tileID[ts-1]) ||
+			(p.entropySync && (rs%s.widthCtbs == 0 || pic.tileID[ts] != pic.tileOf(rs-1))) {
+			// end_of_subset_one_bit, followed by byte_alignment().
+			if sd.c.terminate() != 1 {
+				return errors.New("hevc: invalid end_of_subset_one_bit")
+			}
+			sd.r.align()
+			sd.c.start()
+		}
+	}
+}
+
+// fail records an error decoding the slice segment,
+// which stops all further reads from the slice segment.
+func (sd *sliceDecoder) fail(err error) {
+	if sd.r.err == nil {
+		sd.r.err = err
+	}
+	sd.r.pos = len(sd.r.buf) * 8
+}
+
+// available returns whether the block containing luma sample
+// (xNb, yNb) is available for prediction of the block containing
+// luma sample (xCurr, yCurr), following the z-scan order block
+// availability derivation process (6.4.1).
+func (sd *sliceDecoder) available(xCurr, yCurr, xNb, yNb int) bool {
+	pic := sd.pic
+	if xNb < 0 || yNb < 0 || xNb >= pic.width[0] || yNb >= pic.height[0] {
+		return false
+	}
+
+	nb, curr := pic.ctbAt(xNb, yNb), pic.ctbAt(xCurr, yCurr)
+	if nb != curr {
+		hdr := pic.ctbs[nb].hdr
+		return hdr != nil &&
+			pic.rsToTs[nb] < pic.rsToTs[curr] &&
+			hdr.sliceAddr == sd.hdr.sliceAddr &&
+			pic.tileOf(nb) == pic.tileOf(curr)
+	}
+
+	return zOrder(pic.sps, xNb, yNb) <= zOrder(pic.sps, xCurr, yCurr)
+}
+
+// zOrder returns the z-scan order address within its CTB
+// of the minimum transform block containing given luma sample.
+func zOrder(s *sps, x, y int) int {
+	mask := 1<<uint(s.log2CtbSize) - 1
+	x = (x & mask) >> uint(s.log2MinTbSize)
+	y = (y & mask) >> uint(s.log2MinTbSize)
+
+	z := 0
+	for i := uint(0); i < 4; i++ {
+		z |= (x>>i&1)<<(2*i) | (y>>i&1)<<(2*i+1)
+	}
+	return z
+}
+
+// decodeCTU decodes a coding_tree_unit().
+func (sd *sliceDecoder) decodeCTU(ctbX, ctbY int) {
+	if sd.hdr.saoLuma || sd.hdr.saoChroma {
+		sd.decodeSAO(ctbX, ctbY)
+	}
+
+	log2 := sd.pic.sps.log2CtbSize
+	sd.codingQuadtree(ctbX<<uint(log2), ctbY<<uint(log2), log2, 0)
+}
+
+// decodeSAO decodes the sao() syntax of a CTB.
+func (sd *sliceDecoder) decodeSAO(rx, ry int) {
+	pic, rs := sd.pic, sd.ctbAddr
+	s := pic.sps
+	params := &pic.ctbs[rs].sao
+
+	if rx > 0 && rs-1 >= sd.hdr.sliceAddr && pic.tileOf(rs-1) == pic.tileOf(rs) {
+		if sd.c.decision(ctxSaoMerge) == 1 {
+			// sao_merge_left_flag
+			*params = pic.ctbs[rs-1].sao
+			return
+		}
+	}
+
+	up := rs - s.widthCtbs
+	if ry > 0 && up >= sd.hdr.sliceAddr && pic.tileOf(up) == pic.tileOf(rs) {
+		if sd.c.decision(ctxSaoMerge) == 1 {
+			// sao_merge_up_flag
+			*params = pic.ctbs[up].sao
+			return
+		}
+	}
+
+	for cIdx := 0; cIdx < 3; cIdx++ {
+		if (cIdx == 0 && !sd.hdr.saoLuma) || (cIdx > 0 && !sd.hdr.saoChroma) {
+			continue
+		}
+
+		sao := &params[cIdx]
+		if cIdx < 2 {
+			sao.typ = uint8(sd.c.saoTypeIdx())
+		} else {
+			sao.typ = params[1].typ
+		}
+		if sao.typ == 0 {
+			continue
+		}
+
+		bitDepth := s.bitDepthY
+		if cIdx > 0 {
+			bitDepth = s.bitDepthC
+		}
+		for i := range sao.offsets {
+			sao.offsets[i] = int8(sd.c.saoOffsetAbs(bitDepth))
+		}
+
+		if sao.typ == 1 {
+			// Band offset.
+			for i, v := range sao.offsets {
+				if v != 0 && sd.c.bypass() == 1 {
+					sao.offsets[i] = -v
+				}
+			}
+			sao.class = uint8(sd.c.bypassBits(5)) // sao_band_position
+		} else {
+			// Edge offset.
+			sao.offsets[2] = -sao.offsets[2]
+			sao.offsets[3] = -sao.offsets[3]
+			if cIdx < 2 {
+				sao.class = uint8(sd.c.bypassBits(2)) // sao_eo_class
+			} else {
+				sao.class = params[1].class
+			}
+		}
+	}
+}
+
+// codingQuadtree decodes a coding_quadtree().
+func (sd *sliceDecoder) codingQuadtree(x0, y0, log2 int, depth int) {
+	s, p := sd.pic.sps, sd.pic.pps
+	size := 1 << uint(log2)
+
+	var split bool
+	if x0+size <= s.width && y0+size <= s.height && log2 > s.log2MinCbSize {
+		inc := 0
+		if sd.available(x0, y0, x0-1, y0) && int(sd.pic.blockAt(x0-1, y0).depth) > depth {
+			inc++
+		}
+		if sd.available(x0, y0, x0, y0-1) && int(sd.pic.blockAt(x0, y0-1).depth) > depth {
+			inc++
+		}
+		split = sd.c.decision(ctxSplitCU+inc) == 1
+	} else {
+		split = log2 > s.log2MinCbSize
+	}
+
+	if log2 >= s.log2CtbSize-p.diffCuQPDeltaDepth {
+		// Start of a quantization group.
+		sd.cuQPDeltaCoded = false
+		sd.cuQPDelta = 0
+		sd.predictQP(x0, y0)
+	}
+
+	if !split {
+		sd.codingUnit(x0, y0, log2, depth)
+		return
+	}
+
+	half := size / 2
+	for i := 0; i < 4; i++ {
+		x, y := x0+(i&1)*half, y0+(i>>1)*half
+		if x < s.width && y < s.height && sd.r.err == nil {
+			sd.codingQuadtree(x, y, log2-1, depth+1)
+		}
+	}
+}
+
+// predictQP derives qPY_PRED for the quantization group
+// starting at given luma sample (8.6.1).
+func (sd *sliceDecoder) predictQP(xQg, yQg int) {
+	pic := sd.pic
+	mask := 1<<uint(pic.sps.log2CtbSize) - 1
+
+	// Neighbouring quantization groups are only used
+	// for prediction when in the same CTB, and
+	// otherwise the previous QpY is used.
+	qpA, qpB := pic.qpY, pic.qpY
+	if xQg&mask != 0 {
+		qpA = int(pic.blockAt(xQg-1, yQg).qp)
+	}
+	if yQg&mask != 0 {
+		qpB = int(pic.blockAt(xQg, yQg-1).qp)
+	}
+	sd.qpYPred = (qpA + qpB + 1) >> 1
+	sd.updateQP()
+}
+
+// updateQP derives QpY, Qp′Cb and Qp′Cr for the current
+// coding unit from qPY_PRED and CuQpDeltaVal (8.6.1).
+func (sd *sliceDecoder) updateQP() {
+	s, p := sd.pic.sps, sd.pic.pps
+	bdOffsetY := 6 * (s.bitDepthY - 8)
+	bdOffsetC := 6 * (s.bitDepthC - 8)
+
+	sd.qpY = (sd.qpYPred+sd.cuQPDelta+52+2*bdOffsetY)%(52+bdOffsetY) - bdOffsetY
+	sd.qpCb = chromaQP(clip3(-bdOffsetC, 57, sd.qpY+p.cbQPOffset+sd.hdr.cbQPOffset)) + bdOffsetC
+	sd.qpCr = chromaQP(clip3(-bdOffsetC, 57, sd.qpY+p.crQPOffset+sd.hdr.crQPOffset)) + bdOffsetC
+}
+
+// chromaQP maps qPi to QpC for 4:2:0 pictures (Table 8-10).
+func chromaQP(qpi int) int {
+	switch {
+	case qpi < 30:
+		return qpi
+	case qpi > 43:
+		return qpi - 6
+	default:
+		return int(chromaQPTable[qpi-30])
+	}
+}
+
+var chromaQPTable = [14]uint8{29, 30, 31, 32, 33, 33, 34, 34, 35, 35, 36, 36, 37, 37}
+
+// setBlocks calls fn for each block of a square area of luma samples.
+func (sd *sliceDecoder) setBlocks(x0, y0, size int, fn func(b *block)) {
+	pic := sd.pic
+	stride := pic.width[0] >> 2
+	for y := y0 >> 2; y < (y0+size)>>2; y++ {
+		for x := x0 >> 2; x < (x0+size)>>2; x++ {
+			fn(&pic.blocks[y*stride+x])
+		}
+	}
+}
+
+// codingUnit decodes a coding_unit().
+func (sd *sliceDecoder) codingUnit(x0, y0, log2 int, depth int) {
+	s, p := sd.pic.sps, sd.pic.pps
+	size := 1 << uint(log2)
+
+	sd.bypass = p.transquantBypass && sd.c.decision(ctxTransquantBypass) == 1
+	sd.nxn = log2 == s.log2MinCbSize && sd.c.partModeNxN()
+	sd.updateQP()
+
+	var flags uint8
+	if sd.bypass {
+		flags = blockNoFilter
+	}
+	sd.setBlocks(x0, y0, size, func(b *block) {
+		b.depth = uint8(depth)
+		b.flags = flags
+	})
+
+	pcm := !sd.nxn && s.pcm && log2 >= s.log2MinPcmSize && log2 <= s.log2MaxPcmSize &&
+		sd.c.terminate() == 1
+	if pcm {
+		sd.decodePCM(x0, y0, log2)
+	} else {
+		sd.decodeIntraModes(x0, y0, log2)
+		sd.maxTrafo = s.maxTrDepthIntra
+		if sd.nxn {
+			sd.maxTrafo++
+		}
+		sd.transformTree(x0, y0, x0, y0, log2, 0, 0, false, false)
+	}
+
+	qp := int8(sd.qpY)
+	sd.setBlocks(x0, y0, size, func(b *block) {
+		b.qp = qp
+	})
+	sd.pic.qpY = sd.qpY
+}
+
+// decodePCM decodes the pcm_sample() of a coding unit.
+func (sd *sliceDecoder) decodePCM(x0, y0, log2 int) {
+	pic, s := sd.pic, sd.pic.sps
+	size := 1 << uint(log2)
+
+	sd.setBlocks(x0, y0, size, func(b *block) {
+		b.mode = modeDC
+		if s.pcmLoopFilterOff {
+			b.flags |= blockNoFilter
+		}
+	})
+	sd.markEdges(x0, y0, size, blockEdgeV|blockEdgeH)
+
+	// Skip pcm_alignment_zero_bit.
+	sd.r.align()
+
+	read := func(c, x0, y0, size, bits, bitDepth int) {
+		plane, stride := pic.planes[c], pic.width[c]
+		for y := y0; y < y0+size; y++ {
+			for x := x0; x < x0+size; x++ {
+				plane[y*stride+x] = uint16(sd.r.u(bits) << uint(bitDepth-bits))
+			}
+		}
+	}
+
+	read(0, x0, y0, size, s.pcmBitDepthY, s.bitDepthY)
+	if s.chromaFormatIDC != 0 {
+		read(1, x0/2, y0/2, size/2, s.pcmBitDepthC, s.bitDepthC)
+		read(2, x0/2, y0/2, size/2, s.pcmBitDepthC, s.bitDepthC)
+	}
+
+	sd.c.start()
+}
+
+// markEdges sets given flags on the blocks along the left
+// and top edges of a transform block of luma samples.
+func (sd *sliceDecoder) markEdges(x0, y0, size int, flags uint8) {
+	pic := sd.pic
+	for i := 0; i < size; i += 4 {
+		pic.blockAt(x0, y0+i).flags |= flags &^ blockEdgeH
+		pic.blockAt(x0+i, y0).flags |= flags &^ blockEdgeV
+	}
+}
+
+// decodeIntraModes decodes the luma and chroma intra prediction
+// modes of a coding unit, deriving IntraPredModeY (8.4.2) for
+// each prediction block and IntraPredModeC (8.4.3).
+func (sd *sliceDecoder) decodeIntraModes(x0, y0, log2 int) {
+	pic, s := sd.pic, sd.pic.sps
+	parts, size := 1, 1<<uint(log2)
+	if sd.nxn {
+		parts, size = 4, size/2
+	}
+
+	var prevFlags [4]bool
+	for i := 0; i < parts; i++ {
+		prevFlags[i] = sd.c.decision(ctxPrevIntraLuma) == 1
+	}
+
+	for i := 0; i < parts; i++ {
+		xPb, yPb := x0+(i&1)*size, y0+(i>>1)*size
+
+		// Derive the candidate modes from the neighbouring
+		// blocks, the block above is only used if it's in
+		// the same CTB.
+		candA, candB := modeDC, modeDC
+		if sd.available(xPb, yPb, xPb-1, yPb) {
+			candA = int(pic.blockAt(xPb-1, yPb).mode)
+		}
+		if sd.available(xPb, yPb, xPb, yPb-1) && (yPb-1)>>uint(s.log2CtbSize) == yPb>>uint(s.log2CtbSize) {
+			candB = int(pic.blockAt(xPb, yPb-1).mode)
+		}
+
+		var cands [3]int
+		switch {
+		case candA == candB && candA < 2:
+			cands = [3]int{modePlanar, modeDC, modeVer}
+		case candA == candB:
+			cands = [3]int{candA, 2 + (candA+29)%32, 2 + (candA-2+1)%32}
+		default:
+			cands[0], cands[1] = candA, candB
+			switch {
+			case candA != modePlanar && candB != modePlanar:
+				cands[2] = modePlanar
+			case candA != modeDC && candB != modeDC:
+				cands[2] = modeDC
+			default:
+				cands[2] = modeVer
+			}
+		}
+
+		var mode int
+		if prevFlags[i] {
+			mode = cands[sd.c.mpmIdx()]
+		} else {
+			// Sort the candidates, and step the
+			// remaining mode over each of them.
+			if cands[0] > cands[1] {
+				cands[0], cands[1] = cands[1], cands[0]
+			}
+			if cands[0] > cands[2] {
+				cands[0], cands[2] = cands[2], cands[0]
+			}
+			if cands[1] > cands[2] {
+				cands[1], cands[2] = cands[2], cands[1]
+			}
+			mode = sd.c.bypassBits(5) // rem_intra_luma_pred_mode
+			for _, cand := range cands {
+				if mode >= cand {
+					mode++
+				}
+			}
+		}
+
+		sd.setBlocks(xPb, yPb, size, func(b *block) {
+			b.mode = uint8(mode)
+		})
+	}
+
+	if s.chromaFormatIDC == 0 {
+		return
+	}
+
+	lumaMode := int(pic.blockAt(x0, y0).mode)
+	switch v := sd.c.intraChromaPredMode(); v {
+	case 4:
+		sd.chromaMode = lumaMode
+	default:
+		sd.chromaMode = [4]int{modePlanar, modeVer, modeHor, modeDC}[v]
+		if sd.chromaMode == lumaMode {
+			sd.chromaMode = 34
+		}
+	}
+}
+
+// transformTree decodes a transform_tree(), given the cbf_cb
+// and cbf_cr flags of its parent transform tree, if any.
+func (sd *sliceDecoder) transformTree(x0, y0, xBase, yBase, log2, depth, blkIdx int, parentCb, parentCr bool) {
+	s := sd.pic.sps
+
+	var split bool
+	if log2 <= s.log2MaxTbSize && log2 > s.log2MinTbSize && depth < sd.maxTrafo && !(sd.nxn && depth == 0) {
+		split = sd.c.decision(ctxSplitTransform+5-log2) == 1
+	} else {
+		split = log2 > s.log2MaxTbSize || (sd.nxn && depth == 0)
+	}
+
+	// The chroma of 4x4 luma blocks is coded with the
+	// last block, using the flags of the parent tree.
+	cbfCb, cbfCr := parentCb, parentCr
+	if log2 > 2 && s.chromaFormatIDC != 0 {
+		cbfCb, cbfCr = false, false
+		if depth == 0 || parentCb {
+			cbfCb = sd.c.decision(ctxCbfChroma+depth) == 1
+		}
+		if depth == 0 || parentCr {
+			cbfCr = sd.c.decision(ctxCbfChroma+depth) == 1
+		}
+	}
+
+	if split {
+		half := 1 << uint(log2-1)
+		for i := 0; i < 4 && sd.r.err == nil; i++ {
+			x, y := x0+(i&1)*half, y0+(i>>1)*half
+			sd.transformTree(x, y, x0, y0, log2-1, depth+1, i, cbfCb, cbfCr)
+		}
+		return
+	}
+
+	inc := 0
+	if depth == 0 {
+		inc = 1
+	}
+	cbfLuma := sd.c.decision(ctxCbfLuma+inc) == 1
+	sd.transformUnit(x0, y0, xBase, yBase, log2, blkIdx, cbfLuma, cbfCb, cbfCr)
+}
+
+// transformUnit decodes a transform_unit(), and reconstructs
+// its samples using intra prediction and the decoded residual.
+func (sd *sliceDecoder) transformUnit(x0, y0, xBase, yBase, log2, blkIdx int, cbfLuma, cbfCb, cbfCr bool) {
+	s, p := sd.pic.sps, sd.pic.pps
+	chroma := s.chromaFormatIDC != 0
+
+	sd.markEdges(x0, y0, 1<<uint(log2), blockEdgeV|blockEdgeH)
+
+	if p.cuQPDelta && !sd.cuQPDeltaCoded && (cbfLuma || (chroma && (cbfCb || cbfCr))) {
+		sd.cuQPDelta = sd.c.cuQPDelta()
+		sd.cuQPDeltaCoded = true
+		bdOffsetY := 6 * (s.bitDepthY - 8)
+		if sd.cuQPDelta < -(26+bdOffsetY/2) || sd.cuQPDelta > 25+bdOffsetY/2 {
+			sd.fail(fmt.Errorf("invalid cu_qp_delta %d", sd.cuQPDelta))
+			return
+		}
+		sd.updateQP()
+	}
+
+	lumaMode := int(sd.pic.blockAt(x0, y0).mode)
+	sd.reconstruct(0, x0, y0, log2, lumaMode, cbfLuma)
+
+	if !chroma {
+		return
+	}
+
+	if log2 > 2 {
+		sd.reconstruct(1, x0/2, y0/2, log2-1, sd.chromaMode, cbfCb)
+		sd.reconstruct(2, x0/2, y0/2, log2-1, sd.chromaMode, cbfCr)
+	} else if blkIdx == 3 {
+		sd.reconstruct(1, xBase/2, yBase/2, 2, sd.chromaMode, cbfCb)
+		sd.reconstruct(2, xBase/2, yBase/2, 2, sd.chromaMode, cbfCr)
+	}
+}
+
+// reconstruct predicts a transform block of given component,
+// and if coded, adds the residual decoded from its residual_coding().
+func (sd *sliceDecoder) reconstruct(cIdx, x0, y0, log2, mode int, coded bool) {
+	sd.predictIntra(cIdx, x0, y0, log2, mode)
+	if !coded || sd.r.err != nil {
+		return
+	}
+
+	transformSkip := sd.residualCoding(log2, cIdx, mode)
+	if sd.r.err != nil {
+		return
+	}
+	sd.decodeResidual(cIdx, log2, transformSkip)
+
+	pic := sd.pic
+	plane, stride := pic.planes[cIdx], pic.width[cIdx]
+	max := int32(1)<<uint(pic.sps.bitDepthY) - 1
+	if cIdx > 0 {
+		max = int32(1)<<uint(pic.sps.bitDepthC) - 1
+	}
+
+	n := 1 << uint(log2)
+	for y := 0; y < n; y++ {
+		row := plane[(y0+y)*stride+x0:]
+		res := sd.res[y*n:]
+		for x := 0; x < n; x++ {
+			v := int32(row[x]) + res[x]
+			if v < 0 {
+				v = 0
+			} else if v > max {
+				v = max
+			}
+			row[x] = uint16(v)
+		}
+	}
+}
+
+// ctxIdxMap maps the positions of 4x4 blocks to
+// sig_coeff_flag context increments (Table 9-50).
+var ctxIdxMap = [16]uint8{0, 1, 4, 5, 2, 3, 4, 5, 6, 6, 8, 8, 7, 7, 8, 8}
+
+// residualCoding decodes a residual_coding() into the coefficients
+// of the slice decoder, in raster order, returning the value of
+// transform_skip_flag. The intra prediction mode of the block
+// is used to select the scan order.
+func (sd *sliceDecoder) residualCoding(log2, cIdx, predMode int) bool {
+	c, p := &sd.c, sd.pic.pps
+	n := 1 << uint(log2)
+	coeffs := sd.coeffs[:n*n]
+	for i := range coeffs {
+		coeffs[i] = 0
+	}
+
+	transformSkip := false
+	if p.transformSkip && !sd.bypass && log2 == 2 {
+		inc := 0
+		if cIdx > 0 {
+			inc = 1
+		}
+		transformSkip = c.decision(ctxTransformSkip+inc) == 1
+	}
+
+	lastX := c.lastSigCoeffPrefix(ctxLastX, log2, cIdx)
+	lastY := c.lastSigCoeffPrefix(ctxLastY, log2, cIdx)
+	lastX = c.lastSigCoeff(lastX)
+	lastY = c.lastSigCoeff(lastY)
+
+	scanIdx := scanDiag
+	if log2 == 2 || (log2 == 3 && cIdx == 0) {
+		if predMode >= 6 && predMode <= 14 {
+			scanIdx = scanVer
+		} else if predMode >= 22 && predMode <= 30 {
+			scanIdx = scanHor
+		}
+	}
+	if scanIdx == scanVer {
+		lastX, lastY = lastY, lastX
+	}
+	if lastX >= n || lastY >= n {
+		sd.fail(errors.New("invalid last significant coefficient"))
+		return false
+	}
+
+	// Find the sub-block and scan position of
+	// the last significant coefficient.
+	log2Sb := log2 - 2
+	nSb := 1 << uint(log2Sb)
+	sbScan := scanOrder[log2Sb][scanIdx]
+	posScan := scanOrder[2][scanIdx]
+	lastSubBlock, lastScanPos := 0, 0
+	for i, pos := range sbScan {
+		if int(pos[0]) == lastX>>2 && int(pos[1]) == lastY>>2 {
+			lastSubBlock = i
+		}
+	}
+	for i, pos := range posScan {
+		if int(pos[0]) == lastX&3 && int(pos[1]) == lastY&3 {
+			lastScanPos = i
+		}
+	}
+
+	for y := 0; y < nSb; y++ {
+		for x := 0; x < nSb; x++ {
+			sd.codedSbFlags[x][y] = false
+		}
+	}
+
+	chromaInc := 0
+	if cIdx > 0 {
+		chromaInc = 1
+	}
+
+	greater1Ctx := 1
+	for i := lastSubBlock; i >= 0; i-- {
+		xS, yS := int(sbScan[i][0]), int(sbScan[i][1])
+
+		// Neighbouring coded sub-block flags.
+		var right, below int
+		if xS < nSb-1 && sd.codedSbFlags[xS+1][yS] {
+			right = 1
+		}
+		if yS < nSb-1 && sd.codedSbFlags[xS][yS+1] {
+			below = 1
+		}
+
+		inferSbDc := false
+		if i < lastSubBlock && i > 0 {
+			inc := 0
+			if right+below > 0 {
+				inc = 1
+			}
+			sd.codedSbFlags[xS][yS] = c.decision(ctxCodedSubBlock+inc+2*chromaInc) == 1
+			inferSbDc = true
+		} else {
+			sd.codedSbFlags[xS][yS] = true
+		}
+
+		if !sd.codedSbFlags[xS][yS] {
+			continue
+		}
+
+		// Decode sig_coeff_flags, and make a list of the scan
+		// positions of the significant coefficients.
+		numSig := 0
+		start := 15
+		if i == lastSubBlock {
+			start = lastScanPos - 1
+			sd.sigPos[0] = lastScanPos
+			numSig = 1
+		}
+		prevCsbf := right | below<<1
+		for sp := start; sp >= 0; sp-- {
+			xC := xS<<2 + int(posScan[sp][0])
+			yC := yS<<2 + int(posScan[sp][1])
+			sig := true
+			if sp > 0 || !inferSbDc {
+				inc := sigCtxInc(log2, cIdx, xC, yC, prevCsbf, scanIdx)
+				sig = c.decision(ctxSig+inc) == 1
+				if sig {
+					inferSbDc = false
+				}
+			}
+			if sig {
+				sd.sigPos[numSig] = sp
+				numSig++
+			}
+		}
+
+		if numSig == 0 {
+			continue
+		}
+
+		// Decode coeff_abs_level_greater1_flags
+		// and coeff_abs_level_greater2_flag.
+		ctxSet := 0
+		if i > 0 && cIdx == 0 {
+			ctxSet = 2
+		}
+		if greater1Ctx == 0 {
+			ctxSet++
+		}
+		greater1Ctx = 1
+		firstGreater1 := -1
+		for k := 0; k < numSig; k++ {
+			sd.greater1[k] = false
+			if k >= 8 {
+				continue
+			}
+			sd.greater1[k] = c.decision(ctxGreater1+16*chromaInc+ctxSet*4+greater1Ctx) == 1
+			if sd.greater1[k] {
+				greater1Ctx = 0
+				if firstGreater1 < 0 {
+					firstGreater1 = k
+				}
+			} else if greater1Ctx > 0 && greater1Ctx < 3 {
+				greater1Ctx++
+			}
+		}
+
+		greater2 := false
+		if firstGreater1 >= 0 {
+			greater2 = c.decision(ctxGreater2+4*chromaInc+ctxSet) == 1
+		}
+
+		// Decode coeff_sign_flags, the sign of the first coefficient
+		// in scan order may be hidden in the parity of the sum of
+		// the absolute levels.
+		signHidden := p.signDataHiding && !sd.bypass && sd.sigPos[0]-sd.sigPos[numSig-1] > 3
+		var signs uint32
+		for k := 0; k < numSig; k++ {
+			if !signHidden || k < numSig-1 {
+				signs |= uint32(c.bypass()) << uint(k)
+			}
+		}
+
+		// Decode coeff_abs_level_remaining, and set
+		// the values of the coefficients.
+		rice, sumAbs := 0, 0
+		for k := 0; k < numSig; k++ {
+			base := 1
+			threshold := 1
+			if sd.greater1[k] {
+				base++
+			}
+			if k == firstGreater1 && greater2 {
+				base++
+			}
+			if k < 8 {
+				threshold = 2
+				if k == firstGreater1 {
+					threshold = 3
+				}
+			}
+
+			abs := base
+			if base == threshold {
+				abs += c.coeffAbsLevelRemaining(rice)
+				if abs > 3<<uint(rice) && rice < 4 {
+					rice++
+				}
+			}
+
+			v := abs
+			if signs>>uint(k)&1 == 1 {
+				v = -v
+			}
+			if signHidden {
+				sumAbs += abs
+				if k == numSig-1 && sumAbs%2 == 1 {
+					v = -v
+				}
+			}
+
+			pos := posScan[sd.sigPos[k]]
+			xC := xS<<2 + int(pos[0])
+			yC := yS<<2 + int(pos[1])
+			coeffs[yC*n+xC] = int32(v)
+		}
+	}
+
+	return transformSkip
+}
+
+// sigCtxInc returns the ctxInc of a sig_coeff_flag (9.3.4.2.5).
+func sigCtxInc(log2, cIdx, xC, yC, prevCsbf, scanIdx int) int {
+	var sigCtx int
+	switch {
+	case log2 == 2:
+		sigCtx = int(ctxIdxMap[yC<<2+xC])
+	case xC+yC == 0:
+		sigCtx = 0
+	default:
+		xP, yP := xC&3, yC&3
+		switch prevCsbf {
+		case 0:
+			switch {
+			case xP+yP == 0:
+				sigCtx = 2
+			case xP+yP < 3:
+				sigCtx = 1
+			}
+		case 1:
+			sigCtx = 2 - clip3(0, 2, yP)
+		case 2:
+			sigCtx = 2 - clip3(0, 2, xP)
+		default:
+			sigCtx = 2
+		}
+
+		if cIdx == 0 {
+			if xC>>2 > 0 || yC>>2 > 0 {
+				sigCtx += 3
+			}
+			switch {
+			case log2 > 3:
+				sigCtx += 21
+			case scanIdx == scanDiag:
+				sigCtx += 9
+			default:
+				sigCtx += 15
+			}
+		} else {
+			if log2 == 3 {
+				sigCtx += 9
+			} else {
+				sigCtx += 12
+			}
+		}
+	}
+
+	if cIdx > 0 {
+		return 27 + sigCtx
+	}
+	return sigCtx
+}
@@ -0,0 +1,222 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package hevc
+
+// Scan orders, as scanIdx values.
+const (
+	scanDiag = 0 // up-right diagonal
+	scanHor  = 1 // horizontal
+	scanVer  = 2 // vertical
+)
+
+var (
+	// scanOrder holds the (x, y) positions of each scan
+	// order, by log2 block size and scanIdx (6.5.3 to 6.5.5).
+	scanOrder = func() (t [4][3][][2]uint8) {
+		for log2 := range t {
+			size := 1 << uint(log2)
+
+			diag := make([][2]uint8, 0, size*size)
+			for x, y := 0, 0; len(diag) < size*size; {
+				for y >= 0 {
+					if x < size && y < size {
+						diag = append(diag, [2]uint8{uint8(x), uint8(y)})
+					}
+					y--
+					x++
+				}
+				y, x = x, 0
+			}
+
+			hor := make([][2]uint8, 0, size*size)
+			ver := make([][2]uint8, 0, size*size)
+			for i := 0; i < size; i++ {
+				for j := 0; j < size; j++ {
+					hor = append(hor, [2]uint8{uint8(j), uint8(i)})
+					ver = append(ver, [2]uint8{uint8(i), uint8(j)})
+				}
+			}
+
+			t[log2] = [3][][2]uint8{diag, hor, ver}
+		}
+		return
+	}()
+
+	// levelScale holds the scaling factors by qP%6 (8.6.3).
+	levelScale = [6]int64{40, 45, 51, 57, 64, 72}
+
+	// dst4x4 holds the transform matrix of
+	// the 4x4 DST-like transform (8.6.4.2).
+	dst4x4 = [4][4]int32{
+		{29, 55, 74, 84},
+		{74, 74, 0, -74},
+		{84, -29, -74, 55},
+		{55, -84, 74, -29},
+	}
+
+	// dct holds the transform matrices of the DCT-like
+	// transforms by log2 size (8.6.4.2), where each row
+	// holds a basis function. The matrices are derived
+	// from the 32x32 matrix, which is built from the
+	// magnitudes of its coefficients by phase.
+	dct = func() (t [6][][]int32) {
+		mags := [33]int32{
+			90, 90, 90, 90, 89, 88, 87, 85, 83, 82, 80, 78, 75, 73, 70, 67,
+			64, 61, 57, 54, 50, 46, 43, 38, 36, 31, 25, 22, 18, 13, 9, 4, 0,
+		}
+		coeff := func(k, n int) int32 {
+			if k == 0 {
+				return 64
+			}
+			switch q := (2*n + 1) * k % 128; {
+			case q <= 32:
+				return mags[q]
+			case q <= 64:
+				return -mags[64-q]
+			case q <= 96:
+				return -mags[q-64]
+			default:
+				return mags[128-q]
+			}
+		}
+
+		for log2 := 2; log2 <= 5; log2++ {
+			size := 1 << uint(log2)
+			m := make([][]int32, size)
+			for k := range m {
+				m[k] = make([]int32, size)
+				for n := range m[k] {
+					m[k][n] = coeff(k*32/size, n)
+				}
+			}
+			t[log2] = m
+		}
+		return
+	}()
+)
+
+// clip3 clamps v to the range [lo, hi].
+func clip3(lo, hi, v int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// decodeResidual scales and transforms the decoded coefficients
+// of a transform block, into the residual samples of the slice
+// decoder (8.6.2 to 8.6.4).
+func (sd *sliceDecoder) decodeResidual(cIdx, log2 int, transformSkip bool) {
+	s, p := sd.pic.sps, sd.pic.pps
+	n := 1 << uint(log2)
+	coeffs := sd.coeffs[:n*n]
+	res := sd.res[:n*n]
+
+	if sd.bypass {
+		copy(res, coeffs)
+		return
+	}
+
+	bitDepth, qp := s.bitDepthY, sd.qpY+6*(s.bitDepthY-8)
+	switch cIdx {
+	case 1:
+		bitDepth, qp = s.bitDepthC, sd.qpCb
+	case 2:
+		bitDepth, qp = s.bitDepthC, sd.qpCr
+	}
+
+	// Scaling process for transform coefficients (8.6.3).
+	var factors []uint8
+	if s.scalingEnabled {
+		scaling := s.scaling
+		if p.scaling != nil {
+			scaling = p.scaling
+		}
+		factors = scaling.factors[log2-2][cIdx]
+	}
+
+	bdShift := uint(bitDepth + log2 - 5)
+	scale := levelScale[qp%6] << uint(qp/6)
+	maxX, maxY := 0, 0
+	for i, c := range coeffs {
+		if c == 0 {
+			continue
+		}
+		m := int64(16)
+		if factors != nil {
+			m = int64(factors[i])
+		}
+		v := (int64(c)*m*scale + 1<<(bdShift-1)) >> bdShift
+		coeffs[i] = int32(clip3(-32768, 32767, int(v)))
+		if x := i & (n - 1); x > maxX {
+			maxX = x
+		}
+		if y := i >> uint(log2); y > maxY {
+			maxY = y
+		}
+	}
+
+	// Transformation process (8.6.4.2), and the final
+	// scaling of the residual samples (8.6.2).
+	shift := uint(20 - bitDepth)
+	round := int32(1) << (shift - 1)
+
+	if transformSkip {
+		for i, c := range coeffs {
+			res[i] = (c<<7 + round) >> shift
+		}
+		return
+	}
+
+	var mat [][]int32
+	if n == 4 && cIdx == 0 {
+		mat = [][]int32{dst4x4[0][:], dst4x4[1][:], dst4x4[2][:], dst4x4[3][:]}
+	} else {
+		mat = dct[log2]
+	}
+
+	// Transform the columns, into the residual
+	// buffer as intermediate storage.
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			var sum int32
+			if x <= maxX {
+				for k := 0; k <= maxY; k++ {
+					sum += mat[k][y] * coeffs[k*n+x]
+				}
+			}
+			res[y*n+x] = int32(clip3(-32768, 32767, int((sum+64)>>7)))
+		}
+	}
+
+	// Transform the rows.
+	var row [32]int32
+	for y := 0; y < n; y++ {
+		copy(row[:n], res[y*n:])
+		for x := 0; x < n; x++ {
+			var sum int32
+			for k := 0; k <= maxX; k++ {
+				sum += mat[k][x] * row[k]
+			}
+			res[y*n+x] = (sum + round) >> shift
+		}
+	}
+}
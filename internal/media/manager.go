@@ -39,7 +39,15 @@ var SupportedMIMETypes = []string{
 	mimeImageGif,
 	mimeImagePng,
 	mimeImageWebp,
+	mimeImageHeif,
+	mimeImageAvif,
 	mimeVideoMp4,
+	mimeVideoQuicktime,
+	mimeVideoWebm,
+	mimeAudioMpeg,
+	mimeAudioOgg,
+	mimeAudioFlac,
+	mimeAudioWav,
 }
 
 var SupportedEmojiMIMETypes = []string{
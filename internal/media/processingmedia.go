@@ -30,6 +30,7 @@ import (
 	"github.com/disintegration/imaging"
 	"github.com/h2non/filetype"
 	terminator "github.com/superseriousbusiness/exif-terminator"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/log"
@@ -176,9 +177,12 @@ func (p *ProcessingMedia) store(ctx context.Context) error {
 	r := io.MultiReader(bytes.NewReader(hdrBuf), rc)
 
 	switch info.Extension {
-	case "mp4":
+	case "mp4", "mov", "webm":
 		p.media.Type = gtsmodel.FileTypeVideo
 
+	case "mp3", "ogg", "flac", "wav":
+		p.media.Type = gtsmodel.FileTypeAudio
+
 	case "gif":
 		p.media.Type = gtsmodel.FileTypeImage
 
@@ -192,6 +196,34 @@ func (p *ProcessingMedia) store(ctx context.Context) error {
 			}
 		}
 
+	case mimeHeif, mimeAvif:
+		p.media.Type = gtsmodel.FileTypeImage
+
+		// HEIF files can't be
+		// streamed, so read fully.
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return gtserror.Newf("error reading incoming media: %w", err)
+		}
+
+		if info.Extension == mimeHeif && config.GetMediaImageTranscode() {
+			// Browsers can't display HEIC images, so transcode
+			// (which also drops metadata) to a web-safe format.
+			tr, typ, err := transcodeHEIF(data)
+			if err == nil {
+				r, info = tr, typ
+				break
+			}
+
+			// Fall back to storing the original.
+			log.Warnf(ctx, "error transcoding heif image, storing as-is: %v", err)
+		}
+
+		if err := stripHEIFMetadata(data); err != nil {
+			return gtserror.Newf("error cleaning metadata: %w", err)
+		}
+		r = bytes.NewReader(data)
+
 	default:
 		return gtserror.Newf("unsupported file type: %s", info.Extension)
 	}
@@ -253,13 +285,32 @@ func (p *ProcessingMedia) finish(ctx context.Context) error {
 	var fullImg *gtsImage
 
 	switch p.media.File.ContentType {
-	// .jpeg, .gif, .webp image type
-	case mimeImageJpeg, mimeImageGif, mimeImageWebp:
+	// .jpeg, .gif image type
+	case mimeImageJpeg, mimeImageGif:
 		fullImg, err = decodeImage(rc, imaging.AutoOrientation(true))
 		if err != nil {
 			return gtserror.Newf("error decoding image: %w", err)
 		}
 
+	// .webp image (may be animated)
+	case mimeImageWebp:
+		fullImg, err = decodeWebP(rc)
+		if err != nil {
+			return gtserror.Newf("error decoding image: %w", err)
+		}
+
+	// .heif, .avif image types
+	case mimeImageHeif, mimeImageAvif:
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			return gtserror.Newf("error reading image: %w", err)
+		}
+
+		fullImg, err = decodeHEIF(data)
+		if err != nil {
+			return gtserror.Newf("error decoding image: %w", err)
+		}
+
 	// .png image (requires ancillary chunk stripping)
 	case mimeImagePng:
 		fullImg, err = decodeImage(&pngAncillaryChunkStripper{
@@ -269,9 +320,9 @@ func (p *ProcessingMedia) finish(ctx context.Context) error {
 			return gtserror.Newf("error decoding image: %w", err)
 		}
 
-	// .mp4 video type
-	case mimeVideoMp4:
-		video, err := decodeVideoFrame(rc)
+	// .mp4, .mov, .webm video types
+	case mimeVideoMp4, mimeVideoQuicktime, mimeVideoWebm:
+		video, err := decodeVideoFrame(rc, p.media.File.ContentType)
 		if err != nil {
 			return gtserror.Newf("error decoding video: %w", err)
 		}
@@ -283,6 +334,24 @@ func (p *ProcessingMedia) finish(ctx context.Context) error {
 		p.media.FileMeta.Original.Duration = &video.duration
 		p.media.FileMeta.Original.Framerate = &video.framerate
 		p.media.FileMeta.Original.Bitrate = &video.bitrate
+
+	// .mp3, .ogg, .flac, .wav audio types
+	case mimeAudioMpeg, mimeAudioOgg, mimeAudioFlac, mimeAudioWav:
+		audio, err := decodeAudio(rc, p.media.File.ContentType, int64(p.media.File.FileSize))
+		if err != nil {
+			return gtserror.Newf("error decoding audio: %w", err)
+		}
+
+		// Use cover art as image if
+		// embedded, else a placeholder.
+		fullImg = audio.cover
+		if fullImg == nil {
+			fullImg = audioPlaceholder()
+		}
+
+		// Set audio metadata in attachment info.
+		p.media.FileMeta.Original.Duration = &audio.duration
+		p.media.FileMeta.Original.Bitrate = &audio.bitrate
 	}
 
 	// The image should be in-memory by now.
@@ -290,11 +359,14 @@ func (p *ProcessingMedia) finish(ctx context.Context) error {
 		return gtserror.Newf("error closing file: %w", err)
 	}
 
-	// Set full-size dimensions in attachment info.
-	p.media.FileMeta.Original.Width = int(fullImg.Width())
-	p.media.FileMeta.Original.Height = int(fullImg.Height())
-	p.media.FileMeta.Original.Size = int(fullImg.Size())
-	p.media.FileMeta.Original.Aspect = fullImg.AspectRatio()
+	// Set full-size dimensions in attachment info
+	// (audio has none; its image is only a thumbnail).
+	if p.media.Type != gtsmodel.FileTypeAudio {
+		p.media.FileMeta.Original.Width = int(fullImg.Width())
+		p.media.FileMeta.Original.Height = int(fullImg.Height())
+		p.media.FileMeta.Original.Size = int(fullImg.Size())
+		p.media.FileMeta.Original.Aspect = fullImg.AspectRatio()
+	}
 
 	// Calculate attachment thumbnail file path
 	p.media.Thumbnail.Path = fmt.Sprintf(
@@ -27,6 +27,7 @@ import (
 const (
 	mimeImage = "image"
 	mimeVideo = "video"
+	mimeAudio = "audio"
 
 	mimeJpeg      = "jpeg"
 	mimeImageJpeg = mimeImage + "/" + mimeJpeg
@@ -40,8 +41,32 @@ const (
 	mimeWebp      = "webp"
 	mimeImageWebp = mimeImage + "/" + mimeWebp
 
+	mimeHeif      = "heif"
+	mimeImageHeif = mimeImage + "/" + mimeHeif
+
+	mimeAvif      = "avif"
+	mimeImageAvif = mimeImage + "/" + mimeAvif
+
 	mimeMp4      = "mp4"
 	mimeVideoMp4 = mimeVideo + "/" + mimeMp4
+
+	mimeQuicktime      = "quicktime"
+	mimeVideoQuicktime = mimeVideo + "/" + mimeQuicktime
+
+	mimeWebm      = "webm"
+	mimeVideoWebm = mimeVideo + "/" + mimeWebm
+
+	mimeMpeg      = "mpeg"
+	mimeAudioMpeg = mimeAudio + "/" + mimeMpeg
+
+	mimeOgg      = "ogg"
+	mimeAudioOgg = mimeAudio + "/" + mimeOgg
+
+	mimeFlac      = "x-flac"
+	mimeAudioFlac = mimeAudio + "/" + mimeFlac
+
+	mimeWav      = "x-wav"
+	mimeAudioWav = mimeAudio + "/" + mimeWav
 )
 
 // EmojiMaxBytes is the maximum permitted bytes of an emoji upload (50kb)
@@ -18,12 +18,16 @@
 package media
 
 import (
+	"errors"
 	"fmt"
+	"image"
 	"io"
 
 	"github.com/abema/go-mp4"
 	"github.com/superseriousbusiness/gotosocial/internal/iotools"
 	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/media/h264"
+	"github.com/superseriousbusiness/gotosocial/internal/media/hevc"
 )
 
 type gtsVideo struct {
@@ -33,9 +37,17 @@ type gtsVideo struct {
 	framerate float32
 }
 
-// decodeVideoFrame decodes and returns an image from a single frame in the given video stream.
-// (note: currently this only returns a blank image resized to fit video dimensions).
-func decodeVideoFrame(r io.Reader) (*gtsVideo, error) {
+// decodeVideoFrame decodes and returns an image from a single frame in the given
+// video stream of content type, along with metadata about the video. Where the
+// frame can't be decoded, e.g. due to an unsupported codec, a blank image resized
+// to fit the video dimensions is returned instead.
+func decodeVideoFrame(r io.Reader, contentType string) (*gtsVideo, error) {
+	if contentType == mimeVideoWebm {
+		// webm can be
+		// read as a stream.
+		return decodeWebM(r)
+	}
+
 	// we need a readseeker to decode the video...
 	tfs, err := iotools.TempFileSeeker(r)
 	if err != nil {
@@ -47,23 +59,44 @@ func decodeVideoFrame(r io.Reader) (*gtsVideo, error) {
 		}
 	}()
 
+	// mp4 and quicktime share
+	// the same box structure.
+	return decodeMP4(tfs)
+}
+
+// decodeMP4 decodes a frame and metadata from an mp4 or quicktime video.
+func decodeMP4(rs io.ReadSeeker) (*gtsVideo, error) {
 	// probe the video file to extract useful metadata from it; for methodology, see:
 	// https://github.com/abema/go-mp4/blob/7d8e5a7c5e644e0394261b0cf72fef79ce246d31/mp4tool/probe/probe.go#L85-L154
-	info, err := mp4.Probe(tfs)
+	info, err := mp4.Probe(rs)
 	if err != nil {
 		return nil, fmt.Errorf("error during mp4 probe: %w", err)
 	}
 
+	// mp4.Probe only reports AVC video tracks,
+	// so look up any HEVC tracks separately.
+	hevcTracks, err := mp4HEVCTracks(rs)
+	if err != nil {
+		return nil, err
+	}
+
 	var (
 		width        int
 		height       int
 		videoBitrate uint64
 		audioBitrate uint64
+		videoTrack   *mp4.Track
 		video        gtsVideo
 	)
 
 	for _, tr := range info.Tracks {
-		if tr.AVC == nil {
+		var w, h int
+		switch hvc := hevcTracks[tr.TrackID]; {
+		case tr.AVC != nil:
+			w, h = int(tr.AVC.Width), int(tr.AVC.Height)
+		case hvc != nil:
+			w, h = hvc.width, hvc.height
+		default:
 			// audio track
 			if br := tr.Samples.GetBitrate(tr.Timescale); br > audioBitrate {
 				audioBitrate = br
@@ -78,11 +111,12 @@ func decodeVideoFrame(r io.Reader) (*gtsVideo, error) {
 		}
 
 		// video track
-		if w := int(tr.AVC.Width); w > width {
+		if w > width {
 			width = w
+			videoTrack = tr
 		}
 
-		if h := int(tr.AVC.Height); h > height {
+		if h > height {
 			height = h
 		}
 
@@ -102,7 +136,263 @@ func decodeVideoFrame(r io.Reader) (*gtsVideo, error) {
 	// (since they're both playing at the same time)
 	video.bitrate = audioBitrate + videoBitrate
 
-	// Check for empty video metadata.
+	if err := video.checkMetadata(width, height); err != nil {
+		return nil, err
+	}
+
+	// Decode first key frame of the widest video track.
+	video.frame = videoFrame(width, height, func() (image.Image, error) {
+		if hvc := hevcTracks[videoTrack.TrackID]; hvc != nil {
+			return decodeHEVC(hvc.config, hvc.width, hvc.height, mp4Samples(rs, videoTrack))
+		}
+		return decodeMP4Frame(rs, videoTrack)
+	})
+
+	return &video, nil
+}
+
+// decodeMP4Frame decodes the first key frame of the given AVC video track.
+func decodeMP4Frame(rs io.ReadSeeker, track *mp4.Track) (image.Image, error) {
+	config, err := mp4AVCConfig(rs, track.TrackID)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeH264(config, int(track.AVC.Width), int(track.AVC.Height), mp4Samples(rs, track))
+}
+
+// mp4Samples returns a function returning each
+// sample of the given track in turn, or io.EOF
+// once there are no samples left.
+func mp4Samples(rs io.ReadSeeker, track *mp4.Track) func() ([]byte, error) {
+	var (
+		chunk  int
+		sample int
+		offset uint64
+		left   uint32
+	)
+
+	return func() ([]byte, error) {
+		// Move to the next chunk
+		// once this one is exhausted.
+		for left == 0 {
+			if chunk >= len(track.Chunks) {
+				return nil, io.EOF
+			}
+			offset = track.Chunks[chunk].DataOffset
+			left = track.Chunks[chunk].SamplesPerChunk
+			chunk++
+		}
+
+		if sample >= len(track.Samples) {
+			return nil, io.EOF
+		}
+
+		size := track.Samples[sample].Size
+		b := make([]byte, size)
+		if _, err := rs.Seek(int64(offset), io.SeekStart); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(rs, b); err != nil {
+			return nil, err
+		}
+
+		offset += uint64(size)
+		left--
+		sample++
+
+		return b, nil
+	}
+}
+
+// mp4HEVCTrack contains details of an HEVC
+// video track, from its sample description.
+type mp4HEVCTrack struct {
+	width  int
+	height int
+	config []byte // hvcC box payload
+}
+
+// mp4HEVCTracks returns details of each HEVC
+// (hvc1 or hev1) video track, keyed by track ID.
+func mp4HEVCTracks(rs io.ReadSeeker) (map[uint32]*mp4HEVCTrack, error) {
+	traks, err := mp4.ExtractBox(rs, nil, mp4.BoxPath{mp4.BoxTypeMoov(), mp4.BoxTypeTrak()})
+	if err != nil {
+		return nil, fmt.Errorf("error extracting tracks: %w", err)
+	}
+
+	tracks := make(map[uint32]*mp4HEVCTrack)
+	for _, trak := range traks {
+		for _, typ := range []mp4.BoxType{mp4.BoxTypeHvc1(), mp4.BoxTypeHev1()} {
+			stsd := mp4.BoxPath{
+				mp4.BoxTypeMdia(),
+				mp4.BoxTypeMinf(),
+				mp4.BoxTypeStbl(),
+				mp4.BoxTypeStsd(),
+				typ,
+			}
+
+			entry, err := mp4.ExtractBoxWithPayload(rs, trak, stsd)
+			if err != nil {
+				return nil, fmt.Errorf("error extracting hevc sample entry: %w", err)
+			}
+
+			if len(entry) == 0 {
+				continue
+			}
+
+			hvcC, err := mp4.ExtractBox(rs, trak, append(stsd, mp4.BoxTypeHvcC()))
+			if err != nil {
+				return nil, fmt.Errorf("error extracting hevc configuration: %w", err)
+			}
+
+			if len(hvcC) == 0 {
+				continue
+			}
+
+			tkhd, err := mp4.ExtractBoxWithPayload(rs, trak, mp4.BoxPath{mp4.BoxTypeTkhd()})
+			if err != nil {
+				return nil, fmt.Errorf("error extracting track header: %w", err)
+			}
+
+			if len(tkhd) == 0 {
+				continue
+			}
+
+			config, err := mp4BoxPayload(rs, hvcC[0])
+			if err != nil {
+				return nil, err
+			}
+
+			vse := entry[0].Payload.(*mp4.VisualSampleEntry)
+			tracks[tkhd[0].Payload.(*mp4.Tkhd).TrackID] = &mp4HEVCTrack{
+				width:  int(vse.Width),
+				height: int(vse.Height),
+				config: config,
+			}
+			break
+		}
+	}
+
+	return tracks, nil
+}
+
+// mp4BoxPayload reads the raw payload of the given box.
+func mp4BoxPayload(rs io.ReadSeeker, box *mp4.BoxInfo) ([]byte, error) {
+	b := make([]byte, box.Size-box.HeaderSize)
+	if _, err := rs.Seek(int64(box.Offset+box.HeaderSize), io.SeekStart); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(rs, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// mp4AVCConfig returns the raw AVC decoder configuration
+// record (avcC box payload) of the track with given ID.
+func mp4AVCConfig(rs io.ReadSeeker, trackID uint32) ([]byte, error) {
+	traks, err := mp4.ExtractBox(rs, nil, mp4.BoxPath{mp4.BoxTypeMoov(), mp4.BoxTypeTrak()})
+	if err != nil {
+		return nil, fmt.Errorf("error extracting tracks: %w", err)
+	}
+
+	for _, trak := range traks {
+		tkhd, err := mp4.ExtractBoxWithPayload(rs, trak, mp4.BoxPath{mp4.BoxTypeTkhd()})
+		if err != nil {
+			return nil, fmt.Errorf("error extracting track header: %w", err)
+		}
+
+		if len(tkhd) == 0 || tkhd[0].Payload.(*mp4.Tkhd).TrackID != trackID {
+			continue
+		}
+
+		avcC, err := mp4.ExtractBox(rs, trak, mp4.BoxPath{
+			mp4.BoxTypeMdia(),
+			mp4.BoxTypeMinf(),
+			mp4.BoxTypeStbl(),
+			mp4.BoxTypeStsd(),
+			mp4.BoxTypeAvc1(),
+			mp4.BoxTypeAvcC(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error extracting avc configuration: %w", err)
+		}
+
+		if len(avcC) == 0 {
+			break
+		}
+
+		return mp4BoxPayload(rs, avcC[0])
+	}
+
+	return nil, fmt.Errorf("no avc configuration found for track %d", trackID)
+}
+
+// decodeH264 decodes the first intra coded picture from the H.264
+// samples returned by next, using the given AVC decoder configuration
+// record and the track's dimensions. Samples are read until one is
+// decoded, or next returns an error.
+func decodeH264(config []byte, width int, height int, next func() ([]byte, error)) (image.Image, error) {
+	dec, err := h264.NewDecoder(config, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		sample, err := next()
+		if err != nil {
+			return nil, err
+		}
+
+		img, err := dec.Decode(sample)
+		if errors.Is(err, h264.ErrNotIntra) {
+			continue
+		}
+
+		return img, err
+	}
+}
+
+// decodeHEVC decodes the first intra coded picture from the HEVC
+// samples returned by next, using the given HEVC decoder configuration
+// record and the track's dimensions. Samples are read until one is
+// decoded, or next returns an error.
+func decodeHEVC(config []byte, width int, height int, next func() ([]byte, error)) (image.Image, error) {
+	dec, err := hevc.NewDecoder(config, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		sample, err := next()
+		if err != nil {
+			return nil, err
+		}
+
+		img, err := dec.Decode(sample)
+		if errors.Is(err, hevc.ErrNotIntra) {
+			continue
+		}
+
+		return img, err
+	}
+}
+
+// videoFrame returns the video frame image returned by decode, or
+// where it can't be decoded a blank image of the given dimensions.
+func videoFrame(width, height int, decode func() (image.Image, error)) *gtsImage {
+	img, err := decode()
+	if err != nil {
+		log.Warnf(nil, "error decoding video frame, using blank image: %v", err)
+		return blankImage(width, height)
+	}
+	return &gtsImage{image: img}
+}
+
+// checkMetadata returns an error if any of
+// the video's metadata could not be determined.
+func (v *gtsVideo) checkMetadata(width, height int) error {
 	var empty []string
 	if width == 0 {
 		empty = append(empty, "width")
@@ -110,22 +400,17 @@ func decodeVideoFrame(r io.Reader) (*gtsVideo, error) {
 	if height == 0 {
 		empty = append(empty, "height")
 	}
-	if video.duration == 0 {
+	if v.duration == 0 {
 		empty = append(empty, "duration")
 	}
-	if video.framerate == 0 {
+	if v.framerate == 0 {
 		empty = append(empty, "framerate")
 	}
-	if video.bitrate == 0 {
+	if v.bitrate == 0 {
 		empty = append(empty, "bitrate")
 	}
 	if len(empty) > 0 {
-		return nil, fmt.Errorf("error determining video metadata: %v", empty)
+		return fmt.Errorf("error determining video metadata: %v", empty)
 	}
-
-	// Create new empty "frame" image.
-	// TODO: decode frame from video file.
-	video.frame = blankImage(width, height)
-
-	return &video, nil
+	return nil
 }
@@ -0,0 +1,478 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package media
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+
+	"golang.org/x/image/vp8"
+)
+
+// EBML element IDs used when parsing WebM (Matroska) files, see:
+// https://www.matroska.org/technical/elements.html
+const (
+	ebmlIDHeader          = 0x1A45DFA3
+	ebmlIDSegment         = 0x18538067
+	ebmlIDInfo            = 0x1549A966
+	ebmlIDTimecodeScale   = 0x2AD7B1
+	ebmlIDDuration        = 0x4489
+	ebmlIDTracks          = 0x1654AE6B
+	ebmlIDTrackEntry      = 0xAE
+	ebmlIDTrackNumber     = 0xD7
+	ebmlIDTrackType       = 0x83
+	ebmlIDCodecID         = 0x86
+	ebmlIDCodecPrivate    = 0x63A2
+	ebmlIDDefaultDuration = 0x23E383
+	ebmlIDVideo           = 0xE0
+	ebmlIDPixelWidth      = 0xB0
+	ebmlIDPixelHeight     = 0xBA
+	ebmlIDCluster         = 0x1F43B675
+	ebmlIDTimecode        = 0xE7
+	ebmlIDSimpleBlock     = 0xA3
+	ebmlIDBlockGroup      = 0xA0
+	ebmlIDBlock           = 0xA1
+)
+
+// ebmlUnknownSize is returned as the size
+// of elements with an unknown data size.
+const ebmlUnknownSize = -1
+
+// webmTrackTypeVideo is the TrackType of video tracks.
+const webmTrackTypeVideo = 1
+
+// webmTrack contains information gathered
+// about a single track of a WebM file.
+type webmTrack struct {
+	number          uint64
+	trackType       uint64
+	codec           string
+	codecPrivate    []byte
+	defaultDuration uint64 // in nanoseconds
+	width           int
+	height          int
+	frames          int
+}
+
+// decodeWebM decodes a frame and metadata from a WebM video.
+//
+// Metadata is read from the segment info and tracks, with the
+// duration and frame rate derived from block timestamps where
+// these aren't present (as with e.g. files written by browsers).
+// The first key frame of the first video track is decoded as
+// the video frame if it's VP8 encoded; for other codecs, such
+// as VP9 and AV1, a blank frame is used as for undecodable MP4s.
+func decodeWebM(r io.Reader) (*gtsVideo, error) {
+	var (
+		er            = ebmlReader{r: bufio.NewReader(r)}
+		timecodeScale = uint64(1000000) // nanoseconds per tick
+		duration      float64           // in ticks
+		clusterTime   uint64            // in ticks
+		endTime       int64             // in ticks
+		blockBytes    uint64
+		tracks        []*webmTrack
+		videoTrack    *webmTrack
+		frame         image.Image
+		frameErr      error
+		video         gtsVideo
+	)
+
+	// current returns the track of the
+	// most recently read track entry.
+	current := func() (*webmTrack, error) {
+		if len(tracks) == 0 {
+			return nil, errors.New("track element outside of track entry")
+		}
+		return tracks[len(tracks)-1], nil
+	}
+
+	for {
+		id, size, err := er.readElementHeader()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("error reading webm element: %w", err)
+		}
+
+		if size == ebmlUnknownSize && id != ebmlIDSegment && id != ebmlIDCluster {
+			// Only segments and clusters may be of
+			// unknown size (e.g. from live streams).
+			return nil, fmt.Errorf("unexpected unknown size of webm element %x", id)
+		}
+
+		switch id {
+		case ebmlIDTrackEntry:
+			tracks = append(tracks, &webmTrack{})
+
+		case ebmlIDTimecodeScale:
+			timecodeScale, err = er.readUint(size)
+
+		case ebmlIDDuration:
+			duration, err = er.readFloat(size)
+
+		case ebmlIDTrackNumber, ebmlIDTrackType, ebmlIDDefaultDuration, ebmlIDPixelWidth, ebmlIDPixelHeight:
+			var (
+				track *webmTrack
+				v     uint64
+			)
+			if track, err = current(); err != nil {
+				break
+			}
+			if v, err = er.readUint(size); err != nil {
+				break
+			}
+			switch id {
+			case ebmlIDTrackNumber:
+				track.number = v
+			case ebmlIDTrackType:
+				track.trackType = v
+			case ebmlIDDefaultDuration:
+				track.defaultDuration = v
+			case ebmlIDPixelWidth:
+				track.width = int(v)
+			case ebmlIDPixelHeight:
+				track.height = int(v)
+			}
+
+		case ebmlIDCodecID, ebmlIDCodecPrivate:
+			var (
+				track *webmTrack
+				b     []byte
+			)
+			if track, err = current(); err != nil {
+				break
+			}
+			if b, err = er.readBytes(size); err != nil {
+				break
+			}
+			if id == ebmlIDCodecID {
+				track.codec = string(bytes.TrimRight(b, "\x00"))
+			} else {
+				track.codecPrivate = b
+			}
+
+		case ebmlIDTimecode:
+			clusterTime, err = er.readUint(size)
+
+		case ebmlIDSimpleBlock, ebmlIDBlock:
+			var b []byte
+			if b, err = er.readBytes(size); err != nil {
+				break
+			}
+			blockBytes += uint64(len(b))
+
+			// Parse block header: track number,
+			// timecode relative to the cluster,
+			// and flags.
+			num, n := ebmlVint(b)
+			if n == 0 || len(b) < n+3 {
+				err = errors.New("invalid block header")
+				break
+			}
+			t := int64(clusterTime) + int64(int16(binary.BigEndian.Uint16(b[n:])))
+			if t > endTime {
+				endTime = t
+			}
+			flags := b[n+2]
+			data := b[n+3:]
+
+			if videoTrack == nil {
+				// Use the first video track
+				// (tracks precede clusters).
+				for _, tr := range tracks {
+					if tr.trackType == webmTrackTypeVideo {
+						videoTrack = tr
+						break
+					}
+				}
+			}
+
+			if videoTrack == nil || videoTrack.number != uint64(num) {
+				// Not a video frame.
+				break
+			}
+			videoTrack.frames++
+
+			if frame != nil || frameErr != nil {
+				// Already tried decoding.
+				break
+			}
+
+			if flags&0x06 != 0 {
+				// Laced frames are
+				// not supported.
+				break
+			}
+
+			if id == ebmlIDSimpleBlock && flags&0x80 == 0 {
+				// Not a key frame.
+				break
+			}
+
+			frame, frameErr = videoTrack.decodeFrame(data)
+
+		default:
+			// Master elements of interest are entered by reading
+			// their children as though they were siblings; element
+			// IDs are unique, so no state of the nesting is needed.
+			if !ebmlMaster(id) {
+				err = er.skip(size)
+			}
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("error reading webm element %x: %w", id, err)
+		}
+	}
+
+	if videoTrack == nil {
+		return nil, errors.New("no video track found")
+	}
+
+	// Prefer duration given in the segment info, falling
+	// back to the end of the last block (taking the frame
+	// duration, if known, as the last block's duration).
+	if duration == 0 {
+		duration = float64(endTime)
+		if dd := videoTrack.defaultDuration; dd > 0 {
+			duration += float64(dd) / float64(timecodeScale)
+		}
+	}
+	video.duration = float32(duration * float64(timecodeScale) / 1e9)
+
+	if dd := videoTrack.defaultDuration; dd > 0 {
+		video.framerate = float32(1e9 / float64(dd))
+	} else if video.duration > 0 {
+		video.framerate = float32(videoTrack.frames) / video.duration
+	}
+
+	if video.duration > 0 {
+		video.bitrate = uint64(float64(blockBytes*8) / float64(video.duration))
+	}
+
+	width, height := videoTrack.width, videoTrack.height
+	if err := video.checkMetadata(width, height); err != nil {
+		return nil, err
+	}
+
+	video.frame = videoFrame(width, height, func() (image.Image, error) {
+		if frameErr == nil && frame == nil {
+			frameErr = errors.New("no key frame found")
+		}
+		return frame, frameErr
+	})
+
+	return &video, nil
+}
+
+// decodeFrame decodes the given frame of the video
+// track, returning a nil image if it's not a key frame.
+func (t *webmTrack) decodeFrame(b []byte) (image.Image, error) {
+	switch t.codec {
+	case "V_VP8":
+		dec := vp8.NewDecoder()
+		dec.Init(bytes.NewReader(b), len(b))
+
+		fh, err := dec.DecodeFrameHeader()
+		if err != nil {
+			return nil, err
+		}
+
+		if !fh.KeyFrame {
+			return nil, nil
+		}
+
+		return dec.DecodeFrame()
+
+	default:
+		return nil, fmt.Errorf("no decoder for video codec %s", t.codec)
+	}
+}
+
+// ebmlMaster returns whether id is that of a master
+// element that is entered rather than skipped.
+func ebmlMaster(id uint64) bool {
+	switch id {
+	case ebmlIDSegment,
+		ebmlIDCluster,
+		ebmlIDInfo,
+		ebmlIDTracks,
+		ebmlIDTrackEntry,
+		ebmlIDVideo,
+		ebmlIDBlockGroup:
+		return true
+	default:
+		return false
+	}
+}
+
+// ebmlVint parses a variable size integer from the start of b,
+// without its length marker, returning it and its length in
+// bytes. A length of zero is returned if b is invalid.
+func ebmlVint(b []byte) (uint64, int) {
+	if len(b) == 0 || b[0] == 0 {
+		return 0, 0
+	}
+
+	n := 1
+	for mask := byte(0x80); b[0]&mask == 0; mask >>= 1 {
+		n++
+	}
+	if len(b) < n {
+		return 0, 0
+	}
+
+	v := uint64(b[0]) & (0xff >> n)
+	for _, c := range b[1:n] {
+		v = v<<8 | uint64(c)
+	}
+
+	return v, n
+}
+
+// ebmlReader reads EBML elements.
+type ebmlReader struct{ r *bufio.Reader }
+
+// readElementHeader reads the ID and data size of the next element,
+// returning ebmlUnknownSize as the size if it's unknown. The element
+// ID is returned with its length marker as is conventional.
+func (er *ebmlReader) readElementHeader() (uint64, int64, error) {
+	id, n, err := er.readVint()
+	if err != nil {
+		return 0, 0, err
+	}
+	id |= 1 << (7 * n)
+
+	size, n, err := er.readVint()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if size == 1<<(7*n)-1 {
+		// All ones (reserved) is
+		// used for unknown size.
+		return id, ebmlUnknownSize, nil
+	}
+
+	if size > math.MaxInt32 {
+		return 0, 0, fmt.Errorf("element %x too large", id)
+	}
+
+	return id, int64(size), nil
+}
+
+// readVint reads a variable size integer, returning
+// it without its length marker, and its length in bytes.
+func (er *ebmlReader) readVint() (uint64, int, error) {
+	b, err := er.r.Peek(1)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if b[0] == 0 {
+		return 0, 0, errors.New("invalid variable size integer")
+	}
+
+	n := 1
+	for mask := byte(0x80); b[0]&mask == 0; mask >>= 1 {
+		n++
+	}
+
+	if b, err = er.r.Peek(n); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, 0, err
+	}
+
+	v, _ := ebmlVint(b)
+	_, err = er.r.Discard(n)
+	return v, n, err
+}
+
+// readBytes reads element data of given size.
+func (er *ebmlReader) readBytes(size int64) ([]byte, error) {
+	b := make([]byte, size)
+	if _, err := io.ReadFull(er.r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+// readUint reads unsigned integer element data of given size.
+func (er *ebmlReader) readUint(size int64) (uint64, error) {
+	if size > 8 {
+		return 0, fmt.Errorf("invalid unsigned integer size %d", size)
+	}
+
+	b, err := er.readBytes(size)
+	if err != nil {
+		return 0, err
+	}
+
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+
+	return v, nil
+}
+
+// readFloat reads float element data of given size.
+func (er *ebmlReader) readFloat(size int64) (float64, error) {
+	if size != 0 && size != 4 && size != 8 {
+		return 0, fmt.Errorf("invalid float size %d", size)
+	}
+
+	b, err := er.readBytes(size)
+	if err != nil {
+		return 0, err
+	}
+
+	switch size {
+	case 4:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 8:
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	default:
+		return 0, nil
+	}
+}
+
+// skip discards element data of given size.
+func (er *ebmlReader) skip(size int64) error {
+	if _, err := er.r.Discard(int(size)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return nil
+}
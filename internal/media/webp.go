@@ -0,0 +1,139 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"io"
+
+	"golang.org/x/image/webp"
+)
+
+// webpFlagAnimation and webpFlagAlpha are
+// feature flags of the VP8X chunk, see:
+// https://developers.google.com/speed/webp/docs/riff_container
+const (
+	webpFlagAnimation = 0x02
+	webpFlagAlpha     = 0x10
+)
+
+// webpMaxDimension is the maximum width
+// or height of a WebP image or frame.
+const webpMaxDimension = 16383
+
+// decodeWebP decodes a WebP image from the given reader. Where the
+// image is animated, only the first frame is decoded, which x/image/webp
+// doesn't support by itself.
+func decodeWebP(r io.Reader) (*gtsImage, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < 30 ||
+		string(data[12:16]) != "VP8X" ||
+		data[20]&webpFlagAnimation == 0 {
+		// Not animated.
+		return decodeImage(bytes.NewReader(data))
+	}
+
+	canvasW := int(uint24(data[24:])) + 1
+	canvasH := int(uint24(data[27:])) + 1
+	if canvasW > webpMaxDimension || canvasH > webpMaxDimension {
+		return nil, errors.New("invalid webp canvas size")
+	}
+
+	// Find the first animation frame.
+	var frame []byte
+	for b := data[12:]; len(b) >= 8 && frame == nil; {
+		size := int(binary.LittleEndian.Uint32(b[4:]))
+		if size > len(b)-8 {
+			return nil, errors.New("invalid webp chunk size")
+		}
+
+		if string(b[:4]) == "ANMF" {
+			frame = b[8 : 8+size]
+		}
+
+		// Chunks are padded
+		// to an even size.
+		size += size & 1
+		if size > len(b)-8 {
+			break
+		}
+		b = b[8+size:]
+	}
+
+	if len(frame) < 16 {
+		return nil, errors.New("no webp animation frame found")
+	}
+
+	var (
+		x, y   = 2 * int(uint24(frame)), 2 * int(uint24(frame[3:]))
+		w, h   = int(uint24(frame[6:])) + 1, int(uint24(frame[9:])) + 1
+		chunks = frame[16:]
+	)
+
+	// Wrap the frame's image data into a still image,
+	// with a VP8X chunk to declare any alpha chunk.
+	var buf bytes.Buffer
+	buf.WriteString("RIFF\x00\x00\x00\x00WEBP")
+	if len(chunks) >= 4 && string(chunks[:4]) == "ALPH" {
+		buf.WriteString("VP8X\x0a\x00\x00\x00")
+		buf.Write([]byte{webpFlagAlpha, 0, 0, 0})
+		buf.Write(putUint24(uint32(w - 1)))
+		buf.Write(putUint24(uint32(h - 1)))
+	}
+	buf.Write(chunks)
+
+	still := buf.Bytes()
+	binary.LittleEndian.PutUint32(still[4:], uint32(len(still)-8))
+
+	img, err := webp.Decode(bytes.NewReader(still))
+	if err != nil {
+		return nil, err
+	}
+
+	if x+w > canvasW || y+h > canvasH {
+		return nil, errors.New("webp animation frame outside canvas")
+	}
+
+	if x == 0 && y == 0 && w == canvasW && h == canvasH {
+		return &gtsImage{image: img}, nil
+	}
+
+	// Frame only covers part of the canvas, draw
+	// it in place over a transparent background.
+	canvas := image.NewNRGBA(image.Rect(0, 0, canvasW, canvasH))
+	draw.Draw(canvas, img.Bounds().Add(image.Pt(x, y)), img, img.Bounds().Min, draw.Src)
+	return &gtsImage{image: canvas}, nil
+}
+
+// uint24 returns the little-endian 24-bit integer at the start of b.
+func uint24(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+}
+
+// putUint24 returns v as a little-endian 24-bit integer.
+func putUint24(v uint32) []byte {
+	return []byte{byte(v), byte(v >> 8), byte(v >> 16)}
+}
@@ -41,10 +41,12 @@ const (
 // The middleware first checks whether an incoming http request has been
 // http-signed with a well-formed signature. If so, it will check if the
 // domain that signed the request is permitted to access the server, using
-// the provided uriBlocked function. If the domain is blocked, the middleware
-// will abort the request chain with http code 403 forbidden. If it is not
-// blocked, the handler will set the key verifier and the signature in the
-// context for use down the line.
+// the provided uriBlocked function. This function should take account of the
+// instance federation mode, ie., in allowlist mode any domain that isn't
+// explicitly allowed should be reported as blocked. If the domain is blocked,
+// the middleware will abort the request chain with http code 403 forbidden.
+// If it is not blocked, the handler will set the key verifier and the
+// signature in the context for use down the line.
 //
 // In case of an error, the request will be aborted with http code 500.
 func SignatureCheck(uriBlocked func(context.Context, *url.URL) (bool, error)) func(*gin.Context) {
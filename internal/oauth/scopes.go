@@ -0,0 +1,190 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oauth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scope is an OAuth scope, as requested by an application,
+// granted to a token, or required by an API route.
+//
+// Scopes are hierarchical: a token granted a scope is
+// also granted every sub-scope of it, so a token with
+// "read" can use routes that require "read:statuses",
+// and a token with "admin" or "admin:read" can use
+// routes that require "admin:read:accounts".
+type Scope string
+
+const (
+	ScopeRead                   = Scope("read")
+	ScopeReadAccounts           = Scope("read:accounts")
+	ScopeReadBlocks             = Scope("read:blocks")
+	ScopeReadBookmarks          = Scope("read:bookmarks")
+	ScopeReadFavourites         = Scope("read:favourites")
+	ScopeReadFilters            = Scope("read:filters")
+	ScopeReadFollows            = Scope("read:follows")
+	ScopeReadLists              = Scope("read:lists")
+	ScopeReadMutes              = Scope("read:mutes")
+	ScopeReadNotifications      = Scope("read:notifications")
+	ScopeReadReports            = Scope("read:reports")
+	ScopeReadSearch             = Scope("read:search")
+	ScopeReadStatuses           = Scope("read:statuses")
+	ScopeWrite                  = Scope("write")
+	ScopeWriteAccounts          = Scope("write:accounts")
+	ScopeWriteBlocks            = Scope("write:blocks")
+	ScopeWriteBookmarks         = Scope("write:bookmarks")
+	ScopeWriteConversations     = Scope("write:conversations")
+	ScopeWriteFavourites        = Scope("write:favourites")
+	ScopeWriteFilters           = Scope("write:filters")
+	ScopeWriteFollows           = Scope("write:follows")
+	ScopeWriteLists             = Scope("write:lists")
+	ScopeWriteMedia             = Scope("write:media")
+	ScopeWriteMutes             = Scope("write:mutes")
+	ScopeWriteNotifications     = Scope("write:notifications")
+	ScopeWriteReports           = Scope("write:reports")
+	ScopeWriteStatuses          = Scope("write:statuses")
+	ScopeFollow                 = Scope("follow")
+	ScopePush                   = Scope("push")
+	ScopeAdmin                  = Scope("admin")
+	ScopeAdminRead              = Scope("admin:read")
+	ScopeAdminReadAccounts      = Scope("admin:read:accounts")
+	ScopeAdminReadReports       = Scope("admin:read:reports")
+	ScopeAdminReadDomainAllows  = Scope("admin:read:domain_allows")
+	ScopeAdminReadDomainBlocks  = Scope("admin:read:domain_blocks")
+	ScopeAdminWrite             = Scope("admin:write")
+	ScopeAdminWriteAccounts     = Scope("admin:write:accounts")
+	ScopeAdminWriteReports      = Scope("admin:write:reports")
+	ScopeAdminWriteDomainAllows = Scope("admin:write:domain_allows")
+	ScopeAdminWriteDomainBlocks = Scope("admin:write:domain_blocks")
+)
+
+// knownScopes contains every scope
+// that can be requested or granted.
+var knownScopes = map[Scope]struct{}{
+	ScopeRead:                   {},
+	ScopeReadAccounts:           {},
+	ScopeReadBlocks:             {},
+	ScopeReadBookmarks:          {},
+	ScopeReadFavourites:         {},
+	ScopeReadFilters:            {},
+	ScopeReadFollows:            {},
+	ScopeReadLists:              {},
+	ScopeReadMutes:              {},
+	ScopeReadNotifications:      {},
+	ScopeReadReports:            {},
+	ScopeReadSearch:             {},
+	ScopeReadStatuses:           {},
+	ScopeWrite:                  {},
+	ScopeWriteAccounts:          {},
+	ScopeWriteBlocks:            {},
+	ScopeWriteBookmarks:         {},
+	ScopeWriteConversations:     {},
+	ScopeWriteFavourites:        {},
+	ScopeWriteFilters:           {},
+	ScopeWriteFollows:           {},
+	ScopeWriteLists:             {},
+	ScopeWriteMedia:             {},
+	ScopeWriteMutes:             {},
+	ScopeWriteNotifications:     {},
+	ScopeWriteReports:           {},
+	ScopeWriteStatuses:          {},
+	ScopeFollow:                 {},
+	ScopePush:                   {},
+	ScopeAdmin:                  {},
+	ScopeAdminRead:              {},
+	ScopeAdminReadAccounts:      {},
+	ScopeAdminReadReports:       {},
+	ScopeAdminReadDomainAllows:  {},
+	ScopeAdminReadDomainBlocks:  {},
+	ScopeAdminWrite:             {},
+	ScopeAdminWriteAccounts:     {},
+	ScopeAdminWriteReports:      {},
+	ScopeAdminWriteDomainAllows: {},
+	ScopeAdminWriteDomainBlocks: {},
+}
+
+// followScopes contains the scopes granted by the
+// legacy "follow" scope, in addition to their parents.
+var followScopes = map[Scope]struct{}{
+	ScopeReadBlocks:   {},
+	ScopeWriteBlocks:  {},
+	ScopeReadFollows:  {},
+	ScopeWriteFollows: {},
+	ScopeReadMutes:    {},
+	ScopeWriteMutes:   {},
+}
+
+// PermittedBy returns true if the given space-separated
+// list of granted scopes includes scope s, either directly,
+// or through one of its parent scopes.
+func (s Scope) PermittedBy(granted string) bool {
+	for _, g := range strings.Fields(granted) {
+		if g == string(s) {
+			// Directly granted.
+			return true
+		}
+
+		if strings.HasPrefix(string(s), g+":") {
+			// Granted through parent.
+			return true
+		}
+
+		if Scope(g) == ScopeFollow {
+			if _, ok := followScopes[s]; ok {
+				// Granted through legacy follow scope.
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ValidateScopes checks whether the given space-separated
+// list of scopes contains only known scopes, returning an
+// error for the first unknown scope, or if no scopes are given.
+func ValidateScopes(scopes string) error {
+	fields := strings.Fields(scopes)
+	if len(fields) == 0 {
+		return fmt.Errorf("no scopes provided")
+	}
+
+	for _, f := range fields {
+		if _, ok := knownScopes[Scope(f)]; !ok {
+			return fmt.Errorf("scope %s is not a recognized scope", f)
+		}
+	}
+
+	return nil
+}
+
+// CheckScopesPermitted checks whether every scope in the
+// given space-separated list of requested scopes is permitted
+// by the space-separated list of scopes an app registered with,
+// returning an error for the first scope that isn't.
+func CheckScopesPermitted(requested string, registered string) error {
+	for _, f := range strings.Fields(requested) {
+		if !Scope(f).PermittedBy(registered) {
+			return fmt.Errorf("scope %s is not permitted by the scopes of the application", f)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package oauth_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type ScopesTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ScopesTestSuite) TestPermittedBy() {
+	for _, test := range []struct {
+		scope     oauth.Scope
+		granted   string
+		permitted bool
+	}{
+		{oauth.ScopeRead, "read", true},
+		{oauth.ScopeReadStatuses, "read", true},
+		{oauth.ScopeReadStatuses, "read:statuses", true},
+		{oauth.ScopeReadStatuses, "read:accounts write", false},
+		{oauth.ScopeRead, "read:statuses", false},
+		{oauth.ScopeWriteMedia, "read write follow push", true},
+		{oauth.ScopeWriteFollows, "follow", true},
+		{oauth.ScopeReadMutes, "follow", true},
+		{oauth.ScopeWriteStatuses, "follow", false},
+		{oauth.ScopeAdminReadAccounts, "admin", true},
+		{oauth.ScopeAdminReadAccounts, "admin:read", true},
+		{oauth.ScopeAdminWriteAccounts, "admin:read", false},
+		{oauth.ScopeAdminRead, "read write", false},
+		{oauth.ScopeReadAccounts, "readwrite", false},
+		{oauth.ScopeReadAccounts, "", false},
+	} {
+		suite.Equal(test.permitted, test.scope.PermittedBy(test.granted), "%s by %q", test.scope, test.granted)
+	}
+}
+
+func (suite *ScopesTestSuite) TestValidateScopes() {
+	suite.NoError(oauth.ValidateScopes("read"))
+	suite.NoError(oauth.ValidateScopes("read write follow push"))
+	suite.NoError(oauth.ValidateScopes("read:statuses write:media admin:read:accounts"))
+	suite.EqualError(oauth.ValidateScopes(""), "no scopes provided")
+	suite.EqualError(oauth.ValidateScopes("read user admin"), "scope user is not a recognized scope")
+	suite.EqualError(oauth.ValidateScopes("read:everything"), "scope read:everything is not a recognized scope")
+}
+
+func (suite *ScopesTestSuite) TestCheckScopesPermitted() {
+	suite.NoError(oauth.CheckScopesPermitted("read", "read write"))
+	suite.NoError(oauth.CheckScopesPermitted("read:statuses write:media", "read write"))
+	suite.NoError(oauth.CheckScopesPermitted("read:follows write:blocks", "follow"))
+	suite.EqualError(oauth.CheckScopesPermitted("read write", "read"), "scope write is not permitted by the scopes of the application")
+	suite.EqualError(oauth.CheckScopesPermitted("admin:read", "read write follow push"), "scope admin:read is not permitted by the scopes of the application")
+}
+
+func TestScopesTestSuite(t *testing.T) {
+	suite.Run(t, new(ScopesTestSuite))
+}
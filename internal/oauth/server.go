@@ -26,6 +26,7 @@ import (
 
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/log"
 	"github.com/superseriousbusiness/oauth2/v4"
 	oautherr "github.com/superseriousbusiness/oauth2/v4/errors"
@@ -120,6 +121,23 @@ func New(ctx context.Context, database db.Basic) Server {
 		return userID, nil
 	})
 	srv.SetClientInfoHandler(server.ClientFormHandler)
+	srv.SetClientScopeHandler(func(tgr *oauth2.TokenGenerateRequest) (bool, error) {
+		// Only allow recognized scopes.
+		if ValidateScopes(tgr.Scope) != nil {
+			return false, nil
+		}
+
+		// And only those the app registered with.
+		app := &gtsmodel.Application{}
+		if err := database.GetWhere(tgr.Request.Context(), []db.Where{{Key: "client_id", Value: tgr.ClientID}}, app); err != nil {
+			if errors.Is(err, db.ErrNoEntries) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		return CheckScopesPermitted(tgr.Scope, app.Scopes) == nil, nil
+	})
 	return &s{
 		server: srv,
 	}
@@ -139,6 +157,12 @@ func (s *s) HandleTokenRequest(r *http.Request) (map[string]interface{}, gtserro
 		return nil, gtserror.NewErrorBadRequest(err, help, adv)
 	}
 
+	if gt == oauth2.ClientCredentials && tgr.Scope == "" {
+		// Default to read scope, as
+		// for authorization requests.
+		tgr.Scope = string(ScopeRead)
+	}
+
 	ti, err := s.server.GetAccessToken(ctx, gt, tgr)
 	if err != nil {
 		help := fmt.Sprintf("could not get access token: %s", err)
@@ -1,6 +1,8 @@
 package oauth
 
 import (
+	"fmt"
+
 	"github.com/gin-gonic/gin"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/oauth2/v4"
@@ -88,3 +90,19 @@ func Authed(c *gin.Context, requireToken bool, requireApp bool, requireUser bool
 
 	return a, nil
 }
+
+// CheckScope returns an error if the token on a does
+// not grant the given scope. If a has no token, ie.,
+// the request was not authenticated, no error is returned,
+// since there's no scope to restrict.
+func (a *Auth) CheckScope(scope Scope) error {
+	if a.Token == nil {
+		return nil
+	}
+
+	if !scope.PermittedBy(a.Token.GetScope()) {
+		return fmt.Errorf("token scope %q does not permit %s", a.Token.GetScope(), scope)
+	}
+
+	return nil
+}
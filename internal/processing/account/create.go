@@ -19,13 +19,17 @@ package account
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
 	"github.com/superseriousbusiness/gotosocial/internal/text"
 	"github.com/superseriousbusiness/oauth2/v4"
@@ -42,6 +46,59 @@ func (p *Processor) Create(
 	app *gtsmodel.Application,
 	form *apimodel.AccountCreateRequest,
 ) (*apimodel.Token, gtserror.WithCode) {
+	user, errWithCode := p.newSignup(ctx, form, app.ID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// Generate access token *before* doing side effects; we
+	// don't want to process side effects if something borks.
+	accessToken, err := p.oauthServer.GenerateUserAccessToken(ctx, appToken, app.ClientSecret, user.ID)
+	if err != nil {
+		err := fmt.Errorf("error creating new access token for user %s: %w", user.ID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	p.enqueueSignup(ctx, user)
+
+	return &apimodel.Token{
+		AccessToken: accessToken.GetAccess(),
+		TokenType:   "Bearer",
+		Scope:       accessToken.GetScope(),
+		CreatedAt:   accessToken.GetAccessCreateAt().Unix(),
+	}, nil
+}
+
+// SignUp processes the given form for creating a new account
+// through the web sign-up page, returning the new user if
+// successful. Unlike Create, no application or token is
+// involved: the user signs in separately once they've
+// confirmed their email address (and been approved).
+//
+// Fields on the form should have already been validated by the
+// caller, before this function is called.
+func (p *Processor) SignUp(
+	ctx context.Context,
+	form *apimodel.AccountCreateRequest,
+) (*gtsmodel.User, gtserror.WithCode) {
+	user, errWithCode := p.newSignup(ctx, form, "")
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	p.enqueueSignup(ctx, user)
+
+	return user, nil
+}
+
+// newSignup checks availability of the requested username
+// and email address, and the validity of the invite code
+// (if any), then stores a new user + account from the form.
+func (p *Processor) newSignup(
+	ctx context.Context,
+	form *apimodel.AccountCreateRequest,
+	appID string,
+) (*gtsmodel.User, gtserror.WithCode) {
 	emailAvailable, err := p.state.DB.IsEmailAvailable(ctx, form.Email)
 	if err != nil {
 		err := fmt.Errorf("db error checking email availability: %w", err)
@@ -62,6 +119,47 @@ func (p *Processor) Create(
 		return nil, gtserror.NewErrorConflict(err, err.Error())
 	}
 
+	// If an invite code was given, make sure
+	// it's valid before going any further.
+	var invite *gtsmodel.Invite
+	if form.InviteCode != "" {
+		invite, err = p.state.DB.GetInviteByCode(ctx, form.InviteCode)
+		if err != nil && !errors.Is(err, db.ErrNoEntries) {
+			err := fmt.Errorf("db error getting invite: %w", err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		if invite == nil || !invite.Valid(time.Now()) {
+			const help = "invite code is invalid or has expired"
+			err := fmt.Errorf("invite code %s is not valid", form.InviteCode)
+			return nil, gtserror.NewErrorForbidden(err, help)
+		}
+
+		// Claim a use of the invite before creating
+		// the user, so that concurrent sign-ups can't
+		// take the invite past its maximum uses.
+		if err := p.state.DB.ClaimInviteUse(ctx, invite, time.Now()); err != nil {
+			if !errors.Is(err, db.ErrNoEntries) {
+				err := fmt.Errorf("db error claiming invite: %w", err)
+				return nil, gtserror.NewErrorInternalError(err)
+			}
+
+			const help = "invite code is invalid or has expired"
+			err := fmt.Errorf("invite code %s is no longer valid", form.InviteCode)
+			return nil, gtserror.NewErrorForbidden(err, help)
+		}
+	}
+
+	// Users signing up with an invite may skip
+	// approval, if the instance is set up that way.
+	preApproved := !config.GetAccountsApprovalRequired() ||
+		(invite != nil && config.GetAccountsInvitesBypassApproval())
+
+	var inviteID string
+	if invite != nil {
+		inviteID = invite.ID
+	}
+
 	// Only store reason if one is required.
 	var reason string
 	if config.GetAccountsReasonRequired() {
@@ -73,37 +171,58 @@ func (p *Processor) Create(
 		Email:       form.Email,
 		Password:    form.Password,
 		Reason:      text.SanitizePlaintext(reason),
-		PreApproved: !config.GetAccountsApprovalRequired(), // Mark as approved if no approval required.
+		PreApproved: preApproved, // Mark as approved if no approval required.
 		SignUpIP:    form.IP,
 		Locale:      form.Locale,
-		AppID:       app.ID,
+		AppID:       appID,
+		InviteID:    inviteID,
 	})
 	if err != nil {
+		if invite != nil {
+			// Sign-up didn't go through,
+			// so give the invite use back.
+			if err := p.state.DB.ReleaseInviteUse(ctx, invite); err != nil {
+				log.Errorf(ctx, "db error releasing invite %s: %v", invite.ID, err)
+			}
+		}
+
 		err := fmt.Errorf("db error creating new signup: %w", err)
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
-	// Generate access token *before* doing side effects; we
-	// don't want to process side effects if something borks.
-	accessToken, err := p.oauthServer.GenerateUserAccessToken(ctx, appToken, app.ClientSecret, user.ID)
-	if err != nil {
-		err := fmt.Errorf("error creating new access token for user %s: %w", user.ID, err)
-		return nil, gtserror.NewErrorInternalError(err)
+	if invite != nil && *invite.Autofollow {
+		// Make the new user follow the inviter.
+		p.followInviter(ctx, invite, user)
 	}
 
-	// There are side effects for creating a new account
-	// (confirmation emails etc), perform these async.
+	return user, nil
+}
+
+// enqueueSignup enqueues side effects for creating
+// a new account (confirmation emails etc) for the
+// given user, which will be performed async.
+func (p *Processor) enqueueSignup(ctx context.Context, user *gtsmodel.User) {
 	p.state.Workers.EnqueueClientAPI(ctx, messages.FromClientAPI{
 		APObjectType:   ap.ObjectProfile,
 		APActivityType: ap.ActivityCreate,
 		GTSModel:       user.Account,
 		OriginAccount:  user.Account,
 	})
+}
 
-	return &apimodel.Token{
-		AccessToken: accessToken.GetAccess(),
-		TokenType:   "Bearer",
-		Scope:       accessToken.GetScope(),
-		CreatedAt:   accessToken.GetAccessCreateAt().Unix(),
-	}, nil
+// followInviter makes the new user follow the creator of
+// the invite they signed up with. Errors are logged rather
+// than returned, since the sign up itself has already
+// succeeded by this point.
+func (p *Processor) followInviter(ctx context.Context, invite *gtsmodel.Invite, user *gtsmodel.User) {
+	if err := p.state.DB.PopulateInvite(ctx, invite); err != nil {
+		log.Errorf(ctx, "db error populating invite %s: %v", invite.ID, err)
+		return
+	}
+
+	if _, errWithCode := p.FollowCreate(ctx, user.Account, &apimodel.AccountFollowRequest{
+		ID: invite.User.AccountID,
+	}); errWithCode != nil {
+		log.Errorf(ctx, "error following inviter %s: %v", invite.User.AccountID, errWithCode)
+	}
 }
@@ -0,0 +1,170 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package account_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+)
+
+type CreateTestSuite struct {
+	AccountStandardTestSuite
+}
+
+func (suite *CreateTestSuite) createForm(inviteCode string) *apimodel.AccountCreateRequest {
+	return &apimodel.AccountCreateRequest{
+		Username:   "invited_person",
+		Email:      "invited@example.org",
+		Password:   "sdfkjhsdfkjhsdfkjhsdf1!",
+		Agreement:  true,
+		Locale:     "en",
+		InviteCode: inviteCode,
+		IP:         net.ParseIP("192.0.2.1"),
+	}
+}
+
+func (suite *CreateTestSuite) TestCreateWithInvite() {
+	ctx := context.Background()
+	config.SetAccountsApprovalRequired(true)
+	config.SetAccountsInvitesBypassApproval(true)
+
+	app := suite.testApplications["application_1"]
+	appToken := oauth.DBTokenToToken(suite.testTokens["local_account_1_client_application_token"])
+
+	token, errWithCode := suite.accountProcessor.Create(ctx, appToken, app, suite.createForm("wkTbG4Ya"))
+	suite.NoError(errWithCode)
+	suite.NotEmpty(token.AccessToken)
+
+	account, err := suite.db.GetAccountByUsernameDomain(ctx, "invited_person", "")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	user, err := suite.db.GetUserByAccountID(ctx, account.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// invite bypasses approval
+	suite.True(*user.Approved)
+	suite.Equal("01H9PQ6Y3W6N1DHZ8Y8T3QNMB2", user.InviteID)
+
+	invite, err := suite.db.GetInviteByID(ctx, "01H9PQ6Y3W6N1DHZ8Y8T3QNMB2")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal(2, invite.Uses)
+
+	// new account should follow (or have requested to follow) the inviter
+	adminAccount := suite.testAccounts["admin_account"]
+	following, err := suite.db.IsFollowing(ctx, user.AccountID, adminAccount.ID)
+	suite.NoError(err)
+	requested, err := suite.db.IsFollowRequested(ctx, user.AccountID, adminAccount.ID)
+	suite.NoError(err)
+	suite.True(following || requested)
+}
+
+func (suite *CreateTestSuite) TestCreateWithInviteApprovalStillRequired() {
+	ctx := context.Background()
+	config.SetAccountsApprovalRequired(true)
+
+	app := suite.testApplications["application_1"]
+	appToken := oauth.DBTokenToToken(suite.testTokens["local_account_1_client_application_token"])
+
+	_, errWithCode := suite.accountProcessor.Create(ctx, appToken, app, suite.createForm("wkTbG4Ya"))
+	suite.NoError(errWithCode)
+
+	account, err := suite.db.GetAccountByUsernameDomain(ctx, "invited_person", "")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	user, err := suite.db.GetUserByAccountID(ctx, account.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.False(*user.Approved)
+}
+
+func (suite *CreateTestSuite) TestCreateWithExpiredInvite() {
+	app := suite.testApplications["application_1"]
+	appToken := oauth.DBTokenToToken(suite.testTokens["local_account_1_client_application_token"])
+
+	_, errWithCode := suite.accountProcessor.Create(context.Background(), appToken, app, suite.createForm("Yx1bQ9cZ"))
+	suite.Equal(http.StatusForbidden, errWithCode.Code())
+}
+
+func (suite *CreateTestSuite) TestCreateWithUsedUpInvite() {
+	ctx := context.Background()
+	app := suite.testApplications["application_1"]
+	appToken := oauth.DBTokenToToken(suite.testTokens["local_account_1_client_application_token"])
+
+	// Use up the invite.
+	invite, err := suite.db.GetInviteByID(ctx, "01H9PQ6Y3W6N1DHZ8Y8T3QNMB2")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	invite.Uses = invite.MaxUses
+	if err := suite.db.UpdateInvite(ctx, invite, "uses"); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	_, errWithCode := suite.accountProcessor.Create(ctx, appToken, app, suite.createForm("wkTbG4Ya"))
+	suite.Equal(http.StatusForbidden, errWithCode.Code())
+
+	// No account should have been created.
+	_, err = suite.db.GetAccountByUsernameDomain(ctx, "invited_person", "")
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
+func (suite *CreateTestSuite) TestCreateWithUnknownInvite() {
+	app := suite.testApplications["application_1"]
+	appToken := oauth.DBTokenToToken(suite.testTokens["local_account_1_client_application_token"])
+
+	_, errWithCode := suite.accountProcessor.Create(context.Background(), appToken, app, suite.createForm("nope1234"))
+	suite.Equal(http.StatusForbidden, errWithCode.Code())
+}
+
+func (suite *CreateTestSuite) TestSignUp() {
+	config.SetAccountsApprovalRequired(false)
+
+	user, errWithCode := suite.accountProcessor.SignUp(context.Background(), suite.createForm(""))
+	suite.NoError(errWithCode)
+	suite.True(*user.Approved)
+	suite.Empty(user.CreatedByApplicationID)
+	suite.Equal("invited@example.org", user.UnconfirmedEmail)
+
+	// side effects (confirmation email etc) should be enqueued
+	msg := <-suite.fromClientAPIChan
+	suite.Equal(ap.ObjectProfile, msg.APObjectType)
+	suite.Equal(ap.ActivityCreate, msg.APActivityType)
+	suite.Equal(user.AccountID, msg.OriginAccount.ID)
+}
+
+func TestCreateTestSuite(t *testing.T) {
+	suite.Run(t, &CreateTestSuite{})
+}
@@ -59,6 +59,10 @@ func (p *Processor) Delete(ctx context.Context, account *gtsmodel.Account, origi
 		return gtserror.NewErrorInternalError(err)
 	}
 
+	if err := p.deleteAccountMutes(ctx, account); err != nil {
+		return gtserror.NewErrorInternalError(err)
+	}
+
 	if err := p.deleteAccountStatuses(ctx, account); err != nil {
 		return gtserror.NewErrorInternalError(err)
 	}
@@ -105,7 +109,8 @@ func (p *Processor) DeleteSelf(ctx context.Context, account *gtsmodel.Account) g
 }
 
 // deleteUserAndTokensForAccount deletes the gtsmodel.User and
-// any OAuth tokens and applications for the given account.
+// any OAuth tokens, applications, Web Push subscriptions, and
+// invites for the given account.
 //
 // Callers to this function should already have checked that
 // this is a local account, or else it won't have a user associated
@@ -138,6 +143,21 @@ func (p *Processor) deleteUserAndTokensForAccount(ctx context.Context, account *
 		}
 	}
 
+	// Delete any Web Push subscriptions made with those tokens.
+	if err := p.state.DB.DeleteWebPushSubscriptionsByAccountID(ctx, account.ID); err != nil {
+		return gtserror.Newf("db error deleting web push subscriptions: %w", err)
+	}
+
+	// Delete any statuses scheduled for later publication.
+	if err := p.state.DB.DeleteScheduledStatusesByAccountID(ctx, account.ID); err != nil {
+		return gtserror.Newf("db error deleting scheduled statuses: %w", err)
+	}
+
+	// Delete any invites created by this user.
+	if err := p.state.DB.DeleteInvitesByUserID(ctx, user.ID); err != nil {
+		return gtserror.Newf("db error deleting invites: %w", err)
+	}
+
 	columns, err := stubbifyUser(user)
 	if err != nil {
 		return gtserror.Newf("error stubbifying user: %w", err)
@@ -283,6 +303,13 @@ func (p *Processor) deleteAccountBlocks(ctx context.Context, account *gtsmodel.A
 	return nil
 }
 
+func (p *Processor) deleteAccountMutes(ctx context.Context, account *gtsmodel.Account) error {
+	if err := p.state.DB.DeleteAccountMutes(ctx, account.ID); err != nil {
+		return gtserror.Newf("db error deleting account mutes for %s: %w", account.ID, err)
+	}
+	return nil
+}
+
 // deleteAccountStatuses iterates through all statuses owned by
 // the given account, passing each discovered status (and boosts
 // thereof) to the processor workers for further async processing.
@@ -398,6 +425,24 @@ func (p *Processor) deleteAccountPeripheral(ctx context.Context, account *gtsmod
 		return err
 	}
 
+	// Delete all thread mutes owned by given account.
+	if err := p.state.DB.DeleteStatusMutes(ctx, "", account.ID); // nocollapse
+	err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return err
+	}
+
+	// Delete all thread mutes targeting given account.
+	if err := p.state.DB.DeleteStatusMutes(ctx, account.ID, ""); // nocollapse
+	err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return err
+	}
+
+	// Delete all conversations owned by given account.
+	if err := p.state.DB.DeleteConversationsByOwnerAccountID(ctx, account.ID); // nocollapse
+	err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return err
+	}
+
 	// Delete all faves owned by given account.
 	if err := p.state.DB.DeleteStatusFaves(ctx, account.ID, ""); // nocollapse
 	err != nil && !errors.Is(err, db.ErrNoEntries) {
@@ -410,6 +455,12 @@ func (p *Processor) deleteAccountPeripheral(ctx context.Context, account *gtsmod
 		return err
 	}
 
+	// Delete all poll votes owned by given account.
+	if err := p.state.DB.DeletePollVotesByAccountID(ctx, account.ID); // nocollapse
+	err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return err
+	}
+
 	// TODO: add status mutes here when they're implemented.
 
 	return nil
@@ -445,7 +496,7 @@ func stubbifyAccount(account *gtsmodel.Account, origin string) []string {
 	account.Note = ""
 	account.NoteRaw = ""
 	account.Memorial = falseBool()
-	account.AlsoKnownAs = ""
+	account.AlsoKnownAsURIs = nil
 	account.MovedToAccountID = ""
 	account.Reason = ""
 	account.Discoverable = falseBool()
@@ -468,7 +519,7 @@ func stubbifyAccount(account *gtsmodel.Account, origin string) []string {
 		"note",
 		"note_raw",
 		"memorial",
-		"also_known_as",
+		"also_known_as_uris",
 		"moved_to_account_id",
 		"reason",
 		"discoverable",
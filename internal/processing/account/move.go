@@ -0,0 +1,181 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package account
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"golang.org/x/exp/slices"
+)
+
+// MoveSelf moves the given local account to the account at movedToURI,
+// provided that account lists the given account as an alias (alsoKnownAs).
+// The Move will be federated out and followers moved asynchronously.
+func (p *Processor) MoveSelf(ctx context.Context, account *gtsmodel.Account, movedToURI string) gtserror.WithCode {
+	targetURI, err := url.Parse(movedToURI)
+	if err != nil || !targetURI.IsAbs() {
+		const help = "moved_to_uri was not a valid uri"
+		return gtserror.NewErrorBadRequest(errors.New(help), help)
+	}
+
+	if targetURI.String() == account.URI {
+		const help = "an account cannot move to itself"
+		return gtserror.NewErrorBadRequest(errors.New(help), help)
+	}
+
+	// Fetch the account to move to, dereferencing if necessary.
+	target, _, err := p.federator.GetAccountByURI(ctx, account.Username, targetURI)
+	if err != nil {
+		err := gtserror.Newf("error getting target account %s: %w", targetURI, err)
+		return gtserror.NewErrorNotFound(err, "target account could not be found")
+	}
+
+	if target.IsRemote() {
+		// Make sure we're checking up-to-date aliases,
+		// as the target may only just have added ours.
+		target, _, err = p.federator.RefreshAccount(ctx, account.Username, target, nil, true)
+		if err != nil {
+			err := gtserror.Newf("error refreshing target account %s: %w", targetURI, err)
+			return gtserror.NewErrorNotFound(err, "target account could not be found")
+		}
+	}
+
+	if errWithCode := checkMoveTarget(account, target); errWithCode != nil {
+		return errWithCode
+	}
+
+	// Mark the account as moved.
+	account.MovedToAccountID = target.ID
+	account.MovedTo = target
+	if err := p.state.DB.UpdateAccount(ctx, account, "moved_to_account_id"); err != nil {
+		err := gtserror.Newf("db error updating account: %w", err)
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	// Process the move side effects asynchronously.
+	p.state.Workers.EnqueueClientAPI(ctx, messages.FromClientAPI{
+		APObjectType:   ap.ObjectProfile,
+		APActivityType: ap.ActivityMove,
+		GTSModel:       account,
+		OriginAccount:  account,
+		TargetAccount:  target,
+	})
+
+	return nil
+}
+
+// MoveRemote handles a Move of the given remote origin account to the
+// account at targetURI, as received via the inbox of requestUser. If the
+// target verifies the move, origin is marked as moved and its local
+// followers are moved to follow the target instead.
+func (p *Processor) MoveRemote(ctx context.Context, requestUser string, origin *gtsmodel.Account, targetURI *url.URL) error {
+	// Fetch the account being moved to, dereferencing if necessary.
+	target, _, err := p.federator.GetAccountByURI(ctx, requestUser, targetURI)
+	if err != nil {
+		return gtserror.Newf("error getting target account %s: %w", targetURI, err)
+	}
+
+	if target.IsRemote() {
+		// Make sure we're checking up-to-date aliases,
+		// as the target may only just have added origin.
+		target, _, err = p.federator.RefreshAccount(ctx, requestUser, target, nil, true)
+		if err != nil {
+			return gtserror.Newf("error refreshing target account %s: %w", targetURI, err)
+		}
+	}
+
+	if errWithCode := checkMoveTarget(origin, target); errWithCode != nil {
+		return gtserror.Newf("invalid move of %s to %s: %w", origin.URI, target.URI, errWithCode)
+	}
+
+	if origin.MovedToAccountID != target.ID {
+		// Mark the origin account as moved.
+		origin.MovedToAccountID = target.ID
+		origin.MovedTo = target
+		if err := p.state.DB.UpdateAccount(ctx, origin, "moved_to_account_id"); err != nil {
+			return gtserror.Newf("db error updating account: %w", err)
+		}
+	}
+
+	return p.MoveFollowers(ctx, origin, target)
+}
+
+// MoveFollowers moves the local followers of origin account to target
+// account, by following target and then unfollowing origin for each one.
+// Callers should already have verified that the move is valid.
+func (p *Processor) MoveFollowers(ctx context.Context, origin *gtsmodel.Account, target *gtsmodel.Account) error {
+	follows, err := p.state.DB.GetAccountLocalFollowers(ctx, origin.ID)
+	if err != nil {
+		return gtserror.Newf("db error getting local followers of %s: %w", origin.ID, err)
+	}
+
+	for _, follow := range follows {
+		if follow.AccountID == target.ID {
+			// Target can't follow itself.
+			continue
+		}
+
+		// Follow the target, retaining existing follow settings.
+		if _, errWithCode := p.FollowCreate(ctx, follow.Account, &apimodel.AccountFollowRequest{
+			ID:      target.ID,
+			Reblogs: follow.ShowReblogs,
+			Notify:  follow.Notify,
+		}); errWithCode != nil {
+			// eg., target blocks follower; leave the follow as-is.
+			log.Errorf(ctx, "error following move target %s for account %s: %v", target.ID, follow.AccountID, errWithCode)
+			continue
+		}
+
+		// Unfollow the old account.
+		if _, errWithCode := p.FollowRemove(ctx, follow.Account, origin.ID); errWithCode != nil {
+			log.Errorf(ctx, "error unfollowing moved account %s for account %s: %v", origin.ID, follow.AccountID, errWithCode)
+		}
+	}
+
+	return nil
+}
+
+// checkMoveTarget checks whether origin account is
+// allowed to move to target account, returning an
+// appropriate error if not.
+func checkMoveTarget(origin *gtsmodel.Account, target *gtsmodel.Account) gtserror.WithCode {
+	if target.ID == origin.ID {
+		const help = "an account cannot move to itself"
+		return gtserror.NewErrorBadRequest(errors.New(help), help)
+	}
+
+	if target.MovedToAccountID != "" {
+		const help = "target account has itself moved to another account"
+		return gtserror.NewErrorUnprocessableEntity(errors.New(help), help)
+	}
+
+	if !slices.Contains(target.AlsoKnownAsURIs, origin.URI) {
+		const help = "target account does not list this account in its aliases (alsoKnownAs)"
+		return gtserror.NewErrorUnprocessableEntity(errors.New(help), help)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package account_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+)
+
+type MoveTestSuite struct {
+	AccountStandardTestSuite
+}
+
+func (suite *MoveTestSuite) TestMoveSelfNoAlias() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+	target := suite.testAccounts["local_account_2"]
+
+	// Target doesn't list account as an alias, so move should be refused.
+	errWithCode := suite.accountProcessor.MoveSelf(ctx, account, target.URI)
+	if suite.NotNil(errWithCode) {
+		suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+	}
+
+	dbAccount, err := suite.db.GetAccountByID(ctx, account.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Empty(dbAccount.MovedToAccountID)
+}
+
+func (suite *MoveTestSuite) TestMoveSelf() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+	target := suite.testAccounts["local_account_2"]
+
+	// Alias target to account so the move is allowed.
+	target.AlsoKnownAsURIs = []string{account.URI}
+	if err := suite.db.UpdateAccount(ctx, target, "also_known_as_uris"); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	if errWithCode := suite.accountProcessor.MoveSelf(ctx, account, target.URI); errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+
+	dbAccount, err := suite.db.GetAccountByID(ctx, account.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal(target.ID, dbAccount.MovedToAccountID)
+
+	// A move message should have been enqueued.
+	msg := <-suite.fromClientAPIChan
+	suite.Equal(ap.ActivityMove, msg.APActivityType)
+	suite.Equal(ap.ObjectProfile, msg.APObjectType)
+	suite.Equal(target.ID, msg.TargetAccount.ID)
+}
+
+func (suite *MoveTestSuite) TestMoveFollowers() {
+	ctx := context.Background()
+	account := suite.testAccounts["local_account_1"]
+	target := suite.testAccounts["remote_account_1"]
+	follower := suite.testAccounts["admin_account"]
+
+	if err := suite.accountProcessor.MoveFollowers(ctx, account, target); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Follower should have requested to follow the target...
+	requested, err := suite.db.IsFollowRequested(ctx, follower.ID, target.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.True(requested)
+
+	// ... and no longer follow the moved account.
+	following, err := suite.db.IsFollowing(ctx, follower.ID, account.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.False(following)
+}
+
+func TestMoveTestSuite(t *testing.T) {
+	suite.Run(t, new(MoveTestSuite))
+}
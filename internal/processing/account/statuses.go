@@ -27,6 +27,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/statusfilter"
 	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
@@ -101,9 +102,21 @@ func (p *Processor) StatusesGet(
 		prevMinIDValue = filtered[0].ID
 	)
 
+	var filters []*gtsmodel.Filter
+	if requestingAccount != nil {
+		filters, err = p.state.DB.GetFiltersForAccountID(ctx, requestingAccount.ID)
+		if err != nil {
+			err = gtserror.Newf("couldn't retrieve filters for account %s: %w", requestingAccount.ID, err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+	}
+
 	for _, s := range filtered {
 		// Convert filtered statuses to API statuses.
-		item, err := p.tc.StatusToAPIStatus(ctx, s, requestingAccount)
+		item, err := p.tc.StatusToAPIStatus(ctx, s, requestingAccount, statusfilter.FilterContextAccount, filters)
+		if errors.Is(err, statusfilter.ErrHideStatus) {
+			continue
+		}
 		if err != nil {
 			log.Errorf(ctx, "error convering to api status: %v", err)
 			continue
@@ -173,7 +186,7 @@ func (p *Processor) WebStatusesGet(ctx context.Context, targetAccountID string,
 
 	for _, s := range statuses {
 		// Convert fetched statuses to API statuses.
-		item, err := p.tc.StatusToAPIStatus(ctx, s, nil)
+		item, err := p.tc.StatusToAPIStatus(ctx, s, nil, statusfilter.FilterContextNone, nil)
 		if err != nil {
 			log.Errorf(ctx, "error convering to api status: %v", err)
 			continue
@@ -261,6 +261,14 @@ func (p *Processor) Update(ctx context.Context, account *gtsmodel.Account, form
 		account.EnableRSS = form.EnableRSS
 	}
 
+	if form.AlsoKnownAsURIs != nil {
+		alsoKnownAsURIs := *form.AlsoKnownAsURIs
+		if err := validate.AlsoKnownAsURIs(alsoKnownAsURIs); err != nil {
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+		account.AlsoKnownAsURIs = alsoKnownAsURIs
+	}
+
 	err := p.state.DB.UpdateAccount(ctx, account)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("could not update account %s: %s", account.ID, err))
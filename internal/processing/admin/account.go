@@ -19,14 +19,18 @@ package admin
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
 	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
 func (p *Processor) AccountAction(ctx context.Context, account *gtsmodel.Account, form *apimodel.AdminAccountActionRequest) gtserror.WithCode {
@@ -62,3 +66,259 @@ func (p *Processor) AccountAction(ctx context.Context, account *gtsmodel.Account
 
 	return nil
 }
+
+// Account statuses that can be used to filter AccountsGet.
+const (
+	AccountStatusPending = "pending" // Sign-up not yet approved.
+	AccountStatusActive  = "active"  // Sign-up approved.
+)
+
+// AccountsGet returns local accounts on this instance, optionally filtered by status,
+// with the given paging parameters. Status can be empty, "pending", or "active".
+func (p *Processor) AccountsGet(
+	ctx context.Context,
+	status string,
+	maxID string,
+	sinceID string,
+	minID string,
+	limit int,
+) (*apimodel.PageableResponse, gtserror.WithCode) {
+	var approved *bool
+	switch status {
+	case "":
+		// No filter.
+	case AccountStatusPending:
+		approved = util.Ptr(false)
+	case AccountStatusActive:
+		approved = util.Ptr(true)
+	default:
+		err := fmt.Errorf("status must be one of %s or %s, provided value was %s", AccountStatusPending, AccountStatusActive, status)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	accounts, err := p.state.DB.GetLocalAccounts(ctx, approved, maxID, sinceID, minID, limit)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			return util.EmptyPageableResponse(), nil
+		}
+		err = gtserror.Newf("db error getting accounts: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	count := len(accounts)
+	items := make([]interface{}, 0, count)
+	nextMaxIDValue := accounts[count-1].ID
+	prevMinIDValue := accounts[0].ID
+
+	for _, a := range accounts {
+		item, err := p.tc.AccountToAdminAPIAccount(ctx, a)
+		if err != nil {
+			err = gtserror.Newf("error converting account to api: %w", err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+		items = append(items, item)
+	}
+
+	extraQueryParams := []string{}
+	if status != "" {
+		extraQueryParams = append(extraQueryParams, "status="+status)
+	}
+
+	return util.PackagePageableResponse(util.PageableResponseParams{
+		Items:            items,
+		Path:             "/api/v1/admin/accounts",
+		NextMaxIDValue:   nextMaxIDValue,
+		PrevMinIDValue:   prevMinIDValue,
+		Limit:            limit,
+		ExtraQueryParams: extraQueryParams,
+	})
+}
+
+// AccountGet returns the admin view of one account with the given ID.
+func (p *Processor) AccountGet(ctx context.Context, accountID string) (*apimodel.AdminAccountInfo, gtserror.WithCode) {
+	account, err := p.state.DB.GetAccountByID(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err = fmt.Errorf("no account exists with id %s", accountID)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		err = gtserror.Newf("db error getting account %s: %w", accountID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return p.apiAdminAccount(ctx, account)
+}
+
+// AccountApprove approves the pending sign-up of the local account with the given
+// ID, allowing the user to log in, and emails the user to let them know. Approving
+// an account that's already approved is a no-op.
+func (p *Processor) AccountApprove(ctx context.Context, account *gtsmodel.Account, accountID string) (*apimodel.AdminAccountInfo, gtserror.WithCode) {
+	user, errWithCode := p.getUserByAccountID(ctx, accountID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if *user.Approved {
+		// Already approved,
+		// nothing to do.
+		return p.apiAdminAccount(ctx, user.Account)
+	}
+
+	user.Approved = util.Ptr(true)
+	if err := p.state.DB.UpdateUser(ctx, user, "approved"); err != nil {
+		err = gtserror.Newf("db error updating user %s: %w", user.ID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// Process side effects (emailing) asynchronously.
+	p.state.Workers.EnqueueClientAPI(ctx, messages.FromClientAPI{
+		APObjectType:   ap.ObjectProfile,
+		APActivityType: ap.ActivityAccept,
+		GTSModel:       user,
+		OriginAccount:  account,
+		TargetAccount:  user.Account,
+	})
+
+	return p.apiAdminAccount(ctx, user.Account)
+}
+
+// AccountReject rejects the pending sign-up of the local account with the given ID.
+// The user and account are removed, but a gtsmodel.DeniedUser tombstone is kept so
+// that the username + email address can't be immediately reused for a new sign-up.
+// If sendEmail is true, the user will be emailed to let them know, including the
+// given message (if set).
+func (p *Processor) AccountReject(
+	ctx context.Context,
+	account *gtsmodel.Account,
+	accountID string,
+	privateComment string,
+	sendEmail bool,
+	message string,
+) (*apimodel.AdminAccountInfo, gtserror.WithCode) {
+	user, errWithCode := p.getUserByAccountID(ctx, accountID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if *user.Approved {
+		err := fmt.Errorf("account %s has already been approved", accountID)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	// Prepare the account to return, *before* it gets deleted.
+	apiAccount, errWithCode := p.apiAdminAccount(ctx, user.Account)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	email := user.Email
+	if email == "" {
+		email = user.UnconfirmedEmail
+	}
+
+	deniedUser := &gtsmodel.DeniedUser{
+		ID:                     id.NewULID(),
+		Email:                  email,
+		Username:               user.Account.Username,
+		SignUpIP:               user.SignUpIP,
+		Locale:                 user.Locale,
+		CreatedByApplicationID: user.CreatedByApplicationID,
+		SignUpReason:           user.Account.Reason,
+		PrivateComment:         text.SanitizePlaintext(privateComment),
+		SendEmail:              &sendEmail,
+		Message:                text.SanitizePlaintext(message),
+		DeniedByAccountID:      account.ID,
+		DeniedByAccount:        account,
+	}
+
+	if err := p.state.DB.PutDeniedUser(ctx, deniedUser); err != nil {
+		err = gtserror.Newf("db error putting denied user: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if err := p.deleteRejectedUser(ctx, user); err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// Process side effects (emailing) asynchronously.
+	p.state.Workers.EnqueueClientAPI(ctx, messages.FromClientAPI{
+		APObjectType:   ap.ObjectProfile,
+		APActivityType: ap.ActivityReject,
+		GTSModel:       deniedUser,
+		OriginAccount:  account,
+	})
+
+	return apiAccount, nil
+}
+
+// deleteRejectedUser removes the given user, its account, and anything
+// else created along with the sign-up (tokens, notifications), from the db.
+//
+// Unlike a regular account deletion, the account is not stubbified, since
+// the username should become available again after the rejection cooldown.
+func (p *Processor) deleteRejectedUser(ctx context.Context, user *gtsmodel.User) error {
+	tokens := []*gtsmodel.Token{}
+	if err := p.state.DB.GetWhere(ctx, []db.Where{{Key: "user_id", Value: user.ID}}, &tokens); err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return gtserror.Newf("db error getting tokens: %w", err)
+	}
+
+	for _, t := range tokens {
+		if err := p.state.DB.DeleteByID(ctx, t.ID, t); err != nil {
+			return gtserror.Newf("db error deleting token: %w", err)
+		}
+	}
+
+	// Remove sign-up notifications
+	// originating from the account.
+	if err := p.state.DB.DeleteNotifications(ctx, nil, "", user.AccountID); err != nil {
+		return gtserror.Newf("db error deleting notifications: %w", err)
+	}
+
+	if err := p.state.DB.DeleteUserByID(ctx, user.ID); err != nil {
+		return gtserror.Newf("db error deleting user: %w", err)
+	}
+
+	if err := p.state.DB.DeleteAccount(ctx, user.AccountID); err != nil {
+		return gtserror.Newf("db error deleting account: %w", err)
+	}
+
+	return nil
+}
+
+// getUserByAccountID is a shortcut function for getting the
+// user (with account populated) of the local account with the
+// given ID, returning an appropriate error if something goes wrong.
+func (p *Processor) getUserByAccountID(ctx context.Context, accountID string) (*gtsmodel.User, gtserror.WithCode) {
+	user, err := p.state.DB.GetUserByAccountID(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err = fmt.Errorf("no local account exists with id %s", accountID)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		err = gtserror.Newf("db error getting user for account %s: %w", accountID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if user.Account == nil {
+		user.Account, err = p.state.DB.GetAccountByID(ctx, accountID)
+		if err != nil {
+			err = gtserror.Newf("db error getting account %s: %w", accountID, err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+	}
+
+	return user, nil
+}
+
+// apiAdminAccount is a cheeky shortcut function for returning
+// the admin API version of the given account, or an appropriate
+// error if something goes wrong.
+func (p *Processor) apiAdminAccount(ctx context.Context, account *gtsmodel.Account) (*apimodel.AdminAccountInfo, gtserror.WithCode) {
+	apiAccount, err := p.tc.AccountToAdminAPIAccount(ctx, account)
+	if err != nil {
+		err = gtserror.Newf("error converting account %s to admin api model: %w", account.ID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return apiAccount, nil
+}
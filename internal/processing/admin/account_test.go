@@ -0,0 +1,109 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package admin_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/processing/admin"
+)
+
+type AccountTestSuite struct {
+	AdminStandardTestSuite
+}
+
+func (suite *AccountTestSuite) TestAccountsGetPending() {
+	resp, errWithCode := suite.adminProcessor.AccountsGet(context.Background(), admin.AccountStatusPending, "", "", "", 20)
+	suite.NoError(errWithCode)
+	suite.Len(resp.Items, 1)
+	suite.Contains(resp.LinkHeader, "status=pending")
+}
+
+func (suite *AccountTestSuite) TestAccountsGetInvalidStatus() {
+	_, errWithCode := suite.adminProcessor.AccountsGet(context.Background(), "bogus", "", "", "", 20)
+	suite.EqualError(errWithCode, "status must be one of pending or active, provided value was bogus")
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+}
+
+func (suite *AccountTestSuite) TestAccountApprove() {
+	var (
+		ctx           = context.Background()
+		adminAccount  = suite.testAccounts["admin_account"]
+		targetAccount = suite.testAccounts["unconfirmed_account"]
+	)
+
+	apiAccount, errWithCode := suite.adminProcessor.AccountApprove(ctx, adminAccount, targetAccount.ID)
+	suite.NoError(errWithCode)
+	suite.True(apiAccount.Approved)
+
+	user, err := suite.db.GetUserByAccountID(ctx, targetAccount.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.True(*user.Approved)
+}
+
+func (suite *AccountTestSuite) TestAccountReject() {
+	var (
+		ctx           = context.Background()
+		adminAccount  = suite.testAccounts["admin_account"]
+		targetAccount = suite.testAccounts["unconfirmed_account"]
+	)
+
+	apiAccount, errWithCode := suite.adminProcessor.AccountReject(ctx, adminAccount, targetAccount.ID, "spam", true, "sorry!")
+	suite.NoError(errWithCode)
+	suite.Equal(targetAccount.Username, apiAccount.Username)
+
+	// User and account should be gone.
+	_, err := suite.db.GetUserByAccountID(ctx, targetAccount.ID)
+	suite.True(errors.Is(err, db.ErrNoEntries))
+	_, err = suite.db.GetAccountByID(ctx, targetAccount.ID)
+	suite.True(errors.Is(err, db.ErrNoEntries))
+
+	// Username should not be available
+	// again until the cooldown has passed.
+	available, err := suite.db.IsUsernameAvailable(ctx, targetAccount.Username)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.False(available)
+}
+
+func (suite *AccountTestSuite) TestAccountRejectApproved() {
+	var (
+		ctx           = context.Background()
+		adminAccount  = suite.testAccounts["admin_account"]
+		targetAccount = suite.testAccounts["local_account_1"]
+	)
+
+	_, errWithCode := suite.adminProcessor.AccountReject(ctx, adminAccount, targetAccount.ID, "", false, "")
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+
+	// Account should still be there.
+	_, err := suite.db.GetAccountByID(ctx, targetAccount.ID)
+	suite.NoError(err)
+}
+
+func TestAccountTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountTestSuite))
+}
@@ -0,0 +1,78 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package admin
+
+import (
+	"context"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// DeliveryQueueGet returns the current depth of the outgoing
+// federation delivery queue, along with any domains that
+// deliveries are failing to, and how many are queued for each.
+func (p *Processor) DeliveryQueueGet(ctx context.Context) (*apimodel.AdminDeliveryQueue, gtserror.WithCode) {
+	depth, err := p.state.DB.CountDeliveries(ctx)
+	if err != nil {
+		err := gtserror.Newf("error counting deliveries: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	failures, err := p.state.DB.GetDeliveryFailures(ctx)
+	if err != nil {
+		err := gtserror.Newf("error getting delivery failures: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	var (
+		now            = time.Now()
+		failingDomains = make([]*apimodel.AdminDeliveryFailure, 0, len(failures))
+	)
+
+	for _, failure := range failures {
+		queued, err := p.state.DB.CountDeliveriesForDomain(ctx, failure.Domain)
+		if err != nil {
+			err := gtserror.Newf("error counting deliveries for %s: %w", failure.Domain, err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		failingDomain := &apimodel.AdminDeliveryFailure{
+			Domain:       failure.Domain,
+			FailingSince: util.FormatISO8601(failure.FailingSince),
+			Failures:     failure.Failures,
+			LastError:    failure.LastError,
+			Queued:       queued,
+		}
+
+		if now.Before(failure.RetryAt) {
+			retryAt := util.FormatISO8601(failure.RetryAt)
+			failingDomain.Unreachable = true
+			failingDomain.RetryAt = &retryAt
+		}
+
+		failingDomains = append(failingDomains, failingDomain)
+	}
+
+	return &apimodel.AdminDeliveryQueue{
+		QueueDepth:     depth,
+		FailingDomains: failingDomains,
+	}, nil
+}
@@ -0,0 +1,260 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+)
+
+// DomainAllowCreate creates an instance-level allow for the given domain.
+// Allows only take effect when the instance runs in allowlist federation mode.
+//
+// If a domain allow already exists for the domain, it will just be returned.
+func (p *Processor) DomainAllowCreate(
+	ctx context.Context,
+	account *gtsmodel.Account,
+	domain string,
+	obfuscate bool,
+	publicComment string,
+	privateComment string,
+	subscriptionID string,
+) (*apimodel.DomainAllow, gtserror.WithCode) {
+	// Check if an allow already exists for this domain.
+	domainAllow, err := p.state.DB.GetDomainAllow(ctx, domain)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		// Something went wrong in the DB.
+		err = gtserror.Newf("db error getting domain allow %s: %w", domain, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if domainAllow == nil {
+		// No allow exists yet, create it.
+		domainAllow = &gtsmodel.DomainAllow{
+			ID:                 id.NewULID(),
+			Domain:             domain,
+			CreatedByAccountID: account.ID,
+			PrivateComment:     text.SanitizePlaintext(privateComment),
+			PublicComment:      text.SanitizePlaintext(publicComment),
+			Obfuscate:          &obfuscate,
+			SubscriptionID:     subscriptionID,
+		}
+
+		// Insert the new allow into the database.
+		if err := p.state.DB.CreateDomainAllow(ctx, domainAllow); err != nil {
+			err = gtserror.Newf("db error putting domain allow %s: %w", domain, err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+	}
+
+	return p.apiDomainAllow(ctx, domainAllow, false)
+}
+
+// DomainAllowsImport handles the import of multiple domain allows,
+// by calling the DomainAllowCreate function for each domain in the
+// provided file. Will return a slice of processed domain allows.
+//
+// In the case of total failure, a gtserror.WithCode will be returned
+// so that the caller can respond appropriately. In the case of
+// partial or total success, a MultiStatus model will be returned,
+// which contains information about success/failure count, so that
+// the caller can retry any failures as they wish.
+func (p *Processor) DomainAllowsImport(
+	ctx context.Context,
+	account *gtsmodel.Account,
+	domainsF *multipart.FileHeader,
+) (*apimodel.MultiStatus, gtserror.WithCode) {
+	// Open the provided file.
+	file, err := domainsF.Open()
+	if err != nil {
+		err = gtserror.Newf("error opening attachment: %w", err)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+	defer file.Close()
+
+	// Copy the file contents into a buffer.
+	buf := new(bytes.Buffer)
+	size, err := io.Copy(buf, file)
+	if err != nil {
+		err = gtserror.Newf("error reading attachment: %w", err)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	// Ensure we actually read something.
+	if size == 0 {
+		err = gtserror.New("error reading attachment: size 0 bytes")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	// Parse bytes as slice of domain allows.
+	domainAllows := make([]*apimodel.DomainAllow, 0)
+	if err := json.Unmarshal(buf.Bytes(), &domainAllows); err != nil {
+		err = gtserror.Newf("error parsing attachment as domain allows: %w", err)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	count := len(domainAllows)
+	if count == 0 {
+		err = gtserror.New("error importing domain allows: 0 entries provided")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	// Try to process each domain allow, differentiating
+	// between successes and errors so that the caller can
+	// try failed imports again if desired.
+	multiStatusEntries := make([]apimodel.MultiStatusEntry, 0, count)
+
+	for _, domainAllow := range domainAllows {
+		var (
+			domain         = domainAllow.Domain.Domain
+			obfuscate      = domainAllow.Obfuscate
+			publicComment  = domainAllow.PublicComment
+			privateComment = domainAllow.PrivateComment
+			subscriptionID = "" // No sub ID for imports.
+			errWithCode    gtserror.WithCode
+		)
+
+		domainAllow, errWithCode = p.DomainAllowCreate(
+			ctx,
+			account,
+			domain,
+			obfuscate,
+			publicComment,
+			privateComment,
+			subscriptionID,
+		)
+
+		var entry *apimodel.MultiStatusEntry
+
+		if errWithCode != nil {
+			entry = &apimodel.MultiStatusEntry{
+				// Use the failed domain entry as the resource value.
+				Resource: domain,
+				Message:  errWithCode.Safe(),
+				Status:   errWithCode.Code(),
+			}
+		} else {
+			entry = &apimodel.MultiStatusEntry{
+				// Use successfully created API model domain allow as the resource value.
+				Resource: domainAllow,
+				Message:  http.StatusText(http.StatusOK),
+				Status:   http.StatusOK,
+			}
+		}
+
+		multiStatusEntries = append(multiStatusEntries, *entry)
+	}
+
+	return apimodel.NewMultiStatus(multiStatusEntries), nil
+}
+
+// DomainAllowsGet returns all existing domain allows. If export is
+// true, the format will be suitable for writing out to an export.
+func (p *Processor) DomainAllowsGet(ctx context.Context, account *gtsmodel.Account, export bool) ([]*apimodel.DomainAllow, gtserror.WithCode) {
+	domainAllows, err := p.state.DB.GetDomainAllows(ctx)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err = gtserror.Newf("db error getting domain allows: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiDomainAllows := make([]*apimodel.DomainAllow, 0, len(domainAllows))
+	for _, domainAllow := range domainAllows {
+		apiDomainAllow, errWithCode := p.apiDomainAllow(ctx, domainAllow, export)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+
+		apiDomainAllows = append(apiDomainAllows, apiDomainAllow)
+	}
+
+	return apiDomainAllows, nil
+}
+
+// DomainAllowGet returns one domain allow with the given id. If export
+// is true, the format will be suitable for writing out to an export.
+func (p *Processor) DomainAllowGet(ctx context.Context, id string, export bool) (*apimodel.DomainAllow, gtserror.WithCode) {
+	domainAllow, err := p.state.DB.GetDomainAllowByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err = fmt.Errorf("no domain allow exists with id %s", id)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+
+		// Something went wrong in the DB.
+		err = gtserror.Newf("db error getting domain allow %s: %w", id, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return p.apiDomainAllow(ctx, domainAllow, export)
+}
+
+// DomainAllowDelete removes one domain allow with the given ID.
+func (p *Processor) DomainAllowDelete(ctx context.Context, account *gtsmodel.Account, id string) (*apimodel.DomainAllow, gtserror.WithCode) {
+	domainAllow, err := p.state.DB.GetDomainAllowByID(ctx, id)
+	if err != nil {
+		if !errors.Is(err, db.ErrNoEntries) {
+			// Real error.
+			err = gtserror.Newf("db error getting domain allow: %w", err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		// There are just no entries for this ID.
+		err = fmt.Errorf("no domain allow entry exists with ID %s", id)
+		return nil, gtserror.NewErrorNotFound(err, err.Error())
+	}
+
+	// Prepare the domain allow to return, *before* the deletion goes through.
+	apiDomainAllow, errWithCode := p.apiDomainAllow(ctx, domainAllow, false)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// Delete the original domain allow.
+	if err := p.state.DB.DeleteDomainAllow(ctx, domainAllow.Domain); err != nil {
+		err = gtserror.Newf("db error deleting domain allow: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return apiDomainAllow, nil
+}
+
+// apiDomainAllow is a cheeky shortcut function for returning the API
+// version of the given domainAllow, or an appropriate error if
+// something goes wrong.
+func (p *Processor) apiDomainAllow(ctx context.Context, domainAllow *gtsmodel.DomainAllow, export bool) (*apimodel.DomainAllow, gtserror.WithCode) {
+	apiDomainAllow, err := p.tc.DomainAllowToAPIDomainAllow(ctx, domainAllow, export)
+	if err != nil {
+		err = gtserror.Newf("error converting domain allow for %s to api model : %w", domainAllow.Domain, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return apiDomainAllow, nil
+}
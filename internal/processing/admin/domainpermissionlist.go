@@ -0,0 +1,249 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package admin
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// maxDomainPermissionsListSize is the maximum
+// number of bytes we'll read from a domain
+// permissions list before bailing, 10MiB.
+const maxDomainPermissionsListSize = 10 << 20
+
+// domainPermissionEntry is one entry
+// parsed from a domain permissions list.
+type domainPermissionEntry struct {
+	domain        string
+	publicComment string
+	obfuscate     bool
+}
+
+// parseDomainPermissions parses domain permission entries from
+// the given reader, according to the given content type. Entries
+// with invalid domains are skipped, and duplicates are dropped.
+//
+// For block lists in CSV format, entries with a severity other
+// than "suspend" are also skipped, since we don't support them.
+func parseDomainPermissions(
+	r io.Reader,
+	contentType string,
+	permType gtsmodel.DomainPermissionType,
+) ([]*domainPermissionEntry, error) {
+	var (
+		entries []*domainPermissionEntry
+		err     error
+	)
+
+	// Don't read more than we're willing to handle.
+	r = io.LimitReader(r, maxDomainPermissionsListSize)
+
+	switch contentType {
+	case "text/csv":
+		entries, err = parseDomainPermissionsCSV(r, permType)
+	case "application/json":
+		entries, err = parseDomainPermissionsJSON(r)
+	case "text/plain":
+		entries, err = parseDomainPermissionsPlain(r)
+	default:
+		err = gtserror.Newf("unsupported content type %s", contentType)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Normalize entry domains, dropping
+	// any invalid ones and duplicates.
+	seen := make(map[string]struct{}, len(entries))
+	valid := entries[:0]
+
+	for _, entry := range entries {
+		domain, ok := normalizeListDomain(entry.domain)
+		if !ok {
+			continue
+		}
+
+		if _, ok := seen[domain]; ok {
+			continue
+		}
+
+		seen[domain] = struct{}{}
+		entry.domain = domain
+		valid = append(valid, entry)
+	}
+
+	return valid, nil
+}
+
+// parseDomainPermissionsCSV parses entries from a Mastodon-style CSV list,
+// ie., a header row containing at least a "#domain" column, optionally with
+// "#severity", "#public_comment", and "#obfuscate" columns. Column names
+// may be given with or without the leading '#'.
+func parseDomainPermissionsCSV(r io.Reader, permType gtsmodel.DomainPermissionType) ([]*domainPermissionEntry, error) {
+	csvReader := csv.NewReader(r)
+	csvReader.FieldsPerRecord = -1 // Allow variable fields.
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, gtserror.Newf("error reading csv header: %w", err)
+	}
+
+	// Map column names to their indices.
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimPrefix(strings.TrimSpace(name), "#")
+		columns[strings.ToLower(name)] = i
+	}
+
+	domainIdx, ok := columns["domain"]
+	if !ok {
+		return nil, gtserror.New("csv header contained no domain column")
+	}
+
+	// field returns the value of the named
+	// column in the given record, if present.
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var entries []*domainPermissionEntry
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, gtserror.Newf("error reading csv record: %w", err)
+		}
+
+		if domainIdx >= len(record) {
+			// Malformed record.
+			continue
+		}
+
+		if permType == gtsmodel.DomainPermissionBlock {
+			// Only suspensions are supported as blocks.
+			severity := field(record, "severity")
+			if severity != "" && severity != "suspend" {
+				continue
+			}
+		}
+
+		obfuscate, _ := strconv.ParseBool(field(record, "obfuscate"))
+		entries = append(entries, &domainPermissionEntry{
+			domain:        record[domainIdx],
+			publicComment: field(record, "public_comment"),
+			obfuscate:     obfuscate,
+		})
+	}
+
+	return entries, nil
+}
+
+// parseDomainPermissionsJSON parses entries from a JSON list
+// in the same format as used by domain block import / export.
+func parseDomainPermissionsJSON(r io.Reader) ([]*domainPermissionEntry, error) {
+	var list []struct {
+		Domain        string `json:"domain"`
+		PublicComment string `json:"public_comment"`
+		Obfuscate     bool   `json:"obfuscate"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&list); err != nil {
+		return nil, gtserror.Newf("error decoding json: %w", err)
+	}
+
+	entries := make([]*domainPermissionEntry, 0, len(list))
+	for _, item := range list {
+		entries = append(entries, &domainPermissionEntry{
+			domain:        item.Domain,
+			publicComment: item.PublicComment,
+			obfuscate:     item.Obfuscate,
+		})
+	}
+
+	return entries, nil
+}
+
+// parseDomainPermissionsPlain parses entries from a plaintext
+// list of one domain per line. Empty lines are ignored, as are
+// comment lines beginning with '#'.
+func parseDomainPermissionsPlain(r io.Reader) ([]*domainPermissionEntry, error) {
+	var entries []*domainPermissionEntry
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		entries = append(entries, &domainPermissionEntry{
+			domain: line,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, gtserror.Newf("error reading plaintext: %w", err)
+	}
+
+	return entries, nil
+}
+
+// normalizeListDomain normalizes the given domain from a domain
+// permissions list, returning false if the domain is not valid
+// (eg., it's obfuscated, or not a domain name at all).
+func normalizeListDomain(domain string) (string, bool) {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	domain = strings.TrimPrefix(domain, "*.")
+	domain = strings.TrimSuffix(domain, ".")
+
+	if domain == "" || strings.Contains(domain, "*") {
+		// Empty or obfuscated.
+		return "", false
+	}
+
+	domain, err := util.Punify(domain)
+	if err != nil {
+		return "", false
+	}
+
+	if _, ok := dns.IsDomainName(domain); !ok || !strings.Contains(domain, ".") {
+		return "", false
+	}
+
+	return domain, true
+}
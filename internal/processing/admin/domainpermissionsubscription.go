@@ -0,0 +1,636 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtscontext"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+)
+
+// DomainPermissionSubscriptionCreate creates a new subscription to the domain
+// permissions list at the given URI, and enqueues a first sync of the list.
+func (p *Processor) DomainPermissionSubscriptionCreate(
+	ctx context.Context,
+	account *gtsmodel.Account,
+	priority uint8,
+	title string,
+	permissionType string,
+	uri string,
+	contentType string,
+	adoptOrphans bool,
+) (*apimodel.DomainPermissionSubscription, gtserror.WithCode) {
+	permType, errWithCode := parseDomainPermissionType(permissionType)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if permType == "" {
+		err := errors.New("permission_type must be set")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	listURI, err := url.Parse(uri)
+	if err != nil || listURI.Host == "" && listURI.Scheme != "file" {
+		err := fmt.Errorf("invalid uri %q", uri)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	switch listURI.Scheme {
+	case "http", "https", "file":
+		// no problem
+	default:
+		err := fmt.Errorf("uri scheme must be one of http, https, or file, provided value was %s", listURI.Scheme)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	switch contentType {
+	case "text/csv", "application/json", "text/plain":
+		// no problem
+	default:
+		err := fmt.Errorf("content_type must be one of text/csv, application/json, or text/plain, provided value was %s", contentType)
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	sub := &gtsmodel.DomainPermissionSubscription{
+		ID:                 id.NewULID(),
+		Priority:           priority,
+		Title:              text.SanitizePlaintext(title),
+		PermissionType:     permType,
+		CreatedByAccountID: account.ID,
+		CreatedByAccount:   account,
+		URI:                listURI.String(),
+		ContentType:        contentType,
+		AdoptOrphans:       &adoptOrphans,
+	}
+
+	if err := p.state.DB.CreateDomainPermissionSubscription(ctx, sub); err != nil {
+		if errors.Is(err, db.ErrAlreadyExists) {
+			err = fmt.Errorf("a domain permission subscription with uri %s already exists", sub.URI)
+			return nil, gtserror.NewErrorConflict(err, err.Error())
+		}
+
+		err = gtserror.Newf("db error putting domain permission subscription: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// Do a first sync of the new subscription
+	// asynchronously, since it might take a while.
+	p.state.Workers.ClientAPI.Enqueue(func(ctx context.Context) {
+		if _, err := p.syncDomainPermissionSubscription(ctx, sub); err != nil {
+			log.Errorf(ctx, "error syncing domain permission subscription %s: %v", sub.URI, err)
+		}
+	})
+
+	return p.apiDomainPermissionSubscription(ctx, sub)
+}
+
+// DomainPermissionSubscriptionsGet returns all domain permission subscriptions of
+// the given permission type, or of all permission types if permissionType is empty.
+func (p *Processor) DomainPermissionSubscriptionsGet(
+	ctx context.Context,
+	permissionType string,
+) ([]*apimodel.DomainPermissionSubscription, gtserror.WithCode) {
+	permType, errWithCode := parseDomainPermissionType(permissionType)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	subs, err := p.state.DB.GetDomainPermissionSubscriptions(ctx, permType)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err = gtserror.Newf("db error getting domain permission subscriptions: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiSubs := make([]*apimodel.DomainPermissionSubscription, 0, len(subs))
+	for _, sub := range subs {
+		apiSub, errWithCode := p.apiDomainPermissionSubscription(ctx, sub)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+
+		apiSubs = append(apiSubs, apiSub)
+	}
+
+	return apiSubs, nil
+}
+
+// DomainPermissionSubscriptionGet returns one domain permission subscription with the given id.
+func (p *Processor) DomainPermissionSubscriptionGet(ctx context.Context, id string) (*apimodel.DomainPermissionSubscription, gtserror.WithCode) {
+	sub, errWithCode := p.getDomainPermissionSubscription(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.apiDomainPermissionSubscription(ctx, sub)
+}
+
+// DomainPermissionSubscriptionRemove removes one domain permission subscription with the
+// given id. If removeChildren is true, all domain permissions created by the subscription
+// will be removed too (with side effects); otherwise they will be orphaned and left in place.
+func (p *Processor) DomainPermissionSubscriptionRemove(
+	ctx context.Context,
+	account *gtsmodel.Account,
+	id string,
+	removeChildren bool,
+) (*apimodel.DomainPermissionSubscription, gtserror.WithCode) {
+	sub, errWithCode := p.getDomainPermissionSubscription(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// Prepare the subscription to return, *before* the deletion goes through.
+	apiSub, errWithCode := p.apiDomainPermissionSubscription(ctx, sub)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	children, err := p.getDomainPermissionsBySubscriptionID(ctx, sub.PermissionType, sub.ID)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	for _, child := range children {
+		if removeChildren {
+			// Remove the child permission entirely.
+			if errWithCode := p.deleteDomainPermission(ctx, account, sub.PermissionType, child); errWithCode != nil {
+				return nil, errWithCode
+			}
+			continue
+		}
+
+		// Just orphan the child permission.
+		if err := p.setDomainPermissionSubscriptionID(ctx, sub.PermissionType, child, ""); err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+	}
+
+	if err := p.state.DB.DeleteDomainPermissionSubscription(ctx, sub.ID); err != nil {
+		err = gtserror.Newf("db error deleting domain permission subscription: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return apiSub, nil
+}
+
+// DomainPermissionSubscriptionTest fetches the list of the domain permission subscription
+// with the given id, and returns a preview of the changes that syncing it would make,
+// without actually making any changes.
+func (p *Processor) DomainPermissionSubscriptionTest(ctx context.Context, id string) (*apimodel.DomainPermissionSubscriptionPreview, gtserror.WithCode) {
+	sub, errWithCode := p.getDomainPermissionSubscription(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	preview, err := p.syncDomainPermissionSubscription(gtscontext.SetDryRun(ctx), sub)
+	if err != nil {
+		err = gtserror.Newf("error testing domain permission subscription: %w", err)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	return preview, nil
+}
+
+// DomainPermissionSubscriptionsSync fetches and syncs all domain permission
+// subscriptions, in order of priority. It's intended to be called periodically
+// by the scheduler. Errors are logged rather than returned, and stored on
+// the subscription for admins to see.
+func (p *Processor) DomainPermissionSubscriptionsSync(ctx context.Context) {
+	subs, err := p.state.DB.GetDomainPermissionSubscriptions(ctx, "")
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		log.Errorf(ctx, "db error getting domain permission subscriptions: %v", err)
+		return
+	}
+
+	for _, sub := range subs {
+		if _, err := p.syncDomainPermissionSubscription(ctx, sub); err != nil {
+			log.Errorf(ctx, "error syncing domain permission subscription %s: %v", sub.URI, err)
+		}
+	}
+}
+
+// syncDomainPermissionSubscription fetches the list for the given subscription
+// and diffs it against the domain permissions previously created by the subscription,
+// creating and removing permissions (with side effects) as necessary. If the context
+// is set to dry run, no changes are made, but the returned preview is still populated.
+func (p *Processor) syncDomainPermissionSubscription(
+	ctx context.Context,
+	sub *gtsmodel.DomainPermissionSubscription,
+) (*apimodel.DomainPermissionSubscriptionPreview, error) {
+	dryRun := gtscontext.DryRun(ctx)
+
+	// Fetch + parse the latest version of the list.
+	entries, err := p.fetchDomainPermissions(ctx, sub)
+
+	if !dryRun {
+		// Store the outcome of the fetch.
+		sub.FetchedAt = time.Now()
+		sub.Error = ""
+		if err != nil {
+			sub.Error = err.Error()
+		} else {
+			sub.SuccessfullyFetchedAt = sub.FetchedAt
+		}
+
+		if err := p.state.DB.UpdateDomainPermissionSubscription(
+			ctx, sub,
+			"fetched_at",
+			"successfully_fetched_at",
+			"error",
+		); err != nil {
+			return nil, gtserror.Newf("db error updating domain permission subscription: %w", err)
+		}
+	}
+
+	if err != nil {
+		// Don't touch existing
+		// permissions on failure.
+		return nil, err
+	}
+
+	if sub.CreatedByAccount == nil {
+		// Permissions are created + removed on behalf of the subscription creator.
+		sub.CreatedByAccount, err = p.state.DB.GetAccountByID(ctx, sub.CreatedByAccountID)
+		if err != nil {
+			return nil, gtserror.Newf("db error getting subscription creator: %w", err)
+		}
+	}
+
+	// Get priorities of other subscriptions of the same type,
+	// so that we can resolve which subscription owns a permission.
+	subs, err := p.state.DB.GetDomainPermissionSubscriptions(ctx, sub.PermissionType)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return nil, gtserror.Newf("db error getting domain permission subscriptions: %w", err)
+	}
+
+	priorities := make(map[string]uint8, len(subs))
+	for _, s := range subs {
+		priorities[s.ID] = s.Priority
+	}
+
+	var (
+		preview = &apimodel.DomainPermissionSubscriptionPreview{
+			Created: []string{},
+			Adopted: []string{},
+			Removed: []string{},
+		}
+		listed = make(map[string]struct{}, len(entries))
+	)
+
+	for _, entry := range entries {
+		listed[entry.domain] = struct{}{}
+
+		existing, err := p.getDomainPermission(ctx, sub.PermissionType, entry.domain)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case existing == nil:
+			// No permission exists yet for this
+			// domain, create one owned by this sub.
+			preview.Created = append(preview.Created, entry.domain)
+			if dryRun {
+				continue
+			}
+
+			if errWithCode := p.createDomainPermission(ctx, sub, entry); errWithCode != nil {
+				log.Errorf(ctx, "error creating domain permission for %s: %v", entry.domain, errWithCode)
+			}
+			continue
+
+		case existing.subscriptionID == sub.ID:
+			// Already ours, nothing to do.
+			continue
+
+		case existing.subscriptionID == "":
+			// Orphan permission, only adopt
+			// it if subscription is set to.
+			if !*sub.AdoptOrphans {
+				continue
+			}
+
+		default:
+			// Owned by another subscription, only take
+			// it over if this one has higher priority
+			// (or the other subscription no longer exists).
+			priority, ok := priorities[existing.subscriptionID]
+			if ok && priority >= sub.Priority {
+				continue
+			}
+		}
+
+		preview.Adopted = append(preview.Adopted, entry.domain)
+		if dryRun {
+			continue
+		}
+
+		if err := p.setDomainPermissionSubscriptionID(ctx, sub.PermissionType, existing, sub.ID); err != nil {
+			log.Errorf(ctx, "error adopting domain permission for %s: %v", entry.domain, err)
+		}
+	}
+
+	// Remove any permissions owned by this
+	// subscription that are no longer listed.
+	owned, err := p.getDomainPermissionsBySubscriptionID(ctx, sub.PermissionType, sub.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, perm := range owned {
+		if _, ok := listed[perm.domain]; ok {
+			continue
+		}
+
+		preview.Removed = append(preview.Removed, perm.domain)
+		if dryRun {
+			continue
+		}
+
+		if errWithCode := p.deleteDomainPermission(ctx, sub.CreatedByAccount, sub.PermissionType, perm); errWithCode != nil {
+			log.Errorf(ctx, "error removing domain permission for %s: %v", perm.domain, errWithCode)
+		}
+	}
+
+	return preview, nil
+}
+
+// fetchDomainPermissions fetches and parses the
+// domain permissions list of the given subscription,
+// either from the local filesystem or a remote server.
+func (p *Processor) fetchDomainPermissions(
+	ctx context.Context,
+	sub *gtsmodel.DomainPermissionSubscription,
+) ([]*domainPermissionEntry, error) {
+	listURI, err := url.Parse(sub.URI)
+	if err != nil {
+		return nil, gtserror.Newf("invalid uri %s: %w", sub.URI, err)
+	}
+
+	var rc io.ReadCloser
+
+	if listURI.Scheme == "file" {
+		// Local list, just open the file.
+		rc, err = os.Open(listURI.Path)
+		if err != nil {
+			return nil, gtserror.Newf("error opening %s: %w", listURI.Path, err)
+		}
+	} else {
+		// Remote list, fetch using instance account transport.
+		tsport, err := p.transportController.NewTransportForUsername(ctx, "")
+		if err != nil {
+			return nil, gtserror.Newf("error getting transport: %w", err)
+		}
+
+		rc, err = tsport.DereferenceDomainPermissions(ctx, listURI, sub.ContentType)
+		if err != nil {
+			return nil, gtserror.Newf("error dereferencing %s: %w", sub.URI, err)
+		}
+	}
+	defer rc.Close()
+
+	entries, err := parseDomainPermissions(rc, sub.ContentType, sub.PermissionType)
+	if err != nil {
+		return nil, gtserror.Newf("error parsing %s: %w", sub.URI, err)
+	}
+
+	return entries, nil
+}
+
+// getDomainPermissionSubscription is a shortcut function for
+// getting one domain permission subscription by its id,
+// returning an appropriate error if something goes wrong.
+func (p *Processor) getDomainPermissionSubscription(ctx context.Context, id string) (*gtsmodel.DomainPermissionSubscription, gtserror.WithCode) {
+	sub, err := p.state.DB.GetDomainPermissionSubscriptionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err = fmt.Errorf("no domain permission subscription exists with id %s", id)
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+
+		// Something went wrong in the DB.
+		err = gtserror.Newf("db error getting domain permission subscription %s: %w", id, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return sub, nil
+}
+
+// apiDomainPermissionSubscription is a cheeky shortcut function for
+// returning the API version of the given subscription, or an
+// appropriate error if something goes wrong.
+func (p *Processor) apiDomainPermissionSubscription(ctx context.Context, sub *gtsmodel.DomainPermissionSubscription) (*apimodel.DomainPermissionSubscription, gtserror.WithCode) {
+	apiSub, err := p.tc.DomainPermissionSubscriptionToAPIDomainPermissionSubscription(ctx, sub)
+	if err != nil {
+		err = gtserror.Newf("error converting domain permission subscription %s to api model: %w", sub.URI, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return apiSub, nil
+}
+
+// parseDomainPermissionType parses the given permission type
+// string, returning a bad request error if it's not valid.
+// An empty string is returned as an empty permission type.
+func parseDomainPermissionType(permissionType string) (gtsmodel.DomainPermissionType, gtserror.WithCode) {
+	switch permType := gtsmodel.DomainPermissionType(permissionType); permType {
+	case "", gtsmodel.DomainPermissionBlock, gtsmodel.DomainPermissionAllow:
+		return permType, nil
+	default:
+		err := fmt.Errorf("permission_type must be one of block or allow, provided value was %s", permissionType)
+		return "", gtserror.NewErrorBadRequest(err, err.Error())
+	}
+}
+
+// domainPermission is a minimal view of an existing
+// domain block or allow, used when syncing subscriptions.
+type domainPermission struct {
+	id             string
+	domain         string
+	subscriptionID string
+}
+
+// getDomainPermission returns the domain permission of the given
+// type for the given domain, or nil if no such permission exists.
+func (p *Processor) getDomainPermission(
+	ctx context.Context,
+	permType gtsmodel.DomainPermissionType,
+	domain string,
+) (*domainPermission, error) {
+	var (
+		perm *domainPermission
+		err  error
+	)
+
+	switch permType {
+	case gtsmodel.DomainPermissionBlock:
+		var block *gtsmodel.DomainBlock
+		block, err = p.state.DB.GetDomainBlock(ctx, domain)
+		if block != nil {
+			perm = &domainPermission{block.ID, block.Domain, block.SubscriptionID}
+		}
+
+	case gtsmodel.DomainPermissionAllow:
+		var allow *gtsmodel.DomainAllow
+		allow, err = p.state.DB.GetDomainAllow(ctx, domain)
+		if allow != nil {
+			perm = &domainPermission{allow.ID, allow.Domain, allow.SubscriptionID}
+		}
+	}
+
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return nil, gtserror.Newf("db error getting domain %s %s: %w", permType, domain, err)
+	}
+
+	return perm, nil
+}
+
+// getDomainPermissionsBySubscriptionID returns all domain
+// permissions of the given type created by the given subscription.
+func (p *Processor) getDomainPermissionsBySubscriptionID(
+	ctx context.Context,
+	permType gtsmodel.DomainPermissionType,
+	subscriptionID string,
+) ([]*domainPermission, error) {
+	var perms []*domainPermission
+
+	switch permType {
+	case gtsmodel.DomainPermissionBlock:
+		blocks, err := p.state.DB.GetDomainBlocksBySubscriptionID(ctx, subscriptionID)
+		if err != nil && !errors.Is(err, db.ErrNoEntries) {
+			return nil, gtserror.Newf("db error getting domain blocks: %w", err)
+		}
+
+		for _, block := range blocks {
+			perms = append(perms, &domainPermission{block.ID, block.Domain, block.SubscriptionID})
+		}
+
+	case gtsmodel.DomainPermissionAllow:
+		allows, err := p.state.DB.GetDomainAllowsBySubscriptionID(ctx, subscriptionID)
+		if err != nil && !errors.Is(err, db.ErrNoEntries) {
+			return nil, gtserror.Newf("db error getting domain allows: %w", err)
+		}
+
+		for _, allow := range allows {
+			perms = append(perms, &domainPermission{allow.ID, allow.Domain, allow.SubscriptionID})
+		}
+	}
+
+	return perms, nil
+}
+
+// createDomainPermission creates a domain permission of the subscription's
+// type for the given list entry, processing side effects as appropriate.
+func (p *Processor) createDomainPermission(
+	ctx context.Context,
+	sub *gtsmodel.DomainPermissionSubscription,
+	entry *domainPermissionEntry,
+) (errWithCode gtserror.WithCode) {
+	switch sub.PermissionType {
+	case gtsmodel.DomainPermissionBlock:
+		_, errWithCode = p.DomainBlockCreate(
+			ctx,
+			sub.CreatedByAccount,
+			entry.domain,
+			entry.obfuscate,
+			entry.publicComment,
+			"", // No private comment for subscriptions.
+			sub.ID,
+		)
+
+	case gtsmodel.DomainPermissionAllow:
+		_, errWithCode = p.DomainAllowCreate(
+			ctx,
+			sub.CreatedByAccount,
+			entry.domain,
+			entry.obfuscate,
+			entry.publicComment,
+			"", // No private comment for subscriptions.
+			sub.ID,
+		)
+	}
+
+	return errWithCode
+}
+
+// deleteDomainPermission deletes the given domain permission
+// of the given type, processing side effects as appropriate.
+func (p *Processor) deleteDomainPermission(
+	ctx context.Context,
+	account *gtsmodel.Account,
+	permType gtsmodel.DomainPermissionType,
+	perm *domainPermission,
+) (errWithCode gtserror.WithCode) {
+	switch permType {
+	case gtsmodel.DomainPermissionBlock:
+		_, errWithCode = p.DomainBlockDelete(ctx, account, perm.id)
+	case gtsmodel.DomainPermissionAllow:
+		_, errWithCode = p.DomainAllowDelete(ctx, account, perm.id)
+	}
+
+	return errWithCode
+}
+
+// setDomainPermissionSubscriptionID updates the subscription
+// ID of the given domain permission of the given type.
+func (p *Processor) setDomainPermissionSubscriptionID(
+	ctx context.Context,
+	permType gtsmodel.DomainPermissionType,
+	perm *domainPermission,
+	subscriptionID string,
+) error {
+	var err error
+
+	switch permType {
+	case gtsmodel.DomainPermissionBlock:
+		var block *gtsmodel.DomainBlock
+		block, err = p.state.DB.GetDomainBlockByID(ctx, perm.id)
+		if err == nil {
+			block.SubscriptionID = subscriptionID
+			err = p.state.DB.UpdateDomainBlock(ctx, block, "subscription_id")
+		}
+
+	case gtsmodel.DomainPermissionAllow:
+		var allow *gtsmodel.DomainAllow
+		allow, err = p.state.DB.GetDomainAllowByID(ctx, perm.id)
+		if err == nil {
+			allow.SubscriptionID = subscriptionID
+			err = p.state.DB.UpdateDomainAllow(ctx, allow, "subscription_id")
+		}
+	}
+
+	if err != nil {
+		return gtserror.Newf("db error updating domain %s %s: %w", permType, perm.domain, err)
+	}
+
+	perm.subscriptionID = subscriptionID
+	return nil
+}
@@ -0,0 +1,191 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package admin_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type DomainPermissionSubscriptionTestSuite struct {
+	AdminStandardTestSuite
+}
+
+const testBlocklistURI = "https://lists.example.org/blocklist.csv"
+
+const testBlocklist = `#domain,#severity,#reject_media,#reject_reports,#public_comment,#obfuscate
+baddies.example.org,suspend,false,false,they're bad,false
+Replyguys.com,suspend,false,false,reply guys,false
+silenced.example.org,silence,false,false,just a bit annoying,false
+*.more-baddies.example.org,suspend,false,false,,true
+`
+
+func (suite *DomainPermissionSubscriptionTestSuite) putSubscription(adoptOrphans bool) *gtsmodel.DomainPermissionSubscription {
+	sub := &gtsmodel.DomainPermissionSubscription{
+		ID:                 "01H8ARPV6SA1QKG4ZMXD8ZAQM6",
+		Priority:           100,
+		Title:              "test blocklist",
+		PermissionType:     gtsmodel.DomainPermissionBlock,
+		CreatedByAccountID: suite.testAccounts["admin_account"].ID,
+		URI:                testBlocklistURI,
+		ContentType:        "text/csv",
+		AdoptOrphans:       &adoptOrphans,
+	}
+
+	if err := suite.db.CreateDomainPermissionSubscription(context.Background(), sub); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	return sub
+}
+
+func (suite *DomainPermissionSubscriptionTestSuite) TestSubscriptionTest() {
+	ctx := context.Background()
+	suite.remoteLists[testBlocklistURI] = testBlocklist
+	sub := suite.putSubscription(true)
+
+	preview, errWithCode := suite.adminProcessor.DomainPermissionSubscriptionTest(ctx, sub.ID)
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+
+	suite.Equal([]string{"baddies.example.org", "more-baddies.example.org"}, preview.Created)
+	suite.Equal([]string{"replyguys.com"}, preview.Adopted)
+	suite.Empty(preview.Removed)
+
+	// Nothing should have actually been changed.
+	_, err := suite.db.GetDomainBlock(ctx, "baddies.example.org")
+	suite.ErrorIs(err, db.ErrNoEntries)
+
+	block, err := suite.db.GetDomainBlock(ctx, "replyguys.com")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Empty(block.SubscriptionID)
+
+	dbSub, err := suite.db.GetDomainPermissionSubscriptionByID(ctx, sub.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Zero(dbSub.FetchedAt)
+}
+
+func (suite *DomainPermissionSubscriptionTestSuite) TestSubscriptionSync() {
+	ctx := context.Background()
+	suite.remoteLists[testBlocklistURI] = testBlocklist
+	sub := suite.putSubscription(false)
+
+	suite.adminProcessor.DomainPermissionSubscriptionsSync(ctx)
+
+	// New blocks should be created + owned by the subscription.
+	for _, domain := range []string{"baddies.example.org", "more-baddies.example.org"} {
+		block, err := suite.db.GetDomainBlock(ctx, domain)
+		if err != nil {
+			suite.FailNow(err.Error())
+		}
+		suite.Equal(sub.ID, block.SubscriptionID)
+	}
+
+	// Orphan block should not have been adopted.
+	block, err := suite.db.GetDomainBlock(ctx, "replyguys.com")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Empty(block.SubscriptionID)
+
+	// Silenced domain should be skipped.
+	_, err = suite.db.GetDomainBlock(ctx, "silenced.example.org")
+	suite.ErrorIs(err, db.ErrNoEntries)
+
+	dbSub, err := suite.db.GetDomainPermissionSubscriptionByID(ctx, sub.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.NotZero(dbSub.FetchedAt)
+	suite.Equal(dbSub.FetchedAt, dbSub.SuccessfullyFetchedAt)
+	suite.Empty(dbSub.Error)
+
+	// Drop one domain from the list and sync again.
+	suite.remoteLists[testBlocklistURI] = "more-baddies.example.org\n"
+	dbSub.ContentType = "text/plain"
+	if err := suite.db.UpdateDomainPermissionSubscription(ctx, dbSub, "content_type"); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.adminProcessor.DomainPermissionSubscriptionsSync(ctx)
+
+	// Block for the dropped domain should be removed.
+	if !testrig.WaitFor(func() bool {
+		_, err := suite.db.GetDomainBlock(ctx, "baddies.example.org")
+		return errors.Is(err, db.ErrNoEntries)
+	}) {
+		suite.FailNow("timed out waiting for domain block to be removed")
+	}
+
+	_, err = suite.db.GetDomainBlock(ctx, "more-baddies.example.org")
+	suite.NoError(err)
+}
+
+func (suite *DomainPermissionSubscriptionTestSuite) TestSubscriptionSyncFetchError() {
+	ctx := context.Background()
+	sub := suite.putSubscription(false)
+
+	// No list at the URI, so fetching should fail.
+	suite.adminProcessor.DomainPermissionSubscriptionsSync(ctx)
+
+	dbSub, err := suite.db.GetDomainPermissionSubscriptionByID(ctx, sub.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.WithinDuration(time.Now(), dbSub.FetchedAt, time.Minute)
+	suite.Zero(dbSub.SuccessfullyFetchedAt)
+	suite.NotEmpty(dbSub.Error)
+}
+
+func (suite *DomainPermissionSubscriptionTestSuite) TestSubscriptionRemoveOrphansChildren() {
+	ctx := context.Background()
+	suite.remoteLists[testBlocklistURI] = testBlocklist
+	sub := suite.putSubscription(false)
+
+	suite.adminProcessor.DomainPermissionSubscriptionsSync(ctx)
+
+	if _, errWithCode := suite.adminProcessor.DomainPermissionSubscriptionRemove(ctx, suite.testAccounts["admin_account"], sub.ID, false); errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+
+	// Block should still exist, but be orphaned.
+	block, err := suite.db.GetDomainBlock(ctx, "baddies.example.org")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Empty(block.SubscriptionID)
+
+	_, err = suite.db.GetDomainPermissionSubscriptionByID(ctx, sub.ID)
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
+func TestDomainPermissionSubscriptionTestSuite(t *testing.T) {
+	suite.Run(t, &DomainPermissionSubscriptionTestSuite{})
+}
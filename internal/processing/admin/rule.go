@@ -0,0 +1,203 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+	"github.com/superseriousbusiness/gotosocial/internal/validate"
+)
+
+// RulesGet returns all active rules of this instance, in order.
+func (p *Processor) RulesGet(ctx context.Context) ([]*apimodel.AdminInstanceRule, gtserror.WithCode) {
+	rules, err := p.state.DB.GetActiveRules(ctx)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiRules := make([]*apimodel.AdminInstanceRule, 0, len(rules))
+	for _, rule := range rules {
+		apiRules = append(apiRules, p.tc.RuleToAdminAPIRule(rule))
+	}
+
+	return apiRules, nil
+}
+
+// RuleGet returns one active rule, with the given ID.
+func (p *Processor) RuleGet(ctx context.Context, id string) (*apimodel.AdminInstanceRule, gtserror.WithCode) {
+	rule, errWithCode := p.getActiveRule(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.tc.RuleToAdminAPIRule(rule), nil
+}
+
+// RuleCreate adds a new rule to the end of this instance's rules.
+func (p *Processor) RuleCreate(ctx context.Context, form *apimodel.InstanceRuleCreateRequest) (*apimodel.AdminInstanceRule, gtserror.WithCode) {
+	if err := validate.InstanceRuleText(form.Text); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	rules, err := p.state.DB.GetActiveRules(ctx)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// Put the new rule after the last
+	// active one, if there are any.
+	var order uint
+	if len(rules) != 0 {
+		order = *rules[len(rules)-1].Order + 1
+	}
+
+	rule := &gtsmodel.Rule{
+		ID:      id.NewULID(),
+		Text:    form.Text,
+		Order:   &order,
+		Deleted: util.Ptr(false),
+	}
+
+	if err := p.state.DB.PutRule(ctx, rule); err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return p.tc.RuleToAdminAPIRule(rule), nil
+}
+
+// RuleUpdate updates the text and/or the position of the rule with the given ID.
+// When the position changes, the other active rules are renumbered to make room.
+func (p *Processor) RuleUpdate(ctx context.Context, id string, form *apimodel.InstanceRuleUpdateRequest) (*apimodel.AdminInstanceRule, gtserror.WithCode) {
+	if form.Text == nil && form.Order == nil {
+		const text = "empty form submitted"
+		return nil, gtserror.NewErrorBadRequest(errors.New(text), text)
+	}
+
+	rule, errWithCode := p.getActiveRule(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if form.Text != nil {
+		if err := validate.InstanceRuleText(*form.Text); err != nil {
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+
+		rule.Text = *form.Text
+		if err := p.state.DB.UpdateRule(ctx, rule, "text"); err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+	}
+
+	if form.Order != nil {
+		if *form.Order < 0 {
+			const text = "order must be 0 or greater"
+			return nil, gtserror.NewErrorBadRequest(errors.New(text), text)
+		}
+
+		if errWithCode := p.reorderRule(ctx, rule, *form.Order); errWithCode != nil {
+			return nil, errWithCode
+		}
+	}
+
+	return p.tc.RuleToAdminAPIRule(rule), nil
+}
+
+// RuleDelete deletes the rule with the given ID. The rule is only marked
+// as deleted, so that reports which refer to it can still show its text.
+func (p *Processor) RuleDelete(ctx context.Context, id string) (*apimodel.AdminInstanceRule, gtserror.WithCode) {
+	rule, errWithCode := p.getActiveRule(ctx, id)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	rule.Deleted = util.Ptr(true)
+	if err := p.state.DB.UpdateRule(ctx, rule, "deleted"); err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return p.tc.RuleToAdminAPIRule(rule), nil
+}
+
+// getActiveRule gets the rule with the given
+// ID, returning 404 if it has been deleted.
+func (p *Processor) getActiveRule(ctx context.Context, id string) (*gtsmodel.Rule, gtserror.WithCode) {
+	rule, err := p.state.DB.GetRuleByID(ctx, id)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if rule == nil || *rule.Deleted {
+		err := fmt.Errorf("rule %s not found", id)
+		return nil, gtserror.NewErrorNotFound(err, err.Error())
+	}
+
+	return rule, nil
+}
+
+// reorderRule moves the given rule to the given position
+// among the active rules, and renumbers any rules whose
+// position changed as a result.
+func (p *Processor) reorderRule(ctx context.Context, rule *gtsmodel.Rule, position int) gtserror.WithCode {
+	rules, err := p.state.DB.GetActiveRules(ctx)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	// Take the rule out of the list...
+	others := make([]*gtsmodel.Rule, 0, len(rules))
+	for _, r := range rules {
+		if r.ID != rule.ID {
+			others = append(others, r)
+		}
+	}
+
+	// ...and put it back in at the
+	// given position, or at the end.
+	if position > len(others) {
+		position = len(others)
+	}
+
+	reordered := make([]*gtsmodel.Rule, 0, len(others)+1)
+	reordered = append(reordered, others[:position]...)
+	reordered = append(reordered, rule)
+	reordered = append(reordered, others[position:]...)
+
+	for i, r := range reordered {
+		order := uint(i)
+		if r.Order != nil && *r.Order == order {
+			// Already in place.
+			continue
+		}
+
+		r.Order = &order
+		if err := p.state.DB.UpdateRule(ctx, r, "order"); err != nil {
+			return gtserror.NewErrorInternalError(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package admin_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+type RuleTestSuite struct {
+	AdminStandardTestSuite
+}
+
+// ruleTexts returns the texts of the active rules, in order.
+func (suite *RuleTestSuite) ruleTexts() []string {
+	rules, errWithCode := suite.adminProcessor.RulesGet(context.Background())
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+
+	texts := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		texts = append(texts, rule.Text)
+	}
+	return texts
+}
+
+func (suite *RuleTestSuite) TestRulesGet() {
+	suite.Equal([]string{
+		"Be nice to each other.",
+		"No spam or advertising.",
+	}, suite.ruleTexts())
+}
+
+func (suite *RuleTestSuite) TestRuleGetDeleted() {
+	rule := suite.testRules["rule_deleted"]
+
+	_, errWithCode := suite.adminProcessor.RuleGet(context.Background(), rule.ID)
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func (suite *RuleTestSuite) TestRuleCreate() {
+	rule, errWithCode := suite.adminProcessor.RuleCreate(context.Background(), &apimodel.InstanceRuleCreateRequest{
+		Text: "Put content warnings on spoilers.",
+	})
+	suite.NoError(errWithCode)
+	suite.NotEmpty(rule.ID)
+
+	suite.Equal([]string{
+		"Be nice to each other.",
+		"No spam or advertising.",
+		"Put content warnings on spoilers.",
+	}, suite.ruleTexts())
+}
+
+func (suite *RuleTestSuite) TestRuleCreateEmpty() {
+	_, errWithCode := suite.adminProcessor.RuleCreate(context.Background(), &apimodel.InstanceRuleCreateRequest{})
+	suite.EqualError(errWithCode, "rule text must be provided, and must be no more than 1000 chars")
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+}
+
+func (suite *RuleTestSuite) TestRuleUpdateOrder() {
+	ctx := context.Background()
+
+	// Add a third rule, then move it to the top.
+	rule, errWithCode := suite.adminProcessor.RuleCreate(ctx, &apimodel.InstanceRuleCreateRequest{
+		Text: "Put content warnings on spoilers.",
+	})
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+
+	_, errWithCode = suite.adminProcessor.RuleUpdate(ctx, rule.ID, &apimodel.InstanceRuleUpdateRequest{
+		Order: util.Ptr(0),
+	})
+	suite.NoError(errWithCode)
+
+	suite.Equal([]string{
+		"Put content warnings on spoilers.",
+		"Be nice to each other.",
+		"No spam or advertising.",
+	}, suite.ruleTexts())
+
+	// Now move the first rule past the end.
+	_, errWithCode = suite.adminProcessor.RuleUpdate(ctx, rule.ID, &apimodel.InstanceRuleUpdateRequest{
+		Order: util.Ptr(10),
+	})
+	suite.NoError(errWithCode)
+
+	suite.Equal([]string{
+		"Be nice to each other.",
+		"No spam or advertising.",
+		"Put content warnings on spoilers.",
+	}, suite.ruleTexts())
+}
+
+func (suite *RuleTestSuite) TestRuleUpdateText() {
+	rule := suite.testRules["rule_2"]
+
+	apiRule, errWithCode := suite.adminProcessor.RuleUpdate(context.Background(), rule.ID, &apimodel.InstanceRuleUpdateRequest{
+		Text: util.Ptr("No spam."),
+	})
+	suite.NoError(errWithCode)
+	suite.Equal("No spam.", apiRule.Text)
+
+	suite.Equal([]string{
+		"Be nice to each other.",
+		"No spam.",
+	}, suite.ruleTexts())
+}
+
+func (suite *RuleTestSuite) TestRuleDelete() {
+	ctx := context.Background()
+	rule := suite.testRules["rule_1"]
+
+	_, errWithCode := suite.adminProcessor.RuleDelete(ctx, rule.ID)
+	suite.NoError(errWithCode)
+
+	suite.Equal([]string{
+		"No spam or advertising.",
+	}, suite.ruleTexts())
+
+	// The rule is still there for reports to refer to.
+	dbRule, err := suite.db.GetRuleByID(ctx, rule.ID)
+	suite.NoError(err)
+	suite.True(*dbRule.Deleted)
+}
+
+func TestRuleTestSuite(t *testing.T) {
+	suite.Run(t, new(RuleTestSuite))
+}
@@ -37,6 +37,10 @@ func (p *Processor) AppCreate(ctx context.Context, authed *oauth.Auth, form *api
 		scopes = form.Scopes
 	}
 
+	if err := oauth.ValidateScopes(scopes); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
 	// generate new IDs for this application and its associated client
 	clientID, err := id.NewRandomULID()
 	if err != nil {
@@ -0,0 +1,80 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package conversations
+
+import (
+	"context"
+	"errors"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"github.com/superseriousbusiness/gotosocial/internal/visibility"
+)
+
+type Processor struct {
+	state  *state.State
+	tc     typeutils.TypeConverter
+	filter *visibility.Filter
+}
+
+func New(state *state.State, tc typeutils.TypeConverter, filter *visibility.Filter) Processor {
+	return Processor{
+		state:  state,
+		tc:     tc,
+		filter: filter,
+	}
+}
+
+// getConversationOwnedBy is a shortcut to get one conversation from the
+// database and check that it's owned by the given account. Will return
+// appropriate errors so caller doesn't need to bother.
+func (p *Processor) getConversationOwnedBy(ctx context.Context, id string, requestingAccount *gtsmodel.Account) (*gtsmodel.Conversation, gtserror.WithCode) {
+	conversation, err := p.state.DB.GetConversationByID(ctx, id)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err = gtserror.Newf("db error getting conversation %s: %w", id, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if conversation == nil {
+		err := gtserror.Newf("conversation %s not found", id)
+		return nil, gtserror.NewErrorNotFound(err)
+	}
+
+	if conversation.AccountID != requestingAccount.ID {
+		err := gtserror.Newf("conversation %s does not belong to account %s", id, requestingAccount.ID)
+		return nil, gtserror.NewErrorNotFound(err)
+	}
+
+	return conversation, nil
+}
+
+// apiConversation is a shortcut to return the API version of the given
+// conversation, or return an appropriate error if conversion fails.
+func (p *Processor) apiConversation(ctx context.Context, conversation *gtsmodel.Conversation, requestingAccount *gtsmodel.Account) (*apimodel.Conversation, gtserror.WithCode) {
+	apiConversation, err := p.tc.ConversationToAPIConversation(ctx, conversation, requestingAccount)
+	if err != nil {
+		err = gtserror.Newf("error converting conversation %s to api: %w", conversation.ID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return apiConversation, nil
+}
@@ -0,0 +1,84 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package conversations
+
+import (
+	"context"
+	"errors"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/paging"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// GetAll returns a page of conversations owned by the requesting account,
+// most recently active first. Paging is done by the ID of the most recent
+// status in each conversation, as with Mastodon.
+func (p *Processor) GetAll(
+	ctx context.Context,
+	requestingAccount *gtsmodel.Account,
+	page *paging.Pager,
+) (*apimodel.PageableResponse, gtserror.WithCode) {
+	conversations, err := p.state.DB.GetConversationsByOwnerAccountID(ctx,
+		requestingAccount.ID,
+		page,
+	)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err = gtserror.Newf("db error getting conversations: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// Check for zero length.
+	count := len(conversations)
+	if count == 0 {
+		return util.EmptyPageableResponse(), nil
+	}
+
+	var (
+		items = make([]interface{}, 0, count)
+
+		// Set next + prev values before API converting
+		// so the caller can still page even on error.
+		nextMaxIDValue = conversations[count-1].LastStatusID
+		prevMinIDValue = conversations[0].LastStatusID
+	)
+
+	for _, conversation := range conversations {
+		apiConversation, err := p.tc.ConversationToAPIConversation(ctx, conversation, requestingAccount)
+		if err != nil {
+			log.Errorf(ctx, "error converting conversation %s to api: %v", conversation.ID, err)
+			continue
+		}
+
+		items = append(items, apiConversation)
+	}
+
+	return util.PackagePageableResponse(util.PageableResponseParams{
+		Items:          items,
+		Path:           "/api/v1/conversations",
+		NextMaxIDKey:   "max_id",
+		PrevMinIDKey:   "min_id",
+		NextMaxIDValue: nextMaxIDValue,
+		PrevMinIDValue: prevMinIDValue,
+		Limit:          page.Limit,
+	})
+}
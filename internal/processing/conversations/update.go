@@ -0,0 +1,198 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package conversations
+
+import (
+	"context"
+	"errors"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// ConversationNotification carries an updated
+// conversation and the local account that owns it,
+// so that the caller can stream it to that account.
+type ConversationNotification struct {
+	Account      *gtsmodel.Account
+	Conversation *apimodel.Conversation
+}
+
+// UpdateConversationsForStatus updates the conversations of all local
+// participants in the given direct-visibility status, creating them
+// where necessary. Returns the conversations that were updated, for
+// streaming. Statuses of other visibilities are ignored.
+func (p *Processor) UpdateConversationsForStatus(ctx context.Context, status *gtsmodel.Status) ([]ConversationNotification, error) {
+	if status.Visibility != gtsmodel.VisibilityDirect {
+		// Only DMs are conversations.
+		return nil, nil
+	}
+
+	if err := p.state.DB.PopulateStatus(ctx, status); err != nil {
+		return nil, gtserror.Newf("error populating status %s: %w", status.ID, err)
+	}
+
+	// Gather the author and all mentioned accounts,
+	// these are the participants of the conversation.
+	participants := make([]*gtsmodel.Account, 0, 1+len(status.Mentions))
+	participants = append(participants, status.Account)
+	for _, mention := range status.Mentions {
+		if mention.TargetAccount == nil {
+			log.Warnf(ctx, "mention %s target account not populated", mention.ID)
+			continue
+		}
+
+		if !containsAccount(participants, mention.TargetAccountID) {
+			participants = append(participants, mention.TargetAccount)
+		}
+	}
+
+	// Conversations are grouped by the root of their thread.
+	threadID, err := p.state.DB.GetStatusThreadRootID(ctx, status)
+	if err != nil {
+		return nil, gtserror.Newf("error getting thread root of status %s: %w", status.ID, err)
+	}
+
+	notifications := make([]ConversationNotification, 0, len(participants))
+	for _, participant := range participants {
+		if !participant.IsLocal() {
+			// Only local accounts
+			// have conversations.
+			continue
+		}
+
+		notification, err := p.updateConversation(ctx, participant, participants, threadID, status)
+		if err != nil {
+			log.Errorf(ctx, "error updating conversation for account %s: %v", participant.ID, err)
+			continue
+		}
+
+		if notification != nil {
+			notifications = append(notifications, *notification)
+		}
+	}
+
+	return notifications, nil
+}
+
+// updateConversation updates the given owner's conversation in the given
+// thread between the given participants to include status, creating it
+// if necessary. Returns nil notification if nothing needs streaming.
+func (p *Processor) updateConversation(
+	ctx context.Context,
+	owner *gtsmodel.Account,
+	participants []*gtsmodel.Account,
+	threadID string,
+	status *gtsmodel.Status,
+) (*ConversationNotification, error) {
+	visible, err := p.filter.StatusVisible(ctx, owner, status)
+	if err != nil {
+		return nil, gtserror.Newf("error checking status visibility: %w", err)
+	}
+
+	if !visible {
+		return nil, nil
+	}
+
+	otherAccountIDs := make([]string, 0, len(participants)-1)
+	for _, participant := range participants {
+		if participant.ID != owner.ID {
+			otherAccountIDs = append(otherAccountIDs, participant.ID)
+		}
+	}
+
+	// Statuses authored by the owner are already read by them.
+	read := (status.AccountID == owner.ID)
+
+	conversation, err := p.state.DB.GetConversationByThreadAndAccountIDs(ctx,
+		owner.ID,
+		threadID,
+		otherAccountIDs,
+	)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return nil, gtserror.Newf("db error getting conversation: %w", err)
+	}
+
+	switch {
+	case conversation == nil:
+		// No conversation yet, create one.
+		conversation = &gtsmodel.Conversation{
+			ID:              id.NewULID(),
+			AccountID:       owner.ID,
+			Account:         owner,
+			OtherAccountIDs: otherAccountIDs,
+			ThreadID:        threadID,
+			LastStatusID:    status.ID,
+			LastStatus:      status,
+			Read:            util.Ptr(read),
+		}
+
+		if err := p.state.DB.PutConversation(ctx, conversation); err != nil {
+			return nil, gtserror.Newf("db error putting conversation: %w", err)
+		}
+
+	case status.ID > conversation.LastStatusID:
+		// Status is newer than the current last status.
+		conversation.LastStatusID = status.ID
+		conversation.LastStatus = status
+		conversation.Read = util.Ptr(read)
+
+		if err := p.state.DB.UpdateConversation(ctx, conversation, "last_status_id", "read"); err != nil {
+			return nil, gtserror.Newf("db error updating conversation: %w", err)
+		}
+
+	default:
+		// Older status (e.g. dereferenced later on), just link
+		// it so the conversation is kept in sync on deletion.
+		if err := p.state.DB.LinkConversationToStatus(ctx, conversation.ID, status.ID); err != nil {
+			return nil, gtserror.Newf("db error linking conversation to status: %w", err)
+		}
+
+		return nil, nil
+	}
+
+	if err := p.state.DB.LinkConversationToStatus(ctx, conversation.ID, status.ID); err != nil {
+		return nil, gtserror.Newf("db error linking conversation to status: %w", err)
+	}
+
+	apiConversation, err := p.tc.ConversationToAPIConversation(ctx, conversation, owner)
+	if err != nil {
+		return nil, gtserror.Newf("error converting conversation to api: %w", err)
+	}
+
+	return &ConversationNotification{
+		Account:      owner,
+		Conversation: apiConversation,
+	}, nil
+}
+
+// containsAccount returns whether the
+// given accounts contain one with given ID.
+func containsAccount(accounts []*gtsmodel.Account, id string) bool {
+	for _, account := range accounts {
+		if account.ID == id {
+			return true
+		}
+	}
+	return false
+}
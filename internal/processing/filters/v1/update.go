@@ -0,0 +1,118 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// Update an existing filter and filter keyword for the given account, using the provided parameters.
+// These params should have already been validated by the time they reach this function.
+func (p *Processor) Update(
+	ctx context.Context,
+	account *gtsmodel.Account,
+	filterKeywordID string,
+	form *apimodel.FilterCreateUpdateRequestV1,
+) (*apimodel.FilterV1, gtserror.WithCode) {
+	filterKeyword, filter, errWithCode := p.getFilterKeyword(ctx, account.ID, filterKeywordID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// Work out the filter-level fields that the form results in.
+	var (
+		action    = gtsmodel.FilterActionWarn
+		expiresAt time.Time
+		contexts  = &gtsmodel.Filter{}
+	)
+
+	if util.PtrValueOr(form.Irreversible, false) {
+		action = gtsmodel.FilterActionHide
+	}
+
+	if expiresIn := util.PtrValueOr(form.ExpiresIn, 0); expiresIn != 0 {
+		expiresAt = time.Now().Add(time.Second * time.Duration(expiresIn))
+	}
+
+	typeutils.APIFilterContextsToFilter(form.Context, contexts)
+
+	filterChanged := action != filter.Action ||
+		!expiresAt.Equal(filter.ExpiresAt) ||
+		*contexts.ContextHome != util.PtrValueOr(filter.ContextHome, false) ||
+		*contexts.ContextNotifications != util.PtrValueOr(filter.ContextNotifications, false) ||
+		*contexts.ContextPublic != util.PtrValueOr(filter.ContextPublic, false) ||
+		*contexts.ContextThread != util.PtrValueOr(filter.ContextThread, false) ||
+		*contexts.ContextAccount != util.PtrValueOr(filter.ContextAccount, false)
+
+	if filterChanged && len(filter.Keywords) > 1 {
+		// The filter-level fields would apply to all keywords,
+		// but a v1 filter can only represent a single keyword.
+		err := errors.New("can't change filter-level fields for a filter with multiple keywords through the v1 API; use the v2 API instead")
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	// Update the filter keyword itself.
+	filterKeyword.Keyword = form.Phrase
+	filterKeyword.WholeWord = util.Ptr(util.PtrValueOr(form.WholeWord, false))
+	if err := p.state.DB.UpdateFilterKeyword(ctx, filterKeyword, "keyword", "whole_word"); err != nil {
+		if errors.Is(err, db.ErrAlreadyExists) {
+			err = errors.New("duplicate filter keyword")
+			return nil, gtserror.NewErrorConflict(err, err.Error())
+		}
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if len(filter.Keywords) <= 1 {
+		// A v1 filter is titled after its only keyword.
+		filter.Title = form.Phrase
+		filter.Action = action
+		filter.ExpiresAt = expiresAt
+		filter.ContextHome = contexts.ContextHome
+		filter.ContextNotifications = contexts.ContextNotifications
+		filter.ContextPublic = contexts.ContextPublic
+		filter.ContextThread = contexts.ContextThread
+		filter.ContextAccount = contexts.ContextAccount
+
+		if err := p.state.DB.UpdateFilter(
+			ctx,
+			filter,
+			"title",
+			"action",
+			"expires_at",
+			"context_home",
+			"context_notifications",
+			"context_public",
+			"context_thread",
+			"context_account",
+		); err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+	}
+
+	filterKeyword.Filter = filter
+	return p.apiFilter(ctx, filterKeyword)
+}
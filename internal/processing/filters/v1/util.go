@@ -0,0 +1,70 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+)
+
+// getFilterKeyword is a shortcut to get one filter keyword, and
+// its fully populated parent filter, from the database and check
+// that it's owned by the given accountID. Will return
+// appropriate errors so caller doesn't need to bother.
+func (p *Processor) getFilterKeyword(ctx context.Context, accountID string, filterKeywordID string) (*gtsmodel.FilterKeyword, *gtsmodel.Filter, gtserror.WithCode) {
+	filterKeyword, err := p.state.DB.GetFilterKeywordByID(ctx, filterKeywordID)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			// Filter doesn't seem to exist.
+			return nil, nil, gtserror.NewErrorNotFound(err)
+		}
+		// Real database error.
+		return nil, nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if filterKeyword.AccountID != accountID {
+		err = fmt.Errorf("filter keyword with id %s does not belong to account %s", filterKeyword.ID, accountID)
+		return nil, nil, gtserror.NewErrorNotFound(err)
+	}
+
+	filter, err := p.state.DB.GetFilterByID(ctx, filterKeyword.FilterID)
+	if err != nil {
+		err = gtserror.Newf("db error getting filter %s: %w", filterKeyword.FilterID, err)
+		return nil, nil, gtserror.NewErrorInternalError(err)
+	}
+	filterKeyword.Filter = filter
+
+	return filterKeyword, filter, nil
+}
+
+// apiFilter is a shortcut to return the API v1 filter version of the given
+// filter keyword, or return an appropriate error if conversion fails.
+func (p *Processor) apiFilter(ctx context.Context, filterKeyword *gtsmodel.FilterKeyword) (*apimodel.FilterV1, gtserror.WithCode) {
+	apiFilter, err := p.tc.FilterKeywordToAPIFilterV1(ctx, filterKeyword)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting filter keyword to API v1 filter: %w", err))
+	}
+
+	return apiFilter, nil
+}
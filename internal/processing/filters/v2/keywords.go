@@ -0,0 +1,133 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+// KeywordCreate adds a new keyword to an existing filter for the given account.
+// These params should have already been validated by the time they reach this function.
+func (p *Processor) KeywordCreate(ctx context.Context, account *gtsmodel.Account, filterID string, form *apimodel.FilterKeywordCreateUpdateRequest) (*apimodel.FilterKeyword, gtserror.WithCode) {
+	filter, errWithCode := p.getFilter(ctx, account.ID, filterID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	filterKeyword := &gtsmodel.FilterKeyword{
+		ID:        id.NewULID(),
+		AccountID: account.ID,
+		FilterID:  filter.ID,
+		Keyword:   form.Keyword,
+		WholeWord: util.Ptr(util.PtrValueOr(form.WholeWord, false)),
+	}
+
+	if errWithCode := p.putFilterKeyword(ctx, filterKeyword); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.tc.FilterKeywordToAPIFilterKeyword(ctx, filterKeyword), nil
+}
+
+// KeywordGet looks up a filter keyword by ID.
+func (p *Processor) KeywordGet(ctx context.Context, account *gtsmodel.Account, filterKeywordID string) (*apimodel.FilterKeyword, gtserror.WithCode) {
+	filterKeyword, errWithCode := p.getFilterKeyword(ctx, account.ID, filterKeywordID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.tc.FilterKeywordToAPIFilterKeyword(ctx, filterKeyword), nil
+}
+
+// KeywordsGetForFilterID looks up all filter keywords for the given filter.
+func (p *Processor) KeywordsGetForFilterID(ctx context.Context, account *gtsmodel.Account, filterID string) ([]*apimodel.FilterKeyword, gtserror.WithCode) {
+	filter, errWithCode := p.getFilter(ctx, account.ID, filterID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	apiFilterKeywords := make([]*apimodel.FilterKeyword, 0, len(filter.Keywords))
+	for _, filterKeyword := range filter.Keywords {
+		apiFilterKeywords = append(apiFilterKeywords, p.tc.FilterKeywordToAPIFilterKeyword(ctx, filterKeyword))
+	}
+
+	return apiFilterKeywords, nil
+}
+
+// KeywordUpdate updates an existing filter keyword for the given account.
+// These params should have already been validated by the time they reach this function.
+func (p *Processor) KeywordUpdate(ctx context.Context, account *gtsmodel.Account, filterKeywordID string, form *apimodel.FilterKeywordCreateUpdateRequest) (*apimodel.FilterKeyword, gtserror.WithCode) {
+	filterKeyword, errWithCode := p.getFilterKeyword(ctx, account.ID, filterKeywordID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	filterKeyword.Keyword = form.Keyword
+	filterKeyword.WholeWord = util.Ptr(util.PtrValueOr(form.WholeWord, false))
+
+	if errWithCode := p.updateFilterKeyword(ctx, filterKeyword, "keyword", "whole_word"); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.tc.FilterKeywordToAPIFilterKeyword(ctx, filterKeyword), nil
+}
+
+// KeywordDelete deletes an existing filter keyword for the given account.
+func (p *Processor) KeywordDelete(ctx context.Context, account *gtsmodel.Account, filterKeywordID string) gtserror.WithCode {
+	filterKeyword, errWithCode := p.getFilterKeyword(ctx, account.ID, filterKeywordID)
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	if err := p.state.DB.DeleteFilterKeywordByID(ctx, filterKeyword.ID); err != nil {
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	return nil
+}
+
+// getFilterKeyword is a shortcut to get one filter keyword from the
+// database and check that it's owned by the given accountID. Will
+// return appropriate errors so caller doesn't need to bother.
+func (p *Processor) getFilterKeyword(ctx context.Context, accountID string, filterKeywordID string) (*gtsmodel.FilterKeyword, gtserror.WithCode) {
+	filterKeyword, err := p.state.DB.GetFilterKeywordByID(ctx, filterKeywordID)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			// Filter keyword doesn't seem to exist.
+			return nil, gtserror.NewErrorNotFound(err)
+		}
+		// Real database error.
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if filterKeyword.AccountID != accountID {
+		err = fmt.Errorf("filter keyword with id %s does not belong to account %s", filterKeyword.ID, accountID)
+		return nil, gtserror.NewErrorNotFound(err)
+	}
+
+	return filterKeyword, nil
+}
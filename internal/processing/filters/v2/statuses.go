@@ -0,0 +1,130 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+// StatusCreate adds a new status to an existing filter for the given account.
+// These params should have already been validated by the time they reach this function.
+func (p *Processor) StatusCreate(ctx context.Context, account *gtsmodel.Account, filterID string, form *apimodel.FilterStatusCreateRequest) (*apimodel.FilterStatus, gtserror.WithCode) {
+	filter, errWithCode := p.getFilter(ctx, account.ID, filterID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if errWithCode := p.checkStatusExists(ctx, form.StatusID); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	filterStatus := &gtsmodel.FilterStatus{
+		ID:        id.NewULID(),
+		AccountID: account.ID,
+		FilterID:  filter.ID,
+		StatusID:  form.StatusID,
+	}
+
+	if errWithCode := p.putFilterStatus(ctx, filterStatus); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.tc.FilterStatusToAPIFilterStatus(ctx, filterStatus), nil
+}
+
+// StatusGet looks up a filter status by ID.
+func (p *Processor) StatusGet(ctx context.Context, account *gtsmodel.Account, filterStatusID string) (*apimodel.FilterStatus, gtserror.WithCode) {
+	filterStatus, errWithCode := p.getFilterStatus(ctx, account.ID, filterStatusID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.tc.FilterStatusToAPIFilterStatus(ctx, filterStatus), nil
+}
+
+// StatusesGetForFilterID looks up all filter statuses for the given filter.
+func (p *Processor) StatusesGetForFilterID(ctx context.Context, account *gtsmodel.Account, filterID string) ([]*apimodel.FilterStatus, gtserror.WithCode) {
+	filter, errWithCode := p.getFilter(ctx, account.ID, filterID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	apiFilterStatuses := make([]*apimodel.FilterStatus, 0, len(filter.Statuses))
+	for _, filterStatus := range filter.Statuses {
+		apiFilterStatuses = append(apiFilterStatuses, p.tc.FilterStatusToAPIFilterStatus(ctx, filterStatus))
+	}
+
+	return apiFilterStatuses, nil
+}
+
+// StatusDelete deletes an existing filter status for the given account.
+func (p *Processor) StatusDelete(ctx context.Context, account *gtsmodel.Account, filterStatusID string) gtserror.WithCode {
+	filterStatus, errWithCode := p.getFilterStatus(ctx, account.ID, filterStatusID)
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	if err := p.state.DB.DeleteFilterStatusByID(ctx, filterStatus.ID); err != nil {
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	return nil
+}
+
+// getFilterStatus is a shortcut to get one filter status from the
+// database and check that it's owned by the given accountID. Will
+// return appropriate errors so caller doesn't need to bother.
+func (p *Processor) getFilterStatus(ctx context.Context, accountID string, filterStatusID string) (*gtsmodel.FilterStatus, gtserror.WithCode) {
+	filterStatus, err := p.state.DB.GetFilterStatusByID(ctx, filterStatusID)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			// Filter status doesn't seem to exist.
+			return nil, gtserror.NewErrorNotFound(err)
+		}
+		// Real database error.
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if filterStatus.AccountID != accountID {
+		err = fmt.Errorf("filter status with id %s does not belong to account %s", filterStatus.ID, accountID)
+		return nil, gtserror.NewErrorNotFound(err)
+	}
+
+	return filterStatus, nil
+}
+
+// checkStatusExists returns an appropriate
+// error if the given status can't be found.
+func (p *Processor) checkStatusExists(ctx context.Context, statusID string) gtserror.WithCode {
+	if _, err := p.state.DB.GetStatusByID(ctx, statusID); err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err = fmt.Errorf("status with id %s not found", statusID)
+			return gtserror.NewErrorNotFound(err, err.Error())
+		}
+		return gtserror.NewErrorInternalError(err)
+	}
+	return nil
+}
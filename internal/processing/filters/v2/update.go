@@ -0,0 +1,223 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+	"golang.org/x/exp/slices"
+)
+
+// Update an existing filter for the given account, using the provided parameters.
+// Keywords and statuses without an ID are created; those with an ID are updated,
+// or deleted if _destroy is set. These params should have already been validated
+// by the time they reach this function.
+func (p *Processor) Update(
+	ctx context.Context,
+	account *gtsmodel.Account,
+	filterID string,
+	form *apimodel.FilterUpdateRequestV2,
+) (*apimodel.FilterV2, gtserror.WithCode) {
+	filter, errWithCode := p.getFilter(ctx, account.ID, filterID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// Update filter-level fields that were provided.
+	columns := make([]string, 0, 8)
+
+	if form.Title != nil {
+		filter.Title = *form.Title
+		columns = append(columns, "title")
+	}
+
+	if form.FilterAction != nil {
+		filter.Action = typeutils.APIFilterActionToFilterAction(*form.FilterAction)
+		columns = append(columns, "action")
+	}
+
+	if form.ExpiresIn != nil {
+		filter.ExpiresAt = time.Time{}
+		if *form.ExpiresIn != 0 {
+			filter.ExpiresAt = time.Now().Add(time.Second * time.Duration(*form.ExpiresIn))
+		}
+		columns = append(columns, "expires_at")
+	}
+
+	if form.Context != nil {
+		typeutils.APIFilterContextsToFilter(*form.Context, filter)
+		columns = append(columns,
+			"context_home",
+			"context_notifications",
+			"context_public",
+			"context_thread",
+			"context_account",
+		)
+	}
+
+	if len(columns) != 0 {
+		if err := p.state.DB.UpdateFilter(ctx, filter, columns...); err != nil {
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+	}
+
+	// Create, update, or delete keywords.
+	for _, formKeyword := range form.Keywords {
+		if formKeyword.ID == nil {
+			if formKeyword.Keyword == nil {
+				err := errors.New("keyword must be provided for new filter keywords")
+				return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+			}
+
+			filterKeyword := &gtsmodel.FilterKeyword{
+				ID:        id.NewULID(),
+				AccountID: account.ID,
+				FilterID:  filter.ID,
+				Keyword:   *formKeyword.Keyword,
+				WholeWord: util.Ptr(util.PtrValueOr(formKeyword.WholeWord, false)),
+			}
+			if errWithCode := p.putFilterKeyword(ctx, filterKeyword); errWithCode != nil {
+				return nil, errWithCode
+			}
+			continue
+		}
+
+		idx := slices.IndexFunc(filter.Keywords, func(filterKeyword *gtsmodel.FilterKeyword) bool {
+			return filterKeyword.ID == *formKeyword.ID
+		})
+		if idx == -1 {
+			err := fmt.Errorf("filter keyword with id %s does not belong to filter %s", *formKeyword.ID, filter.ID)
+			return nil, gtserror.NewErrorNotFound(err)
+		}
+		filterKeyword := filter.Keywords[idx]
+
+		if util.PtrValueOr(formKeyword.Destroy, false) {
+			if err := p.state.DB.DeleteFilterKeywordByID(ctx, filterKeyword.ID); err != nil {
+				return nil, gtserror.NewErrorInternalError(err)
+			}
+			continue
+		}
+
+		keywordColumns := make([]string, 0, 2)
+		if formKeyword.Keyword != nil {
+			filterKeyword.Keyword = *formKeyword.Keyword
+			keywordColumns = append(keywordColumns, "keyword")
+		}
+		if formKeyword.WholeWord != nil {
+			filterKeyword.WholeWord = formKeyword.WholeWord
+			keywordColumns = append(keywordColumns, "whole_word")
+		}
+		if len(keywordColumns) != 0 {
+			if errWithCode := p.updateFilterKeyword(ctx, filterKeyword, keywordColumns...); errWithCode != nil {
+				return nil, errWithCode
+			}
+		}
+	}
+
+	// Create or delete statuses.
+	for _, formStatus := range form.Statuses {
+		if formStatus.ID == nil {
+			if formStatus.StatusID == nil {
+				err := errors.New("status_id must be provided for new filter statuses")
+				return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+			}
+
+			if errWithCode := p.checkStatusExists(ctx, *formStatus.StatusID); errWithCode != nil {
+				return nil, errWithCode
+			}
+
+			filterStatus := &gtsmodel.FilterStatus{
+				ID:        id.NewULID(),
+				AccountID: account.ID,
+				FilterID:  filter.ID,
+				StatusID:  *formStatus.StatusID,
+			}
+			if errWithCode := p.putFilterStatus(ctx, filterStatus); errWithCode != nil {
+				return nil, errWithCode
+			}
+			continue
+		}
+
+		if !slices.ContainsFunc(filter.Statuses, func(filterStatus *gtsmodel.FilterStatus) bool {
+			return filterStatus.ID == *formStatus.ID
+		}) {
+			err := fmt.Errorf("filter status with id %s does not belong to filter %s", *formStatus.ID, filter.ID)
+			return nil, gtserror.NewErrorNotFound(err)
+		}
+
+		// Filter statuses can't be updated, only deleted.
+		if util.PtrValueOr(formStatus.Destroy, false) {
+			if err := p.state.DB.DeleteFilterStatusByID(ctx, *formStatus.ID); err != nil {
+				return nil, gtserror.NewErrorInternalError(err)
+			}
+		}
+	}
+
+	// Reload the filter to pick up keyword and status changes.
+	return p.Get(ctx, account, filter.ID)
+}
+
+// putFilterKeyword inserts the given filter keyword,
+// returning a conflict error if it's a duplicate.
+func (p *Processor) putFilterKeyword(ctx context.Context, filterKeyword *gtsmodel.FilterKeyword) gtserror.WithCode {
+	if err := p.state.DB.PutFilterKeyword(ctx, filterKeyword); err != nil {
+		if errors.Is(err, db.ErrAlreadyExists) {
+			err = errors.New("duplicate filter keyword")
+			return gtserror.NewErrorConflict(err, err.Error())
+		}
+		return gtserror.NewErrorInternalError(err)
+	}
+	return nil
+}
+
+// updateFilterKeyword updates the given columns of the
+// given filter keyword, returning a conflict error if
+// the update would result in a duplicate.
+func (p *Processor) updateFilterKeyword(ctx context.Context, filterKeyword *gtsmodel.FilterKeyword, columns ...string) gtserror.WithCode {
+	if err := p.state.DB.UpdateFilterKeyword(ctx, filterKeyword, columns...); err != nil {
+		if errors.Is(err, db.ErrAlreadyExists) {
+			err = errors.New("duplicate filter keyword")
+			return gtserror.NewErrorConflict(err, err.Error())
+		}
+		return gtserror.NewErrorInternalError(err)
+	}
+	return nil
+}
+
+// putFilterStatus inserts the given filter status,
+// returning a conflict error if it's a duplicate.
+func (p *Processor) putFilterStatus(ctx context.Context, filterStatus *gtsmodel.FilterStatus) gtserror.WithCode {
+	if err := p.state.DB.PutFilterStatus(ctx, filterStatus); err != nil {
+		if errors.Is(err, db.ErrAlreadyExists) {
+			err = errors.New("duplicate filter status")
+			return gtserror.NewErrorConflict(err, err.Error())
+		}
+		return gtserror.NewErrorInternalError(err)
+	}
+	return nil
+}
@@ -75,28 +75,45 @@ func (p *Processor) ProcessFromClientAPI(ctx context.Context, clientMsg messages
 		case ap.ActivityBlock:
 			// CREATE BLOCK
 			return p.processCreateBlockFromClientAPI(ctx, clientMsg)
+		case ap.ActivityQuestion:
+			// CREATE POLL VOTE
+			return p.processCreatePollVoteFromClientAPI(ctx, clientMsg)
 		}
 	case ap.ActivityUpdate:
 		// UPDATE
 		switch clientMsg.APObjectType {
+		case ap.ObjectNote:
+			// UPDATE STATUS (edited)
+			return p.processUpdateStatusFromClientAPI(ctx, clientMsg)
 		case ap.ObjectProfile, ap.ActorPerson:
 			// UPDATE ACCOUNT/PROFILE
 			return p.processUpdateAccountFromClientAPI(ctx, clientMsg)
 		case ap.ActivityFlag:
 			// UPDATE A FLAG/REPORT (mark as resolved/closed)
 			return p.processUpdateReportFromClientAPI(ctx, clientMsg)
+		case ap.ActivityQuestion:
+			// UPDATE A POLL (closed)
+			return p.processUpdatePollFromClientAPI(ctx, clientMsg)
 		}
 	case ap.ActivityAccept:
 		// ACCEPT
-		if clientMsg.APObjectType == ap.ActivityFollow {
+		switch clientMsg.APObjectType {
+		case ap.ActivityFollow:
 			// ACCEPT FOLLOW
 			return p.processAcceptFollowFromClientAPI(ctx, clientMsg)
+		case ap.ObjectProfile:
+			// ACCEPT ACCOUNT/PROFILE (sign-up)
+			return p.processAcceptAccountFromClientAPI(ctx, clientMsg)
 		}
 	case ap.ActivityReject:
 		// REJECT
-		if clientMsg.APObjectType == ap.ActivityFollow {
+		switch clientMsg.APObjectType {
+		case ap.ActivityFollow:
 			// REJECT FOLLOW (request)
 			return p.processRejectFollowFromClientAPI(ctx, clientMsg)
+		case ap.ObjectProfile:
+			// REJECT ACCOUNT/PROFILE (sign-up)
+			return p.processRejectAccountFromClientAPI(ctx, clientMsg)
 		}
 	case ap.ActivityUndo:
 		// UNDO
@@ -124,6 +141,12 @@ func (p *Processor) ProcessFromClientAPI(ctx context.Context, clientMsg messages
 			// DELETE ACCOUNT/PROFILE
 			return p.processDeleteAccountFromClientAPI(ctx, clientMsg)
 		}
+	case ap.ActivityMove:
+		// MOVE
+		if clientMsg.APObjectType == ap.ObjectProfile {
+			// MOVE ACCOUNT/PROFILE
+			return p.processMoveAccountFromClientAPI(ctx, clientMsg)
+		}
 	case ap.ActivityFlag:
 		// FLAG
 		if clientMsg.APObjectType == ap.ObjectProfile {
@@ -152,7 +175,52 @@ func (p *Processor) processCreateAccountFromClientAPI(ctx context.Context, clien
 	}
 
 	// email a confirmation to this user
-	return p.User().EmailSendConfirmation(ctx, user, account.Username)
+	if err := p.User().EmailSendConfirmation(ctx, user, account.Username); err != nil {
+		return err
+	}
+
+	// let instance moderators know about the new sign-up
+	if err := p.notifySignup(ctx, account); err != nil {
+		return err
+	}
+
+	if *user.Approved {
+		// Nothing for
+		// moderators to do.
+		return nil
+	}
+
+	// email moderators so they can approve or reject the sign-up
+	return p.emailSignup(ctx, user)
+}
+
+func (p *Processor) processAcceptAccountFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
+	user, ok := clientMsg.GTSModel.(*gtsmodel.User)
+	if !ok {
+		return gtserror.New("user was not parseable as *gtsmodel.User")
+	}
+
+	// Send the newly approved user an email to let them know.
+	if err := p.emailSignupApproved(ctx, user); err != nil {
+		return gtserror.Newf("error emailing approved user: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Processor) processRejectAccountFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
+	deniedUser, ok := clientMsg.GTSModel.(*gtsmodel.DeniedUser)
+	if !ok {
+		return gtserror.New("denied user was not parseable as *gtsmodel.DeniedUser")
+	}
+
+	// Send the rejected user an email
+	// to let them know (if requested).
+	if err := p.emailSignupRejected(ctx, deniedUser); err != nil {
+		return gtserror.Newf("error emailing rejected user: %w", err)
+	}
+
+	return nil
 }
 
 func (p *Processor) processCreateStatusFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
@@ -258,6 +326,23 @@ func (p *Processor) processCreateBlockFromClientAPI(ctx context.Context, clientM
 	return p.federateBlock(ctx, block)
 }
 
+func (p *Processor) processCreatePollVoteFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
+	vote, ok := clientMsg.GTSModel.(*gtsmodel.PollVote)
+	if !ok {
+		return gtserror.New("vote was not parseable as *gtsmodel.PollVote")
+	}
+
+	// Vote counts changed on the poll's status;
+	// uncache the prepared version from all timelines.
+	p.invalidateStatusFromTimelines(ctx, vote.Poll.StatusID)
+
+	if err := p.federatePollVote(ctx, vote); err != nil {
+		return gtserror.Newf("error federating poll vote: %w", err)
+	}
+
+	return nil
+}
+
 func (p *Processor) processUpdateAccountFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
 	account, ok := clientMsg.GTSModel.(*gtsmodel.Account)
 	if !ok {
@@ -282,6 +367,54 @@ func (p *Processor) processUpdateReportFromClientAPI(ctx context.Context, client
 	return p.emailReportClosed(ctx, report)
 }
 
+func (p *Processor) processUpdateStatusFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
+	status, ok := clientMsg.GTSModel.(*gtsmodel.Status)
+	if !ok {
+		return gtserror.New("status was not parseable as *gtsmodel.Status")
+	}
+
+	// Status content changed; uncache the
+	// prepared version from all timelines.
+	p.invalidateStatusFromTimelines(ctx, status.ID)
+
+	if err := p.streamStatusUpdate(ctx, status); err != nil {
+		return gtserror.Newf("error streaming status update: %w", err)
+	}
+
+	// Notify any newly mentioned accounts.
+	if err := p.notifyStatusMentions(ctx, status); err != nil {
+		return gtserror.Newf("error notifying status mentions: %w", err)
+	}
+
+	if err := p.federateStatusUpdate(ctx, status); err != nil {
+		return gtserror.Newf("error federating status update: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Processor) processUpdatePollFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
+	status, ok := clientMsg.GTSModel.(*gtsmodel.Status)
+	if !ok {
+		return gtserror.New("status was not parseable as *gtsmodel.Status")
+	}
+
+	// The poll has closed, let its voters know the results.
+	if err := p.notifyPollClose(ctx, status); err != nil {
+		return gtserror.Newf("error notifying poll close: %w", err)
+	}
+
+	// Poll counts / closed time changed on the status;
+	// uncache the prepared version from all timelines.
+	p.invalidateStatusFromTimelines(ctx, status.ID)
+
+	if err := p.federateStatusUpdate(ctx, status); err != nil {
+		return gtserror.Newf("error federating status update: %w", err)
+	}
+
+	return nil
+}
+
 func (p *Processor) processAcceptFollowFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
 	follow, ok := clientMsg.GTSModel.(*gtsmodel.Follow)
 	if !ok {
@@ -411,6 +544,21 @@ func (p *Processor) processDeleteAccountFromClientAPI(ctx context.Context, clien
 	return p.account.Delete(ctx, clientMsg.TargetAccount, origin)
 }
 
+func (p *Processor) processMoveAccountFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
+	account, ok := clientMsg.GTSModel.(*gtsmodel.Account)
+	if !ok {
+		return errors.New("account was not parseable as *gtsmodel.Account")
+	}
+
+	if err := p.federateAccountMove(ctx, account, clientMsg.TargetAccount); err != nil {
+		return err
+	}
+
+	// Move any local followers of the
+	// account over to the target account.
+	return p.account.MoveFollowers(ctx, account, clientMsg.TargetAccount)
+}
+
 func (p *Processor) processReportAccountFromClientAPI(ctx context.Context, clientMsg messages.FromClientAPI) error {
 	report, ok := clientMsg.GTSModel.(*gtsmodel.Report)
 	if !ok {
@@ -485,6 +633,68 @@ func (p *Processor) federateAccountDelete(ctx context.Context, account *gtsmodel
 	return err
 }
 
+func (p *Processor) federateAccountMove(ctx context.Context, account *gtsmodel.Account, targetAccount *gtsmodel.Account) error {
+	// Do nothing if this isn't our activity.
+	if !account.IsLocal() {
+		return nil
+	}
+
+	outboxIRI, err := url.Parse(account.OutboxURI)
+	if err != nil {
+		return fmt.Errorf("federateAccountMove: error parsing outboxURI %s: %w", account.OutboxURI, err)
+	}
+
+	actorIRI, err := url.Parse(account.URI)
+	if err != nil {
+		return fmt.Errorf("federateAccountMove: error parsing actorIRI %s: %w", account.URI, err)
+	}
+
+	targetIRI, err := url.Parse(targetAccount.URI)
+	if err != nil {
+		return fmt.Errorf("federateAccountMove: error parsing targetIRI %s: %w", targetAccount.URI, err)
+	}
+
+	followersIRI, err := url.Parse(account.FollowersURI)
+	if err != nil {
+		return fmt.Errorf("federateAccountMove: error parsing followersIRI %s: %w", account.FollowersURI, err)
+	}
+
+	publicIRI, err := url.Parse(pub.PublicActivityPubIRI)
+	if err != nil {
+		return fmt.Errorf("federateAccountMove: error parsing url %s: %w", pub.PublicActivityPubIRI, err)
+	}
+
+	// create a move and set the moving account as actor
+	move := streams.NewActivityStreamsMove()
+
+	moveActor := streams.NewActivityStreamsActorProperty()
+	moveActor.AppendIRI(actorIRI)
+	move.SetActivityStreamsActor(moveActor)
+
+	// Set the moving account IRI as the 'object' property.
+	moveObject := streams.NewActivityStreamsObjectProperty()
+	moveObject.AppendIRI(actorIRI)
+	move.SetActivityStreamsObject(moveObject)
+
+	// Set the account being moved to as the 'target' property.
+	moveTarget := streams.NewActivityStreamsTargetProperty()
+	moveTarget.AppendIRI(targetIRI)
+	move.SetActivityStreamsTarget(moveTarget)
+
+	// send to followers...
+	moveTo := streams.NewActivityStreamsToProperty()
+	moveTo.AppendIRI(followersIRI)
+	move.SetActivityStreamsTo(moveTo)
+
+	// ... and CC to public
+	moveCC := streams.NewActivityStreamsCcProperty()
+	moveCC.AppendIRI(publicIRI)
+	move.SetActivityStreamsCc(moveCC)
+
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, move)
+	return err
+}
+
 func (p *Processor) federateStatus(ctx context.Context, status *gtsmodel.Status) error {
 	// do nothing if the status shouldn't be federated
 	if !*status.Federated {
@@ -509,7 +719,7 @@ func (p *Processor) federateStatus(ctx context.Context, status *gtsmodel.Status)
 		return fmt.Errorf("federateStatus: error converting status to as format: %s", err)
 	}
 
-	create, err := p.tc.WrapNoteInCreate(asStatus, false)
+	create, err := p.tc.WrapStatusableInCreate(asStatus, false)
 	if err != nil {
 		return fmt.Errorf("federateStatus: error wrapping status in create: %s", err)
 	}
@@ -523,6 +733,83 @@ func (p *Processor) federateStatus(ctx context.Context, status *gtsmodel.Status)
 	return err
 }
 
+func (p *Processor) federateStatusUpdate(ctx context.Context, status *gtsmodel.Status) error {
+	if status.Account == nil {
+		statusAccount, err := p.state.DB.GetAccountByID(ctx, status.AccountID)
+		if err != nil {
+			return gtserror.Newf("error fetching status author account: %w", err)
+		}
+		status.Account = statusAccount
+	}
+
+	// Do nothing if this isn't our activity.
+	if !status.Account.IsLocal() {
+		return nil
+	}
+
+	asStatus, err := p.tc.StatusToAS(ctx, status)
+	if err != nil {
+		return gtserror.Newf("error converting status to as format: %w", err)
+	}
+
+	update, err := p.tc.WrapStatusableInUpdate(asStatus, false)
+	if err != nil {
+		return gtserror.Newf("error wrapping status in update: %w", err)
+	}
+
+	outboxIRI, err := url.Parse(status.Account.OutboxURI)
+	if err != nil {
+		return gtserror.Newf("error parsing outboxURI %s: %w", status.Account.OutboxURI, err)
+	}
+
+	_, err = p.federator.FederatingActor().Send(ctx, outboxIRI, update)
+	return err
+}
+
+func (p *Processor) federatePollVote(ctx context.Context, vote *gtsmodel.PollVote) error {
+	if vote.Poll == nil {
+		poll, err := p.state.DB.GetPollByID(ctx, vote.PollID)
+		if err != nil {
+			return gtserror.Newf("error fetching vote poll: %w", err)
+		}
+		vote.Poll = poll
+	}
+
+	if vote.Poll.Status == nil {
+		status, err := p.state.DB.GetStatusByID(ctx, vote.Poll.StatusID)
+		if err != nil {
+			return gtserror.Newf("error fetching poll status: %w", err)
+		}
+		vote.Poll.Status = status
+	}
+
+	// Do nothing if this vote is in one of our
+	// own polls, nobody else needs to know yet.
+	if *vote.Poll.Status.Local {
+		return nil
+	}
+
+	creates, err := p.tc.PollVoteToASCreates(ctx, vote)
+	if err != nil {
+		return gtserror.Newf("error converting vote to as format: %w", err)
+	}
+
+	outboxIRI, err := url.Parse(vote.Account.OutboxURI)
+	if err != nil {
+		return gtserror.Newf("error parsing outboxURI %s: %w", vote.Account.OutboxURI, err)
+	}
+
+	var errs gtserror.MultiError
+
+	for _, create := range creates {
+		if _, err := p.federator.FederatingActor().Send(ctx, outboxIRI, create); err != nil {
+			errs.Appendf("error sending vote: %w", err)
+		}
+	}
+
+	return errs.Combine()
+}
+
 func (p *Processor) federateStatusDelete(ctx context.Context, status *gtsmodel.Status) error {
 	if status.Account == nil {
 		statusAccount, err := p.state.DB.GetAccountByID(ctx, status.AccountID)
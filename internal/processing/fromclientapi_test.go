@@ -267,6 +267,63 @@ func (suite *FromClientAPITestSuite) TestProcessNewStatusWithNotification() {
 	suite.Equal(newStatus.ID, notif.Status.ID)
 }
 
+// This test ensures that when local_account_2 sends a direct
+// message to local_account_1, a conversation is created for
+// both accounts, and streamed to local_account_1.
+func (suite *FromClientAPITestSuite) TestProcessNewDirectStatusConversation() {
+	var (
+		ctx              = context.Background()
+		postingAccount   = suite.testAccounts["local_account_2"]
+		receivingAccount = suite.testAccounts["local_account_1"]
+		dmStatus         = suite.testStatuses["local_account_2_status_6"]
+	)
+
+	directStream, errWithCode := suite.processor.Stream().Open(ctx, receivingAccount, stream.TimelineDirect)
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+
+	// Process the direct status.
+	if err := suite.processor.ProcessFromClientAPI(ctx, messages.FromClientAPI{
+		APObjectType:   ap.ObjectNote,
+		APActivityType: ap.ActivityCreate,
+		GTSModel:       dmStatus,
+		OriginAccount:  postingAccount,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Check message in direct stream.
+	directMsg := <-directStream.Messages
+	suite.Equal(stream.EventTypeConversation, directMsg.Event)
+	suite.EqualValues([]string{stream.TimelineDirect}, directMsg.Stream)
+	suite.Empty(directStream.Messages) // Stream should now be empty.
+
+	// Check conversation from direct stream.
+	apiConversation := &apimodel.Conversation{}
+	if err := json.Unmarshal([]byte(directMsg.Payload), apiConversation); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.True(apiConversation.Unread)
+	suite.Equal(dmStatus.ID, apiConversation.LastStatus.ID)
+	if suite.Len(apiConversation.Accounts, 1) {
+		suite.Equal(postingAccount.ID, apiConversation.Accounts[0].ID)
+	}
+
+	// The posting account should have the
+	// conversation too, already marked as read.
+	conversation, err := suite.db.GetConversationByThreadAndAccountIDs(ctx,
+		postingAccount.ID,
+		dmStatus.ID,
+		[]string{receivingAccount.ID},
+	)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal(dmStatus.ID, conversation.LastStatusID)
+	suite.True(*conversation.Read)
+}
+
 func TestFromClientAPITestSuite(t *testing.T) {
 	suite.Run(t, &FromClientAPITestSuite{})
 }
@@ -20,6 +20,7 @@ package processing
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
@@ -29,6 +30,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/id"
 	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/statusfilter"
 	"github.com/superseriousbusiness/gotosocial/internal/stream"
 	"github.com/superseriousbusiness/gotosocial/internal/timeline"
 )
@@ -73,6 +75,41 @@ func (p *Processor) timelineAndNotifyStatus(ctx context.Context, status *gtsmode
 		return gtserror.Newf("error notifying status mentions for status %s: %w", status.ID, err)
 	}
 
+	// Update + stream conversations of each local account in a DM.
+	if err := p.updateConversationsForStatus(ctx, status); err != nil {
+		return gtserror.Newf("error updating conversations for status %s: %w", status.ID, err)
+	}
+
+	// Stream the status to any open public,
+	// local or hashtag streams it belongs on.
+	if err := p.stream.PublicUpdate(ctx, status); err != nil {
+		return gtserror.Newf("error streaming status %s to public streams: %w", status.ID, err)
+	}
+
+	return nil
+}
+
+// updateConversationsForStatus updates the conversations of local
+// participants in the given direct-visibility status, and streams
+// the updated conversations to those accounts' direct timelines.
+func (p *Processor) updateConversationsForStatus(ctx context.Context, status *gtsmodel.Status) error {
+	notifications, err := p.conversations.UpdateConversationsForStatus(ctx, status)
+	if err != nil {
+		return err
+	}
+
+	errs := gtserror.NewMultiError(len(notifications))
+
+	for _, notification := range notifications {
+		if err := p.stream.Conversation(notification.Conversation, notification.Account); err != nil {
+			errs.Appendf("error streaming conversation to account %s: %w", notification.Account.ID, err)
+		}
+	}
+
+	if err := errs.Combine(); err != nil {
+		return gtserror.Newf("%w", err)
+	}
+
 	return nil
 }
 
@@ -204,8 +241,19 @@ func (p *Processor) timelineStatus(
 		return false, nil
 	}
 
+	filters, err := p.state.DB.GetFiltersForAccountID(ctx, account.ID)
+	if err != nil {
+		err = gtserror.Newf("couldn't retrieve filters for account %s: %w", account.ID, err)
+		return true, err
+	}
+
 	// The status was inserted so stream it to the user.
-	apiStatus, err := p.tc.StatusToAPIStatus(ctx, status, account)
+	apiStatus, err := p.tc.StatusToAPIStatus(ctx, status, account, statusfilter.FilterContextHome, filters)
+	if errors.Is(err, statusfilter.ErrHideStatus) {
+		// Filtered out by the
+		// user, don't stream it.
+		return true, nil
+	}
 	if err != nil {
 		err = gtserror.Newf("error converting status %s to frontend representation: %w", status.ID, err)
 		return true, err
@@ -219,10 +267,93 @@ func (p *Processor) timelineStatus(
 	return true, nil
 }
 
+// streamStatusUpdate streams the latest version of the given
+// (edited) status to the open streams of the status author,
+// and any local followers who can see it in their timelines.
+func (p *Processor) streamStatusUpdate(ctx context.Context, status *gtsmodel.Status) error {
+	// Ensure status fully populated; including account, mentions, etc.
+	if err := p.state.DB.PopulateStatus(ctx, status); err != nil {
+		return gtserror.Newf("error populating status with id %s: %w", status.ID, err)
+	}
+
+	// Get local followers of the account that posted the status.
+	follows, err := p.state.DB.GetAccountLocalFollowers(ctx, status.AccountID)
+	if err != nil {
+		return gtserror.Newf("error getting local followers for account id %s: %w", status.AccountID, err)
+	}
+
+	// Gather all accounts to stream to.
+	accounts := make([]*gtsmodel.Account, 0, len(follows)+1)
+	for _, follow := range follows {
+		accounts = append(accounts, follow.Account)
+	}
+
+	if status.Account.IsLocal() {
+		// Include the status author
+		// if they're local as well.
+		accounts = append(accounts, status.Account)
+	}
+
+	errs := gtserror.NewMultiError(len(accounts))
+
+	for _, account := range accounts {
+		// Make sure the status is visible in this account's timelines.
+		if timelineable, err := p.filter.StatusHomeTimelineable(ctx, account, status); err != nil {
+			errs.Appendf("error getting timelineability for status for account %s: %w", account.ID, err)
+			continue
+		} else if !timelineable {
+			continue
+		}
+
+		filters, err := p.state.DB.GetFiltersForAccountID(ctx, account.ID)
+		if err != nil {
+			errs.Appendf("couldn't retrieve filters for account %s: %w", account.ID, err)
+			continue
+		}
+
+		apiStatus, err := p.tc.StatusToAPIStatus(ctx, status, account, statusfilter.FilterContextHome, filters)
+		if errors.Is(err, statusfilter.ErrHideStatus) {
+			continue
+		}
+		if err != nil {
+			errs.Appendf("error converting status %s to frontend representation: %w", status.ID, err)
+			continue
+		}
+
+		if err := p.stream.StatusUpdate(apiStatus, account, stream.AllStatusTimelines); err != nil {
+			errs.Appendf("error streaming status update to account %s: %w", account.ID, err)
+		}
+	}
+
+	// Stream the edit to any open public,
+	// local or hashtag streams as well.
+	if err := p.stream.PublicStatusUpdate(ctx, status); err != nil {
+		errs.Appendf("error streaming status update to public streams: %w", err)
+	}
+
+	if err := errs.Combine(); err != nil {
+		return gtserror.Newf("%w", err)
+	}
+
+	return nil
+}
+
 func (p *Processor) notifyStatusMentions(ctx context.Context, status *gtsmodel.Status) error {
 	errs := gtserror.NewMultiError(len(status.Mentions))
 
 	for _, m := range status.Mentions {
+		// Don't notify mentioned accounts
+		// that have muted this thread.
+		muted, err := p.state.DB.IsStatusMutedBy(ctx, status, m.TargetAccountID)
+		if err != nil {
+			errs.Append(err)
+			continue
+		}
+
+		if muted {
+			continue
+		}
+
 		if err := p.notify(
 			ctx,
 			gtsmodel.NotificationMention,
@@ -317,6 +448,89 @@ func (p *Processor) notifyAnnounce(ctx context.Context, status *gtsmodel.Status)
 	)
 }
 
+// notifyPollClose notifies all local voters in the poll
+// attached to status (and the status author, if local)
+// that the poll has closed and its results are available.
+func (p *Processor) notifyPollClose(ctx context.Context, status *gtsmodel.Status) error {
+	if status.PollID == "" {
+		// No poll, nothing to do.
+		return nil
+	}
+
+	votes, err := p.state.DB.GetPollVotes(ctx, status.PollID)
+	if err != nil {
+		return gtserror.Newf("db error getting votes in poll %s: %w", status.PollID, err)
+	}
+
+	errs := gtserror.NewMultiError(len(votes) + 1)
+
+	// Notify the poll author (this
+	// is a noop if they're remote).
+	if err := p.notify(
+		ctx,
+		gtsmodel.NotificationPoll,
+		status.AccountID,
+		status.AccountID,
+		status.ID,
+	); err != nil {
+		errs.Append(err)
+	}
+
+	for _, vote := range votes {
+		// Notify each voter (again
+		// noop if they're remote).
+		if err := p.notify(
+			ctx,
+			gtsmodel.NotificationPoll,
+			vote.AccountID,
+			status.AccountID,
+			status.ID,
+		); err != nil {
+			errs.Append(err)
+		}
+	}
+
+	if err := errs.Combine(); err != nil {
+		return gtserror.Newf("%w", err)
+	}
+
+	return nil
+}
+
+// notifySignup notifies all admins + moderators
+// of this instance that the given local account
+// has just been created through a new sign-up.
+func (p *Processor) notifySignup(ctx context.Context, account *gtsmodel.Account) error {
+	moderators, err := p.state.DB.GetInstanceModerators(ctx)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			// No moderators to notify.
+			return nil
+		}
+		return gtserror.Newf("db error getting instance moderators: %w", err)
+	}
+
+	errs := gtserror.NewMultiError(len(moderators))
+
+	for _, moderator := range moderators {
+		if err := p.notify(
+			ctx,
+			gtsmodel.NotificationSignup,
+			moderator.ID,
+			account.ID,
+			"",
+		); err != nil {
+			errs.Append(err)
+		}
+	}
+
+	if err := errs.Combine(); err != nil {
+		return gtserror.Newf("%w", err)
+	}
+
+	return nil
+}
+
 func (p *Processor) notify(
 	ctx context.Context,
 	notificationType gtsmodel.NotificationType,
@@ -334,6 +548,23 @@ func (p *Processor) notify(
 		return nil
 	}
 
+	// Check whether target has muted
+	// notifications from the origin.
+	mute, err := p.state.DB.GetMute(
+		gtscontext.SetBarebones(ctx),
+		targetAccountID,
+		originAccountID,
+	)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return gtserror.Newf("error checking mute %s->%s: %w", targetAccountID, originAccountID, err)
+	}
+
+	if mute != nil && !mute.Expired(time.Now()) && *mute.Notifications {
+		// Notifications from origin
+		// muted by target, skip.
+		return nil
+	}
+
 	// Make sure a notification doesn't
 	// already exist with these params.
 	if _, err := p.state.DB.GetNotification(
@@ -364,8 +595,18 @@ func (p *Processor) notify(
 		return gtserror.Newf("error putting notification in database: %w", err)
 	}
 
+	filters, err := p.state.DB.GetFiltersForAccountID(ctx, targetAccount.ID)
+	if err != nil {
+		return gtserror.Newf("couldn't retrieve filters for account %s: %w", targetAccount.ID, err)
+	}
+
 	// Stream notification to the user.
-	apiNotif, err := p.tc.NotificationToAPINotification(ctx, notif)
+	apiNotif, err := p.tc.NotificationToAPINotification(ctx, notif, filters)
+	if errors.Is(err, statusfilter.ErrHideStatus) {
+		// Filtered out by the
+		// user, don't stream it.
+		return nil
+	}
 	if err != nil {
 		return gtserror.Newf("error converting notification to api representation: %w", err)
 	}
@@ -374,6 +615,14 @@ func (p *Processor) notify(
 		return gtserror.Newf("error streaming notification to account: %w", err)
 	}
 
+	// Push notification to the user's Web Push
+	// subscriptions, if any, in the background.
+	_ = p.state.Workers.WebPush.MustEnqueueCtx(ctx, func(ctx context.Context) {
+		if err := p.webPushSender.Send(ctx, notif, apiNotif); err != nil {
+			log.Errorf(ctx, "error sending web push notification: %v", err)
+		}
+	})
+
 	return nil
 }
 
@@ -411,6 +660,18 @@ func (p *Processor) wipeStatus(ctx context.Context, statusToDelete *gtsmodel.Sta
 		}
 	}
 
+	// delete the poll attached to this status (and all its votes)
+	if statusToDelete.PollID != "" {
+		if err := p.state.DB.DeletePollByID(ctx, statusToDelete.PollID); err != nil {
+			errs.Appendf("error deleting status poll: %w", err)
+		}
+	}
+
+	// delete the edit history of this status
+	if err := p.state.DB.DeleteStatusEdits(ctx, statusToDelete.EditIDs); err != nil {
+		errs.Appendf("error deleting status edits: %w", err)
+	}
+
 	// delete all notification entries generated by this status
 	if err := p.state.DB.DeleteNotificationsForStatus(ctx, statusToDelete.ID); err != nil {
 		errs.Appendf("error deleting status notifications: %w", err)
@@ -421,6 +682,16 @@ func (p *Processor) wipeStatus(ctx context.Context, statusToDelete *gtsmodel.Sta
 		errs.Appendf("error deleting status bookmarks: %w", err)
 	}
 
+	// delete all thread mutes that point to this status
+	if err := p.state.DB.DeleteStatusMutesForStatus(ctx, statusToDelete.ID); err != nil {
+		errs.Appendf("error deleting status mutes: %w", err)
+	}
+
+	// remove this status from any conversations it's in
+	if err := p.state.DB.DeleteStatusFromConversations(ctx, statusToDelete.ID); err != nil {
+		errs.Appendf("error deleting status from conversations: %w", err)
+	}
+
 	// delete all faves of this status
 	if err := p.state.DB.DeleteStatusFavesForStatus(ctx, statusToDelete.ID); err != nil {
 		errs.Appendf("error deleting status faves: %w", err)
@@ -525,12 +796,25 @@ func (p *Processor) emailReport(ctx context.Context, report *gtsmodel.Report) er
 		}
 	}
 
+	if len(report.RuleIDs) != 0 && report.Rules == nil {
+		report.Rules, err = p.state.DB.GetRulesByIDs(ctx, report.RuleIDs)
+		if err != nil {
+			return gtserror.Newf("error getting report rules: %w", err)
+		}
+	}
+
+	reportRules := make([]string, 0, len(report.Rules))
+	for _, rule := range report.Rules {
+		reportRules = append(reportRules, rule.Text)
+	}
+
 	reportData := email.NewReportData{
 		InstanceURL:        instance.URI,
 		InstanceName:       instance.Title,
 		ReportURL:          instance.URI + "/settings/admin/reports/" + report.ID,
 		ReportDomain:       report.Account.Domain,
 		ReportTargetDomain: report.TargetAccount.Domain,
+		ReportRules:        reportRules,
 	}
 
 	if err := p.emailSender.SendNewReportEmail(toAddresses, reportData); err != nil {
@@ -585,3 +869,97 @@ func (p *Processor) emailReportClosed(ctx context.Context, report *gtsmodel.Repo
 
 	return p.emailSender.SendReportClosedEmail(user.Email, reportClosedData)
 }
+
+func (p *Processor) emailSignup(ctx context.Context, user *gtsmodel.User) error {
+	instance, err := p.state.DB.GetInstance(ctx, config.GetHost())
+	if err != nil {
+		return gtserror.Newf("error getting instance: %w", err)
+	}
+
+	toAddresses, err := p.state.DB.GetInstanceModeratorAddresses(ctx)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			// No registered moderator addresses.
+			return nil
+		}
+		return gtserror.Newf("error getting instance moderator addresses: %w", err)
+	}
+
+	if user.Account == nil {
+		user.Account, err = p.state.DB.GetAccountByID(ctx, user.AccountID)
+		if err != nil {
+			return gtserror.Newf("error getting user account: %w", err)
+		}
+	}
+
+	newSignupData := email.NewSignupData{
+		InstanceURL:    instance.URI,
+		InstanceName:   instance.Title,
+		SignupUsername: user.Account.Username,
+		SignupEmail:    user.UnconfirmedEmail,
+		SignupReason:   user.Account.Reason,
+		SignupURL:      instance.URI + "/settings/admin/accounts/" + user.AccountID,
+	}
+
+	if err := p.emailSender.SendNewSignupEmail(toAddresses, newSignupData); err != nil {
+		return gtserror.Newf("error emailing instance moderators: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Processor) emailSignupApproved(ctx context.Context, user *gtsmodel.User) error {
+	// Email the address the user signed
+	// up with, even if it's not confirmed yet.
+	toAddress := user.Email
+	if toAddress == "" {
+		toAddress = user.UnconfirmedEmail
+	}
+
+	if toAddress == "" {
+		// Nobody to email.
+		return nil
+	}
+
+	instance, err := p.state.DB.GetInstance(ctx, config.GetHost())
+	if err != nil {
+		return gtserror.Newf("db error getting instance: %w", err)
+	}
+
+	if user.Account == nil {
+		user.Account, err = p.state.DB.GetAccountByID(ctx, user.AccountID)
+		if err != nil {
+			return gtserror.Newf("error getting user account: %w", err)
+		}
+	}
+
+	signupApprovedData := email.SignupApprovedData{
+		Username:     user.Account.Username,
+		InstanceURL:  instance.URI,
+		InstanceName: instance.Title,
+	}
+
+	return p.emailSender.SendSignupApprovedEmail(toAddress, signupApprovedData)
+}
+
+func (p *Processor) emailSignupRejected(ctx context.Context, deniedUser *gtsmodel.DeniedUser) error {
+	if !*deniedUser.SendEmail {
+		// Moderator chose not
+		// to send an email.
+		return nil
+	}
+
+	instance, err := p.state.DB.GetInstance(ctx, config.GetHost())
+	if err != nil {
+		return gtserror.Newf("db error getting instance: %w", err)
+	}
+
+	signupRejectedData := email.SignupRejectedData{
+		Username:     deniedUser.Username,
+		InstanceURL:  instance.URI,
+		InstanceName: instance.Title,
+		Message:      deniedUser.Message,
+	}
+
+	return p.emailSender.SendSignupRejectedEmail(deniedUser.Email, signupRejectedData)
+}
@@ -86,9 +86,19 @@ func (p *Processor) ProcessFromFederator(ctx context.Context, federatorMsg messa
 		}
 	case ap.ActivityUpdate:
 		// UPDATE SOMETHING
-		if federatorMsg.APObjectType == ap.ObjectProfile {
+		switch federatorMsg.APObjectType {
+		case ap.ObjectProfile:
 			// UPDATE AN ACCOUNT
 			return p.processUpdateAccountFromFederator(ctx, federatorMsg)
+		case ap.ObjectNote, ap.ActivityQuestion:
+			// UPDATE A STATUS (edit, or poll counts)
+			return p.processUpdateStatusFromFederator(ctx, federatorMsg)
+		}
+	case ap.ActivityMove:
+		// MOVE SOMETHING
+		if federatorMsg.APObjectType == ap.ObjectProfile {
+			// MOVE AN ACCOUNT
+			return p.processMoveAccountFromFederator(ctx, federatorMsg)
 		}
 	case ap.ActivityDelete:
 		// DELETE SOMETHING
@@ -448,6 +458,87 @@ func (p *Processor) processUpdateAccountFromFederator(ctx context.Context, feder
 	return nil
 }
 
+// processMoveAccountFromFederator handles Activity Move and Object Profile.
+func (p *Processor) processMoveAccountFromFederator(ctx context.Context, federatorMsg messages.FromFederator) error {
+	// Parse the moving account model.
+	account, ok := federatorMsg.GTSModel.(*gtsmodel.Account)
+	if !ok {
+		return gtserror.New("account was not parseable as *gtsmodel.Account")
+	}
+
+	// The IRI of the account being moved to should be set on the message.
+	if federatorMsg.APIri == nil {
+		return gtserror.New("no target IRI set on move account message")
+	}
+
+	if err := p.account.MoveRemote(ctx,
+		federatorMsg.ReceivingAccount.Username,
+		account,
+		federatorMsg.APIri,
+	); err != nil {
+		return gtserror.Newf("error moving account: %w", err)
+	}
+
+	return nil
+}
+
+// processUpdateStatusFromFederator handles Activity Update and Object Note / Question
+func (p *Processor) processUpdateStatusFromFederator(ctx context.Context, federatorMsg messages.FromFederator) error {
+	// Parse the old/existing status model.
+	status, ok := federatorMsg.GTSModel.(*gtsmodel.Status)
+	if !ok {
+		return gtserror.New("status was not parseable as *gtsmodel.Status")
+	}
+
+	// Because this was an Update, the new Statusable should be set on the message.
+	apStatus, ok := federatorMsg.APObjectModel.(ap.Statusable)
+	if !ok {
+		return gtserror.New("Statusable was not parseable on update status message")
+	}
+
+	// Check whether the poll was open before this update.
+	wasOpen := status.Poll != nil && status.Poll.ClosedAt.IsZero()
+
+	// Fetch up-to-date content, attachments,
+	// mentions, poll counts, closed time, etc.
+	latest, _, err := p.federator.RefreshStatus(
+		ctx,
+		federatorMsg.ReceivingAccount.Username,
+		status,
+		apStatus,
+		true, // Force refresh.
+	)
+	if err != nil {
+		return gtserror.Newf("error refreshing updated status: %w", err)
+	}
+
+	if wasOpen && latest.Poll != nil && !latest.Poll.ClosedAt.IsZero() {
+		// The poll has now closed, let local voters know.
+		if err := p.notifyPollClose(ctx, latest); err != nil {
+			return gtserror.Newf("error notifying poll close: %w", err)
+		}
+	}
+
+	// Status content / poll counts changed;
+	// uncache the prepared version from all timelines.
+	p.invalidateStatusFromTimelines(ctx, latest.ID)
+
+	if len(latest.EditIDs) > len(status.EditIDs) {
+		// The status was edited, stream
+		// the latest version to local users.
+		if err := p.streamStatusUpdate(ctx, latest); err != nil {
+			return gtserror.Newf("error streaming status update: %w", err)
+		}
+
+		// Notify any newly mentioned accounts.
+		if err := p.notifyStatusMentions(ctx, latest); err != nil {
+			return gtserror.Newf("error notifying status mentions: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // processDeleteStatusFromFederator handles Activity Delete and Object Note
 func (p *Processor) processDeleteStatusFromFederator(ctx context.Context, federatorMsg messages.FromFederator) error {
 	status, ok := federatorMsg.GTSModel.(*gtsmodel.Status)
@@ -67,6 +67,13 @@ func (p *Processor) InstanceGetV2(ctx context.Context) (*apimodel.InstanceV2, gt
 		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error converting instance to api representation: %s", err))
 	}
 
+	// Clients need the VAPID public key
+	// before they can create push subscriptions.
+	ai.Configuration.VAPID.PublicKey, err = p.webPushSender.VAPIDPublicKey(ctx)
+	if err != nil {
+		return nil, gtserror.NewErrorInternalError(fmt.Errorf("error getting vapid public key: %s", err))
+	}
+
 	return ai, nil
 }
 
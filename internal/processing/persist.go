@@ -0,0 +1,488 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package processing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+
+	"github.com/superseriousbusiness/activity/streams"
+	"github.com/superseriousbusiness/activity/streams/vocab"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+)
+
+// workerMessage is the serialized form of a FromClientAPI or
+// FromFederator message, as persisted in the database. Rather
+// than the models themselves, GTS models are referenced by
+// type + ID and refetched from the database on replay.
+//
+// The exception is models of delete, undo and reject messages,
+// which are removed from the database before (or while) being
+// processed, so can't be refetched. A snapshot of these models'
+// own fields is stored instead, see gtsModelSnapshot.
+type workerMessage struct {
+	APObjectType       string          `json:"ap_object_type,omitempty"`
+	APActivityType     string          `json:"ap_activity_type,omitempty"`
+	APIri              string          `json:"ap_iri,omitempty"`
+	APObjectModel      json.RawMessage `json:"ap_object_model,omitempty"`
+	GTSModelType       string          `json:"gts_model_type,omitempty"`
+	GTSModelID         string          `json:"gts_model_id,omitempty"`
+	GTSModelSnapshot   json.RawMessage `json:"gts_model_snapshot,omitempty"`
+	OriginAccountID    string          `json:"origin_account_id,omitempty"`
+	TargetAccountID    string          `json:"target_account_id,omitempty"`
+	ReceivingAccountID string          `json:"receiving_account_id,omitempty"`
+}
+
+// ReplayWorkerMessages enqueues any persisted worker messages
+// that weren't processed before the instance last stopped. It
+// should be called once on startup, after the workers have been
+// started and the processor's enqueue functions set on them.
+func (p *Processor) ReplayWorkerMessages(ctx context.Context) {
+	clientMsgs, err := p.state.DB.GetWorkerMessages(ctx, gtsmodel.WorkerTypeClientAPI)
+	if err != nil {
+		log.Errorf(ctx, "error getting client API worker messages: %v", err)
+	}
+
+	for _, workerMsg := range clientMsgs {
+		msg, err := p.deserializeClientAPI(ctx, workerMsg.Data)
+		if err != nil {
+			log.Warnf(ctx, "dropping client API worker message %s: %v", workerMsg.ID, err)
+			p.processed(ctx, workerMsg.ID)
+			continue
+		}
+
+		p.enqueueClientAPI(ctx, []string{workerMsg.ID}, []messages.FromClientAPI{msg})
+	}
+
+	federatorMsgs, err := p.state.DB.GetWorkerMessages(ctx, gtsmodel.WorkerTypeFederator)
+	if err != nil {
+		log.Errorf(ctx, "error getting federator worker messages: %v", err)
+	}
+
+	for _, workerMsg := range federatorMsgs {
+		msg, err := p.deserializeFederator(ctx, workerMsg.Data)
+		if err != nil {
+			log.Warnf(ctx, "dropping federator worker message %s: %v", workerMsg.ID, err)
+			p.processed(ctx, workerMsg.ID)
+			continue
+		}
+
+		p.enqueueFederator(ctx, []string{workerMsg.ID}, []messages.FromFederator{msg})
+	}
+
+	if n := len(clientMsgs) + len(federatorMsgs); n > 0 {
+		log.Infof(ctx, "replayed %d persisted worker messages", n)
+	}
+}
+
+// persistClientAPI persists the given messages if configured to do
+// so, returning the ID of the worker message each was persisted as,
+// or an empty string for those that weren't.
+func (p *Processor) persistClientAPI(ctx context.Context, msgs []messages.FromClientAPI) []string {
+	ids := make([]string, len(msgs))
+	if !config.GetAdvancedPersistWorkerMessages() {
+		return ids
+	}
+
+	for i, msg := range msgs {
+		data, err := serializeClientAPI(msg)
+		if err != nil {
+			log.Warnf(ctx, "not persisting client API message: %v", err)
+			continue
+		}
+
+		ids[i] = p.persist(ctx, gtsmodel.WorkerTypeClientAPI, data)
+	}
+
+	return ids
+}
+
+// persistFederator persists the given messages if configured to do
+// so, returning the ID of the worker message each was persisted as,
+// or an empty string for those that weren't.
+func (p *Processor) persistFederator(ctx context.Context, msgs []messages.FromFederator) []string {
+	ids := make([]string, len(msgs))
+	if !config.GetAdvancedPersistWorkerMessages() {
+		return ids
+	}
+
+	for i, msg := range msgs {
+		data, err := serializeFederator(msg)
+		if err != nil {
+			log.Warnf(ctx, "not persisting federator message: %v", err)
+			continue
+		}
+
+		ids[i] = p.persist(ctx, gtsmodel.WorkerTypeFederator, data)
+	}
+
+	return ids
+}
+
+// persist stores the given serialized message
+// for the given worker, returning its ID, or an
+// empty string if it couldn't be stored.
+func (p *Processor) persist(ctx context.Context, worker gtsmodel.WorkerType, data []byte) string {
+	workerMsg := &gtsmodel.WorkerMessage{
+		ID:     id.NewULID(),
+		Worker: worker,
+		Data:   data,
+	}
+
+	if err := p.state.DB.PutWorkerMessage(ctx, workerMsg); err != nil {
+		log.Errorf(ctx, "error persisting %s worker message: %v", worker, err)
+		return ""
+	}
+
+	return workerMsg.ID
+}
+
+// processed removes the persisted worker message with the given
+// ID (if any), now that it's been processed. If ctx is done, the
+// worker was stopped while processing it, so the message is kept
+// to be replayed on next startup.
+func (p *Processor) processed(ctx context.Context, id string) {
+	if id == "" || ctx.Err() != nil {
+		return
+	}
+
+	if err := p.state.DB.DeleteWorkerMessageByID(ctx, id); err != nil {
+		log.Errorf(ctx, "error deleting worker message %s: %v", id, err)
+	}
+}
+
+func serializeClientAPI(msg messages.FromClientAPI) ([]byte, error) {
+	modelType, modelID, err := gtsModelRef(msg.GTSModel)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := gtsModelSnapshot(msg.APActivityType, msg.GTSModel)
+	if err != nil {
+		return nil, err
+	}
+
+	workerMsg := workerMessage{
+		APObjectType:     msg.APObjectType,
+		APActivityType:   msg.APActivityType,
+		GTSModelType:     modelType,
+		GTSModelID:       modelID,
+		GTSModelSnapshot: snapshot,
+	}
+
+	if msg.OriginAccount != nil {
+		workerMsg.OriginAccountID = msg.OriginAccount.ID
+	}
+
+	if msg.TargetAccount != nil {
+		workerMsg.TargetAccountID = msg.TargetAccount.ID
+	}
+
+	return json.Marshal(workerMsg)
+}
+
+func serializeFederator(msg messages.FromFederator) ([]byte, error) {
+	modelType, modelID, err := gtsModelRef(msg.GTSModel)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := gtsModelSnapshot(msg.APActivityType, msg.GTSModel)
+	if err != nil {
+		return nil, err
+	}
+
+	workerMsg := workerMessage{
+		APObjectType:     msg.APObjectType,
+		APActivityType:   msg.APActivityType,
+		GTSModelType:     modelType,
+		GTSModelID:       modelID,
+		GTSModelSnapshot: snapshot,
+	}
+
+	if msg.APIri != nil {
+		workerMsg.APIri = msg.APIri.String()
+	}
+
+	if msg.APObjectModel != nil {
+		t, ok := msg.APObjectModel.(vocab.Type)
+		if !ok {
+			return nil, gtserror.Newf("unsupported AP object model type %T", msg.APObjectModel)
+		}
+
+		m, err := ap.Serialize(t)
+		if err != nil {
+			return nil, gtserror.Newf("error serializing AP object model: %w", err)
+		}
+
+		workerMsg.APObjectModel, err = json.Marshal(m)
+		if err != nil {
+			return nil, gtserror.Newf("error marshaling AP object model: %w", err)
+		}
+	}
+
+	if msg.ReceivingAccount != nil {
+		workerMsg.ReceivingAccountID = msg.ReceivingAccount.ID
+	}
+
+	return json.Marshal(workerMsg)
+}
+
+func (p *Processor) deserializeClientAPI(ctx context.Context, data []byte) (messages.FromClientAPI, error) {
+	var (
+		workerMsg workerMessage
+		msg       messages.FromClientAPI
+		err       error
+	)
+
+	if err := json.Unmarshal(data, &workerMsg); err != nil {
+		return msg, gtserror.Newf("error unmarshaling message: %w", err)
+	}
+
+	msg.APObjectType = workerMsg.APObjectType
+	msg.APActivityType = workerMsg.APActivityType
+
+	msg.GTSModel, err = p.gtsModel(ctx, workerMsg.GTSModelType, workerMsg.GTSModelID, workerMsg.GTSModelSnapshot)
+	if err != nil {
+		return msg, err
+	}
+
+	if workerMsg.OriginAccountID != "" {
+		msg.OriginAccount, err = p.state.DB.GetAccountByID(ctx, workerMsg.OriginAccountID)
+		if err != nil {
+			return msg, gtserror.Newf("error getting origin account %s: %w", workerMsg.OriginAccountID, err)
+		}
+	}
+
+	if workerMsg.TargetAccountID != "" {
+		msg.TargetAccount, err = p.state.DB.GetAccountByID(ctx, workerMsg.TargetAccountID)
+		if err != nil {
+			return msg, gtserror.Newf("error getting target account %s: %w", workerMsg.TargetAccountID, err)
+		}
+	}
+
+	return msg, nil
+}
+
+func (p *Processor) deserializeFederator(ctx context.Context, data []byte) (messages.FromFederator, error) {
+	var (
+		workerMsg workerMessage
+		msg       messages.FromFederator
+		err       error
+	)
+
+	if err := json.Unmarshal(data, &workerMsg); err != nil {
+		return msg, gtserror.Newf("error unmarshaling message: %w", err)
+	}
+
+	msg.APObjectType = workerMsg.APObjectType
+	msg.APActivityType = workerMsg.APActivityType
+
+	if workerMsg.APIri != "" {
+		msg.APIri, err = url.Parse(workerMsg.APIri)
+		if err != nil {
+			return msg, gtserror.Newf("error parsing AP iri: %w", err)
+		}
+	}
+
+	if len(workerMsg.APObjectModel) > 0 {
+		m := make(map[string]interface{})
+		if err := json.Unmarshal(workerMsg.APObjectModel, &m); err != nil {
+			return msg, gtserror.Newf("error unmarshaling AP object model: %w", err)
+		}
+
+		msg.APObjectModel, err = streams.ToType(ctx, m)
+		if err != nil {
+			return msg, gtserror.Newf("error resolving AP object model: %w", err)
+		}
+	}
+
+	msg.GTSModel, err = p.gtsModel(ctx, workerMsg.GTSModelType, workerMsg.GTSModelID, workerMsg.GTSModelSnapshot)
+	if err != nil {
+		return msg, err
+	}
+
+	if workerMsg.ReceivingAccountID != "" {
+		msg.ReceivingAccount, err = p.state.DB.GetAccountByID(ctx, workerMsg.ReceivingAccountID)
+		if err != nil {
+			return msg, gtserror.Newf("error getting receiving account %s: %w", workerMsg.ReceivingAccountID, err)
+		}
+	}
+
+	return msg, nil
+}
+
+// gtsModelRef returns the type name and ID that
+// the given GTS model can be refetched by with gtsModel.
+func gtsModelRef(model interface{}) (string, string, error) {
+	switch model := model.(type) {
+	case nil:
+		return "", "", nil
+	case *gtsmodel.Account:
+		return "Account", model.ID, nil
+	case *gtsmodel.Block:
+		return "Block", model.ID, nil
+	case *gtsmodel.DeniedUser:
+		return "DeniedUser", model.ID, nil
+	case *gtsmodel.DomainBlock:
+		return "DomainBlock", model.ID, nil
+	case *gtsmodel.Follow:
+		return "Follow", model.ID, nil
+	case *gtsmodel.FollowRequest:
+		return "FollowRequest", model.ID, nil
+	case *gtsmodel.PollVote:
+		return "PollVote", model.ID, nil
+	case *gtsmodel.Report:
+		return "Report", model.ID, nil
+	case *gtsmodel.Status:
+		return "Status", model.ID, nil
+	case *gtsmodel.StatusFave:
+		return "StatusFave", model.ID, nil
+	case *gtsmodel.User:
+		return "User", model.ID, nil
+	default:
+		return "", "", gtserror.Newf("unsupported GTS model type %T", model)
+	}
+}
+
+// gtsModelSnapshot returns the serialized fields of the given
+// GTS model, without any populated relations, if it's the model
+// of a delete, undo or reject message of the given activity type.
+// Such models are removed from the database before (or while)
+// the message is processed, so can't be refetched on replay.
+func gtsModelSnapshot(activityType string, model interface{}) (json.RawMessage, error) {
+	switch activityType {
+	case ap.ActivityDelete, ap.ActivityUndo, ap.ActivityReject:
+	default:
+		return nil, nil
+	}
+
+	var snapshot interface{}
+	switch model := model.(type) {
+	case *gtsmodel.Block:
+		m := *model
+		m.Account, m.TargetAccount = nil, nil
+		snapshot = &m
+	case *gtsmodel.Follow:
+		m := *model
+		m.Account, m.TargetAccount = nil, nil
+		snapshot = &m
+	case *gtsmodel.FollowRequest:
+		m := *model
+		m.Account, m.TargetAccount = nil, nil
+		snapshot = &m
+	case *gtsmodel.Status:
+		m := *model
+		m.Attachments, m.Tags, m.Mentions, m.Emojis = nil, nil, nil, nil
+		m.Account, m.InReplyTo, m.InReplyToAccount = nil, nil, nil
+		m.BoostOf, m.BoostOfAccount = nil, nil
+		m.CreatedWithApplication, m.Poll, m.Edits = nil, nil, nil
+		snapshot = &m
+	case *gtsmodel.StatusFave:
+		m := *model
+		m.Account, m.TargetAccount, m.Status = nil, nil, nil
+		snapshot = &m
+	default:
+		// Other models, eg. an account
+		// or domain block, are kept.
+		return nil, nil
+	}
+
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, gtserror.Newf("error marshaling GTS model snapshot: %w", err)
+	}
+
+	return b, nil
+}
+
+// gtsModel refetches the GTS model of the given type name
+// and ID, as returned by gtsModelRef, or where a snapshot of
+// the model was stored (see gtsModelSnapshot), restores it.
+func (p *Processor) gtsModel(ctx context.Context, modelType string, modelID string, snapshot json.RawMessage) (interface{}, error) {
+	var (
+		model interface{}
+		err   error
+	)
+
+	if len(snapshot) > 0 {
+		switch modelType {
+		case "Block":
+			model = new(gtsmodel.Block)
+		case "Follow":
+			model = new(gtsmodel.Follow)
+		case "FollowRequest":
+			model = new(gtsmodel.FollowRequest)
+		case "Status":
+			model = new(gtsmodel.Status)
+		case "StatusFave":
+			model = new(gtsmodel.StatusFave)
+		default:
+			return nil, gtserror.Newf("unexpected snapshot of %s %s", modelType, modelID)
+		}
+
+		if err := json.Unmarshal(snapshot, model); err != nil {
+			return nil, gtserror.Newf("error unmarshaling snapshot of %s %s: %w", modelType, modelID, err)
+		}
+
+		return model, nil
+	}
+
+	switch modelType {
+	case "":
+		return nil, nil
+	case "Account":
+		model, err = p.state.DB.GetAccountByID(ctx, modelID)
+	case "Block":
+		model, err = p.state.DB.GetBlockByID(ctx, modelID)
+	case "DeniedUser":
+		model, err = p.state.DB.GetDeniedUserByID(ctx, modelID)
+	case "DomainBlock":
+		model, err = p.state.DB.GetDomainBlockByID(ctx, modelID)
+	case "Follow":
+		model, err = p.state.DB.GetFollowByID(ctx, modelID)
+	case "FollowRequest":
+		model, err = p.state.DB.GetFollowRequestByID(ctx, modelID)
+	case "PollVote":
+		model, err = p.state.DB.GetPollVoteByID(ctx, modelID)
+	case "Report":
+		model, err = p.state.DB.GetReportByID(ctx, modelID)
+	case "Status":
+		model, err = p.state.DB.GetStatusByID(ctx, modelID)
+	case "StatusFave":
+		model, err = p.state.DB.GetStatusFaveByID(ctx, modelID)
+	case "User":
+		model, err = p.state.DB.GetUserByID(ctx, modelID)
+	default:
+		err = errors.New("unknown model type")
+	}
+
+	if err != nil {
+		return nil, gtserror.Newf("error getting %s %s: %w", modelType, modelID, err)
+	}
+
+	return model, nil
+}
@@ -0,0 +1,210 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package processing_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type PersistTestSuite struct {
+	ProcessingStandardTestSuite
+}
+
+func (suite *PersistTestSuite) faveNotified(ctx context.Context, fave *gtsmodel.StatusFave) bool {
+	_, err := suite.db.GetNotification(ctx, gtsmodel.NotificationFave, fave.TargetAccountID, fave.AccountID, fave.StatusID)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		suite.FailNow(err.Error())
+	}
+	return err == nil
+}
+
+func (suite *PersistTestSuite) workerMessages(ctx context.Context) []*gtsmodel.WorkerMessage {
+	msgs, err := suite.db.GetWorkerMessages(ctx, gtsmodel.WorkerTypeClientAPI)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	return msgs
+}
+
+func (suite *PersistTestSuite) putWorkerMessage(ctx context.Context, data map[string]interface{}) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	if err := suite.db.PutWorkerMessage(ctx, &gtsmodel.WorkerMessage{
+		ID:     id.NewULID(),
+		Worker: gtsmodel.WorkerTypeClientAPI,
+		Data:   b,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+}
+
+func (suite *PersistTestSuite) TestEnqueuePersisted() {
+	var (
+		ctx  = context.Background()
+		fave = testrig.NewTestFaves()["local_account_1_admin_account_status_1"]
+	)
+
+	config.SetAdvancedPersistWorkerMessages(true)
+	suite.False(suite.faveNotified(ctx, fave))
+
+	suite.processor.EnqueueClientAPI(ctx, messages.FromClientAPI{
+		APObjectType:   ap.ActivityLike,
+		APActivityType: ap.ActivityCreate,
+		GTSModel:       fave,
+		OriginAccount:  suite.testAccounts["local_account_1"],
+		TargetAccount:  suite.testAccounts["admin_account"],
+	})
+
+	// Message should be processed,
+	// then removed from the database.
+	if !testrig.WaitFor(func() bool {
+		return suite.faveNotified(ctx, fave) && len(suite.workerMessages(ctx)) == 0
+	}) {
+		suite.FailNow("timed out waiting for persisted message to be processed")
+	}
+}
+
+func (suite *PersistTestSuite) TestReplay() {
+	var (
+		ctx  = context.Background()
+		fave = testrig.NewTestFaves()["local_account_1_admin_account_status_1"]
+	)
+
+	suite.False(suite.faveNotified(ctx, fave))
+
+	// Message left over from a previous run.
+	suite.putWorkerMessage(ctx, map[string]interface{}{
+		"ap_object_type":    ap.ActivityLike,
+		"ap_activity_type":  ap.ActivityCreate,
+		"gts_model_type":    "StatusFave",
+		"gts_model_id":      fave.ID,
+		"origin_account_id": fave.AccountID,
+		"target_account_id": fave.TargetAccountID,
+	})
+
+	suite.processor.ReplayWorkerMessages(ctx)
+
+	if !testrig.WaitFor(func() bool {
+		return suite.faveNotified(ctx, fave) && len(suite.workerMessages(ctx)) == 0
+	}) {
+		suite.FailNow("timed out waiting for replayed message to be processed")
+	}
+}
+
+func (suite *PersistTestSuite) TestReplayModelGone() {
+	ctx := context.Background()
+
+	// Message referencing a fave
+	// that's since been deleted.
+	suite.putWorkerMessage(ctx, map[string]interface{}{
+		"ap_object_type":    ap.ActivityLike,
+		"ap_activity_type":  ap.ActivityCreate,
+		"gts_model_type":    "StatusFave",
+		"gts_model_id":      "01H8ZB6Q4H4K9S8ERJ5E4V1X2N",
+		"origin_account_id": suite.testAccounts["local_account_1"].ID,
+	})
+
+	suite.processor.ReplayWorkerMessages(ctx)
+
+	// Message should just be dropped.
+	suite.Empty(suite.workerMessages(ctx))
+}
+
+func (suite *PersistTestSuite) TestReplayUndoModelGone() {
+	var (
+		ctx           = context.Background()
+		block         = suite.testBlocks["local_account_2_block_remote_account_1"]
+		targetAccount = suite.testAccounts["remote_account_1"]
+	)
+
+	// Block is deleted before the
+	// undo message is processed.
+	if err := suite.db.DeleteBlockByID(ctx, block.ID); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Message left over from a previous run,
+	// with a snapshot of the deleted block.
+	suite.putWorkerMessage(ctx, map[string]interface{}{
+		"ap_object_type":     ap.ActivityBlock,
+		"ap_activity_type":   ap.ActivityUndo,
+		"gts_model_type":     "Block",
+		"gts_model_id":       block.ID,
+		"gts_model_snapshot": block,
+		"origin_account_id":  block.AccountID,
+		"target_account_id":  block.TargetAccountID,
+	})
+
+	suite.processor.ReplayWorkerMessages(ctx)
+
+	// Undo should still be sent to the blocked account.
+	var sent [][]byte
+	if !testrig.WaitFor(func() bool {
+		sentI, ok := suite.httpClient.SentMessages.Load(*targetAccount.SharedInboxURI)
+		if ok {
+			sent, ok = sentI.([][]byte)
+			if !ok {
+				panic("SentMessages entry was not [][]byte")
+			}
+			return true
+		}
+		return false
+	}) {
+		suite.FailNow("timed out waiting for message")
+	}
+
+	undo := &struct {
+		Type   string `json:"type"`
+		Object struct {
+			ID   string `json:"id"`
+			Type string `json:"type"`
+		}
+	}{}
+	if err := json.Unmarshal(sent[0], undo); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal(ap.ActivityUndo, undo.Type)
+	suite.Equal(ap.ActivityBlock, undo.Object.Type)
+	suite.Equal(block.URI, undo.Object.ID)
+
+	if !testrig.WaitFor(func() bool {
+		return len(suite.workerMessages(ctx)) == 0
+	}) {
+		suite.FailNow("timed out waiting for replayed message to be removed")
+	}
+}
+
+func TestPersistTestSuite(t *testing.T) {
+	suite.Run(t, &PersistTestSuite{})
+}
@@ -0,0 +1,146 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package polls
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"github.com/superseriousbusiness/gotosocial/internal/visibility"
+)
+
+type Processor struct {
+	state  *state.State
+	tc     typeutils.TypeConverter
+	filter *visibility.Filter
+}
+
+func New(state *state.State, tc typeutils.TypeConverter, filter *visibility.Filter) Processor {
+	return Processor{
+		state:  state,
+		tc:     tc,
+		filter: filter,
+	}
+}
+
+// getTargetPoll fetches a target poll ID for requesting account, taking visibility of the poll's originating status into account.
+func (p *Processor) getTargetPoll(ctx context.Context, requestingAccount *gtsmodel.Account, targetID string) (*gtsmodel.Poll, gtserror.WithCode) {
+	// Load the poll with given ID from database.
+	poll, err := p.state.DB.GetPollByID(ctx, targetID)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("error getting poll %s from database: %w", targetID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if poll == nil {
+		err := gtserror.Newf("poll %s not found", targetID)
+		return nil, gtserror.NewErrorNotFound(err)
+	}
+
+	// Load the poll's originating status from database.
+	status, err := p.state.DB.GetStatusByID(ctx, poll.StatusID)
+	if err != nil {
+		err := gtserror.Newf("error getting status %s for poll %s: %w", poll.StatusID, poll.ID, err)
+		return nil, gtserror.NewErrorNotFound(err)
+	}
+
+	// Check whether the requesting account can see poll status.
+	visible, err := p.filter.StatusVisible(ctx, requestingAccount, status)
+	if err != nil {
+		err := gtserror.Newf("error checking status visibility: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if !visible {
+		err := gtserror.Newf("poll %s not visible to requester", targetID)
+		return nil, gtserror.NewErrorNotFound(err)
+	}
+
+	// Attach status to poll.
+	poll.Status = status
+
+	return poll, nil
+}
+
+// apiPoll is a shortcut function to convert a poll to its api model representation, wrapping any errors.
+func (p *Processor) apiPoll(ctx context.Context, requestingAccount *gtsmodel.Account, poll *gtsmodel.Poll) (*apimodel.Poll, gtserror.WithCode) {
+	apiPoll, err := p.tc.PollToAPIPoll(ctx, requestingAccount, poll)
+	if err != nil {
+		err := gtserror.Newf("error converting poll %s to frontend representation: %w", poll.ID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	return apiPoll, nil
+}
+
+// PollGet gets the poll with given ID, taking visibility of the poll's originating status into account.
+func (p *Processor) PollGet(ctx context.Context, requestingAccount *gtsmodel.Account, pollID string) (*apimodel.Poll, gtserror.WithCode) {
+	poll, errWithCode := p.getTargetPoll(ctx, requestingAccount, pollID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.apiPoll(ctx, requestingAccount, poll)
+}
+
+// CloseExpired closes all polls which have passed their expiry
+// time, passing each to the client API worker queue to handle
+// the side effects (notifying voters, federating the results).
+func (p *Processor) CloseExpired(ctx context.Context) {
+	polls, err := p.state.DB.GetExpiredPolls(ctx)
+	if err != nil {
+		log.Errorf(ctx, "error getting expired polls: %v", err)
+		return
+	}
+
+	now := time.Now()
+
+	for _, poll := range polls {
+		// Mark the poll as closed.
+		poll.ClosedAt = now
+
+		if err := p.state.DB.UpdatePoll(ctx, poll, "closed_at"); err != nil {
+			log.Errorf(ctx, "error closing poll %s: %v", poll.ID, err)
+			continue
+		}
+
+		// Fetch the (fully populated) status this poll is attached to.
+		status, err := p.state.DB.GetStatusByID(ctx, poll.StatusID)
+		if err != nil {
+			log.Errorf(ctx, "error getting status for poll %s: %v", poll.ID, err)
+			continue
+		}
+
+		// Enqueue the closed poll for side effects.
+		p.state.Workers.EnqueueClientAPI(ctx, messages.FromClientAPI{
+			APObjectType:   ap.ActivityQuestion,
+			APActivityType: ap.ActivityUpdate,
+			GTSModel:       status,
+			OriginAccount:  status.Account,
+		})
+	}
+}
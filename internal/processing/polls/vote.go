@@ -0,0 +1,120 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package polls
+
+import (
+	"context"
+	"errors"
+
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+)
+
+// PollVote casts the given choices as votes in the poll with given ID
+// on behalf of the requesting account, returning the updated poll.
+func (p *Processor) PollVote(ctx context.Context, requestingAccount *gtsmodel.Account, pollID string, choices []int) (*apimodel.Poll, gtserror.WithCode) {
+	// Get (+ check visibility of) requested poll with ID.
+	poll, errWithCode := p.getTargetPoll(ctx, requestingAccount, pollID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	switch {
+	// Poll author isn't allowed to vote in their own poll.
+	case requestingAccount.ID == poll.Status.AccountID:
+		const text = "you can't vote in your own poll"
+		return nil, gtserror.NewErrorUnprocessableEntity(errors.New(text), text)
+
+	// Poll has already closed, no more voting!
+	case poll.Closed() || poll.Expired():
+		const text = "poll already closed"
+		return nil, gtserror.NewErrorUnprocessableEntity(errors.New(text), text)
+
+	// No choices given, or multiple given for single-choice poll.
+	case len(choices) == 0 || (!*poll.Multiple && len(choices) > 1):
+		const text = "invalid number of choices for poll"
+		return nil, gtserror.NewErrorBadRequest(errors.New(text), text)
+	}
+
+	// Check each choice is in range and only given once.
+	seen := make(map[int]struct{}, len(choices))
+	for _, choice := range choices {
+		if choice < 0 || choice >= len(poll.Options) {
+			const text = "invalid option index for poll"
+			return nil, gtserror.NewErrorBadRequest(errors.New(text), text)
+		}
+
+		if _, ok := seen[choice]; ok {
+			const text = "duplicate option index for poll"
+			return nil, gtserror.NewErrorBadRequest(errors.New(text), text)
+		}
+
+		seen[choice] = struct{}{}
+	}
+
+	// Check whether requester has already voted in this poll.
+	existing, err := p.state.DB.GetPollVoteBy(ctx, poll.ID, requestingAccount.ID)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("error getting existing vote in poll %s: %w", poll.ID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if existing != nil {
+		const text = "you have already voted in this poll"
+		return nil, gtserror.NewErrorUnprocessableEntity(errors.New(text), text)
+	}
+
+	// Wrap the vote in a model.
+	vote := &gtsmodel.PollVote{
+		ID:        id.NewULID(),
+		Choices:   choices,
+		AccountID: requestingAccount.ID,
+		Account:   requestingAccount,
+		PollID:    poll.ID,
+		Poll:      poll,
+	}
+
+	// Insert the new poll vote into the database, this updates poll counts.
+	if err := p.state.DB.PutPollVote(ctx, vote); err != nil {
+		err := gtserror.Newf("error inserting vote in poll %s: %w", poll.ID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// Reload the poll with updated vote counts.
+	updated, err := p.state.DB.GetPollByID(ctx, poll.ID)
+	if err != nil {
+		err := gtserror.Newf("error getting updated poll %s: %w", poll.ID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	updated.Status = poll.Status
+
+	// Enqueue the vote for side effects (e.g. federation).
+	p.state.Workers.EnqueueClientAPI(ctx, messages.FromClientAPI{
+		APObjectType:   ap.ActivityQuestion,
+		APActivityType: ap.ActivityCreate,
+		GTSModel:       vote,
+		OriginAccount:  requestingAccount,
+	})
+
+	return p.apiPoll(ctx, requestingAccount, updated)
+}
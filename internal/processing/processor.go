@@ -28,10 +28,15 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 	"github.com/superseriousbusiness/gotosocial/internal/processing/account"
 	"github.com/superseriousbusiness/gotosocial/internal/processing/admin"
+	"github.com/superseriousbusiness/gotosocial/internal/processing/conversations"
 	"github.com/superseriousbusiness/gotosocial/internal/processing/fedi"
+	filtersv1 "github.com/superseriousbusiness/gotosocial/internal/processing/filters/v1"
+	filtersv2 "github.com/superseriousbusiness/gotosocial/internal/processing/filters/v2"
 	"github.com/superseriousbusiness/gotosocial/internal/processing/list"
 	"github.com/superseriousbusiness/gotosocial/internal/processing/markers"
 	"github.com/superseriousbusiness/gotosocial/internal/processing/media"
+	"github.com/superseriousbusiness/gotosocial/internal/processing/polls"
+	"github.com/superseriousbusiness/gotosocial/internal/processing/push"
 	"github.com/superseriousbusiness/gotosocial/internal/processing/report"
 	"github.com/superseriousbusiness/gotosocial/internal/processing/search"
 	"github.com/superseriousbusiness/gotosocial/internal/processing/status"
@@ -41,33 +46,40 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/state"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 	"github.com/superseriousbusiness/gotosocial/internal/visibility"
+	"github.com/superseriousbusiness/gotosocial/internal/webpush"
 )
 
 type Processor struct {
-	federator    federation.Federator
-	tc           typeutils.TypeConverter
-	oauthServer  oauth.Server
-	mediaManager *mm.Manager
-	state        *state.State
-	emailSender  email.Sender
-	filter       *visibility.Filter
+	federator     federation.Federator
+	tc            typeutils.TypeConverter
+	oauthServer   oauth.Server
+	mediaManager  *mm.Manager
+	state         *state.State
+	emailSender   email.Sender
+	webPushSender webpush.Sender
+	filter        *visibility.Filter
 
 	/*
 		SUB-PROCESSORS
 	*/
 
-	account  account.Processor
-	admin    admin.Processor
-	fedi     fedi.Processor
-	list     list.Processor
-	markers  markers.Processor
-	media    media.Processor
-	report   report.Processor
-	search   search.Processor
-	status   status.Processor
-	stream   stream.Processor
-	timeline timeline.Processor
-	user     user.Processor
+	account       account.Processor
+	admin         admin.Processor
+	conversations conversations.Processor
+	fedi          fedi.Processor
+	filtersv1     filtersv1.Processor
+	filtersv2     filtersv2.Processor
+	list          list.Processor
+	markers       markers.Processor
+	media         media.Processor
+	polls         polls.Processor
+	push          push.Processor
+	report        report.Processor
+	search        search.Processor
+	status        status.Processor
+	stream        stream.Processor
+	timeline      timeline.Processor
+	user          user.Processor
 }
 
 func (p *Processor) Account() *account.Processor {
@@ -78,10 +90,22 @@ func (p *Processor) Admin() *admin.Processor {
 	return &p.admin
 }
 
+func (p *Processor) Conversations() *conversations.Processor {
+	return &p.conversations
+}
+
 func (p *Processor) Fedi() *fedi.Processor {
 	return &p.fedi
 }
 
+func (p *Processor) FiltersV1() *filtersv1.Processor {
+	return &p.filtersv1
+}
+
+func (p *Processor) FiltersV2() *filtersv2.Processor {
+	return &p.filtersv2
+}
+
 func (p *Processor) List() *list.Processor {
 	return &p.list
 }
@@ -94,6 +118,14 @@ func (p *Processor) Media() *media.Processor {
 	return &p.media
 }
 
+func (p *Processor) Polls() *polls.Processor {
+	return &p.polls
+}
+
+func (p *Processor) Push() *push.Processor {
+	return &p.push
+}
+
 func (p *Processor) Report() *report.Processor {
 	return &p.report
 }
@@ -126,58 +158,83 @@ func NewProcessor(
 	mediaManager *mm.Manager,
 	state *state.State,
 	emailSender email.Sender,
+	webPushSender webpush.Sender,
 ) *Processor {
 	parseMentionFunc := GetParseMentionFunc(state.DB, federator)
 
 	filter := visibility.NewFilter(state)
 
 	processor := &Processor{
-		federator:    federator,
-		tc:           tc,
-		oauthServer:  oauthServer,
-		mediaManager: mediaManager,
-		state:        state,
-		filter:       filter,
-		emailSender:  emailSender,
+		federator:     federator,
+		tc:            tc,
+		oauthServer:   oauthServer,
+		mediaManager:  mediaManager,
+		state:         state,
+		filter:        filter,
+		emailSender:   emailSender,
+		webPushSender: webPushSender,
 	}
 
 	// Instantiate sub processors.
 	processor.account = account.New(state, tc, mediaManager, oauthServer, federator, filter, parseMentionFunc)
 	processor.admin = admin.New(state, tc, mediaManager, federator.TransportController(), emailSender)
+	processor.conversations = conversations.New(state, tc, filter)
 	processor.fedi = fedi.New(state, tc, federator, filter)
+	processor.filtersv1 = filtersv1.New(state, tc)
+	processor.filtersv2 = filtersv2.New(state, tc)
 	processor.list = list.New(state, tc)
 	processor.markers = markers.New(state, tc)
 	processor.media = media.New(state, tc, mediaManager, federator.TransportController())
+	processor.polls = polls.New(state, tc, filter)
+	processor.push = push.New(state, tc, webPushSender)
 	processor.report = report.New(state, tc)
 	processor.timeline = timeline.New(state, tc, filter)
 	processor.search = search.New(state, federator, tc, filter)
 	processor.status = status.New(state, federator, tc, filter, parseMentionFunc)
-	processor.stream = stream.New(state, oauthServer)
-	processor.user = user.New(state, emailSender)
+	processor.stream = stream.New(state, tc, filter, oauthServer)
+	processor.user = user.New(state, tc, emailSender)
 
 	return processor
 }
 
 func (p *Processor) EnqueueClientAPI(ctx context.Context, msgs ...messages.FromClientAPI) {
 	log.Trace(ctx, "enqueuing")
+	ids := p.persistClientAPI(ctx, msgs)
+	p.enqueueClientAPI(ctx, ids, msgs)
+}
+
+func (p *Processor) EnqueueFederator(ctx context.Context, msgs ...messages.FromFederator) {
+	log.Trace(ctx, "enqueuing")
+	ids := p.persistFederator(ctx, msgs)
+	p.enqueueFederator(ctx, ids, msgs)
+}
+
+// enqueueClientAPI enqueues the given messages for processing
+// by the client API worker pool. ids holds the ID of the worker
+// message each was persisted as (if any), to remove once processed.
+func (p *Processor) enqueueClientAPI(ctx context.Context, ids []string, msgs []messages.FromClientAPI) {
 	_ = p.state.Workers.ClientAPI.MustEnqueueCtx(ctx, func(ctx context.Context) {
-		for _, msg := range msgs {
+		for i, msg := range msgs {
 			log.Trace(ctx, "processing: %+v", msg)
 			if err := p.ProcessFromClientAPI(ctx, msg); err != nil {
 				log.Errorf(ctx, "error processing client API message: %v", err)
 			}
+			p.processed(ctx, ids[i])
 		}
 	})
 }
 
-func (p *Processor) EnqueueFederator(ctx context.Context, msgs ...messages.FromFederator) {
-	log.Trace(ctx, "enqueuing")
+// enqueueFederator enqueues the given messages for processing
+// by the federator worker pool. ids holds the ID of the worker
+// message each was persisted as (if any), to remove once processed.
+func (p *Processor) enqueueFederator(ctx context.Context, ids []string, msgs []messages.FromFederator) {
 	_ = p.state.Workers.Federator.MustEnqueueCtx(ctx, func(ctx context.Context) {
-		for _, msg := range msgs {
+		for i, msg := range msgs {
 			log.Trace(ctx, "processing: %+v", msg)
 			if err := p.ProcessFromFederator(ctx, msg); err != nil {
 				log.Errorf(ctx, "error processing federator message: %v", err)
 			}
+			p.processed(ctx, ids[i])
 		}
 	})
 }
@@ -0,0 +1,150 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package push
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+	"github.com/superseriousbusiness/gotosocial/internal/webpush"
+)
+
+// Create creates a web push subscription for the given access token,
+// replacing any subscription that the token already had.
+func (p *Processor) Create(
+	ctx context.Context,
+	account *gtsmodel.Account,
+	accessToken string,
+	form *apimodel.WebPushSubscriptionCreateRequest,
+) (*apimodel.WebPushSubscription, gtserror.WithCode) {
+	endpoint, err := url.Parse(form.Subscription.Endpoint)
+	if err != nil || !endpoint.IsAbs() || (endpoint.Scheme != "https" && endpoint.Scheme != "http") {
+		err := gtserror.New("subscription endpoint must be an absolute http or https URL")
+		return nil, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	keys := form.Subscription.Keys
+	if err := webpush.ValidateKeys(keys.P256dh, keys.Auth); err != nil {
+		return nil, gtserror.NewErrorBadRequest(err, "invalid subscription keys: "+err.Error())
+	}
+
+	tokenID, errWithCode := p.getTokenID(ctx, accessToken)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	subscription := &gtsmodel.WebPushSubscription{
+		ID:                 id.NewULID(),
+		AccountID:          account.ID,
+		TokenID:            tokenID,
+		Endpoint:           endpoint.String(),
+		Auth:               keys.Auth,
+		P256dh:             keys.P256dh,
+		AlertFollow:        util.Ptr(false),
+		AlertFollowRequest: util.Ptr(false),
+		AlertFavourite:     util.Ptr(false),
+		AlertMention:       util.Ptr(false),
+		AlertReblog:        util.Ptr(false),
+		AlertPoll:          util.Ptr(false),
+		AlertStatus:        util.Ptr(false),
+		AlertAdminSignup:   util.Ptr(false),
+		Policy:             gtsmodel.WebPushNotificationPolicyAll,
+	}
+
+	if errWithCode := applyData(subscription, &form.Data); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// Make sure the instance has a VAPID
+	// key pair for the client to use.
+	if _, err := p.webPushSender.VAPIDPublicKey(ctx); err != nil {
+		err = gtserror.Newf("error getting vapid public key: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// Each token can only have one
+	// subscription, so replace any
+	// existing one with the new one.
+	if err := p.state.DB.DeleteWebPushSubscriptionByTokenID(ctx, tokenID); err != nil {
+		err = gtserror.Newf("db error deleting existing web push subscription: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if err := p.state.DB.PutWebPushSubscription(ctx, subscription); err != nil {
+		err = gtserror.Newf("db error putting web push subscription: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return p.apiSubscription(ctx, subscription)
+}
+
+// applyData applies the alerts and
+// policy set in the given request
+// data to the given subscription.
+func applyData(subscription *gtsmodel.WebPushSubscription, data *apimodel.WebPushSubscriptionRequestData) gtserror.WithCode {
+	alerts := data.Alerts
+	for _, a := range []struct {
+		form  *bool
+		alert **bool
+	}{
+		{alerts.Follow, &subscription.AlertFollow},
+		{alerts.FollowRequest, &subscription.AlertFollowRequest},
+		{alerts.Favourite, &subscription.AlertFavourite},
+		{alerts.Mention, &subscription.AlertMention},
+		{alerts.Reblog, &subscription.AlertReblog},
+		{alerts.Poll, &subscription.AlertPoll},
+		{alerts.Status, &subscription.AlertStatus},
+		{alerts.AdminSignup, &subscription.AlertAdminSignup},
+	} {
+		if a.form != nil {
+			*a.alert = util.Ptr(*a.form)
+		}
+	}
+
+	if data.Policy != nil {
+		policy := gtsmodel.WebPushNotificationPolicy(*data.Policy)
+		switch policy {
+		case gtsmodel.WebPushNotificationPolicyAll,
+			gtsmodel.WebPushNotificationPolicyFollowed,
+			gtsmodel.WebPushNotificationPolicyFollower,
+			gtsmodel.WebPushNotificationPolicyNone:
+			subscription.Policy = policy
+		default:
+			err := fmt.Errorf("policy %q not recognized, must be one of all, followed, follower, none", *data.Policy)
+			return gtserror.NewErrorBadRequest(err, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// apiSubscription converts the given subscription to its API model.
+func (p *Processor) apiSubscription(ctx context.Context, subscription *gtsmodel.WebPushSubscription) (*apimodel.WebPushSubscription, gtserror.WithCode) {
+	apiSubscription, err := p.tc.WebPushSubscriptionToAPIWebPushSubscription(ctx, subscription)
+	if err != nil {
+		err = gtserror.Newf("error converting web push subscription to api: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+	return apiSubscription, nil
+}
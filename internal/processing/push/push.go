@@ -0,0 +1,80 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package push
+
+import (
+	"context"
+	"errors"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"github.com/superseriousbusiness/gotosocial/internal/webpush"
+)
+
+type Processor struct {
+	state         *state.State
+	tc            typeutils.TypeConverter
+	webPushSender webpush.Sender
+}
+
+func New(state *state.State, tc typeutils.TypeConverter, webPushSender webpush.Sender) Processor {
+	return Processor{
+		state:         state,
+		tc:            tc,
+		webPushSender: webPushSender,
+	}
+}
+
+// getTokenID returns the database ID of the oauth
+// token with the given access token string.
+func (p *Processor) getTokenID(ctx context.Context, accessToken string) (string, gtserror.WithCode) {
+	token := &gtsmodel.Token{}
+	if err := p.state.DB.GetWhere(ctx, []db.Where{{Key: "access", Value: accessToken}}, token); err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err = gtserror.New("no token found for access token")
+			return "", gtserror.NewErrorUnauthorized(err, err.Error())
+		}
+		err = gtserror.Newf("db error getting token: %w", err)
+		return "", gtserror.NewErrorInternalError(err)
+	}
+	return token.ID, nil
+}
+
+// getSubscription returns the web push
+// subscription of the given access token.
+func (p *Processor) getSubscription(ctx context.Context, accessToken string) (*gtsmodel.WebPushSubscription, gtserror.WithCode) {
+	tokenID, errWithCode := p.getTokenID(ctx, accessToken)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	subscription, err := p.state.DB.GetWebPushSubscriptionByTokenID(ctx, tokenID)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err = gtserror.New("no web push subscription exists for this token")
+			return nil, gtserror.NewErrorNotFound(err, err.Error())
+		}
+		err = gtserror.Newf("db error getting web push subscription: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return subscription, nil
+}
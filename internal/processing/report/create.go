@@ -64,6 +64,25 @@ func (p *Processor) Create(ctx context.Context, account *gtsmodel.Account, form
 		}
 	}
 
+	// fetch rules by IDs given in the report form (noop if no rules given)
+	rules, err := p.state.DB.GetRulesByIDs(ctx, form.RuleIDs)
+	if err != nil {
+		err = fmt.Errorf("db error fetching report rules: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	activeRules := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		activeRules[r.ID] = !*r.Deleted
+	}
+
+	for _, ruleID := range form.RuleIDs {
+		if !activeRules[ruleID] {
+			err = fmt.Errorf("rule with ID %s does not exist", ruleID)
+			return nil, gtserror.NewErrorBadRequest(err, err.Error())
+		}
+	}
+
 	reportID := id.NewULID()
 	report := &gtsmodel.Report{
 		ID:              reportID,
@@ -75,6 +94,8 @@ func (p *Processor) Create(ctx context.Context, account *gtsmodel.Account, form
 		Comment:         form.Comment,
 		StatusIDs:       form.StatusIDs,
 		Statuses:        statuses,
+		RuleIDs:         form.RuleIDs,
+		Rules:           rules,
 		Forwarded:       &form.Forward,
 	}
 
@@ -26,10 +26,11 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/statusfilter"
 )
 
 func (p *Processor) apiStatus(ctx context.Context, targetStatus *gtsmodel.Status, requestingAccount *gtsmodel.Account) (*apimodel.Status, gtserror.WithCode) {
-	apiStatus, err := p.tc.StatusToAPIStatus(ctx, targetStatus, requestingAccount)
+	apiStatus, err := p.tc.StatusToAPIStatus(ctx, targetStatus, requestingAccount, statusfilter.FilterContextNone, nil)
 	if err != nil {
 		err = gtserror.Newf("error converting status %s to frontend representation: %w", targetStatus.ID, err)
 		return nil, gtserror.NewErrorInternalError(err)
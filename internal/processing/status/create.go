@@ -38,6 +38,12 @@ import (
 
 // Create processes the given form to create a new status, returning the api model representation of that status if it's OK.
 func (p *Processor) Create(ctx context.Context, account *gtsmodel.Account, application *gtsmodel.Application, form *apimodel.AdvancedStatusCreateForm) (*apimodel.Status, gtserror.WithCode) {
+	return p.create(ctx, account, application, form, "")
+}
+
+// create is Create, additionally allowing media held for
+// the given scheduled status (if any) to be attached.
+func (p *Processor) create(ctx context.Context, account *gtsmodel.Account, application *gtsmodel.Application, form *apimodel.AdvancedStatusCreateForm, scheduledStatusID string) (*apimodel.Status, gtserror.WithCode) {
 	accountURIs := uris.GenerateURIsForAccount(account.Username)
 	thisStatusID := id.NewULID()
 	local := true
@@ -64,7 +70,7 @@ func (p *Processor) Create(ctx context.Context, account *gtsmodel.Account, appli
 		return nil, errWithCode
 	}
 
-	if errWithCode := processMediaIDs(ctx, p.state.DB, form, account.ID, newStatus); errWithCode != nil {
+	if errWithCode := processMediaIDs(ctx, p.state.DB, form, account.ID, scheduledStatusID, newStatus); errWithCode != nil {
 		return nil, errWithCode
 	}
 
@@ -80,6 +86,27 @@ func (p *Processor) Create(ctx context.Context, account *gtsmodel.Account, appli
 		return nil, gtserror.NewErrorInternalError(err)
 	}
 
+	if form.Poll != nil {
+		// Process poll, inserting into database.
+		poll, errWithCode := p.processPoll(ctx,
+			newStatus.ID,
+			form.Poll,
+			newStatus.CreatedAt,
+		)
+		if errWithCode != nil {
+			return nil, errWithCode
+		}
+
+		// Set poll and its ID
+		// on status before insert.
+		newStatus.PollID = poll.ID
+		newStatus.Poll = poll
+		poll.Status = newStatus
+
+		// Statuses with polls are represented as Questions.
+		newStatus.ActivityStreamsType = ap.ActivityQuestion
+	}
+
 	// put the new status in the database
 	if err := p.state.DB.PutStatus(ctx, newStatus); err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
@@ -96,6 +123,46 @@ func (p *Processor) Create(ctx context.Context, account *gtsmodel.Account, appli
 	return p.apiStatus(ctx, newStatus, account)
 }
 
+func (p *Processor) processPoll(ctx context.Context, statusID string, form *apimodel.PollRequest, now time.Time) (*gtsmodel.Poll, gtserror.WithCode) {
+	var expiresAt time.Time
+
+	// Set an expiry time if one given.
+	if in := form.ExpiresIn; in > 0 {
+		expiresIn := time.Duration(in)
+		expiresAt = now.Add(expiresIn * time.Second)
+	}
+
+	// Create new poll for status.
+	poll := &gtsmodel.Poll{
+		ID:         id.NewULID(),
+		Multiple:   &form.Multiple,
+		HideCounts: &form.HideTotals,
+		Options:    sanitizePollOptions(form.Options),
+		StatusID:   statusID,
+		ExpiresAt:  expiresAt,
+	}
+
+	// Set zero vote counts.
+	poll.ResetVotes()
+
+	// Insert the newly created poll model in the database.
+	if err := p.state.DB.PutPoll(ctx, poll); err != nil {
+		err := gtserror.Newf("error inserting poll in db: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return poll, nil
+}
+
+// sanitizePollOptions returns a sanitized copy of the given poll options.
+func sanitizePollOptions(options []string) []string {
+	sanitized := make([]string, len(options))
+	for i, option := range options {
+		sanitized[i] = text.SanitizePlaintext(option)
+	}
+	return sanitized
+}
+
 func processReplyToID(ctx context.Context, dbService db.DB, form *apimodel.AdvancedStatusCreateForm, thisAccountID string, status *gtsmodel.Status) gtserror.WithCode {
 	if form.InReplyToID == "" {
 		return nil
@@ -148,7 +215,7 @@ func processReplyToID(ctx context.Context, dbService db.DB, form *apimodel.Advan
 	return nil
 }
 
-func processMediaIDs(ctx context.Context, dbService db.DB, form *apimodel.AdvancedStatusCreateForm, thisAccountID string, status *gtsmodel.Status) gtserror.WithCode {
+func processMediaIDs(ctx context.Context, dbService db.DB, form *apimodel.AdvancedStatusCreateForm, thisAccountID string, scheduledStatusID string, status *gtsmodel.Status) gtserror.WithCode {
 	if form.MediaIDs == nil {
 		return nil
 	}
@@ -171,7 +238,8 @@ func processMediaIDs(ctx context.Context, dbService db.DB, form *apimodel.Advanc
 			return gtserror.NewErrorBadRequest(err, err.Error())
 		}
 
-		if attachment.StatusID != "" || attachment.ScheduledStatusID != "" {
+		if (attachment.StatusID != "" && attachment.StatusID != status.ID) ||
+			(attachment.ScheduledStatusID != "" && attachment.ScheduledStatusID != scheduledStatusID) {
 			err = fmt.Errorf("ProcessMediaIDs: media with id %s is already attached to a status", mediaID)
 			return gtserror.NewErrorBadRequest(err, err.Error())
 		}
@@ -0,0 +1,252 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package status
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/messages"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+	"golang.org/x/exp/slices"
+)
+
+// Edit processes the given form to edit an existing status owned by the requesting
+// account, storing the previous version in the status' edit history, and returning
+// the api model representation of the up-to-date status if it's OK.
+func (p *Processor) Edit(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string, form *apimodel.StatusEditRequest) (*apimodel.Status, gtserror.WithCode) {
+	status, errWithCode := p.getOwnStatus(ctx, requestingAccount, targetStatusID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if status.BoostOfID != "" {
+		err := gtserror.Newf("status %s is a boost", status.ID)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, "boosts cannot be edited")
+	}
+
+	// Take a snapshot of the current version
+	// of the status, to store in its history.
+	edit := &gtsmodel.StatusEdit{
+		ID:             id.NewULID(),
+		Content:        status.Content,
+		ContentWarning: status.ContentWarning,
+		Text:           status.Text,
+		Language:       status.Language,
+		Sensitive:      status.Sensitive,
+		AttachmentIDs:  status.AttachmentIDs,
+		Attachments:    status.Attachments,
+		StatusID:       status.ID,
+		CreatedAt:      status.EditedAt,
+	}
+
+	if edit.CreatedAt.IsZero() {
+		// Status never edited before,
+		// so this version is the original.
+		edit.CreatedAt = status.CreatedAt
+	}
+
+	if status.Poll != nil {
+		// Include poll options + votes at time of edit.
+		edit.PollOptions = status.Poll.Options
+		edit.PollVotes = status.Poll.Votes
+	}
+
+	// Wrap the edit form in a create form, so that
+	// we can reuse the status creation processing.
+	createForm := &apimodel.AdvancedStatusCreateForm{
+		StatusCreateRequest: apimodel.StatusCreateRequest{
+			Status:      form.Status,
+			MediaIDs:    form.MediaIDs,
+			Poll:        form.Poll,
+			Sensitive:   form.Sensitive,
+			SpoilerText: form.SpoilerText,
+			Language:    form.Language,
+			ContentType: form.ContentType,
+		},
+	}
+
+	// Reset the editable fields of the
+	// status before processing the form.
+	sensitive := form.Sensitive
+	status.ContentWarning = text.SanitizePlaintext(form.SpoilerText)
+	status.Sensitive = &sensitive
+	status.Language = ""
+	status.Text = form.Status
+	status.Attachments = nil
+	status.AttachmentIDs = nil
+	status.Mentions = nil
+	status.MentionIDs = nil
+	status.Tags = nil
+	status.TagIDs = nil
+	status.Emojis = nil
+	status.EmojiIDs = nil
+
+	if errWithCode := processMediaIDs(ctx, p.state.DB, createForm, requestingAccount.ID, "", status); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if err := processLanguage(ctx, createForm, requestingAccount.Language, status); err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if err := processContent(ctx, p.state.DB, p.formatter, p.parseMention, createForm, requestingAccount.ID, status); err != nil {
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	now := time.Now()
+
+	oldPollID, errWithCode := p.processEditPoll(ctx, status, form.Poll, now)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	// Insert the historical version of the status.
+	if err := p.state.DB.PutStatusEdit(ctx, edit); err != nil {
+		err := gtserror.Newf("error inserting status edit in db: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// Append the edit to the status history.
+	status.EditIDs = append(slices.Clone(status.EditIDs), edit.ID)
+	status.Edits = append(slices.Clone(status.Edits), edit)
+	status.EditedAt = now
+
+	// Update the latest version of the status in the database.
+	if err := p.state.DB.UpdateStatus(ctx, status); err != nil {
+		err := gtserror.Newf("error updating status in db: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if oldPollID != "" {
+		// Only now the status no longer refers to it,
+		// delete the previous poll (and all its votes).
+		if err := p.state.DB.DeletePollByID(ctx, oldPollID); err != nil {
+			log.Errorf(ctx, "error deleting poll %s from db: %v", oldPollID, err)
+		}
+	}
+
+	// send it back to the processor for async processing
+	p.state.Workers.EnqueueClientAPI(ctx, messages.FromClientAPI{
+		APObjectType:   ap.ObjectNote,
+		APActivityType: ap.ActivityUpdate,
+		GTSModel:       status,
+		OriginAccount:  requestingAccount,
+	})
+
+	return p.apiStatus(ctx, status, requestingAccount)
+}
+
+// processEditPoll updates the poll attached to the given status according
+// to the edit form: removing it, replacing it (and so resetting all votes)
+// if the options have changed, or otherwise leaving the existing poll in place.
+// The ID of a removed or replaced poll is returned, for the caller to delete
+// once the updated status has been stored.
+func (p *Processor) processEditPoll(ctx context.Context, status *gtsmodel.Status, form *apimodel.PollRequest, now time.Time) (string, gtserror.WithCode) {
+	if form != nil && status.Poll != nil &&
+		*status.Poll.Multiple == form.Multiple &&
+		slices.Equal(status.Poll.Options, sanitizePollOptions(form.Options)) {
+		// Poll is unchanged, keep the existing one (+ votes).
+		return "", nil
+	}
+
+	// Detach the previous poll (if any).
+	oldPollID := status.PollID
+	status.PollID = ""
+	status.Poll = nil
+	status.ActivityStreamsType = ap.ObjectNote
+
+	if form == nil {
+		// No new poll.
+		return oldPollID, nil
+	}
+
+	// Process the new poll, inserting into database.
+	poll, errWithCode := p.processPoll(ctx, status.ID, form, now)
+	if errWithCode != nil {
+		return "", errWithCode
+	}
+
+	// Set poll and its ID on status.
+	status.PollID = poll.ID
+	status.Poll = poll
+	poll.Status = status
+
+	// Statuses with polls are represented as Questions.
+	status.ActivityStreamsType = ap.ActivityQuestion
+
+	return oldPollID, nil
+}
+
+// HistoryGet gets the edit history of the given status, oldest first, ending with the current version.
+func (p *Processor) HistoryGet(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string) ([]*apimodel.StatusEdit, gtserror.WithCode) {
+	targetStatus, errWithCode := p.getVisibleStatus(ctx, requestingAccount, targetStatusID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	apiEdits, err := p.tc.StatusToAPIEdits(ctx, targetStatus)
+	if err != nil {
+		err = gtserror.Newf("error converting status %s edits to frontend representation: %w", targetStatus.ID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return apiEdits, nil
+}
+
+// SourceGet gets the plaintext source of the given status, for use when editing it.
+func (p *Processor) SourceGet(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string) (*apimodel.StatusSource, gtserror.WithCode) {
+	targetStatus, errWithCode := p.getOwnStatus(ctx, requestingAccount, targetStatusID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return &apimodel.StatusSource{
+		ID:          targetStatus.ID,
+		Text:        targetStatus.Text,
+		SpoilerText: targetStatus.ContentWarning,
+	}, nil
+}
+
+// getOwnStatus fetches the status with given ID, ensuring it belongs to the requesting account.
+func (p *Processor) getOwnStatus(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string) (*gtsmodel.Status, gtserror.WithCode) {
+	targetStatus, err := p.state.DB.GetStatusByID(ctx, targetStatusID)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) {
+			err = gtserror.Newf("status %s not found", targetStatusID)
+			return nil, gtserror.NewErrorNotFound(err)
+		}
+		err = gtserror.Newf("db error fetching status %s: %w", targetStatusID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if targetStatus.AccountID != requestingAccount.ID {
+		err = gtserror.Newf("status %s doesn't belong to requesting account", targetStatusID)
+		return nil, gtserror.NewErrorForbidden(err)
+	}
+
+	return targetStatus, nil
+}
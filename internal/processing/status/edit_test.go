@@ -0,0 +1,134 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package status_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+)
+
+type StatusEditTestSuite struct {
+	StatusStandardTestSuite
+}
+
+func (suite *StatusEditTestSuite) TestEditStatus() {
+	ctx := context.Background()
+
+	editingAccount := suite.testAccounts["local_account_1"]
+	targetStatus := suite.testStatuses["local_account_1_status_1"]
+
+	form := &apimodel.StatusEditRequest{
+		Status:      "hello world, this status has been edited",
+		SpoilerText: "edited",
+		Language:    "en",
+		ContentType: apimodel.StatusContentTypePlain,
+	}
+
+	apiStatus, errWithCode := suite.status.Edit(ctx, editingAccount, targetStatus.ID, form)
+	suite.NoError(errWithCode)
+	suite.NotNil(apiStatus)
+	suite.Equal("<p>hello world, this status has been edited</p>", apiStatus.Content)
+	suite.Equal("edited", apiStatus.SpoilerText)
+	suite.NotNil(apiStatus.EditedAt)
+
+	// The previous version should now be in the history.
+	dbStatus, err := suite.db.GetStatusByID(ctx, targetStatus.ID)
+	suite.NoError(err)
+	suite.Len(dbStatus.EditIDs, 1)
+	suite.Len(dbStatus.Edits, 1)
+	suite.Equal(targetStatus.Content, dbStatus.Edits[0].Content)
+	suite.Equal(targetStatus.ContentWarning, dbStatus.Edits[0].ContentWarning)
+
+	// History should contain the original and the current version.
+	history, errWithCode := suite.status.HistoryGet(ctx, editingAccount, targetStatus.ID)
+	suite.NoError(errWithCode)
+	suite.Len(history, 2)
+	suite.Equal(targetStatus.Content, history[0].Content)
+	suite.Equal(apiStatus.Content, history[1].Content)
+
+	// Source should reflect the edited text.
+	source, errWithCode := suite.status.SourceGet(ctx, editingAccount, targetStatus.ID)
+	suite.NoError(errWithCode)
+	suite.Equal(form.Status, source.Text)
+	suite.Equal(form.SpoilerText, source.SpoilerText)
+}
+
+func (suite *StatusEditTestSuite) TestEditRemovePoll() {
+	ctx := context.Background()
+
+	editingAccount := suite.testAccounts["local_account_1"]
+	apiStatus, errWithCode := suite.status.Create(ctx, editingAccount, suite.testApplications["application_1"], &apimodel.AdvancedStatusCreateForm{
+		StatusCreateRequest: apimodel.StatusCreateRequest{
+			Status: "which is best?",
+			Poll: &apimodel.PollRequest{
+				Options:   []string{"this one", "that one"},
+				ExpiresIn: 3600,
+			},
+			Visibility:  apimodel.VisibilityPublic,
+			Language:    "en",
+			ContentType: apimodel.StatusContentTypePlain,
+		},
+	})
+	suite.NoError(errWithCode)
+
+	dbStatus, err := suite.db.GetStatusByID(ctx, apiStatus.ID)
+	suite.NoError(err)
+	pollID := dbStatus.PollID
+	suite.NotEmpty(pollID)
+
+	apiStatus, errWithCode = suite.status.Edit(ctx, editingAccount, apiStatus.ID, &apimodel.StatusEditRequest{
+		Status:      "never mind",
+		Language:    "en",
+		ContentType: apimodel.StatusContentTypePlain,
+	})
+	suite.NoError(errWithCode)
+	suite.Nil(apiStatus.Poll)
+
+	// The status should no longer refer to the
+	// poll, which should have been deleted.
+	dbStatus, err = suite.db.GetStatusByID(ctx, apiStatus.ID)
+	suite.NoError(err)
+	suite.Empty(dbStatus.PollID)
+
+	_, err = suite.db.GetPollByID(ctx, pollID)
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
+func (suite *StatusEditTestSuite) TestEditSomeoneElsesStatus() {
+	ctx := context.Background()
+
+	editingAccount := suite.testAccounts["local_account_1"]
+	targetStatus := suite.testStatuses["admin_account_status_1"]
+
+	form := &apimodel.StatusEditRequest{
+		Status: "this isn't my status",
+	}
+
+	apiStatus, errWithCode := suite.status.Edit(ctx, editingAccount, targetStatus.ID, form)
+	suite.Nil(apiStatus)
+	suite.Equal(http.StatusForbidden, errWithCode.Code())
+}
+
+func TestStatusEditTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusEditTestSuite))
+}
@@ -24,6 +24,7 @@ import (
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/statusfilter"
 )
 
 // Get gets the given status, taking account of privacy settings and blocks etc.
@@ -48,6 +49,16 @@ func (p *Processor) ContextGet(ctx context.Context, requestingAccount *gtsmodel.
 		Descendants: []apimodel.Status{},
 	}
 
+	var filters []*gtsmodel.Filter
+	if requestingAccount != nil {
+		var err error
+		filters, err = p.state.DB.GetFiltersForAccountID(ctx, requestingAccount.ID)
+		if err != nil {
+			err = gtserror.Newf("couldn't retrieve filters for account %s: %w", requestingAccount.ID, err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+	}
+
 	parents, err := p.state.DB.GetStatusParents(ctx, targetStatus, false)
 	if err != nil {
 		return nil, gtserror.NewErrorInternalError(err)
@@ -55,7 +66,7 @@ func (p *Processor) ContextGet(ctx context.Context, requestingAccount *gtsmodel.
 
 	for _, status := range parents {
 		if v, err := p.filter.StatusVisible(ctx, requestingAccount, status); err == nil && v {
-			apiStatus, err := p.tc.StatusToAPIStatus(ctx, status, requestingAccount)
+			apiStatus, err := p.tc.StatusToAPIStatus(ctx, status, requestingAccount, statusfilter.FilterContextThread, filters)
 			if err == nil {
 				context.Ancestors = append(context.Ancestors, *apiStatus)
 			}
@@ -73,7 +84,7 @@ func (p *Processor) ContextGet(ctx context.Context, requestingAccount *gtsmodel.
 
 	for _, status := range children {
 		if v, err := p.filter.StatusVisible(ctx, requestingAccount, status); err == nil && v {
-			apiStatus, err := p.tc.StatusToAPIStatus(ctx, status, requestingAccount)
+			apiStatus, err := p.tc.StatusToAPIStatus(ctx, status, requestingAccount, statusfilter.FilterContextThread, filters)
 			if err == nil {
 				context.Descendants = append(context.Descendants, *apiStatus)
 			}
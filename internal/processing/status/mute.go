@@ -0,0 +1,116 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtscontext"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+// MuteCreate mutes the thread that the given status is part of for
+// the requestingAccount (no-op if the thread is already muted).
+func (p *Processor) MuteCreate(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	targetStatus, threadRoot, existingMuteID, errWithCode := p.getMuteTarget(ctx, requestingAccount, targetStatusID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if existingMuteID != "" {
+		// Thread is already muted.
+		return p.apiStatus(ctx, targetStatus, requestingAccount)
+	}
+
+	// Create and store a new mute,
+	// keyed on the root of the thread.
+	gtsMute := &gtsmodel.StatusMute{
+		ID:              id.NewULID(),
+		AccountID:       requestingAccount.ID,
+		Account:         requestingAccount,
+		TargetAccountID: threadRoot.AccountID,
+		StatusID:        threadRoot.ID,
+	}
+
+	if err := p.state.DB.PutStatusMute(ctx, gtsMute); err != nil {
+		err = gtserror.Newf("error putting status mute in database: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return p.apiStatus(ctx, targetStatus, requestingAccount)
+}
+
+// MuteRemove unmutes the thread that the given status is part of for
+// the requestingAccount (no-op if the thread isn't muted).
+func (p *Processor) MuteRemove(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string) (*apimodel.Status, gtserror.WithCode) {
+	targetStatus, _, existingMuteID, errWithCode := p.getMuteTarget(ctx, requestingAccount, targetStatusID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if existingMuteID == "" {
+		// Thread isn't muted.
+		return p.apiStatus(ctx, targetStatus, requestingAccount)
+	}
+
+	// We have a mute to remove.
+	if err := p.state.DB.DeleteStatusMute(ctx, existingMuteID); err != nil {
+		err = gtserror.Newf("error removing status mute: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return p.apiStatus(ctx, targetStatus, requestingAccount)
+}
+
+func (p *Processor) getMuteTarget(ctx context.Context, requestingAccount *gtsmodel.Account, targetStatusID string) (*gtsmodel.Status, *gtsmodel.Status, string, gtserror.WithCode) {
+	targetStatus, errWithCode := p.getVisibleStatus(ctx, requestingAccount, targetStatusID)
+	if errWithCode != nil {
+		return nil, nil, "", errWithCode
+	}
+
+	// Mutes apply to the whole thread,
+	// so get the root status of it.
+	rootID, err := p.state.DB.GetStatusThreadRootID(ctx, targetStatus)
+	if err != nil {
+		err = fmt.Errorf("getMuteTarget: error getting thread root: %w", err)
+		return nil, nil, "", gtserror.NewErrorInternalError(err)
+	}
+
+	threadRoot := targetStatus
+	if rootID != targetStatus.ID {
+		threadRoot, err = p.state.DB.GetStatusByID(gtscontext.SetBarebones(ctx), rootID)
+		if err != nil {
+			err = fmt.Errorf("getMuteTarget: error getting thread root %s: %w", rootID, err)
+			return nil, nil, "", gtserror.NewErrorInternalError(err)
+		}
+	}
+
+	muteID, err := p.state.DB.GetStatusMuteID(ctx, requestingAccount.ID, threadRoot.ID)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err = fmt.Errorf("getMuteTarget: error checking existing mute: %w", err)
+		return nil, nil, "", gtserror.NewErrorInternalError(err)
+	}
+
+	return targetStatus, threadRoot, muteID, nil
+}
@@ -0,0 +1,366 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+)
+
+const (
+	// minScheduleAhead is how far in the future
+	// statuses must be scheduled for, as Mastodon.
+	minScheduleAhead = 5 * time.Minute
+
+	// maxScheduledStatuses is the most statuses an account
+	// may have scheduled at any one time, as Mastodon.
+	maxScheduledStatuses = 300
+)
+
+// ScheduledStatusCreate processes the given form to schedule a new status for publication
+// at the time given in form.ScheduledAt, returning the api model representation of the
+// scheduled status. The status itself will only be created once it falls due.
+func (p *Processor) ScheduledStatusCreate(ctx context.Context, account *gtsmodel.Account, application *gtsmodel.Application, form *apimodel.AdvancedStatusCreateForm) (*apimodel.ScheduledStatus, gtserror.WithCode) {
+	scheduledAt, errWithCode := parseScheduledAt(form.ScheduledAt)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	count, err := p.state.DB.CountScheduledStatusesForAccountID(ctx, account.ID)
+	if err != nil {
+		err := gtserror.Newf("db error counting scheduled statuses: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if count >= maxScheduledStatuses {
+		err := fmt.Errorf("you can't have more than %d scheduled statuses", maxScheduledStatuses)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	// Check now that the status would be created OK, using
+	// a stand-in for it, so the caller knows straight away
+	// if there's a problem with the reply or media.
+	check := &gtsmodel.Status{}
+
+	if errWithCode := processReplyToID(ctx, p.state.DB, form, account.ID, check); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	if errWithCode := processMediaIDs(ctx, p.state.DB, form, account.ID, "", check); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	sensitive := form.Sensitive
+	scheduledStatus := &gtsmodel.ScheduledStatus{
+		ID:               id.NewULID(),
+		AccountID:        account.ID,
+		Account:          account,
+		ApplicationID:    application.ID,
+		Application:      application,
+		ScheduledAt:      scheduledAt,
+		Text:             form.Status,
+		MediaIDs:         check.AttachmentIDs,
+		MediaAttachments: check.Attachments,
+		InReplyToID:      form.InReplyToID,
+		Sensitive:        &sensitive,
+		SpoilerText:      form.SpoilerText,
+		Federated:        form.Federated,
+		Boostable:        form.Boostable,
+		Replyable:        form.Replyable,
+		Likeable:         form.Likeable,
+		Language:         form.Language,
+		ContentType:      string(form.ContentType),
+	}
+
+	if form.Visibility != "" {
+		scheduledStatus.Visibility = typeutils.APIVisToVis(form.Visibility)
+	}
+
+	pollMultiple, pollHideTotals := false, false
+	if form.Poll != nil {
+		scheduledStatus.PollOptions = form.Poll.Options
+		scheduledStatus.PollExpiresIn = form.Poll.ExpiresIn
+		pollMultiple = form.Poll.Multiple
+		pollHideTotals = form.Poll.HideTotals
+	}
+	scheduledStatus.PollMultiple = &pollMultiple
+	scheduledStatus.PollHideTotals = &pollHideTotals
+
+	if err := p.state.DB.PutScheduledStatus(ctx, scheduledStatus); err != nil {
+		err := gtserror.Newf("db error putting scheduled status: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	// Mark the media as belonging to the scheduled
+	// status, so it can't be attached elsewhere,
+	// or cleaned up as unused in the meantime.
+	for _, attachment := range scheduledStatus.MediaAttachments {
+		attachment.ScheduledStatusID = scheduledStatus.ID
+		if err := p.state.DB.UpdateAttachment(ctx, attachment, "scheduled_status_id"); err != nil {
+			err := gtserror.Newf("db error updating attachment %s: %w", attachment.ID, err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+	}
+
+	return p.apiScheduledStatus(ctx, scheduledStatus)
+}
+
+// ScheduledStatusGet returns the scheduled status with the given ID, if it belongs to the given account.
+func (p *Processor) ScheduledStatusGet(ctx context.Context, account *gtsmodel.Account, scheduledStatusID string) (*apimodel.ScheduledStatus, gtserror.WithCode) {
+	scheduledStatus, errWithCode := p.getOwnScheduledStatus(ctx, account, scheduledStatusID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	return p.apiScheduledStatus(ctx, scheduledStatus)
+}
+
+// ScheduledStatusesGet returns a page of the statuses that the given account has scheduled.
+func (p *Processor) ScheduledStatusesGet(ctx context.Context, account *gtsmodel.Account, maxID string, sinceID string, minID string, limit int) (*apimodel.PageableResponse, gtserror.WithCode) {
+	scheduledStatuses, err := p.state.DB.GetScheduledStatusesForAccountID(ctx, account.ID, maxID, sinceID, minID, limit)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("db error getting scheduled statuses: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	count := len(scheduledStatuses)
+	if count == 0 {
+		return util.EmptyPageableResponse(), nil
+	}
+
+	items := make([]interface{}, 0, count)
+	for _, scheduledStatus := range scheduledStatuses {
+		item, err := p.tc.ScheduledStatusToAPIScheduledStatus(ctx, scheduledStatus)
+		if err != nil {
+			log.Errorf(ctx, "error converting scheduled status to api: %v", err)
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return util.PackagePageableResponse(util.PageableResponseParams{
+		Items:          items,
+		Path:           "/api/v1/scheduled_statuses",
+		NextMaxIDValue: scheduledStatuses[count-1].ID,
+		PrevMinIDValue: scheduledStatuses[0].ID,
+		Limit:          limit,
+	})
+}
+
+// ScheduledStatusUpdate changes the time at which the given scheduled status will be published.
+func (p *Processor) ScheduledStatusUpdate(ctx context.Context, account *gtsmodel.Account, scheduledStatusID string, form *apimodel.ScheduledStatusUpdateRequest) (*apimodel.ScheduledStatus, gtserror.WithCode) {
+	scheduledStatus, errWithCode := p.getOwnScheduledStatus(ctx, account, scheduledStatusID)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	scheduledAt, errWithCode := parseScheduledAt(form.ScheduledAt)
+	if errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	scheduledStatus.ScheduledAt = scheduledAt
+	if err := p.state.DB.UpdateScheduledStatus(ctx, scheduledStatus, "scheduled_at"); err != nil {
+		err := gtserror.Newf("db error updating scheduled status: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return p.apiScheduledStatus(ctx, scheduledStatus)
+}
+
+// ScheduledStatusDelete cancels the given scheduled status, so that it won't be published.
+func (p *Processor) ScheduledStatusDelete(ctx context.Context, account *gtsmodel.Account, scheduledStatusID string) gtserror.WithCode {
+	scheduledStatus, errWithCode := p.getOwnScheduledStatus(ctx, account, scheduledStatusID)
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	if err := p.deleteScheduledStatus(ctx, scheduledStatus); err != nil {
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	return nil
+}
+
+// ScheduledStatusesPublish creates each scheduled status that is now due,
+// through the normal status creation path. Statuses that fall due while
+// the instance is down are published the next time this is called.
+func (p *Processor) ScheduledStatusesPublish(ctx context.Context) {
+	if !p.publishing.TryLock() {
+		// Already publishing in
+		// another run, leave it.
+		return
+	}
+	defer p.publishing.Unlock()
+
+	scheduledStatuses, err := p.state.DB.GetDueScheduledStatuses(ctx, time.Now())
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		log.Errorf(ctx, "error getting due scheduled statuses: %v", err)
+		return
+	}
+
+	for _, scheduledStatus := range scheduledStatuses {
+		if err := p.publishScheduledStatus(ctx, scheduledStatus); err != nil {
+			log.Errorf(ctx, "error publishing scheduled status %s: %v", scheduledStatus.ID, err)
+		}
+	}
+}
+
+func (p *Processor) publishScheduledStatus(ctx context.Context, scheduledStatus *gtsmodel.ScheduledStatus) error {
+	if err := p.state.DB.PopulateScheduledStatus(ctx, scheduledStatus); err != nil {
+		return gtserror.Newf("error populating scheduled status: %w", err)
+	}
+
+	if !scheduledStatus.Account.SuspendedAt.IsZero() {
+		// Account was suspended
+		// in the meantime, drop it.
+		return p.deleteScheduledStatus(ctx, scheduledStatus)
+	}
+
+	form := &apimodel.AdvancedStatusCreateForm{
+		StatusCreateRequest: apimodel.StatusCreateRequest{
+			Status:      scheduledStatus.Text,
+			MediaIDs:    scheduledStatus.MediaIDs,
+			InReplyToID: scheduledStatus.InReplyToID,
+			Sensitive:   *scheduledStatus.Sensitive,
+			SpoilerText: scheduledStatus.SpoilerText,
+			Language:    scheduledStatus.Language,
+			ContentType: apimodel.StatusContentType(scheduledStatus.ContentType),
+		},
+		AdvancedVisibilityFlagsForm: apimodel.AdvancedVisibilityFlagsForm{
+			Federated: scheduledStatus.Federated,
+			Boostable: scheduledStatus.Boostable,
+			Replyable: scheduledStatus.Replyable,
+			Likeable:  scheduledStatus.Likeable,
+		},
+	}
+
+	if scheduledStatus.Visibility != "" {
+		form.Visibility = p.tc.VisToAPIVis(ctx, scheduledStatus.Visibility)
+	}
+
+	if len(scheduledStatus.PollOptions) != 0 {
+		form.Poll = &apimodel.PollRequest{
+			Options:    scheduledStatus.PollOptions,
+			ExpiresIn:  scheduledStatus.PollExpiresIn,
+			Multiple:   *scheduledStatus.PollMultiple,
+			HideTotals: *scheduledStatus.PollHideTotals,
+		}
+	}
+
+	if _, errWithCode := p.create(ctx, scheduledStatus.Account, scheduledStatus.Application, form, scheduledStatus.ID); errWithCode != nil {
+		if errWithCode.Code() < http.StatusInternalServerError {
+			// Status can never be created as it
+			// is (eg., the reply target is gone),
+			// so drop it rather than retry forever.
+			if err := p.deleteScheduledStatus(ctx, scheduledStatus); err != nil {
+				log.Errorf(ctx, "error deleting scheduled status %s: %v", scheduledStatus.ID, err)
+			}
+		}
+
+		return gtserror.Newf("error creating status: %w", errWithCode)
+	}
+
+	// Only remove the scheduled status now the real one
+	// exists, so that if creating it failed, it's tried
+	// again next time. Publishing runs never overlap, so
+	// it can't be published more than once meanwhile.
+	return p.deleteScheduledStatus(ctx, scheduledStatus)
+}
+
+// deleteScheduledStatus deletes the given scheduled
+// status, releasing any media that was held for it.
+func (p *Processor) deleteScheduledStatus(ctx context.Context, scheduledStatus *gtsmodel.ScheduledStatus) error {
+	if err := p.state.DB.DeleteScheduledStatusByID(ctx, scheduledStatus.ID); err != nil {
+		return gtserror.Newf("db error deleting scheduled status: %w", err)
+	}
+
+	attachments, err := p.state.DB.GetAttachmentsByIDs(ctx, scheduledStatus.MediaIDs)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return gtserror.Newf("db error getting attachments: %w", err)
+	}
+
+	for _, attachment := range attachments {
+		if attachment.ScheduledStatusID != scheduledStatus.ID {
+			continue
+		}
+
+		attachment.ScheduledStatusID = ""
+		if err := p.state.DB.UpdateAttachment(ctx, attachment, "scheduled_status_id"); err != nil {
+			return gtserror.Newf("db error updating attachment %s: %w", attachment.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// getOwnScheduledStatus returns the scheduled status with the given ID,
+// or a not found error if it doesn't exist or isn't owned by the account.
+func (p *Processor) getOwnScheduledStatus(ctx context.Context, account *gtsmodel.Account, scheduledStatusID string) (*gtsmodel.ScheduledStatus, gtserror.WithCode) {
+	scheduledStatus, err := p.state.DB.GetScheduledStatusByID(ctx, scheduledStatusID)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("db error getting scheduled status: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if scheduledStatus == nil || scheduledStatus.AccountID != account.ID {
+		err := fmt.Errorf("scheduled status %s not found", scheduledStatusID)
+		return nil, gtserror.NewErrorNotFound(err)
+	}
+
+	return scheduledStatus, nil
+}
+
+func (p *Processor) apiScheduledStatus(ctx context.Context, scheduledStatus *gtsmodel.ScheduledStatus) (*apimodel.ScheduledStatus, gtserror.WithCode) {
+	apiScheduledStatus, err := p.tc.ScheduledStatusToAPIScheduledStatus(ctx, scheduledStatus)
+	if err != nil {
+		err = gtserror.Newf("error converting scheduled status %s to frontend representation: %w", scheduledStatus.ID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return apiScheduledStatus, nil
+}
+
+// parseScheduledAt parses the given ISO 8601 datetime,
+// checking that it's far enough in the future to schedule.
+func parseScheduledAt(in string) (time.Time, gtserror.WithCode) {
+	scheduledAt, err := time.Parse(time.RFC3339, in)
+	if err != nil {
+		err := fmt.Errorf("scheduled_at %s could not be parsed as an ISO 8601 datetime", in)
+		return time.Time{}, gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	if scheduledAt.Before(time.Now().Add(minScheduleAhead)) {
+		err := fmt.Errorf("scheduled_at must be at least %d minutes in the future", int(minScheduleAhead.Minutes()))
+		return time.Time{}, gtserror.NewErrorUnprocessableEntity(err, err.Error())
+	}
+
+	return scheduledAt, nil
+}
@@ -0,0 +1,217 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package status_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type ScheduledStatusTestSuite struct {
+	StatusStandardTestSuite
+}
+
+func (suite *ScheduledStatusTestSuite) TestScheduledStatusCreate() {
+	var (
+		ctx         = context.Background()
+		account     = suite.testAccounts["local_account_1"]
+		application = suite.testApplications["application_1"]
+		attachment  = suite.testAttachments["local_account_1_unattached_1"]
+		scheduledAt = time.Now().Add(time.Hour)
+	)
+
+	form := &apimodel.AdvancedStatusCreateForm{
+		StatusCreateRequest: apimodel.StatusCreateRequest{
+			Status:      "this will be posted in an hour",
+			MediaIDs:    []string{attachment.ID},
+			Visibility:  apimodel.VisibilityUnlisted,
+			ScheduledAt: scheduledAt.Format(time.RFC3339),
+			Language:    "en",
+		},
+	}
+
+	apiScheduledStatus, errWithCode := suite.status.ScheduledStatusCreate(ctx, account, application, form)
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+
+	suite.Equal(util.FormatISO8601(scheduledAt.Truncate(time.Second)), apiScheduledStatus.ScheduledAt)
+	suite.Equal("this will be posted in an hour", apiScheduledStatus.Params.Text)
+	suite.Equal("unlisted", apiScheduledStatus.Params.Visibility)
+	suite.Equal([]string{attachment.ID}, apiScheduledStatus.Params.MediaIDs)
+	suite.Len(apiScheduledStatus.MediaAttachments, 1)
+
+	// Media should now be held for the scheduled status.
+	dbAttachment, err := suite.db.GetAttachmentByID(ctx, attachment.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal(apiScheduledStatus.ID, dbAttachment.ScheduledStatusID)
+
+	// So it can't be used for another status.
+	form.ScheduledAt = ""
+	_, errWithCode = suite.status.Create(ctx, account, application, form)
+	suite.Equal(http.StatusBadRequest, errWithCode.Code())
+}
+
+func (suite *ScheduledStatusTestSuite) TestScheduledStatusCreateTooSoon() {
+	var (
+		ctx         = context.Background()
+		account     = suite.testAccounts["local_account_1"]
+		application = suite.testApplications["application_1"]
+	)
+
+	form := &apimodel.AdvancedStatusCreateForm{
+		StatusCreateRequest: apimodel.StatusCreateRequest{
+			Status:      "not long to wait",
+			ScheduledAt: time.Now().Add(time.Minute).Format(time.RFC3339),
+		},
+	}
+
+	_, errWithCode := suite.status.ScheduledStatusCreate(ctx, account, application, form)
+	suite.Equal(http.StatusUnprocessableEntity, errWithCode.Code())
+	suite.Equal("Unprocessable Entity: scheduled_at must be at least 5 minutes in the future", errWithCode.Safe())
+}
+
+func (suite *ScheduledStatusTestSuite) TestScheduledStatusesPublish() {
+	ctx := context.Background()
+
+	// Bring the scheduled status forward to now.
+	scheduledStatus, err := suite.db.GetScheduledStatusByID(ctx, testrig.NewTestScheduledStatuses()["local_account_1_scheduled_status_1"].ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	scheduledStatus.ScheduledAt = time.Now()
+	if err := suite.db.UpdateScheduledStatus(ctx, scheduledStatus, "scheduled_at"); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.status.ScheduledStatusesPublish(ctx)
+
+	// Scheduled status should be gone now.
+	_, err = suite.db.GetScheduledStatusByID(ctx, scheduledStatus.ID)
+	suite.ErrorIs(err, db.ErrNoEntries)
+
+	// And published as a real status.
+	statuses, err := suite.db.GetAccountStatuses(ctx, scheduledStatus.AccountID, 1, false, false, "", "", false, false)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal("happy new year from the distant future!", statuses[0].Text)
+	suite.Equal(scheduledStatus.ApplicationID, statuses[0].CreatedWithApplicationID)
+}
+
+func (suite *ScheduledStatusTestSuite) TestScheduledStatusesPublishWithMedia() {
+	var (
+		ctx         = context.Background()
+		account     = suite.testAccounts["local_account_1"]
+		application = suite.testApplications["application_1"]
+		attachment  = suite.testAttachments["local_account_1_unattached_1"]
+	)
+
+	apiScheduledStatus, errWithCode := suite.status.ScheduledStatusCreate(ctx, account, application, &apimodel.AdvancedStatusCreateForm{
+		StatusCreateRequest: apimodel.StatusCreateRequest{
+			Status:      "look at this",
+			MediaIDs:    []string{attachment.ID},
+			ScheduledAt: time.Now().Add(time.Hour).Format(time.RFC3339),
+			Language:    "en",
+		},
+	})
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+
+	scheduledStatus, err := suite.db.GetScheduledStatusByID(ctx, apiScheduledStatus.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	scheduledStatus.ScheduledAt = time.Now()
+	if err := suite.db.UpdateScheduledStatus(ctx, scheduledStatus, "scheduled_at"); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.status.ScheduledStatusesPublish(ctx)
+
+	_, err = suite.db.GetScheduledStatusByID(ctx, scheduledStatus.ID)
+	suite.ErrorIs(err, db.ErrNoEntries)
+
+	// The held media should have been attached
+	// to the real status, and no longer be held.
+	statuses, err := suite.db.GetAccountStatuses(ctx, account.ID, 1, false, false, "", "", false, false)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal("look at this", statuses[0].Text)
+	suite.Equal([]string{attachment.ID}, statuses[0].AttachmentIDs)
+
+	dbAttachment, err := suite.db.GetAttachmentByID(ctx, attachment.ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Empty(dbAttachment.ScheduledStatusID)
+}
+
+func (suite *ScheduledStatusTestSuite) TestScheduledStatusesPublishFailed() {
+	ctx := context.Background()
+
+	scheduledStatus, err := suite.db.GetScheduledStatusByID(ctx, testrig.NewTestScheduledStatuses()["local_account_1_scheduled_status_1"].ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Reply to a status that doesn't exist (any more).
+	scheduledStatus.ScheduledAt = time.Now()
+	scheduledStatus.InReplyToID = "01H0ZZZZZZZZZZZZZZZZZZZZZZ"
+	if err := suite.db.UpdateScheduledStatus(ctx, scheduledStatus, "scheduled_at", "in_reply_to_id"); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.status.ScheduledStatusesPublish(ctx)
+
+	// It can't ever be published, so it should
+	// have been dropped rather than left to retry.
+	_, err = suite.db.GetScheduledStatusByID(ctx, scheduledStatus.ID)
+	suite.ErrorIs(err, db.ErrNoEntries)
+
+	statuses, err := suite.db.GetAccountStatuses(ctx, scheduledStatus.AccountID, 1, false, false, "", "", false, false)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.NotEqual("happy new year from the distant future!", statuses[0].Text)
+}
+
+func (suite *ScheduledStatusTestSuite) TestScheduledStatusGetNotOwn() {
+	ctx := context.Background()
+
+	_, errWithCode := suite.status.ScheduledStatusGet(ctx, suite.testAccounts["local_account_2"], "01H9J5QZ1B7Y3E8K2TGTXC9XHR")
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func TestScheduledStatusTestSuite(t *testing.T) {
+	suite.Run(t, &ScheduledStatusTestSuite{})
+}
@@ -18,6 +18,8 @@
 package status
 
 import (
+	"sync"
+
 	"github.com/superseriousbusiness/gotosocial/internal/federation"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/state"
@@ -33,6 +35,11 @@ type Processor struct {
 	filter       *visibility.Filter
 	formatter    text.Formatter
 	parseMention gtsmodel.ParseMentionFunc
+
+	// Held while publishing due scheduled
+	// statuses, so that overlapping runs
+	// can't publish the same status twice.
+	publishing *sync.Mutex
 }
 
 // New returns a new status processor.
@@ -44,5 +51,6 @@ func New(state *state.State, federator federation.Federator, tc typeutils.TypeCo
 		filter:       filter,
 		formatter:    text.NewFormatter(state.DB),
 		parseMention: parseMention,
+		publishing:   &sync.Mutex{},
 	}
 }
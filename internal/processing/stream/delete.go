@@ -18,7 +18,6 @@
 package stream
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/superseriousbusiness/gotosocial/internal/stream"
@@ -26,30 +25,48 @@ import (
 
 // Delete streams the delete of the given statusID to *ALL* open streams.
 func (p *Processor) Delete(statusID string) error {
-	errs := []string{}
-
-	// get all account IDs with open streams
-	accountIDs := []string{}
-	p.streamMap.Range(func(k interface{}, _ interface{}) bool {
-		key, ok := k.(string)
-		if !ok {
-			panic("streamMap key was not a string (account id)")
+	// Range over every open stream, including anonymous
+	// ones, rather than just those keyed by account ID.
+	p.subscribers.Range(func(_ any, v any) bool {
+		s := v.(*subscriber).stream //nolint:forcetypeassert
+
+		s.Lock()
+		defer s.Unlock()
+
+		if !s.Connected {
+			return true
 		}
 
-		accountIDs = append(accountIDs, key)
-		return true
-	})
+		for streamType := range s.StreamTypes {
+			if isStatusStreamType(streamType) {
+				s.Messages <- &stream.Message{
+					Stream:  []string{streamType},
+					Event:   stream.EventTypeDelete,
+					Payload: statusID,
+				}
 
-	// stream the delete to every account
-	for _, accountID := range accountIDs {
-		if err := p.toAccount(statusID, stream.EventTypeDelete, stream.AllStatusTimelines, accountID); err != nil {
-			errs = append(errs, err.Error())
+				// Only send the delete
+				// once to each stream.
+				break
+			}
 		}
-	}
 
-	if len(errs) != 0 {
-		return fmt.Errorf("one or more errors streaming status delete: %s", strings.Join(errs, ";"))
-	}
+		return true
+	})
 
 	return nil
 }
+
+// isStatusStreamType returns whether the given stream type
+// is one that statuses can be delivered to, taking account
+// of list IDs or tag names appended to the stream type,
+// eg., `list:01H3YF48G8B7KTPQFS8D2QBVG8` or `hashtag:example`.
+func isStatusStreamType(streamType string) bool {
+	for _, timeline := range stream.AllStatusTimelines {
+		if streamType == timeline ||
+			strings.HasPrefix(streamType, timeline+":") {
+			return true
+		}
+	}
+	return false
+}
@@ -31,9 +31,15 @@ import (
 )
 
 // Open returns a new Stream for the given account, which will contain a channel for passing messages back to the caller.
+// Account may be nil, in which case the stream is anonymous, and will only receive public and hashtag updates.
 func (p *Processor) Open(ctx context.Context, account *gtsmodel.Account, streamType string) (*stream.Stream, gtserror.WithCode) {
+	var accountID string
+	if account != nil {
+		accountID = account.ID
+	}
+
 	l := log.WithContext(ctx).WithFields(kv.Fields{
-		{"account", account.ID},
+		{"account", accountID},
 		{"streamType", streamType},
 	}...)
 	l.Debug("received open stream request")
@@ -66,6 +72,19 @@ func (p *Processor) Open(ctx context.Context, account *gtsmodel.Account, streamT
 	}
 	go p.waitToCloseStream(account, newStream)
 
+	// Register the stream globally, so that
+	// it can receive public and hashtag updates.
+	p.subscribers.Store(streamID, &subscriber{
+		account: account,
+		stream:  newStream,
+	})
+
+	if account == nil {
+		// Anonymous streams have
+		// no per-account entry.
+		return newStream, nil
+	}
+
 	v, ok := p.streamMap.Load(account.ID)
 	if ok {
 		// There is an entry in the streamMap
@@ -105,6 +124,15 @@ func (p *Processor) waitToCloseStream(account *gtsmodel.Account, thisStream *str
 	// indicate the stream is no longer connected
 	thisStream.Connected = false
 
+	// remove the stream from the global registry
+	p.subscribers.Delete(thisStream.ID)
+
+	if account == nil {
+		// anonymous streams have no entry in the stream map
+		close(thisStream.Messages)
+		return
+	}
+
 	// load and parse the entry for this account from the stream map
 	v, ok := p.streamMap.Load(account.ID)
 	if !ok || v == nil {
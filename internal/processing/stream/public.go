@@ -0,0 +1,258 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/statusfilter"
+	"github.com/superseriousbusiness/gotosocial/internal/stream"
+)
+
+// PublicUpdate streams the given status as an update to every open
+// public, public:local, hashtag and hashtag:local stream that it belongs
+// on, checking that the status is timelineable for each subscriber.
+func (p *Processor) PublicUpdate(ctx context.Context, status *gtsmodel.Status) error {
+	return p.toPublic(ctx, status, stream.EventTypeUpdate)
+}
+
+// PublicStatusUpdate is like PublicUpdate, but streams
+// the given status as an edit of an earlier status.
+func (p *Processor) PublicStatusUpdate(ctx context.Context, status *gtsmodel.Status) error {
+	return p.toPublic(ctx, status, stream.EventTypeStatusUpdate)
+}
+
+func (p *Processor) toPublic(ctx context.Context, status *gtsmodel.Status, event string) error {
+	if status.Visibility != gtsmodel.VisibilityPublic {
+		// Only public statuses
+		// go on public streams.
+		return nil
+	}
+
+	local := status.Local != nil && *status.Local
+
+	// Gather the stream types that this
+	// status could be delivered to.
+	publicTypes := []string{stream.TimelinePublic}
+	if local {
+		publicTypes = append(publicTypes, stream.TimelineLocal)
+	}
+
+	tagTypes := make([]string, 0, 2*len(status.Tags))
+	for _, tag := range status.Tags {
+		if !*tag.Useable || !*tag.Listable {
+			// Tag can't be listed on
+			// this instance, skip it.
+			continue
+		}
+
+		// Stream types for hashtags are keyed
+		// by tag name, eg., `hashtag:example`.
+		tagTypes = append(tagTypes, stream.TimelineHashtag+":"+tag.Name)
+		if local {
+			tagTypes = append(tagTypes, stream.TimelineHashtagLocal+":"+tag.Name)
+		}
+	}
+
+	var (
+		errs = gtserror.NewMultiError(0)
+
+		// Status as it's shown to anonymous subscribers,
+		// which is the same for each of them, so we only
+		// need to convert it once.
+		anonPayload string
+	)
+
+	p.subscribers.Range(func(_ any, v any) bool {
+		sub := v.(*subscriber) //nolint:forcetypeassert
+
+		streamType, err := p.publicStreamType(ctx, sub, status, publicTypes, tagTypes)
+		if err != nil {
+			errs.Appendf("error checking timelineability for stream %s: %w", sub.stream.ID, err)
+			return true
+		}
+
+		if streamType == "" {
+			// Status doesn't belong
+			// on this stream, skip.
+			return true
+		}
+
+		var payload string
+		if sub.account == nil && anonPayload != "" {
+			payload = anonPayload
+		} else {
+			payload, err = p.publicPayload(ctx, sub.account, status)
+			if errors.Is(err, statusfilter.ErrHideStatus) {
+				// Filtered out by
+				// the subscriber.
+				return true
+			}
+
+			if err != nil {
+				errs.Appendf("error preparing status %s for stream %s: %w", status.ID, sub.stream.ID, err)
+				return true
+			}
+
+			if sub.account == nil {
+				anonPayload = payload
+			}
+		}
+
+		toStream(sub.stream, payload, event, streamType)
+		return true
+	})
+
+	if err := errs.Combine(); err != nil {
+		return gtserror.Newf("%w", err)
+	}
+
+	return nil
+}
+
+// publicStreamType returns the first of the given public and tag
+// stream types that the subscriber's stream is subscribed to, and
+// on which the status is timelineable for the subscriber. Statuses
+// by, or boosting statuses by, accounts the subscriber has muted
+// are on none. If there is no such stream type, an empty string
+// will be returned.
+func (p *Processor) publicStreamType(
+	ctx context.Context,
+	sub *subscriber,
+	status *gtsmodel.Status,
+	publicTypes []string,
+	tagTypes []string,
+) (string, error) {
+	// Take a snapshot of the stream types subscribed to
+	// now, so we don't hold the lock while doing lookups.
+	var publicType, tagType string
+
+	sub.stream.Lock()
+	publicType = firstSubscribed(sub.stream, publicTypes)
+	tagType = firstSubscribed(sub.stream, tagTypes)
+	sub.stream.Unlock()
+
+	if publicType == "" && tagType == "" {
+		// Not subscribed to
+		// any of the types.
+		return "", nil
+	}
+
+	if sub.account != nil {
+		// Check whether the subscriber has muted the
+		// status author, or the author of a boosted status.
+		muted, err := p.state.DB.IsMuted(ctx, sub.account.ID, status.AccountID)
+		if err != nil {
+			return "", gtserror.Newf("error checking mute %s->%s: %w", sub.account.ID, status.AccountID, err)
+		}
+
+		if !muted && status.BoostOfAccountID != "" {
+			muted, err = p.state.DB.IsMuted(ctx, sub.account.ID, status.BoostOfAccountID)
+			if err != nil {
+				return "", gtserror.Newf("error checking mute %s->%s: %w", sub.account.ID, status.BoostOfAccountID, err)
+			}
+		}
+
+		if muted {
+			return "", nil
+		}
+	}
+
+	if publicType != "" {
+		timelineable, err := p.filter.StatusPublicTimelineable(ctx, sub.account, status)
+		if err != nil {
+			return "", err
+		}
+
+		if timelineable {
+			return publicType, nil
+		}
+	}
+
+	if tagType != "" {
+		timelineable, err := p.filter.StatusTagTimelineable(ctx, sub.account, status)
+		if err != nil {
+			return "", err
+		}
+
+		if timelineable {
+			return tagType, nil
+		}
+	}
+
+	return "", nil
+}
+
+// publicPayload converts the given status to
+// its JSON frontend representation, as seen by
+// the given account, which may be nil.
+func (p *Processor) publicPayload(ctx context.Context, account *gtsmodel.Account, status *gtsmodel.Status) (string, error) {
+	var filters []*gtsmodel.Filter
+	if account != nil {
+		var err error
+		filters, err = p.state.DB.GetFiltersForAccountID(ctx, account.ID)
+		if err != nil {
+			return "", gtserror.Newf("couldn't retrieve filters for account %s: %w", account.ID, err)
+		}
+	}
+
+	apiStatus, err := p.tc.StatusToAPIStatus(ctx, status, account, statusfilter.FilterContextPublic, filters)
+	if err != nil {
+		return "", err
+	}
+
+	bytes, err := json.Marshal(apiStatus)
+	if err != nil {
+		return "", gtserror.Newf("error marshalling status to json: %w", err)
+	}
+
+	return string(bytes), nil
+}
+
+// firstSubscribed returns the first of the given stream types
+// that the stream is subscribed to, or an empty string. The
+// caller must hold the stream lock.
+func firstSubscribed(s *stream.Stream, streamTypes []string) string {
+	for _, streamType := range streamTypes {
+		if _, found := s.StreamTypes[streamType]; found {
+			return streamType
+		}
+	}
+	return ""
+}
+
+// toStream puts a message with the given payload
+// into the stream, if it's still connected.
+func toStream(s *stream.Stream, payload string, event string, streamType string) {
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.Connected {
+		return
+	}
+
+	s.Messages <- &stream.Message{
+		Stream:  []string{streamType},
+		Event:   event,
+		Payload: payload,
+	}
+}
@@ -0,0 +1,212 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package stream_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/stream"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type PublicTestSuite struct {
+	StreamTestSuite
+}
+
+// getStatus returns the fully populated test status with the given key.
+func (suite *PublicTestSuite) getStatus(key string) *gtsmodel.Status {
+	status, err := suite.db.GetStatusByID(context.Background(), testrig.NewTestStatuses()[key].ID)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	return status
+}
+
+// openStream opens a stream of the given type for the given
+// account, which may be nil, hanging it up when the test ends.
+func (suite *PublicTestSuite) openStream(account *gtsmodel.Account, streamType string) *stream.Stream {
+	s, errWithCode := suite.streamProcessor.Open(context.Background(), account, streamType)
+	if errWithCode != nil {
+		suite.FailNow(errWithCode.Error())
+	}
+	suite.T().Cleanup(func() { close(s.Hangup) })
+	return s
+}
+
+// nextMessage returns the next message waiting in
+// the given stream, or nil if there isn't one.
+func nextMessage(s *stream.Stream) *stream.Message {
+	select {
+	case msg := <-s.Messages:
+		return msg
+	default:
+		return nil
+	}
+}
+
+func (suite *PublicTestSuite) TestPublicUpdateAnonymous() {
+	s := suite.openStream(nil, stream.TimelinePublic)
+	status := suite.getStatus("admin_account_status_1")
+
+	if err := suite.streamProcessor.PublicUpdate(context.Background(), status); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	msg := nextMessage(s)
+	if !suite.NotNil(msg) {
+		suite.FailNow("expected message")
+	}
+	suite.Equal(stream.EventTypeUpdate, msg.Event)
+	suite.Equal([]string{stream.TimelinePublic}, msg.Stream)
+	suite.Contains(msg.Payload, `"id":"01F8MH75CBF9JFX4ZAD54N0W0R"`)
+
+	// Only one message per stream.
+	suite.Nil(nextMessage(s))
+}
+
+func (suite *PublicTestSuite) TestPublicUpdateLocal() {
+	local := suite.openStream(nil, stream.TimelineLocal)
+	public := suite.openStream(suite.testAccounts["local_account_1"], stream.TimelinePublic)
+
+	// Pretend this status came from elsewhere.
+	status := suite.getStatus("admin_account_status_1")
+	status.Local = testrig.FalseBool()
+
+	if err := suite.streamProcessor.PublicUpdate(context.Background(), status); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Nil(nextMessage(local))
+	suite.NotNil(nextMessage(public))
+}
+
+func (suite *PublicTestSuite) TestPublicUpdateNotPublic() {
+	s := suite.openStream(nil, stream.TimelinePublic)
+	status := suite.getStatus("local_account_2_status_3")
+
+	if err := suite.streamProcessor.PublicUpdate(context.Background(), status); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Nil(nextMessage(s))
+}
+
+func (suite *PublicTestSuite) TestPublicUpdateHashtag() {
+	var (
+		account = suite.testAccounts["local_account_1"]
+		tagged  = suite.openStream(account, stream.TimelineHashtag+":welcome")
+		local   = suite.openStream(nil, stream.TimelineHashtagLocal+":welcome")
+		other   = suite.openStream(account, stream.TimelineHashtag+":hashtag")
+		status  = suite.getStatus("admin_account_status_1")
+	)
+
+	if err := suite.streamProcessor.PublicStatusUpdate(context.Background(), status); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	msg := nextMessage(tagged)
+	if !suite.NotNil(msg) {
+		suite.FailNow("expected message")
+	}
+	suite.Equal(stream.EventTypeStatusUpdate, msg.Event)
+	suite.Equal([]string{"hashtag:welcome"}, msg.Stream)
+
+	msg = nextMessage(local)
+	if !suite.NotNil(msg) {
+		suite.FailNow("expected message")
+	}
+	suite.Equal([]string{"hashtag:local:welcome"}, msg.Stream)
+
+	suite.Nil(nextMessage(other))
+}
+
+func (suite *PublicTestSuite) TestPublicUpdateMuted() {
+	var (
+		ctx     = context.Background()
+		account = suite.testAccounts["local_account_1"]
+		public  = suite.openStream(account, stream.TimelinePublic)
+		tagged  = suite.openStream(account, stream.TimelineHashtag+":welcome")
+		anon    = suite.openStream(nil, stream.TimelinePublic)
+		status  = suite.getStatus("admin_account_status_1")
+	)
+
+	// Mute the status author.
+	if err := suite.db.PutMute(ctx, &gtsmodel.UserMute{
+		ID:              "01H7DM1T6K5TR1P3V0C4GKJQ2D",
+		AccountID:       account.ID,
+		TargetAccountID: status.AccountID,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	if err := suite.streamProcessor.PublicUpdate(ctx, status); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Nil(nextMessage(public))
+	suite.Nil(nextMessage(tagged))
+	suite.NotNil(nextMessage(anon))
+}
+
+func (suite *PublicTestSuite) TestPublicUpdateBoostOfMuted() {
+	var (
+		ctx     = context.Background()
+		account = suite.testAccounts["local_account_1"]
+		s       = suite.openStream(account, stream.TimelinePublic)
+		status  = suite.getStatus("admin_account_status_1")
+	)
+
+	// Pretend this status is a boost of a
+	// status by an account that's muted.
+	status.BoostOfAccountID = suite.testAccounts["local_account_2"].ID
+	if err := suite.db.PutMute(ctx, &gtsmodel.UserMute{
+		ID:              "01H7DM1T6K5TR1P3V0C4GKJQ2D",
+		AccountID:       account.ID,
+		TargetAccountID: status.BoostOfAccountID,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	if err := suite.streamProcessor.PublicUpdate(ctx, status); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Nil(nextMessage(s))
+}
+
+func (suite *PublicTestSuite) TestDeleteAnonymous() {
+	s := suite.openStream(nil, stream.TimelineHashtag+":welcome")
+
+	if err := suite.streamProcessor.Delete("01F8MH75CBF9JFX4ZAD54N0W0R"); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	msg := nextMessage(s)
+	if !suite.NotNil(msg) {
+		suite.FailNow("expected message")
+	}
+	suite.Equal(stream.EventTypeDelete, msg.Event)
+	suite.Equal("01F8MH75CBF9JFX4ZAD54N0W0R", msg.Payload)
+}
+
+func TestPublicTestSuite(t *testing.T) {
+	suite.Run(t, &PublicTestSuite{})
+}
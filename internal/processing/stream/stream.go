@@ -20,20 +20,42 @@ package stream
 import (
 	"sync"
 
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 	"github.com/superseriousbusiness/gotosocial/internal/state"
 	"github.com/superseriousbusiness/gotosocial/internal/stream"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
+	"github.com/superseriousbusiness/gotosocial/internal/visibility"
 )
 
 type Processor struct {
 	state       *state.State
+	tc          typeutils.TypeConverter
+	filter      *visibility.Filter
 	oauthServer oauth.Server
-	streamMap   sync.Map
+
+	// Per-account streams, keyed by account ID.
+	streamMap sync.Map
+
+	// All open streams, including those
+	// opened without an account, keyed
+	// by stream ID. Used for fanning out
+	// to public and hashtag streams.
+	subscribers sync.Map
+}
+
+// subscriber is an open stream, along with
+// the account that opened it, if any.
+type subscriber struct {
+	account *gtsmodel.Account // nil if anonymous
+	stream  *stream.Stream
 }
 
-func New(state *state.State, oauthServer oauth.Server) Processor {
+func New(state *state.State, tc typeutils.TypeConverter, filter *visibility.Filter, oauthServer oauth.Server) Processor {
 	return Processor{
 		state:       state,
+		tc:          tc,
+		filter:      filter,
 		oauthServer: oauthServer,
 	}
 }
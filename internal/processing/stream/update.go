@@ -35,3 +35,13 @@ func (p *Processor) Update(s *apimodel.Status, account *gtsmodel.Account, stream
 
 	return p.toAccount(string(bytes), stream.EventTypeUpdate, streamTypes, account.ID)
 }
+
+// StatusUpdate streams the given edited status to any open, appropriate streams belonging to the given account.
+func (p *Processor) StatusUpdate(s *apimodel.Status, account *gtsmodel.Account, streamTypes []string) error {
+	bytes, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("error marshalling status to json: %s", err)
+	}
+
+	return p.toAccount(string(bytes), stream.EventTypeStatusUpdate, streamTypes, account.ID)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
 	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/superseriousbusiness/gotosocial/internal/statusfilter"
 	"github.com/superseriousbusiness/gotosocial/internal/timeline"
 	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 	"github.com/superseriousbusiness/gotosocial/internal/util"
@@ -98,7 +99,13 @@ func HomeTimelineStatusPrepare(state *state.State, tc typeutils.TypeConverter) t
 			return nil, err
 		}
 
-		return tc.StatusToAPIStatus(ctx, status, requestingAccount)
+		filters, err := state.DB.GetFiltersForAccountID(ctx, requestingAccount.ID)
+		if err != nil {
+			err = gtserror.Newf("couldn't retrieve filters for account %s: %w", requestingAccount.ID, err)
+			return nil, err
+		}
+
+		return tc.StatusToAPIStatus(ctx, status, requestingAccount, statusfilter.FilterContextHome, filters)
 	}
 }
 
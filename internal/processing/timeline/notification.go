@@ -28,6 +28,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/log"
 	"github.com/superseriousbusiness/gotosocial/internal/oauth"
+	"github.com/superseriousbusiness/gotosocial/internal/statusfilter"
 	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
@@ -43,6 +44,12 @@ func (p *Processor) NotificationsGet(ctx context.Context, authed *oauth.Auth, ma
 		return util.EmptyPageableResponse(), nil
 	}
 
+	filters, err := p.state.DB.GetFiltersForAccountID(ctx, authed.Account.ID)
+	if err != nil {
+		err = gtserror.Newf("couldn't retrieve filters for account %s: %w", authed.Account.ID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
 	var (
 		items          = make([]interface{}, 0, count)
 		nextMaxIDValue string
@@ -87,7 +94,10 @@ func (p *Processor) NotificationsGet(ctx context.Context, authed *oauth.Auth, ma
 			}
 		}
 
-		item, err := p.tc.NotificationToAPINotification(ctx, n)
+		item, err := p.tc.NotificationToAPINotification(ctx, n, filters)
+		if errors.Is(err, statusfilter.ErrHideStatus) {
+			continue
+		}
 		if err != nil {
 			log.Debugf(ctx, "skipping notification %s because it couldn't be converted to its api representation: %s", n.ID, err)
 			continue
@@ -121,9 +131,15 @@ func (p *Processor) NotificationGet(ctx context.Context, account *gtsmodel.Accou
 		return nil, gtserror.NewErrorNotFound(err)
 	}
 
-	apiNotif, err := p.tc.NotificationToAPINotification(ctx, notif)
+	filters, err := p.state.DB.GetFiltersForAccountID(ctx, account.ID)
 	if err != nil {
-		if errors.Is(err, db.ErrNoEntries) {
+		err = gtserror.Newf("couldn't retrieve filters for account %s: %w", account.ID, err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiNotif, err := p.tc.NotificationToAPINotification(ctx, notif, filters)
+	if err != nil {
+		if errors.Is(err, db.ErrNoEntries) || errors.Is(err, statusfilter.ErrHideStatus) {
 			return nil, gtserror.NewErrorNotFound(err)
 		}
 
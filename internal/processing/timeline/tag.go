@@ -27,6 +27,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/statusfilter"
 	"github.com/superseriousbusiness/gotosocial/internal/text"
 	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
@@ -111,6 +112,16 @@ func (p *Processor) packageTagResponse(
 		prevMinIDValue = statuses[0].ID
 	)
 
+	var filters []*gtsmodel.Filter
+	if requestingAcct != nil {
+		var err error
+		filters, err = p.state.DB.GetFiltersForAccountID(ctx, requestingAcct.ID)
+		if err != nil {
+			err = gtserror.Newf("couldn't retrieve filters for account %s: %w", requestingAcct.ID, err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+	}
+
 	for _, s := range statuses {
 		timelineable, err := p.filter.StatusTagTimelineable(ctx, requestingAcct, s)
 		if err != nil {
@@ -122,7 +133,10 @@ func (p *Processor) packageTagResponse(
 			continue
 		}
 
-		apiStatus, err := p.tc.StatusToAPIStatus(ctx, s, requestingAcct)
+		apiStatus, err := p.tc.StatusToAPIStatus(ctx, s, requestingAcct, statusfilter.FilterContextPublic, filters)
+		if errors.Is(err, statusfilter.ErrHideStatus) {
+			continue
+		}
 		if err != nil {
 			log.Errorf(ctx, "error converting to api status: %v", err)
 			continue
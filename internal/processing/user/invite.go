@@ -0,0 +1,149 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+)
+
+const (
+	// inviteCodeLength is the number of characters in an invite
+	// code, enough that codes can't feasibly be guessed.
+	inviteCodeLength = 16
+
+	// inviteCodeChars are the characters an invite code is made of.
+	inviteCodeChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// InvitesGet returns all invites created by the given user, newest first.
+func (p *Processor) InvitesGet(ctx context.Context, user *gtsmodel.User) ([]*apimodel.Invite, gtserror.WithCode) {
+	invites, err := p.state.DB.GetInvitesByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("db error getting invites: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	apiInvites := make([]*apimodel.Invite, 0, len(invites))
+	for _, invite := range invites {
+		apiInvites = append(apiInvites, p.tc.InviteToAPIInvite(ctx, invite))
+	}
+
+	return apiInvites, nil
+}
+
+// InviteCreate creates a new invite link for the given user, if they're allowed to.
+func (p *Processor) InviteCreate(ctx context.Context, user *gtsmodel.User, form *apimodel.InviteCreateRequest) (*apimodel.Invite, gtserror.WithCode) {
+	if !config.GetAccountsAllowInvites() && !*user.Admin && !*user.Moderator {
+		const help = "creating invites is not allowed on this instance"
+		err := gtserror.New(help)
+		return nil, gtserror.NewErrorForbidden(err, help)
+	}
+
+	if form.MaxUses < 0 {
+		const help = "max_uses must not be negative"
+		err := gtserror.New(help)
+		return nil, gtserror.NewErrorBadRequest(err, help)
+	}
+
+	if form.ExpiresIn < 0 {
+		const help = "expires_in must not be negative"
+		err := gtserror.New(help)
+		return nil, gtserror.NewErrorBadRequest(err, help)
+	}
+
+	code, err := newInviteCode()
+	if err != nil {
+		err := gtserror.Newf("error generating invite code: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	invite := &gtsmodel.Invite{
+		ID:         id.NewULID(),
+		Code:       code,
+		UserID:     user.ID,
+		User:       user,
+		MaxUses:    form.MaxUses,
+		Autofollow: &form.Autofollow,
+	}
+
+	if form.ExpiresIn > 0 {
+		invite.ExpiresAt = time.Now().Add(time.Duration(form.ExpiresIn) * time.Second)
+	}
+
+	if err := p.state.DB.PutInvite(ctx, invite); err != nil {
+		err := gtserror.Newf("db error storing invite: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return p.tc.InviteToAPIInvite(ctx, invite), nil
+}
+
+// InviteRevoke expires the invite with the given ID, which
+// must belong to the given user, so that it can't be used.
+func (p *Processor) InviteRevoke(ctx context.Context, user *gtsmodel.User, inviteID string) (*apimodel.Invite, gtserror.WithCode) {
+	invite, err := p.state.DB.GetInviteByID(ctx, inviteID)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("db error getting invite: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	if invite == nil || invite.UserID != user.ID {
+		err := gtserror.Newf("invite %s not found for user %s", inviteID, user.ID)
+		return nil, gtserror.NewErrorNotFound(err)
+	}
+
+	now := time.Now()
+	if !invite.Valid(now) {
+		// Already can't be used,
+		// nothing else to do.
+		return p.tc.InviteToAPIInvite(ctx, invite), nil
+	}
+
+	invite.ExpiresAt = now
+	if err := p.state.DB.UpdateInvite(ctx, invite, "expires_at"); err != nil {
+		err := gtserror.Newf("db error updating invite: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return p.tc.InviteToAPIInvite(ctx, invite), nil
+}
+
+// newInviteCode returns a new random invite code.
+func newInviteCode() (string, error) {
+	code := make([]byte, inviteCodeLength)
+	max := big.NewInt(int64(len(inviteCodeChars)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = inviteCodeChars[n.Int64()]
+	}
+	return string(code), nil
+}
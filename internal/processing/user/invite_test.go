@@ -0,0 +1,96 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+)
+
+type InviteTestSuite struct {
+	UserStandardTestSuite
+}
+
+func (suite *InviteTestSuite) TestInvitesGet() {
+	invites, errWithCode := suite.user.InvitesGet(context.Background(), suite.testUsers["admin_account"])
+	suite.NoError(errWithCode)
+	suite.Len(invites, 1)
+
+	invite := invites[0]
+	suite.Equal("wkTbG4Ya", invite.Code)
+	suite.Equal("http://localhost:8080/auth/sign_up?invite=wkTbG4Ya", invite.URL)
+	suite.Equal(5, *invite.MaxUses)
+	suite.Equal(1, invite.Uses)
+	suite.Nil(invite.ExpiresAt)
+	suite.True(invite.Autofollow)
+	suite.False(invite.Expired)
+}
+
+func (suite *InviteTestSuite) TestInviteCreateNotAllowed() {
+	_, errWithCode := suite.user.InviteCreate(context.Background(), suite.testUsers["local_account_2"], &apimodel.InviteCreateRequest{})
+	suite.Equal(http.StatusForbidden, errWithCode.Code())
+}
+
+func (suite *InviteTestSuite) TestInviteCreate() {
+	ctx := context.Background()
+	config.SetAccountsAllowInvites(true)
+	user := suite.testUsers["local_account_2"]
+
+	invite, errWithCode := suite.user.InviteCreate(ctx, user, &apimodel.InviteCreateRequest{
+		MaxUses:   1,
+		ExpiresIn: 3600,
+	})
+	suite.NoError(errWithCode)
+	suite.Len(invite.Code, 16)
+	suite.Equal(1, *invite.MaxUses)
+	suite.NotNil(invite.ExpiresAt)
+	suite.False(invite.Autofollow)
+	suite.False(invite.Expired)
+
+	dbInvite, err := suite.db.GetInviteByCode(ctx, invite.Code)
+	suite.NoError(err)
+	suite.Equal(user.ID, dbInvite.UserID)
+}
+
+func (suite *InviteTestSuite) TestInviteRevoke() {
+	ctx := context.Background()
+	user := suite.testUsers["admin_account"]
+
+	invite, errWithCode := suite.user.InviteRevoke(ctx, user, "01H9PQ6Y3W6N1DHZ8Y8T3QNMB2")
+	suite.NoError(errWithCode)
+	suite.True(invite.Expired)
+	suite.NotNil(invite.ExpiresAt)
+
+	dbInvite, err := suite.db.GetInviteByID(ctx, "01H9PQ6Y3W6N1DHZ8Y8T3QNMB2")
+	suite.NoError(err)
+	suite.False(dbInvite.ExpiresAt.IsZero())
+}
+
+func (suite *InviteTestSuite) TestInviteRevokeNotOwn() {
+	_, errWithCode := suite.user.InviteRevoke(context.Background(), suite.testUsers["local_account_2"], "01H9PQ6Y3W6N1DHZ8Y8T3QNMB2")
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func TestInviteTestSuite(t *testing.T) {
+	suite.Run(t, &InviteTestSuite{})
+}
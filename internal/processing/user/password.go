@@ -19,13 +19,32 @@ package user
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/email"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/uris"
 	"github.com/superseriousbusiness/gotosocial/internal/validate"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// resetPasswordTokenTTL is how long a
+	// password reset token remains valid.
+	resetPasswordTokenTTL = time.Hour
+
+	// resetPasswordResendInterval is the minimum time
+	// between sending two password reset emails to the
+	// same user, to avoid the form being used for spam.
+	resetPasswordResendInterval = 5 * time.Minute
+)
+
 // PasswordChange processes a password change request for the given user.
 func (p *Processor) PasswordChange(ctx context.Context, user *gtsmodel.User, oldPassword string, newPassword string) gtserror.WithCode {
 	// Ensure provided oldPassword is the correct current password.
@@ -68,3 +87,211 @@ func (p *Processor) PasswordChange(ctx context.Context, user *gtsmodel.User, old
 
 	return nil
 }
+
+// PasswordResetRequest queues sending a password reset email to the user with
+// the given confirmed email address. To avoid revealing which email addresses
+// belong to accounts on this instance, the user is looked up and emailed in the
+// background, so the request takes the same time whether one is found or not.
+func (p *Processor) PasswordResetRequest(ctx context.Context, emailAddress string) gtserror.WithCode {
+	if config.GetOIDCEnabled() {
+		const help = "password reset is not available when OIDC is enabled"
+		err := gtserror.New(help)
+		return gtserror.NewErrorNotFound(err, help)
+	}
+
+	if emailAddress == "" {
+		const help = "no email address provided"
+		err := gtserror.New(help)
+		return gtserror.NewErrorBadRequest(err, help)
+	}
+
+	p.state.Workers.ClientAPI.Enqueue(func(ctx context.Context) {
+		if err := p.passwordResetSend(ctx, emailAddress); err != nil {
+			log.Errorf(ctx, "error sending password reset email: %v", err)
+		}
+	})
+
+	return nil
+}
+
+// passwordResetSend sends a password reset email to the user with the given
+// confirmed email address, if there is one that's allowed to reset it.
+func (p *Processor) passwordResetSend(ctx context.Context, emailAddress string) error {
+	user, err := p.state.DB.GetUserByEmailAddress(ctx, emailAddress)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return gtserror.Newf("db error getting user: %w", err)
+	}
+
+	if user == nil {
+		log.Debugf(ctx, "no user found with email address %s", emailAddress)
+		return nil
+	}
+
+	if *user.Disabled || !*user.Approved {
+		log.Debugf(ctx, "user %s is disabled or not approved", user.ID)
+		return nil
+	}
+
+	if user.Account == nil {
+		user.Account, err = p.state.DB.GetAccountByID(ctx, user.AccountID)
+		if err != nil {
+			return gtserror.Newf("db error getting account: %w", err)
+		}
+	}
+
+	if !user.Account.SuspendedAt.IsZero() {
+		log.Debugf(ctx, "account %s is suspended", user.AccountID)
+		return nil
+	}
+
+	if !user.ResetPasswordSentAt.IsZero() &&
+		time.Since(user.ResetPasswordSentAt) < resetPasswordResendInterval {
+		log.Debugf(ctx, "password reset email recently sent to user %s", user.ID)
+		return nil
+	}
+
+	// Pull our instance entry from the
+	// database to greet the user nicely.
+	instance, err := p.state.DB.GetInstance(ctx, config.GetHost())
+	if err != nil {
+		return gtserror.Newf("db error getting instance: %w", err)
+	}
+
+	// Generate a new single-use token; a uuid
+	// has enough random bits to not be guessable.
+	token := uuid.NewString()
+
+	resetData := email.ResetData{
+		Username:     user.Account.Username,
+		InstanceURL:  instance.URI,
+		InstanceName: instance.Title,
+		ResetLink:    uris.GenerateURIForPasswordReset(token),
+	}
+
+	// Store the token on the user entry before
+	// sending it, so that the link in the email
+	// works as soon as the email is received.
+	now := time.Now()
+	user.ResetPasswordToken = token
+	user.ResetPasswordSentAt = now
+	user.LastEmailedAt = now
+
+	if err := p.state.DB.UpdateUser(
+		ctx, user,
+		"reset_password_token",
+		"reset_password_sent_at",
+		"last_emailed_at",
+	); err != nil {
+		return gtserror.Newf("db error updating user: %w", err)
+	}
+
+	if err := p.emailSender.SendResetEmail(user.Email, resetData); err != nil {
+		return gtserror.Newf("error sending reset email to user %s: %w", user.ID, err)
+	}
+
+	return nil
+}
+
+// PasswordResetCheck checks whether the given password reset
+// token is valid, returning the user it belongs to if so.
+func (p *Processor) PasswordResetCheck(ctx context.Context, token string) (*gtsmodel.User, gtserror.WithCode) {
+	if config.GetOIDCEnabled() {
+		const help = "password reset is not available when OIDC is enabled"
+		err := gtserror.New(help)
+		return nil, gtserror.NewErrorNotFound(err, help)
+	}
+
+	if token == "" {
+		const help = "no token provided"
+		err := gtserror.New(help)
+		return nil, gtserror.NewErrorBadRequest(err, help)
+	}
+
+	user, err := p.state.DB.GetUserByResetPasswordToken(ctx, token)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("db error getting user: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	const help = "password reset link is invalid or has expired"
+
+	if user == nil {
+		err := gtserror.New("no user found with reset password token")
+		return nil, gtserror.NewErrorNotFound(err, help)
+	}
+
+	if time.Since(user.ResetPasswordSentAt) > resetPasswordTokenTTL {
+		err := gtserror.Newf("reset password token for user %s expired", user.ID)
+		return nil, gtserror.NewErrorNotFound(err, help)
+	}
+
+	if *user.Disabled {
+		err := gtserror.Newf("user %s is disabled", user.ID)
+		return nil, gtserror.NewErrorForbidden(err, help)
+	}
+
+	return user, nil
+}
+
+// PasswordReset sets a new password for the user that the given password
+// reset token belongs to. The token is invalidated, and all of the user's
+// existing OAuth tokens are revoked so that any other sessions are logged out.
+func (p *Processor) PasswordReset(ctx context.Context, token string, newPassword string) gtserror.WithCode {
+	user, errWithCode := p.PasswordResetCheck(ctx, token)
+	if errWithCode != nil {
+		return errWithCode
+	}
+
+	// Ensure new password is strong enough.
+	if err := validate.Password(newPassword); err != nil {
+		return gtserror.NewErrorBadRequest(err, err.Error())
+	}
+
+	// Hash the new password.
+	encryptedPassword, err := bcrypt.GenerateFromPassword(
+		[]byte(newPassword),
+		bcrypt.DefaultCost,
+	)
+	if err != nil {
+		err := gtserror.Newf("%w", err)
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	// Set new password on user, clearing the
+	// reset token only if it's not been used
+	// meanwhile by a concurrent request.
+	user.EncryptedPassword = string(encryptedPassword)
+
+	err = p.state.DB.UpdateUserPasswordByResetToken(ctx, user, token)
+	if errors.Is(err, db.ErrNoEntries) {
+		const help = "password reset link is invalid or has expired"
+		err := gtserror.Newf("reset password token for user %s already used", user.ID)
+		return gtserror.NewErrorNotFound(err, help)
+	}
+
+	if err != nil {
+		err := gtserror.Newf("db error updating user: %w", err)
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	// Revoke all existing tokens for this user.
+	tokens := []*gtsmodel.Token{}
+	if err := p.state.DB.GetWhere(ctx, []db.Where{{Key: "user_id", Value: user.ID}}, &tokens); err != nil && !errors.Is(err, db.ErrNoEntries) {
+		err := gtserror.Newf("db error getting tokens: %w", err)
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	for _, t := range tokens {
+		if err := p.state.DB.DeleteWebPushSubscriptionByTokenID(ctx, t.ID); err != nil {
+			err := gtserror.Newf("db error deleting web push subscription: %w", err)
+			return gtserror.NewErrorInternalError(err)
+		}
+
+		if err := p.state.DB.DeleteByID(ctx, t.ID, t); err != nil {
+			err := gtserror.Newf("db error deleting token: %w", err)
+			return gtserror.NewErrorInternalError(err)
+		}
+	}
+
+	return nil
+}
@@ -19,10 +19,13 @@ package user_test
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -89,3 +92,121 @@ func (suite *ChangePasswordTestSuite) TestChangePasswordWeakNew() {
 func TestChangePasswordTestSuite(t *testing.T) {
 	suite.Run(t, &ChangePasswordTestSuite{})
 }
+
+type ResetPasswordTestSuite struct {
+	UserStandardTestSuite
+}
+
+// waitForWorkers waits until the client API
+// worker has got through everything queued so far.
+func (suite *ResetPasswordTestSuite) waitForWorkers() {
+	done := make(chan struct{})
+	suite.state.Workers.ClientAPI.Enqueue(func(context.Context) {
+		close(done)
+	})
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		suite.FailNow("timed out waiting for workers")
+	}
+}
+
+func (suite *ResetPasswordTestSuite) TestPasswordResetRequest() {
+	ctx := context.Background()
+
+	errWithCode := suite.user.PasswordResetRequest(ctx, "zork@example.org")
+	suite.NoError(errWithCode)
+	suite.waitForWorkers()
+
+	// a token should be set on zork
+	dbUser, err := suite.db.GetUserByEmailAddress(ctx, "zork@example.org")
+	suite.NoError(err)
+	token := dbUser.ResetPasswordToken
+	suite.NotEmpty(token)
+	suite.WithinDuration(time.Now(), dbUser.ResetPasswordSentAt, 1*time.Minute)
+
+	// email should contain the token
+	suite.Len(suite.sentEmails, 1)
+	email, ok := suite.sentEmails["zork@example.org"]
+	suite.True(ok)
+	suite.Contains(email, "Subject: GoToSocial Password Reset\r\n")
+	suite.Contains(email, fmt.Sprintf("http://localhost:8080/auth/reset_password?token=%s\r\n", token))
+
+	// asking again straight away should not send another email
+	errWithCode = suite.user.PasswordResetRequest(ctx, "zork@example.org")
+	suite.NoError(errWithCode)
+	suite.waitForWorkers()
+
+	dbUser, err = suite.db.GetUserByEmailAddress(ctx, "zork@example.org")
+	suite.NoError(err)
+	suite.Equal(token, dbUser.ResetPasswordToken)
+}
+
+func (suite *ResetPasswordTestSuite) TestPasswordResetRequestUnknownEmail() {
+	errWithCode := suite.user.PasswordResetRequest(context.Background(), "nobody@example.org")
+	suite.NoError(errWithCode)
+	suite.waitForWorkers()
+	suite.Empty(suite.sentEmails)
+}
+
+func (suite *ResetPasswordTestSuite) TestPasswordReset() {
+	ctx := context.Background()
+	user := suite.testUsers["local_account_1"]
+
+	user.ResetPasswordToken = "1d1aa44b-afa4-49c8-ac4b-eceb61715cc6"
+	user.ResetPasswordSentAt = time.Now().Add(-5 * time.Minute)
+	err := suite.db.UpdateUser(ctx, user, "reset_password_token", "reset_password_sent_at")
+	suite.NoError(err)
+
+	errWithCode := suite.user.PasswordReset(ctx, "1d1aa44b-afa4-49c8-ac4b-eceb61715cc6", "verygoodnewpassword")
+	suite.NoError(errWithCode)
+
+	// get user from the db again
+	dbUser := &gtsmodel.User{}
+	err = suite.db.GetByID(ctx, user.ID, dbUser)
+	suite.NoError(err)
+
+	// check the password has changed and token is cleared
+	err = bcrypt.CompareHashAndPassword([]byte(dbUser.EncryptedPassword), []byte("verygoodnewpassword"))
+	suite.NoError(err)
+	suite.Empty(dbUser.ResetPasswordToken)
+	suite.True(dbUser.ResetPasswordSentAt.IsZero())
+
+	// zork's oauth tokens should all be revoked
+	tokens := []*gtsmodel.Token{}
+	err = suite.db.GetWhere(ctx, []db.Where{{Key: "user_id", Value: user.ID}}, &tokens)
+	if err != nil {
+		suite.ErrorIs(err, db.ErrNoEntries)
+	}
+	suite.Empty(tokens)
+
+	// the token should not be usable a second time
+	errWithCode = suite.user.PasswordReset(ctx, "1d1aa44b-afa4-49c8-ac4b-eceb61715cc6", "anotherverygoodnewpassword")
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+}
+
+func (suite *ResetPasswordTestSuite) TestPasswordResetExpiredToken() {
+	ctx := context.Background()
+	user := suite.testUsers["local_account_1"]
+
+	user.ResetPasswordToken = "1d1aa44b-afa4-49c8-ac4b-eceb61715cc6"
+	user.ResetPasswordSentAt = time.Now().Add(-2 * time.Hour)
+	err := suite.db.UpdateUser(ctx, user, "reset_password_token", "reset_password_sent_at")
+	suite.NoError(err)
+
+	errWithCode := suite.user.PasswordReset(ctx, "1d1aa44b-afa4-49c8-ac4b-eceb61715cc6", "verygoodnewpassword")
+	suite.Equal(http.StatusNotFound, errWithCode.Code())
+	suite.Equal("Not Found: password reset link is invalid or has expired", errWithCode.Safe())
+
+	// check the password has not changed
+	dbUser := &gtsmodel.User{}
+	err = suite.db.GetByID(ctx, user.ID, dbUser)
+	suite.NoError(err)
+	err = bcrypt.CompareHashAndPassword([]byte(dbUser.EncryptedPassword), []byte("password"))
+	suite.NoError(err)
+}
+
+func TestResetPasswordTestSuite(t *testing.T) {
+	suite.Run(t, &ResetPasswordTestSuite{})
+}
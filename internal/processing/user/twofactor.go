@@ -0,0 +1,418 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package user
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/totp"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// twoFactorRecoveryCodeCount is the number of one-time
+	// recovery codes generated when enabling two-factor auth.
+	twoFactorRecoveryCodeCount = 10
+
+	// twoFactorRecoveryCodeSize is the number of random
+	// bytes in each recovery code, before encoding.
+	twoFactorRecoveryCodeSize = 10
+
+	// twoFactorMaxAttempts is the number of codes that can be
+	// entered in a row without a correct one, before the user
+	// is locked out of two-factor checks for twoFactorLockout.
+	twoFactorMaxAttempts = 5
+
+	// twoFactorLockout is how long users are locked out
+	// for after twoFactorMaxAttempts incorrect codes.
+	twoFactorLockout = 15 * time.Minute
+)
+
+// recoveryCodeEncoding is used to encode
+// recovery codes in a way that's easy to type.
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TwoFactorGet returns the two-factor authentication status of the given user.
+func (p *Processor) TwoFactorGet(user *gtsmodel.User) *apimodel.TwoFactor {
+	twoFactor := &apimodel.TwoFactor{}
+	if !user.TwoFactorEnabledAt.IsZero() {
+		enabledAt := util.FormatISO8601(user.TwoFactorEnabledAt)
+		twoFactor.EnabledAt = &enabledAt
+	}
+	return twoFactor
+}
+
+// TwoFactorSetup generates a new TOTP secret for the given user, to be added
+// to an authenticator app. Two-factor authentication is not enabled until a
+// code generated from the secret is passed to TwoFactorEnable.
+func (p *Processor) TwoFactorSetup(
+	ctx context.Context,
+	user *gtsmodel.User,
+	account *gtsmodel.Account,
+) (*apimodel.TwoFactorSetup, gtserror.WithCode) {
+	if config.GetOIDCEnabled() {
+		const help = "two-factor authentication is managed by your OIDC provider"
+		err := gtserror.New(help)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, help)
+	}
+
+	if !user.TwoFactorEnabledAt.IsZero() {
+		const help = "two-factor authentication is already enabled"
+		err := gtserror.New(help)
+		return nil, gtserror.NewErrorConflict(err, help)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		err := gtserror.Newf("error generating secret: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	encrypted, err := p.encryptTwoFactorSecret(ctx, secret)
+	if err != nil {
+		err := gtserror.Newf("error encrypting secret: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	user.TwoFactorSecret = encrypted
+	if err := p.state.DB.UpdateUser(
+		ctx, user,
+		"two_factor_secret",
+	); err != nil {
+		err := gtserror.Newf("db error updating user: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	accountName := account.Username + "@" + config.GetAccountDomain()
+	return &apimodel.TwoFactorSetup{
+		Secret: secret,
+		URI:    totp.URI(config.GetHost(), accountName, secret),
+	}, nil
+}
+
+// TwoFactorEnable enables two-factor authentication for the given user, if the
+// given code is valid for the secret generated by TwoFactorSetup. A set of
+// one-time recovery codes is returned, which will not be shown again.
+func (p *Processor) TwoFactorEnable(
+	ctx context.Context,
+	user *gtsmodel.User,
+	code string,
+) (*apimodel.TwoFactorRecoveryCodes, gtserror.WithCode) {
+	if !user.TwoFactorEnabledAt.IsZero() {
+		const help = "two-factor authentication is already enabled"
+		err := gtserror.New(help)
+		return nil, gtserror.NewErrorConflict(err, help)
+	}
+
+	if user.TwoFactorSecret == "" {
+		const help = "two-factor authentication has not been set up yet"
+		err := gtserror.New(help)
+		return nil, gtserror.NewErrorUnprocessableEntity(err, help)
+	}
+
+	if errWithCode := p.checkTOTP(ctx, user, code); errWithCode != nil {
+		return nil, errWithCode
+	}
+
+	codes := make([]string, twoFactorRecoveryCodeCount)
+	hashes := make([]string, twoFactorRecoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, twoFactorRecoveryCodeSize)
+		if _, err := rand.Read(b); err != nil {
+			err := gtserror.Newf("error generating recovery code: %w", err)
+			return nil, gtserror.NewErrorInternalError(err)
+		}
+
+		// Split the code into groups of four
+		// characters to make it easier to copy.
+		enc := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+		groups := make([]string, 0, len(enc)/4)
+		for len(enc) > 0 {
+			n := 4
+			if len(enc) < n {
+				n = len(enc)
+			}
+			groups = append(groups, enc[:n])
+			enc = enc[n:]
+		}
+
+		codes[i] = strings.Join(groups, "-")
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	user.TwoFactorEnabledAt = time.Now()
+	user.TwoFactorRecoveryCodes = hashes
+	if err := p.state.DB.UpdateUser(
+		ctx, user,
+		"two_factor_enabled_at",
+		"two_factor_recovery_codes",
+	); err != nil {
+		err := gtserror.Newf("db error updating user: %w", err)
+		return nil, gtserror.NewErrorInternalError(err)
+	}
+
+	return &apimodel.TwoFactorRecoveryCodes{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// TwoFactorDisable disables two-factor authentication for the given user,
+// after checking that the given password is correct, and that the given code
+// is either a code from their authenticator app or one of their recovery codes.
+func (p *Processor) TwoFactorDisable(ctx context.Context, user *gtsmodel.User, password string, code string) gtserror.WithCode {
+	if err := bcrypt.CompareHashAndPassword([]byte(user.EncryptedPassword), []byte(password)); err != nil {
+		err := gtserror.Newf("%w", err)
+		return gtserror.NewErrorUnauthorized(err, "password was incorrect")
+	}
+
+	// A stolen password alone
+	// shouldn't be enough to
+	// turn off the second factor.
+	if errWithCode := p.TwoFactorCheck(ctx, user, code); errWithCode != nil {
+		return errWithCode
+	}
+
+	user.TwoFactorSecret = ""
+	user.TwoFactorEnabledAt = time.Time{}
+	user.TwoFactorRecoveryCodes = nil
+	if err := p.state.DB.UpdateUser(
+		ctx, user,
+		"two_factor_secret",
+		"two_factor_enabled_at",
+		"two_factor_recovery_codes",
+	); err != nil {
+		err := gtserror.Newf("db error updating user: %w", err)
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	return nil
+}
+
+// TwoFactorCheck checks the given code as the second step of signing in as the
+// given user. The code may be either a code from the user's authenticator app,
+// or one of their recovery codes, in which case the recovery code is used up.
+func (p *Processor) TwoFactorCheck(ctx context.Context, user *gtsmodel.User, code string) gtserror.WithCode {
+	if user.TwoFactorEnabledAt.IsZero() {
+		const help = "two-factor authentication is not enabled"
+		err := gtserror.New(help)
+		return gtserror.NewErrorUnprocessableEntity(err, help)
+	}
+
+	// Count this attempt before checking the code, so that
+	// codes can't be guessed by trying lots of them at once.
+	if err := p.state.DB.ClaimTwoFactorAttempt(ctx, user, twoFactorMaxAttempts, twoFactorLockout); err != nil {
+		if !errors.Is(err, db.ErrNoEntries) {
+			err := gtserror.Newf("db error counting two-factor attempt for user %s: %w", user.ID, err)
+			return gtserror.NewErrorInternalError(err)
+		}
+
+		const help = "too many incorrect codes, please try again later"
+		err := gtserror.Newf("user %s is locked out of two-factor checks", user.ID)
+		return gtserror.NewErrorTooManyRequests(err, help)
+	}
+
+	if errWithCode := p.checkTwoFactorCode(ctx, user, code); errWithCode != nil {
+		return errWithCode
+	}
+
+	// Code was correct, so
+	// start the count over.
+	user.TwoFactorAttempts = 0
+	if err := p.state.DB.UpdateUser(
+		ctx, user,
+		"two_factor_attempts",
+	); err != nil {
+		err := gtserror.Newf("db error updating user: %w", err)
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	return nil
+}
+
+// checkTwoFactorCode checks the given code against the user's TOTP secret
+// if it looks like a TOTP code, or against their recovery codes otherwise.
+func (p *Processor) checkTwoFactorCode(ctx context.Context, user *gtsmodel.User, code string) gtserror.WithCode {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return p.checkTOTP(ctx, user, code)
+	}
+
+	// Not the right length for a TOTP code,
+	// so check if it's one of the recovery codes.
+	hash := hashRecoveryCode(code)
+	for {
+		i := -1
+		for j, h := range user.TwoFactorRecoveryCodes {
+			if subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1 {
+				i = j
+			}
+		}
+
+		if i < 0 {
+			err := gtserror.Newf("recovery code for user %s was incorrect", user.ID)
+			return gtserror.NewErrorUnauthorized(err, "code was incorrect")
+		}
+
+		// Recovery codes are single-use,
+		// so remove this one from the user.
+		codes := make([]string, 0, len(user.TwoFactorRecoveryCodes)-1)
+		codes = append(codes, user.TwoFactorRecoveryCodes[:i]...)
+		codes = append(codes, user.TwoFactorRecoveryCodes[i+1:]...)
+
+		err := p.state.DB.UpdateUserTwoFactorRecoveryCodes(ctx, user, codes)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, db.ErrNoEntries) {
+			err := gtserror.Newf("db error updating user: %w", err)
+			return gtserror.NewErrorInternalError(err)
+		}
+
+		// Codes were changed meanwhile, perhaps by this
+		// one being used, so check again against fresh ones.
+		dbUser, err := p.state.DB.GetUserByID(ctx, user.ID)
+		if err != nil {
+			err := gtserror.Newf("db error getting user %s: %w", user.ID, err)
+			return gtserror.NewErrorInternalError(err)
+		}
+		user.TwoFactorRecoveryCodes = dbUser.TwoFactorRecoveryCodes
+	}
+}
+
+// checkTOTP checks the given code against the user's TOTP secret.
+func (p *Processor) checkTOTP(ctx context.Context, user *gtsmodel.User, code string) gtserror.WithCode {
+	secret, err := p.decryptTwoFactorSecret(ctx, user.TwoFactorSecret)
+	if err != nil {
+		err := gtserror.Newf("error decrypting secret for user %s: %w", user.ID, err)
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	counter, valid, err := totp.Validate(secret, code, time.Now())
+	if err != nil {
+		err := gtserror.Newf("error validating code for user %s: %w", user.ID, err)
+		return gtserror.NewErrorInternalError(err)
+	}
+
+	if !valid {
+		err := gtserror.Newf("code for user %s was incorrect", user.ID)
+		return gtserror.NewErrorUnauthorized(err, "code was incorrect")
+	}
+
+	// Each code may only be used once, so record
+	// its counter, rejecting it if this code (or
+	// a later one) has been accepted already.
+	if err := p.state.DB.UpdateUserTwoFactorCounter(ctx, user, int64(counter)); err != nil {
+		if !errors.Is(err, db.ErrNoEntries) {
+			err := gtserror.Newf("db error updating two-factor counter for user %s: %w", user.ID, err)
+			return gtserror.NewErrorInternalError(err)
+		}
+
+		err := gtserror.Newf("code for user %s was already used", user.ID)
+		return gtserror.NewErrorUnauthorized(err, "code was already used")
+	}
+
+	return nil
+}
+
+// twoFactorCipher returns an AEAD cipher for encrypting TOTP
+// secrets at rest, keyed with the router session encryption key.
+func (p *Processor) twoFactorCipher(ctx context.Context) (cipher.AEAD, error) {
+	session, err := p.state.DB.GetSession(ctx)
+	if err != nil {
+		return nil, gtserror.Newf("db error getting router session: %w", err)
+	}
+
+	block, err := aes.NewCipher(session.Crypt)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// encryptTwoFactorSecret encrypts the given TOTP secret for
+// storage, returning it base64 encoded with a random nonce.
+func (p *Processor) encryptTwoFactorSecret(ctx context.Context, secret string) (string, error) {
+	gcm, err := p.twoFactorCipher(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptTwoFactorSecret reverses encryptTwoFactorSecret.
+func (p *Processor) decryptTwoFactorSecret(ctx context.Context, encrypted string) (string, error) {
+	gcm, err := p.twoFactorCipher(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("encrypted secret too short")
+	}
+
+	nonce, sealed := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	secret, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(secret), nil
+}
+
+// hashRecoveryCode returns the hash of the given recovery code for
+// storage. Since recovery codes are long and random, a fast hash is
+// fine here, and avoids bcrypting every stored code on each check.
+// Case and separators are ignored to be forgiving of typos.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,220 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/totp"
+)
+
+type TwoFactorTestSuite struct {
+	UserStandardTestSuite
+}
+
+func (suite *TwoFactorTestSuite) TestTwoFactorEnableDisable() {
+	ctx := context.Background()
+	user := suite.testUsers["local_account_1"]
+	account := suite.testAccounts["local_account_1"]
+
+	suite.Nil(suite.user.TwoFactorGet(user).EnabledAt)
+
+	setup, errWithCode := suite.user.TwoFactorSetup(ctx, user, account)
+	suite.NoError(errWithCode)
+	suite.Contains(setup.URI, "otpauth://totp/localhost:8080:the_mighty_zork@localhost:8080?")
+	suite.Contains(setup.URI, "secret="+setup.Secret)
+
+	// the secret should not be stored in plaintext
+	dbUser, err := suite.db.GetUserByID(ctx, user.ID)
+	suite.NoError(err)
+	suite.NotEmpty(dbUser.TwoFactorSecret)
+	suite.NotContains(dbUser.TwoFactorSecret, setup.Secret)
+	suite.True(dbUser.TwoFactorEnabledAt.IsZero())
+
+	// enabling with the wrong code should fail
+	_, errWithCode = suite.user.TwoFactorEnable(ctx, dbUser, "000000")
+	suite.Equal(http.StatusUnauthorized, errWithCode.Code())
+
+	code, err := totp.Code(setup.Secret, time.Now())
+	suite.NoError(err)
+
+	codes, errWithCode := suite.user.TwoFactorEnable(ctx, dbUser, code)
+	suite.NoError(errWithCode)
+	suite.Len(codes.RecoveryCodes, 10)
+	suite.Len(codes.RecoveryCodes[0], 19)
+	suite.NotNil(suite.user.TwoFactorGet(dbUser).EnabledAt)
+
+	// setting up again while enabled should fail
+	_, errWithCode = suite.user.TwoFactorSetup(ctx, dbUser, account)
+	suite.Equal(http.StatusConflict, errWithCode.Code())
+
+	nextCode, err := totp.Code(setup.Secret, time.Now().Add(totp.Period*time.Second))
+	suite.NoError(err)
+
+	// disabling with the wrong password should fail
+	errWithCode = suite.user.TwoFactorDisable(ctx, dbUser, "ooooopsydoooopsy", nextCode)
+	suite.Equal(http.StatusUnauthorized, errWithCode.Code())
+	suite.Equal("Unauthorized: password was incorrect", errWithCode.Safe())
+
+	// as should disabling with the wrong code
+	errWithCode = suite.user.TwoFactorDisable(ctx, dbUser, "password", "000000")
+	suite.Equal(http.StatusUnauthorized, errWithCode.Code())
+	suite.Equal("Unauthorized: code was incorrect", errWithCode.Safe())
+
+	errWithCode = suite.user.TwoFactorDisable(ctx, dbUser, "password", nextCode)
+	suite.NoError(errWithCode)
+
+	dbUser, err = suite.db.GetUserByID(ctx, user.ID)
+	suite.NoError(err)
+	suite.Empty(dbUser.TwoFactorSecret)
+	suite.True(dbUser.TwoFactorEnabledAt.IsZero())
+	suite.Empty(dbUser.TwoFactorRecoveryCodes)
+}
+
+func (suite *TwoFactorTestSuite) TestTwoFactorCheck() {
+	ctx := context.Background()
+	user := suite.testUsers["local_account_1"]
+	account := suite.testAccounts["local_account_1"]
+
+	setup, errWithCode := suite.user.TwoFactorSetup(ctx, user, account)
+	suite.NoError(errWithCode)
+
+	code, err := totp.Code(setup.Secret, time.Now())
+	suite.NoError(err)
+
+	codes, errWithCode := suite.user.TwoFactorEnable(ctx, user, code)
+	suite.NoError(errWithCode)
+
+	// the code used to enable two-factor
+	// auth can't be used again to sign in
+	errWithCode = suite.user.TwoFactorCheck(ctx, user, code)
+	suite.Equal(http.StatusUnauthorized, errWithCode.Code())
+	suite.Equal("Unauthorized: code was already used", errWithCode.Safe())
+
+	// but the next code from the app should work
+	now := time.Now()
+	nextCode, err := totp.Code(setup.Secret, now.Add(totp.Period*time.Second))
+	suite.NoError(err)
+	suite.NoError(suite.user.TwoFactorCheck(ctx, user, nextCode))
+
+	// only once though
+	errWithCode = suite.user.TwoFactorCheck(ctx, user, nextCode)
+	suite.Equal(http.StatusUnauthorized, errWithCode.Code())
+	suite.Equal("Unauthorized: code was already used", errWithCode.Safe())
+
+	// and earlier codes that are still within
+	// the allowed skew don't work any more either
+	prevCode, err := totp.Code(setup.Secret, now.Add(-totp.Period*time.Second))
+	suite.NoError(err)
+	errWithCode = suite.user.TwoFactorCheck(ctx, user, prevCode)
+	suite.Equal(http.StatusUnauthorized, errWithCode.Code())
+
+	// a wrong code should not
+	errWithCode = suite.user.TwoFactorCheck(ctx, user, "000000")
+	suite.Equal(http.StatusUnauthorized, errWithCode.Code())
+	suite.Equal("Unauthorized: code was incorrect", errWithCode.Safe())
+
+	// a recovery code should work, ignoring case
+	suite.NoError(suite.user.TwoFactorCheck(ctx, user, " "+strings.ToUpper(codes.RecoveryCodes[3])+" "))
+
+	dbUser, err := suite.db.GetUserByID(ctx, user.ID)
+	suite.NoError(err)
+	suite.Len(dbUser.TwoFactorRecoveryCodes, 9)
+
+	// but only once
+	errWithCode = suite.user.TwoFactorCheck(ctx, dbUser, codes.RecoveryCodes[3])
+	suite.Equal(http.StatusUnauthorized, errWithCode.Code())
+
+	// other recovery codes still work
+	stale := new(gtsmodel.User)
+	*stale = *dbUser
+	suite.NoError(suite.user.TwoFactorCheck(ctx, dbUser, codes.RecoveryCodes[4]))
+
+	// and can't be used again by a request
+	// that loaded the user before they were
+	errWithCode = suite.user.TwoFactorCheck(ctx, stale, codes.RecoveryCodes[4])
+	suite.Equal(http.StatusUnauthorized, errWithCode.Code())
+
+	// while such a request can still use others
+	suite.NoError(suite.user.TwoFactorCheck(ctx, stale, codes.RecoveryCodes[5]))
+
+	dbUser, err = suite.db.GetUserByID(ctx, user.ID)
+	suite.NoError(err)
+	suite.Len(dbUser.TwoFactorRecoveryCodes, 7)
+}
+
+func (suite *TwoFactorTestSuite) TestTwoFactorCheckLockout() {
+	ctx := context.Background()
+	user := suite.testUsers["local_account_1"]
+	account := suite.testAccounts["local_account_1"]
+
+	setup, errWithCode := suite.user.TwoFactorSetup(ctx, user, account)
+	suite.NoError(errWithCode)
+
+	now := time.Now()
+	enableCode, err := totp.Code(setup.Secret, now.Add(-totp.Period*time.Second))
+	suite.NoError(err)
+
+	_, errWithCode = suite.user.TwoFactorEnable(ctx, user, enableCode)
+	suite.NoError(errWithCode)
+
+	// five wrong codes in a row are allowed...
+	for i := 0; i < 5; i++ {
+		errWithCode = suite.user.TwoFactorCheck(ctx, user, "000000")
+		suite.Equal(http.StatusUnauthorized, errWithCode.Code())
+	}
+
+	// ...but then even the right code is refused
+	code, err := totp.Code(setup.Secret, now)
+	suite.NoError(err)
+
+	errWithCode = suite.user.TwoFactorCheck(ctx, user, code)
+	suite.Equal(http.StatusTooManyRequests, errWithCode.Code())
+	suite.Equal("Too Many Requests: too many incorrect codes, please try again later", errWithCode.Safe())
+
+	// the lockout is stored on the user, so fetching
+	// them again (eg., signing in again) doesn't help
+	dbUser, err := suite.db.GetUserByID(ctx, user.ID)
+	suite.NoError(err)
+	suite.Equal(5, dbUser.TwoFactorAttempts)
+
+	errWithCode = suite.user.TwoFactorCheck(ctx, dbUser, code)
+	suite.Equal(http.StatusTooManyRequests, errWithCode.Code())
+
+	// once the lockout has passed, the right code works
+	dbUser.TwoFactorAttemptedAt = now.Add(-time.Hour)
+	if err := suite.db.UpdateUser(ctx, dbUser, "two_factor_attempted_at"); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.NoError(suite.user.TwoFactorCheck(ctx, dbUser, code))
+
+	// and the count starts over
+	dbUser, err = suite.db.GetUserByID(ctx, user.ID)
+	suite.NoError(err)
+	suite.Equal(0, dbUser.TwoFactorAttempts)
+}
+
+func TestTwoFactorTestSuite(t *testing.T) {
+	suite.Run(t, &TwoFactorTestSuite{})
+}
@@ -20,17 +20,20 @@ package user
 import (
 	"github.com/superseriousbusiness/gotosocial/internal/email"
 	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/superseriousbusiness/gotosocial/internal/typeutils"
 )
 
 type Processor struct {
 	state       *state.State
+	tc          typeutils.TypeConverter
 	emailSender email.Sender
 }
 
 // New returns a new user processor
-func New(state *state.State, emailSender email.Sender) Processor {
+func New(state *state.State, tc typeutils.TypeConverter, emailSender email.Sender) Processor {
 	return Processor{
 		state:       state,
+		tc:          tc,
 		emailSender: emailSender,
 	}
 }
@@ -33,7 +33,8 @@ type UserStandardTestSuite struct {
 	db          db.DB
 	state       state.State
 
-	testUsers map[string]*gtsmodel.User
+	testUsers    map[string]*gtsmodel.User
+	testAccounts map[string]*gtsmodel.Account
 
 	sentEmails map[string]string
 
@@ -52,12 +53,15 @@ func (suite *UserStandardTestSuite) SetupTest() {
 	suite.sentEmails = make(map[string]string)
 	suite.emailSender = testrig.NewEmailSender("../../../web/template/", suite.sentEmails)
 	suite.testUsers = testrig.NewTestUsers()
+	suite.testAccounts = testrig.NewTestAccounts()
 
-	suite.user = user.New(&suite.state, suite.emailSender)
+	suite.user = user.New(&suite.state, testrig.NewTestTypeConverter(suite.db), suite.emailSender)
 
 	testrig.StandardDBSetup(suite.db, nil)
+	testrig.StartWorkers(&suite.state)
 }
 
 func (suite *UserStandardTestSuite) TearDownTest() {
 	testrig.StandardDBTeardown(suite.db)
+	testrig.StopWorkers(&suite.state)
 }
@@ -26,6 +26,10 @@ const (
 	EventTypeUpdate string = "update"
 	// EventTypeDelete -- something should be deleted from a user
 	EventTypeDelete string = "delete"
+	// EventTypeStatusUpdate -- something in the user's timeline has been edited
+	EventTypeStatusUpdate string = "status.update"
+	// EventTypeConversation -- a user should be shown an updated conversation
+	EventTypeConversation string = "conversation"
 )
 
 const (
@@ -41,6 +45,10 @@ const (
 	TimelineDirect string = "direct"
 	// TimelineList -- statuses for a user's list timeline.
 	TimelineList string = "list"
+	// TimelineHashtag -- public statuses with a given hashtag.
+	TimelineHashtag string = "hashtag"
+	// TimelineHashtagLocal -- public statuses from the LOCAL timeline with a given hashtag.
+	TimelineHashtagLocal string = "hashtag:local"
 )
 
 // AllStatusTimelines contains all Timelines that a status could conceivably be delivered to -- useful for doing deletes.
@@ -50,6 +58,8 @@ var AllStatusTimelines = []string{
 	TimelineHome,
 	TimelineDirect,
 	TimelineList,
+	TimelineHashtag,
+	TimelineHashtagLocal,
 }
 
 // StreamsForAccount is a wrapper for the multiple streams that one account can have running at the same time.
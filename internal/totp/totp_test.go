@@ -0,0 +1,109 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package totp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/totp"
+)
+
+// rfcSecret is the base32 encoding of the SHA1
+// secret "12345678901234567890" from RFC 6238.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+type TOTPTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TOTPTestSuite) TestCodeRFC6238() {
+	// Test vectors from RFC 6238 appendix B,
+	// truncated to 6 digits.
+	for unix, expect := range map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	} {
+		code, err := totp.Code(rfcSecret, time.Unix(unix, 0))
+		suite.NoError(err)
+		suite.Equal(expect, code, "time %d", unix)
+	}
+}
+
+func (suite *TOTPTestSuite) TestValidate() {
+	now := time.Unix(1111111109, 0)
+
+	for _, test := range []struct {
+		code    string
+		valid   bool
+		counter uint64
+	}{
+		{"081804", true, 37037036},   // current period
+		{" 081804 ", true, 37037036}, // surrounding space
+		{"731029", true, 37037035},   // previous period
+		{"050471", true, 37037037},   // next period
+		{"000000", false, 0},         // wrong
+		{"81804", false, 0},          // too short
+		{"", false, 0},               // empty
+	} {
+		counter, valid, err := totp.Validate(rfcSecret, test.code, now)
+		suite.NoError(err)
+		suite.Equal(test.valid, valid, "code %q", test.code)
+		suite.Equal(test.counter, counter, "code %q", test.code)
+	}
+
+	// Codes from two periods ago are no longer valid.
+	_, valid, err := totp.Validate(rfcSecret, "081804", now.Add(2*totp.Period*time.Second))
+	suite.NoError(err)
+	suite.False(valid)
+}
+
+func (suite *TOTPTestSuite) TestGenerateSecret() {
+	secret, err := totp.GenerateSecret()
+	suite.NoError(err)
+	suite.Len(secret, 32)
+
+	now := time.Now()
+	code, err := totp.Code(secret, now)
+	suite.NoError(err)
+
+	// Secrets are accepted regardless of case.
+	_, valid, err := totp.Validate(strings.ToLower(secret), code, now)
+	suite.NoError(err)
+	suite.True(valid)
+}
+
+func (suite *TOTPTestSuite) TestInvalidSecret() {
+	_, err := totp.Code("not base32!", time.Now())
+	suite.EqualError(err, "totp: invalid secret: illegal base32 data at input byte 9")
+}
+
+func (suite *TOTPTestSuite) TestURI() {
+	uri := totp.URI("example.org", "zork", rfcSecret)
+	suite.Equal("otpauth://totp/example.org:zork?algorithm=SHA1&digits=6&issuer=example.org&period=30&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", uri)
+}
+
+func TestTOTPTestSuite(t *testing.T) {
+	suite.Run(t, &TOTPTestSuite{})
+}
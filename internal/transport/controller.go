@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"net/url"
 	"runtime"
+	"sync"
 
 	"codeberg.org/gruf/go-byteutil"
 	"codeberg.org/gruf/go-cache/v3"
@@ -43,6 +44,9 @@ type Controller interface {
 
 	// NewTransportForUsername searches for account with username, and returns result of .NewTransport().
 	NewTransportForUsername(ctx context.Context, username string) (Transport, error)
+
+	// RetryDeliveries attempts all queued deliveries that are due to be retried.
+	RetryDeliveries(ctx context.Context)
 }
 
 type controller struct {
@@ -53,6 +57,9 @@ type controller struct {
 	trspCache cache.TTLCache[string, *transport]
 	userAgent string
 	senders   int // no. concurrent batch delivery routines.
+
+	retrying   sync.Mutex // held while retrying queued deliveries.
+	failuresMu sync.Mutex // protects read-modify-write of delivery failures.
 }
 
 // NewController returns an implementation of the Controller interface for creating new transports
@@ -21,92 +21,64 @@ import (
 	"context"
 	"net/http"
 	"net/url"
-	"sync"
 
 	"codeberg.org/gruf/go-byteutil"
 	apiutil "github.com/superseriousbusiness/gotosocial/internal/api/util"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
 )
 
 func (t *transport) BatchDeliver(ctx context.Context, b []byte, recipients []*url.URL) error {
 	var (
-		// errs accumulates errors received during
-		// attempted delivery by deliverer routines.
-		errs gtserror.MultiError
-
-		// wait blocks until all sender
-		// routines have returned.
-		wait sync.WaitGroup
-
-		// mutex protects 'recipients' and
-		// 'errs' for concurrent access.
-		mutex sync.Mutex
+		// deliveries to attempt
+		// via the delivery queue.
+		deliveries = make([]*gtsmodel.Delivery, 0, len(recipients))
 
 		// Get current instance host info.
 		domain = config.GetAccountDomain()
 		host   = config.GetHost()
 	)
 
-	// Block on expect no. senders.
-	wait.Add(t.controller.senders)
-
-	for i := 0; i < t.controller.senders; i++ {
-		go func() {
-			// Mark returned.
-			defer wait.Done()
-
-			for {
-				// Acquire lock.
-				mutex.Lock()
-
-				if len(recipients) == 0 {
-					// Reached end.
-					mutex.Unlock()
-					return
-				}
-
-				// Pop next recipient.
-				i := len(recipients) - 1
-				to := recipients[i]
-				recipients = recipients[:i]
-
-				// Done with lock.
-				mutex.Unlock()
-
-				// Skip delivery to recipient if it is "us".
-				if to.Host == host || to.Host == domain {
-					continue
-				}
-
-				// Attempt to deliver data to recipient.
-				if err := t.deliver(ctx, b, to); err != nil {
-					mutex.Lock() // safely append err to accumulator.
-					errs.Appendf("error delivering to %s: %v", to, err)
-					mutex.Unlock()
-				}
-			}
-		}()
+	for _, to := range recipients {
+		// Skip delivery to recipient if it is "us".
+		if to.Host == host || to.Host == domain {
+			continue
+		}
+
+		deliveries = append(deliveries, &gtsmodel.Delivery{
+			ID:       id.NewULID(),
+			PubKeyID: t.pubKeyID,
+			InboxURI: to.String(),
+			Domain:   to.Host,
+			Data:     b,
+		})
 	}
 
-	// Wait for finish.
-	wait.Wait()
-
-	// Return combined err.
-	return errs.Combine()
+	// Queue the deliveries, making a first
+	// attempt at each of them straight away.
+	return t.controller.enqueue(ctx, t, deliveries)
 }
 
 func (t *transport) Deliver(ctx context.Context, b []byte, to *url.URL) error {
-	// if 'to' host is our own, skip as we don't need to deliver to ourselves...
-	if to.Host == config.GetHost() || to.Host == config.GetAccountDomain() {
-		return nil
-	}
-
-	// Deliver data to recipient.
-	return t.deliver(ctx, b, to)
+	return t.BatchDeliver(ctx, b, []*url.URL{to})
 }
 
 func (t *transport) deliver(ctx context.Context, b []byte, to *url.URL) error {
+	// Skip delivery to recipient if we don't federate with its
+	// domain; ie., it's blocked, or not allowed in allowlist mode.
+	blocked, err := t.controller.state.DB.IsDomainBlocked(ctx, to.Hostname())
+	if err != nil {
+		return gtserror.Newf("error checking domain block for %s: %w", to.Host, err)
+	}
+
+	if blocked {
+		log.Debugf(ctx, "domain %s is blocked, skipping delivery", to.Host)
+		return nil
+	}
+
 	url := to.String()
 
 	// Use rewindable bytes reader for body.
@@ -0,0 +1,366 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtscontext"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+)
+
+const (
+	// deliveryMaxAttempts is the number of failed
+	// attempts after which a queued delivery is dropped.
+	deliveryMaxAttempts = 12
+
+	// deliveryBackoffMin and deliveryBackoffMax bound
+	// the delay before retrying a failed delivery, which
+	// doubles with each attempt starting from the min.
+	deliveryBackoffMin = time.Minute
+	deliveryBackoffMax = 12 * time.Hour
+
+	// domainUnreachableAfter is how long deliveries to
+	// a domain must have been failing for before the
+	// domain is considered unreachable, and deliveries
+	// to it are held rather than attempted.
+	domainUnreachableAfter = time.Hour
+
+	// domainRetryMax is the longest that deliveries
+	// to an unreachable domain will be held for.
+	domainRetryMax = 24 * time.Hour
+
+	// deliveryBatchSize is the number of due
+	// deliveries to fetch from the queue at once.
+	deliveryBatchSize = 100
+)
+
+// enqueue persists the given deliveries to the delivery
+// queue, then makes a first attempt at each of them using
+// transport t. Deliveries that fail are retried later by
+// RetryDeliveries, so only errors for deliveries that could
+// not be queued in the first place are returned.
+func (c *controller) enqueue(ctx context.Context, t *transport, deliveries []*gtsmodel.Delivery) error {
+	var (
+		// errs accumulates errors from deliveries
+		// that couldn't be queued for retrying.
+		errs gtserror.MultiError
+
+		// mutex protects 'errs'
+		// for concurrent access.
+		mutex sync.Mutex
+
+		now = time.Now()
+	)
+
+	for _, delivery := range deliveries {
+		delivery.NextAttemptAt = now
+		if err := c.state.DB.PutDelivery(ctx, delivery); err != nil {
+			// Not queued, so fall back to a single attempt.
+			log.Errorf(ctx, "error queueing delivery to %s: %v", delivery.InboxURI, err)
+			delivery.ID = ""
+		}
+	}
+
+	c.attemptAll(ctx, deliveries, func(delivery *gtsmodel.Delivery) error {
+		if delivery.ID != "" {
+			return c.attempt(ctx, t, delivery)
+		}
+
+		inbox, err := url.Parse(delivery.InboxURI)
+		if err == nil {
+			err = t.deliver(ctx, delivery.Data, inbox)
+		}
+
+		if err != nil {
+			mutex.Lock() // safely append err to accumulator.
+			errs.Appendf("error delivering to %s: %v", delivery.InboxURI, err)
+			mutex.Unlock()
+		}
+
+		return nil
+	})
+
+	// Return combined err.
+	return errs.Combine()
+}
+
+// RetryDeliveries attempts all queued deliveries that are
+// due, signing each with the key of the account it was
+// originally sent by. If a previous call is still running,
+// this is a no-op.
+func (c *controller) RetryDeliveries(ctx context.Context) {
+	if !c.retrying.TryLock() {
+		// Already running.
+		return
+	}
+	defer c.retrying.Unlock()
+
+	// Failed deliveries are retried by the queue,
+	// so don't backoff and retry in the http client.
+	ctx = gtscontext.SetFastFail(ctx)
+
+	for {
+		deliveries, err := c.state.DB.GetDueDeliveries(ctx, time.Now(), deliveryBatchSize)
+		if err != nil {
+			log.Errorf(ctx, "error getting due deliveries: %v", err)
+			return
+		}
+
+		if !c.attemptAll(ctx, deliveries, func(delivery *gtsmodel.Delivery) error {
+			return c.retry(ctx, delivery)
+		}) {
+			// Queue couldn't be updated, so bail
+			// rather than looping on the same batch.
+			return
+		}
+
+		if len(deliveries) < deliveryBatchSize {
+			// Reached end.
+			return
+		}
+	}
+}
+
+// retry makes another attempt at the given queued delivery,
+// using a transport for the account it was originally sent by.
+func (c *controller) retry(ctx context.Context, delivery *gtsmodel.Delivery) error {
+	account, err := c.state.DB.GetAccountByPubkeyID(ctx, delivery.PubKeyID)
+	if err != nil {
+		if !errors.Is(err, db.ErrNoEntries) {
+			return gtserror.Newf("error getting account for key %s: %w", delivery.PubKeyID, err)
+		}
+
+		// Sending account is gone,
+		// so drop the delivery.
+		log.Infof(ctx, "dropping delivery to %s: no account with key %s", delivery.InboxURI, delivery.PubKeyID)
+		return c.state.DB.DeleteDeliveryByID(ctx, delivery.ID)
+	}
+
+	t, err := c.NewTransport(account.PublicKeyURI, account.PrivateKey)
+	if err != nil {
+		return gtserror.Newf("error creating transport for key %s: %w", delivery.PubKeyID, err)
+	}
+
+	return c.attempt(ctx, t.(*transport), delivery)
+}
+
+// attemptAll calls attempt for each of the given deliveries, spread
+// across the controller's sender routines, returning false if any
+// call returned an error (which is logged).
+func (c *controller) attemptAll(ctx context.Context, deliveries []*gtsmodel.Delivery, attempt func(*gtsmodel.Delivery) error) bool {
+	var (
+		// ok is set false if
+		// any attempt errors.
+		ok = true
+
+		// wait blocks until all sender
+		// routines have returned.
+		wait sync.WaitGroup
+
+		// mutex protects 'deliveries'
+		// and 'ok' for concurrent access.
+		mutex sync.Mutex
+	)
+
+	// Block on expect no. senders.
+	wait.Add(c.senders)
+
+	for i := 0; i < c.senders; i++ {
+		go func() {
+			// Mark returned.
+			defer wait.Done()
+
+			for {
+				// Acquire lock.
+				mutex.Lock()
+
+				if len(deliveries) == 0 {
+					// Reached end.
+					mutex.Unlock()
+					return
+				}
+
+				// Pop next delivery.
+				i := len(deliveries) - 1
+				delivery := deliveries[i]
+				deliveries = deliveries[:i]
+
+				// Done with lock.
+				mutex.Unlock()
+
+				if err := attempt(delivery); err != nil {
+					log.Errorf(ctx, "error updating queued delivery to %s: %v", delivery.InboxURI, err)
+					mutex.Lock()
+					ok = false
+					mutex.Unlock()
+				}
+			}
+		}()
+	}
+
+	// Wait for finish.
+	wait.Wait()
+
+	return ok
+}
+
+// attempt makes one attempt at the given queued delivery using
+// transport t. On success the delivery is removed from the queue,
+// and on failure it's scheduled to be attempted again with backoff.
+// If the delivery's domain is unreachable, it's held until the domain
+// is due to be retried instead. An error is only returned if the queue
+// itself could not be updated.
+func (c *controller) attempt(ctx context.Context, t *transport, delivery *gtsmodel.Delivery) error {
+	now := time.Now()
+
+	failure, err := c.state.DB.GetDeliveryFailure(ctx, delivery.Domain)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return gtserror.Newf("error getting delivery failure for %s: %w", delivery.Domain, err)
+	}
+
+	if failure != nil && now.Before(failure.RetryAt) {
+		// Domain is unreachable, hold the
+		// delivery until it's due to be retried.
+		delivery.NextAttemptAt = failure.RetryAt
+		return c.state.DB.UpdateDelivery(ctx, delivery, "next_attempt_at")
+	}
+
+	inbox, err := url.Parse(delivery.InboxURI)
+	if err != nil {
+		log.Errorf(ctx, "dropping delivery to invalid inbox %s: %v", delivery.InboxURI, err)
+		return c.state.DB.DeleteDeliveryByID(ctx, delivery.ID)
+	}
+
+	err = t.deliver(ctx, delivery.Data, inbox)
+	if err == nil || permanent(err) {
+		if err != nil {
+			log.Warnf(ctx, "dropping delivery to %s: %v", inbox, err)
+		}
+
+		if failure != nil {
+			// Domain responded, so it's reachable again.
+			c.domainReachable(ctx, delivery.Domain)
+		}
+
+		return c.state.DB.DeleteDeliveryByID(ctx, delivery.ID)
+	}
+
+	c.domainFailed(ctx, delivery.Domain, err, now)
+
+	delivery.Attempts++
+	if delivery.Attempts >= deliveryMaxAttempts {
+		log.Warnf(ctx, "dropping delivery to %s after %d attempts: %v", inbox, delivery.Attempts, err)
+		return c.state.DB.DeleteDeliveryByID(ctx, delivery.ID)
+	}
+
+	log.Infof(ctx, "delivery to %s failed, will retry: %v", inbox, err)
+
+	delivery.NextAttemptAt = now.Add(backoff(deliveryBackoffMin<<(delivery.Attempts-1), deliveryBackoffMax))
+	delivery.LastError = err.Error()
+	return c.state.DB.UpdateDelivery(ctx, delivery, "attempts", "next_attempt_at", "last_error")
+}
+
+// domainFailed records a failed delivery to the given domain,
+// marking the domain as unreachable (and so holding deliveries
+// to it until RetryAt) once it's been failing for long enough.
+func (c *controller) domainFailed(ctx context.Context, domain string, cause error, now time.Time) {
+	c.failuresMu.Lock()
+	defer c.failuresMu.Unlock()
+
+	failure, err := c.state.DB.GetDeliveryFailure(ctx, domain)
+	if err != nil {
+		if !errors.Is(err, db.ErrNoEntries) {
+			log.Errorf(ctx, "error getting delivery failure for %s: %v", domain, err)
+			return
+		}
+
+		// First failure in a
+		// row for this domain.
+		failure = &gtsmodel.DeliveryFailure{
+			ID:           id.NewULID(),
+			Domain:       domain,
+			FailingSince: now,
+			Failures:     1,
+			LastError:    cause.Error(),
+		}
+
+		if err := c.state.DB.PutDeliveryFailure(ctx, failure); err != nil {
+			log.Errorf(ctx, "error putting delivery failure for %s: %v", domain, err)
+		}
+
+		return
+	}
+
+	failure.Failures++
+	failure.LastError = cause.Error()
+
+	if failing := now.Sub(failure.FailingSince); failing >= domainUnreachableAfter {
+		// Hold deliveries for about as long as the domain has been
+		// failing, so retries to it back off exponentially over time.
+		failure.RetryAt = now.Add(backoff(failing, domainRetryMax))
+		log.Infof(ctx, "domain %s unreachable for %s, holding deliveries until %s", domain, failing, failure.RetryAt)
+	}
+
+	if err := c.state.DB.UpdateDeliveryFailure(ctx, failure); err != nil {
+		log.Errorf(ctx, "error updating delivery failure for %s: %v", domain, err)
+	}
+}
+
+// domainReachable clears any record of failed deliveries to the given domain.
+func (c *controller) domainReachable(ctx context.Context, domain string) {
+	c.failuresMu.Lock()
+	defer c.failuresMu.Unlock()
+
+	if err := c.state.DB.DeleteDeliveryFailure(ctx, domain); err != nil {
+		log.Errorf(ctx, "error deleting delivery failure for %s: %v", domain, err)
+		return
+	}
+
+	log.Infof(ctx, "deliveries to domain %s are succeeding again", domain)
+}
+
+// permanent returns whether the given delivery error indicates that
+// retrying won't help, ie., the remote responded with a client error.
+func permanent(err error) bool {
+	code := gtserror.StatusCode(err)
+	return code >= 400 && code < 500 &&
+		code != http.StatusRequestTimeout &&
+		code != http.StatusTooManyRequests
+}
+
+// backoff returns a random duration between half of
+// d and d (capped at max), so that retries of deliveries
+// that failed together don't all happen at the same time.
+func backoff(d time.Duration, max time.Duration) time.Duration {
+	if d <= 0 || d > max {
+		d = max
+	}
+
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1)) //nolint:gosec
+}
@@ -0,0 +1,216 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package transport_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/transport"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+type QueueTestSuite struct {
+	TransportTestSuite
+
+	// status code returned
+	// for delivery POSTs.
+	responseCode atomic.Int32
+
+	// no. delivery POSTs made.
+	requests atomic.Int32
+
+	controller transport.Controller
+}
+
+func (suite *QueueTestSuite) SetupTest() {
+	suite.TransportTestSuite.SetupTest()
+
+	suite.responseCode.Store(http.StatusAccepted)
+	suite.requests.Store(0)
+
+	suite.controller = testrig.NewTestTransportController(&suite.state, testrig.NewMockHTTPClient(func(req *http.Request) (*http.Response, error) {
+		suite.requests.Add(1)
+		code := int(suite.responseCode.Load())
+		return &http.Response{
+			StatusCode: code,
+			Status:     fmt.Sprintf("%d %s", code, http.StatusText(code)),
+			Body:       io.NopCloser(strings.NewReader("")),
+			Request:    req,
+		}, nil
+	}, ""))
+
+	var err error
+	suite.transport, err = suite.controller.NewTransportForUsername(context.Background(), "")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+}
+
+func (suite *QueueTestSuite) inboxes() []*url.URL {
+	return []*url.URL{
+		testrig.URLMustParse("https://unknown-instance.com/users/someone/inbox"),
+		testrig.URLMustParse("https://unknown-instance.com/users/someone_else/inbox"),
+	}
+}
+
+func (suite *QueueTestSuite) queued() []*gtsmodel.Delivery {
+	// Look far enough in the future that
+	// all queued deliveries are due.
+	deliveries, err := suite.db.GetDueDeliveries(context.Background(), time.Now().Add(48*time.Hour), 100)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	return deliveries
+}
+
+func (suite *QueueTestSuite) TestBatchDeliver() {
+	err := suite.transport.BatchDeliver(context.Background(), []byte(`{}`), suite.inboxes())
+	suite.NoError(err)
+
+	suite.EqualValues(2, suite.requests.Load())
+	suite.Empty(suite.queued())
+
+	_, err = suite.db.GetDeliveryFailure(context.Background(), "unknown-instance.com")
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
+func (suite *QueueTestSuite) TestBatchDeliverFailed() {
+	suite.responseCode.Store(http.StatusServiceUnavailable)
+
+	err := suite.transport.BatchDeliver(context.Background(), []byte(`{}`), suite.inboxes())
+	suite.NoError(err)
+
+	suite.EqualValues(2, suite.requests.Load())
+
+	deliveries := suite.queued()
+	suite.Len(deliveries, 2)
+	for _, delivery := range deliveries {
+		suite.Equal(1, delivery.Attempts)
+		suite.Equal("unknown-instance.com", delivery.Domain)
+		suite.Equal([]byte(`{}`), delivery.Data)
+		suite.True(delivery.NextAttemptAt.After(time.Now()))
+		suite.Contains(delivery.LastError, "503")
+	}
+
+	failure, err := suite.db.GetDeliveryFailure(context.Background(), "unknown-instance.com")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal(2, failure.Failures)
+	suite.True(failure.RetryAt.IsZero())
+}
+
+func (suite *QueueTestSuite) TestBatchDeliverGone() {
+	suite.responseCode.Store(http.StatusGone)
+
+	err := suite.transport.BatchDeliver(context.Background(), []byte(`{}`), suite.inboxes())
+	suite.NoError(err)
+
+	// Client errors aren't retried.
+	suite.EqualValues(2, suite.requests.Load())
+	suite.Empty(suite.queued())
+}
+
+func (suite *QueueTestSuite) TestRetryDeliveries() {
+	ctx := context.Background()
+	suite.responseCode.Store(http.StatusServiceUnavailable)
+
+	err := suite.transport.BatchDeliver(ctx, []byte(`{}`), suite.inboxes())
+	suite.NoError(err)
+
+	// Nothing due yet, so
+	// nothing should be retried.
+	suite.controller.RetryDeliveries(ctx)
+	suite.EqualValues(2, suite.requests.Load())
+	suite.Len(suite.queued(), 2)
+
+	// Make the deliveries due, and the remote available again.
+	for _, delivery := range suite.queued() {
+		delivery.NextAttemptAt = time.Now().Add(-time.Second)
+		if err := suite.db.UpdateDelivery(ctx, delivery, "next_attempt_at"); err != nil {
+			suite.FailNow(err.Error())
+		}
+	}
+	suite.responseCode.Store(http.StatusOK)
+
+	suite.controller.RetryDeliveries(ctx)
+	suite.EqualValues(4, suite.requests.Load())
+	suite.Empty(suite.queued())
+
+	// Domain is reachable again.
+	_, err = suite.db.GetDeliveryFailure(ctx, "unknown-instance.com")
+	suite.ErrorIs(err, db.ErrNoEntries)
+}
+
+func (suite *QueueTestSuite) TestUnreachableDomain() {
+	ctx := context.Background()
+	suite.responseCode.Store(http.StatusServiceUnavailable)
+
+	// Domain has been failing for a while already.
+	if err := suite.db.PutDeliveryFailure(ctx, &gtsmodel.DeliveryFailure{
+		ID:           id.NewULID(),
+		Domain:       "unknown-instance.com",
+		FailingSince: time.Now().Add(-2 * time.Hour),
+		Failures:     20,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// This failure should mark the domain as unreachable.
+	err := suite.transport.Deliver(ctx, []byte(`{}`), suite.inboxes()[0])
+	suite.NoError(err)
+	suite.EqualValues(1, suite.requests.Load())
+
+	failure, err := suite.db.GetDeliveryFailure(ctx, "unknown-instance.com")
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal(21, failure.Failures)
+	suite.True(failure.RetryAt.After(time.Now().Add(time.Hour)))
+
+	// Further deliveries should be held
+	// until the domain is due to be retried.
+	err = suite.transport.Deliver(ctx, []byte(`{}`), suite.inboxes()[1])
+	suite.NoError(err)
+	suite.EqualValues(1, suite.requests.Load())
+
+	deliveries := suite.queued()
+	suite.Len(deliveries, 2)
+	for _, delivery := range deliveries {
+		if delivery.InboxURI == suite.inboxes()[1].String() {
+			suite.Equal(0, delivery.Attempts)
+			suite.True(delivery.NextAttemptAt.Equal(failure.RetryAt))
+		}
+	}
+}
+
+func TestQueueTestSuite(t *testing.T) {
+	suite.Run(t, new(QueueTestSuite))
+}
@@ -62,6 +62,9 @@ type Transport interface {
 	// DereferenceMedia fetches the given media attachment IRI, returning the reader and filesize.
 	DereferenceMedia(ctx context.Context, iri *url.URL) (io.ReadCloser, int64, error)
 
+	// DereferenceDomainPermissions fetches the domain permissions list at the given IRI, requesting the given content type.
+	DereferenceDomainPermissions(ctx context.Context, iri *url.URL, contentType string) (io.ReadCloser, error)
+
 	// DereferenceInstance dereferences remote instance information, first by checking /api/v1/instance, and then by checking /.well-known/nodeinfo.
 	DereferenceInstance(ctx context.Context, iri *url.URL) (*gtsmodel.Instance, error)
 
@@ -194,7 +194,13 @@ func (c *converter) ASRepresentationToAccount(ctx context.Context, accountable a
 
 	// TODO: FeaturedTagsURI
 
-	// TODO: alsoKnownAs
+	// alsoKnownAs, used to verify account moves.
+	if alsoKnownAs := ap.ExtractAlsoKnownAsURIs(accountable); len(alsoKnownAs) != 0 {
+		acct.AlsoKnownAsURIs = make([]string, 0, len(alsoKnownAs))
+		for _, uri := range alsoKnownAs {
+			acct.AlsoKnownAsURIs = append(acct.AlsoKnownAsURIs, uri.String())
+		}
+	}
 
 	// publicKey
 	pkey, pkeyURL, pkeyOwnerID, err := ap.ExtractPublicKey(accountable)
@@ -305,6 +311,19 @@ func (c *converter) ASStatusToStatus(ctx context.Context, statusable ap.Statusab
 		status.Mentions = mentions
 	}
 
+	// status.Poll
+	//
+	// Attached poll information (the statusable will actually
+	// be a Pollable, as a Question is a subset of our Status).
+	if pollable, ok := statusable.(ap.Pollable); ok {
+		poll, err := ap.ExtractPoll(pollable)
+		if err != nil {
+			l.Infof("error extracting poll: %q", err)
+		} else {
+			status.Poll = poll
+		}
+	}
+
 	// status.ContentWarning
 	//
 	// Topic or content warning for this status;
@@ -327,6 +346,12 @@ func (c *converter) ASStatusToStatus(ctx context.Context, statusable ap.Statusab
 		status.UpdatedAt = published
 	}
 
+	// status.EditedAt
+	//
+	// Time at which this status was last
+	// edited, zero if it never has been.
+	status.EditedAt = ap.ExtractUpdated(statusable)
+
 	// status.AccountURI
 	// status.AccountID
 	// status.Account
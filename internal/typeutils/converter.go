@@ -28,6 +28,7 @@ import (
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/statusfilter"
 )
 
 // TypeConverter is an interface for the common action of converting between apimodule (frontend, serializable) models,
@@ -52,6 +53,8 @@ type TypeConverter interface {
 	// something goes wrong. The returned account will be a bare minimum representation of the account. This function should be used
 	// when someone wants to view an account they've blocked.
 	AccountToAPIAccountBlocked(ctx context.Context, account *gtsmodel.Account) (*apimodel.Account, error)
+	// AccountToAdminAPIAccount converts a gts model account into an admin view account, for serving at /api/v1/admin/accounts.
+	AccountToAdminAPIAccount(ctx context.Context, account *gtsmodel.Account) (*apimodel.AdminAccountInfo, error)
 	// AppToAPIAppSensitive takes a db model application as a param, and returns a populated apitype application, or an error
 	// if something goes wrong. The returned application should be ready to serialize on an API level, and may have sensitive fields
 	// (such as client id and client secret), so serve it only to an authorized user who should have permission to see it.
@@ -75,8 +78,17 @@ type TypeConverter interface {
 	TagToAPITag(ctx context.Context, t *gtsmodel.Tag, stubHistory bool) (apimodel.Tag, error)
 	// StatusToAPIStatus converts a gts model status into its api (frontend) representation for serialization on the API.
 	//
+	// Requesting account can be nil. If filterContext is set, the given filters of the requesting account
+	// are applied to the status: matching "warn" filters are included in the returned status' filtered field,
+	// and a matching "hide" filter results in statusfilter.ErrHideStatus being returned.
+	StatusToAPIStatus(ctx context.Context, s *gtsmodel.Status, requestingAccount *gtsmodel.Account, filterContext statusfilter.FilterContext, filters []*gtsmodel.Filter) (*apimodel.Status, error)
+	// StatusToAPIEdits converts the edit history of a gts model status into its api (frontend) representation for serialization on the API.
+	// The returned slice is in chronological order, and always ends with the current version of the status.
+	StatusToAPIEdits(ctx context.Context, s *gtsmodel.Status) ([]*apimodel.StatusEdit, error)
+	// PollToAPIPoll converts a gts model poll into its api (frontend) representation for serialization on the API.
+	//
 	// Requesting account can be nil.
-	StatusToAPIStatus(ctx context.Context, s *gtsmodel.Status, requestingAccount *gtsmodel.Account) (*apimodel.Status, error)
+	PollToAPIPoll(ctx context.Context, requester *gtsmodel.Account, poll *gtsmodel.Poll) (*apimodel.Poll, error)
 	// VisToAPIVis converts a gts visibility into its api equivalent
 	VisToAPIVis(ctx context.Context, m gtsmodel.Visibility) apimodel.Visibility
 	// InstanceToAPIV1Instance converts a gts instance into its api equivalent for serving at /api/v1/instance
@@ -85,18 +97,47 @@ type TypeConverter interface {
 	InstanceToAPIV2Instance(ctx context.Context, i *gtsmodel.Instance) (*apimodel.InstanceV2, error)
 	// RelationshipToAPIRelationship converts a gts relationship into its api equivalent for serving in various places
 	RelationshipToAPIRelationship(ctx context.Context, r *gtsmodel.Relationship) (*apimodel.Relationship, error)
-	// NotificationToAPINotification converts a gts notification into a api notification
-	NotificationToAPINotification(ctx context.Context, n *gtsmodel.Notification) (*apimodel.Notification, error)
+	// NotificationToAPINotification converts a gts notification into a api notification, applying the given filters
+	// of the target account to the notification's status, if any. If the status matches a "hide" filter,
+	// statusfilter.ErrHideStatus is returned.
+	NotificationToAPINotification(ctx context.Context, n *gtsmodel.Notification, filters []*gtsmodel.Filter) (*apimodel.Notification, error)
 	// DomainBlockToAPIDomainBlock converts a gts model domin block into a api domain block, for serving at /api/v1/admin/domain_blocks
 	DomainBlockToAPIDomainBlock(ctx context.Context, b *gtsmodel.DomainBlock, export bool) (*apimodel.DomainBlock, error)
+	// DomainAllowToAPIDomainAllow converts a gts model domain allow into a api domain allow, for serving at /api/v1/admin/domain_allows
+	DomainAllowToAPIDomainAllow(ctx context.Context, a *gtsmodel.DomainAllow, export bool) (*apimodel.DomainAllow, error)
+	// DomainPermissionSubscriptionToAPIDomainPermissionSubscription converts a gts model domain permission
+	// subscription into an api model domain permission subscription, for serving at /api/v1/admin/domain_permission_subscriptions
+	DomainPermissionSubscriptionToAPIDomainPermissionSubscription(ctx context.Context, s *gtsmodel.DomainPermissionSubscription) (*apimodel.DomainPermissionSubscription, error)
 	// ReportToAPIReport converts a gts model report into an api model report, for serving at /api/v1/reports
 	ReportToAPIReport(ctx context.Context, r *gtsmodel.Report) (*apimodel.Report, error)
 	// ReportToAdminAPIReport converts a gts model report into an admin view report, for serving at /api/v1/admin/reports
 	ReportToAdminAPIReport(ctx context.Context, r *gtsmodel.Report, requestingAccount *gtsmodel.Account) (*apimodel.AdminReport, error)
+	// RuleToAPIRule converts a gts model rule into an api model instance rule, for serving in /api/v1/instance
+	RuleToAPIRule(r *gtsmodel.Rule) apimodel.InstanceRule
+	// RuleToAdminAPIRule converts a gts model rule into an admin view rule, for serving at /api/v1/admin/instance/rules
+	RuleToAdminAPIRule(r *gtsmodel.Rule) *apimodel.AdminInstanceRule
 	// ListToAPIList converts one gts model list into an api model list, for serving at /api/v1/lists/{id}
 	ListToAPIList(ctx context.Context, l *gtsmodel.List) (*apimodel.List, error)
 	// MarkersToAPIMarker converts several gts model markers into an api marker, for serving at /api/v1/markers
 	MarkersToAPIMarker(ctx context.Context, markers []*gtsmodel.Marker) (*apimodel.Marker, error)
+	// FilterToAPIFiltersV1 converts one gts model filter into a slice of api model v1 filters, one per filter keyword, for serving at /api/v1/filters
+	FilterToAPIFiltersV1(ctx context.Context, filter *gtsmodel.Filter) ([]*apimodel.FilterV1, error)
+	// FilterKeywordToAPIFilterV1 converts one gts model filter keyword (with its parent filter) into an api model v1 filter, for serving at /api/v1/filters/{id}
+	FilterKeywordToAPIFilterV1(ctx context.Context, filterKeyword *gtsmodel.FilterKeyword) (*apimodel.FilterV1, error)
+	// FilterToAPIFilterV2 converts one gts model filter into an api model v2 filter, for serving at /api/v2/filters/{id}
+	FilterToAPIFilterV2(ctx context.Context, filter *gtsmodel.Filter) (*apimodel.FilterV2, error)
+	// FilterKeywordToAPIFilterKeyword converts one gts model filter keyword into an api model filter keyword, for serving at /api/v2/filters/keywords/{id}
+	FilterKeywordToAPIFilterKeyword(ctx context.Context, filterKeyword *gtsmodel.FilterKeyword) *apimodel.FilterKeyword
+	// FilterStatusToAPIFilterStatus converts one gts model filter status into an api model filter status, for serving at /api/v2/filters/statuses/{id}
+	FilterStatusToAPIFilterStatus(ctx context.Context, filterStatus *gtsmodel.FilterStatus) *apimodel.FilterStatus
+	// ConversationToAPIConversation converts one gts model conversation into an api model conversation, for serving at /api/v1/conversations
+	ConversationToAPIConversation(ctx context.Context, conversation *gtsmodel.Conversation, requestingAccount *gtsmodel.Account) (*apimodel.Conversation, error)
+	// WebPushSubscriptionToAPIWebPushSubscription converts one gts model web push subscription into an api model web push subscription, for serving at /api/v1/push/subscription
+	WebPushSubscriptionToAPIWebPushSubscription(ctx context.Context, subscription *gtsmodel.WebPushSubscription) (*apimodel.WebPushSubscription, error)
+	// ScheduledStatusToAPIScheduledStatus converts one gts model scheduled status into an api model scheduled status, for serving at /api/v1/scheduled_statuses
+	ScheduledStatusToAPIScheduledStatus(ctx context.Context, scheduledStatus *gtsmodel.ScheduledStatus) (*apimodel.ScheduledStatus, error)
+	// InviteToAPIInvite converts one gts model invite into an api model invite, for serving at /api/v1/invites
+	InviteToAPIInvite(ctx context.Context, invite *gtsmodel.Invite) *apimodel.Invite
 
 	/*
 		INTERNAL (gts) MODEL TO FRONTEND (rss) MODEL
@@ -150,8 +191,8 @@ type TypeConverter interface {
 	// suitable for serving to requesters to whom we want to give as little information as possible because
 	// we don't trust them (yet).
 	AccountToASMinimal(ctx context.Context, a *gtsmodel.Account) (vocab.ActivityStreamsPerson, error)
-	// StatusToAS converts a gts model status into an activity streams note, suitable for federation
-	StatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.ActivityStreamsNote, error)
+	// StatusToAS converts a gts model status into an activity streams note (or question, if the status contains a poll), suitable for federation
+	StatusToAS(ctx context.Context, s *gtsmodel.Status) (ap.Statusable, error)
 	// StatusToASDelete converts a gts model status into a Delete of that status, using just the
 	// URI of the status as object, and addressing the Delete appropriately.
 	StatusToASDelete(ctx context.Context, status *gtsmodel.Status) (vocab.ActivityStreamsDelete, error)
@@ -164,7 +205,7 @@ type TypeConverter interface {
 	// TagToAS converts a gts model tag into a toot Hashtag, suitable for federation.
 	TagToAS(ctx context.Context, t *gtsmodel.Tag) (vocab.TootHashtag, error)
 	// AttachmentToAS converts a gts model media attachment into an activity streams Attachment, suitable for federation
-	AttachmentToAS(ctx context.Context, a *gtsmodel.MediaAttachment) (vocab.ActivityStreamsDocument, error)
+	AttachmentToAS(ctx context.Context, a *gtsmodel.MediaAttachment) (ap.Attachmentable, error)
 	// FaveToAS converts a gts model status fave into an activityStreams LIKE, suitable for federation.
 	FaveToAS(ctx context.Context, f *gtsmodel.StatusFave) (vocab.ActivityStreamsLike, error)
 	// BoostToAS converts a gts model boost into an activityStreams ANNOUNCE, suitable for federation
@@ -192,6 +233,9 @@ type TypeConverter interface {
 	StatusesToASFeaturedCollection(ctx context.Context, featuredCollectionID string, statuses []*gtsmodel.Status) (vocab.ActivityStreamsOrderedCollection, error)
 	// ReportToASFlag converts a gts model report into an activitystreams FLAG, suitable for federation.
 	ReportToASFlag(ctx context.Context, r *gtsmodel.Report) (vocab.ActivityStreamsFlag, error)
+	// PollVoteToASCreates converts a gts model poll vote into a slice of activitystreams CREATEs,
+	// one for each choice in the vote (each wrapping a named Note), suitable for federation.
+	PollVoteToASCreates(ctx context.Context, vote *gtsmodel.PollVote) ([]vocab.ActivityStreamsCreate, error)
 
 	/*
 		INTERNAL (gts) MODEL TO INTERNAL MODEL
@@ -208,12 +252,17 @@ type TypeConverter interface {
 
 	// WrapPersonInUpdate
 	WrapPersonInUpdate(person vocab.ActivityStreamsPerson, originAccount *gtsmodel.Account) (vocab.ActivityStreamsUpdate, error)
-	// WrapNoteInCreate wraps a Note with a Create activity.
+	// WrapStatusableInCreate wraps a Statusable (Note or Question) with a Create activity.
 	//
-	// If objectIRIOnly is set to true, then the function won't put the *entire* note in the Object field of the Create,
-	// but just the AP URI of the note. This is useful in cases where you want to give a remote server something to dereference,
+	// If objectIRIOnly is set to true, then the function won't put the *entire* status in the Object field of the Create,
+	// but just the AP URI of the status. This is useful in cases where you want to give a remote server something to dereference,
 	// and still have control over whether or not they're allowed to actually see the contents.
-	WrapNoteInCreate(note vocab.ActivityStreamsNote, objectIRIOnly bool) (vocab.ActivityStreamsCreate, error)
+	WrapStatusableInCreate(status ap.Statusable, objectIRIOnly bool) (vocab.ActivityStreamsCreate, error)
+	// WrapStatusableInUpdate wraps a Statusable (Note or Question) with an Update activity.
+	//
+	// If objectIRIOnly is set to true, then the function won't put the *entire* status in the Object field of the Update,
+	// but just the AP URI of the status.
+	WrapStatusableInUpdate(status ap.Statusable, objectIRIOnly bool) (vocab.ActivityStreamsUpdate, error)
 }
 
 type converter struct {
@@ -471,15 +471,18 @@ const (
 
 type TypeUtilsTestSuite struct {
 	suite.Suite
-	db              db.DB
-	state           state.State
-	testAccounts    map[string]*gtsmodel.Account
-	testStatuses    map[string]*gtsmodel.Status
-	testAttachments map[string]*gtsmodel.MediaAttachment
-	testPeople      map[string]vocab.ActivityStreamsPerson
-	testEmojis      map[string]*gtsmodel.Emoji
-	testReports     map[string]*gtsmodel.Report
-	testMentions    map[string]*gtsmodel.Mention
+	db                 db.DB
+	state              state.State
+	testAccounts       map[string]*gtsmodel.Account
+	testStatuses       map[string]*gtsmodel.Status
+	testAttachments    map[string]*gtsmodel.MediaAttachment
+	testPeople         map[string]vocab.ActivityStreamsPerson
+	testEmojis         map[string]*gtsmodel.Emoji
+	testReports        map[string]*gtsmodel.Report
+	testMentions       map[string]*gtsmodel.Mention
+	testFilters        map[string]*gtsmodel.Filter
+	testFilterKeywords map[string]*gtsmodel.FilterKeyword
+	testFilterStatuses map[string]*gtsmodel.FilterStatus
 
 	typeconverter typeutils.TypeConverter
 }
@@ -502,6 +505,9 @@ func (suite *TypeUtilsTestSuite) SetupTest() {
 	suite.testEmojis = testrig.NewTestEmojis()
 	suite.testReports = testrig.NewTestReports()
 	suite.testMentions = testrig.NewTestMentions()
+	suite.testFilters = testrig.NewTestFilters()
+	suite.testFilterKeywords = testrig.NewTestFilterKeywords()
+	suite.testFilterStatuses = testrig.NewTestFilterStatuses()
 	suite.typeconverter = typeutils.NewConverter(suite.db)
 
 	testrig.StandardDBSetup(suite.db, nil)
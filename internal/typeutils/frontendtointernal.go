@@ -20,6 +20,7 @@ package typeutils
 import (
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
 func APIVisToVis(m apimodel.Visibility) gtsmodel.Visibility {
@@ -47,3 +48,39 @@ func APIMarkerNameToMarkerName(m apimodel.MarkerName) gtsmodel.MarkerName {
 	}
 	return ""
 }
+
+func APIFilterActionToFilterAction(m apimodel.FilterAction) gtsmodel.FilterAction {
+	switch m {
+	case apimodel.FilterActionWarn:
+		return gtsmodel.FilterActionWarn
+	case apimodel.FilterActionHide:
+		return gtsmodel.FilterActionHide
+	}
+	return ""
+}
+
+// APIFilterContextsToFilter sets the context flags of the given
+// filter from the given api filter contexts: contexts which
+// are included are set to true, all others are set to false.
+func APIFilterContextsToFilter(contexts []apimodel.FilterContext, filter *gtsmodel.Filter) {
+	filter.ContextHome = util.Ptr(false)
+	filter.ContextNotifications = util.Ptr(false)
+	filter.ContextPublic = util.Ptr(false)
+	filter.ContextThread = util.Ptr(false)
+	filter.ContextAccount = util.Ptr(false)
+
+	for _, context := range contexts {
+		switch context {
+		case apimodel.FilterContextHome:
+			filter.ContextHome = util.Ptr(true)
+		case apimodel.FilterContextNotifications:
+			filter.ContextNotifications = util.Ptr(true)
+		case apimodel.FilterContextPublic:
+			filter.ContextPublic = util.Ptr(true)
+		case apimodel.FilterContextThread:
+			filter.ContextThread = util.Ptr(true)
+		case apimodel.FilterContextAccount:
+			filter.ContextAccount = util.Ptr(true)
+		}
+	}
+}
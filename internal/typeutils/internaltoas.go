@@ -29,8 +29,10 @@ import (
 	"github.com/superseriousbusiness/activity/pub"
 	"github.com/superseriousbusiness/activity/streams"
 	"github.com/superseriousbusiness/activity/streams/vocab"
+	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtscontext"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/log"
@@ -170,7 +172,30 @@ func (c *converter) AccountToAS(ctx context.Context, a *gtsmodel.Account) (vocab
 
 	// alsoKnownAs
 	// Required for Move activity.
-	// TODO: NOT IMPLEMENTED **YET** -- this needs to be added as an activitypub extension to https://github.com/go-fed/activity, see https://github.com/go-fed/activity/tree/master/astool
+	// Not part of our AS vocabulary, so set as unknown property.
+	if len(a.AlsoKnownAsURIs) != 0 {
+		alsoKnownAs := make([]interface{}, 0, len(a.AlsoKnownAsURIs))
+		for _, uri := range a.AlsoKnownAsURIs {
+			alsoKnownAs = append(alsoKnownAs, uri)
+		}
+		person.GetUnknownProperties()[ap.PropertyAlsoKnownAs] = alsoKnownAs
+	}
+
+	// movedTo
+	// Set if this account has moved to another account.
+	// Not part of our AS vocabulary, so set as unknown property.
+	if a.MovedToAccountID != "" {
+		if a.MovedTo == nil {
+			a.MovedTo, err = c.db.GetAccountByID(
+				gtscontext.SetBarebones(ctx),
+				a.MovedToAccountID,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("AccountToAS: error getting moved to account %s from database: %w", a.MovedToAccountID, err)
+			}
+		}
+		person.GetUnknownProperties()[ap.PropertyMovedTo] = a.MovedTo.URI
+	}
 
 	// publicKey
 	// Required for signatures.
@@ -401,7 +426,7 @@ func (c *converter) AccountToASMinimal(ctx context.Context, a *gtsmodel.Account)
 	return person, nil
 }
 
-func (c *converter) StatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.ActivityStreamsNote, error) {
+func (c *converter) StatusToAS(ctx context.Context, s *gtsmodel.Status) (ap.Statusable, error) {
 	// ensure prerequisites here before we get stuck in
 
 	// check if author account is already attached to status and attach it if not
@@ -414,8 +439,26 @@ func (c *converter) StatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.A
 		s.Account = a
 	}
 
-	// create the Note!
-	status := streams.NewActivityStreamsNote()
+	// check if poll is already attached to status and attach it if not
+	if s.PollID != "" && s.Poll == nil {
+		p, err := c.db.GetPollByID(ctx, s.PollID)
+		if err != nil {
+			return nil, gtserror.Newf("error retrieving poll from db: %w", err)
+		}
+		s.Poll = p
+	}
+
+	// create the Note, or the Question if this status contains a poll!
+	var status ap.Statusable
+	if s.Poll == nil {
+		status = streams.NewActivityStreamsNote()
+	} else {
+		question := streams.NewActivityStreamsQuestion()
+		if err := c.addPollToAS(s.Poll, question); err != nil {
+			return nil, gtserror.Newf("error converting poll: %w", err)
+		}
+		status = question
+	}
 
 	// id
 	statusURI, err := url.Parse(s.URI)
@@ -451,6 +494,13 @@ func (c *converter) StatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.A
 	publishedProp.Set(s.CreatedAt)
 	status.SetActivityStreamsPublished(publishedProp)
 
+	// updated
+	if !s.EditedAt.IsZero() {
+		updatedProp := streams.NewActivityStreamsUpdatedProperty()
+		updatedProp.Set(s.EditedAt)
+		status.SetActivityStreamsUpdated(updatedProp)
+	}
+
 	// url
 	if s.URL != "" {
 		sURL, err := url.Parse(s.URL)
@@ -617,7 +667,9 @@ func (c *converter) StatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.A
 		if err != nil {
 			return nil, gtserror.Newf("error converting attachment: %w", err)
 		}
-		attachmentProp.AppendActivityStreamsDocument(doc)
+		if err := attachmentProp.AppendType(doc); err != nil {
+			return nil, gtserror.Newf("error appending attachment: %w", err)
+		}
 	}
 	status.SetActivityStreamsAttachment(attachmentProp)
 
@@ -639,6 +691,81 @@ func (c *converter) StatusToAS(ctx context.Context, s *gtsmodel.Status) (vocab.A
 	return status, nil
 }
 
+// addPollToAS sets the options, end time, closed
+// time and voter count of poll on the given Pollable.
+func (c *converter) addPollToAS(poll *gtsmodel.Poll, dst ap.Pollable) error {
+	var optionsProp interface {
+		// the minimum interface for appending AS Notes
+		// to an AS type options property of some kind.
+		AppendActivityStreamsNote(vocab.ActivityStreamsNote)
+	}
+
+	if len(poll.Options) != len(poll.Votes) {
+		return gtserror.Newf("invalid poll %s", poll.ID)
+	}
+
+	if !*poll.HideCounts {
+		// Set total no. voting accounts.
+		voters := streams.NewTootVotersCountProperty()
+		voters.Set(*poll.Voters)
+		dst.SetTootVotersCount(voters)
+	}
+
+	if *poll.Multiple {
+		// Create new multiple-choice (AnyOf) property for poll.
+		anyOfProp := streams.NewActivityStreamsAnyOfProperty()
+		dst.SetActivityStreamsAnyOf(anyOfProp)
+		optionsProp = anyOfProp
+	} else {
+		// Create new single-choice (OneOf) property for poll.
+		oneOfProp := streams.NewActivityStreamsOneOfProperty()
+		dst.SetActivityStreamsOneOf(oneOfProp)
+		optionsProp = oneOfProp
+	}
+
+	for i, name := range poll.Options {
+		// Create new Note object to represent option.
+		note := streams.NewActivityStreamsNote()
+
+		// Create new name property and set the option name.
+		nameProp := streams.NewActivityStreamsNameProperty()
+		nameProp.AppendXMLSchemaString(name)
+		note.SetActivityStreamsName(nameProp)
+
+		if !*poll.HideCounts {
+			// Create new total items property to hold the vote count.
+			totalItemsProp := streams.NewActivityStreamsTotalItemsProperty()
+			totalItemsProp.Set(poll.Votes[i])
+
+			// Create new replies property with collection to encompass count.
+			repliesProp := streams.NewActivityStreamsRepliesProperty()
+			collection := streams.NewActivityStreamsCollection()
+			collection.SetActivityStreamsTotalItems(totalItemsProp)
+			repliesProp.SetActivityStreamsCollection(collection)
+
+			// Attach the replies to Note object.
+			note.SetActivityStreamsReplies(repliesProp)
+		}
+
+		// Append the note to options property.
+		optionsProp.AppendActivityStreamsNote(note)
+	}
+
+	// Set poll endTime property.
+	endTimeProp := streams.NewActivityStreamsEndTimeProperty()
+	endTimeProp.Set(poll.ExpiresAt)
+	dst.SetActivityStreamsEndTime(endTimeProp)
+
+	if !poll.ClosedAt.IsZero() {
+		// Poll is closed, set closed property.
+		closedProp := streams.NewActivityStreamsClosedProperty()
+		closedProp.AppendXMLSchemaDateTime(poll.ClosedAt)
+		dst.SetActivityStreamsClosed(closedProp)
+	}
+
+	return nil
+}
+
 func (c *converter) StatusToASDelete(ctx context.Context, s *gtsmodel.Status) (vocab.ActivityStreamsDelete, error) {
 	// Parse / fetch some information
 	// we need to create the Delete.
@@ -943,9 +1070,14 @@ func (c *converter) EmojiToAS(ctx context.Context, e *gtsmodel.Emoji) (vocab.Too
 	return emoji, nil
 }
 
-func (c *converter) AttachmentToAS(ctx context.Context, a *gtsmodel.MediaAttachment) (vocab.ActivityStreamsDocument, error) {
-	// type -- Document
-	doc := streams.NewActivityStreamsDocument()
+func (c *converter) AttachmentToAS(ctx context.Context, a *gtsmodel.MediaAttachment) (ap.Attachmentable, error) {
+	// type -- Audio for audio, else Document
+	var doc ap.Attachmentable
+	if a.Type == gtsmodel.FileTypeAudio {
+		doc = streams.NewActivityStreamsAudio()
+	} else {
+		doc = streams.NewActivityStreamsDocument()
+	}
 
 	// mediaType aka mime content type
 	mediaTypeProp := streams.NewActivityStreamsMediaTypeProperty()
@@ -1398,12 +1530,12 @@ func (c *converter) StatusesToASOutboxPage(ctx context.Context, outboxID string,
 	var highest string
 	var lowest string
 	for _, s := range statuses {
-		note, err := c.StatusToAS(ctx, s)
+		statusable, err := c.StatusToAS(ctx, s)
 		if err != nil {
 			return nil, err
 		}
 
-		create, err := c.WrapNoteInCreate(note, true)
+		create, err := c.WrapStatusableInCreate(statusable, true)
 		if err != nil {
 			return nil, err
 		}
@@ -1556,3 +1688,103 @@ func (c *converter) ReportToASFlag(ctx context.Context, r *gtsmodel.Report) (voc
 
 	return flag, nil
 }
+
+func (c *converter) PollVoteToASCreates(ctx context.Context, vote *gtsmodel.PollVote) ([]vocab.ActivityStreamsCreate, error) {
+	if vote.Account == nil {
+		a, err := c.db.GetAccountByID(ctx, vote.AccountID)
+		if err != nil {
+			return nil, gtserror.Newf("error getting vote account from db: %w", err)
+		}
+		vote.Account = a
+	}
+
+	if vote.Poll == nil {
+		p, err := c.db.GetPollByID(ctx, vote.PollID)
+		if err != nil {
+			return nil, gtserror.Newf("error getting vote poll from db: %w", err)
+		}
+		vote.Poll = p
+	}
+
+	if vote.Poll.Status == nil {
+		s, err := c.db.GetStatusByID(ctx, vote.Poll.StatusID)
+		if err != nil {
+			return nil, gtserror.Newf("error getting poll status from db: %w", err)
+		}
+		vote.Poll.Status = s
+	}
+
+	// Get the vote author (voter) and poll status URIs.
+	accountIRI, err := url.Parse(vote.Account.URI)
+	if err != nil {
+		return nil, gtserror.Newf("error parsing url %s: %w", vote.Account.URI, err)
+	}
+
+	statusIRI, err := url.Parse(vote.Poll.Status.URI)
+	if err != nil {
+		return nil, gtserror.Newf("error parsing url %s: %w", vote.Poll.Status.URI, err)
+	}
+
+	// Votes are addressed only to the poll author.
+	authorIRI, err := url.Parse(vote.Poll.Status.AccountURI)
+	if err != nil {
+		return nil, gtserror.Newf("error parsing url %s: %w", vote.Poll.Status.AccountURI, err)
+	}
+
+	// Each choice gets its own Create of a Note, named
+	// after the option, which is the format that is
+	// expected by other (i.e. mastodon) implementations.
+	creates := make([]vocab.ActivityStreamsCreate, 0, len(vote.Choices))
+	for _, choice := range vote.Choices {
+		if choice < 0 || choice >= len(vote.Poll.Options) {
+			return nil, gtserror.Newf("invalid choice %d for poll %s", choice, vote.PollID)
+		}
+
+		// Derive a unique ID for this specific vote choice.
+		noteIRI, err := url.Parse(fmt.Sprintf("%s#votes/%s/%d", vote.Account.URI, vote.ID, choice))
+		if err != nil {
+			return nil, gtserror.Newf("error parsing vote url: %w", err)
+		}
+
+		note := streams.NewActivityStreamsNote()
+
+		// id
+		noteIDProp := streams.NewJSONLDIdProperty()
+		noteIDProp.SetIRI(noteIRI)
+		note.SetJSONLDId(noteIDProp)
+
+		// name (i.e. the voted-for option)
+		nameProp := streams.NewActivityStreamsNameProperty()
+		nameProp.AppendXMLSchemaString(vote.Poll.Options[choice])
+		note.SetActivityStreamsName(nameProp)
+
+		// inReplyTo (i.e. the poll)
+		inReplyToProp := streams.NewActivityStreamsInReplyToProperty()
+		inReplyToProp.AppendIRI(statusIRI)
+		note.SetActivityStreamsInReplyTo(inReplyToProp)
+
+		// attributedTo
+		attributedToProp := streams.NewActivityStreamsAttributedToProperty()
+		attributedToProp.AppendIRI(accountIRI)
+		note.SetActivityStreamsAttributedTo(attributedToProp)
+
+		// published
+		publishedProp := streams.NewActivityStreamsPublishedProperty()
+		publishedProp.Set(vote.CreatedAt)
+		note.SetActivityStreamsPublished(publishedProp)
+
+		// to
+		toProp := streams.NewActivityStreamsToProperty()
+		toProp.AppendIRI(authorIRI)
+		note.SetActivityStreamsTo(toProp)
+
+		create, err := c.WrapStatusableInCreate(note, false)
+		if err != nil {
+			return nil, gtserror.Newf("error wrapping vote in create: %w", err)
+		}
+
+		creates = append(creates, create)
+	}
+
+	return creates, nil
+}
@@ -361,6 +361,84 @@ func (suite *InternalToASTestSuite) TestStatusToAS() {
 }`, string(bytes))
 }
 
+func (suite *InternalToASTestSuite) TestStatusWithPollToAS() {
+	testStatus := new(gtsmodel.Status)
+	*testStatus = *suite.testStatuses["local_account_1_status_1"]
+	ctx := context.Background()
+
+	multiple := false
+	hideCounts := false
+	voters := 3
+	testStatus.Poll = &gtsmodel.Poll{
+		ID:         "01HEN2QRFA8H3C6QPN7RD4KSR6",
+		Multiple:   &multiple,
+		HideCounts: &hideCounts,
+		Options:    []string{"yes", "no"},
+		Votes:      []int{2, 1},
+		Voters:     &voters,
+		StatusID:   testStatus.ID,
+		ExpiresAt:  testrig.TimeMustParse("2021-10-21T12:40:37+02:00"),
+	}
+	testStatus.PollID = testStatus.Poll.ID
+
+	asStatus, err := suite.typeconverter.StatusToAS(ctx, testStatus)
+	suite.NoError(err)
+
+	ser, err := ap.Serialize(asStatus)
+	suite.NoError(err)
+
+	bytes, err := json.MarshalIndent(ser, "", "  ")
+	suite.NoError(err)
+
+	// trim off everything up to 'attachment';
+	// this is necessary because the order of multiple 'context' entries is not determinate
+	trimmed := strings.Split(string(bytes), "\"attachment\"")[1]
+
+	suite.Equal(`: [],
+  "attributedTo": "http://localhost:8080/users/the_mighty_zork",
+  "cc": "http://localhost:8080/users/the_mighty_zork/followers",
+  "content": "hello everyone!",
+  "endTime": "2021-10-21T12:40:37+02:00",
+  "id": "http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY",
+  "oneOf": [
+    {
+      "name": "yes",
+      "replies": {
+        "totalItems": 2,
+        "type": "Collection"
+      },
+      "type": "Note"
+    },
+    {
+      "name": "no",
+      "replies": {
+        "totalItems": 1,
+        "type": "Collection"
+      },
+      "type": "Note"
+    }
+  ],
+  "published": "2021-10-20T12:40:37+02:00",
+  "replies": {
+    "first": {
+      "id": "http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies?page=true",
+      "next": "http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies?only_other_accounts=false\u0026page=true",
+      "partOf": "http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies",
+      "type": "CollectionPage"
+    },
+    "id": "http://localhost:8080/users/the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY/replies",
+    "type": "Collection"
+  },
+  "sensitive": true,
+  "summary": "introduction post",
+  "tag": [],
+  "to": "https://www.w3.org/ns/activitystreams#Public",
+  "type": "Question",
+  "url": "http://localhost:8080/@the_mighty_zork/statuses/01F8MHAMCHF6Y650WCRSCP4WMY",
+  "votersCount": 3
+}`, trimmed)
+}
+
 func (suite *InternalToASTestSuite) TestStatusWithTagsToASWithIDs() {
 	// use the status with just IDs of attachments and emojis pinned on it
 	testStatus := suite.testStatuses["admin_account_status_1"]
@@ -771,6 +849,38 @@ func (suite *InternalToASTestSuite) TestReportToAS() {
 }`, string(bytes))
 }
 
+func (suite *InternalToASTestSuite) TestAttachmentToASAudio() {
+	ctx := context.Background()
+
+	// take a copy of an existing attachment and pretend it's audio
+	attachment := &gtsmodel.MediaAttachment{}
+	*attachment = *suite.testAttachments["admin_account_status_1_attachment_1"]
+	attachment.Type = gtsmodel.FileTypeAudio
+	attachment.URL = "http://localhost:8080/fileserver/01F8MH17FWEB39HZJ76B6VXSKF/attachment/original/01F8MH6NEM8D7527KZAECTCR76.mp3"
+	attachment.File.ContentType = "audio/mpeg"
+
+	asAttachment, err := suite.typeconverter.AttachmentToAS(ctx, attachment)
+	suite.NoError(err)
+
+	ser, err := ap.Serialize(asAttachment)
+	suite.NoError(err)
+
+	bytes, err := json.MarshalIndent(ser, "", "  ")
+	suite.NoError(err)
+
+	suite.Equal(`{
+  "@context": [
+    "https://www.w3.org/ns/activitystreams",
+    "http://joinmastodon.org/ns"
+  ],
+  "blurhash": "LNJRdVM{00Rj%Mayt7j[4nWBofRj",
+  "mediaType": "audio/mpeg",
+  "name": "Black and white image of some 50's style text saying: Welcome On Board",
+  "type": "Audio",
+  "url": "http://localhost:8080/fileserver/01F8MH17FWEB39HZJ76B6VXSKF/attachment/original/01F8MH6NEM8D7527KZAECTCR76.mp3"
+}`, string(bytes))
+}
+
 func (suite *InternalToASTestSuite) TestPinnedStatusesToASSomeItems() {
 	ctx := context.Background()
 
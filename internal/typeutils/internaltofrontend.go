@@ -24,14 +24,18 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"time"
 
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtscontext"
 	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/log"
 	"github.com/superseriousbusiness/gotosocial/internal/media"
+	"github.com/superseriousbusiness/gotosocial/internal/statusfilter"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
 	"github.com/superseriousbusiness/gotosocial/internal/uris"
 	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
@@ -86,6 +90,7 @@ func (c *converter) AccountToAPIAccountSensitive(ctx context.Context, a *gtsmode
 		Note:                a.NoteRaw,
 		Fields:              c.fieldsToAPIFields(a.FieldsRaw),
 		FollowRequestsCount: frc,
+		AlsoKnownAsURIs:     a.AlsoKnownAsURIs,
 	}
 
 	return apiAccount, nil
@@ -230,6 +235,21 @@ func (c *converter) AccountToAPIAccountPublic(ctx context.Context, a *gtsmodel.A
 		Role:           role,
 	}
 
+	if a.MovedTo != nil {
+		// Convert the account this account moved to, but only
+		// one level deep: copy it and drop its own moved-to
+		// account to avoid endlessly following a move chain.
+		movedTo := new(gtsmodel.Account)
+		*movedTo = *a.MovedTo
+		movedTo.MovedToAccountID = ""
+		movedTo.MovedTo = nil
+
+		accountFrontend.Moved, err = c.AccountToAPIAccountPublic(ctx, movedTo)
+		if err != nil {
+			return nil, fmt.Errorf("AccountToAPIAccountPublic: error converting moved to account %s: %w", movedTo.ID, err)
+		}
+	}
+
 	// Bodge default avatar + header in,
 	// if we didn't have one already.
 	c.ensureAvatar(accountFrontend)
@@ -320,6 +340,7 @@ func (c *converter) AccountToAdminAPIAccount(ctx context.Context, a *gtsmodel.Ac
 		disabled               bool
 		role                   = apimodel.AccountRole{Name: apimodel.AccountRoleUser} // assume user by default
 		createdByApplicationID string
+		invitedByAccountID     string
 	)
 
 	if a.IsRemote() {
@@ -364,6 +385,22 @@ func (c *converter) AccountToAdminAPIAccount(ctx context.Context, a *gtsmodel.Ac
 		approved = *user.Approved
 		disabled = *user.Disabled
 		createdByApplicationID = user.CreatedByApplicationID
+
+		if user.InviteID != "" {
+			// User signed up with an invite,
+			// try to find out who invited them.
+			invite, err := c.db.GetInviteByID(ctx, user.InviteID)
+			if err != nil && !errors.Is(err, db.ErrNoEntries) {
+				return nil, fmt.Errorf("AccountToAdminAPIAccount: error getting invite %s for account id %s: %w", user.InviteID, a.ID, err)
+			}
+
+			if invite != nil {
+				if err := c.db.PopulateInvite(ctx, invite); err != nil {
+					return nil, fmt.Errorf("AccountToAdminAPIAccount: error populating invite %s for account id %s: %w", user.InviteID, a.ID, err)
+				}
+				invitedByAccountID = invite.User.AccountID
+			}
+		}
 	}
 
 	apiAccount, err := c.AccountToAPIAccountPublic(ctx, a)
@@ -389,7 +426,7 @@ func (c *converter) AccountToAdminAPIAccount(ctx context.Context, a *gtsmodel.Ac
 		Suspended:              !a.SuspendedAt.IsZero(),
 		Account:                apiAccount,
 		CreatedByApplicationID: createdByApplicationID,
-		InvitedByAccountID:     "", // not implemented (yet)
+		InvitedByAccountID:     invitedByAccountID,
 	}, nil
 }
 
@@ -471,6 +508,14 @@ func (c *converter) AttachmentToAPIAttachment(ctx context.Context, a *gtsmodel.M
 			apiAttachment.Meta.Original.FrameRate = fr + "/1"
 		}
 
+		if i := a.FileMeta.Original.Bitrate; i != nil {
+			apiAttachment.Meta.Original.Bitrate = int(*i)
+		}
+	case gtsmodel.FileTypeAudio:
+		if i := a.FileMeta.Original.Duration; i != nil {
+			apiAttachment.Meta.Original.Duration = *i
+		}
+
 		if i := a.FileMeta.Original.Bitrate; i != nil {
 			apiAttachment.Meta.Original.Bitrate = int(*i)
 		}
@@ -584,7 +629,13 @@ func (c *converter) TagToAPITag(ctx context.Context, t *gtsmodel.Tag, stubHistor
 	}, nil
 }
 
-func (c *converter) StatusToAPIStatus(ctx context.Context, s *gtsmodel.Status, requestingAccount *gtsmodel.Account) (*apimodel.Status, error) {
+func (c *converter) StatusToAPIStatus(
+	ctx context.Context,
+	s *gtsmodel.Status,
+	requestingAccount *gtsmodel.Account,
+	filterContext statusfilter.FilterContext,
+	filters []*gtsmodel.Filter,
+) (*apimodel.Status, error) {
 	if err := c.db.PopulateStatus(ctx, s); err != nil {
 		// Ensure author account present + correct;
 		// can't really go further without this!
@@ -671,7 +722,6 @@ func (c *converter) StatusToAPIStatus(ctx context.Context, s *gtsmodel.Status, r
 		Tags:               apiTags,
 		Emojis:             apiEmojis,
 		Card:               nil, // TODO: implement cards
-		Poll:               nil, // TODO: implement polls
 		Text:               s.Text,
 	}
 
@@ -689,8 +739,26 @@ func (c *converter) StatusToAPIStatus(ctx context.Context, s *gtsmodel.Status, r
 		apiStatus.Language = func() *string { i := s.Language; return &i }()
 	}
 
+	if !s.EditedAt.IsZero() {
+		apiStatus.EditedAt = util.Ptr(util.FormatISO8601(s.EditedAt))
+	}
+
+	if s.Poll != nil {
+		apiPoll, err := c.PollToAPIPoll(ctx, requestingAccount, s.Poll)
+		if err != nil {
+			return nil, fmt.Errorf("error converting poll: %w", err)
+		}
+
+		apiStatus.Poll = apiPoll
+	}
+
 	if s.BoostOf != nil {
-		apiBoostOf, err := c.StatusToAPIStatus(ctx, s.BoostOf, requestingAccount)
+		apiBoostOf, err := c.StatusToAPIStatus(ctx, s.BoostOf, requestingAccount, filterContext, filters)
+		if errors.Is(err, statusfilter.ErrHideStatus) {
+			// Boosted status is hidden by a filter,
+			// so the boost should be hidden too.
+			return nil, err
+		}
 		if err != nil {
 			return nil, fmt.Errorf("error converting boosted status: %w", err)
 		}
@@ -712,6 +780,16 @@ func (c *converter) StatusToAPIStatus(ctx context.Context, s *gtsmodel.Status, r
 		apiStatus.Application = apiApp
 	}
 
+	// Apply filters.
+	filtered, err := c.statusToAPIFilterResults(ctx, s, requestingAccount, filterContext, filters)
+	if err != nil {
+		if errors.Is(err, statusfilter.ErrHideStatus) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error applying filters: %w", err)
+	}
+	apiStatus.Filtered = filtered
+
 	// Normalization.
 
 	if s.URL == "" {
@@ -723,6 +801,185 @@ func (c *converter) StatusToAPIStatus(ctx context.Context, s *gtsmodel.Status, r
 	return apiStatus, nil
 }
 
+func (c *converter) StatusToAPIEdits(ctx context.Context, s *gtsmodel.Status) ([]*apimodel.StatusEdit, error) {
+	if err := c.db.PopulateStatus(ctx, s); err != nil {
+		// Ensure author account present + correct;
+		// can't really go further without this!
+		if s.Account == nil {
+			return nil, fmt.Errorf("error(s) populating status, cannot continue: %w", err)
+		}
+
+		log.Errorf(ctx, "error(s) populating status, will continue: %v", err)
+	}
+
+	apiAuthorAccount, err := c.AccountToAPIAccountPublic(ctx, s.Account)
+	if err != nil {
+		return nil, fmt.Errorf("error converting status author: %w", err)
+	}
+
+	apiEmojis, err := c.convertEmojisToAPIEmojis(ctx, s.Emojis, s.EmojiIDs)
+	if err != nil {
+		log.Errorf(ctx, "error converting status emojis: %v", err)
+	}
+
+	// Preallocate slice to hold all historical
+	// edits, plus the current version of status.
+	apiEdits := make([]*apimodel.StatusEdit, 0, len(s.Edits)+1)
+
+	for _, edit := range s.Edits {
+		if err := c.db.PopulateStatusEdit(ctx, edit); err != nil {
+			log.Errorf(ctx, "error populating status edit %s: %v", edit.ID, err)
+		}
+
+		apiAttachments, err := c.convertAttachmentsToAPIAttachments(ctx, edit.Attachments, edit.AttachmentIDs)
+		if err != nil {
+			log.Errorf(ctx, "error converting status edit attachments: %v", err)
+		}
+
+		apiEdits = append(apiEdits, &apimodel.StatusEdit{
+			Content:          edit.Content,
+			SpoilerText:      edit.ContentWarning,
+			Sensitive:        *edit.Sensitive,
+			CreatedAt:        util.FormatISO8601(edit.CreatedAt),
+			Account:          apiAuthorAccount,
+			Poll:             pollOptionsToAPIEditPoll(edit.PollOptions),
+			MediaAttachments: apiAttachments,
+			Emojis:           apiEmojis,
+		})
+	}
+
+	apiAttachments, err := c.convertAttachmentsToAPIAttachments(ctx, s.Attachments, s.AttachmentIDs)
+	if err != nil {
+		log.Errorf(ctx, "error converting status attachments: %v", err)
+	}
+
+	// The current version was created
+	// either at last edit, or creation.
+	createdAt := s.EditedAt
+	if createdAt.IsZero() {
+		createdAt = s.CreatedAt
+	}
+
+	var pollOptions []string
+	if s.Poll != nil {
+		pollOptions = s.Poll.Options
+	}
+
+	// Finally, append the current version.
+	apiEdits = append(apiEdits, &apimodel.StatusEdit{
+		Content:          s.Content,
+		SpoilerText:      s.ContentWarning,
+		Sensitive:        *s.Sensitive,
+		CreatedAt:        util.FormatISO8601(createdAt),
+		Account:          apiAuthorAccount,
+		Poll:             pollOptionsToAPIEditPoll(pollOptions),
+		MediaAttachments: apiAttachments,
+		Emojis:           apiEmojis,
+	})
+
+	return apiEdits, nil
+}
+
+// pollOptionsToAPIEditPoll converts the given
+// poll options to the poll representation used
+// in status edits, returning nil if none given.
+func pollOptionsToAPIEditPoll(options []string) *apimodel.StatusEditPoll {
+	if len(options) == 0 {
+		return nil
+	}
+
+	apiOptions := make([]apimodel.StatusEditPollOption, len(options))
+	for i, option := range options {
+		apiOptions[i].Title = option
+	}
+
+	return &apimodel.StatusEditPoll{Options: apiOptions}
+}
+
+func (c *converter) PollToAPIPoll(ctx context.Context, requester *gtsmodel.Account, poll *gtsmodel.Poll) (*apimodel.Poll, error) {
+	var (
+		totalVotes int
+		voters     *int
+		voted      *bool
+		ownChoices *[]int
+		isAuthor   bool
+		expiresAt  *string
+	)
+
+	// Ensure the poll's source status is set,
+	// we need this to check the poll's author.
+	if poll.Status == nil {
+		status, err := c.db.GetStatusByID(gtscontext.SetBarebones(ctx), poll.StatusID)
+		if err != nil {
+			return nil, gtserror.Newf("error getting status for poll %s: %w", poll.ID, err)
+		}
+		poll.Status = status
+	}
+
+	if requester != nil {
+		// Get vote by requester in poll (if any).
+		vote, err := c.db.GetPollVoteBy(ctx, poll.ID, requester.ID)
+		if err != nil && !errors.Is(err, db.ErrNoEntries) {
+			return nil, gtserror.Newf("error getting vote for poll %s: %w", poll.ID, err)
+		}
+
+		if vote != nil {
+			// Set choices by requester.
+			ownChoices = &vote.Choices
+		} else {
+			// Requester hasn't voted yet.
+			ownChoices = new([]int)
+		}
+
+		voted = util.Ptr(vote != nil)
+
+		// Check if requester is author of source status.
+		isAuthor = (requester.ID == poll.Status.AccountID)
+	}
+
+	// Ensure we have vote counts.
+	poll.CheckVotes()
+
+	// Preallocate a slice of frontend model poll options.
+	options := make([]apimodel.PollOption, len(poll.Options))
+
+	// Only show vote counts to requesters if the poll
+	// doesn't hide them, the poll has ended, or the
+	// requester is the author of the poll itself.
+	showCounts := !*poll.HideCounts || isAuthor || poll.Closed()
+
+	for i, title := range poll.Options {
+		options[i].Title = title
+		totalVotes += poll.Votes[i]
+
+		if showCounts {
+			options[i].VotesCount = util.Ptr(poll.Votes[i])
+		}
+	}
+
+	if showCounts {
+		voters = util.Ptr(*poll.Voters)
+	}
+
+	if !poll.ExpiresAt.IsZero() {
+		// Poll has a set expiry time.
+		expiresAt = util.Ptr(util.FormatISO8601(poll.ExpiresAt))
+	}
+
+	return &apimodel.Poll{
+		ID:          poll.ID,
+		ExpiresAt:   expiresAt,
+		Expired:     poll.Closed() || poll.Expired(),
+		Multiple:    *poll.Multiple,
+		VotesCount:  totalVotes,
+		VotersCount: voters,
+		Voted:       voted,
+		OwnVotes:    ownChoices,
+		Options:     options,
+		Emojis:      []apimodel.Emoji{},
+	}, nil
+}
+
 // VisToapi converts a gts visibility into its api equivalent
 func (c *converter) VisToAPIVis(ctx context.Context, m gtsmodel.Visibility) apimodel.Visibility {
 	switch m {
@@ -750,10 +1007,16 @@ func (c *converter) InstanceToAPIV1Instance(ctx context.Context, i *gtsmodel.Ins
 		Languages:        []string{}, // todo: not supported yet
 		Registrations:    config.GetAccountsRegistrationOpen(),
 		ApprovalRequired: config.GetAccountsApprovalRequired(),
-		InvitesEnabled:   false, // todo: not supported yet
+		InvitesEnabled:   config.GetAccountsAllowInvites(),
 		MaxTootChars:     uint(config.GetStatusesMaxChars()),
 	}
 
+	rules, err := c.instanceRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("InstanceToAPIV1Instance: %w", err)
+	}
+	instance.Rules = rules
+
 	if config.GetInstanceInjectMastodonVersion() {
 		instance.Version = toMastodonVersion(instance.Version)
 	}
@@ -854,9 +1117,14 @@ func (c *converter) InstanceToAPIV2Instance(ctx context.Context, i *gtsmodel.Ins
 		Description:   i.Description,
 		Usage:         apimodel.InstanceV2Usage{}, // todo: not implemented
 		Languages:     []string{},                 // todo: not implemented
-		Rules:         []interface{}{},            // todo: not implemented
 	}
 
+	rules, err := c.instanceRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("InstanceToAPIV2Instance: %w", err)
+	}
+	instance.Rules = rules
+
 	if config.GetInstanceInjectMastodonVersion() {
 		instance.Version = toMastodonVersion(instance.Version)
 	}
@@ -953,7 +1221,7 @@ func (c *converter) RelationshipToAPIRelationship(ctx context.Context, r *gtsmod
 	}, nil
 }
 
-func (c *converter) NotificationToAPINotification(ctx context.Context, n *gtsmodel.Notification) (*apimodel.Notification, error) {
+func (c *converter) NotificationToAPINotification(ctx context.Context, n *gtsmodel.Notification, filters []*gtsmodel.Filter) (*apimodel.Notification, error) {
 	if n.TargetAccount == nil {
 		tAccount, err := c.db.GetAccountByID(ctx, n.TargetAccountID)
 		if err != nil {
@@ -994,7 +1262,10 @@ func (c *converter) NotificationToAPINotification(ctx context.Context, n *gtsmod
 		}
 
 		var err error
-		apiStatus, err = c.StatusToAPIStatus(ctx, n.Status, n.TargetAccount)
+		apiStatus, err = c.StatusToAPIStatus(ctx, n.Status, n.TargetAccount, statusfilter.FilterContextNotifications, filters)
+		if errors.Is(err, statusfilter.ErrHideStatus) {
+			return nil, err
+		}
 		if err != nil {
 			return nil, fmt.Errorf("NotificationToapi: error converting status to api: %s", err)
 		}
@@ -1042,16 +1313,73 @@ func (c *converter) DomainBlockToAPIDomainBlock(ctx context.Context, b *gtsmodel
 	return domainBlock, nil
 }
 
+func (c *converter) DomainAllowToAPIDomainAllow(ctx context.Context, a *gtsmodel.DomainAllow, export bool) (*apimodel.DomainAllow, error) {
+	// Domain may be in Punycode,
+	// de-punify it just in case.
+	d, err := util.DePunify(a.Domain)
+	if err != nil {
+		return nil, fmt.Errorf("DomainAllowToAPIDomainAllow: error de-punifying domain %s: %w", a.Domain, err)
+	}
+
+	domainAllow := &apimodel.DomainAllow{
+		Domain: apimodel.Domain{
+			Domain:        d,
+			PublicComment: a.PublicComment,
+		},
+	}
+
+	// if we're exporting a domain allow, return it with minimal information attached
+	if !export {
+		domainAllow.ID = a.ID
+		domainAllow.Obfuscate = *a.Obfuscate
+		domainAllow.PrivateComment = a.PrivateComment
+		domainAllow.SubscriptionID = a.SubscriptionID
+		domainAllow.CreatedBy = a.CreatedByAccountID
+		domainAllow.CreatedAt = util.FormatISO8601(a.CreatedAt)
+	}
+
+	return domainAllow, nil
+}
+
+func (c *converter) DomainPermissionSubscriptionToAPIDomainPermissionSubscription(ctx context.Context, s *gtsmodel.DomainPermissionSubscription) (*apimodel.DomainPermissionSubscription, error) {
+	apiSub := &apimodel.DomainPermissionSubscription{
+		ID:             s.ID,
+		Priority:       s.Priority,
+		Title:          s.Title,
+		PermissionType: string(s.PermissionType),
+		AdoptOrphans:   *s.AdoptOrphans,
+		CreatedBy:      s.CreatedByAccountID,
+		CreatedAt:      util.FormatISO8601(s.CreatedAt),
+		URI:            s.URI,
+		ContentType:    s.ContentType,
+		Error:          s.Error,
+	}
+
+	if !s.FetchedAt.IsZero() {
+		apiSub.FetchedAt = util.FormatISO8601(s.FetchedAt)
+	}
+
+	if !s.SuccessfullyFetchedAt.IsZero() {
+		apiSub.SuccessfullyFetchedAt = util.FormatISO8601(s.SuccessfullyFetchedAt)
+	}
+
+	return apiSub, nil
+}
+
 func (c *converter) ReportToAPIReport(ctx context.Context, r *gtsmodel.Report) (*apimodel.Report, error) {
 	report := &apimodel.Report{
 		ID:          r.ID,
 		CreatedAt:   util.FormatISO8601(r.CreatedAt),
 		ActionTaken: !r.ActionTakenAt.IsZero(),
-		Category:    "other", // todo: only support default 'other' category right now
+		Category:    reportCategory(r),
 		Comment:     r.Comment,
 		Forwarded:   *r.Forwarded,
 		StatusIDs:   r.StatusIDs,
-		RuleIDs:     []int{}, // todo: not supported yet
+		RuleIDs:     r.RuleIDs,
+	}
+
+	if report.RuleIDs == nil {
+		report.RuleIDs = []string{}
 	}
 
 	if !r.ActionTakenAt.IsZero() {
@@ -1137,7 +1465,7 @@ func (c *converter) ReportToAdminAPIReport(ctx context.Context, r *gtsmodel.Repo
 		}
 	}
 	for _, s := range r.Statuses {
-		status, err := c.StatusToAPIStatus(ctx, s, requestingAccount)
+		status, err := c.StatusToAPIStatus(ctx, s, requestingAccount, statusfilter.FilterContextNone, nil)
 		if err != nil {
 			return nil, fmt.Errorf("ReportToAdminAPIReport: error converting status with id %s to api status: %w", s.ID, err)
 		}
@@ -1148,11 +1476,22 @@ func (c *converter) ReportToAdminAPIReport(ctx context.Context, r *gtsmodel.Repo
 		actionTakenComment = &ac
 	}
 
+	if len(r.RuleIDs) != 0 && len(r.Rules) == 0 {
+		r.Rules, err = c.db.GetRulesByIDs(ctx, r.RuleIDs)
+		if err != nil {
+			return nil, fmt.Errorf("ReportToAdminAPIReport: error getting rules from the db: %w", err)
+		}
+	}
+	rules := make([]apimodel.InstanceRule, 0, len(r.Rules))
+	for _, rule := range r.Rules {
+		rules = append(rules, c.RuleToAPIRule(rule))
+	}
+
 	return &apimodel.AdminReport{
 		ID:                   r.ID,
 		ActionTaken:          !r.ActionTakenAt.IsZero(),
 		ActionTakenAt:        actionTakenAt,
-		Category:             "other", // todo: only support default 'other' category right now
+		Category:             reportCategory(r),
 		Comment:              r.Comment,
 		Forwarded:            *r.Forwarded,
 		CreatedAt:            util.FormatISO8601(r.CreatedAt),
@@ -1163,10 +1502,52 @@ func (c *converter) ReportToAdminAPIReport(ctx context.Context, r *gtsmodel.Repo
 		ActionTakenByAccount: actionTakenByAccount,
 		ActionTakenComment:   actionTakenComment,
 		Statuses:             statuses,
-		Rules:                []interface{}{}, // not implemented
+		Rules:                rules,
 	}, nil
 }
 
+// reportCategory returns the category of the given report:
+// "violation" if the reporter selected any broken rules,
+// or the default "other" category if not.
+func reportCategory(r *gtsmodel.Report) string {
+	if len(r.RuleIDs) != 0 {
+		return "violation"
+	}
+	return "other"
+}
+
+func (c *converter) RuleToAPIRule(r *gtsmodel.Rule) apimodel.InstanceRule {
+	return apimodel.InstanceRule{
+		ID:   r.ID,
+		Text: r.Text,
+	}
+}
+
+func (c *converter) RuleToAdminAPIRule(r *gtsmodel.Rule) *apimodel.AdminInstanceRule {
+	return &apimodel.AdminInstanceRule{
+		ID:        r.ID,
+		CreatedAt: util.FormatISO8601(r.CreatedAt),
+		UpdatedAt: util.FormatISO8601(r.UpdatedAt),
+		Text:      r.Text,
+	}
+}
+
+// instanceRules returns the active rules of
+// this instance, converted to their api model.
+func (c *converter) instanceRules(ctx context.Context) ([]apimodel.InstanceRule, error) {
+	rules, err := c.db.GetActiveRules(ctx)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return nil, fmt.Errorf("error getting instance rules: %w", err)
+	}
+
+	apiRules := make([]apimodel.InstanceRule, 0, len(rules))
+	for _, rule := range rules {
+		apiRules = append(apiRules, c.RuleToAPIRule(rule))
+	}
+
+	return apiRules, nil
+}
+
 func (c *converter) ListToAPIList(ctx context.Context, l *gtsmodel.List) (*apimodel.List, error) {
 	return &apimodel.List{
 		ID:            l.ID,
@@ -1195,6 +1576,287 @@ func (c *converter) MarkersToAPIMarker(ctx context.Context, markers []*gtsmodel.
 	return apiMarker, nil
 }
 
+func (c *converter) FilterToAPIFiltersV1(ctx context.Context, filter *gtsmodel.Filter) ([]*apimodel.FilterV1, error) {
+	apiFilters := make([]*apimodel.FilterV1, 0, len(filter.Keywords))
+	for _, filterKeyword := range filter.Keywords {
+		// Ensure the keyword's parent filter is set,
+		// without modifying the passed-in keyword.
+		filterKeyword := *filterKeyword
+		filterKeyword.Filter = filter
+
+		apiFilter, err := c.FilterKeywordToAPIFilterV1(ctx, &filterKeyword)
+		if err != nil {
+			return nil, err
+		}
+		apiFilters = append(apiFilters, apiFilter)
+	}
+	return apiFilters, nil
+}
+
+func (c *converter) FilterKeywordToAPIFilterV1(ctx context.Context, filterKeyword *gtsmodel.FilterKeyword) (*apimodel.FilterV1, error) {
+	if filterKeyword.Filter == nil {
+		return nil, gtserror.New("FilterKeyword.Filter must be populated")
+	}
+	filter := filterKeyword.Filter
+
+	return &apimodel.FilterV1{
+		// v1 filters have a single keyword each, so we use the filter keyword ID as the v1 filter ID.
+		ID:           filterKeyword.ID,
+		Phrase:       filterKeyword.Keyword,
+		Context:      filterToAPIFilterContexts(filter),
+		WholeWord:    util.PtrValueOr(filterKeyword.WholeWord, false),
+		ExpiresAt:    filterExpiresAtToAPIFilterExpiresAt(filter.ExpiresAt),
+		Irreversible: filter.Action == gtsmodel.FilterActionHide,
+	}, nil
+}
+
+func (c *converter) FilterToAPIFilterV2(ctx context.Context, filter *gtsmodel.Filter) (*apimodel.FilterV2, error) {
+	apiFilterKeywords := make([]apimodel.FilterKeyword, 0, len(filter.Keywords))
+	for _, filterKeyword := range filter.Keywords {
+		apiFilterKeywords = append(apiFilterKeywords, *c.FilterKeywordToAPIFilterKeyword(ctx, filterKeyword))
+	}
+
+	apiFilterStatuses := make([]apimodel.FilterStatus, 0, len(filter.Statuses))
+	for _, filterStatus := range filter.Statuses {
+		apiFilterStatuses = append(apiFilterStatuses, *c.FilterStatusToAPIFilterStatus(ctx, filterStatus))
+	}
+
+	return &apimodel.FilterV2{
+		ID:           filter.ID,
+		Title:        filter.Title,
+		Context:      filterToAPIFilterContexts(filter),
+		ExpiresAt:    filterExpiresAtToAPIFilterExpiresAt(filter.ExpiresAt),
+		FilterAction: apimodel.FilterAction(filter.Action),
+		Keywords:     apiFilterKeywords,
+		Statuses:     apiFilterStatuses,
+	}, nil
+}
+
+func (c *converter) FilterKeywordToAPIFilterKeyword(ctx context.Context, filterKeyword *gtsmodel.FilterKeyword) *apimodel.FilterKeyword {
+	return &apimodel.FilterKeyword{
+		ID:        filterKeyword.ID,
+		Keyword:   filterKeyword.Keyword,
+		WholeWord: util.PtrValueOr(filterKeyword.WholeWord, false),
+	}
+}
+
+func (c *converter) FilterStatusToAPIFilterStatus(ctx context.Context, filterStatus *gtsmodel.FilterStatus) *apimodel.FilterStatus {
+	return &apimodel.FilterStatus{
+		ID:       filterStatus.ID,
+		StatusID: filterStatus.StatusID,
+	}
+}
+
+func (c *converter) ConversationToAPIConversation(ctx context.Context, conversation *gtsmodel.Conversation, requestingAccount *gtsmodel.Account) (*apimodel.Conversation, error) {
+	if err := c.db.PopulateConversation(ctx, conversation); err != nil {
+		return nil, gtserror.Newf("error populating conversation %s: %w", conversation.ID, err)
+	}
+
+	apiConversation := &apimodel.Conversation{
+		ID:       conversation.ID,
+		Unread:   !*conversation.Read,
+		Accounts: make([]apimodel.Account, 0, len(conversation.OtherAccounts)),
+	}
+
+	for _, account := range conversation.OtherAccounts {
+		apiAccount, err := c.AccountToAPIAccountPublic(ctx, account)
+		if err != nil {
+			return nil, gtserror.Newf("error converting account %s to api account: %w", account.ID, err)
+		}
+		apiConversation.Accounts = append(apiConversation.Accounts, *apiAccount)
+	}
+
+	apiStatus, err := c.StatusToAPIStatus(ctx,
+		conversation.LastStatus,
+		requestingAccount,
+		statusfilter.FilterContextNone,
+		nil,
+	)
+	if err != nil {
+		return nil, gtserror.Newf("error converting status %s to api status: %w", conversation.LastStatusID, err)
+	}
+	apiConversation.LastStatus = apiStatus
+
+	return apiConversation, nil
+}
+
+// statusToAPIFilterResults applies the given filters of the requesting account to the given status,
+// returning api model filter results for any matching "warn" filters, or statusfilter.ErrHideStatus
+// if the status matched any "hide" filter. Filters are only applied for the given filter context.
+func (c *converter) statusToAPIFilterResults(
+	ctx context.Context,
+	s *gtsmodel.Status,
+	requestingAccount *gtsmodel.Account,
+	filterContext statusfilter.FilterContext,
+	filters []*gtsmodel.Filter,
+) ([]apimodel.FilterResult, error) {
+	if filterContext == statusfilter.FilterContextNone ||
+		len(filters) == 0 || requestingAccount == nil {
+		// Nothing to filter with.
+		return nil, nil
+	}
+
+	if s.AccountID == requestingAccount.ID {
+		// Don't filter the
+		// requester's own statuses.
+		return nil, nil
+	}
+
+	var (
+		now     = time.Now()
+		fields  []string
+		results []apimodel.FilterResult
+	)
+
+	for _, filter := range filters {
+		if !filterAppliesInContext(filter, filterContext) {
+			// Filter not applicable here.
+			continue
+		}
+
+		if filter.Expired(now) {
+			// Filter no longer in effect.
+			continue
+		}
+
+		// List all matching keywords.
+		var keywordMatches []string
+		for _, filterKeyword := range filter.Keywords {
+			if filterKeyword.Regexp == nil {
+				if err := filterKeyword.Compile(); err != nil {
+					return nil, gtserror.Newf("error compiling filter keyword %s: %w", filterKeyword.ID, err)
+				}
+			}
+
+			if fields == nil {
+				// Lazily gather the filterable
+				// text fields of the status.
+				fields = filterableTextFields(s)
+			}
+
+			for _, field := range fields {
+				if filterKeyword.Regexp.MatchString(field) {
+					keywordMatches = append(keywordMatches, filterKeyword.Keyword)
+					break
+				}
+			}
+		}
+
+		// A status has only one ID. Not clear
+		// why this is a list in the Mastodon API.
+		var statusMatches []string
+		for _, filterStatus := range filter.Statuses {
+			if filterStatus.StatusID == s.ID {
+				statusMatches = append(statusMatches, s.ID)
+				break
+			}
+		}
+
+		if len(keywordMatches) == 0 && len(statusMatches) == 0 {
+			// Filter didn't match.
+			continue
+		}
+
+		if filter.Action == gtsmodel.FilterActionHide {
+			// Status should be hidden entirely.
+			return nil, statusfilter.ErrHideStatus
+		}
+
+		apiFilter, err := c.FilterToAPIFilterV2(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, apimodel.FilterResult{
+			Filter:         *apiFilter,
+			KeywordMatches: keywordMatches,
+			StatusMatches:  statusMatches,
+		})
+	}
+
+	return results, nil
+}
+
+// filterAppliesInContext returns whether the given filter
+// should be applied to statuses in the given filter context.
+func filterAppliesInContext(filter *gtsmodel.Filter, filterContext statusfilter.FilterContext) bool {
+	switch filterContext {
+	case statusfilter.FilterContextHome:
+		return util.PtrValueOr(filter.ContextHome, false)
+	case statusfilter.FilterContextNotifications:
+		return util.PtrValueOr(filter.ContextNotifications, false)
+	case statusfilter.FilterContextPublic:
+		return util.PtrValueOr(filter.ContextPublic, false)
+	case statusfilter.FilterContextThread:
+		return util.PtrValueOr(filter.ContextThread, false)
+	case statusfilter.FilterContextAccount:
+		return util.PtrValueOr(filter.ContextAccount, false)
+	}
+	return false
+}
+
+// filterableTextFields returns the plaintext versions of
+// all fields of the given status that keyword filters
+// should be matched against: the content warning, the
+// content, media descriptions, and poll options.
+func filterableTextFields(s *gtsmodel.Status) []string {
+	fieldCount := 2 + len(s.Attachments)
+	if s.Poll != nil {
+		fieldCount += len(s.Poll.Options)
+	}
+	fields := make([]string, 0, fieldCount)
+
+	if s.ContentWarning != "" {
+		fields = append(fields, s.ContentWarning)
+	}
+
+	if s.Content != "" {
+		fields = append(fields, text.SanitizePlaintext(s.Content))
+	}
+
+	for _, attachment := range s.Attachments {
+		if attachment.Description != "" {
+			fields = append(fields, attachment.Description)
+		}
+	}
+
+	if s.Poll != nil {
+		fields = append(fields, s.Poll.Options...)
+	}
+
+	return fields
+}
+
+// filterToAPIFilterContexts returns the api model contexts that the given filter applies in.
+func filterToAPIFilterContexts(filter *gtsmodel.Filter) []apimodel.FilterContext {
+	apiContexts := make([]apimodel.FilterContext, 0, 5)
+	if util.PtrValueOr(filter.ContextHome, false) {
+		apiContexts = append(apiContexts, apimodel.FilterContextHome)
+	}
+	if util.PtrValueOr(filter.ContextNotifications, false) {
+		apiContexts = append(apiContexts, apimodel.FilterContextNotifications)
+	}
+	if util.PtrValueOr(filter.ContextPublic, false) {
+		apiContexts = append(apiContexts, apimodel.FilterContextPublic)
+	}
+	if util.PtrValueOr(filter.ContextThread, false) {
+		apiContexts = append(apiContexts, apimodel.FilterContextThread)
+	}
+	if util.PtrValueOr(filter.ContextAccount, false) {
+		apiContexts = append(apiContexts, apimodel.FilterContextAccount)
+	}
+	return apiContexts
+}
+
+// filterExpiresAtToAPIFilterExpiresAt returns the api model
+// representation of the given filter expiry, if it's set.
+func filterExpiresAtToAPIFilterExpiresAt(expiresAt time.Time) *string {
+	if expiresAt.IsZero() {
+		return nil
+	}
+	return util.Ptr(util.FormatISO8601(expiresAt))
+}
+
 // convertAttachmentsToAPIAttachments will convert a slice of GTS model attachments to frontend API model attachments, falling back to IDs if no GTS models supplied.
 func (c *converter) convertAttachmentsToAPIAttachments(ctx context.Context, attachments []*gtsmodel.MediaAttachment, attachmentIDs []string) ([]apimodel.Attachment, error) {
 	var errs gtserror.MultiError
@@ -1329,3 +1991,101 @@ func (c *converter) convertTagsToAPITags(ctx context.Context, tags []*gtsmodel.T
 
 	return apiTags, errs.Combine()
 }
+
+func (c *converter) WebPushSubscriptionToAPIWebPushSubscription(ctx context.Context, subscription *gtsmodel.WebPushSubscription) (*apimodel.WebPushSubscription, error) {
+	keyPair, err := c.db.GetVAPIDKeyPair(ctx)
+	if err != nil {
+		return nil, gtserror.Newf("error getting vapid key pair: %w", err)
+	}
+
+	return &apimodel.WebPushSubscription{
+		ID:        subscription.ID,
+		Endpoint:  subscription.Endpoint,
+		ServerKey: keyPair.Public,
+		Alerts: apimodel.WebPushSubscriptionAlerts{
+			Follow:        *subscription.AlertFollow,
+			FollowRequest: *subscription.AlertFollowRequest,
+			Favourite:     *subscription.AlertFavourite,
+			Mention:       *subscription.AlertMention,
+			Reblog:        *subscription.AlertReblog,
+			Poll:          *subscription.AlertPoll,
+			Status:        *subscription.AlertStatus,
+			AdminSignup:   *subscription.AlertAdminSignup,
+		},
+		Policy: string(subscription.Policy),
+	}, nil
+}
+
+func (c *converter) ScheduledStatusToAPIScheduledStatus(ctx context.Context, scheduledStatus *gtsmodel.ScheduledStatus) (*apimodel.ScheduledStatus, error) {
+	if err := c.db.PopulateScheduledStatus(ctx, scheduledStatus); err != nil {
+		return nil, gtserror.Newf("error populating scheduled status: %w", err)
+	}
+
+	apiAttachments := make([]apimodel.Attachment, 0, len(scheduledStatus.MediaAttachments))
+	for _, attachment := range scheduledStatus.MediaAttachments {
+		apiAttachment, err := c.AttachmentToAPIAttachment(ctx, attachment)
+		if err != nil {
+			return nil, gtserror.Newf("error converting attachment %s: %w", attachment.ID, err)
+		}
+		apiAttachments = append(apiAttachments, apiAttachment)
+	}
+
+	var apiPoll *apimodel.PollRequest
+	if len(scheduledStatus.PollOptions) != 0 {
+		apiPoll = &apimodel.PollRequest{
+			Options:    scheduledStatus.PollOptions,
+			ExpiresIn:  scheduledStatus.PollExpiresIn,
+			Multiple:   *scheduledStatus.PollMultiple,
+			HideTotals: *scheduledStatus.PollHideTotals,
+		}
+	}
+
+	var visibility string
+	if scheduledStatus.Visibility != "" {
+		visibility = string(c.VisToAPIVis(ctx, scheduledStatus.Visibility))
+	}
+
+	scheduledAt := util.FormatISO8601(scheduledStatus.ScheduledAt)
+
+	return &apimodel.ScheduledStatus{
+		ID:          scheduledStatus.ID,
+		ScheduledAt: scheduledAt,
+		Params: &apimodel.StatusParams{
+			Text:          scheduledStatus.Text,
+			Poll:          apiPoll,
+			InReplyToID:   scheduledStatus.InReplyToID,
+			MediaIDs:      scheduledStatus.MediaIDs,
+			Sensitive:     *scheduledStatus.Sensitive,
+			SpoilerText:   scheduledStatus.SpoilerText,
+			Visibility:    visibility,
+			Language:      scheduledStatus.Language,
+			ScheduledAt:   scheduledAt,
+			ApplicationID: scheduledStatus.ApplicationID,
+		},
+		MediaAttachments: apiAttachments,
+	}, nil
+}
+
+func (c *converter) InviteToAPIInvite(ctx context.Context, invite *gtsmodel.Invite) *apimodel.Invite {
+	apiInvite := &apimodel.Invite{
+		ID:         invite.ID,
+		Code:       invite.Code,
+		URL:        uris.GenerateURIForInvite(invite.Code),
+		CreatedAt:  util.FormatISO8601(invite.CreatedAt),
+		Uses:       invite.Uses,
+		Autofollow: *invite.Autofollow,
+		Expired:    !invite.Valid(time.Now()),
+	}
+
+	if !invite.ExpiresAt.IsZero() {
+		expiresAt := util.FormatISO8601(invite.ExpiresAt)
+		apiInvite.ExpiresAt = &expiresAt
+	}
+
+	if invite.MaxUses > 0 {
+		maxUses := invite.MaxUses
+		apiInvite.MaxUses = &maxUses
+	}
+
+	return apiInvite
+}
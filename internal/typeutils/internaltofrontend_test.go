@@ -23,9 +23,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
 	"github.com/superseriousbusiness/gotosocial/internal/db"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/statusfilter"
 	"github.com/superseriousbusiness/gotosocial/testrig"
 )
 
@@ -327,7 +329,7 @@ func (suite *InternalToFrontendTestSuite) TestLocalInstanceAccountToFrontendBloc
 func (suite *InternalToFrontendTestSuite) TestStatusToFrontend() {
 	testStatus := suite.testStatuses["admin_account_status_1"]
 	requestingAccount := suite.testAccounts["local_account_1"]
-	apiStatus, err := suite.typeconverter.StatusToAPIStatus(context.Background(), testStatus, requestingAccount)
+	apiStatus, err := suite.typeconverter.StatusToAPIStatus(context.Background(), testStatus, requestingAccount, statusfilter.FilterContextNone, nil)
 	suite.NoError(err)
 
 	b, err := json.MarshalIndent(apiStatus, "", "  ")
@@ -433,6 +435,7 @@ func (suite *InternalToFrontendTestSuite) TestStatusToFrontend() {
   ],
   "card": null,
   "poll": null,
+  "edited_at": null,
   "text": "hello world! #welcome ! first post on the instance :rainbow: !"
 }`, string(b))
 }
@@ -442,7 +445,7 @@ func (suite *InternalToFrontendTestSuite) TestStatusToFrontendUnknownLanguage()
 	*testStatus = *suite.testStatuses["admin_account_status_1"]
 	testStatus.Language = ""
 	requestingAccount := suite.testAccounts["local_account_1"]
-	apiStatus, err := suite.typeconverter.StatusToAPIStatus(context.Background(), testStatus, requestingAccount)
+	apiStatus, err := suite.typeconverter.StatusToAPIStatus(context.Background(), testStatus, requestingAccount, statusfilter.FilterContextNone, nil)
 	suite.NoError(err)
 
 	b, err := json.MarshalIndent(apiStatus, "", "  ")
@@ -548,10 +551,91 @@ func (suite *InternalToFrontendTestSuite) TestStatusToFrontendUnknownLanguage()
   ],
   "card": null,
   "poll": null,
+  "edited_at": null,
   "text": "hello world! #welcome ! first post on the instance :rainbow: !"
 }`, string(b))
 }
 
+// Test that a status which is filtered with a warn filter by the requesting user has `filtered` set correctly.
+func (suite *InternalToFrontendTestSuite) TestWarnFilteredStatusToFrontend() {
+	testStatus := suite.testStatuses["admin_account_status_1"]
+	requestingAccount := suite.testAccounts["local_account_1"]
+
+	expectedMatchingFilter := suite.testFilters["local_account_1_filter_1"]
+	expectedMatchingFilterKeyword := suite.testFilterKeywords["local_account_1_filter_1_keyword_1"]
+	expectedMatchingFilterKeyword.Keyword = "welcome"
+	expectedMatchingFilter.Keywords = []*gtsmodel.FilterKeyword{expectedMatchingFilterKeyword}
+	expectedMatchingFilter.Statuses = []*gtsmodel.FilterStatus{}
+
+	requestingAccountFilters := []*gtsmodel.Filter{expectedMatchingFilter}
+
+	apiStatus, err := suite.typeconverter.StatusToAPIStatus(
+		context.Background(),
+		testStatus,
+		requestingAccount,
+		statusfilter.FilterContextHome,
+		requestingAccountFilters,
+	)
+	suite.NoError(err)
+	suite.Len(apiStatus.Filtered, 1)
+
+	filterResult := apiStatus.Filtered[0]
+	suite.Equal(expectedMatchingFilter.ID, filterResult.Filter.ID)
+	suite.Equal(apimodel.FilterActionWarn, filterResult.Filter.FilterAction)
+	suite.Equal([]string{"welcome"}, filterResult.KeywordMatches)
+	suite.Empty(filterResult.StatusMatches)
+}
+
+// Test that a status which is filtered with a hide filter by the requesting user results in the ErrHideStatus error.
+func (suite *InternalToFrontendTestSuite) TestHideFilteredStatusToFrontend() {
+	testStatus := suite.testStatuses["admin_account_status_1"]
+	requestingAccount := suite.testAccounts["local_account_1"]
+
+	expectedMatchingFilter := suite.testFilters["local_account_1_filter_1"]
+	expectedMatchingFilter.Action = gtsmodel.FilterActionHide
+	expectedMatchingFilterKeyword := suite.testFilterKeywords["local_account_1_filter_1_keyword_1"]
+	expectedMatchingFilterKeyword.Keyword = "welcome"
+	expectedMatchingFilter.Keywords = []*gtsmodel.FilterKeyword{expectedMatchingFilterKeyword}
+	expectedMatchingFilter.Statuses = []*gtsmodel.FilterStatus{}
+
+	requestingAccountFilters := []*gtsmodel.Filter{expectedMatchingFilter}
+
+	_, err := suite.typeconverter.StatusToAPIStatus(
+		context.Background(),
+		testStatus,
+		requestingAccount,
+		statusfilter.FilterContextHome,
+		requestingAccountFilters,
+	)
+	suite.ErrorIs(err, statusfilter.ErrHideStatus)
+}
+
+// Test that a filter which doesn't apply in the given context isn't applied.
+func (suite *InternalToFrontendTestSuite) TestFilteredStatusToFrontendWrongContext() {
+	testStatus := suite.testStatuses["admin_account_status_1"]
+	requestingAccount := suite.testAccounts["local_account_1"]
+
+	expectedMatchingFilter := suite.testFilters["local_account_1_filter_1"]
+	expectedMatchingFilter.Action = gtsmodel.FilterActionHide
+	expectedMatchingFilterKeyword := suite.testFilterKeywords["local_account_1_filter_1_keyword_1"]
+	expectedMatchingFilterKeyword.Keyword = "welcome"
+	expectedMatchingFilter.Keywords = []*gtsmodel.FilterKeyword{expectedMatchingFilterKeyword}
+	expectedMatchingFilter.Statuses = []*gtsmodel.FilterStatus{}
+
+	requestingAccountFilters := []*gtsmodel.Filter{expectedMatchingFilter}
+
+	// Test filter doesn't apply to notifications.
+	apiStatus, err := suite.typeconverter.StatusToAPIStatus(
+		context.Background(),
+		testStatus,
+		requestingAccount,
+		statusfilter.FilterContextNotifications,
+		requestingAccountFilters,
+	)
+	suite.NoError(err)
+	suite.Empty(apiStatus.Filtered)
+}
+
 func (suite *InternalToFrontendTestSuite) TestVideoAttachmentToFrontend() {
 	testAttachment := suite.testAttachments["local_account_1_status_4_attachment_2"]
 	apiAttachment, err := suite.typeconverter.AttachmentToAPIAttachment(context.Background(), testAttachment)
@@ -631,7 +715,15 @@ func (suite *InternalToFrontendTestSuite) TestInstanceV1ToFrontend() {
         "image/gif",
         "image/png",
         "image/webp",
-        "video/mp4"
+        "image/heif",
+        "image/avif",
+        "video/mp4",
+        "video/quicktime",
+        "video/webm",
+        "audio/mpeg",
+        "audio/ogg",
+        "audio/x-flac",
+        "audio/x-wav"
       ],
       "image_size_limit": 10485760,
       "image_matrix_limit": 16777216,
@@ -689,7 +781,17 @@ func (suite *InternalToFrontendTestSuite) TestInstanceV1ToFrontend() {
       "name": "admin"
     }
   },
-  "max_toot_chars": 5000
+  "max_toot_chars": 5000,
+  "rules": [
+    {
+      "id": "01HA0VEZ3P6Z8T4E1XW8B3YJ5K",
+      "text": "Be nice to each other."
+    },
+    {
+      "id": "01HA0VF8N2JQ6B5V4D7ZC9M1RT",
+      "text": "No spam or advertising."
+    }
+  ]
 }`, string(b))
 }
 
@@ -749,7 +851,15 @@ func (suite *InternalToFrontendTestSuite) TestInstanceV2ToFrontend() {
         "image/gif",
         "image/png",
         "image/webp",
-        "video/mp4"
+        "image/heif",
+        "image/avif",
+        "video/mp4",
+        "video/quicktime",
+        "video/webm",
+        "audio/mpeg",
+        "audio/ogg",
+        "audio/x-flac",
+        "audio/x-wav"
       ],
       "image_size_limit": 10485760,
       "image_matrix_limit": 16777216,
@@ -768,6 +878,9 @@ func (suite *InternalToFrontendTestSuite) TestInstanceV2ToFrontend() {
     },
     "emojis": {
       "emoji_size_limit": 51200
+    },
+    "vapid": {
+      "public_key": ""
     }
   },
   "registrations": {
@@ -804,7 +917,16 @@ func (suite *InternalToFrontendTestSuite) TestInstanceV2ToFrontend() {
       }
     }
   },
-  "rules": []
+  "rules": [
+    {
+      "id": "01HA0VEZ3P6Z8T4E1XW8B3YJ5K",
+      "text": "Be nice to each other."
+    },
+    {
+      "id": "01HA0VF8N2JQ6B5V4D7ZC9M1RT",
+      "text": "No spam or advertising."
+    }
+  ]
 }`, string(b))
 }
 
@@ -1177,7 +1299,7 @@ func (suite *InternalToFrontendTestSuite) TestAdminReportToFrontend1() {
     "created_by_application_id": "01F8MGXQRHYF5QPMTMXP78QC2F"
   },
   "statuses": [],
-  "rule_ids": [],
+  "rules": [],
   "action_taken_comment": "user was warned not to be a turtle anymore"
 }`, string(b))
 }
@@ -1377,10 +1499,11 @@ func (suite *InternalToFrontendTestSuite) TestAdminReportToFrontend2() {
       "tags": [],
       "emojis": [],
       "card": null,
-      "poll": null
+      "poll": null,
+      "edited_at": null
     }
   ],
-  "rule_ids": [],
+  "rules": [],
   "action_taken_comment": null
 }`, string(b))
 }
@@ -1603,11 +1726,32 @@ func (suite *InternalToFrontendTestSuite) TestAdminReportToFrontendSuspendedLoca
     "created_by_application_id": "01F8MGXQRHYF5QPMTMXP78QC2F"
   },
   "statuses": [],
-  "rule_ids": [],
+  "rules": [],
   "action_taken_comment": "user was warned not to be a turtle anymore"
 }`, string(b))
 }
 
+func (suite *InternalToFrontendTestSuite) TestInviteToFrontend() {
+	invite := testrig.NewTestInvites()["local_account_1_invite_1"]
+
+	apiInvite := suite.typeconverter.InviteToAPIInvite(context.Background(), invite)
+
+	b, err := json.MarshalIndent(apiInvite, "", "  ")
+	suite.NoError(err)
+
+	suite.Equal(`{
+  "id": "01H9PQAE8A2DJ6Q7GZ5NAQ1F7E",
+  "code": "Yx1bQ9cZ",
+  "url": "http://localhost:8080/auth/sign_up?invite=Yx1bQ9cZ",
+  "created_at": "2023-09-06T09:00:00.000Z",
+  "expires_at": "2023-09-07T09:00:00.000Z",
+  "max_uses": null,
+  "uses": 0,
+  "autofollow": false,
+  "expired": true
+}`, string(b))
+}
+
 func TestInternalToFrontendTestSuite(t *testing.T) {
 	suite.Run(t, new(InternalToFrontendTestSuite))
 }
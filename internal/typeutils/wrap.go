@@ -19,6 +19,8 @@ package typeutils
 
 import (
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/superseriousbusiness/activity/pub"
 	"github.com/superseriousbusiness/activity/streams"
@@ -84,21 +86,21 @@ func (c *converter) WrapPersonInUpdate(person vocab.ActivityStreamsPerson, origi
 	return update, nil
 }
 
-func (c *converter) WrapNoteInCreate(note vocab.ActivityStreamsNote, objectIRIOnly bool) (vocab.ActivityStreamsCreate, error) {
+func (c *converter) WrapStatusableInCreate(status ap.Statusable, objectIRIOnly bool) (vocab.ActivityStreamsCreate, error) {
 	create := streams.NewActivityStreamsCreate()
 
 	// Object property
 	objectProp := streams.NewActivityStreamsObjectProperty()
 	if objectIRIOnly {
-		objectProp.AppendIRI(note.GetJSONLDId().GetIRI())
+		objectProp.AppendIRI(status.GetJSONLDId().GetIRI())
 	} else {
-		objectProp.AppendActivityStreamsNote(note)
+		objectProp.AppendType(status)
 	}
 	create.SetActivityStreamsObject(objectProp)
 
 	// ID property
 	idProp := streams.NewJSONLDIdProperty()
-	createID := note.GetJSONLDId().GetIRI().String() + "/activity"
+	createID := status.GetJSONLDId().GetIRI().String() + "/activity"
 	createIDIRI, err := url.Parse(createID)
 	if err != nil {
 		return nil, err
@@ -108,7 +110,7 @@ func (c *converter) WrapNoteInCreate(note vocab.ActivityStreamsNote, objectIRIOn
 
 	// Actor Property
 	actorProp := streams.NewActivityStreamsActorProperty()
-	actorIRI, err := ap.ExtractAttributedToURI(note)
+	actorIRI, err := ap.ExtractAttributedToURI(status)
 	if err != nil {
 		return nil, gtserror.Newf("couldn't extract AttributedTo: %w", err)
 	}
@@ -117,7 +119,7 @@ func (c *converter) WrapNoteInCreate(note vocab.ActivityStreamsNote, objectIRIOn
 
 	// Published Property
 	publishedProp := streams.NewActivityStreamsPublishedProperty()
-	published, err := ap.ExtractPublished(note)
+	published, err := ap.ExtractPublished(status)
 	if err != nil {
 		return nil, gtserror.Newf("couldn't extract Published: %w", err)
 	}
@@ -126,7 +128,7 @@ func (c *converter) WrapNoteInCreate(note vocab.ActivityStreamsNote, objectIRIOn
 
 	// To Property
 	toProp := streams.NewActivityStreamsToProperty()
-	if toURIs := ap.ExtractToURIs(note); len(toURIs) != 0 {
+	if toURIs := ap.ExtractToURIs(status); len(toURIs) != 0 {
 		for _, toURI := range toURIs {
 			toProp.AppendIRI(toURI)
 		}
@@ -135,7 +137,7 @@ func (c *converter) WrapNoteInCreate(note vocab.ActivityStreamsNote, objectIRIOn
 
 	// Cc Property
 	ccProp := streams.NewActivityStreamsCcProperty()
-	if ccURIs := ap.ExtractCcURIs(note); len(ccURIs) != 0 {
+	if ccURIs := ap.ExtractCcURIs(status); len(ccURIs) != 0 {
 		for _, ccURI := range ccURIs {
 			ccProp.AppendIRI(ccURI)
 		}
@@ -144,3 +146,61 @@ func (c *converter) WrapNoteInCreate(note vocab.ActivityStreamsNote, objectIRIOn
 
 	return create, nil
 }
+
+func (c *converter) WrapStatusableInUpdate(status ap.Statusable, objectIRIOnly bool) (vocab.ActivityStreamsUpdate, error) {
+	update := streams.NewActivityStreamsUpdate()
+
+	// Object property
+	objectProp := streams.NewActivityStreamsObjectProperty()
+	if objectIRIOnly {
+		objectProp.AppendIRI(status.GetJSONLDId().GetIRI())
+	} else {
+		objectProp.AppendType(status)
+	}
+	update.SetActivityStreamsObject(objectProp)
+
+	// ID property
+	idProp := streams.NewJSONLDIdProperty()
+	now := time.Now()
+	updateID := status.GetJSONLDId().GetIRI().String() + "#updates/" + strconv.FormatInt(now.Unix(), 10)
+	updateIDIRI, err := url.Parse(updateID)
+	if err != nil {
+		return nil, err
+	}
+	idProp.SetIRI(updateIDIRI)
+	update.SetJSONLDId(idProp)
+
+	// Actor Property
+	actorProp := streams.NewActivityStreamsActorProperty()
+	actorIRI, err := ap.ExtractAttributedToURI(status)
+	if err != nil {
+		return nil, gtserror.Newf("couldn't extract AttributedTo: %w", err)
+	}
+	actorProp.AppendIRI(actorIRI)
+	update.SetActivityStreamsActor(actorProp)
+
+	// Published Property
+	publishedProp := streams.NewActivityStreamsPublishedProperty()
+	publishedProp.Set(now)
+	update.SetActivityStreamsPublished(publishedProp)
+
+	// To Property
+	toProp := streams.NewActivityStreamsToProperty()
+	if toURIs := ap.ExtractToURIs(status); len(toURIs) != 0 {
+		for _, toURI := range toURIs {
+			toProp.AppendIRI(toURI)
+		}
+		update.SetActivityStreamsTo(toProp)
+	}
+
+	// Cc Property
+	ccProp := streams.NewActivityStreamsCcProperty()
+	if ccURIs := ap.ExtractCcURIs(status); len(ccURIs) != 0 {
+		for _, ccURI := range ccURIs {
+			ccProp.AppendIRI(ccURI)
+		}
+		update.SetActivityStreamsCc(ccProp)
+	}
+
+	return update, nil
+}
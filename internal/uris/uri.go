@@ -27,24 +27,26 @@ import (
 )
 
 const (
-	UsersPath        = "users"         // UsersPath is for serving users info
-	StatusesPath     = "statuses"      // StatusesPath is for serving statuses
-	InboxPath        = "inbox"         // InboxPath represents the activitypub inbox location
-	OutboxPath       = "outbox"        // OutboxPath represents the activitypub outbox location
-	FollowersPath    = "followers"     // FollowersPath represents the activitypub followers location
-	FollowingPath    = "following"     // FollowingPath represents the activitypub following location
-	LikedPath        = "liked"         // LikedPath represents the activitypub liked location
-	CollectionsPath  = "collections"   // CollectionsPath represents the activitypub collections location
-	FeaturedPath     = "featured"      // FeaturedPath represents the activitypub featured location
-	PublicKeyPath    = "main-key"      // PublicKeyPath is for serving an account's public key
-	FollowPath       = "follow"        // FollowPath used to generate the URI for an individual follow or follow request
-	UpdatePath       = "updates"       // UpdatePath is used to generate the URI for an account update
-	BlocksPath       = "blocks"        // BlocksPath is used to generate the URI for a block
-	ReportsPath      = "reports"       // ReportsPath is used to generate the URI for a report/flag
-	ConfirmEmailPath = "confirm_email" // ConfirmEmailPath is used to generate the URI for an email confirmation link
-	FileserverPath   = "fileserver"    // FileserverPath is a path component for serving attachments + media
-	EmojiPath        = "emoji"         // EmojiPath represents the activitypub emoji location
-	TagsPath         = "tags"          // TagsPath represents the activitypub tags location
+	UsersPath         = "users"          // UsersPath is for serving users info
+	StatusesPath      = "statuses"       // StatusesPath is for serving statuses
+	InboxPath         = "inbox"          // InboxPath represents the activitypub inbox location
+	OutboxPath        = "outbox"         // OutboxPath represents the activitypub outbox location
+	FollowersPath     = "followers"      // FollowersPath represents the activitypub followers location
+	FollowingPath     = "following"      // FollowingPath represents the activitypub following location
+	LikedPath         = "liked"          // LikedPath represents the activitypub liked location
+	CollectionsPath   = "collections"    // CollectionsPath represents the activitypub collections location
+	FeaturedPath      = "featured"       // FeaturedPath represents the activitypub featured location
+	PublicKeyPath     = "main-key"       // PublicKeyPath is for serving an account's public key
+	FollowPath        = "follow"         // FollowPath used to generate the URI for an individual follow or follow request
+	UpdatePath        = "updates"        // UpdatePath is used to generate the URI for an account update
+	BlocksPath        = "blocks"         // BlocksPath is used to generate the URI for a block
+	ReportsPath       = "reports"        // ReportsPath is used to generate the URI for a report/flag
+	ConfirmEmailPath  = "confirm_email"  // ConfirmEmailPath is used to generate the URI for an email confirmation link
+	ResetPasswordPath = "reset_password" // ResetPasswordPath is used to generate the URI for a password reset link
+	SignUpPath        = "sign_up"        // SignUpPath is used to generate the URI for an invite link
+	FileserverPath    = "fileserver"     // FileserverPath is a path component for serving attachments + media
+	EmojiPath         = "emoji"          // EmojiPath represents the activitypub emoji location
+	TagsPath          = "tags"           // TagsPath represents the activitypub tags location
 )
 
 // UserURIs contains a bunch of UserURIs and URLs for a user, host, account, etc.
@@ -127,6 +129,22 @@ func GenerateURIForEmailConfirm(token string) string {
 	return fmt.Sprintf("%s://%s/%s?token=%s", protocol, host, ConfirmEmailPath, token)
 }
 
+// GenerateURIForPasswordReset returns a link for resetting a password -- something like:
+// https://example.org/auth/reset_password?token=490e337c-0162-454f-ac48-4b22bb92a205
+func GenerateURIForPasswordReset(token string) string {
+	protocol := config.GetProtocol()
+	host := config.GetHost()
+	return fmt.Sprintf("%s://%s/auth/%s?token=%s", protocol, host, ResetPasswordPath, token)
+}
+
+// GenerateURIForInvite returns a link for signing up with an invite -- something like:
+// https://example.org/auth/sign_up?invite=wkTbG4YaQ9cZx1bN
+func GenerateURIForInvite(code string) string {
+	protocol := config.GetProtocol()
+	host := config.GetHost()
+	return fmt.Sprintf("%s://%s/auth/%s?invite=%s", protocol, host, SignUpPath, code)
+}
+
 // GenerateURIsForAccount throws together a bunch of URIs for the given username, with the given protocol and host.
 func GenerateURIsForAccount(username string) *UserURIs {
 	protocol := config.GetProtocol()
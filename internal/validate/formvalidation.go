@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"net/mail"
+	"net/url"
 
 	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
 	"github.com/superseriousbusiness/gotosocial/internal/config"
@@ -44,6 +45,10 @@ const (
 	maximumProfileFieldLength     = 255
 	maximumProfileFields          = 6
 	maximumListTitleLength        = 200
+	maximumFilterKeywordLength    = 40
+	maximumFilterTitleLength      = 200
+	maximumAlsoKnownAsURIs        = 10
+	maximumInstanceRuleLength     = 1000
 )
 
 // Password returns a helpful error if the given password
@@ -133,6 +138,42 @@ func SignUpReason(reason string, reasonRequired bool) error {
 	return nil
 }
 
+// CreateAccount checks through all the necessary prerequisites for creating a new account,
+// according to the provided account create request. If the account isn't eligible, an error will be returned.
+func CreateAccount(form *apimodel.AccountCreateRequest) error {
+	if form == nil {
+		return errors.New("form was nil")
+	}
+
+	// Closed registration can still be
+	// bypassed by signing up with an invite.
+	if !config.GetAccountsRegistrationOpen() && form.InviteCode == "" {
+		return errors.New("registration is not open for this server")
+	}
+
+	if err := Username(form.Username); err != nil {
+		return err
+	}
+
+	if err := Email(form.Email); err != nil {
+		return err
+	}
+
+	if err := Password(form.Password); err != nil {
+		return err
+	}
+
+	if !form.Agreement {
+		return errors.New("agreement to terms and conditions not given")
+	}
+
+	if err := Language(form.Locale); err != nil {
+		return err
+	}
+
+	return SignUpReason(form.Reason, config.GetAccountsReasonRequired())
+}
+
 // DisplayName checks that a requested display name is valid
 func DisplayName(displayName string) error {
 	// TODO: add some validation logic here -- length, characters, etc
@@ -265,6 +306,24 @@ func ProfileFields(fields []*gtsmodel.Field) error {
 	return nil
 }
 
+// AlsoKnownAsURIs validates the alias URIs of an account,
+// checking each is an absolute http(s) URI, and that there
+// are no more than maximumAlsoKnownAsURIs of them.
+func AlsoKnownAsURIs(uris []string) error {
+	if len(uris) > maximumAlsoKnownAsURIs {
+		return fmt.Errorf("cannot have more than %d also_known_as_uris", maximumAlsoKnownAsURIs)
+	}
+
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		if err != nil || !u.IsAbs() || (u.Scheme != "https" && u.Scheme != "http") {
+			return fmt.Errorf("also_known_as_uris entry %s was not a valid http(s) uri", uri)
+		}
+	}
+
+	return nil
+}
+
 // ListTitle validates the title of a new or updated List.
 func ListTitle(title string) error {
 	if title == "" {
@@ -278,6 +337,19 @@ func ListTitle(title string) error {
 	return nil
 }
 
+// InstanceRuleText validates the text of a new or updated instance rule.
+func InstanceRuleText(text string) error {
+	if text == "" {
+		return fmt.Errorf("rule text must be provided, and must be no more than %d chars", maximumInstanceRuleLength)
+	}
+
+	if length := len([]rune(text)); length > maximumInstanceRuleLength {
+		return fmt.Errorf("rule text length must be no more than %d chars, provided text was %d chars", maximumInstanceRuleLength, length)
+	}
+
+	return nil
+}
+
 // ListRepliesPolicy validates the replies_policy of a new or updated list.
 func ListRepliesPolicy(repliesPolicy gtsmodel.RepliesPolicy) error {
 	switch repliesPolicy {
@@ -301,3 +373,76 @@ func MarkerName(name string) error {
 	}
 	return fmt.Errorf("marker timeline name '%s' was not recognized, valid options are '%s', '%s'", name, apimodel.MarkerNameHome, apimodel.MarkerNameNotifications)
 }
+
+// FilterKeyword validates the keyword of a new or updated filter keyword or v1 filter phrase.
+func FilterKeyword(keyword string) error {
+	if keyword == "" {
+		return fmt.Errorf("filter keyword must be provided, and must be no more than %d chars", maximumFilterKeywordLength)
+	}
+
+	if length := len([]rune(keyword)); length > maximumFilterKeywordLength {
+		return fmt.Errorf("filter keyword length must be no more than %d chars, provided keyword was %d chars", maximumFilterKeywordLength, length)
+	}
+
+	return nil
+}
+
+// FilterTitle validates the title of a new or updated v2 filter.
+func FilterTitle(title string) error {
+	if title == "" {
+		return fmt.Errorf("filter title must be provided, and must be no more than %d chars", maximumFilterTitleLength)
+	}
+
+	if length := len([]rune(title)); length > maximumFilterTitleLength {
+		return fmt.Errorf("filter title length must be no more than %d chars, provided title was %d chars", maximumFilterTitleLength, length)
+	}
+
+	return nil
+}
+
+// FilterContexts validates the context of a new or updated filter.
+func FilterContexts(contexts []apimodel.FilterContext) error {
+	if len(contexts) == 0 {
+		return fmt.Errorf("at least one filter context is required")
+	}
+
+	for _, context := range contexts {
+		switch context {
+		case apimodel.FilterContextHome,
+			apimodel.FilterContextNotifications,
+			apimodel.FilterContextPublic,
+			apimodel.FilterContextThread,
+			apimodel.FilterContextAccount:
+			continue
+		default:
+			return fmt.Errorf(
+				"filter context '%s' was not recognized, valid options are '%s', '%s', '%s', '%s', '%s'",
+				context,
+				apimodel.FilterContextHome,
+				apimodel.FilterContextNotifications,
+				apimodel.FilterContextPublic,
+				apimodel.FilterContextThread,
+				apimodel.FilterContextAccount,
+			)
+		}
+	}
+
+	return nil
+}
+
+// FilterAction validates the filter_action of a new or updated v2 filter.
+func FilterAction(action apimodel.FilterAction) error {
+	switch action {
+	case apimodel.FilterActionWarn, apimodel.FilterActionHide:
+		return nil
+	}
+	return fmt.Errorf("filter action '%s' was not recognized, valid options are '%s', '%s'", action, apimodel.FilterActionWarn, apimodel.FilterActionHide)
+}
+
+// FilterExpiresIn validates the expires_in of a new or updated filter.
+func FilterExpiresIn(expiresIn int) error {
+	if expiresIn < 0 {
+		return fmt.Errorf("filter expires_in must not be negative, provided value was %d", expiresIn)
+	}
+	return nil
+}
@@ -69,6 +69,12 @@ func (suite *FilterStandardTestSuite) SetupTest() {
 	suite.db = testrig.NewTestDB(&suite.state)
 	suite.filter = visibility.NewFilter(&suite.state)
 
+	testrig.StartTimelines(
+		&suite.state,
+		suite.filter,
+		testrig.NewTestTypeConverter(suite.db),
+	)
+
 	testrig.StandardDBSetup(suite.db, nil)
 }
 
@@ -91,6 +91,42 @@ func (f *Filter) isStatusHomeTimelineable(ctx context.Context, owner *gtsmodel.A
 		return true, nil
 	}
 
+	// Check whether owner has muted the status author.
+	muted, err := f.state.DB.IsMuted(ctx, owner.ID, status.AccountID)
+	if err != nil {
+		return false, fmt.Errorf("isStatusHomeTimelineable: error checking mute %s->%s: %w", owner.ID, status.AccountID, err)
+	}
+
+	if !muted && status.BoostOfAccountID != "" {
+		// Also check whether owner has muted the boosted status author.
+		muted, err = f.state.DB.IsMuted(ctx, owner.ID, status.BoostOfAccountID)
+		if err != nil {
+			return false, fmt.Errorf("isStatusHomeTimelineable: error checking mute %s->%s: %w", owner.ID, status.BoostOfAccountID, err)
+		}
+	}
+
+	if muted {
+		log.Trace(ctx, "ignoring status from muted author")
+		return false, nil
+	}
+
+	// Check whether owner has muted the thread this status
+	// is in (or for a boost, the thread of the boosted status).
+	threadStatus := status
+	if status.BoostOf != nil {
+		threadStatus = status.BoostOf
+	}
+
+	muted, err = f.state.DB.IsStatusMutedBy(ctx, threadStatus, owner.ID)
+	if err != nil {
+		return false, fmt.Errorf("isStatusHomeTimelineable: error checking thread mute for status %s: %w", threadStatus.ID, err)
+	}
+
+	if muted {
+		log.Trace(ctx, "ignoring status in muted thread")
+		return false, nil
+	}
+
 	if status.MentionsAccount(owner.ID) {
 		// Can always see when you are mentioned.
 		return true, nil
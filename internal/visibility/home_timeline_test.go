@@ -54,6 +54,48 @@ func (suite *StatusStatusHomeTimelineableTestSuite) TestFollowingStatusHomeTimel
 	suite.True(timelineable)
 }
 
+func (suite *StatusStatusHomeTimelineableTestSuite) TestMutedStatusNotHomeTimelineable() {
+	testStatus := suite.testStatuses["local_account_2_status_1"]
+	testAccount := suite.testAccounts["local_account_1"]
+	ctx := context.Background()
+
+	// Mute the status author.
+	if err := suite.db.PutMute(ctx, &gtsmodel.UserMute{
+		ID:              "01H7DM1T6K5TR1P3V0C4GKJQ2D",
+		AccountID:       testAccount.ID,
+		TargetAccountID: testStatus.AccountID,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	timelineable, err := suite.filter.StatusHomeTimelineable(ctx, testAccount, testStatus)
+	suite.NoError(err)
+
+	suite.False(timelineable)
+}
+
+func (suite *StatusStatusHomeTimelineableTestSuite) TestMutedThreadNotHomeTimelineable() {
+	testStatus := suite.testStatuses["local_account_2_status_5"]
+	testAccount := suite.testAccounts["local_account_1"]
+	ctx := context.Background()
+
+	// Mute the thread this status replies to.
+	threadRoot := suite.testStatuses["local_account_1_status_1"]
+	if err := suite.db.PutStatusMute(ctx, &gtsmodel.StatusMute{
+		ID:              "01H7DPZ9Y8P2T4QW5N1XJ3K6RB",
+		AccountID:       testAccount.ID,
+		TargetAccountID: threadRoot.AccountID,
+		StatusID:        threadRoot.ID,
+	}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	timelineable, err := suite.filter.StatusHomeTimelineable(ctx, testAccount, testStatus)
+	suite.NoError(err)
+
+	suite.False(timelineable)
+}
+
 func (suite *StatusStatusHomeTimelineableTestSuite) TestNotFollowingStatusHomeTimelineable() {
 	testStatus := suite.testStatuses["remote_account_1_status_1"]
 	testAccount := suite.testAccounts["local_account_1"]
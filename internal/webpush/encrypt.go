@@ -0,0 +1,193 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// recordSize is the record size used for encrypted payloads;
+	// payloads are always encrypted as a single record, so this
+	// is also the upper limit on the size of the encrypted payload.
+	recordSize = 4096
+
+	// headerSize is the size of the aes128gcm content coding header:
+	// salt (16) + record size (4) + key ID length (1) + key ID (65).
+	headerSize = 16 + 4 + 1 + 65
+
+	// MaxPayloadSize is the maximum size of an unencrypted payload:
+	// the most that fits into one record, minus the padding delimiter
+	// and the AES-GCM tag, minus the header which push services count
+	// towards their 4096 byte limit.
+	MaxPayloadSize = recordSize - headerSize - 1 - 16
+)
+
+// ValidateKeys checks that the given base64url-encoded P-256 public
+// key and auth secret of a user agent can be used to encrypt payloads.
+func ValidateKeys(p256dh string, auth string) error {
+	uaPublicBytes, err := decodeBase64(p256dh)
+	if err != nil {
+		return fmt.Errorf("error decoding p256dh: %w", err)
+	}
+
+	if _, err := ecdh.P256().NewPublicKey(uaPublicBytes); err != nil {
+		return fmt.Errorf("error parsing p256dh: %w", err)
+	}
+
+	authSecret, err := decodeBase64(auth)
+	if err != nil {
+		return fmt.Errorf("error decoding auth: %w", err)
+	}
+
+	if len(authSecret) != 16 {
+		return errors.New("auth secret must be 16 bytes")
+	}
+
+	return nil
+}
+
+// encrypt encrypts the given payload for the user agent with the given
+// base64url-encoded P-256 public key and auth secret, as described in
+// RFC 8291, using the aes128gcm content coding described in RFC 8188.
+//
+// The returned bytes are ready to be used as the body of a push message.
+func encrypt(payload []byte, p256dh string, auth string) ([]byte, error) {
+	if len(payload) > MaxPayloadSize {
+		return nil, fmt.Errorf("payload size %d exceeds maximum of %d", len(payload), MaxPayloadSize)
+	}
+
+	uaPublicBytes, err := decodeBase64(p256dh)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding p256dh: %w", err)
+	}
+
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing p256dh: %w", err)
+	}
+
+	authSecret, err := decodeBase64(auth)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding auth: %w", err)
+	}
+
+	if len(authSecret) != 16 {
+		return nil, errors.New("auth secret must be 16 bytes")
+	}
+
+	// Generate a new, single-use key
+	// pair for this message, and use
+	// it to derive a secret shared
+	// with the user agent.
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("error generating key: %w", err)
+	}
+	asPublicBytes := asPrivate.PublicKey().Bytes()
+
+	ecdhSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving shared secret: %w", err)
+	}
+
+	// Combine the shared secret with the auth
+	// secret to get the input keying material.
+	keyInfo := make([]byte, 0, 14+65+65)
+	keyInfo = append(keyInfo, "WebPush: info\x00"...)
+	keyInfo = append(keyInfo, uaPublicBytes...)
+	keyInfo = append(keyInfo, asPublicBytes...)
+	ikm := hkdf(authSecret, ecdhSecret, keyInfo, 32)
+
+	// Derive content encryption key
+	// and nonce using a random salt.
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("error generating salt: %w", err)
+	}
+	cek := hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating gcm: %w", err)
+	}
+
+	// Write the header, then append the
+	// payload, encrypted as the last (and
+	// only) record, so delimited by 0x02.
+	body := make([]byte, 0, headerSize+len(payload)+1+gcm.Overhead())
+	body = append(body, salt...)
+	body = binary.BigEndian.AppendUint32(body, recordSize)
+	body = append(body, byte(len(asPublicBytes)))
+	body = append(body, asPublicBytes...)
+
+	plaintext := make([]byte, 0, len(payload)+1)
+	plaintext = append(plaintext, payload...)
+	plaintext = append(plaintext, 0x02)
+
+	return gcm.Seal(body, nonce, plaintext, nil), nil
+}
+
+// hkdf derives a key of the given length (<= 32) from the given
+// salt, input keying material and info, as described in RFC 5869.
+func hkdf(salt []byte, ikm []byte, info []byte, length int) []byte {
+	// Extract.
+	mac := hmac.New(sha256.New, salt)
+	mac.Write(ikm)
+	prk := mac.Sum(nil)
+
+	// Expand; we never need more than
+	// one block, so a single round will do.
+	mac = hmac.New(sha256.New, prk)
+	mac.Write(info)
+	mac.Write([]byte{0x01})
+	return mac.Sum(nil)[:length]
+}
+
+// decodeBase64 decodes the given base64url string, which
+// clients may or may not have padded, or may have encoded
+// using the standard rather than the url-safe alphabet.
+func decodeBase64(s string) ([]byte, error) {
+	for _, enc := range []*base64.Encoding{
+		base64.RawURLEncoding,
+		base64.URLEncoding,
+		base64.RawStdEncoding,
+		base64.StdEncoding,
+	} {
+		if b, err := enc.DecodeString(s); err == nil {
+			return b, nil
+		}
+	}
+
+	return nil, errors.New("not valid base64")
+}
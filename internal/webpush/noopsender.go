@@ -0,0 +1,47 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package webpush
+
+import (
+	"context"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+)
+
+// NewNoopSender returns a Sender which manages the instance's
+// VAPID keys as usual, but doesn't actually deliver anything.
+func NewNoopSender(state *state.State) Sender {
+	return &noopSender{
+		keys: &vapidKeys{state: state},
+	}
+}
+
+type noopSender struct {
+	keys *vapidKeys
+}
+
+func (n *noopSender) Send(context.Context, *gtsmodel.Notification, *apimodel.Notification) error {
+	return nil
+}
+
+func (n *noopSender) VAPIDPublicKey(ctx context.Context) (string, error) {
+	public, _, err := n.keys.get(ctx)
+	return public, err
+}
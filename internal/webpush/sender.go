@@ -0,0 +1,402 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtscontext"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/log"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/superseriousbusiness/gotosocial/internal/text"
+)
+
+const (
+	// maxAttempts is the number of times delivery
+	// of one message is attempted before giving up.
+	maxAttempts = 3
+
+	// maxFailures is the number of consecutive failed
+	// deliveries to a subscription after which the
+	// subscription is considered dead and removed.
+	maxFailures = 10
+
+	// ttl is how long (in seconds) push services
+	// should keep a message around for delivery to
+	// a user agent that's not currently reachable.
+	ttl = "172800"
+
+	// maxBodyLength is the maximum length (in runes)
+	// of the body of the notification shown to users.
+	maxBodyLength = 140
+)
+
+// errGone is returned when the push service indicates
+// that a subscription has expired or been unsubscribed.
+var errGone = errors.New("subscription gone")
+
+// HTTPClient is the subset of http client functionality
+// that's needed to deliver messages to push services.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Sender delivers notifications to Web Push subscriptions.
+type Sender interface {
+	// Send delivers the given notification to each of the target account's
+	// Web Push subscriptions that wants it, retrying failed deliveries, and
+	// removing subscriptions whose push service endpoints have gone away.
+	Send(ctx context.Context, notification *gtsmodel.Notification, apiNotification *apimodel.Notification) error
+
+	// VAPIDPublicKey returns the instance's base64url-encoded VAPID public
+	// key, which clients need to create subscriptions with push services.
+	VAPIDPublicKey(ctx context.Context) (string, error)
+}
+
+// NewSender returns a new Sender which delivers
+// notifications to push services using the given client.
+func NewSender(state *state.State, client HTTPClient) Sender {
+	return &sender{
+		state:   state,
+		client:  client,
+		keys:    &vapidKeys{state: state},
+		backoff: 2 * time.Second,
+	}
+}
+
+type sender struct {
+	state   *state.State
+	client  HTTPClient
+	keys    *vapidKeys
+	backoff time.Duration // Backoff before the first retry, doubled for each subsequent retry.
+}
+
+// payload is the JSON payload of a push message,
+// in the format expected by Mastodon API clients.
+type payload struct {
+	AccessToken      string `json:"access_token"`
+	PreferredLocale  string `json:"preferred_locale,omitempty"`
+	NotificationID   string `json:"notification_id"`
+	NotificationType string `json:"notification_type"`
+	Icon             string `json:"icon,omitempty"`
+	Title            string `json:"title"`
+	Body             string `json:"body"`
+}
+
+func (s *sender) VAPIDPublicKey(ctx context.Context) (string, error) {
+	public, _, err := s.keys.get(ctx)
+	return public, err
+}
+
+func (s *sender) Send(ctx context.Context, notification *gtsmodel.Notification, apiNotification *apimodel.Notification) error {
+	subscriptions, err := s.state.DB.GetWebPushSubscriptionsByAccountID(ctx, notification.TargetAccountID)
+	if err != nil {
+		return gtserror.Newf("db error getting web push subscriptions: %w", err)
+	}
+
+	errs := gtserror.NewMultiError(len(subscriptions))
+	for _, subscription := range subscriptions {
+		if err := s.sendTo(ctx, subscription, notification, apiNotification); err != nil {
+			errs.Appendf("error sending to subscription %s: %w", subscription.ID, err)
+		}
+	}
+
+	return errs.Combine()
+}
+
+// sendTo delivers the given notification to the given subscription,
+// if the subscription's alert settings and policy allow it.
+func (s *sender) sendTo(
+	ctx context.Context,
+	subscription *gtsmodel.WebPushSubscription,
+	notification *gtsmodel.Notification,
+	apiNotification *apimodel.Notification,
+) error {
+	if !subscription.Alerts(notification.NotificationType) {
+		// Subscription doesn't
+		// want this type.
+		return nil
+	}
+
+	allowed, err := s.policyAllows(ctx, subscription, notification.OriginAccountID)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		// Not from
+		// wanted account.
+		return nil
+	}
+
+	token := &gtsmodel.Token{}
+	if err := s.state.DB.GetByID(ctx, subscription.TokenID, token); err != nil {
+		if !errors.Is(err, db.ErrNoEntries) {
+			return gtserror.Newf("db error getting token: %w", err)
+		}
+
+		// Token has been revoked,
+		// so remove the subscription.
+		return s.remove(ctx, subscription)
+	}
+
+	var locale string
+	user, err := s.state.DB.GetUserByAccountID(gtscontext.SetBarebones(ctx), subscription.AccountID)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return gtserror.Newf("db error getting user: %w", err)
+	} else if user != nil {
+		locale = user.Locale
+	}
+
+	p := newPayload(apiNotification)
+	p.AccessToken = token.Access
+	p.PreferredLocale = locale
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		return gtserror.Newf("error marshaling payload: %w", err)
+	}
+
+	body, err := encrypt(b, subscription.P256dh, subscription.Auth)
+	if err != nil {
+		return gtserror.Newf("error encrypting payload: %w", err)
+	}
+
+	return s.deliver(ctx, subscription, body)
+}
+
+// policyAllows returns whether the subscription's policy
+// allows notifications originating from the given account.
+func (s *sender) policyAllows(ctx context.Context, subscription *gtsmodel.WebPushSubscription, originAccountID string) (bool, error) {
+	switch subscription.Policy {
+	case gtsmodel.WebPushNotificationPolicyNone:
+		return false, nil
+
+	case gtsmodel.WebPushNotificationPolicyFollowed:
+		following, err := s.state.DB.IsFollowing(ctx, subscription.AccountID, originAccountID)
+		if err != nil {
+			return false, gtserror.Newf("db error checking follow: %w", err)
+		}
+		return following, nil
+
+	case gtsmodel.WebPushNotificationPolicyFollower:
+		follower, err := s.state.DB.IsFollowing(ctx, originAccountID, subscription.AccountID)
+		if err != nil {
+			return false, gtserror.Newf("db error checking follow: %w", err)
+		}
+		return follower, nil
+
+	default:
+		return true, nil
+	}
+}
+
+// deliver posts the given encrypted message body to the subscription's
+// endpoint, retrying with backoff if this fails temporarily. The subscription
+// is removed if the push service reports it gone, or if too many consecutive
+// deliveries to it have failed.
+func (s *sender) deliver(ctx context.Context, subscription *gtsmodel.WebPushSubscription, body []byte) error {
+	var err error
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			// Backoff before retrying.
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(s.backoff * (1 << (attempt - 1))):
+			}
+		}
+
+		var retry bool
+		retry, err = s.post(ctx, subscription, body)
+		if err == nil {
+			// Delivered, so reset
+			// failures if necessary.
+			if subscription.FailureCount == 0 {
+				return nil
+			}
+
+			subscription.FailureCount = 0
+			if err := s.state.DB.UpdateWebPushSubscription(ctx, subscription, "failure_count"); err != nil {
+				return gtserror.Newf("db error updating subscription: %w", err)
+			}
+
+			return nil
+		}
+
+		if errors.Is(err, errGone) {
+			// Push service says this
+			// subscription is no more.
+			return s.remove(ctx, subscription)
+		}
+
+		if !retry {
+			break
+		}
+	}
+
+	// Delivery failed, record failure,
+	// and remove the subscription if
+	// it's been failing for too long.
+	subscription.FailureCount++
+	subscription.LastErrorAt = time.Now()
+
+	if subscription.FailureCount >= maxFailures {
+		log.Infof(ctx, "removing web push subscription %s after %d failed deliveries", subscription.ID, subscription.FailureCount)
+		if err := s.remove(ctx, subscription); err != nil {
+			return err
+		}
+	} else if err := s.state.DB.UpdateWebPushSubscription(ctx, subscription, "failure_count", "last_error_at"); err != nil {
+		return gtserror.Newf("db error updating subscription: %w", err)
+	}
+
+	return err
+}
+
+// post makes one attempt at posting the given encrypted message body
+// to the subscription's endpoint, returning whether it's worth retrying
+// if the attempt fails.
+func (s *sender) post(ctx context.Context, subscription *gtsmodel.WebPushSubscription, body []byte) (bool, error) {
+	public, private, err := s.keys.get(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	authorization, err := vapidAuthorization(subscription.Endpoint, public, private)
+	if err != nil {
+		return false, gtserror.Newf("error creating vapid authorization: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, gtserror.Newf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", ttl)
+	req.Header.Set("Urgency", "normal")
+
+	rsp, err := s.client.Do(req)
+	if err != nil {
+		// Network error,
+		// worth retrying.
+		return true, err
+	}
+	defer rsp.Body.Close()
+
+	// Drain the body so the
+	// connection can be reused.
+	_, _ = io.Copy(io.Discard, rsp.Body)
+
+	switch code := rsp.StatusCode; {
+	case code >= 200 && code < 300:
+		return false, nil
+	case code == http.StatusNotFound || code == http.StatusGone:
+		return false, errGone
+	case code == http.StatusTooManyRequests || code >= 500:
+		return true, fmt.Errorf("push service returned %s", rsp.Status)
+	default:
+		return false, fmt.Errorf("push service returned %s", rsp.Status)
+	}
+}
+
+// remove deletes the given subscription from the database.
+func (s *sender) remove(ctx context.Context, subscription *gtsmodel.WebPushSubscription) error {
+	if err := s.state.DB.DeleteWebPushSubscriptionByID(ctx, subscription.ID); err != nil {
+		return gtserror.Newf("db error deleting subscription: %w", err)
+	}
+	return nil
+}
+
+// newPayload returns a push message payload for the given
+// notification, with a title + body to show to the user.
+func newPayload(n *apimodel.Notification) *payload {
+	var name string
+	if n.Account != nil {
+		name = n.Account.DisplayName
+		if name == "" {
+			name = n.Account.Username
+		}
+	}
+
+	var title string
+	switch gtsmodel.NotificationType(n.Type) {
+	case gtsmodel.NotificationMention:
+		title = name + " mentioned you"
+	case gtsmodel.NotificationReblog:
+		title = name + " boosted your post"
+	case gtsmodel.NotificationFave:
+		title = name + " favourited your post"
+	case gtsmodel.NotificationFollow:
+		title = name + " followed you"
+	case gtsmodel.NotificationFollowRequest:
+		title = name + " requested to follow you"
+	case gtsmodel.NotificationPoll:
+		title = "A poll has ended"
+	case gtsmodel.NotificationStatus:
+		title = name + " just posted"
+	case gtsmodel.NotificationSignup:
+		title = name + " signed up"
+	default:
+		title = "New notification"
+	}
+
+	var body string
+	if s := n.Status; s != nil {
+		// Show content warning rather
+		// than content, if there is one.
+		if s.SpoilerText != "" {
+			body = s.SpoilerText
+		} else {
+			body = text.SanitizePlaintext(s.Content)
+		}
+	} else if n.Account != nil {
+		body = text.SanitizePlaintext(n.Account.Note)
+	}
+
+	if r := []rune(body); len(r) > maxBodyLength {
+		body = string(r[:maxBodyLength-1]) + "…"
+	}
+
+	var icon string
+	if n.Account != nil {
+		icon = n.Account.Avatar
+	}
+
+	return &payload{
+		NotificationID:   n.ID,
+		NotificationType: n.Type,
+		Icon:             icon,
+		Title:            title,
+		Body:             body,
+	}
+}
@@ -0,0 +1,524 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package webpush_test
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	apimodel "github.com/superseriousbusiness/gotosocial/internal/api/model"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
+	"github.com/superseriousbusiness/gotosocial/internal/webpush"
+	"github.com/superseriousbusiness/gotosocial/testrig"
+)
+
+// pushService is a stand-in for a Web Push service,
+// which records the messages posted to it and responds
+// with a configurable sequence of status codes.
+type pushService struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	requests []*pushRequest
+}
+
+type pushRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newPushService(statuses ...int) *pushService {
+	p := &pushService{statuses: statuses}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		p.requests = append(p.requests, &pushRequest{header: r.Header, body: body})
+
+		status := http.StatusCreated
+		if len(p.statuses) > 0 {
+			status, p.statuses = p.statuses[0], p.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	return p
+}
+
+func (p *pushService) received() []*pushRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.requests
+}
+
+// userAgent holds the keys of a user agent (eg., a
+// browser) that has subscribed to a push service.
+type userAgent struct {
+	private *ecdh.PrivateKey
+	auth    []byte
+}
+
+func newUserAgent() *userAgent {
+	private, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	auth := make([]byte, 16)
+	if _, err := rand.Read(auth); err != nil {
+		panic(err)
+	}
+
+	return &userAgent{private: private, auth: auth}
+}
+
+func (u *userAgent) p256dh() string {
+	return base64.RawURLEncoding.EncodeToString(u.private.PublicKey().Bytes())
+}
+
+func (u *userAgent) authSecret() string {
+	return base64.RawURLEncoding.EncodeToString(u.auth)
+}
+
+// decrypt decrypts the given aes128gcm encoded push
+// message body as described in RFC 8291 + RFC 8188.
+func (u *userAgent) decrypt(body []byte) ([]byte, error) {
+	if len(body) < 21 {
+		return nil, errors.New("body too short")
+	}
+
+	salt := body[:16]
+	rs := binary.BigEndian.Uint32(body[16:20])
+	idlen := int(body[20])
+	if len(body) < 21+idlen || int(rs) < len(body)-21-idlen {
+		return nil, errors.New("bad header")
+	}
+	asPublicBytes := body[21 : 21+idlen]
+	ciphertext := body[21+idlen:]
+
+	asPublic, err := ecdh.P256().NewPublicKey(asPublicBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	ecdhSecret, err := u.private.ECDH(asPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	keyInfo := append([]byte("WebPush: info\x00"), u.private.PublicKey().Bytes()...)
+	keyInfo = append(keyInfo, asPublicBytes...)
+	ikm := hkdf(u.auth, ecdhSecret, keyInfo, 32)
+
+	cek := hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Strip padding, which ends with
+	// the last record delimiter 0x02.
+	i := len(plaintext) - 1
+	for i >= 0 && plaintext[i] == 0 {
+		i--
+	}
+	if i < 0 || plaintext[i] != 2 {
+		return nil, errors.New("bad padding")
+	}
+
+	return plaintext[:i], nil
+}
+
+func hkdf(salt, ikm, info []byte, length int) []byte {
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(ikm)
+	prk := extract.Sum(nil)
+
+	expand := hmac.New(sha256.New, prk)
+	expand.Write(info)
+	expand.Write([]byte{1})
+	return expand.Sum(nil)[:length]
+}
+
+type SenderTestSuite struct {
+	suite.Suite
+	db    db.DB
+	state state.State
+
+	testAccounts map[string]*gtsmodel.Account
+	testTokens   map[string]*gtsmodel.Token
+
+	sender webpush.Sender
+}
+
+func (suite *SenderTestSuite) SetupSuite() {
+	suite.testAccounts = testrig.NewTestAccounts()
+	suite.testTokens = testrig.NewTestTokens()
+}
+
+func (suite *SenderTestSuite) SetupTest() {
+	suite.state.Caches.Init()
+
+	testrig.InitTestConfig()
+	testrig.InitTestLog()
+
+	suite.db = testrig.NewTestDB(&suite.state)
+	suite.sender = webpush.NewSender(&suite.state, http.DefaultClient)
+
+	testrig.StandardDBSetup(suite.db, nil)
+}
+
+func (suite *SenderTestSuite) TearDownTest() {
+	testrig.StandardDBTeardown(suite.db)
+}
+
+// subscribe creates a subscription to the given push
+// service for the local_account_1 token, with the
+// given user agent keys and mention alerts enabled.
+func (suite *SenderTestSuite) subscribe(service *pushService, ua *userAgent) *gtsmodel.WebPushSubscription {
+	subscription := &gtsmodel.WebPushSubscription{
+		ID:                 id.NewULID(),
+		AccountID:          suite.testAccounts["local_account_1"].ID,
+		TokenID:            suite.testTokens["local_account_1"].ID,
+		Endpoint:           service.URL + "/push/some-subscription",
+		Auth:               ua.authSecret(),
+		P256dh:             ua.p256dh(),
+		AlertFollow:        util.Ptr(false),
+		AlertFollowRequest: util.Ptr(false),
+		AlertFavourite:     util.Ptr(false),
+		AlertMention:       util.Ptr(true),
+		AlertReblog:        util.Ptr(false),
+		AlertPoll:          util.Ptr(false),
+		AlertStatus:        util.Ptr(false),
+		AlertAdminSignup:   util.Ptr(false),
+		Policy:             gtsmodel.WebPushNotificationPolicyAll,
+	}
+
+	if err := suite.db.PutWebPushSubscription(context.Background(), subscription); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	return subscription
+}
+
+// mention returns a mention notification from
+// admin_account to local_account_1, along with
+// its api model representation.
+func (suite *SenderTestSuite) mention() (*gtsmodel.Notification, *apimodel.Notification) {
+	notification := &gtsmodel.Notification{
+		ID:               id.NewULID(),
+		NotificationType: gtsmodel.NotificationMention,
+		TargetAccountID:  suite.testAccounts["local_account_1"].ID,
+		OriginAccountID:  suite.testAccounts["admin_account"].ID,
+	}
+
+	apiNotification := &apimodel.Notification{
+		ID:   notification.ID,
+		Type: string(notification.NotificationType),
+		Account: &apimodel.Account{
+			Username:    "admin",
+			DisplayName: "",
+			Avatar:      "http://localhost:8080/assets/default_avatars/GoToSocial_icon1.png",
+		},
+		Status: &apimodel.Status{
+			Content: "<p>hello <span class=\"h-card\"><a href=\"http://localhost:8080/@the_mighty_zork\" class=\"u-url mention\">@<span>the_mighty_zork</span></a></span>, how are you?</p>",
+		},
+	}
+
+	return notification, apiNotification
+}
+
+// subscriptionExists returns whether the given subscription is still in the db.
+func (suite *SenderTestSuite) subscriptionExists(subscription *gtsmodel.WebPushSubscription) (*gtsmodel.WebPushSubscription, bool) {
+	dbSubscription, err := suite.db.GetWebPushSubscriptionByTokenID(context.Background(), subscription.TokenID)
+	if errors.Is(err, db.ErrNoEntries) {
+		return nil, false
+	} else if err != nil {
+		suite.FailNow(err.Error())
+	}
+	return dbSubscription, true
+}
+
+func (suite *SenderTestSuite) TestSend() {
+	service := newPushService()
+	defer service.Close()
+
+	ua := newUserAgent()
+	suite.subscribe(service, ua)
+	notification, apiNotification := suite.mention()
+
+	if err := suite.sender.Send(context.Background(), notification, apiNotification); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	requests := service.received()
+	if !suite.Len(requests, 1) {
+		suite.FailNow("")
+	}
+	req := requests[0]
+
+	suite.Equal("aes128gcm", req.header.Get("Content-Encoding"))
+	suite.Equal("application/octet-stream", req.header.Get("Content-Type"))
+	suite.NotEmpty(req.header.Get("TTL"))
+
+	// Check the VAPID authorization.
+	publicKey, err := suite.sender.VAPIDPublicKey(context.Background())
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.verifyVAPID(req.header.Get("Authorization"), publicKey, service.URL)
+
+	// Decrypt + check the payload.
+	plaintext, err := ua.decrypt(req.body)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	payload := make(map[string]interface{})
+	if err := json.Unmarshal(plaintext, &payload); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Equal(map[string]interface{}{
+		"access_token":      suite.testTokens["local_account_1"].Access,
+		"preferred_locale":  "en",
+		"notification_id":   notification.ID,
+		"notification_type": "mention",
+		"icon":              "http://localhost:8080/assets/default_avatars/GoToSocial_icon1.png",
+		"title":             "admin mentioned you",
+		"body":              "hello @the_mighty_zork, how are you?",
+	}, payload)
+}
+
+// verifyVAPID checks that the given Authorization header value
+// contains the given public key and a valid JWT signed with it,
+// for the push service at the given origin.
+func (suite *SenderTestSuite) verifyVAPID(authorization string, publicKey string, origin string) {
+	suite.True(strings.HasPrefix(authorization, "vapid t="))
+	parts := strings.SplitN(strings.TrimPrefix(authorization, "vapid t="), ", k=", 2)
+	if !suite.Len(parts, 2) {
+		suite.FailNow("")
+	}
+	jwt, k := parts[0], parts[1]
+	suite.Equal(publicKey, k)
+
+	segments := strings.Split(jwt, ".")
+	if !suite.Len(segments, 3) {
+		suite.FailNow("")
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(segments[1])
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	claims := make(map[string]interface{})
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		suite.FailNow(err.Error())
+	}
+	suite.Equal(origin, claims["aud"])
+	suite.Equal("http://localhost:8080", claims["sub"])
+
+	publicBytes, err := base64.RawURLEncoding.DecodeString(k)
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(segments[2])
+	if err != nil {
+		suite.FailNow(err.Error())
+	}
+	if !suite.Len(sig, 64) {
+		suite.FailNow("")
+	}
+
+	public := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(publicBytes[1:33]),
+		Y:     new(big.Int).SetBytes(publicBytes[33:65]),
+	}
+
+	digest := sha256.Sum256([]byte(segments[0] + "." + segments[1]))
+	suite.True(ecdsa.Verify(
+		public,
+		digest[:],
+		new(big.Int).SetBytes(sig[:32]),
+		new(big.Int).SetBytes(sig[32:]),
+	))
+}
+
+func (suite *SenderTestSuite) TestSendAlertDisabled() {
+	service := newPushService()
+	defer service.Close()
+
+	suite.subscribe(service, newUserAgent())
+	notification, apiNotification := suite.mention()
+	notification.NotificationType = gtsmodel.NotificationFave
+	apiNotification.Type = string(gtsmodel.NotificationFave)
+
+	if err := suite.sender.Send(context.Background(), notification, apiNotification); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Empty(service.received())
+}
+
+func (suite *SenderTestSuite) TestSendPolicyFollowed() {
+	service := newPushService()
+	defer service.Close()
+
+	subscription := suite.subscribe(service, newUserAgent())
+	subscription.Policy = gtsmodel.WebPushNotificationPolicyFollowed
+	if err := suite.db.UpdateWebPushSubscription(context.Background(), subscription, "policy"); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// local_account_1 follows admin_account,
+	// but not remote_account_1, so only the
+	// first mention should be delivered.
+	notification, apiNotification := suite.mention()
+	if err := suite.sender.Send(context.Background(), notification, apiNotification); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	notification, apiNotification = suite.mention()
+	notification.OriginAccountID = suite.testAccounts["remote_account_1"].ID
+	if err := suite.sender.Send(context.Background(), notification, apiNotification); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	suite.Len(service.received(), 1)
+}
+
+func (suite *SenderTestSuite) TestSendGone() {
+	service := newPushService(http.StatusGone)
+	defer service.Close()
+
+	subscription := suite.subscribe(service, newUserAgent())
+	notification, apiNotification := suite.mention()
+
+	if err := suite.sender.Send(context.Background(), notification, apiNotification); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Push service says the subscription
+	// is gone, so it should be removed.
+	suite.Len(service.received(), 1)
+	_, exists := suite.subscriptionExists(subscription)
+	suite.False(exists)
+}
+
+func (suite *SenderTestSuite) TestSendRetry() {
+	service := newPushService(http.StatusServiceUnavailable, http.StatusCreated)
+	defer service.Close()
+
+	subscription := suite.subscribe(service, newUserAgent())
+	notification, apiNotification := suite.mention()
+
+	if err := suite.sender.Send(context.Background(), notification, apiNotification); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// First attempt should fail, second succeed.
+	suite.Len(service.received(), 2)
+	dbSubscription, exists := suite.subscriptionExists(subscription)
+	suite.True(exists)
+	suite.Zero(dbSubscription.FailureCount)
+}
+
+func (suite *SenderTestSuite) TestSendFailure() {
+	service := newPushService(http.StatusBadRequest)
+	defer service.Close()
+
+	subscription := suite.subscribe(service, newUserAgent())
+	notification, apiNotification := suite.mention()
+
+	err := suite.sender.Send(context.Background(), notification, apiNotification)
+	suite.ErrorContains(err, "push service returned 400 Bad Request")
+
+	// Bad request isn't worth retrying, so there
+	// should have been one attempt, which should
+	// be recorded as a failure on the subscription.
+	suite.Len(service.received(), 1)
+	dbSubscription, exists := suite.subscriptionExists(subscription)
+	suite.True(exists)
+	suite.Equal(1, dbSubscription.FailureCount)
+	suite.False(dbSubscription.LastErrorAt.IsZero())
+}
+
+func (suite *SenderTestSuite) TestSendTokenRevoked() {
+	service := newPushService()
+	defer service.Close()
+
+	subscription := suite.subscribe(service, newUserAgent())
+	if err := suite.db.DeleteByID(context.Background(), subscription.TokenID, &gtsmodel.Token{}); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	notification, apiNotification := suite.mention()
+	if err := suite.sender.Send(context.Background(), notification, apiNotification); err != nil {
+		suite.FailNow(err.Error())
+	}
+
+	// Token's gone so nothing should be
+	// sent, and subscription removed.
+	suite.Empty(service.received())
+	_, exists := suite.subscriptionExists(subscription)
+	suite.False(exists)
+}
+
+func TestSenderTestSuite(t *testing.T) {
+	suite.Run(t, new(SenderTestSuite))
+}
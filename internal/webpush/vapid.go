@@ -0,0 +1,169 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package webpush
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/config"
+	"github.com/superseriousbusiness/gotosocial/internal/db"
+	"github.com/superseriousbusiness/gotosocial/internal/gtserror"
+	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
+	"github.com/superseriousbusiness/gotosocial/internal/id"
+	"github.com/superseriousbusiness/gotosocial/internal/state"
+)
+
+// vapidKeys manages the instance's VAPID key pair, which
+// is created the first time it's needed, then stored in
+// the database and kept in memory for subsequent use.
+type vapidKeys struct {
+	state *state.State
+
+	mu      sync.Mutex
+	public  string
+	private *ecdsa.PrivateKey
+}
+
+// get returns the instance's VAPID key pair,
+// creating and storing it if necessary.
+func (v *vapidKeys) get(ctx context.Context) (string, *ecdsa.PrivateKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.private != nil {
+		// Already loaded.
+		return v.public, v.private, nil
+	}
+
+	keyPair, err := v.state.DB.GetVAPIDKeyPair(ctx)
+	if err != nil && !errors.Is(err, db.ErrNoEntries) {
+		return "", nil, gtserror.Newf("db error getting vapid key pair: %w", err)
+	}
+
+	if keyPair == nil {
+		// No key pair stored
+		// yet, create a new one.
+		key, err := ecdh.P256().GenerateKey(rand.Reader)
+		if err != nil {
+			return "", nil, gtserror.Newf("error generating vapid key pair: %w", err)
+		}
+
+		keyPair = &gtsmodel.VAPIDKeyPair{
+			ID:      id.NewULID(),
+			Public:  base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+			Private: base64.RawURLEncoding.EncodeToString(key.Bytes()),
+		}
+
+		if err := v.state.DB.PutVAPIDKeyPair(ctx, keyPair); err != nil {
+			return "", nil, gtserror.Newf("db error putting vapid key pair: %w", err)
+		}
+	}
+
+	private, err := parsePrivateKey(keyPair.Private)
+	if err != nil {
+		return "", nil, gtserror.Newf("error parsing vapid private key: %w", err)
+	}
+
+	v.public = keyPair.Public
+	v.private = private
+	return v.public, v.private, nil
+}
+
+// parsePrivateKey parses the given base64url-encoded
+// P-256 private key scalar into an ecdsa private key.
+func parsePrivateKey(encoded string) (*ecdsa.PrivateKey, error) {
+	d, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdh.P256().NewPrivateKey(d)
+	if err != nil {
+		return nil, err
+	}
+
+	// Uncompressed point: 0x04 || X || Y.
+	pub := key.PublicKey().Bytes()
+
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(pub[1:33]),
+			Y:     new(big.Int).SetBytes(pub[33:65]),
+		},
+		D: new(big.Int).SetBytes(d),
+	}, nil
+}
+
+// vapidAuthorization returns the value of the Authorization header for a
+// push message to the given endpoint, as described in RFC 8292: a JWT signed
+// with the VAPID private key using ES256, along with the VAPID public key.
+func vapidAuthorization(endpoint string, public string, private *ecdsa.PrivateKey) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("error parsing endpoint: %w", err)
+	}
+
+	header, err := json.Marshal(map[string]string{
+		"typ": "JWT",
+		"alg": "ES256",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]interface{}{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": config.GetProtocol() + "://" + config.GetHost(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) +
+		"." + base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, private, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("error signing jwt: %w", err)
+	}
+
+	// ES256 signatures are the concatenation
+	// of r and s, each padded to 32 bytes.
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	jwt := unsigned + "." + base64.RawURLEncoding.EncodeToString(sig)
+	return "vapid t=" + jwt + ", k=" + public, nil
+}
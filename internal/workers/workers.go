@@ -21,6 +21,7 @@ import (
 	"context"
 	"log"
 	"runtime"
+	"time"
 
 	"codeberg.org/gruf/go-runners"
 	"codeberg.org/gruf/go-sched"
@@ -48,6 +49,17 @@ type Workers struct {
 	// Media manager worker pools.
 	Media runners.WorkerPool
 
+	// WebPush provides a worker pool for delivering
+	// notifications to Web Push subscriptions, kept
+	// separate so slow push services can't hold up
+	// the processing of other side-effects.
+	WebPush runners.WorkerPool
+
+	// Number of workers in the client API and
+	// federator pools, as started by Start().
+	clientAPIWorkers int
+	federatorWorkers int
+
 	// prevent pass-by-value.
 	_ nocopy
 }
@@ -61,17 +73,23 @@ func (w *Workers) Start() {
 		return w.Scheduler.Start(nil)
 	})
 
+	w.clientAPIWorkers = 4 * maxprocs
 	tryUntil("starting client API workerpool", 5, func() bool {
-		return w.ClientAPI.Start(4*maxprocs, 400*maxprocs)
+		return w.ClientAPI.Start(w.clientAPIWorkers, 100*w.clientAPIWorkers)
 	})
 
+	w.federatorWorkers = 4 * maxprocs
 	tryUntil("starting federator workerpool", 5, func() bool {
-		return w.Federator.Start(4*maxprocs, 400*maxprocs)
+		return w.Federator.Start(w.federatorWorkers, 100*w.federatorWorkers)
 	})
 
 	tryUntil("starting media workerpool", 5, func() bool {
 		return w.Media.Start(8*maxprocs, 80*maxprocs)
 	})
+
+	tryUntil("starting web push workerpool", 5, func() bool {
+		return w.WebPush.Start(2*maxprocs, 400*maxprocs)
+	})
 }
 
 // Stop will stop all of the contained worker pools (and global scheduler).
@@ -80,6 +98,69 @@ func (w *Workers) Stop() {
 	tryUntil("stopping client API workerpool", 5, w.ClientAPI.Stop)
 	tryUntil("stopping federator workerpool", 5, w.Federator.Stop)
 	tryUntil("stopping media workerpool", 5, w.Media.Stop)
+	tryUntil("stopping web push workerpool", 5, w.WebPush.Stop)
+}
+
+// Drain blocks until the client API and federator worker pool queues
+// are empty and none of their workers are still processing a message,
+// or until the given timeout, returning whether they were drained.
+// This should be called on shutdown, before stopping the workers or
+// anything they depend on, so that queued messages get processed
+// rather than being dropped (or left to be replayed). The pools
+// must have been started by Start().
+func (w *Workers) Drain(timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for {
+		// Occupy every worker of both pools at once, so that
+		// once they're all held none can be processing a message,
+		// nor enqueue more, and the queues can be checked.
+		release := make(chan struct{})
+		held := hold(ctx, &w.ClientAPI, w.clientAPIWorkers, release) &&
+			hold(ctx, &w.Federator, w.federatorWorkers, release)
+		empty := w.ClientAPI.Queue() == 0 && w.Federator.Queue() == 0
+		close(release)
+
+		if !held {
+			// Timed out.
+			return false
+		}
+
+		if empty {
+			return true
+		}
+
+		// Messages were enqueued by those in
+		// progress while we waited, go again.
+	}
+}
+
+// hold enqueues a function for each of the given number of workers
+// in the pool that blocks until release is closed, and waits until
+// all of them are running, i.e. until each worker has finished the
+// messages queued before them. It returns false if ctx is done first.
+func hold(ctx context.Context, pool *runners.WorkerPool, workers int, release <-chan struct{}) bool {
+	running := make(chan struct{}, workers)
+
+	for i := 0; i < workers; i++ {
+		if !pool.EnqueueCtx(ctx, func(context.Context) {
+			running <- struct{}{}
+			<-release
+		}) {
+			return false
+		}
+	}
+
+	for i := 0; i < workers; i++ {
+		select {
+		case <-running:
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	return true
 }
 
 // nocopy when embedded will signal linter to
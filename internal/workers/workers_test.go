@@ -0,0 +1,75 @@
+// GoToSocial
+// Copyright (C) GoToSocial Authors admin@gotosocial.org
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+
+package workers_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/superseriousbusiness/gotosocial/internal/workers"
+)
+
+func TestDrainWaitsForInFlight(t *testing.T) {
+	var w workers.Workers
+	w.Start()
+	defer w.Stop()
+
+	var done atomic.Bool
+	started := make(chan struct{})
+	w.ClientAPI.Enqueue(func(context.Context) {
+		close(started)
+		time.Sleep(500 * time.Millisecond)
+
+		// Enqueue more work from in-flight work,
+		// as side-effects of messages can do.
+		w.Federator.Enqueue(func(context.Context) {
+			done.Store(true)
+		})
+	})
+
+	// The queue is now empty, but
+	// the message is still in flight.
+	<-started
+
+	if !w.Drain(10 * time.Second) {
+		t.Fatal("timed out draining")
+	}
+
+	if !done.Load() {
+		t.Fatal("drained before in-flight work finished")
+	}
+}
+
+func TestDrainTimeout(t *testing.T) {
+	var w workers.Workers
+	w.Start()
+	defer w.Stop()
+
+	release := make(chan struct{})
+	defer close(release)
+	w.Federator.Enqueue(func(context.Context) {
+		<-release
+	})
+
+	if w.Drain(200 * time.Millisecond) {
+		t.Fatal("expected drain to time out")
+	}
+}
@@ -6,11 +6,15 @@ EXPECT=$(cat << "EOF"
 {
     "account-domain": "peepee",
     "accounts-allow-custom-css": true,
+    "accounts-allow-invites": true,
     "accounts-approval-required": false,
     "accounts-custom-css-length": 5000,
+    "accounts-invites-bypass-approval": true,
     "accounts-reason-required": false,
     "accounts-registration-open": true,
+    "accounts-rejection-cooldown": 86400000000000,
     "advanced-cookies-samesite": "strict",
+    "advanced-persist-worker-messages": true,
     "advanced-rate-limit-requests": 6969,
     "advanced-sender-multiplier": -1,
     "advanced-throttling-multiplier": -1,
@@ -22,8 +26,12 @@ EXPECT=$(cat << "EOF"
         "account-note-mem-ratio": 0.1,
         "block-mem-ratio": 3,
         "boost-of-ids-mem-ratio": 3,
+        "conversation-mem-ratio": 1,
         "emoji-category-mem-ratio": 0.1,
         "emoji-mem-ratio": 3,
+        "filter-keyword-mem-ratio": 0.5,
+        "filter-mem-ratio": 0.5,
+        "filter-status-mem-ratio": 0.5,
         "follow-ids-mem-ratio": 4,
         "follow-mem-ratio": 4,
         "follow-request-ids-mem-ratio": 2,
@@ -37,13 +45,19 @@ EXPECT=$(cat << "EOF"
         "memory-target": 209715200,
         "mention-mem-ratio": 5,
         "notification-mem-ratio": 5,
+        "poll-mem-ratio": 2,
+        "poll-vote-ids-mem-ratio": 2,
+        "poll-vote-mem-ratio": 2,
         "report-mem-ratio": 1,
+        "status-edit-mem-ratio": 2,
         "status-fave-ids-mem-ratio": 3,
         "status-fave-mem-ratio": 5,
         "status-mem-ratio": 18,
         "tag-mem-ratio": 3,
         "tombstone-mem-ratio": 2,
         "user-mem-ratio": 0.1,
+        "user-mute-ids-mem-ratio": 3,
+        "user-mute-mem-ratio": 2,
         "visibility-mem-ratio": 2,
         "webfinger-mem-ratio": 0.1
     },
@@ -75,6 +89,7 @@ EXPECT=$(cat << "EOF"
     "instance-expose-public-timeline": true,
     "instance-expose-suspended": true,
     "instance-expose-suspended-web": true,
+    "instance-federation-mode": "allowlist",
     "instance-inject-mastodon-version": true,
     "landing-page-user": "admin",
     "letsencrypt-cert-dir": "/gotosocial/storage/certs",
@@ -89,6 +104,7 @@ EXPECT=$(cat << "EOF"
     "media-emoji-local-max-size": 420,
     "media-emoji-remote-max-size": 420,
     "media-image-max-size": 420,
+    "media-image-transcode": false,
     "media-remote-cache-days": 30,
     "media-video-max-size": 420,
     "oidc-admin-groups": [
@@ -178,6 +194,7 @@ GTS_TLS_MODE='' \
 GTS_DB_TLS_CA_CERT='' \
 GTS_WEB_TEMPLATE_BASE_DIR='/root' \
 GTS_WEB_ASSET_BASE_DIR='/root' \
+GTS_INSTANCE_FEDERATION_MODE='allowlist' \
 GTS_INSTANCE_EXPOSE_PEERS=true \
 GTS_INSTANCE_EXPOSE_SUSPENDED=true \
 GTS_INSTANCE_EXPOSE_SUSPENDED_WEB=true \
@@ -189,7 +206,11 @@ GTS_ACCOUNTS_CUSTOM_CSS_LENGTH=5000 \
 GTS_ACCOUNTS_REGISTRATION_OPEN=true \
 GTS_ACCOUNTS_APPROVAL_REQUIRED=false \
 GTS_ACCOUNTS_REASON_REQUIRED=false \
+GTS_ACCOUNTS_REJECTION_COOLDOWN='24h' \
+GTS_ACCOUNTS_ALLOW_INVITES=true \
+GTS_ACCOUNTS_INVITES_BYPASS_APPROVAL=true \
 GTS_MEDIA_IMAGE_MAX_SIZE=420 \
+GTS_MEDIA_IMAGE_TRANSCODE=false \
 GTS_MEDIA_VIDEO_MAX_SIZE=420 \
 GTS_MEDIA_DESCRIPTION_MIN_CHARS=69 \
 GTS_MEDIA_DESCRIPTION_MAX_CHARS=5000 \
@@ -235,6 +256,7 @@ GTS_TRACING_ENDPOINT='localhost:4317' \
 GTS_ADVANCED_COOKIES_SAMESITE='strict' \
 GTS_ADVANCED_RATE_LIMIT_REQUESTS=6969 \
 GTS_ADVANCED_SENDER_MULTIPLIER=-1 \
+GTS_ADVANCED_PERSIST_WORKER_MESSAGES=true \
 GTS_ADVANCED_THROTTLING_MULTIPLIER=-1 \
 GTS_ADVANCED_THROTTLING_RETRY_AFTER='10s' \
 GTS_REQUEST_ID_HEADER='X-Trace-Id' \
@@ -62,6 +62,7 @@ var testDefaults = config.Configuration{
 	WebTemplateBaseDir: "./web/template/",
 	WebAssetBaseDir:    "./web/assets/",
 
+	InstanceFederationMode:         config.InstanceFederationModeDefault,
 	InstanceExposePeers:            true,
 	InstanceExposeSuspended:        true,
 	InstanceExposeSuspendedWeb:     true,
@@ -73,7 +74,13 @@ var testDefaults = config.Configuration{
 	AccountsAllowCustomCSS:   true,
 	AccountsCustomCSSLength:  10000,
 
+	AccountsRejectionCooldown: 7 * 24 * time.Hour,
+
+	AccountsAllowInvites:          false,
+	AccountsInvitesBypassApproval: false,
+
 	MediaImageMaxSize:        10485760, // 10mb
+	MediaImageTranscode:      true,
 	MediaVideoMaxSize:        41943040, // 40mb
 	MediaDescriptionMinChars: 0,
 	MediaDescriptionMaxChars: 500,
@@ -128,6 +135,8 @@ var testDefaults = config.Configuration{
 	AdvancedThrottlingMultiplier: 0, // disabled
 	AdvancedSenderMultiplier:     0, // 1 sender only, regardless of CPU
 
+	AdvancedPersistWorkerMessages: false,
+
 	SoftwareVersion: "0.0.0-testrig",
 
 	// simply use cache defaults.
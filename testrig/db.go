@@ -35,8 +35,16 @@ var testModels = []interface{}{
 	&gtsmodel.AccountToEmoji{},
 	&gtsmodel.Application{},
 	&gtsmodel.Block{},
+	&gtsmodel.Conversation{},
+	&gtsmodel.ConversationToStatus{},
+	&gtsmodel.DeniedUser{},
+	&gtsmodel.DomainAllow{},
 	&gtsmodel.DomainBlock{},
+	&gtsmodel.DomainPermissionSubscription{},
 	&gtsmodel.EmailDomainBlock{},
+	&gtsmodel.Filter{},
+	&gtsmodel.FilterKeyword{},
+	&gtsmodel.FilterStatus{},
 	&gtsmodel.Follow{},
 	&gtsmodel.FollowRequest{},
 	&gtsmodel.List{},
@@ -52,9 +60,13 @@ var testModels = []interface{}{
 	&gtsmodel.StatusMute{},
 	&gtsmodel.Tag{},
 	&gtsmodel.User{},
+	&gtsmodel.UserMute{},
 	&gtsmodel.Emoji{},
 	&gtsmodel.Instance{},
 	&gtsmodel.Notification{},
+	&gtsmodel.Poll{},
+	&gtsmodel.PollVote{},
+	&gtsmodel.StatusEdit{},
 	&gtsmodel.RouterSession{},
 	&gtsmodel.Token{},
 	&gtsmodel.Client{},
@@ -62,6 +74,14 @@ var testModels = []interface{}{
 	&gtsmodel.Tombstone{},
 	&gtsmodel.Report{},
 	&gtsmodel.AccountNote{},
+	&gtsmodel.VAPIDKeyPair{},
+	&gtsmodel.WebPushSubscription{},
+	&gtsmodel.ScheduledStatus{},
+	&gtsmodel.Invite{},
+	&gtsmodel.Rule{},
+	&gtsmodel.Delivery{},
+	&gtsmodel.DeliveryFailure{},
+	&gtsmodel.WorkerMessage{},
 }
 
 // NewTestDB returns a new initialized, empty database for testing.
@@ -160,6 +180,24 @@ func StandardDBSetup(db db.DB, accounts map[string]*gtsmodel.Account) {
 		}
 	}
 
+	for _, v := range NewTestScheduledStatuses() {
+		if err := db.Put(ctx, v); err != nil {
+			log.Panic(nil, err)
+		}
+	}
+
+	for _, v := range NewTestInvites() {
+		if err := db.Put(ctx, v); err != nil {
+			log.Panic(nil, err)
+		}
+	}
+
+	for _, v := range NewTestRules() {
+		if err := db.Put(ctx, v); err != nil {
+			log.Panic(nil, err)
+		}
+	}
+
 	for _, v := range NewTestDomainBlocks() {
 		if err := db.Put(ctx, v); err != nil {
 			log.Panic(nil, err)
@@ -264,6 +302,24 @@ func StandardDBSetup(db db.DB, accounts map[string]*gtsmodel.Account) {
 		}
 	}
 
+	for _, v := range NewTestFilters() {
+		if err := db.Put(ctx, v); err != nil {
+			log.Panic(nil, err)
+		}
+	}
+
+	for _, v := range NewTestFilterKeywords() {
+		if err := db.Put(ctx, v); err != nil {
+			log.Panic(nil, err)
+		}
+	}
+
+	for _, v := range NewTestFilterStatuses() {
+		if err := db.Put(ctx, v); err != nil {
+			log.Panic(nil, err)
+		}
+	}
+
 	for _, v := range NewTestNotifications() {
 		if err := db.Put(ctx, v); err != nil {
 			log.Panic(nil, err)
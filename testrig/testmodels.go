@@ -40,6 +40,7 @@ import (
 	"github.com/superseriousbusiness/gotosocial/internal/ap"
 	"github.com/superseriousbusiness/gotosocial/internal/gtsmodel"
 	"github.com/superseriousbusiness/gotosocial/internal/transport"
+	"github.com/superseriousbusiness/gotosocial/internal/util"
 )
 
 // TrueBool just returns a pointer to boolean true.
@@ -341,7 +342,7 @@ func NewTestAccounts() map[string]*gtsmodel.Account {
 			FollowingURI:            "http://localhost:8080/users/localhost:8080/following",
 			FeaturedCollectionURI:   "http://localhost:8080/users/localhost:8080/collections/featured",
 			ActorType:               ap.ActorPerson,
-			AlsoKnownAs:             "",
+			AlsoKnownAsURIs:         []string{},
 			PrivateKey:              &rsa.PrivateKey{},
 			PublicKey:               &rsa.PublicKey{},
 			SensitizedAt:            time.Time{},
@@ -379,7 +380,7 @@ func NewTestAccounts() map[string]*gtsmodel.Account {
 			FollowingURI:            "http://localhost:8080/users/weed_lord420/following",
 			FeaturedCollectionURI:   "http://localhost:8080/users/weed_lord420/collections/featured",
 			ActorType:               ap.ActorPerson,
-			AlsoKnownAs:             "",
+			AlsoKnownAsURIs:         []string{},
 			PrivateKey:              &rsa.PrivateKey{},
 			PublicKey:               &rsa.PublicKey{},
 			PublicKeyURI:            "http://localhost:8080/users/weed_lord420#main-key",
@@ -419,7 +420,7 @@ func NewTestAccounts() map[string]*gtsmodel.Account {
 			FollowingURI:            "http://localhost:8080/users/admin/following",
 			FeaturedCollectionURI:   "http://localhost:8080/users/admin/collections/featured",
 			ActorType:               ap.ActorPerson,
-			AlsoKnownAs:             "",
+			AlsoKnownAsURIs:         []string{},
 			PrivateKey:              &rsa.PrivateKey{},
 			PublicKey:               &rsa.PublicKey{},
 			SensitizedAt:            time.Time{},
@@ -458,7 +459,7 @@ func NewTestAccounts() map[string]*gtsmodel.Account {
 			FollowingURI:            "http://localhost:8080/users/the_mighty_zork/following",
 			FeaturedCollectionURI:   "http://localhost:8080/users/the_mighty_zork/collections/featured",
 			ActorType:               ap.ActorPerson,
-			AlsoKnownAs:             "",
+			AlsoKnownAsURIs:         []string{},
 			PrivateKey:              &rsa.PrivateKey{},
 			PublicKey:               &rsa.PublicKey{},
 			PublicKeyURI:            "http://localhost:8080/users/the_mighty_zork/main-key",
@@ -517,7 +518,7 @@ func NewTestAccounts() map[string]*gtsmodel.Account {
 			FollowingURI:          "http://localhost:8080/users/1happyturtle/following",
 			FeaturedCollectionURI: "http://localhost:8080/users/1happyturtle/collections/featured",
 			ActorType:             ap.ActorPerson,
-			AlsoKnownAs:           "",
+			AlsoKnownAsURIs:       []string{},
 			PrivateKey:            &rsa.PrivateKey{},
 			PublicKey:             &rsa.PublicKey{},
 			PublicKeyURI:          "http://localhost:8080/users/1happyturtle#main-key",
@@ -554,7 +555,7 @@ func NewTestAccounts() map[string]*gtsmodel.Account {
 			FollowingURI:          "http://fossbros-anonymous.io/users/foss_satan/following",
 			FeaturedCollectionURI: "http://fossbros-anonymous.io/users/foss_satan/collections/featured",
 			ActorType:             ap.ActorPerson,
-			AlsoKnownAs:           "",
+			AlsoKnownAsURIs:       []string{},
 			PrivateKey:            &rsa.PrivateKey{},
 			PublicKey:             &rsa.PublicKey{},
 			PublicKeyURI:          "http://fossbros-anonymous.io/users/foss_satan/main-key",
@@ -590,7 +591,7 @@ func NewTestAccounts() map[string]*gtsmodel.Account {
 			FollowingURI:          "http://example.org/users/Some_User/following",
 			FeaturedCollectionURI: "http://example.org/users/Some_User/collections/featured",
 			ActorType:             ap.ActorPerson,
-			AlsoKnownAs:           "",
+			AlsoKnownAsURIs:       []string{},
 			PrivateKey:            &rsa.PrivateKey{},
 			PublicKey:             &rsa.PublicKey{},
 			PublicKeyURI:          "http://example.org/users/Some_User#main-key",
@@ -626,7 +627,7 @@ func NewTestAccounts() map[string]*gtsmodel.Account {
 			FollowingURI:            "http://thequeenisstillalive.technology/users/her_fuckin_maj/following",
 			FeaturedCollectionURI:   "http://thequeenisstillalive.technology/users/her_fuckin_maj/collections/featured",
 			ActorType:               ap.ActorPerson,
-			AlsoKnownAs:             "",
+			AlsoKnownAsURIs:         []string{},
 			PrivateKey:              &rsa.PrivateKey{},
 			PublicKey:               &rsa.PublicKey{},
 			PublicKeyURI:            "http://thequeenisstillalive.technology/users/her_fuckin_maj#main-key",
@@ -662,7 +663,7 @@ func NewTestAccounts() map[string]*gtsmodel.Account {
 			FollowingURI:            "https://xn--xample-ova.org/users/%C3%BCser/following",
 			FeaturedCollectionURI:   "https://xn--xample-ova.org/users/%C3%BCser/collections/featured",
 			ActorType:               ap.ActorPerson,
-			AlsoKnownAs:             "",
+			AlsoKnownAsURIs:         []string{},
 			PrivateKey:              &rsa.PrivateKey{},
 			PublicKey:               &rsa.PublicKey{},
 			PublicKeyURI:            "https://xn--xample-ova.org/users/%C3%BCser#main-key",
@@ -2001,6 +2002,51 @@ func NewTestListEntries() map[string]*gtsmodel.ListEntry {
 	}
 }
 
+func NewTestFilters() map[string]*gtsmodel.Filter {
+	return map[string]*gtsmodel.Filter{
+		"local_account_1_filter_1": {
+			ID:                   "01H72S35G0TNXNQ8J1Y0Q5TB6Z",
+			CreatedAt:            TimeMustParse("2023-08-05T14:00:00+02:00"),
+			UpdatedAt:            TimeMustParse("2023-08-05T14:00:00+02:00"),
+			AccountID:            "01F8MH1H7YV1Z7D2C8K2730QBF",
+			Title:                "fnord",
+			Action:               gtsmodel.FilterActionWarn,
+			ContextHome:          TrueBool(),
+			ContextNotifications: FalseBool(),
+			ContextPublic:        TrueBool(),
+			ContextThread:        TrueBool(),
+			ContextAccount:       FalseBool(),
+		},
+	}
+}
+
+func NewTestFilterKeywords() map[string]*gtsmodel.FilterKeyword {
+	return map[string]*gtsmodel.FilterKeyword{
+		"local_account_1_filter_1_keyword_1": {
+			ID:        "01H72S35G0W4B4C0PDEZ2J6T5R",
+			CreatedAt: TimeMustParse("2023-08-05T14:00:00+02:00"),
+			UpdatedAt: TimeMustParse("2023-08-05T14:00:00+02:00"),
+			AccountID: "01F8MH1H7YV1Z7D2C8K2730QBF",
+			FilterID:  "01H72S35G0TNXNQ8J1Y0Q5TB6Z",
+			Keyword:   "fnord",
+			WholeWord: TrueBool(),
+		},
+	}
+}
+
+func NewTestFilterStatuses() map[string]*gtsmodel.FilterStatus {
+	return map[string]*gtsmodel.FilterStatus{
+		"local_account_1_filter_1_status_1": {
+			ID:        "01H72S35G0SF9SNQ1BYG3D5KZ6",
+			CreatedAt: TimeMustParse("2023-08-05T14:00:00+02:00"),
+			UpdatedAt: TimeMustParse("2023-08-05T14:00:00+02:00"),
+			AccountID: "01F8MH1H7YV1Z7D2C8K2730QBF",
+			FilterID:  "01H72S35G0TNXNQ8J1Y0Q5TB6Z",
+			StatusID:  "01FVW7JHQFSFK166WWKR8CBA6M",
+		},
+	}
+}
+
 func NewTestMarkers() map[string]*gtsmodel.Marker {
 	return map[string]*gtsmodel.Marker{
 		"local_account_1_home_marker": {
@@ -2063,6 +2109,80 @@ func NewTestReports() map[string]*gtsmodel.Report {
 	}
 }
 
+func NewTestScheduledStatuses() map[string]*gtsmodel.ScheduledStatus {
+	return map[string]*gtsmodel.ScheduledStatus{
+		"local_account_1_scheduled_status_1": {
+			ID:             "01H9J5QZ1B7Y3E8K2TGTXC9XHR",
+			CreatedAt:      TimeMustParse("2023-09-05T10:00:00+02:00"),
+			UpdatedAt:      TimeMustParse("2023-09-05T10:00:00+02:00"),
+			AccountID:      "01F8MH1H7YV1Z7D2C8K2730QBF",
+			ApplicationID:  "01F8MGY43H3N2C8EWPR2FPYEXG",
+			ScheduledAt:    TimeMustParse("2099-01-01T12:00:00Z"),
+			Text:           "happy new year from the distant future!",
+			MediaIDs:       []string{},
+			PollMultiple:   FalseBool(),
+			PollHideTotals: FalseBool(),
+			Sensitive:      FalseBool(),
+			Visibility:     gtsmodel.VisibilityPublic,
+		},
+	}
+}
+
+// NewTestInvites returns a map of invite links keyed by a description.
+func NewTestInvites() map[string]*gtsmodel.Invite {
+	return map[string]*gtsmodel.Invite{
+		"admin_account_invite_1": {
+			ID:         "01H9PQ6Y3W6N1DHZ8Y8T3QNMB2",
+			CreatedAt:  TimeMustParse("2023-09-06T10:00:00+02:00"),
+			UpdatedAt:  TimeMustParse("2023-09-06T10:00:00+02:00"),
+			Code:       "wkTbG4Ya",
+			UserID:     "01F8MGWYWKVKS3VS8DV1AMYPGE",
+			MaxUses:    5,
+			Uses:       1,
+			Autofollow: TrueBool(),
+		},
+		"local_account_1_invite_1": {
+			ID:         "01H9PQAE8A2DJ6Q7GZ5NAQ1F7E",
+			CreatedAt:  TimeMustParse("2023-09-06T11:00:00+02:00"),
+			UpdatedAt:  TimeMustParse("2023-09-06T11:00:00+02:00"),
+			Code:       "Yx1bQ9cZ",
+			UserID:     "01F8MGVGPHQ2D3P3X0454H54Z5",
+			ExpiresAt:  TimeMustParse("2023-09-07T11:00:00+02:00"),
+			Autofollow: FalseBool(),
+		},
+	}
+}
+
+// NewTestRules returns a map of instance rules keyed by a description.
+func NewTestRules() map[string]*gtsmodel.Rule {
+	return map[string]*gtsmodel.Rule{
+		"rule_1": {
+			ID:        "01HA0VEZ3P6Z8T4E1XW8B3YJ5K",
+			CreatedAt: TimeMustParse("2023-09-10T10:00:00+02:00"),
+			UpdatedAt: TimeMustParse("2023-09-10T10:00:00+02:00"),
+			Text:      "Be nice to each other.",
+			Order:     util.Ptr(uint(0)),
+			Deleted:   FalseBool(),
+		},
+		"rule_2": {
+			ID:        "01HA0VF8N2JQ6B5V4D7ZC9M1RT",
+			CreatedAt: TimeMustParse("2023-09-10T10:01:00+02:00"),
+			UpdatedAt: TimeMustParse("2023-09-10T10:01:00+02:00"),
+			Text:      "No spam or advertising.",
+			Order:     util.Ptr(uint(1)),
+			Deleted:   FalseBool(),
+		},
+		"rule_deleted": {
+			ID:        "01HA0VFJ6YHK2W3S8P1X4QG0EA",
+			CreatedAt: TimeMustParse("2023-09-10T10:02:00+02:00"),
+			UpdatedAt: TimeMustParse("2023-09-10T11:00:00+02:00"),
+			Text:      "No posting before noon.",
+			Order:     util.Ptr(uint(2)),
+			Deleted:   TrueBool(),
+		},
+	}
+}
+
 // ActivityWithSignature wraps a pub.Activity along with its signature headers, for testing.
 type ActivityWithSignature struct {
 	Activity        pub.Activity
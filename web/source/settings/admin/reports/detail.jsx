@@ -85,6 +85,15 @@ function ReportDetailForm({ data: report }) {
 					<b>Forwarded: </b> <span>{report.forwarded ? "Yes" : "No"}</span>
 					<b>Category: </b> <span>{report.category}</span>
 
+					{report.rules.length > 0 && <>
+						<b>Rules broken: </b>
+						<ol className="rules">
+							{report.rules.map((rule) => (
+								<li key={rule.id}>{rule.text}</li>
+							))}
+						</ol>
+					</>}
+
 					<b>Reason: </b>
 					{report.comment.length > 0
 						? <p>{report.comment}</p>